@@ -1,8 +1,22 @@
 package address
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"encoding/json"
+
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 // Address is a wrapper arroung the ethereum 20-byte address.
 type Address struct {
 	Address common.Address `json:"address"`
 }
+
+// MarshalJSON encodes the address as a checksummed hex string.
+func (address Address) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Address util.Address `json:"address"`
+	}{
+		Address: util.Address(address.Address),
+	})
+}
@@ -4,17 +4,20 @@ import (
 	"net/http"
 
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
 )
 
 var (
-	_ Getter = &Client{}
+	_ Getter         = &Client{}
+	_ util.RawCaller = &Client{}
 )
 
 // NewClient creates a new address client that provides access to a Raiden node
 // ethereum address that is being used.
 func NewClient(config *config.Config, httpClient *http.Client) *Client {
 	return &Client{
-		Getter: NewGetter(config, httpClient),
+		Getter:    NewGetter(config, httpClient),
+		RawCaller: util.NewRawCaller(config, httpClient),
 	}
 }
 
@@ -22,4 +25,5 @@ func NewClient(config *config.Config, httpClient *http.Client) *Client {
 // a Raiden node.
 type Client struct {
 	Getter
+	util.RawCaller
 }
@@ -2,8 +2,6 @@ package address
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -47,28 +45,14 @@ func (lister *defaultGetter) Get(ctx context.Context) (common.Address, error) {
 		address = common.Address{}
 
 		requestURL      *url.URL
-		request         *http.Request
-		response        *http.Response
 		addressResponse = &addressResponse{}
 	)
 
-	if requestURL, err = lister.getRequestURL(); err != nil {
+	if requestURL, err = lister.baseClient.Endpoint("address"); err != nil {
 		return address, err
 	}
 
-	if request, err = http.NewRequest("GET", requestURL.String(), nil); err != nil {
-		return address, err
-	}
-
-	request = request.WithContext(ctx)
-
-	if response, err = lister.baseClient.HTTPClient.Do(request); err != nil {
-		return address, err
-	}
-
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&addressResponse); err != nil {
+	if err = lister.baseClient.Call(ctx, "GET", requestURL, nil, &addressResponse); err != nil {
 		return address, err
 	}
 
@@ -76,17 +60,3 @@ func (lister *defaultGetter) Get(ctx context.Context) (common.Address, error) {
 
 	return address, nil
 }
-
-func (lister *defaultGetter) getRequestURL() (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/address", lister.baseClient.Config.Host, lister.baseClient.Config.APIVersion)
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
-	}
-
-	return requestURL, nil
-}
@@ -115,6 +115,7 @@ func TestNewGetter(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Zero(t, address)
 				return
 			}
 
@@ -0,0 +1,72 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrZeroTokenNetworkAddress is returned when parsing the zero address as the address
+// of a token network, which no token network is deployed at.
+var ErrZeroTokenNetworkAddress = errors.New("token network address must not be the zero address")
+
+// TokenNetworkAddress is the address of the token network contract of a token. It is
+// distinct from common.Address so that a token network address can't be passed where
+// the address of a token is expected, or the other way around, without an explicit
+// conversion. It is marshalled as the EIP-55 checksummed hex string used by the
+// Raiden node.
+type TokenNetworkAddress common.Address
+
+// HexToTokenNetworkAddress returns the token network address of a hex string, like
+// common.HexToAddress does with no validation, see ParseTokenNetworkAddress.
+func HexToTokenNetworkAddress(value string) TokenNetworkAddress {
+	return TokenNetworkAddress(common.HexToAddress(value))
+}
+
+// ParseTokenNetworkAddress parses a hex string as a token network address, returning
+// an error when it is not a valid address or is the zero address.
+func ParseTokenNetworkAddress(value string) (TokenNetworkAddress, error) {
+	if !common.IsHexAddress(value) {
+		return TokenNetworkAddress{}, fmt.Errorf("invalid token network address %q", value)
+	}
+
+	address := HexToTokenNetworkAddress(value)
+	if address.IsZero() {
+		return TokenNetworkAddress{}, ErrZeroTokenNetworkAddress
+	}
+
+	return address, nil
+}
+
+// Address returns the token network address as a plain address, e.g. to compare it
+// with the address of a contract event.
+func (address TokenNetworkAddress) Address() common.Address {
+	return common.Address(address)
+}
+
+// IsZero tells whether the address is the zero address, e.g. of a token that has no
+// token network.
+func (address TokenNetworkAddress) IsZero() bool {
+	return address == TokenNetworkAddress{}
+}
+
+// Hex returns the EIP-55 checksummed hex string of the address.
+func (address TokenNetworkAddress) Hex() string {
+	return common.Address(address).Hex()
+}
+
+// String implements fmt.Stringer, see Hex.
+func (address TokenNetworkAddress) String() string {
+	return address.Hex()
+}
+
+// MarshalText returns the checksummed hex string of the address.
+func (address TokenNetworkAddress) MarshalText() ([]byte, error) {
+	return []byte(address.Hex()), nil
+}
+
+// UnmarshalText parses a hex string as a token network address.
+func (address *TokenNetworkAddress) UnmarshalText(input []byte) error {
+	return (*common.Address)(address).UnmarshalText(input)
+}
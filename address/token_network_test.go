@@ -0,0 +1,88 @@
+package address
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTokenNetworkAddress(t *testing.T) {
+	type testcase struct {
+		name          string
+		value         string
+		expectedHex   string
+		expectedError error
+		expectError   bool
+	}
+
+	var cases = []testcase{
+		testcase{
+			name:        "lowercase hex is checksummed",
+			value:       "0xe5637f0103794c7e05469a9964e4563089a5e6f2",
+			expectedHex: "0xE5637F0103794C7e05469A9964E4563089a5E6f2",
+		},
+		testcase{
+			name:        "not an address",
+			value:       "0xE5637F01",
+			expectError: true,
+		},
+		testcase{
+			name:        "not hex",
+			value:       "token network",
+			expectError: true,
+		},
+		testcase{
+			name:          "zero address",
+			value:         "0x0000000000000000000000000000000000000000",
+			expectedError: ErrZeroTokenNetworkAddress,
+			expectError:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			address, err := ParseTokenNetworkAddress(tc.value)
+
+			if tc.expectError {
+				require.Error(t, err)
+				assert.True(t, address.IsZero())
+
+				if tc.expectedError != nil {
+					assert.Equal(t, tc.expectedError, err)
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedHex, address.Hex())
+			assert.Equal(t, tc.expectedHex, address.String())
+			assert.Equal(t, common.HexToAddress(tc.value), address.Address())
+		})
+	}
+}
+
+func TestTokenNetworkAddressJSON(t *testing.T) {
+	var (
+		value = struct {
+			TokenNetwork TokenNetworkAddress `json:"token_network"`
+		}{
+			TokenNetwork: HexToTokenNetworkAddress("0xe5637f0103794c7e05469a9964e4563089a5e6f2"),
+		}
+		decoded = value
+	)
+
+	encoded, err := json.Marshal(value)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"token_network":"0xE5637F0103794C7e05469A9964E4563089a5E6f2"}`, string(encoded))
+
+	decoded.TokenNetwork = TokenNetworkAddress{}
+
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, value, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"token_network":"0x1234"}`), &decoded))
+}
@@ -0,0 +1,71 @@
+// Package amounts decodes the token amounts returned by a Raiden node without loss
+// of precision. Amounts are integers in the smallest unit of a token, which for a
+// token with 18 decimals exceed the range of an int64 from about 9.2 tokens on.
+package amounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// Parse will parse a JSON number as an amount, whether the node sent it as a number
+// or as a string. A missing number is a zero amount.
+func Parse(number json.Number) (*big.Int, error) {
+	if number == "" {
+		return new(big.Int), nil
+	}
+
+	amount, ok := new(big.Int).SetString(number.String(), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %s", number.String())
+	}
+
+	return amount, nil
+}
+
+// Int64 returns the amount as an int64, or false when it does not fit one, e.g. for
+// an amount stored in an integer column.
+func Int64(amount *big.Int) (int64, bool) {
+	if amount == nil {
+		return 0, true
+	}
+
+	if !amount.IsInt64() {
+		return 0, false
+	}
+
+	return amount.Int64(), true
+}
+
+// OrZero returns the amount, or a zero amount when it is nil, so that amounts of
+// types built by hand can be used like the decoded ones.
+func OrZero(amount *big.Int) *big.Int {
+	if amount == nil {
+		return new(big.Int)
+	}
+
+	return amount
+}
+
+// Number returns the amount as a JSON number, the way the Raiden node sends amounts.
+// A nil amount is zero.
+func Number(amount *big.Int) json.Number {
+	return json.Number(OrZero(amount).String())
+}
+
+// Equal tells whether two amounts are the same, comparing their values rather than
+// the internal representation reflect.DeepEqual would. A nil amount is zero.
+func Equal(amount, other *big.Int) bool {
+	return OrZero(amount).Cmp(OrZero(other)) == 0
+}
+
+// Copy returns a copy of the amount that does not share its memory, so that changing
+// one does not change the other. The copy of a nil amount is nil.
+func Copy(amount *big.Int) *big.Int {
+	if amount == nil {
+		return nil
+	}
+
+	return new(big.Int).Set(amount)
+}
@@ -0,0 +1,115 @@
+package amounts
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	type testcase struct {
+		name           string
+		number         json.Number
+		expectedAmount string
+		expectedError  error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "small amount",
+			number:         json.Number("25"),
+			expectedAmount: "25",
+		},
+		testcase{
+			name:           "amount larger than an int64",
+			number:         json.Number("1000000000000000000000"),
+			expectedAmount: "1000000000000000000000",
+		},
+		testcase{
+			name:           "missing amount",
+			number:         json.Number(""),
+			expectedAmount: "0",
+		},
+		testcase{
+			name:          "invalid amount",
+			number:        json.Number("1.5"),
+			expectedError: errors.New("invalid amount: 1.5"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			amount, err := Parse(tc.number)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAmount, amount.String())
+		})
+	}
+}
+
+func TestDecodeString(t *testing.T) {
+	var response struct {
+		Amount json.Number `json:"amount"`
+	}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"1000000000000000000000"}`), &response))
+
+	amount, err := Parse(response.Amount)
+
+	require.NoError(t, err)
+	assert.Equal(t, "1000000000000000000000", amount.String())
+}
+
+func TestInt64(t *testing.T) {
+	value, ok := Int64(big.NewInt(42))
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), value)
+
+	value, ok = Int64(nil)
+	assert.True(t, ok)
+	assert.Equal(t, int64(0), value)
+
+	_, ok = Int64(new(big.Int).Lsh(big.NewInt(1), 64))
+	assert.False(t, ok)
+}
+
+func TestOrZero(t *testing.T) {
+	assert.Equal(t, 0, OrZero(nil).Sign())
+	assert.Equal(t, big.NewInt(7), OrZero(big.NewInt(7)))
+}
+
+func TestEqual(t *testing.T) {
+	var (
+		zero, _    = new(big.Int).SetString("0", 10)
+		decoded, _ = Parse("1000000000000000000000")
+	)
+
+	assert.True(t, Equal(nil, nil))
+	assert.True(t, Equal(nil, zero))
+	assert.True(t, Equal(new(big.Int), zero))
+	assert.True(t, Equal(decoded, new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil)))
+	assert.False(t, Equal(big.NewInt(7), nil))
+	assert.False(t, Equal(big.NewInt(7), big.NewInt(-7)))
+}
+
+func TestCopy(t *testing.T) {
+	var (
+		amount = big.NewInt(7)
+		copied = Copy(amount)
+	)
+
+	assert.Nil(t, Copy(nil))
+	assert.Equal(t, amount, copied)
+
+	copied.SetInt64(8)
+	assert.Equal(t, int64(7), amount.Int64())
+}
@@ -0,0 +1,153 @@
+package amounts
+
+import (
+	"math/big"
+	"strings"
+)
+
+// The rounding policies of the amounts computed by the client, which are integers in
+// the smallest unit of a token.
+const (
+	// RoundFloor rounds down, towards negative infinity, e.g. so that a fee the client
+	// pays out is never more than its share.
+	RoundFloor Rounding = "floor"
+	// RoundCeil rounds up, towards positive infinity, e.g. so that a fee reserved for a
+	// payment always covers it.
+	RoundCeil Rounding = "ceil"
+	// RoundHalfEven rounds to the nearest amount, and halves to the even one, the
+	// rounding of the banks, whose errors cancel out over many amounts.
+	RoundHalfEven Rounding = "half_even"
+)
+
+// Rounding is how an amount is rounded when it is divided, formatted with fewer
+// decimals than its token has, or split in parts. The zero Rounding rounds down, like
+// RoundFloor, which is how the client used to round.
+type Rounding string
+
+// Quo returns the amount divided by the divisor, rounded. It panics when the divisor
+// is zero, like big.Int does.
+func (rounding Rounding) Quo(amount, divisor *big.Int) *big.Int {
+	var (
+		quotient, remainder = new(big.Int).QuoRem(OrZero(amount), divisor, new(big.Int))
+		// the quotient is truncated towards zero, it moves away from zero when
+		// rounded to a larger amount, in the direction of the sign of the result
+		direction = int64(OrZero(amount).Sign() * divisor.Sign())
+	)
+
+	if remainder.Sign() == 0 {
+		return quotient
+	}
+
+	switch rounding {
+	case RoundCeil:
+		if direction > 0 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	case RoundHalfEven:
+		var (
+			twice  = new(big.Int).Abs(remainder)
+			cmp    = twice.Lsh(twice, 1).Cmp(new(big.Int).Abs(divisor))
+			halfUp = cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1)
+		)
+
+		if halfUp {
+			quotient.Add(quotient, big.NewInt(direction))
+		}
+	default:
+		if direction < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		}
+	}
+
+	return quotient
+}
+
+// Scale returns the amount times numerator over denominator, rounded, e.g. the
+// proportional fee of an amount in parts per million with a denominator of 1000000.
+func (rounding Rounding) Scale(amount, numerator, denominator *big.Int) *big.Int {
+	return rounding.Quo(new(big.Int).Mul(OrZero(amount), OrZero(numerator)), denominator)
+}
+
+// Split divides a positive amount in the number of parts, which add up to the amount:
+// every part is the amount over the parts, rounded, but the last one which takes
+// what is left. Parts rounded up leave less to the last ones, which may be zero, e.g.
+// 5 in 4 parts rounded up is 2, 2, 1 and 0, and down 1, 1, 1 and 2.
+func (rounding Rounding) Split(amount *big.Int, parts int) []*big.Int {
+	if parts <= 0 {
+		return []*big.Int{}
+	}
+
+	var (
+		split     = make([]*big.Int, 0, parts)
+		share     = rounding.Quo(amount, big.NewInt(int64(parts)))
+		remaining = Copy(OrZero(amount))
+	)
+
+	for i := 0; i < parts-1; i++ {
+		part := Copy(share)
+
+		if part.Cmp(remaining) > 0 {
+			part = Copy(remaining)
+		}
+
+		split = append(split, part)
+		remaining.Sub(remaining, part)
+	}
+
+	return append(split, remaining)
+}
+
+// Places returns the amount rounded to places digits after the decimal point in whole
+// tokens of a token with the decimals, still in the smallest unit of the token, e.g.
+// 1555 with 3 decimals to 2 places as 1560 rounded up. Amounts with fewer decimals
+// than the places are returned as they are.
+func (rounding Rounding) Places(amount *big.Int, decimals, places int) *big.Int {
+	if places < 0 {
+		places = 0
+	}
+
+	if decimals <= places {
+		return Copy(OrZero(amount))
+	}
+
+	var (
+		unit     = new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-places)), nil)
+		quotient = rounding.Quo(amount, unit)
+	)
+
+	return quotient.Mul(quotient, unit)
+}
+
+// FormatRounded returns the amount like Format, rounded to places digits after the
+// decimal point, e.g. 1555000000000000000 of a token with 18 decimals as "1.56 DAI"
+// to 2 places rounding RoundHalfEven, so that displays don't truncate amounts silently.
+func (token *Token) FormatRounded(amount *big.Int, places int, rounding Rounding) string {
+	if token == nil {
+		return token.Format(amount)
+	}
+
+	var formatted = token.Format(rounding.Places(amount, token.Decimals, places))
+
+	if places <= 0 || token.Decimals <= 0 {
+		return formatted
+	}
+
+	// the places are padded with zeros, so that amounts align in columns
+	var number, symbol = formatted, ""
+
+	if index := strings.Index(formatted, " "); index >= 0 {
+		number, symbol = formatted[:index], formatted[index:]
+	}
+
+	if places > token.Decimals {
+		places = token.Decimals
+	}
+
+	if !strings.Contains(number, ".") {
+		number += "."
+	}
+
+	fraction := number[strings.Index(number, ".")+1:]
+
+	return number + strings.Repeat("0", places-len(fraction)) + symbol
+}
@@ -0,0 +1,107 @@
+package amounts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundingQuo(t *testing.T) {
+	type testcase struct {
+		amount   int64
+		divisor  int64
+		expected map[Rounding]int64
+	}
+
+	testcases := []testcase{
+		testcase{amount: 10, divisor: 4, expected: map[Rounding]int64{RoundFloor: 2, RoundCeil: 3, RoundHalfEven: 2, "": 2}},
+		testcase{amount: 14, divisor: 4, expected: map[Rounding]int64{RoundFloor: 3, RoundCeil: 4, RoundHalfEven: 4, "": 3}},
+		testcase{amount: 11, divisor: 4, expected: map[Rounding]int64{RoundFloor: 2, RoundCeil: 3, RoundHalfEven: 3, "": 2}},
+		testcase{amount: 9, divisor: 4, expected: map[Rounding]int64{RoundFloor: 2, RoundCeil: 3, RoundHalfEven: 2, "": 2}},
+		testcase{amount: 12, divisor: 4, expected: map[Rounding]int64{RoundFloor: 3, RoundCeil: 3, RoundHalfEven: 3, "": 3}},
+		testcase{amount: -10, divisor: 4, expected: map[Rounding]int64{RoundFloor: -3, RoundCeil: -2, RoundHalfEven: -2, "": -3}},
+		testcase{amount: -11, divisor: 4, expected: map[Rounding]int64{RoundFloor: -3, RoundCeil: -2, RoundHalfEven: -3, "": -3}},
+		testcase{amount: 10, divisor: -4, expected: map[Rounding]int64{RoundFloor: -3, RoundCeil: -2, RoundHalfEven: -2, "": -3}},
+	}
+
+	for _, tc := range testcases {
+		for rounding, expected := range tc.expected {
+			assert.Equal(t, expected, rounding.Quo(big.NewInt(tc.amount), big.NewInt(tc.divisor)).Int64(), "%d / %d rounding %q", tc.amount, tc.divisor, rounding)
+		}
+	}
+
+	assert.Equal(t, int64(0), RoundCeil.Quo(nil, big.NewInt(3)).Int64())
+}
+
+func TestRoundingScale(t *testing.T) {
+	// a proportional fee of 0.05% of 1999
+	assert.Equal(t, int64(0), RoundFloor.Scale(big.NewInt(1999), big.NewInt(500), big.NewInt(1000000)).Int64())
+	assert.Equal(t, int64(1), RoundCeil.Scale(big.NewInt(1999), big.NewInt(500), big.NewInt(1000000)).Int64())
+	assert.Equal(t, int64(1), RoundHalfEven.Scale(big.NewInt(1999), big.NewInt(500), big.NewInt(1000000)).Int64())
+}
+
+func TestRoundingSplit(t *testing.T) {
+	type testcase struct {
+		name     string
+		rounding Rounding
+		amount   int64
+		parts    int
+		expected []int64
+	}
+
+	testcases := []testcase{
+		testcase{name: "even amount", rounding: RoundCeil, amount: 12, parts: 3, expected: []int64{4, 4, 4}},
+		testcase{name: "floor", rounding: RoundFloor, amount: 5, parts: 4, expected: []int64{1, 1, 1, 2}},
+		testcase{name: "ceil", rounding: RoundCeil, amount: 5, parts: 4, expected: []int64{2, 2, 1, 0}},
+		testcase{name: "half even", rounding: RoundHalfEven, amount: 10, parts: 4, expected: []int64{2, 2, 2, 4}},
+		testcase{name: "single part", rounding: RoundCeil, amount: 7, parts: 1, expected: []int64{7}},
+		testcase{name: "no parts", rounding: RoundCeil, amount: 7, parts: 0, expected: []int64{}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var split = make([]int64, 0)
+
+			for _, part := range tc.rounding.Split(big.NewInt(tc.amount), tc.parts) {
+				split = append(split, part.Int64())
+			}
+
+			assert.Equal(t, tc.expected, split)
+		})
+	}
+}
+
+func TestTokenFormatRounded(t *testing.T) {
+	type testcase struct {
+		name           string
+		token          *Token
+		amount         *big.Int
+		places         int
+		rounding       Rounding
+		expectedFormat string
+	}
+
+	var (
+		dai        = &Token{Symbol: "DAI", Decimals: 18}
+		amount, _  = new(big.Int).SetString("1555000000000000000", 10)
+		halfway, _ = new(big.Int).SetString("1565000000000000000", 10)
+	)
+
+	testcases := []testcase{
+		testcase{name: "floor", token: dai, amount: amount, places: 2, rounding: RoundFloor, expectedFormat: "1.55 DAI"},
+		testcase{name: "ceil", token: dai, amount: amount, places: 2, rounding: RoundCeil, expectedFormat: "1.56 DAI"},
+		testcase{name: "half even up", token: dai, amount: amount, places: 2, rounding: RoundHalfEven, expectedFormat: "1.56 DAI"},
+		testcase{name: "half even down", token: dai, amount: halfway, places: 2, rounding: RoundHalfEven, expectedFormat: "1.56 DAI"},
+		testcase{name: "padded places", token: dai, amount: big.NewInt(1500000000000000000), places: 2, rounding: RoundFloor, expectedFormat: "1.50 DAI"},
+		testcase{name: "whole tokens", token: dai, amount: amount, places: 0, rounding: RoundHalfEven, expectedFormat: "2 DAI"},
+		testcase{name: "more places than decimals", token: &Token{Decimals: 2}, amount: big.NewInt(150), places: 4, rounding: RoundCeil, expectedFormat: "1.50"},
+		testcase{name: "unknown token", amount: big.NewInt(1234), places: 2, rounding: RoundCeil, expectedFormat: "1234"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedFormat, tc.token.FormatRounded(tc.amount, tc.places, tc.rounding))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package amounts
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// Token describes the unit of the amounts of a token, which the Raiden node does not
+// know of: amounts are integers in the smallest unit of the token, which has
+// Decimals digits after the decimal point in whole tokens.
+type Token struct {
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+}
+
+// Format returns the amount in whole tokens followed by the symbol of the token, e.g.
+// 1500000000000000000 of a token with 18 decimals as "1.5 DAI". A nil token formats
+// the amount as the integer it is.
+func (token *Token) Format(amount *big.Int) string {
+	if token == nil {
+		return OrZero(amount).String()
+	}
+
+	if token.Symbol == "" {
+		return FormatUnits(amount, token.Decimals)
+	}
+
+	return FormatUnits(amount, token.Decimals) + " " + token.Symbol
+}
+
+// Parse parses an amount given in whole tokens, see ParseUnits. A nil token parses
+// the amount as an integer.
+func (token *Token) Parse(value string) (*big.Int, error) {
+	if token == nil {
+		return ParseUnits(value, 0)
+	}
+
+	return ParseUnits(value, token.Decimals)
+}
+
+// FormatUnits returns an amount in the smallest unit of a token as whole tokens, e.g.
+// 1500000000000000000 with 18 decimals as 1.5.
+func FormatUnits(amount *big.Int, decimals int) string {
+	var digits = OrZero(amount).String()
+
+	if decimals <= 0 {
+		return digits
+	}
+
+	negative := strings.HasPrefix(digits, "-")
+	digits = strings.TrimPrefix(digits, "-")
+
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	if negative {
+		whole = "-" + whole
+	}
+
+	if fraction == "" {
+		return whole
+	}
+
+	return whole + "." + fraction
+}
+
+// ParseUnits parses a positive amount given in whole tokens with up to decimals digits
+// after the decimal point, such as 1.5, into the smallest unit of the token.
+func ParseUnits(value string, decimals int) (*big.Int, error) {
+	var (
+		whole    = value
+		fraction string
+	)
+
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	if parts := strings.SplitN(value, ".", 2); len(parts) == 2 {
+		whole, fraction = parts[0], parts[1]
+	}
+
+	if len(fraction) > decimals {
+		return nil, fmt.Errorf("invalid amount: %s has more than %d decimals", value, decimals)
+	}
+
+	amount, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", decimals-len(fraction)), 10)
+	if !ok || amount.Sign() < 0 || strings.ContainsAny(whole+fraction, "+-") {
+		return nil, fmt.Errorf("invalid amount: %s", value)
+	}
+
+	return amount, nil
+}
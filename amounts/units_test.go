@@ -0,0 +1,74 @@
+package amounts
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenFormat(t *testing.T) {
+	type testcase struct {
+		name           string
+		token          *Token
+		amount         *big.Int
+		expectedFormat string
+	}
+
+	var (
+		dai           = &Token{Symbol: "DAI", Decimals: 18}
+		largeValue, _ = new(big.Int).SetString("25000000000000000000000", 10)
+	)
+
+	testcases := []testcase{
+		testcase{name: "whole tokens", token: dai, amount: largeValue, expectedFormat: "25000 DAI"},
+		testcase{name: "fraction of a token", token: dai, amount: big.NewInt(1500000000000000), expectedFormat: "0.0015 DAI"},
+		testcase{name: "negative amount", token: &Token{Symbol: "WETH", Decimals: 2}, amount: big.NewInt(-150), expectedFormat: "-1.5 WETH"},
+		testcase{name: "nil amount", token: dai, expectedFormat: "0 DAI"},
+		testcase{name: "token without symbol", token: &Token{Decimals: 3}, amount: big.NewInt(1234), expectedFormat: "1.234"},
+		testcase{name: "unknown token", amount: big.NewInt(1234), expectedFormat: "1234"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedFormat, tc.token.Format(tc.amount))
+		})
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	type testcase struct {
+		value          string
+		decimals       int
+		expectedAmount string
+		expectedError  string
+	}
+
+	testcases := []testcase{
+		testcase{value: "1.5", decimals: 18, expectedAmount: "1500000000000000000"},
+		testcase{value: "42", decimals: 2, expectedAmount: "4200"},
+		testcase{value: "42", decimals: 0, expectedAmount: "42"},
+		testcase{value: ".5", decimals: 1, expectedAmount: "5"},
+		testcase{value: "0.105", decimals: 2, expectedError: "invalid amount: 0.105 has more than 2 decimals"},
+		testcase{value: "-1", decimals: 2, expectedError: "invalid amount: -1"},
+		testcase{value: "1.-5", decimals: 2, expectedError: "invalid amount: 1.-5"},
+		testcase{value: "ten", decimals: 2, expectedError: "invalid amount: ten"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.value, func(t *testing.T) {
+			amount, err := ParseUnits(tc.value, tc.decimals)
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAmount, amount.String())
+			assert.Equal(t, tc.value != ".5", FormatUnits(amount, tc.decimals) == tc.value)
+		})
+	}
+}
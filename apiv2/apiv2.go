@@ -0,0 +1,82 @@
+// Package apiv2 describes how version 2 of the Raiden node API differs from version 1,
+// for the client to route its requests and translate their payloads when configured
+// with APIVersion "v2". Endpoints and fields not listed here are the same in both.
+//
+// Responses of version 2 have the shapes of the payloads of Raiden 1.x, which the
+// client decodes whatever the API version, see config.Config.NodeVersion.
+package apiv2
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+// Version is the APIVersion of a configuration using version 2 of the API.
+const Version = "v2"
+
+// resources are the resources of version 1 at another path in version 2.
+var resources = map[string]string{
+	"pending_transfers": "transfers/pending",
+}
+
+// amountFields are the fields of request payloads holding token amounts, which
+// version 2 takes as decimal strings rather than JSON numbers.
+var amountFields = []string{"amount", "total_deposit", "funds"}
+
+// Path returns the path of version 2 for the path of an endpoint of version 1,
+// relative to the API root, e.g. "transfers/pending/0x..." for
+// "pending_transfers/0x...".
+func Path(path string) string {
+	for from, to := range resources {
+		if path == from || strings.HasPrefix(path, from+"/") {
+			return to + strings.TrimPrefix(path, from)
+		}
+	}
+
+	return path
+}
+
+// Resource returns the resource of version 1 of a URL path of version 2, e.g.
+// "pending_transfers" for "/api/v2/transfers/pending/0x...", and the first segment
+// after the API root for resources at the same path in both versions.
+func Resource(path string) string {
+	var _, version, relative, ok = config.SplitAPIPath(path)
+
+	if !ok || version != Version {
+		return ""
+	}
+
+	path = relative
+
+	for from, to := range resources {
+		if path == to || strings.HasPrefix(path, to+"/") {
+			return from
+		}
+	}
+
+	return strings.SplitN(path, "/", 2)[0]
+}
+
+// EncodeRequest rewrites the JSON payload of a request of version 1 into the shape
+// of version 2. Payloads that are not JSON objects are returned as is.
+func EncodeRequest(data []byte) ([]byte, error) {
+	var object map[string]interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&object); err != nil || object == nil {
+		return data, nil
+	}
+
+	for _, name := range amountFields {
+		if amount, ok := object[name].(json.Number); ok {
+			object[name] = amount.String()
+		}
+	}
+
+	return json.Marshal(object)
+}
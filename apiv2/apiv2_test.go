@@ -0,0 +1,62 @@
+package apiv2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPath(t *testing.T) {
+	assert.Equal(t, "channels/0x9aBa529db3FF2D8409A1da4C9eB148879b046700", Path("channels/0x9aBa529db3FF2D8409A1da4C9eB148879b046700"))
+	assert.Equal(t, "transfers/pending", Path("pending_transfers"))
+	assert.Equal(t, "transfers/pending/0x9aBa529db3FF2D8409A1da4C9eB148879b046700", Path("pending_transfers/0x9aBa529db3FF2D8409A1da4C9eB148879b046700"))
+	assert.Equal(t, "pending_transfersx", Path("pending_transfersx"))
+}
+
+func TestResource(t *testing.T) {
+	assert.Equal(t, "channels", Resource("/api/v2/channels/0x9aBa529db3FF2D8409A1da4C9eB148879b046700"))
+	assert.Equal(t, "pending_transfers", Resource("/api/v2/transfers/pending"))
+	assert.Equal(t, "pending_transfers", Resource("/api/v2/transfers/pending/0x9aBa529db3FF2D8409A1da4C9eB148879b046700"))
+	assert.Equal(t, "", Resource("/api/v1/channels"))
+}
+
+func TestEncodeRequest(t *testing.T) {
+	type testcase struct {
+		name         string
+		payload      string
+		expectedJSON string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "payment amount",
+			payload:      `{"amount":1000000000000000000000,"identifier":42}`,
+			expectedJSON: `{"amount":"1000000000000000000000","identifier":42}`,
+		},
+		testcase{
+			name:         "channel deposit",
+			payload:      `{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","total_deposit":35000000,"settle_timeout":500}`,
+			expectedJSON: `{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","total_deposit":"35000000","settle_timeout":500}`,
+		},
+		testcase{
+			name:         "connection funds",
+			payload:      `{"funds":1337}`,
+			expectedJSON: `{"funds":"1337"}`,
+		},
+		testcase{
+			name:         "not an object",
+			payload:      `[1,2]`,
+			expectedJSON: `[1,2]`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := EncodeRequest([]byte(tc.payload))
+			require.NoError(t, err)
+
+			assert.JSONEq(t, tc.expectedJSON, string(data))
+		})
+	}
+}
@@ -0,0 +1,243 @@
+// Package backpressure sends payments at the pace a Raiden node can take, slowing down
+// and pausing new payments while the node is congested instead of blasting it into
+// the cascade of failures that follows.
+package backpressure
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultMaxInFlight is the number of payments sent at once by a sender whose
+	// options have none.
+	DefaultMaxInFlight = 8
+	// DefaultMaxPending is the number of pending transfers of the node at which a
+	// sender whose options have none pauses.
+	DefaultMaxPending = 100
+	// DefaultPollInterval is the time between two polls of the pending transfers of
+	// the node when no interval is given.
+	DefaultPollInterval = time.Second
+)
+
+// Options configures a sender. New payments are paused once the node has MaxPending
+// pending transfers, until they drop to ResumePending, half of MaxPending when zero.
+// The polls are paced by Clock, the Clock of the configuration when nil.
+type Options struct {
+	MaxInFlight   int
+	MaxPending    int
+	ResumePending int
+	PollInterval  time.Duration
+	Clock         clock.Clock
+}
+
+// Stats is the state of a sender: the pending transfers of the node at the last poll,
+// the payments being sent and the number of them allowed at once. PollErr is the error
+// of the last poll, nil when it succeeded.
+type Stats struct {
+	Pending  int
+	InFlight int
+	Limit    int
+	Paused   bool
+	PollErr  error
+}
+
+// Sender sends payments while adapting to the congestion of the node. The number of
+// payments sent at once grows by one after every poll finding the node below
+// ResumePending, is halved when it is above, or when a payment fails because the node
+// is overloaded, and drops to zero, pausing new payments, when the node reaches
+// MaxPending. It is safe for concurrent use.
+type Sender struct {
+	initiator      payments.Initiator
+	transferLister pendingtransfers.Lister
+	options        Options
+	clock          clock.Clock
+
+	mutex   sync.Mutex
+	stats   Stats
+	polled  time.Time
+	polling bool
+	changed chan struct{}
+}
+
+// NewSender creates an adaptive sender of payments given a Raiden node configuration,
+// an http client and options, nil options being the defaults.
+func NewSender(config *config.Config, httpClient *http.Client, options *Options) *Sender {
+	var sender = &Sender{
+		initiator:      payments.NewInitiator(config, httpClient),
+		transferLister: pendingtransfers.NewLister(config, httpClient),
+		clock:          config.Time(),
+		changed:        make(chan struct{}),
+	}
+
+	if options != nil {
+		sender.options = *options
+	}
+
+	if sender.options.Clock != nil {
+		sender.clock = sender.options.Clock
+	}
+
+	if sender.options.MaxInFlight <= 0 {
+		sender.options.MaxInFlight = DefaultMaxInFlight
+	}
+
+	if sender.options.MaxPending <= 0 {
+		sender.options.MaxPending = DefaultMaxPending
+	}
+
+	if sender.options.ResumePending <= 0 || sender.options.ResumePending > sender.options.MaxPending {
+		sender.options.ResumePending = sender.options.MaxPending / 2
+	}
+
+	if sender.options.PollInterval <= 0 {
+		sender.options.PollInterval = DefaultPollInterval
+	}
+
+	sender.stats.Limit = sender.options.MaxInFlight
+
+	return sender
+}
+
+// Send will wait until the node can take another payment, or the context is done,
+// then send the payment.
+func (sender *Sender) Send(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64) (*payments.Payment, error) {
+	var (
+		err     error
+		payment *payments.Payment
+	)
+
+	if err = sender.acquire(ctx); err != nil {
+		return nil, err
+	}
+
+	payment, err = sender.initiator.InitiateWithIdentifier(ctx, tokenAddress, targetAddress, amount, identifier)
+
+	sender.release(err)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// Stats returns the current state of the sender.
+func (sender *Sender) Stats() Stats {
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+
+	return sender.stats
+}
+
+// acquire waits for the sender to allow another payment, polling the node while the
+// sender is paused.
+func (sender *Sender) acquire(ctx context.Context) error {
+	for {
+		sender.poll(ctx)
+
+		sender.mutex.Lock()
+
+		if !sender.stats.Paused && sender.stats.InFlight < sender.stats.Limit {
+			sender.stats.InFlight++
+			sender.mutex.Unlock()
+
+			return nil
+		}
+
+		var (
+			changed = sender.changed
+			polled  = sender.clock.After(sender.options.PollInterval)
+		)
+
+		sender.mutex.Unlock()
+
+		select {
+		case <-changed:
+		case <-polled:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// release ends a payment, halving the limit when the node was overloaded.
+func (sender *Sender) release(err error) {
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+
+	sender.stats.InFlight--
+
+	if err != nil && raidenerrors.IsRetryable(err) {
+		sender.decrease()
+	}
+
+	sender.broadcast()
+}
+
+// poll updates the pending transfers of the node when the last poll is older than the
+// poll interval, unless another call is already polling. Failed polls leave the
+// state as it was until the next poll.
+func (sender *Sender) poll(ctx context.Context) {
+	sender.mutex.Lock()
+
+	if sender.polling || sender.clock.Now().Sub(sender.polled) < sender.options.PollInterval {
+		sender.mutex.Unlock()
+		return
+	}
+
+	sender.polling = true
+	sender.mutex.Unlock()
+
+	transfers, err := sender.transferLister.ListAll(ctx)
+
+	sender.mutex.Lock()
+	defer sender.mutex.Unlock()
+
+	sender.polling = false
+	sender.polled = sender.clock.Now()
+	sender.stats.PollErr = err
+
+	if err != nil {
+		return
+	}
+
+	sender.stats.Pending = len(transfers)
+
+	switch {
+	case sender.stats.Pending >= sender.options.MaxPending:
+		sender.stats.Paused = true
+	case sender.stats.Pending > sender.options.ResumePending:
+		sender.decrease()
+	default:
+		sender.stats.Paused = false
+
+		if sender.stats.Limit < sender.options.MaxInFlight {
+			sender.stats.Limit++
+		}
+	}
+
+	sender.broadcast()
+}
+
+func (sender *Sender) decrease() {
+	if sender.stats.Limit /= 2; sender.stats.Limit < 1 {
+		sender.stats.Limit = 1
+	}
+}
+
+// broadcast wakes up the calls waiting for the state of the sender to change.
+func (sender *Sender) broadcast() {
+	close(sender.changed)
+	sender.changed = make(chan struct{})
+}
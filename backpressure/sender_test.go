@@ -0,0 +1,260 @@
+package backpressure
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSender() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		sender        = NewSender(config, http.DefaultClient, &Options{MaxInFlight: 4, MaxPending: 50})
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		waitGroup     sync.WaitGroup
+	)
+
+	for identifier := int64(1); identifier <= 1000; identifier++ {
+		waitGroup.Add(1)
+
+		go func(identifier int64) {
+			defer waitGroup.Done()
+
+			if _, err := sender.Send(context.Background(), tokenAddress, targetAddress, big.NewInt(10), identifier); err != nil {
+				fmt.Printf("payment %d failed: %s\n", identifier, err.Error())
+			}
+		}(identifier)
+	}
+
+	waitGroup.Wait()
+}
+
+func TestSender(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var (
+		mutex         sync.Mutex
+		pending       int
+		sent          int
+		paymentStatus = http.StatusOK
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		sender        = NewSender(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient, &Options{
+			MaxInFlight:   4,
+			MaxPending:    10,
+			ResumePending: 2,
+			PollInterval:  time.Millisecond,
+		})
+		setPending = func(count int) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			pending = count
+		}
+		send = func(timeout time.Duration) error {
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+
+			_, err := sender.Send(ctx, tokenAddress, targetAddress, big.NewInt(10), 1)
+
+			return err
+		}
+	)
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", func(request *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		transfers := make([]string, pending)
+		for i := range transfers {
+			transfers[i] = `{"channel_identifier":1,"initiator":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","locked_amount":10,"payment_identifier":1,"role":"initiator","target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","transferred_amount":0}`
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, "["+strings.Join(transfers, ",")+"]"), nil
+	})
+
+	httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", func(request *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		sent++
+
+		if paymentStatus != http.StatusOK {
+			return httpmock.NewStringResponse(paymentStatus, ""), nil
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, `{"initiator_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","amount":10,"identifier":1}`), nil
+	})
+
+	t.Run("idle node", func(t *testing.T) {
+		require.NoError(t, send(time.Second))
+
+		assert.Equal(t, Stats{Pending: 0, InFlight: 0, Limit: 4}, sender.Stats())
+	})
+
+	t.Run("overloaded node halves the limit", func(t *testing.T) {
+		mutex.Lock()
+		paymentStatus = http.StatusServiceUnavailable
+		mutex.Unlock()
+
+		assert.Error(t, send(time.Second))
+		assert.Equal(t, 2, sender.Stats().Limit)
+
+		mutex.Lock()
+		paymentStatus = http.StatusOK
+		mutex.Unlock()
+	})
+
+	t.Run("congested node pauses payments", func(t *testing.T) {
+		setPending(10)
+
+		require.Eventually(t, func() bool { return send(10*time.Millisecond) != nil }, time.Second, time.Millisecond)
+
+		mutex.Lock()
+		sentBefore := sent
+		mutex.Unlock()
+
+		assert.Equal(t, context.DeadlineExceeded, send(20*time.Millisecond))
+		assert.True(t, sender.Stats().Paused)
+
+		setPending(5)
+
+		assert.Equal(t, context.DeadlineExceeded, send(20*time.Millisecond))
+		assert.True(t, sender.Stats().Paused)
+		assert.Equal(t, 1, sender.Stats().Limit)
+
+		mutex.Lock()
+		assert.Equal(t, sentBefore, sent)
+		mutex.Unlock()
+	})
+
+	t.Run("payments resume", func(t *testing.T) {
+		var errs = make(chan error, 3)
+
+		for i := 0; i < 3; i++ {
+			go func() { errs <- send(time.Second) }()
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		setPending(1)
+
+		for i := 0; i < 3; i++ {
+			assert.NoError(t, <-errs)
+		}
+
+		require.Eventually(t, func() bool {
+			require.NoError(t, send(time.Second))
+			return sender.Stats().Limit == 4
+		}, time.Second, time.Millisecond)
+
+		assert.False(t, sender.Stats().Paused)
+	})
+}
+
+func TestSenderLimitsInFlight(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var (
+		mutex       sync.Mutex
+		inFlight    int
+		maxInFlight int
+		sender      = NewSender(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient, &Options{MaxInFlight: 2})
+		waitGroup   sync.WaitGroup
+	)
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", httpmock.NewStringResponder(http.StatusOK, `[]`))
+	httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", func(request *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		if inFlight++; inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mutex.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mutex.Lock()
+		inFlight--
+		mutex.Unlock()
+
+		return httpmock.NewStringResponse(http.StatusOK, `{"amount":10,"identifier":1}`), nil
+	})
+
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			_, err := sender.Send(context.Background(), common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"), common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"), big.NewInt(10), 1)
+			assert.NoError(t, err)
+		}()
+	}
+
+	waitGroup.Wait()
+
+	assert.Equal(t, 2, maxInFlight)
+}
+
+func TestSenderClock(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var (
+		mutex    sync.Mutex
+		pending  = `[{"channel_identifier":1,"locked_amount":10,"payment_identifier":1,"role":"initiator","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"}]`
+		polls    int
+		manual   = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+		sender   = NewSender(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient, &Options{MaxPending: 1, PollInterval: time.Minute, Clock: manual})
+		errs     = make(chan error, 1)
+		paidURL  = "POST http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		paidJSON = `{"amount":10,"identifier":1}`
+	)
+
+	// the node is congested at the first poll only
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", func(request *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if polls++; polls > 1 {
+			return httpmock.NewStringResponse(http.StatusOK, `[]`), nil
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, pending), nil
+	})
+	httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK, paidJSON))
+
+	go func() {
+		_, err := sender.Send(context.Background(), common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"), common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"), big.NewInt(10), 1)
+		errs <- err
+	}()
+
+	// the payment waits for the next poll, a minute later
+	manual.BlockUntil(1)
+
+	assert.True(t, sender.Stats().Paused)
+	assert.Equal(t, 0, httpmock.GetCallCountInfo()[paidURL])
+
+	manual.Advance(time.Minute)
+
+	require.NoError(t, <-errs)
+	assert.False(t, sender.Stats().Paused)
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()[paidURL])
+}
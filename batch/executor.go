@@ -0,0 +1,235 @@
+// Package batch executes a list of heterogeneous operations (opening channels,
+// deposits, payments) across token networks against a Raiden node, respecting the
+// dependencies between them and a limit on how many run at once.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/idempotency"
+	"github.com/cpurta/go-raiden-client/payments"
+)
+
+// DefaultConcurrency is the number of operations run at once when a non positive
+// concurrency is given to NewExecutor.
+const DefaultConcurrency = 4
+
+// Executor is a generic interface to run a batch of operations against a Raiden node.
+type Executor interface {
+	Execute(ctx context.Context, operations []*Operation) (*Report, error)
+}
+
+// NewExecutor creates a new default batch executor given a Raiden node configuration,
+// an http client and the maximum number of operations to run concurrently. The
+// operations are timed, and aged while waiting, on the Clock of the configuration.
+func NewExecutor(config *config.Config, httpClient *http.Client, concurrency int) Executor {
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	return &defaultExecutor{
+		channelClient: channels.NewClient(config, httpClient),
+		paymentClient: payments.NewClient(config, httpClient),
+		concurrency:   concurrency,
+		aging:         PriorityAging,
+		clock:         config.Time(),
+	}
+}
+
+// NewExecutorWithStore creates a new default batch executor that sends payments with
+// an identifier through an idempotent payer backed by the given store, so that running
+// the same batch again after a crash never pays twice.
+func NewExecutorWithStore(config *config.Config, httpClient *http.Client, concurrency int, store idempotency.Store) Executor {
+	var executor = NewExecutor(config, httpClient, concurrency).(*defaultExecutor)
+
+	executor.payer = idempotency.NewPayer(config, httpClient, store)
+
+	return executor
+}
+
+type defaultExecutor struct {
+	channelClient *channels.Client
+	paymentClient *payments.Client
+	payer         idempotency.Payer
+	concurrency   int
+	aging         time.Duration
+	clock         clock.Clock
+}
+
+// Execute will run every operation once all of its dependencies have succeeded. When
+// more operations are ready than may run at once, the ones with the highest Priority
+// run first, see PriorityAging. The returned error is only set when the batch itself is invalid (duplicate IDs, unknown
+// or circular dependencies), failures of single operations are part of the Report.
+func (executor *defaultExecutor) Execute(ctx context.Context, operations []*Operation) (*Report, error) {
+	var (
+		err       error
+		waitGroup sync.WaitGroup
+		scheduler = newScheduler(executor.concurrency, executor.aging, executor.clock)
+		results   = make(map[string]*Result)
+		done      = make(map[string]chan struct{})
+		report    = &Report{
+			Results: make([]*Result, 0, len(operations)),
+		}
+	)
+
+	if err = validate(operations); err != nil {
+		return nil, err
+	}
+
+	for _, operation := range operations {
+		results[operation.ID] = &Result{Operation: operation}
+		done[operation.ID] = make(chan struct{})
+	}
+
+	for _, operation := range operations {
+		waitGroup.Add(1)
+
+		go func(operation *Operation) {
+			var result = results[operation.ID]
+
+			defer waitGroup.Done()
+			defer close(done[operation.ID])
+
+			for _, dependency := range operation.DependsOn {
+				<-done[dependency]
+
+				if results[dependency].Status != Succeeded {
+					result.Status = Skipped
+					result.Err = fmt.Errorf("dependency %s did not succeed", dependency)
+					return
+				}
+			}
+
+			if err := scheduler.acquire(ctx, operation.Priority); err != nil {
+				result.Status = Skipped
+				result.Err = err
+				return
+			}
+
+			defer scheduler.release()
+
+			executor.perform(ctx, result)
+		}(operation)
+	}
+
+	waitGroup.Wait()
+
+	for _, operation := range operations {
+		var result = results[operation.ID]
+
+		switch result.Status {
+		case Succeeded:
+			report.Succeeded++
+		case Failed:
+			report.Failed++
+		case Skipped:
+			report.Skipped++
+		}
+
+		report.Results = append(report.Results, result)
+	}
+
+	return report, nil
+}
+
+func (executor *defaultExecutor) perform(ctx context.Context, result *Result) {
+	var (
+		err       error
+		operation = result.Operation
+	)
+
+	result.Started = executor.clock.Now()
+
+	switch operation.Type {
+	case OpenChannel:
+		result.Channel, err = executor.channelClient.Open(ctx, operation.TokenAddress, operation.PartnerAddress, operation.Amount, operation.SettleTimeout)
+	case Deposit:
+		result.Channel, err = executor.channelClient.IncreaseDeposit(ctx, operation.TokenAddress, operation.PartnerAddress, operation.Amount)
+	case CloseChannel:
+		result.Channel, err = executor.channelClient.Close(ctx, operation.TokenAddress, operation.PartnerAddress)
+	case Pay:
+		if executor.payer != nil && operation.Identifier != 0 {
+			result.Payment, err = executor.payer.SendIdempotent(ctx, operation.TokenAddress, operation.PartnerAddress, operation.Amount, operation.Identifier)
+			break
+		}
+
+		result.Payment, err = executor.paymentClient.InitiateWithIdentifier(ctx, operation.TokenAddress, operation.PartnerAddress, operation.Amount, operation.Identifier)
+	default:
+		err = fmt.Errorf("unknown operation type: %s", operation.Type)
+	}
+
+	result.Finished = executor.clock.Now()
+
+	if err != nil {
+		result.Status = Failed
+		result.Err = err
+		return
+	}
+
+	result.Status = Succeeded
+}
+
+// validate will ensure that every operation has a unique ID and that the dependencies
+// between operations reference known operations and do not form a cycle.
+func validate(operations []*Operation) error {
+	var (
+		dependencies = make(map[string][]string)
+		visiting     = make(map[string]bool)
+		visited      = make(map[string]bool)
+		visit        func(id string) error
+	)
+
+	for _, operation := range operations {
+		if operation.ID == "" {
+			return fmt.Errorf("operation of type %s is missing an ID", operation.Type)
+		}
+
+		if _, ok := dependencies[operation.ID]; ok {
+			return fmt.Errorf("duplicate operation ID: %s", operation.ID)
+		}
+
+		dependencies[operation.ID] = operation.DependsOn
+	}
+
+	visit = func(id string) error {
+		if visited[id] {
+			return nil
+		}
+
+		if visiting[id] {
+			return fmt.Errorf("circular dependency on operation: %s", id)
+		}
+
+		visiting[id] = true
+
+		for _, dependency := range dependencies[id] {
+			if _, ok := dependencies[dependency]; !ok {
+				return fmt.Errorf("operation %s depends on unknown operation: %s", id, dependency)
+			}
+
+			if err := visit(dependency); err != nil {
+				return err
+			}
+		}
+
+		visiting[id] = false
+		visited[id] = true
+
+		return nil
+	}
+
+	for _, operation := range operations {
+		if err := visit(operation.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,191 @@
+package batch
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/idempotency"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleExecutor() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		daiAddress     = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		partnerAddress = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+		executor       = NewExecutor(config, http.DefaultClient, 2)
+		report         *Report
+		err            error
+	)
+
+	if report, err = executor.Execute(context.Background(), []*Operation{
+		&Operation{ID: "open-dai", Type: OpenChannel, TokenAddress: daiAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(1000), SettleTimeout: 500},
+		&Operation{ID: "pay-dai", Type: Pay, TokenAddress: daiAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(10), Identifier: 1, DependsOn: []string{"open-dai"}},
+	}); err != nil {
+		panic(fmt.Sprintf("invalid batch: %s", err.Error()))
+	}
+
+	fmt.Printf("succeeded: %d, failed: %d, skipped: %d\n", report.Succeeded, report.Failed, report.Skipped)
+}
+
+func TestExecutor(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		otherToken     = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		channelJSON    = `{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":100,"total_deposit":100,"state":"opened","settle_timeout":500,"reveal_timeout":30}`
+	)
+
+	type testcase struct {
+		name             string
+		prepHTTPMock     func()
+		operations       []*Operation
+		expectedStatuses map[string]Status
+		expectedError    error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully executed dependent operations",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusCreated, channelJSON))
+				httpmock.RegisterResponder("PATCH", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK, channelJSON))
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK, `{"amount":10,"identifier":1}`))
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/0x2a65Aca4D5fC5B5C859090a6c34d164135398226/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK, `{"amount":5,"identifier":2}`))
+			},
+			operations: []*Operation{
+				&Operation{ID: "open", Type: OpenChannel, TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(100), SettleTimeout: 500},
+				&Operation{ID: "deposit", Type: Deposit, TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(200), DependsOn: []string{"open"}},
+				&Operation{ID: "pay", Type: Pay, TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(10), Identifier: 1, DependsOn: []string{"deposit"}},
+				&Operation{ID: "pay-other", Type: Pay, TokenAddress: otherToken, PartnerAddress: partnerAddress, Amount: big.NewInt(5), Identifier: 2},
+			},
+			expectedStatuses: map[string]Status{
+				"open":      Succeeded,
+				"deposit":   Succeeded,
+				"pay":       Succeeded,
+				"pay-other": Succeeded,
+			},
+		},
+		testcase{
+			name: "failed operation skips its dependents",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusConflict, ``))
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/0x2a65Aca4D5fC5B5C859090a6c34d164135398226/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK, `{"amount":5,"identifier":2}`))
+			},
+			operations: []*Operation{
+				&Operation{ID: "open", Type: OpenChannel, TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(100), SettleTimeout: 500},
+				&Operation{ID: "pay", Type: Pay, TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(10), Identifier: 1, DependsOn: []string{"open"}},
+				&Operation{ID: "pay-other", Type: Pay, TokenAddress: otherToken, PartnerAddress: partnerAddress, Amount: big.NewInt(5), Identifier: 2},
+			},
+			expectedStatuses: map[string]Status{
+				"open":      Failed,
+				"pay":       Skipped,
+				"pay-other": Succeeded,
+			},
+		},
+		testcase{
+			name:         "circular dependencies",
+			prepHTTPMock: func() {},
+			operations: []*Operation{
+				&Operation{ID: "a", Type: Pay, DependsOn: []string{"b"}},
+				&Operation{ID: "b", Type: Pay, DependsOn: []string{"a"}},
+			},
+			expectedError: fmt.Errorf("circular dependency on operation: a"),
+		},
+		testcase{
+			name:         "unknown dependency",
+			prepHTTPMock: func() {},
+			operations: []*Operation{
+				&Operation{ID: "a", Type: Pay, DependsOn: []string{"missing"}},
+			},
+			expectedError: fmt.Errorf("operation a depends on unknown operation: missing"),
+		},
+		testcase{
+			name:         "duplicate operation IDs",
+			prepHTTPMock: func() {},
+			operations: []*Operation{
+				&Operation{ID: "a", Type: Pay},
+				&Operation{ID: "a", Type: Pay},
+			},
+			expectedError: fmt.Errorf("duplicate operation ID: a"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err      error
+				report   *Report
+				executor = NewExecutor(config, http.DefaultClient, 2)
+				ctx      = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.Deactivate()
+
+			tc.prepHTTPMock()
+
+			report, err = executor.Execute(ctx, tc.operations)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, report.Results, len(tc.operations))
+
+			for id, status := range tc.expectedStatuses {
+				assert.Equal(t, status, report.Result(id).Status, id)
+			}
+
+			for i, result := range report.Results {
+				assert.Equal(t, tc.operations[i], result.Operation)
+			}
+		})
+	}
+}
+
+func TestExecutorWithStore(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		paymentURL = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		executor   = NewExecutorWithStore(config, http.DefaultClient, 2, idempotency.NewMemoryStore())
+		operations = []*Operation{
+			&Operation{ID: "pay", Type: Pay, TokenAddress: common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"), PartnerAddress: common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"), Amount: big.NewInt(10), Identifier: 1},
+		}
+		ctx = context.Background()
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", paymentURL, httpmock.NewStringResponder(http.StatusOK, `{"amount":10,"identifier":1}`))
+
+	for i := 0; i < 2; i++ {
+		report, err := executor.Execute(ctx, operations)
+
+		require.NoError(t, err)
+		assert.True(t, report.OK())
+		assert.Equal(t, int64(1), report.Result("pay").Payment.Identifier)
+	}
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST "+paymentURL], "a batch run twice must only pay once")
+}
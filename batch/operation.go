@@ -0,0 +1,90 @@
+package batch
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OperationType is the kind of call an Operation will make to the Raiden node.
+type OperationType string
+
+const (
+	// OpenChannel opens a channel with the partner using Amount as the deposit.
+	OpenChannel OperationType = "open"
+	// Deposit increases the total deposit of the channel with the partner to Amount.
+	Deposit OperationType = "deposit"
+	// CloseChannel closes the channel with the partner.
+	CloseChannel OperationType = "close"
+	// Pay sends Amount to the partner using Identifier as the payment identifier.
+	Pay OperationType = "pay"
+)
+
+// Operation is a single step of a batch. An operation will only be performed once
+// every operation listed in DependsOn has succeeded. Operations of a higher Priority,
+// e.g. urgent payouts, are performed before the other ready ones, zero being the
+// priority of bulk traffic such as settlements.
+type Operation struct {
+	ID             string
+	Type           OperationType
+	TokenAddress   common.Address
+	PartnerAddress common.Address
+	Amount         *big.Int
+	SettleTimeout  int64
+	Identifier     int64
+	DependsOn      []string
+	Priority       int
+}
+
+// Status is the outcome of an Operation within a batch.
+type Status string
+
+const (
+	// Succeeded means the Raiden node accepted the operation.
+	Succeeded Status = "succeeded"
+	// Failed means the Raiden node returned an error for the operation.
+	Failed Status = "failed"
+	// Skipped means the operation was not attempted since a dependency did not
+	// succeed or the context was cancelled.
+	Skipped Status = "skipped"
+)
+
+// Result holds the outcome of a single Operation.
+type Result struct {
+	Operation *Operation
+	Status    Status
+	Err       error
+	Channel   *channels.Channel
+	Payment   *payments.Payment
+	Started   time.Time
+	Finished  time.Time
+}
+
+// Report is the structured result of a batch, with one Result per Operation in the
+// order the operations were given.
+type Report struct {
+	Results   []*Result
+	Succeeded int
+	Failed    int
+	Skipped   int
+}
+
+// OK reports whether every operation in the batch succeeded.
+func (report *Report) OK() bool {
+	return report.Failed == 0 && report.Skipped == 0
+}
+
+// Result returns the result of the operation with the given ID, or nil if there
+// was no such operation in the batch.
+func (report *Report) Result(id string) *Result {
+	for _, result := range report.Results {
+		if result.Operation.ID == id {
+			return result
+		}
+	}
+
+	return nil
+}
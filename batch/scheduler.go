@@ -0,0 +1,132 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+)
+
+// PriorityAging is the time after which an operation waiting for a slot is raised by
+// one priority, so that bulk operations still run while urgent ones keep coming.
+const PriorityAging = 10 * time.Second
+
+// scheduler hands out a limited number of slots to the operations waiting for one,
+// the most urgent first, operations of the same priority in the order they came.
+type scheduler struct {
+	aging time.Duration
+	now   func() time.Time
+
+	mutex    sync.Mutex
+	free     int
+	sequence int
+	waiting  []*waiter
+}
+
+type waiter struct {
+	priority int
+	enqueued time.Time
+	sequence int
+	ready    chan struct{}
+}
+
+func newScheduler(slots int, aging time.Duration, clock clock.Clock) *scheduler {
+	return &scheduler{
+		aging: aging,
+		now:   clock.Now,
+		free:  slots,
+	}
+}
+
+// acquire waits for a slot until the context is done.
+func (scheduler *scheduler) acquire(ctx context.Context, priority int) error {
+	scheduler.mutex.Lock()
+
+	if scheduler.free > 0 && len(scheduler.waiting) == 0 {
+		scheduler.free--
+		scheduler.mutex.Unlock()
+
+		return nil
+	}
+
+	scheduler.sequence++
+
+	var waiter = &waiter{
+		priority: priority,
+		enqueued: scheduler.now(),
+		sequence: scheduler.sequence,
+		ready:    make(chan struct{}),
+	}
+
+	scheduler.waiting = append(scheduler.waiting, waiter)
+	scheduler.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	scheduler.mutex.Lock()
+
+	for i, queued := range scheduler.waiting {
+		if queued == waiter {
+			scheduler.waiting = append(scheduler.waiting[:i], scheduler.waiting[i+1:]...)
+			scheduler.mutex.Unlock()
+
+			return ctx.Err()
+		}
+	}
+
+	// the slot was handed out as the context was done
+	scheduler.mutex.Unlock()
+	scheduler.release()
+
+	return ctx.Err()
+}
+
+// release gives the slot back, to the most urgent waiting operation if any.
+func (scheduler *scheduler) release() {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	if len(scheduler.waiting) == 0 {
+		scheduler.free++
+		return
+	}
+
+	var (
+		now  = scheduler.now()
+		next = 0
+	)
+
+	for i, waiter := range scheduler.waiting[1:] {
+		if scheduler.before(waiter, scheduler.waiting[next], now) {
+			next = i + 1
+		}
+	}
+
+	close(scheduler.waiting[next].ready)
+	scheduler.waiting = append(scheduler.waiting[:next], scheduler.waiting[next+1:]...)
+}
+
+// before tells whether the waiter is to run before the other one.
+func (scheduler *scheduler) before(waiter, other *waiter, now time.Time) bool {
+	var priority, otherPriority = scheduler.priority(waiter, now), scheduler.priority(other, now)
+
+	if priority != otherPriority {
+		return priority > otherPriority
+	}
+
+	return waiter.sequence < other.sequence
+}
+
+// priority is the priority of the waiter raised by the time it waited.
+func (scheduler *scheduler) priority(waiter *waiter, now time.Time) int {
+	if scheduler.aging <= 0 {
+		return waiter.priority
+	}
+
+	return waiter.priority + int(now.Sub(waiter.enqueued)/scheduler.aging)
+}
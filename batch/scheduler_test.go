@@ -0,0 +1,195 @@
+package batch
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduler(t *testing.T) {
+	type testcase struct {
+		name          string
+		aging         time.Duration
+		priorities    []int
+		waited        []time.Duration
+		expectedOrder []int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:          "most urgent first",
+			priorities:    []int{0, 5, 1, 5},
+			waited:        []time.Duration{0, 0, 0, 0},
+			expectedOrder: []int{1, 3, 2, 0},
+		},
+		testcase{
+			name:          "same priority in order",
+			priorities:    []int{0, 0, 0},
+			waited:        []time.Duration{0, 0, 0},
+			expectedOrder: []int{0, 1, 2},
+		},
+		testcase{
+			name:          "waiting raises the priority",
+			aging:         time.Minute,
+			priorities:    []int{0, 5, 4},
+			waited:        []time.Duration{6 * time.Minute, 0, 150 * time.Second},
+			expectedOrder: []int{0, 2, 1},
+		},
+		testcase{
+			name:          "no aging",
+			priorities:    []int{0, 5},
+			waited:        []time.Duration{time.Hour, 0},
+			expectedOrder: []int{1, 0},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mutex     sync.Mutex
+				order     = make([]int, 0)
+				waitGroup sync.WaitGroup
+				start     = time.Date(2018, 10, 30, 12, 0, 0, 0, time.UTC)
+				scheduler = newScheduler(1, tc.aging, clock.Real())
+			)
+
+			require.NoError(t, scheduler.acquire(context.Background(), 0))
+
+			for i, priority := range tc.priorities {
+				scheduler.mutex.Lock()
+				scheduler.now = func() time.Time { return start.Add(-tc.waited[i]) }
+				scheduler.mutex.Unlock()
+
+				waitGroup.Add(1)
+
+				go func(i, priority int) {
+					defer waitGroup.Done()
+
+					require.NoError(t, scheduler.acquire(context.Background(), priority))
+
+					mutex.Lock()
+					order = append(order, i)
+					mutex.Unlock()
+
+					scheduler.release()
+				}(i, priority)
+
+				require.Eventually(t, func() bool {
+					scheduler.mutex.Lock()
+					defer scheduler.mutex.Unlock()
+
+					return len(scheduler.waiting) == i+1
+				}, time.Second, time.Millisecond)
+			}
+
+			scheduler.mutex.Lock()
+			scheduler.now = func() time.Time { return start }
+			scheduler.mutex.Unlock()
+
+			scheduler.release()
+			waitGroup.Wait()
+
+			assert.Equal(t, tc.expectedOrder, order)
+			assert.Equal(t, 1, scheduler.free)
+		})
+	}
+}
+
+func TestSchedulerCancelled(t *testing.T) {
+	var (
+		scheduler   = newScheduler(1, PriorityAging, clock.Real())
+		ctx, cancel = context.WithCancel(context.Background())
+		errs        = make(chan error)
+	)
+
+	require.NoError(t, scheduler.acquire(context.Background(), 0))
+
+	go func() { errs <- scheduler.acquire(ctx, 0) }()
+
+	require.Eventually(t, func() bool {
+		scheduler.mutex.Lock()
+		defer scheduler.mutex.Unlock()
+
+		return len(scheduler.waiting) == 1
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	assert.Equal(t, context.Canceled, <-errs)
+
+	scheduler.release()
+
+	assert.Empty(t, scheduler.waiting)
+	assert.Equal(t, 1, scheduler.free)
+}
+
+func TestSchedulerClock(t *testing.T) {
+	type testcase struct {
+		name          string
+		advance       time.Duration
+		expectedOrder []int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:          "most urgent first",
+			advance:       3 * PriorityAging,
+			expectedOrder: []int{1, 0},
+		},
+		testcase{
+			name:          "aged past the urgent one",
+			advance:       6 * PriorityAging,
+			expectedOrder: []int{0, 1},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				manual    = clock.NewManual(time.Date(2018, 10, 30, 12, 0, 0, 0, time.UTC))
+				scheduler = newScheduler(1, PriorityAging, manual)
+				order     = make(chan int, 2)
+				waitGroup sync.WaitGroup
+			)
+
+			require.NoError(t, scheduler.acquire(context.Background(), 0))
+
+			// the bulk operation waits while the clock moves, the urgent one comes last
+			for i, priority := range []int{0, 5} {
+				waitGroup.Add(1)
+
+				go func(i, priority int) {
+					defer waitGroup.Done()
+
+					require.NoError(t, scheduler.acquire(context.Background(), priority))
+					order <- i
+					scheduler.release()
+				}(i, priority)
+
+				require.Eventually(t, func() bool {
+					scheduler.mutex.Lock()
+					defer scheduler.mutex.Unlock()
+
+					return len(scheduler.waiting) == i+1
+				}, time.Second, time.Millisecond)
+
+				if i == 0 {
+					manual.Advance(tc.advance)
+				}
+			}
+
+			scheduler.release()
+			waitGroup.Wait()
+			close(order)
+
+			for _, expected := range tc.expectedOrder {
+				assert.Equal(t, expected, <-order)
+			}
+		})
+	}
+}
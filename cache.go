@@ -0,0 +1,37 @@
+package raidenclient
+
+import (
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func newCache() *config.Cache {
+	return config.NewCache()
+}
+
+// Cache returns the cache shared by the sub-clients of the client, the Cache of its
+// configuration, nil when it has none.
+func (client *Client) Cache() *config.Cache {
+	if client.config == nil {
+		return nil
+	}
+
+	return client.config.Cache
+}
+
+// InvalidateToken removes what the sub-clients of the client know of the token from
+// their cache, e.g. after its channels were changed by another client of the node,
+// so that they are read from the node anew.
+func (client *Client) InvalidateToken(tokenAddress common.Address) {
+	if cache := client.Cache(); cache != nil {
+		cache.Invalidate(tokenAddress, "")
+	}
+}
+
+// InvalidateCache removes everything the sub-clients of the client know from their
+// cache.
+func (client *Client) InvalidateCache() {
+	if cache := client.Cache(); cache != nil {
+		cache.InvalidateAll()
+	}
+}
@@ -0,0 +1,74 @@
+package raidenclient
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientCache(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		requests int
+		node     = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			mutex.Lock()
+			requests++
+			mutex.Unlock()
+
+			writer.Write([]byte(`"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"`))
+		}))
+		count = func() int {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return requests
+		}
+		raidenConfig = &config.Config{Host: node.URL, APIVersion: "v1"}
+		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359")
+		ctx          = context.Background()
+	)
+	defer node.Close()
+
+	raidenClient := New(raidenConfig)
+
+	require.NotNil(t, raidenClient.Cache())
+	assert.Nil(t, raidenConfig.Cache, "the configuration is left as is")
+
+	for i := 0; i < 2; i++ {
+		_, err := raidenClient.Tokens().Get(ctx, tokenAddress)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, count())
+
+	raidenClient.InvalidateToken(tokenAddress)
+
+	_, err := raidenClient.Tokens().Get(ctx, tokenAddress)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count())
+
+	raidenClient.InvalidateCache()
+
+	_, err = raidenClient.Tokens().Get(ctx, tokenAddress)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count())
+
+	t.Run("without cache", func(t *testing.T) {
+		var raidenClient = NewClient(raidenConfig, nil)
+
+		assert.Nil(t, raidenClient.Cache())
+		raidenClient.InvalidateToken(tokenAddress)
+		raidenClient.InvalidateCache()
+
+		_, err := raidenClient.Tokens().Get(ctx, tokenAddress)
+		require.NoError(t, err)
+		assert.Equal(t, 4, count())
+	})
+}
@@ -0,0 +1,217 @@
+// Package changefeed paces the polls of the watchers of a Raiden node: polls come
+// faster while changes are found and slow down while the node is idle or struggling,
+// and every interval is jittered so that many client instances do not poll a node in
+// step.
+package changefeed
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+)
+
+const (
+	// DefaultBackoff is the factor the interval grows by after a poll without change
+	// when no backoff is given.
+	DefaultBackoff = 2
+	// DefaultIdleFactor is how many times the minimum interval the interval grows to
+	// while idle when no maximum interval is given.
+	DefaultIdleFactor = 8
+	// DefaultJitter is the fraction of every interval randomized when no jitter is
+	// given.
+	DefaultJitter = 0.2
+	// DefaultSlowResponse is the latency beyond which a poll signals a loaded node
+	// when no latency is given.
+	DefaultSlowResponse = 5 * time.Second
+)
+
+// Options configures the intervals of a feed. The interval is MinInterval after a poll
+// found changes and grows by Backoff after every poll without change, or failed, up
+// to MaxInterval. Every interval is then randomized by up to Jitter of its length,
+// either way, a negative Jitter disabling it. The intervals are waited for on
+// Clock, the system time when nil.
+//
+// A poll observed taking longer than SlowResponse, a negative SlowResponse disabling
+// the latency signal, or failing with a 5xx or 429 status signals a loaded node: the
+// floor of the interval then grows by Backoff up to MaxInterval, changes or not, and
+// shrinks back by Backoff down to MinInterval with every poll of a healthy node, so
+// that the watchers do not add to the pressure on a struggling node. A wait asked
+// for by the Retry-After header of a failed poll is always honored.
+type Options struct {
+	MinInterval  time.Duration
+	MaxInterval  time.Duration
+	Backoff      float64
+	Jitter       float64
+	SlowResponse time.Duration
+	Clock        clock.Clock
+}
+
+// PollFunc polls once, returning whether it found changes.
+type PollFunc func(ctx context.Context) (changed bool, err error)
+
+// Feed is the interval between the polls of a watcher. It is not to be used by
+// several watchers at once.
+type Feed struct {
+	options    Options
+	interval   time.Duration
+	floor      time.Duration
+	loaded     bool
+	retryAfter time.Duration
+	random     func() float64
+}
+
+// New creates a feed with the options, the interval starting at MinInterval. A
+// MaxInterval below MinInterval is DefaultIdleFactor times MinInterval.
+func New(options *Options) *Feed {
+	var feed = &Feed{random: rand.New(rand.NewSource(time.Now().UnixNano())).Float64}
+
+	if options != nil {
+		feed.options = *options
+	}
+
+	if feed.options.MinInterval <= 0 {
+		feed.options.MinInterval = time.Second
+	}
+
+	if feed.options.MaxInterval < feed.options.MinInterval {
+		feed.options.MaxInterval = DefaultIdleFactor * feed.options.MinInterval
+	}
+
+	if feed.options.Backoff <= 1 {
+		feed.options.Backoff = DefaultBackoff
+	}
+
+	if feed.options.Jitter == 0 {
+		feed.options.Jitter = DefaultJitter
+	}
+
+	if feed.options.SlowResponse == 0 {
+		feed.options.SlowResponse = DefaultSlowResponse
+	}
+
+	feed.options.Clock = clock.Or(feed.options.Clock)
+	feed.interval = feed.options.MinInterval
+	feed.floor = feed.options.MinInterval
+
+	return feed
+}
+
+// Now returns the time on the clock of the feed, the start of a poll to observe.
+func (feed *Feed) Now() time.Time {
+	return feed.options.Clock.Now()
+}
+
+// Observe records the load signals of the poll started at the given time which
+// returned the error, see Options, for the next interval.
+func (feed *Feed) Observe(started time.Time, err error) {
+	if feed.options.SlowResponse > 0 && feed.Now().Sub(started) > feed.options.SlowResponse {
+		feed.loaded = true
+	}
+
+	if statusCode := raidenerrors.StatusCode(err); statusCode >= 500 || statusCode == http.StatusTooManyRequests {
+		feed.loaded = true
+
+		if retryAfter := raidenerrors.RetryAfter(err); retryAfter > feed.retryAfter {
+			feed.retryAfter = retryAfter
+		}
+	}
+}
+
+// Next returns the time to wait before the next poll, given whether the last one
+// found changes and the load signals observed since the previous interval.
+func (feed *Feed) Next(changed bool) time.Duration {
+	if feed.loaded {
+		if feed.floor < feed.interval {
+			feed.floor = feed.interval
+		}
+
+		feed.floor = time.Duration(float64(feed.floor) * feed.options.Backoff)
+	} else {
+		feed.floor = time.Duration(float64(feed.floor) / feed.options.Backoff)
+	}
+
+	if feed.floor > feed.options.MaxInterval {
+		feed.floor = feed.options.MaxInterval
+	}
+
+	if feed.floor < feed.options.MinInterval {
+		feed.floor = feed.options.MinInterval
+	}
+
+	if changed {
+		feed.interval = feed.floor
+	} else {
+		feed.interval = time.Duration(float64(feed.interval) * feed.options.Backoff)
+		if feed.interval > feed.options.MaxInterval {
+			feed.interval = feed.options.MaxInterval
+		}
+	}
+
+	if feed.interval < feed.floor {
+		feed.interval = feed.floor
+	}
+
+	var interval, retryAfter = feed.interval, feed.retryAfter
+
+	feed.loaded, feed.retryAfter = false, 0
+
+	if feed.options.Jitter >= 0 {
+		interval = time.Duration(float64(interval) * (1 + feed.options.Jitter*(2*feed.random()-1)))
+	}
+
+	// the node is not polled sooner than it asked, jitter or not
+	if interval < retryAfter {
+		interval = retryAfter
+	}
+
+	return interval
+}
+
+// Wait waits for the next poll, given whether the last one found changes, returning
+// false when the context is done first.
+func (feed *Feed) Wait(ctx context.Context, changed bool) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-feed.options.Clock.After(feed.Next(changed)):
+		return true
+	}
+}
+
+// Run will poll until the context is done, when the returned channel is closed, the
+// first poll being made right away. Errors of single polls are delivered on it,
+// without ending the feed, and dropped when nobody is receiving them.
+func Run(ctx context.Context, options *Options, poll PollFunc) <-chan error {
+	var (
+		feed = New(options)
+		errs = make(chan error, 1)
+	)
+
+	go func() {
+		defer close(errs)
+
+		for {
+			started := feed.Now()
+
+			changed, err := poll(ctx)
+			feed.Observe(started, err)
+
+			if err != nil && ctx.Err() == nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+
+			if !feed.Wait(ctx, changed && err == nil) {
+				return
+			}
+		}
+	}()
+
+	return errs
+}
@@ -0,0 +1,239 @@
+package changefeed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleRun() {
+	var ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+
+	defer cancel()
+
+	errs := Run(ctx, &Options{MinInterval: time.Second, MaxInterval: 30 * time.Second}, func(ctx context.Context) (bool, error) {
+		// poll the node, telling whether anything changed since the last poll
+		return false, nil
+	})
+
+	for err := range errs {
+		fmt.Println("unable to poll:", err.Error())
+	}
+}
+
+func TestFeedNext(t *testing.T) {
+	type testcase struct {
+		name              string
+		options           *Options
+		random            float64
+		changes           []bool
+		expectedIntervals []time.Duration
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:              "backs off while idle up to the maximum",
+			options:           &Options{MinInterval: time.Second, MaxInterval: 5 * time.Second, Jitter: -1},
+			changes:           []bool{false, false, false, false},
+			expectedIntervals: []time.Duration{2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second},
+		},
+		testcase{
+			name:              "resets on changes",
+			options:           &Options{MinInterval: time.Second, Backoff: 3, Jitter: -1},
+			changes:           []bool{false, false, true, false, false, false},
+			expectedIntervals: []time.Duration{3 * time.Second, 8 * time.Second, time.Second, 3 * time.Second, 8 * time.Second, 8 * time.Second},
+		},
+		testcase{
+			name:              "jitter below",
+			options:           &Options{MinInterval: time.Second},
+			random:            0,
+			changes:           []bool{true, false},
+			expectedIntervals: []time.Duration{800 * time.Millisecond, 1600 * time.Millisecond},
+		},
+		testcase{
+			name:              "jitter above",
+			options:           &Options{MinInterval: time.Second, Jitter: 0.5},
+			random:            1,
+			changes:           []bool{true},
+			expectedIntervals: []time.Duration{1500 * time.Millisecond},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				feed      = New(tc.options)
+				intervals = make([]time.Duration, 0, len(tc.changes))
+			)
+
+			feed.random = func() float64 { return tc.random }
+
+			for _, changed := range tc.changes {
+				intervals = append(intervals, feed.Next(changed))
+			}
+
+			assert.Equal(t, tc.expectedIntervals, intervals)
+		})
+	}
+}
+
+func TestFeedLoad(t *testing.T) {
+	type poll struct {
+		latency time.Duration
+		err     error
+		changed bool
+	}
+
+	type testcase struct {
+		name              string
+		options           *Options
+		polls             []poll
+		expectedIntervals []time.Duration
+	}
+
+	var (
+		failed      = raidenerrors.New(http.StatusInternalServerError, errors.New("internal error"))
+		unavailable = raidenerrors.New(http.StatusServiceUnavailable, errors.New("unavailable"))
+		limited     = &raidenerrors.Error{StatusCode: http.StatusTooManyRequests, RetryAfter: 30 * time.Second, Err: errors.New("too many requests")}
+		rejected    = raidenerrors.New(http.StatusConflict, errors.New("conflict"))
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:    "backs off under load and recovers",
+			options: &Options{MinInterval: time.Second, MaxInterval: 16 * time.Second, Jitter: -1},
+			polls: []poll{
+				poll{err: failed, changed: true},
+				poll{latency: 6 * time.Second, changed: true},
+				poll{changed: true},
+				poll{changed: true},
+			},
+			expectedIntervals: []time.Duration{2 * time.Second, 4 * time.Second, 2 * time.Second, time.Second},
+		},
+		testcase{
+			name:    "idle under load",
+			options: &Options{MinInterval: time.Second, MaxInterval: 16 * time.Second, Jitter: -1},
+			polls: []poll{
+				poll{err: unavailable},
+				poll{err: unavailable},
+				poll{},
+				poll{changed: true},
+			},
+			expectedIntervals: []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, time.Second},
+		},
+		testcase{
+			name:    "up to the maximum",
+			options: &Options{MinInterval: time.Second, MaxInterval: 3 * time.Second, Jitter: -1},
+			polls: []poll{
+				poll{err: failed, changed: true},
+				poll{err: failed, changed: true},
+				poll{err: failed, changed: true},
+			},
+			expectedIntervals: []time.Duration{2 * time.Second, 3 * time.Second, 3 * time.Second},
+		},
+		testcase{
+			name:    "retry after",
+			options: &Options{MinInterval: time.Second, MaxInterval: 8 * time.Second},
+			polls: []poll{
+				poll{err: limited},
+				poll{changed: true},
+			},
+			expectedIntervals: []time.Duration{30 * time.Second, 800 * time.Millisecond},
+		},
+		testcase{
+			name:              "client errors",
+			options:           &Options{MinInterval: time.Second, Jitter: -1},
+			polls:             []poll{poll{err: rejected, changed: true}},
+			expectedIntervals: []time.Duration{time.Second},
+		},
+		testcase{
+			name:              "slow responses ignored",
+			options:           &Options{MinInterval: time.Second, SlowResponse: -1, Jitter: -1},
+			polls:             []poll{poll{latency: time.Minute, changed: true}},
+			expectedIntervals: []time.Duration{time.Second},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				manual    = clock.NewManual(time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC))
+				intervals = make([]time.Duration, 0, len(tc.polls))
+			)
+
+			tc.options.Clock = manual
+
+			feed := New(tc.options)
+			feed.random = func() float64 { return 0 }
+
+			for _, poll := range tc.polls {
+				started := feed.Now()
+
+				manual.Advance(poll.latency)
+				feed.Observe(started, poll.err)
+
+				intervals = append(intervals, feed.Next(poll.changed))
+			}
+
+			assert.Equal(t, tc.expectedIntervals, intervals)
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	var (
+		polls       int
+		ctx, cancel = context.WithCancel(context.Background())
+		pollErr     = errors.New("node unreachable")
+	)
+
+	defer cancel()
+
+	errs := Run(ctx, &Options{MinInterval: time.Millisecond}, func(ctx context.Context) (bool, error) {
+		polls++
+		if polls == 3 {
+			cancel()
+		}
+
+		return false, pollErr
+	})
+
+	var received []error
+	for err := range errs {
+		received = append(received, err)
+	}
+
+	assert.Equal(t, 3, polls)
+	assert.Equal(t, pollErr, received[0])
+}
+
+func TestWaitClock(t *testing.T) {
+	var (
+		manual = clock.NewManual(time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC))
+		feed   = New(&Options{MinInterval: time.Minute, Jitter: -1, Clock: manual})
+		waited = make(chan bool)
+	)
+
+	go func() {
+		waited <- feed.Wait(context.Background(), true)
+	}()
+
+	manual.BlockUntil(1)
+	manual.Advance(59 * time.Second)
+
+	select {
+	case <-waited:
+		t.Fatal("waited less than the interval")
+	default:
+	}
+
+	manual.Advance(time.Second)
+	assert.True(t, <-waited)
+}
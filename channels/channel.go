@@ -1,29 +1,187 @@
 package channels
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 type channel struct {
-	TokenNetworkIdentifier string `json:"token_network_identifier"`
-	ChannelIdentifier      int64  `json:"channel_identifier"`
-	PartnerAddress         string `json:"partner_address"`
-	TokenAddress           string `json:"token_address"`
-	Balance                int64  `json:"balance"`
-	TotalDeposit           int64  `json:"total_deposit"`
-	State                  string `json:"state"`
-	SettleTimeout          int64  `json:"settle_timeout"`
-	RevealTimeout          int64  `json:"reveal_timeout"`
+	TokenNetworkIdentifier string      `json:"token_network_identifier"`
+	ChannelIdentifier      int64       `json:"channel_identifier"`
+	PartnerAddress         string      `json:"partner_address"`
+	TokenAddress           string      `json:"token_address"`
+	Balance                json.Number `json:"balance"`
+	TotalDeposit           json.Number `json:"total_deposit"`
+	TotalWithdraw          json.Number `json:"total_withdraw,omitempty"`
+	State                  string      `json:"state"`
+	SettleTimeout          int64       `json:"settle_timeout"`
+	RevealTimeout          int64       `json:"reveal_timeout"`
+	Label                  *Label      `json:"label,omitempty"`
+}
+
+func (channel *channel) toChannel() (*Channel, error) {
+	var (
+		err           error
+		balance       *big.Int
+		totalDeposit  *big.Int
+		totalWithdraw *big.Int
+	)
+
+	if balance, err = amounts.Parse(channel.Balance); err != nil {
+		return nil, err
+	}
+
+	if totalDeposit, err = amounts.Parse(channel.TotalDeposit); err != nil {
+		return nil, err
+	}
+
+	if channel.TotalWithdraw != "" {
+		if totalWithdraw, err = amounts.Parse(channel.TotalWithdraw); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Channel{
+		TokenNetworkIdentifier: address.HexToTokenNetworkAddress(channel.TokenNetworkIdentifier),
+		ChannelIdentifier:      channel.ChannelIdentifier,
+		PartnerAddress:         common.HexToAddress(channel.PartnerAddress),
+		TokenAddress:           common.HexToAddress(channel.TokenAddress),
+		Balance:                balance,
+		TotalDeposit:           totalDeposit,
+		TotalWithdraw:          totalWithdraw,
+		State:                  channel.State,
+		SettleTimeout:          channel.SettleTimeout,
+		RevealTimeout:          channel.RevealTimeout,
+		Label:                  channel.Label,
+	}, nil
 }
 
 // Channel represents a payment channel between two ethereum addresses. This contains
 // high level information about the network, partners, the token being used.
+// TotalWithdraw is nil when the node does not report the amount withdrawn. Label is
+// the label stored by the client for the channel, see LabelStore, nil when it has
+// none or the channel was not labelled.
 type Channel struct {
-	TokenNetworkIdentifier common.Address
+	TokenNetworkIdentifier address.TokenNetworkAddress
 	ChannelIdentifier      int64
 	PartnerAddress         common.Address
 	TokenAddress           common.Address
-	Balance                int64
-	TotalDeposit           int64
+	Balance                *big.Int
+	TotalDeposit           *big.Int
+	TotalWithdraw          *big.Int
 	State                  string
 	SettleTimeout          int64
 	RevealTimeout          int64
+	Label                  *Label
+}
+
+// String returns a concise description of the channel for logs, e.g. "channel 20
+// 0xEA67…↔0x61C8… balance 25000000 opened", with the token and partner addresses
+// abbreviated and the balance in the smallest unit of the token.
+func (channel Channel) String() string {
+	return fmt.Sprintf("channel %d %s↔%s balance %s %s", channel.ChannelIdentifier, util.ShortAddress(channel.TokenAddress), util.ShortAddress(channel.PartnerAddress), amounts.OrZero(channel.Balance).String(), channel.State)
+}
+
+// MarshalJSON encodes the channel in the format of the Raiden node API, with the
+// addresses as checksummed hex strings and the amounts as JSON numbers, and its label
+// when it has one.
+func (channel Channel) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fromChannel(&channel))
+}
+
+// UnmarshalJSON decodes a channel in the format of the Raiden node API, which is the
+// format channels are marshalled in.
+func (channel *Channel) UnmarshalJSON(data []byte) error {
+	var (
+		err     error
+		decoded *Channel
+	)
+
+	if decoded, err = decodeChannel(data); err != nil {
+		return err
+	}
+
+	*channel = *decoded
+
+	return nil
+}
+
+func decodeChannel(data []byte) (*Channel, error) {
+	var response = &channel{}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return nil, err
+	}
+
+	return response.toChannel()
+}
+
+func fromChannel(value *Channel) *channel {
+	var totalWithdraw json.Number
+
+	if value.TotalWithdraw != nil {
+		totalWithdraw = amounts.Number(value.TotalWithdraw)
+	}
+
+	return &channel{
+		TokenNetworkIdentifier: value.TokenNetworkIdentifier.Hex(),
+		ChannelIdentifier:      value.ChannelIdentifier,
+		PartnerAddress:         value.PartnerAddress.Hex(),
+		TokenAddress:           value.TokenAddress.Hex(),
+		Balance:                amounts.Number(value.Balance),
+		TotalDeposit:           amounts.Number(value.TotalDeposit),
+		TotalWithdraw:          totalWithdraw,
+		State:                  value.State,
+		SettleTimeout:          value.SettleTimeout,
+		RevealTimeout:          value.RevealTimeout,
+		Label:                  value.Label,
+	}
+}
+
+// Equal tells whether the channel has the same state as the other, comparing the
+// amounts by value, see amounts.Equal, except that a total withdraw the node did not
+// report differs from a zero one. Labels are not part of the state of a channel and
+// are not compared. Two nil channels are equal.
+func (channel *Channel) Equal(other *Channel) bool {
+	if channel == nil || other == nil {
+		return channel == other
+	}
+
+	return channel.TokenNetworkIdentifier == other.TokenNetworkIdentifier &&
+		channel.ChannelIdentifier == other.ChannelIdentifier &&
+		channel.PartnerAddress == other.PartnerAddress &&
+		channel.TokenAddress == other.TokenAddress &&
+		amounts.Equal(channel.Balance, other.Balance) &&
+		amounts.Equal(channel.TotalDeposit, other.TotalDeposit) &&
+		(channel.TotalWithdraw == nil) == (other.TotalWithdraw == nil) &&
+		amounts.Equal(channel.TotalWithdraw, other.TotalWithdraw) &&
+		channel.State == other.State &&
+		channel.SettleTimeout == other.SettleTimeout &&
+		channel.RevealTimeout == other.RevealTimeout
+}
+
+// Clone returns a deep copy of the channel, whose amounts can be changed without
+// changing those of the channel, nor its label.
+func (channel *Channel) Clone() *Channel {
+	if channel == nil {
+		return nil
+	}
+
+	clone := *channel
+	clone.Balance = amounts.Copy(channel.Balance)
+	clone.TotalDeposit = amounts.Copy(channel.TotalDeposit)
+	clone.TotalWithdraw = amounts.Copy(channel.TotalWithdraw)
+
+	if channel.Label != nil {
+		label := *channel.Label
+		clone.Label = &label
+	}
+
+	return &clone
 }
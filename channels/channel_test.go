@@ -0,0 +1,140 @@
+package channels
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelJSONRoundTrip(t *testing.T) {
+	type testcase struct {
+		name string
+		data string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "channel",
+			data: `{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000,"total_deposit":35000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}`,
+		},
+		testcase{
+			name: "amounts larger than an int64",
+			data: `{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000000000000000,"total_deposit":35000000000000000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var channel = &Channel{}
+
+			require.NoError(t, json.Unmarshal([]byte(tc.data), channel))
+
+			data, err := json.Marshal(channel)
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.data, string(data))
+		})
+	}
+}
+
+func TestChannelMarshalJSONNilAmounts(t *testing.T) {
+	data, err := json.Marshal(Channel{State: "opened"})
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"token_network_identifier":"0x0000000000000000000000000000000000000000","channel_identifier":0,"partner_address":"0x0000000000000000000000000000000000000000","token_address":"0x0000000000000000000000000000000000000000","balance":0,"total_deposit":0,"state":"opened","settle_timeout":0,"reveal_timeout":0}`, string(data))
+}
+
+func TestChannelEqual(t *testing.T) {
+	var data = `{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":0,"total_deposit":35000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}`
+
+	type testcase struct {
+		name     string
+		change   func(channel *Channel)
+		expected bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:     "same state",
+			change:   func(channel *Channel) {},
+			expected: true,
+		},
+		testcase{
+			name: "amounts with another representation",
+			change: func(channel *Channel) {
+				channel.Balance = nil
+				channel.TotalDeposit = new(big.Int).Mul(big.NewInt(35), big.NewInt(1000000))
+			},
+			expected: true,
+		},
+		testcase{
+			name: "another balance",
+			change: func(channel *Channel) {
+				channel.Balance = big.NewInt(1)
+			},
+		},
+		testcase{
+			name: "a reported total withdraw",
+			change: func(channel *Channel) {
+				channel.TotalWithdraw = new(big.Int)
+			},
+		},
+		testcase{
+			name: "another state",
+			change: func(channel *Channel) {
+				channel.State = "closed"
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var channel, other = &Channel{}, &Channel{}
+
+			require.NoError(t, json.Unmarshal([]byte(data), channel))
+			require.NoError(t, json.Unmarshal([]byte(data), other))
+
+			tc.change(other)
+
+			assert.Equal(t, tc.expected, channel.Equal(other))
+			assert.Equal(t, tc.expected, other.Equal(channel))
+		})
+	}
+
+	assert.True(t, (*Channel)(nil).Equal(nil))
+	assert.False(t, (*Channel)(nil).Equal(&Channel{}))
+}
+
+func TestChannelClone(t *testing.T) {
+	var (
+		channel = &Channel{
+			ChannelIdentifier: 20,
+			Balance:           big.NewInt(25),
+			TotalDeposit:      big.NewInt(35),
+			State:             "opened",
+		}
+		clone = channel.Clone()
+	)
+
+	assert.True(t, channel.Equal(clone))
+	assert.Nil(t, clone.TotalWithdraw)
+
+	clone.Balance.SetInt64(0)
+	clone.TotalDeposit.Add(clone.TotalDeposit, big.NewInt(10))
+
+	assert.Equal(t, int64(25), channel.Balance.Int64())
+	assert.Equal(t, int64(35), channel.TotalDeposit.Int64())
+	assert.Nil(t, (*Channel)(nil).Clone())
+}
+
+func TestChannelString(t *testing.T) {
+	var channel = &Channel{}
+
+	require.NoError(t, json.Unmarshal([]byte(`{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000,"total_deposit":35000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}`), channel))
+
+	assert.Equal(t, "channel 20 0xEA67…↔0x61C8… balance 25000000 opened", channel.String())
+	assert.Equal(t, "channel 0 0x0000…↔0x0000… balance 0 ", Channel{}.String())
+}
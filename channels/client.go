@@ -4,22 +4,34 @@ import (
 	"net/http"
 
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
 )
 
 var (
 	_ Opener            = &Client{}
 	_ Closer            = &Client{}
 	_ IncreaseDepositor = &Client{}
+	_ Lister            = &Client{}
+	_ Getter            = &Client{}
+	_ TimeoutsGetter    = &Client{}
+	_ Withdrawer        = &Client{}
+	_ util.RawCaller    = &Client{}
 )
 
 // NewClient creates a new client to all channel operations that can be performed
-// on a Raiden node. This includes Opening, Closing and Increasing the deposit of
-// a channel.
+// on a Raiden node. This includes Opening, Closing, Listing, Getting and Increasing
+// the deposit of a channel, withdrawing from it, and getting the timeouts channels are
+// opened with.
 func NewClient(config *config.Config, httpClient *http.Client) *Client {
 	return &Client{
 		Opener:            NewOpener(config, httpClient),
 		Closer:            NewCloser(config, httpClient),
 		IncreaseDepositor: NewIncreaseDepositor(config, httpClient),
+		Lister:            NewLister(config, httpClient),
+		Getter:            NewGetter(config, httpClient),
+		TimeoutsGetter:    NewTimeoutsGetter(config, httpClient),
+		Withdrawer:        NewWithdrawer(config, httpClient),
+		RawCaller:         util.NewRawCaller(config, httpClient),
 	}
 }
 
@@ -29,4 +41,9 @@ type Client struct {
 	Opener
 	Closer
 	IncreaseDepositor
+	Lister
+	Getter
+	TimeoutsGetter
+	Withdrawer
+	util.RawCaller
 }
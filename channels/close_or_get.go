@@ -0,0 +1,64 @@
+package channels
+
+import (
+	"context"
+
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CloseOrGet returns a closer that, when the node refuses to close a channel because
+// it is already closed or settled, returns the channel in its current state instead
+// of the error, so that cleanup jobs can close channels again after a retry. A
+// settled channel the node no longer lists is returned with the token, the partner
+// and the "settled" state only.
+//
+//	closer := channels.CloseOrGet(channels.NewCloser(config, httpClient), channels.NewLister(config, httpClient))
+func CloseOrGet(closer Closer, lister Lister) Closer {
+	return &closeOrGetter{
+		closer: closer,
+		lister: lister,
+	}
+}
+
+type closeOrGetter struct {
+	closer Closer
+	lister Lister
+}
+
+// Close will close the channel, or get the channel when it is already closed or
+// settled, see CloseOrGet.
+func (closer *closeOrGetter) Close(ctx context.Context, tokenAddress, partnerAddress common.Address) (*Channel, error) {
+	channel, err := closer.closer.Close(ctx, tokenAddress, partnerAddress)
+	if err == nil {
+		return channel, nil
+	}
+
+	if !raidenerrors.HasCode(err, raidenerrors.CodeChannelAlreadyClosed) {
+		return nil, err
+	}
+
+	channelList, listErr := closer.lister.ListToken(ctx, tokenAddress)
+	if listErr != nil {
+		return nil, err
+	}
+
+	for _, existing := range channelList {
+		if existing.PartnerAddress != partnerAddress {
+			continue
+		}
+
+		if existing.State == "opened" {
+			// the node did not close the channel after all
+			return nil, err
+		}
+
+		return existing, nil
+	}
+
+	return &Channel{
+		TokenAddress:   tokenAddress,
+		PartnerAddress: partnerAddress,
+		State:          "settled",
+	}, nil
+}
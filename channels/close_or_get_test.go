@@ -0,0 +1,121 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleCloseOrGet() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		closer         = CloseOrGet(NewCloser(config, http.DefaultClient), NewLister(config, http.DefaultClient))
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	channel, err := closer.Close(context.Background(), tokenAddress, partnerAddress)
+	if err != nil {
+		panic(fmt.Sprintf("unable to close channel: %s", err.Error()))
+	}
+
+	fmt.Printf("channel %s\n", channel.State)
+}
+
+func TestCloseOrGet(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		closeURL       = "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		listURL        = "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		channelJSON    = `{"channel_identifier":%d,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":30,"total_deposit":30,"state":"%s"}`
+	)
+
+	type testcase struct {
+		name               string
+		prepHTTPMock       func()
+		expectedIdentifier int64
+		expectedState      string
+		expectedCode       raidenerrors.Code
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "closed channel",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", closeURL, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(channelJSON, 20, "closed")))
+			},
+			expectedIdentifier: 20,
+			expectedState:      "closed",
+		},
+		testcase{
+			name: "already closed channel",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", closeURL, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Attempted to close an already closed channel"}`))
+				httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "["+fmt.Sprintf(channelJSON, 20, "settling")+"]"))
+			},
+			expectedIdentifier: 20,
+			expectedState:      "settling",
+		},
+		testcase{
+			name: "settled channel no longer listed",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", closeURL, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Channel is already settled"}`))
+				httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, `[]`))
+			},
+			expectedState: "settled",
+		},
+		testcase{
+			name: "channel listed as opened",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", closeURL, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Attempted to close an already closed channel"}`))
+				httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "["+fmt.Sprintf(channelJSON, 20, "opened")+"]"))
+			},
+			expectedCode: raidenerrors.CodeChannelAlreadyClosed,
+		},
+		testcase{
+			name: "other error",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", closeURL, httpmock.NewStringResponder(http.StatusNotFound, `{"errors":"Channel not found"}`))
+			},
+			expectedCode: raidenerrors.CodeChannelNotFound,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			channel, err := CloseOrGet(NewCloser(config, http.DefaultClient), NewLister(config, http.DefaultClient)).Close(context.Background(), tokenAddress, partnerAddress)
+
+			if tc.expectedCode != raidenerrors.CodeUnknown {
+				assert.True(t, raidenerrors.HasCode(err, tc.expectedCode))
+				assert.Nil(t, channel)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIdentifier, channel.ChannelIdentifier)
+			assert.Equal(t, tc.expectedState, channel.State)
+			assert.Equal(t, partnerAddress, channel.PartnerAddress)
+		})
+	}
+}
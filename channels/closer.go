@@ -2,13 +2,11 @@ package channels
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
-	"strings"
 
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/progress"
 	"github.com/cpurta/go-raiden-client/util"
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -38,68 +36,39 @@ type defaultCloser struct {
 	baseClient *util.BaseClient
 }
 
-// Close will close a payment channel given a token address and a partner address.
+// Close will close a payment channel given a token address and a partner address,
+// reporting its progress to the progress options of the context, if any. It waits
+// for the other mutations of the channel by the process to be done first.
 func (closer *defaultCloser) Close(ctx context.Context, tokenAddress, partnerAddress common.Address) (*Channel, error) {
 	var (
 		err     error
+		unlock  func()
 		channel = &channel{}
 
 		requestURL          *url.URL
-		request             *http.Request
-		response            *http.Response
-		requestBody         []byte
 		channelCloseRequest = &channelCloseRequest{
 			State: "closed",
 		}
 	)
 
-	if requestURL, err = closer.getRequestURL(tokenAddress, partnerAddress); err != nil {
+	if requestURL, err = closer.baseClient.Endpoint("channels/%s/%s", tokenAddress.Hex(), partnerAddress.Hex()); err != nil {
 		return nil, err
 	}
 
-	if requestBody, err = json.Marshal(channelCloseRequest); err != nil {
+	if unlock, err = mutations.lock(ctx, requestURL); err != nil {
 		return nil, err
 	}
+	defer unlock()
 
-	if request, err = http.NewRequest("PATCH", requestURL.String(), strings.NewReader(string(requestBody))); err != nil {
-		return nil, err
-	}
-
-	request = request.WithContext(ctx)
-
-	if response, err = closer.baseClient.HTTPClient.Do(request); err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&channel); err != nil {
-		return nil, err
+	closed := func(channel *Channel) bool {
+		return channel.State != "opened"
 	}
 
-	return &Channel{
-		TokenNetworkIdentifier: common.HexToAddress(channel.TokenNetworkIdentifier),
-		ChannelIdentifier:      channel.ChannelIdentifier,
-		PartnerAddress:         common.HexToAddress(channel.PartnerAddress),
-		TokenAddress:           common.HexToAddress(channel.TokenAddress),
-		Balance:                channel.Balance,
-		TotalDeposit:           channel.TotalDeposit,
-		State:                  channel.State,
-		SettleTimeout:          channel.SettleTimeout,
-		RevealTimeout:          channel.RevealTimeout,
-	}, nil
-}
-
-func (closer *defaultCloser) getRequestURL(tokenAddress, partnerAddress common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/channels/%s/%s", closer.baseClient.Config.Host, closer.baseClient.Config.APIVersion, tokenAddress.Hex(), partnerAddress.Hex())
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
+	if err = progress.Track(ctx, progress.OperationChannelClose, progress.TransactionStages, pollMined(closer.baseClient, tokenAddress, partnerAddress, closed), func(ctx context.Context) error {
+		return closer.baseClient.Call(ctx, "PATCH", requestURL, channelCloseRequest, channel)
+	}); err != nil {
 		return nil, err
 	}
 
-	return requestURL, nil
+	return indexed(closer.baseClient.Config, channel)
 }
@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"os"
 	"testing"
 
+	"github.com/cpurta/go-raiden-client/address"
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jarcoal/httpmock"
@@ -72,12 +74,12 @@ func TestCloser(t *testing.T) {
 			},
 			expectedError: nil,
 			expectedChannel: &Channel{
-				TokenNetworkIdentifier: common.HexToAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
+				TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
 				ChannelIdentifier:      int64(20),
 				PartnerAddress:         common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
 				TokenAddress:           common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
-				Balance:                int64(25000000),
-				TotalDeposit:           int64(35000000),
+				Balance:                big.NewInt(25000000),
+				TotalDeposit:           big.NewInt(35000000),
 				State:                  "closed",
 				SettleTimeout:          int64(500),
 				RevealTimeout:          int64(30),
@@ -95,16 +97,14 @@ func TestCloser(t *testing.T) {
 					),
 				)
 			},
-			expectedError:   errors.New("EOF"),
-			expectedChannel: &Channel{},
+			expectedError: errors.New("EOF"),
 		},
 		testcase{
 			name: "unable to make http request",
 			prepHTTPMock: func() {
 				httpmock.Deactivate()
 			},
-			expectedError:   fmt.Errorf("Patch http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9: dial tcp %s:5001: connect: connection refused", localhostIP),
-			expectedChannel: &Channel{},
+			expectedError: fmt.Errorf("Patch http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9: dial tcp %s:5001: connect: connection refused", localhostIP),
 		},
 	}
 
@@ -129,6 +129,7 @@ func TestCloser(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, channel)
 				return
 			}
 
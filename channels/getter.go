@@ -0,0 +1,53 @@
+package channels
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Getter is a generic interface to get a single payment channel of a Raiden node by
+// its token and partner, e.g. the channel a tokens.Partner refers to.
+type Getter interface {
+	Get(ctx context.Context, tokenAddress, partnerAddress common.Address) (*Channel, error)
+}
+
+var _ Getter = &defaultGetter{}
+
+// NewGetter creates a new default channel getter given a Raiden node configuration
+// and an http client.
+func NewGetter(config *config.Config, httpClient *http.Client) Getter {
+	return &defaultGetter{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultGetter struct {
+	baseClient *util.BaseClient
+}
+
+// Get will return the channel of the node with the partner in the token network.
+func (getter *defaultGetter) Get(ctx context.Context, tokenAddress, partnerAddress common.Address) (*Channel, error) {
+	var (
+		err        error
+		requestURL *url.URL
+		channel    = &channel{}
+	)
+
+	if requestURL, err = getter.baseClient.Endpoint("channels/%s/%s", tokenAddress.Hex(), partnerAddress.Hex()); err != nil {
+		return nil, err
+	}
+
+	if err = getter.baseClient.Call(ctx, "GET", requestURL, nil, channel); err != nil {
+		return nil, err
+	}
+
+	return indexed(getter.baseClient.Config, channel)
+}
@@ -0,0 +1,90 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetter(t *testing.T) {
+	type testcase struct {
+		name            string
+		prepHTTPMock    func()
+		expectedChannel *Channel
+		expectedError   error
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully got the channel",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK,
+					`{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000,"total_deposit":35000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}`))
+			},
+			expectedChannel: &Channel{
+				TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
+				ChannelIdentifier:      int64(20),
+				PartnerAddress:         partnerAddress,
+				TokenAddress:           tokenAddress,
+				Balance:                big.NewInt(25000000),
+				TotalDeposit:           big.NewInt(35000000),
+				State:                  "opened",
+				SettleTimeout:          int64(500),
+				RevealTimeout:          int64(30),
+			},
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+			},
+			expectedError: errors.New("EOF"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err     error
+				channel *Channel
+
+				getter = NewGetter(config, http.DefaultClient)
+				ctx    = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			channel, err = getter.Get(ctx, tokenAddress, partnerAddress)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, channel)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedChannel, channel)
+		})
+	}
+}
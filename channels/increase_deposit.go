@@ -3,24 +3,25 @@ package channels
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
-	"strings"
 
+	"github.com/cpurta/go-raiden-client/amounts"
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/progress"
 	"github.com/cpurta/go-raiden-client/util"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 type increaseDepositRequest struct {
-	TotalDeposit int64 `json:"total_deposit"`
+	TotalDeposit json.Number `json:"total_deposit"`
 }
 
 // IncreaseDepositor represents a generic interface to Increase the Deposit of a Payment Channel given a token and
 // partner address.
 type IncreaseDepositor interface {
-	IncreaseDeposit(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit int64) (*Channel, error)
+	IncreaseDeposit(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit *big.Int) (*Channel, error)
 }
 
 // NewIncreaseDepositor creates a new default Channel depositor increaser given a Raiden node configuration
@@ -39,67 +40,39 @@ type defaultIncreaseDepositor struct {
 }
 
 // Close will increase the deposit a payment channel given a token address and a partner address.
-func (depositor *defaultIncreaseDepositor) IncreaseDeposit(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit int64) (*Channel, error) {
+// Its progress is reported to the progress options of the context, if any. It waits
+// for the other mutations of the channel by the process to be done first, so that
+// concurrent deposits don't race on the total deposit.
+func (depositor *defaultIncreaseDepositor) IncreaseDeposit(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit *big.Int) (*Channel, error) {
 	var (
 		err     error
+		unlock  func()
 		channel = &channel{}
 
 		requestURL             *url.URL
-		request                *http.Request
-		response               *http.Response
-		requestBody            []byte
 		increaseDepositRequest = &increaseDepositRequest{
-			TotalDeposit: deposit,
+			TotalDeposit: amounts.Number(deposit),
 		}
 	)
 
-	if requestURL, err = depositor.getRequestURL(tokenAddress, partnerAddress); err != nil {
+	if requestURL, err = depositor.baseClient.Endpoint("channels/%s/%s", tokenAddress.Hex(), partnerAddress.Hex()); err != nil {
 		return nil, err
 	}
 
-	if requestBody, err = json.Marshal(increaseDepositRequest); err != nil {
+	if unlock, err = mutations.lock(ctx, requestURL); err != nil {
 		return nil, err
 	}
+	defer unlock()
 
-	if request, err = http.NewRequest("PATCH", requestURL.String(), strings.NewReader(string(requestBody))); err != nil {
-		return nil, err
-	}
-
-	request = request.WithContext(ctx)
-
-	if response, err = depositor.baseClient.HTTPClient.Do(request); err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&channel); err != nil {
-		return nil, err
+	deposited := func(channel *Channel) bool {
+		return channel.TotalDeposit != nil && channel.TotalDeposit.Cmp(amounts.OrZero(deposit)) >= 0
 	}
 
-	return &Channel{
-		TokenNetworkIdentifier: common.HexToAddress(channel.TokenNetworkIdentifier),
-		ChannelIdentifier:      channel.ChannelIdentifier,
-		PartnerAddress:         common.HexToAddress(channel.PartnerAddress),
-		TokenAddress:           common.HexToAddress(channel.TokenAddress),
-		Balance:                channel.Balance,
-		TotalDeposit:           channel.TotalDeposit,
-		State:                  channel.State,
-		SettleTimeout:          channel.SettleTimeout,
-		RevealTimeout:          channel.RevealTimeout,
-	}, nil
-}
-
-func (depositor *defaultIncreaseDepositor) getRequestURL(tokenAddress, partnerAddress common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/channels/%s/%s", depositor.baseClient.Config.Host, depositor.baseClient.Config.APIVersion, tokenAddress.Hex(), partnerAddress.Hex())
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
+	if err = progress.Track(ctx, progress.OperationChannelDeposit, progress.TransactionStages, pollMined(depositor.baseClient, tokenAddress, partnerAddress, deposited), func(ctx context.Context) error {
+		return depositor.baseClient.Call(ctx, "PATCH", requestURL, increaseDepositRequest, channel)
+	}); err != nil {
 		return nil, err
 	}
 
-	return requestURL, nil
+	return indexed(depositor.baseClient.Config, channel)
 }
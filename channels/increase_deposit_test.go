@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"os"
 	"testing"
 
+	"github.com/cpurta/go-raiden-client/address"
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jarcoal/httpmock"
@@ -31,7 +33,7 @@ func ExampleIncreaseDepositor() {
 
 	channelClient = NewClient(config, http.DefaultClient)
 
-	if channel, err = channelClient.IncreaseDeposit(context.Background(), tokenAddress, partnerAddress, deposit); err != nil {
+	if channel, err = channelClient.IncreaseDeposit(context.Background(), tokenAddress, partnerAddress, big.NewInt(deposit)); err != nil {
 		panic(fmt.Sprintf("unable to increase deposit in payment channel: %s", err.Error()))
 	}
 
@@ -73,12 +75,12 @@ func TestIncreaseDepositer(t *testing.T) {
 			},
 			expectedError: nil,
 			expectedChannel: &Channel{
-				TokenNetworkIdentifier: common.HexToAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
+				TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
 				ChannelIdentifier:      int64(20),
 				PartnerAddress:         common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
 				TokenAddress:           common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
-				Balance:                int64(25000100),
-				TotalDeposit:           int64(35000100),
+				Balance:                big.NewInt(25000100),
+				TotalDeposit:           big.NewInt(35000100),
 				State:                  "opened",
 				SettleTimeout:          int64(500),
 				RevealTimeout:          int64(30),
@@ -96,16 +98,14 @@ func TestIncreaseDepositer(t *testing.T) {
 					),
 				)
 			},
-			expectedError:   errors.New("EOF"),
-			expectedChannel: &Channel{},
+			expectedError: errors.New("EOF"),
 		},
 		testcase{
 			name: "unable to make http request",
 			prepHTTPMock: func() {
 				httpmock.Deactivate()
 			},
-			expectedError:   fmt.Errorf("Patch http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9: dial tcp %s:5001: connect: connection refused", localhostIP),
-			expectedChannel: &Channel{},
+			expectedError: fmt.Errorf("Patch http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9: dial tcp %s:5001: connect: connection refused", localhostIP),
 		},
 	}
 
@@ -127,10 +127,11 @@ func TestIncreaseDepositer(t *testing.T) {
 
 			tc.prepHTTPMock()
 
-			channel, err = despositer.IncreaseDeposit(ctx, tokenAddress, partnerAddress, totalDeposit)
+			channel, err = despositer.IncreaseDeposit(ctx, tokenAddress, partnerAddress, big.NewInt(totalDeposit))
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, channel)
 				return
 			}
 
@@ -0,0 +1,162 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrUnknownChannel is returned when no channel of the node has the identifier being
+// resolved, even after listing the channels of its token anew.
+var ErrUnknownChannel = errors.New("unknown channel")
+
+// Index maps the channel_identifier referenced by pending transfers and events back to
+// the channels of a node. Channel identifiers are only unique within a token network,
+// so channels are resolved by the token address along with their identifier.
+//
+// Channels are listed on the first resolution of a token and listed anew when an
+// identifier is not known yet, e.g. for a channel opened since. Channels the caller
+// already has, such as the result of an open, can be added with Update.
+type Index struct {
+	lister Lister
+	cache  *config.Cache
+}
+
+// indexPrefix is the prefix of the keys of the channels of an index in its cache.
+const indexPrefix = "channels/"
+
+// NewIndex creates an empty index of the channels listed by the lister.
+func NewIndex(lister Lister) *Index {
+	return &Index{
+		lister: lister,
+		cache:  config.NewCache(),
+	}
+}
+
+// IndexOf returns the index of the channels of the node of the configuration, kept in
+// its Cache so that every sub-client created with the configuration resolves the
+// channels alike, and the channels opened, deposited to, withdrawn from and closed
+// through the channels client are updated in it. It is a new index when the
+// configuration has no cache.
+func IndexOf(config *config.Config, httpClient *http.Client) *Index {
+	var index = NewIndex(NewLister(config, httpClient))
+
+	if config != nil && config.Cache != nil {
+		index.cache = config.Cache
+	}
+
+	return index
+}
+
+// Refresh lists all the channels of the node into the index.
+func (index *Index) Refresh(ctx context.Context) error {
+	var (
+		err         error
+		channelList []*Channel
+	)
+
+	if channelList, err = index.lister.ListAll(ctx); err != nil {
+		return err
+	}
+
+	index.Update(channelList...)
+
+	return nil
+}
+
+// Update adds the channels to the index, replacing the ones with the same token
+// address and identifier.
+func (index *Index) Update(channelList ...*Channel) {
+	for _, channel := range channelList {
+		index.cache.Store(channel.TokenAddress, indexKey(channel.ChannelIdentifier), channel)
+	}
+}
+
+// Invalidate removes the channels of the token from the index, e.g. after they were
+// changed outside of the client, so that they are listed anew when next resolved.
+func (index *Index) Invalidate(tokenAddress common.Address) {
+	index.cache.Invalidate(tokenAddress, indexPrefix)
+}
+
+// Lookup returns the indexed channel of the token with the identifier, without
+// listing the channels of the node.
+func (index *Index) Lookup(tokenAddress common.Address, channelIdentifier int64) (*Channel, bool) {
+	value, ok := index.cache.Load(tokenAddress, indexKey(channelIdentifier))
+	if !ok {
+		return nil, false
+	}
+
+	return value.(*Channel), true
+}
+
+// Resolve returns the channel of the token with the identifier, listing the channels
+// of the token when it is not indexed yet. ErrUnknownChannel is returned when the node
+// has no such channel.
+func (index *Index) Resolve(ctx context.Context, tokenAddress common.Address, channelIdentifier int64) (*Channel, error) {
+	var (
+		err         error
+		channelList []*Channel
+	)
+
+	if channel, ok := index.Lookup(tokenAddress, channelIdentifier); ok {
+		return channel, nil
+	}
+
+	if channelList, err = index.lister.ListToken(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	index.Update(channelList...)
+
+	if channel, ok := index.Lookup(tokenAddress, channelIdentifier); ok {
+		return channel, nil
+	}
+
+	return nil, ErrUnknownChannel
+}
+
+// Partner returns the partner address of the channel of the token with the
+// identifier, see Resolve.
+func (index *Index) Partner(ctx context.Context, tokenAddress common.Address, channelIdentifier int64) (common.Address, error) {
+	channel, err := index.Resolve(ctx, tokenAddress, channelIdentifier)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return channel.PartnerAddress, nil
+}
+
+func indexKey(channelIdentifier int64) string {
+	return indexPrefix + strconv.FormatInt(channelIdentifier, 10)
+}
+
+// indexed converts the channel answered by the node and updates it in the index of
+// the configuration, see cacheChannels.
+func indexed(config *config.Config, response *channel) (*Channel, error) {
+	channel, err := response.toChannel()
+	if err != nil {
+		return nil, err
+	}
+
+	cacheChannels(config, channel)
+
+	return channel, nil
+}
+
+// cacheChannels updates the channels answered by the node in the index of the
+// configuration, when it has a cache, see IndexOf.
+func cacheChannels(config *config.Config, channelList ...*Channel) {
+	if config == nil || config.Cache == nil {
+		return
+	}
+
+	for _, channel := range channelList {
+		if channel.ChannelIdentifier != 0 {
+			config.Cache.Store(channel.TokenAddress, indexKey(channel.ChannelIdentifier), channel)
+		}
+	}
+}
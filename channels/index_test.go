@@ -0,0 +1,124 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleIndex() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		index        = NewIndex(NewLister(config, http.DefaultClient))
+		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+	)
+
+	// e.g. the token address and channel identifier of a pending transfer
+	partner, err := index.Partner(context.Background(), tokenAddress, 20)
+	if err != nil {
+		panic(fmt.Sprintf("unable to resolve channel: %s", err.Error()))
+	}
+
+	fmt.Println("pending transfer with partner:", partner.Hex())
+}
+
+func TestIndex(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		ctx          = context.Background()
+		index        = NewIndex(NewLister(config, http.DefaultClient))
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		otherToken   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359")
+		partner      = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		newPartner   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		tokenURL     = "http://localhost:5001/api/v1/channels/" + tokenAddress.Hex()
+		channelsJSON = `[{"channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":30,"total_deposit":30,"state":"opened"}]`
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", tokenURL, httpmock.NewStringResponder(http.StatusOK, channelsJSON))
+
+	_, ok := index.Lookup(tokenAddress, 20)
+	assert.False(t, ok)
+
+	address, err := index.Partner(ctx, tokenAddress, 20)
+	require.NoError(t, err)
+	assert.Equal(t, partner, address)
+
+	_, err = index.Partner(ctx, tokenAddress, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+tokenURL])
+
+	_, err = index.Resolve(ctx, tokenAddress, 21)
+	assert.Equal(t, ErrUnknownChannel, err)
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()["GET "+tokenURL])
+
+	index.Update(&Channel{TokenAddress: otherToken, ChannelIdentifier: 20, PartnerAddress: newPartner})
+
+	address, err = index.Partner(ctx, otherToken, 20)
+	require.NoError(t, err)
+	assert.Equal(t, newPartner, address)
+
+	channel, ok := index.Lookup(tokenAddress, 20)
+	require.True(t, ok)
+	assert.Equal(t, partner, channel.PartnerAddress)
+}
+
+func TestIndexOf(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+			Cache:      config.NewCache(),
+		}
+		ctx          = context.Background()
+		index        = IndexOf(config, http.DefaultClient)
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partner      = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		tokenURL     = "http://localhost:5001/api/v1/channels/" + tokenAddress.Hex()
+		channelJSON  = `{"channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":30,"total_deposit":30,"state":"opened"}`
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusCreated, channelJSON))
+	httpmock.RegisterResponder("GET", tokenURL, httpmock.NewStringResponder(http.StatusOK, "["+channelJSON+"]"))
+
+	_, err := NewOpener(config, http.DefaultClient).Open(ctx, tokenAddress, partner, nil, DefaultSettleTimeout)
+	require.NoError(t, err)
+
+	// the channel opened by another sub-client is resolved without listing
+	address, err := index.Partner(ctx, tokenAddress, 20)
+	require.NoError(t, err)
+	assert.Equal(t, partner, address)
+	assert.Equal(t, 0, httpmock.GetCallCountInfo()["GET "+tokenURL])
+
+	channel, ok := IndexOf(config, http.DefaultClient).Lookup(tokenAddress, 20)
+	require.True(t, ok)
+	assert.Equal(t, "opened", channel.State)
+
+	index.Invalidate(tokenAddress)
+
+	_, ok = index.Lookup(tokenAddress, 20)
+	assert.False(t, ok)
+
+	_, err = index.Partner(ctx, tokenAddress, 20)
+	require.NoError(t, err)
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+tokenURL], "the invalidated channels are listed anew")
+}
@@ -0,0 +1,31 @@
+//go:build go1.23
+// +build go1.23
+
+package channels
+
+import (
+	"context"
+	"iter"
+
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// All returns an iterator over every channel of the node, listed with the lister once
+// the iteration starts, see util.Iterate:
+//
+//	for channel, err := range channels.All(ctx, client.Channels()) {
+//		if err != nil {
+//			return err
+//		}
+//	}
+func All(ctx context.Context, lister Lister) iter.Seq2[*Channel, error] {
+	return util.Iterate(ctx, lister.ListAll)
+}
+
+// AllToken returns an iterator over the channels of the token, see All.
+func AllToken(ctx context.Context, lister Lister, tokenAddress common.Address) iter.Seq2[*Channel, error] {
+	return util.Iterate(ctx, func(ctx context.Context) ([]*Channel, error) {
+		return lister.ListToken(ctx, tokenAddress)
+	})
+}
@@ -0,0 +1,66 @@
+//go:build go1.23
+// +build go1.23
+
+package channels
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleAll() {
+	var (
+		lister = NewLister(&config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}, http.DefaultClient)
+	)
+
+	for channel, err := range All(context.Background(), lister) {
+		if err != nil {
+			panic(fmt.Sprintf("unable to list channels: %s", err.Error()))
+		}
+
+		if channel.State == "opened" {
+			fmt.Println("open channel with", channel.PartnerAddress.Hex())
+			break
+		}
+	}
+}
+
+func TestAll(t *testing.T) {
+	var (
+		lister = NewLister(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient)
+		ctx    = context.Background()
+		token  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK,
+		`[{"channel_identifier":1,"state":"opened","balance":10},{"channel_identifier":2,"state":"closed","balance":0}]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/"+token.Hex(), httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+
+	var identifiers []int64
+
+	for channel, err := range All(ctx, lister) {
+		require.NoError(t, err)
+		identifiers = append(identifiers, channel.ChannelIdentifier)
+	}
+
+	assert.Equal(t, []int64{1, 2}, identifiers)
+
+	for channel, err := range AllToken(ctx, lister, token) {
+		assert.Nil(t, channel)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,118 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Label is what an operator attaches to a channel to tell it apart, e.g. the name
+// "Binance hot wallet" and a note on who to contact about it. The node knows nothing
+// of labels, which are stored by the client, see LabelStore.
+type Label struct {
+	Name string `json:"name"`
+	Note string `json:"note,omitempty"`
+}
+
+// LabelStore keeps the labels of channels in a storage.Store, keyed by the token and
+// partner of their channel as "labels/<token>/<partner>", so that a label survives
+// the channel being closed and opened again with the same partner.
+type LabelStore struct {
+	store storage.Store
+}
+
+// NewLabelStore creates a label store keeping the labels in the store.
+func NewLabelStore(store storage.Store) *LabelStore {
+	return &LabelStore{store: store}
+}
+
+// Get returns the label of the channel of the token with the partner, or nil when it
+// has none.
+func (labels *LabelStore) Get(tokenAddress, partnerAddress common.Address) (*Label, error) {
+	var label = &Label{}
+
+	value, err := labels.store.Get(labelKey(tokenAddress, partnerAddress))
+
+	switch {
+	case err == storage.ErrNotFound:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	if err = json.Unmarshal(value, label); err != nil {
+		return nil, err
+	}
+
+	return label, nil
+}
+
+// Set labels the channel of the token with the partner, replacing its label. A nil
+// label removes it.
+func (labels *LabelStore) Set(tokenAddress, partnerAddress common.Address, label *Label) error {
+	if label == nil {
+		return labels.store.Delete(labelKey(tokenAddress, partnerAddress))
+	}
+
+	value, err := json.Marshal(label)
+	if err != nil {
+		return err
+	}
+
+	return labels.store.Put(labelKey(tokenAddress, partnerAddress), value)
+}
+
+// Apply sets the Label of every channel to its stored label, or nil when it has none.
+func (labels *LabelStore) Apply(channelList ...*Channel) error {
+	for _, channel := range channelList {
+		label, err := labels.Get(channel.TokenAddress, channel.PartnerAddress)
+		if err != nil {
+			return err
+		}
+
+		channel.Label = label
+	}
+
+	return nil
+}
+
+// WithLabels returns a lister labelling the channels listed by the lister with their
+// stored labels, so that the labels show wherever channels are listed, e.g. by the
+// history recorder or in the moves of the rebalancer.
+func WithLabels(lister Lister, labels *LabelStore) Lister {
+	return &labelledLister{
+		lister: lister,
+		labels: labels,
+	}
+}
+
+type labelledLister struct {
+	lister Lister
+	labels *LabelStore
+}
+
+func (lister *labelledLister) ListAll(ctx context.Context) ([]*Channel, error) {
+	return lister.label(lister.lister.ListAll(ctx))
+}
+
+func (lister *labelledLister) ListToken(ctx context.Context, tokenAddress common.Address) ([]*Channel, error) {
+	return lister.label(lister.lister.ListToken(ctx, tokenAddress))
+}
+
+func (lister *labelledLister) label(channelList []*Channel, err error) ([]*Channel, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if err = lister.labels.Apply(channelList...); err != nil {
+		return nil, err
+	}
+
+	return channelList, nil
+}
+
+func labelKey(tokenAddress, partnerAddress common.Address) string {
+	return "labels/" + tokenAddress.Hex() + "/" + partnerAddress.Hex()
+}
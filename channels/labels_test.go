@@ -0,0 +1,106 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleWithLabels() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		labels         = NewLabelStore(storage.NewFileStore("raiden-labels.json"))
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	if err := labels.Set(tokenAddress, partnerAddress, &Label{Name: "Binance hot wallet"}); err != nil {
+		panic(err)
+	}
+
+	channelList, err := WithLabels(NewLister(config, http.DefaultClient), labels).ListToken(context.Background(), tokenAddress)
+	if err != nil {
+		panic(fmt.Sprintf("unable to list channels: %s", err.Error()))
+	}
+
+	for _, channel := range channelList {
+		if channel.Label != nil {
+			fmt.Println(channel.Label.Name, channel.PartnerAddress.Hex())
+		}
+	}
+}
+
+func TestLabelStore(t *testing.T) {
+	var (
+		labels         = NewLabelStore(storage.NewMemoryStore())
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		otherPartner   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		label          = &Label{Name: "Binance hot wallet", Note: "ask the exchange desk"}
+	)
+
+	missing, err := labels.Get(tokenAddress, partnerAddress)
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+
+	require.NoError(t, labels.Set(tokenAddress, partnerAddress, label))
+
+	stored, err := labels.Get(tokenAddress, partnerAddress)
+	require.NoError(t, err)
+	assert.Equal(t, label, stored)
+
+	channelList := []*Channel{
+		&Channel{TokenAddress: tokenAddress, PartnerAddress: partnerAddress},
+		&Channel{TokenAddress: tokenAddress, PartnerAddress: otherPartner, Label: &Label{Name: "stale"}},
+	}
+
+	require.NoError(t, labels.Apply(channelList...))
+	assert.Equal(t, label, channelList[0].Label)
+	assert.Nil(t, channelList[1].Label)
+
+	require.NoError(t, labels.Set(tokenAddress, partnerAddress, nil))
+
+	removed, err := labels.Get(tokenAddress, partnerAddress)
+	require.NoError(t, err)
+	assert.Nil(t, removed)
+}
+
+func TestWithLabels(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		labels         = NewLabelStore(storage.NewMemoryStore())
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		lister         = WithLabels(NewLister(config, http.DefaultClient), labels)
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+
+	require.NoError(t, labels.Set(tokenAddress, partnerAddress, &Label{Name: "Binance hot wallet"}))
+
+	channelList, err := lister.ListAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, channelList, 1)
+	assert.Equal(t, &Label{Name: "Binance hot wallet"}, channelList[0].Label)
+
+	_, err = lister.ListToken(context.Background(), tokenAddress)
+	assert.Error(t, err)
+}
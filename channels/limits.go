@@ -0,0 +1,92 @@
+package channels
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NewLimitedOpener creates a channel opener that validates the deposit of every
+// channel against the limits of its token network before opening it, returning a
+// *tokens.LimitError instead of the error of the node on a deprecated network or a
+// deposit above the limits. The limits are read for every open, since the tokens
+// held by the network change.
+func NewLimitedOpener(config *config.Config, limitsGetter tokens.LimitsGetter, httpClient *http.Client) Opener {
+	return &limitedOpener{
+		opener:       NewOpener(config, httpClient),
+		limitsGetter: limitsGetter,
+	}
+}
+
+type limitedOpener struct {
+	opener       Opener
+	limitsGetter tokens.LimitsGetter
+}
+
+// Open will validate the deposit and open the channel, see Opener.
+func (opener *limitedOpener) Open(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit *big.Int, settleTimeout int64) (*Channel, error) {
+	limits, err := opener.limitsGetter.Limits(ctx, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = limits.Validate(big.NewInt(0), amounts.OrZero(deposit)); err != nil {
+		return nil, err
+	}
+
+	return opener.opener.Open(ctx, tokenAddress, partnerAddress, deposit, settleTimeout)
+}
+
+// NewLimitedDepositor creates a depositor that validates every new total deposit
+// against the limits of the token network of the channel before depositing, see
+// NewLimitedOpener.
+func NewLimitedDepositor(config *config.Config, limitsGetter tokens.LimitsGetter, httpClient *http.Client) IncreaseDepositor {
+	return &limitedDepositor{
+		depositor:    NewIncreaseDepositor(config, httpClient),
+		lister:       NewLister(config, httpClient),
+		limitsGetter: limitsGetter,
+	}
+}
+
+type limitedDepositor struct {
+	depositor    IncreaseDepositor
+	lister       Lister
+	limitsGetter tokens.LimitsGetter
+}
+
+// IncreaseDeposit will validate the total deposit, only the part above the current
+// total deposit of the channel adding to the tokens held by the network, and
+// increase the deposit, see IncreaseDepositor.
+func (depositor *limitedDepositor) IncreaseDeposit(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit *big.Int) (*Channel, error) {
+	var (
+		err            error
+		limits         *tokens.Limits
+		channelList    []*Channel
+		currentDeposit = big.NewInt(0)
+	)
+
+	if limits, err = depositor.limitsGetter.Limits(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	if channelList, err = depositor.lister.ListToken(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	for _, channel := range channelList {
+		if channel.PartnerAddress == partnerAddress && channel.State == "opened" {
+			currentDeposit = amounts.OrZero(channel.TotalDeposit)
+		}
+	}
+
+	if err = limits.Validate(currentDeposit, amounts.OrZero(deposit)); err != nil {
+		return nil, err
+	}
+
+	return depositor.depositor.IncreaseDeposit(ctx, tokenAddress, partnerAddress, deposit)
+}
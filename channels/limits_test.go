@@ -0,0 +1,140 @@
+package channels
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// limitsGetterFunc gets limits with a function, for tests.
+type limitsGetterFunc func() (*tokens.Limits, error)
+
+func (getter limitsGetterFunc) Limits(ctx context.Context, tokenAddress common.Address) (*tokens.Limits, error) {
+	return getter()
+}
+
+func fixedLimits(deprecated bool) tokens.LimitsGetter {
+	return limitsGetterFunc(func() (*tokens.Limits, error) {
+		return &tokens.Limits{
+			ChannelParticipantDepositLimit: big.NewInt(100),
+			TokenNetworkDepositLimit:       big.NewInt(1000),
+			TokenNetworkDeposit:            big.NewInt(950),
+			Deprecated:                     deprecated,
+		}, nil
+	})
+}
+
+func TestLimitedOpener(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	type testcase struct {
+		name         string
+		deprecated   bool
+		deposit      int64
+		expectedCode raidenerrors.Code
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:    "deposit within the limits",
+			deposit: 30,
+		},
+		testcase{
+			name:         "deposit above the participant limit",
+			deposit:      120,
+			expectedCode: raidenerrors.CodeDepositLimitExceeded,
+		},
+		testcase{
+			name:         "deprecated token network",
+			deprecated:   true,
+			expectedCode: raidenerrors.CodeTokenNetworkDeprecated,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusCreated, `{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","total_deposit":30,"state":"opened"}`))
+
+			channel, err := NewLimitedOpener(config, fixedLimits(tc.deprecated), http.DefaultClient).Open(context.Background(), tokenAddress, partnerAddress, big.NewInt(tc.deposit), 500)
+
+			if tc.expectedCode != raidenerrors.CodeUnknown {
+				assert.True(t, raidenerrors.HasCode(err, tc.expectedCode))
+				assert.Zero(t, httpmock.GetTotalCallCount())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, "opened", channel.State)
+		})
+	}
+}
+
+func TestLimitedDepositor(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		channelURL     = "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+	)
+
+	type testcase struct {
+		name         string
+		totalDeposit int64
+		expectedCode raidenerrors.Code
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "added deposit within the network limit",
+			totalDeposit: 90,
+		},
+		testcase{
+			name:         "added deposit above the network limit",
+			totalDeposit: 95,
+			expectedCode: raidenerrors.CodeDepositLimitExceeded,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusOK, `[{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","total_deposit":40,"state":"opened"}]`))
+			httpmock.RegisterResponder("PATCH", channelURL, httpmock.NewStringResponder(http.StatusOK, `{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","total_deposit":90,"state":"opened"}`))
+
+			channel, err := NewLimitedDepositor(config, fixedLimits(false), http.DefaultClient).IncreaseDeposit(context.Background(), tokenAddress, partnerAddress, big.NewInt(tc.totalDeposit))
+
+			if tc.expectedCode != raidenerrors.CodeUnknown {
+				assert.True(t, raidenerrors.HasCode(err, tc.expectedCode))
+				assert.Zero(t, httpmock.GetCallCountInfo()["PATCH "+channelURL])
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, big.NewInt(90), channel.TotalDeposit)
+		})
+	}
+}
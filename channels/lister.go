@@ -0,0 +1,90 @@
+package channels
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Lister represents a generic interface to list all of the Payment Channels known
+// by a Raiden node, or only the channels that belong to a given token network.
+type Lister interface {
+	ListAll(ctx context.Context) ([]*Channel, error)
+	ListToken(ctx context.Context, tokenAddress common.Address) ([]*Channel, error)
+}
+
+var _ Lister = &defaultLister{}
+
+// NewLister creates a new default Channel lister given a Raiden node configuration
+// and an http client.
+func NewLister(config *config.Config, httpClient *http.Client) Lister {
+	return &defaultLister{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultLister struct {
+	baseClient *util.BaseClient
+}
+
+// ListAll will list all of the payment channels that the Raiden node is a part of.
+func (lister *defaultLister) ListAll(ctx context.Context) ([]*Channel, error) {
+	var (
+		url *url.URL
+		err error
+	)
+
+	if url, err = lister.baseClient.Endpoint("channels"); err != nil {
+		return nil, err
+	}
+
+	return lister.getChannels(ctx, url)
+}
+
+// ListToken will list all of the payment channels that the Raiden node has opened
+// for a given token.
+func (lister *defaultLister) ListToken(ctx context.Context, tokenAddress common.Address) ([]*Channel, error) {
+	var (
+		url *url.URL
+		err error
+	)
+
+	if url, err = lister.baseClient.Endpoint("channels/%s", tokenAddress.Hex()); err != nil {
+		return nil, err
+	}
+
+	return lister.getChannels(ctx, url)
+}
+
+func (lister *defaultLister) getChannels(ctx context.Context, url *url.URL) ([]*Channel, error) {
+	var (
+		err              error
+		channelResponses = make([]*channel, 0)
+		channels         = make([]*Channel, 0)
+	)
+
+	if err = lister.baseClient.Call(ctx, "GET", url, nil, &channelResponses); err != nil {
+		return nil, err
+	}
+
+	for _, channelResponse := range channelResponses {
+		var channel *Channel
+
+		if channel, err = channelResponse.toChannel(); err != nil {
+			return nil, err
+		}
+
+		channels = append(channels, channel)
+	}
+
+	cacheChannels(lister.baseClient.Config, channels...)
+
+	return channels, nil
+}
@@ -0,0 +1,294 @@
+package channels
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleLister() {
+	var (
+		channelClient *Client
+		config        = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		channels     []*Channel
+		err          error
+	)
+
+	channelClient = NewClient(config, http.DefaultClient)
+
+	if channels, err = channelClient.ListAll(context.Background()); err != nil {
+		panic(fmt.Sprintf("unable to list payment channels: %s", err.Error()))
+	}
+
+	fmt.Printf("all channels: %+v\n", channels)
+
+	if channels, err = channelClient.ListToken(context.Background(), tokenAddress); err != nil {
+		panic(fmt.Sprintf("unable to list token payment channels: %s", err.Error()))
+	}
+
+	fmt.Printf("token channels: %+v\n", channels)
+}
+
+func TestLister(t *testing.T) {
+	var (
+		localhostIP = "[::1]"
+		config      = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+	)
+
+	if os.Getenv("USE_IPV4") != "" {
+		localhostIP = "127.0.0.1"
+	}
+
+	type testcase struct {
+		name             string
+		prepHTTPMock     func()
+		expectedChannels []*Channel
+		expectedError    error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully listed payment channels",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder(
+					"GET",
+					"http://localhost:5001/api/v1/channels",
+					httpmock.NewStringResponder(
+						http.StatusOK,
+						`[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000,"total_deposit":35000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}]`,
+					),
+				)
+
+				httpmock.RegisterResponder(
+					"GET",
+					"http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+					httpmock.NewStringResponder(
+						http.StatusOK,
+						`[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000,"total_deposit":35000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}]`,
+					),
+				)
+			},
+			expectedError: nil,
+			expectedChannels: []*Channel{
+				&Channel{
+					TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
+					ChannelIdentifier:      int64(20),
+					PartnerAddress:         common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+					TokenAddress:           common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+					Balance:                big.NewInt(25000000),
+					TotalDeposit:           big.NewInt(35000000),
+					State:                  "opened",
+					SettleTimeout:          int64(500),
+					RevealTimeout:          int64(30),
+				},
+			},
+		},
+		testcase{
+			name: "successfully listed amounts larger than an int64",
+			prepHTTPMock: func() {
+				body := `[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000000000000000,"total_deposit":"35000000000000000000","state":"opened","settle_timeout":500,"reveal_timeout":30}]`
+
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, body))
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusOK, body))
+			},
+			expectedError: nil,
+			expectedChannels: []*Channel{
+				&Channel{
+					TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
+					ChannelIdentifier:      int64(20),
+					PartnerAddress:         common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+					TokenAddress:           common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+					Balance:                bigAmount("25000000000000000000"),
+					TotalDeposit:           bigAmount("35000000000000000000"),
+					State:                  "opened",
+					SettleTimeout:          int64(500),
+					RevealTimeout:          int64(30),
+				},
+			},
+		},
+		testcase{
+			name: "invalid amount",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK,
+					`[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"balance":"2.5","total_deposit":35000000,"state":"opened"}]`))
+			},
+			expectedError:    errors.New("invalid amount: 2.5"),
+			expectedChannels: nil,
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder(
+					"GET",
+					"http://localhost:5001/api/v1/channels",
+					httpmock.NewStringResponder(
+						http.StatusInternalServerError,
+						``,
+					),
+				)
+			},
+			expectedError:    errors.New("EOF"),
+			expectedChannels: nil,
+		},
+		testcase{
+			name: "unable to make http request",
+			prepHTTPMock: func() {
+				httpmock.Deactivate()
+			},
+			expectedError:    fmt.Errorf("Get http://localhost:5001/api/v1/channels: dial tcp %s:5001: connect: connection refused", localhostIP),
+			expectedChannels: nil,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err          error
+				channels     []*Channel
+				tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+
+				lister = NewLister(config, http.DefaultClient)
+				ctx    = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.Deactivate()
+
+			tc.prepHTTPMock()
+
+			// test list all
+
+			channels, err = lister.ListAll(ctx)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, channels)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedChannels, channels)
+
+			// test list token
+
+			channels, err = lister.ListToken(ctx, tokenAddress)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedChannels, channels)
+		})
+	}
+}
+
+func TestListerDecodeModes(t *testing.T) {
+	type testcase struct {
+		name          string
+		decodeMode    config.DecodeMode
+		expectedError error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:       "lenient decoding ignores unknown fields",
+			decodeMode: config.Lenient,
+		},
+		testcase{
+			name:          "strict decoding rejects unknown fields",
+			decodeMode:    config.Strict,
+			expectedError: errors.New(`json: unknown field "fee_schedule"`),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				config = &config.Config{
+					Host:       "http://localhost:5001",
+					APIVersion: "v1",
+					DecodeMode: tc.decodeMode,
+				}
+				lister = NewLister(config, http.DefaultClient)
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK,
+				`[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000,"total_deposit":35000000,"fee_schedule":{},"state":"opened","settle_timeout":500,"reveal_timeout":30}]`))
+
+			channels, err := lister.ListAll(context.Background())
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, channels, 1)
+			assert.Equal(t, big.NewInt(25000000), channels[0].Balance)
+		})
+	}
+}
+
+func bigAmount(value string) *big.Int {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		panic("invalid test amount " + value)
+	}
+
+	return amount
+}
+
+func TestListerNodeVersion(t *testing.T) {
+	var config = &config.Config{
+		Host:        "http://localhost:5001",
+		APIVersion:  "v1",
+		NodeVersion: "1.1.1",
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"GET",
+		"http://localhost:5001/api/v1/channels",
+		httpmock.NewStringResponder(
+			http.StatusOK,
+			`[{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":"20","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":"25000000","total_deposit":"35000000","state":"opened","settle_timeout":"500","reveal_timeout":"30"}]`,
+		),
+	)
+
+	channels, err := NewLister(config, http.DefaultClient).ListAll(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Channel{
+		&Channel{
+			TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
+			ChannelIdentifier:      20,
+			PartnerAddress:         common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+			TokenAddress:           common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+			Balance:                big.NewInt(25000000),
+			TotalDeposit:           big.NewInt(35000000),
+			State:                  "opened",
+			SettleTimeout:          500,
+			RevealTimeout:          30,
+		},
+	}, channels)
+}
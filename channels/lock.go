@@ -0,0 +1,65 @@
+package channels
+
+import (
+	"context"
+	"net/url"
+	"sync"
+)
+
+// mutations serializes the opening of a channel, the deposits in it, the withdraws
+// from it and its closing by every client of the process, so that the goroutines of
+// an application mutating a channel at once neither race on its total deposit nor
+// run into the conflicts the node answers while a transaction of the channel is
+// pending. Channels are told apart by their endpoint, i.e. by node, token and partner.
+var mutations = &channelLocks{locks: make(map[string]*channelLock)}
+
+type channelLocks struct {
+	mutex sync.Mutex
+	locks map[string]*channelLock
+}
+
+// channelLock is a semaphore rather than a mutex so that waiting for it stops when
+// the context is done, users counting the mutations holding or waiting for it so
+// that it is dropped once none is left.
+type channelLock struct {
+	semaphore chan struct{}
+	users     int
+}
+
+// lock will wait for the other mutations of the channel at the endpoint to be done,
+// returning the function releasing it, or the error of the context if it is done
+// first.
+func (locks *channelLocks) lock(ctx context.Context, endpoint *url.URL) (func(), error) {
+	var key = endpoint.String()
+
+	locks.mutex.Lock()
+
+	lock, ok := locks.locks[key]
+	if !ok {
+		lock = &channelLock{semaphore: make(chan struct{}, 1)}
+		locks.locks[key] = lock
+	}
+
+	lock.users++
+	locks.mutex.Unlock()
+
+	select {
+	case lock.semaphore <- struct{}{}:
+		return func() {
+			<-lock.semaphore
+			locks.release(key, lock)
+		}, nil
+	case <-ctx.Done():
+		locks.release(key, lock)
+		return nil, ctx.Err()
+	}
+}
+
+func (locks *channelLocks) release(key string, lock *channelLock) {
+	locks.mutex.Lock()
+	defer locks.mutex.Unlock()
+
+	if lock.users--; lock.users == 0 {
+		delete(locks.locks, key)
+	}
+}
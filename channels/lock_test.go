@@ -0,0 +1,144 @@
+package channels
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMutationsSerialized(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359")
+		partnerAddress = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+		channelURL     = "http://localhost:5001/api/v1/channels/" + tokenAddress.Hex() + "/" + partnerAddress.Hex()
+		channelJSON    = `{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x1f7402f55e142820ea3812106d0657103fc1709e","token_address":"0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359","balance":25000000,"total_deposit":35000000,"state":"opened","settle_timeout":100,"reveal_timeout":30}`
+		mutex          sync.Mutex
+		inFlight       int
+		maxInFlight    int
+		wg             sync.WaitGroup
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	responder := func(request *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		mutex.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mutex.Lock()
+		inFlight--
+		mutex.Unlock()
+
+		return httpmock.NewStringResponse(http.StatusOK, channelJSON), nil
+	}
+
+	httpmock.RegisterResponder("PATCH", channelURL, responder)
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", responder)
+
+	var (
+		client        = NewClient(config, http.DefaultClient)
+		otherClient   = NewClient(config, http.DefaultClient)
+		otherPartner  = common.HexToAddress("0x61c808d82a3ac53231750dadc13c777b59310bd9")
+		otherResponse = httpmock.NewStringResponder(http.StatusOK, channelJSON)
+		mutationFuncs = []func() error{
+			func() error {
+				_, err := client.Open(context.Background(), tokenAddress, partnerAddress, big.NewInt(10), 100)
+				return err
+			},
+			func() error {
+				_, err := client.IncreaseDeposit(context.Background(), tokenAddress, partnerAddress, big.NewInt(20))
+				return err
+			},
+			func() error {
+				_, err := otherClient.IncreaseDeposit(context.Background(), tokenAddress, partnerAddress, big.NewInt(30))
+				return err
+			},
+			func() error {
+				_, err := client.Withdraw(context.Background(), tokenAddress, partnerAddress, big.NewInt(5))
+				return err
+			},
+			func() error {
+				_, err := otherClient.Close(context.Background(), tokenAddress, partnerAddress)
+				return err
+			},
+		}
+	)
+
+	httpmock.RegisterResponder("PATCH", "http://localhost:5001/api/v1/channels/"+tokenAddress.Hex()+"/"+otherPartner.Hex(), otherResponse)
+
+	for _, mutation := range mutationFuncs {
+		wg.Add(1)
+
+		go func(mutation func() error) {
+			defer wg.Done()
+			assert.NoError(t, mutation())
+		}(mutation)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, 1, maxInFlight, "the mutations of a channel must be serialized")
+
+	// another channel is not held up by a pending mutation
+	unlock, err := mutations.lock(context.Background(), mustParse(t, channelURL))
+	require.NoError(t, err)
+
+	_, err = client.Close(context.Background(), tokenAddress, otherPartner)
+	assert.NoError(t, err)
+
+	unlock()
+
+	mutations.mutex.Lock()
+	assert.Empty(t, mutations.locks, "the locks are dropped once released")
+	mutations.mutex.Unlock()
+}
+
+func TestMutationsCancelled(t *testing.T) {
+	var endpoint = mustParse(t, "http://localhost:5001/api/v1/channels/0x89d24a6B4CcB1B6fAA2625fE562bDD9a23260359/0x1F7402f55e142820Ea3812106D0657103FC1709e")
+
+	unlock, err := mutations.lock(context.Background(), endpoint)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = mutations.lock(ctx, endpoint)
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	unlock()
+
+	unlock, err = mutations.lock(context.Background(), endpoint)
+	require.NoError(t, err)
+	unlock()
+
+	mutations.mutex.Lock()
+	assert.Empty(t, mutations.locks)
+	mutations.mutex.Unlock()
+}
+
+func mustParse(t *testing.T, rawURL string) *url.URL {
+	endpoint, err := url.Parse(rawURL)
+	require.NoError(t, err)
+
+	return endpoint
+}
@@ -0,0 +1,29 @@
+//go:build go1.21
+// +build go1.21
+
+package channels
+
+import (
+	"log/slog"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+)
+
+// LogValue implements slog.LogValuer, logging the channel as a group of its
+// identifier, addresses, state and amounts rather than a dump of the struct.
+func (channel Channel) LogValue() slog.Value {
+	var attrs = []slog.Attr{
+		slog.Int64("channel_identifier", channel.ChannelIdentifier),
+		slog.String("token_address", channel.TokenAddress.Hex()),
+		slog.String("partner_address", channel.PartnerAddress.Hex()),
+		slog.String("state", channel.State),
+		slog.String("balance", amounts.OrZero(channel.Balance).String()),
+		slog.String("total_deposit", amounts.OrZero(channel.TotalDeposit).String()),
+	}
+
+	if channel.TotalWithdraw != nil {
+		attrs = append(attrs, slog.String("total_withdraw", channel.TotalWithdraw.String()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
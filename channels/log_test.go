@@ -0,0 +1,39 @@
+//go:build go1.21
+// +build go1.21
+
+package channels
+
+import (
+	"bytes"
+	"log/slog"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChannelLogValue(t *testing.T) {
+	var (
+		buffer bytes.Buffer
+		logger = slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{ReplaceAttr: dropTime}))
+	)
+
+	logger.Info("closing", "channel", &Channel{
+		ChannelIdentifier: 20,
+		TokenAddress:      common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+		PartnerAddress:    common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+		Balance:           big.NewInt(25),
+		State:             "opened",
+	})
+
+	assert.Equal(t, "level=INFO msg=closing channel.channel_identifier=20 channel.token_address=0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8 channel.partner_address=0x61C808D82A3Ac53231750daDc13c777b59310bD9 channel.state=opened channel.balance=25 channel.total_deposit=0\n", buffer.String())
+}
+
+func dropTime(groups []string, attr slog.Attr) slog.Attr {
+	if len(groups) == 0 && attr.Key == slog.TimeKey {
+		return slog.Attr{}
+	}
+
+	return attr
+}
@@ -0,0 +1,53 @@
+package channels
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// OpenOrGet returns an opener that, when the node refuses to open a channel because
+// one already exists with the partner, returns the existing channel instead of the
+// error, as long as it is still open. Its deposit and settle timeout are the ones the
+// channel was opened with, not the ones of the call.
+//
+//	opener := channels.OpenOrGet(channels.NewOpener(config, httpClient), channels.NewLister(config, httpClient))
+func OpenOrGet(opener Opener, lister Lister) Opener {
+	return &openOrGetter{
+		opener: opener,
+		lister: lister,
+	}
+}
+
+type openOrGetter struct {
+	opener Opener
+	lister Lister
+}
+
+// Open will open the channel, or get the open channel that already exists with the
+// partner, see OpenOrGet.
+func (opener *openOrGetter) Open(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit *big.Int, settleTimeout int64) (*Channel, error) {
+	channel, err := opener.opener.Open(ctx, tokenAddress, partnerAddress, deposit, settleTimeout)
+	if err == nil {
+		return channel, nil
+	}
+
+	if !raidenerrors.HasCode(err, raidenerrors.CodeChannelExists) {
+		return nil, err
+	}
+
+	channelList, listErr := opener.lister.ListToken(ctx, tokenAddress)
+	if listErr != nil {
+		return nil, err
+	}
+
+	for _, existing := range channelList {
+		if existing.PartnerAddress == partnerAddress && existing.State == "opened" {
+			return existing, nil
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,110 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleOpenOrGet() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		opener         = OpenOrGet(NewOpener(config, http.DefaultClient), NewLister(config, http.DefaultClient))
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	channel, err := opener.Open(context.Background(), tokenAddress, partnerAddress, big.NewInt(1000), 500)
+	if err != nil {
+		panic(fmt.Sprintf("unable to open channel: %s", err.Error()))
+	}
+
+	fmt.Printf("usable channel: %+v\n", channel)
+}
+
+func TestOpenOrGet(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		openURL        = "http://localhost:5001/api/v1/channels"
+		listURL        = "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		channelJSON    = `{"channel_identifier":%d,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":30,"total_deposit":30,"state":"%s"}`
+	)
+
+	type testcase struct {
+		name               string
+		prepHTTPMock       func()
+		expectedIdentifier int64
+		expectedCode       raidenerrors.Code
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "opened channel",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PUT", openURL, httpmock.NewStringResponder(http.StatusCreated, fmt.Sprintf(channelJSON, 21, "opened")))
+			},
+			expectedIdentifier: 21,
+		},
+		testcase{
+			name: "existing open channel",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PUT", openURL, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Channel with given partner address already exists"}`))
+				httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "["+fmt.Sprintf(channelJSON, 20, "opened")+"]"))
+			},
+			expectedIdentifier: 20,
+		},
+		testcase{
+			name: "existing closed channel",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PUT", openURL, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Channel with given partner address already exists"}`))
+				httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "["+fmt.Sprintf(channelJSON, 20, "closed")+"]"))
+			},
+			expectedCode: raidenerrors.CodeChannelExists,
+		},
+		testcase{
+			name: "other conflict",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PUT", openURL, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Not enough balance to deposit. 10 tokens available"}`))
+			},
+			expectedCode: raidenerrors.CodeInsufficientFunds,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			channel, err := OpenOrGet(NewOpener(config, http.DefaultClient), NewLister(config, http.DefaultClient)).Open(context.Background(), tokenAddress, partnerAddress, big.NewInt(30), 500)
+
+			if tc.expectedCode != raidenerrors.CodeUnknown {
+				assert.True(t, raidenerrors.HasCode(err, tc.expectedCode))
+				assert.Nil(t, channel)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedIdentifier, channel.ChannelIdentifier)
+			assert.Equal(t, big.NewInt(30), channel.Balance)
+		})
+	}
+}
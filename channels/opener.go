@@ -3,27 +3,28 @@ package channels
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
-	"strings"
 
+	"github.com/cpurta/go-raiden-client/amounts"
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/progress"
 	"github.com/cpurta/go-raiden-client/util"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 type channelOpenRequest struct {
-	PartnerAddress string `json:"partner_address"`
-	TokenAddress   string `json:"token_address"`
-	TotalDeposit   int64  `json:"total_deposit"`
-	SettleTimeout  int64  `json:"settle_timeout"`
+	PartnerAddress string      `json:"partner_address"`
+	TokenAddress   string      `json:"token_address"`
+	TotalDeposit   json.Number `json:"total_deposit"`
+	SettleTimeout  int64       `json:"settle_timeout"`
 }
 
 // Opener represents a generic interface to Open a Payment Channel given a token,
 // partner address, deposit and a settle timeout.
 type Opener interface {
-	Open(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit, settleTimeout int64) (*Channel, error)
+	Open(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit *big.Int, settleTimeout int64) (*Channel, error)
 }
 
 // NewOpener creates a new default Channel opener given a Raiden node configuration
@@ -42,70 +43,46 @@ type defaultOpener struct {
 }
 
 // Open will open a new payment channel given a token address, partner address, deposit, and settle timeout.
-func (opener *defaultOpener) Open(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit, settleTimeout int64) (*Channel, error) {
+// Its progress is reported to the progress options of the context, if any. It waits for
+// the other mutations of the channel by the process to be done first.
+func (opener *defaultOpener) Open(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit *big.Int, settleTimeout int64) (*Channel, error) {
 	var (
 		err     error
+		unlock  func()
 		channel = &channel{}
 
 		requestURL         *url.URL
-		request            *http.Request
-		response           *http.Response
-		requestBody        []byte
+		channelURL         *url.URL
 		channelOpenRequest = &channelOpenRequest{
 			PartnerAddress: partnerAddress.Hex(),
 			TokenAddress:   tokenAddress.Hex(),
-			TotalDeposit:   deposit,
+			TotalDeposit:   amounts.Number(deposit),
 			SettleTimeout:  settleTimeout,
 		}
 	)
 
-	if requestURL, err = opener.getRequestURL(); err != nil {
+	if requestURL, err = opener.baseClient.Endpoint("channels"); err != nil {
 		return nil, err
 	}
 
-	if requestBody, err = json.Marshal(channelOpenRequest); err != nil {
+	if channelURL, err = opener.baseClient.Endpoint("channels/%s/%s", tokenAddress.Hex(), partnerAddress.Hex()); err != nil {
 		return nil, err
 	}
 
-	if request, err = http.NewRequest("PUT", requestURL.String(), strings.NewReader(string(requestBody))); err != nil {
+	if unlock, err = mutations.lock(ctx, channelURL); err != nil {
 		return nil, err
 	}
+	defer unlock()
 
-	request = request.WithContext(ctx)
-
-	if response, err = opener.baseClient.HTTPClient.Do(request); err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&channel); err != nil {
-		return nil, err
+	opened := func(channel *Channel) bool {
+		return channel.State == "opened"
 	}
 
-	return &Channel{
-		TokenNetworkIdentifier: common.HexToAddress(channel.TokenNetworkIdentifier),
-		ChannelIdentifier:      channel.ChannelIdentifier,
-		PartnerAddress:         common.HexToAddress(channel.PartnerAddress),
-		TokenAddress:           common.HexToAddress(channel.TokenAddress),
-		Balance:                channel.Balance,
-		TotalDeposit:           channel.TotalDeposit,
-		State:                  channel.State,
-		SettleTimeout:          channel.SettleTimeout,
-		RevealTimeout:          channel.RevealTimeout,
-	}, nil
-}
-
-func (opener *defaultOpener) getRequestURL() (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/channels", opener.baseClient.Config.Host, opener.baseClient.Config.APIVersion)
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
+	if err = progress.Track(ctx, progress.OperationChannelOpen, progress.TransactionStages, pollMined(opener.baseClient, tokenAddress, partnerAddress, opened), func(ctx context.Context) error {
+		return opener.baseClient.Call(ctx, "PUT", requestURL, channelOpenRequest, channel)
+	}); err != nil {
 		return nil, err
 	}
 
-	return requestURL, nil
+	return indexed(opener.baseClient.Config, channel)
 }
@@ -4,10 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math/big"
 	"net/http"
 	"os"
 	"testing"
 
+	"github.com/cpurta/go-raiden-client/address"
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jarcoal/httpmock"
@@ -31,7 +34,7 @@ func ExampleOpener() {
 
 	channelClient = NewClient(config, http.DefaultClient)
 
-	if channel, err = channelClient.IncreaseDeposit(context.Background(), tokenAddress, partnerAddress, settleTimeout); err != nil {
+	if channel, err = channelClient.IncreaseDeposit(context.Background(), tokenAddress, partnerAddress, big.NewInt(settleTimeout)); err != nil {
 		panic(fmt.Sprintf("unable to open payment channel: %s", err.Error()))
 	}
 
@@ -73,12 +76,12 @@ func TestOpener(t *testing.T) {
 			},
 			expectedError: nil,
 			expectedChannel: &Channel{
-				TokenNetworkIdentifier: common.HexToAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
+				TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"),
 				ChannelIdentifier:      int64(20),
 				PartnerAddress:         common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
 				TokenAddress:           common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
-				Balance:                int64(25000000),
-				TotalDeposit:           int64(35000000),
+				Balance:                big.NewInt(25000000),
+				TotalDeposit:           big.NewInt(35000000),
 				State:                  "opened",
 				SettleTimeout:          int64(500),
 				RevealTimeout:          int64(30),
@@ -96,16 +99,14 @@ func TestOpener(t *testing.T) {
 					),
 				)
 			},
-			expectedError:   errors.New("EOF"),
-			expectedChannel: &Channel{},
+			expectedError: errors.New("EOF"),
 		},
 		testcase{
 			name: "unable to make http request",
 			prepHTTPMock: func() {
 				httpmock.Deactivate()
 			},
-			expectedError:   fmt.Errorf("Put http://localhost:5001/api/v1/channels: dial tcp %s:5001: connect: connection refused", localhostIP),
-			expectedChannel: &Channel{},
+			expectedError: fmt.Errorf("Put http://localhost:5001/api/v1/channels: dial tcp %s:5001: connect: connection refused", localhostIP),
 		},
 	}
 
@@ -128,10 +129,11 @@ func TestOpener(t *testing.T) {
 
 			tc.prepHTTPMock()
 
-			channel, err = opener.Open(ctx, tokenAddress, partnerAddress, totalDeposit, settleTimeout)
+			channel, err = opener.Open(ctx, tokenAddress, partnerAddress, big.NewInt(totalDeposit), settleTimeout)
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, channel)
 				return
 			}
 
@@ -140,3 +142,32 @@ func TestOpener(t *testing.T) {
 		})
 	}
 }
+
+func TestOpenerLargeDeposit(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		// 100 tokens of 18 decimals, beyond the range of an int64
+		deposit, _ = new(big.Int).SetString("100000000000000000000", 10)
+		body       []byte
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", func(request *http.Request) (*http.Response, error) {
+		body, _ = ioutil.ReadAll(request.Body)
+
+		return httpmock.NewStringResponse(http.StatusCreated, `{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":100000000000000000000,"total_deposit":100000000000000000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}`), nil
+	})
+
+	channel, err := NewOpener(config, http.DefaultClient).Open(context.Background(), tokenAddress, partnerAddress, deposit, 500)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","total_deposit":100000000000000000000,"settle_timeout":500}`, string(body))
+	assert.Equal(t, 0, deposit.Cmp(channel.TotalDeposit))
+}
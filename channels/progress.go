@@ -0,0 +1,28 @@
+package channels
+
+import (
+	"context"
+
+	"github.com/cpurta/go-raiden-client/progress"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// pollMined returns the poll of the progress of a transaction on the channel of the
+// token with the partner, which is mined once the channel listed by the node is done.
+func pollMined(baseClient *util.BaseClient, tokenAddress, partnerAddress common.Address, done func(channel *Channel) bool) progress.Poll {
+	var lister = NewLister(baseClient.Config, baseClient.HTTPClient)
+
+	return func(ctx context.Context) (progress.Stage, error) {
+		channelList, err := lister.ListToken(ctx, tokenAddress)
+		if err != nil {
+			return "", err
+		}
+
+		if channel := findPartner(channelList, partnerAddress); channel != nil && done(channel) {
+			return progress.Mined, nil
+		}
+
+		return progress.Submitted, nil
+	}
+}
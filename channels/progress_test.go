@@ -0,0 +1,61 @@
+package channels
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/progress"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenProgress(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		channelJSON    = `{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":0,"total_deposit":0,"state":"opened","settle_timeout":500}`
+		listed         = make(chan struct{})
+		listOnce       sync.Once
+		mutex          sync.Mutex
+		stages         []progress.Stage
+		ctx            = progress.WithOptions(context.Background(), &progress.Options{
+			Report: func(event *progress.Event) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				stages = append(stages, event.Stage)
+			},
+			Interval: time.Millisecond,
+		})
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// the node answers the open once the opened channel was listed
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", func(request *http.Request) (*http.Response, error) {
+		<-listed
+		return httpmock.NewStringResponse(http.StatusCreated, channelJSON), nil
+	})
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", func(request *http.Request) (*http.Response, error) {
+		defer listOnce.Do(func() { close(listed) })
+		return httpmock.NewStringResponse(http.StatusOK, "["+channelJSON+"]"), nil
+	})
+
+	channel, err := NewOpener(config, http.DefaultClient).Open(ctx, tokenAddress, partnerAddress, big.NewInt(0), 500)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(7), channel.ChannelIdentifier)
+	assert.Equal(t, []progress.Stage{progress.Submitted, progress.Mined, progress.Confirmed}, stages)
+}
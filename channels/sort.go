@@ -0,0 +1,70 @@
+package channels
+
+import (
+	"sort"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// stateOrder is the position of the states of a channel in its lifecycle, states
+// that are not known coming last.
+var stateOrder = map[string]int{
+	"opened":  0,
+	"closed":  1,
+	"settled": 2,
+}
+
+// SortByBalance sorts the channels by balance, the largest first. The sort is stable,
+// so channels with the same balance keep their order.
+func SortByBalance(channelList []*Channel) {
+	sort.SliceStable(channelList, func(i, j int) bool {
+		return amounts.OrZero(channelList[i].Balance).Cmp(amounts.OrZero(channelList[j].Balance)) > 0
+	})
+}
+
+// SortByState sorts the channels by state in the order of their lifecycle: opened,
+// closed then settled, states that are not known coming last in alphabetical order.
+// The sort is stable, so channels in the same state keep their order, e.g. sorted by
+// balance beforehand.
+func SortByState(channelList []*Channel) {
+	sort.SliceStable(channelList, func(i, j int) bool {
+		var (
+			left, leftKnown   = stateOrder[channelList[i].State]
+			right, rightKnown = stateOrder[channelList[j].State]
+		)
+
+		switch {
+		case leftKnown && rightKnown:
+			return left < right
+		case leftKnown != rightKnown:
+			return leftKnown
+		}
+
+		return channelList[i].State < channelList[j].State
+	})
+}
+
+// GroupByToken groups the channels by token address, every group keeping the order of
+// the channels.
+func GroupByToken(channelList []*Channel) map[common.Address][]*Channel {
+	var groups = make(map[common.Address][]*Channel)
+
+	for _, channel := range channelList {
+		groups[channel.TokenAddress] = append(groups[channel.TokenAddress], channel)
+	}
+
+	return groups
+}
+
+// GroupByPartner groups the channels by partner address, every group keeping the
+// order of the channels, e.g. the channels of several tokens with the same partner.
+func GroupByPartner(channelList []*Channel) map[common.Address][]*Channel {
+	var groups = make(map[common.Address][]*Channel)
+
+	for _, channel := range channelList {
+		groups[channel.PartnerAddress] = append(groups[channel.PartnerAddress], channel)
+	}
+
+	return groups
+}
@@ -0,0 +1,70 @@
+package channels
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	sortToken1   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	sortToken2   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+	sortPartner1 = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	sortPartner2 = common.HexToAddress("0x00AF5cBfc8dC76cd599aF623E60F763228906F3E")
+)
+
+func sortChannels() []*Channel {
+	return []*Channel{
+		&Channel{ChannelIdentifier: 1, TokenAddress: sortToken1, PartnerAddress: sortPartner1, Balance: big.NewInt(10), State: "settled"},
+		&Channel{ChannelIdentifier: 2, TokenAddress: sortToken1, PartnerAddress: sortPartner2, Balance: big.NewInt(30), State: "opened"},
+		&Channel{ChannelIdentifier: 3, TokenAddress: sortToken2, PartnerAddress: sortPartner1, State: "waiting_for_close"},
+		&Channel{ChannelIdentifier: 4, TokenAddress: sortToken2, PartnerAddress: sortPartner1, Balance: big.NewInt(10), State: "closed"},
+		&Channel{ChannelIdentifier: 5, TokenAddress: sortToken1, PartnerAddress: sortPartner2, Balance: big.NewInt(20), State: "opened"},
+	}
+}
+
+func identifiers(channelList []*Channel) []int64 {
+	var identifiers = make([]int64, 0, len(channelList))
+
+	for _, channel := range channelList {
+		identifiers = append(identifiers, channel.ChannelIdentifier)
+	}
+
+	return identifiers
+}
+
+func TestSortByBalance(t *testing.T) {
+	var channelList = sortChannels()
+
+	SortByBalance(channelList)
+
+	assert.Equal(t, []int64{2, 5, 1, 4, 3}, identifiers(channelList))
+}
+
+func TestSortByState(t *testing.T) {
+	var channelList = sortChannels()
+
+	SortByBalance(channelList)
+	SortByState(channelList)
+
+	assert.Equal(t, []int64{2, 5, 4, 1, 3}, identifiers(channelList))
+}
+
+func TestGroupByToken(t *testing.T) {
+	var groups = GroupByToken(sortChannels())
+
+	assert.Len(t, groups, 2)
+	assert.Equal(t, []int64{1, 2, 5}, identifiers(groups[sortToken1]))
+	assert.Equal(t, []int64{3, 4}, identifiers(groups[sortToken2]))
+}
+
+func TestGroupByPartner(t *testing.T) {
+	var groups = GroupByPartner(sortChannels())
+
+	assert.Len(t, groups, 2)
+	assert.Equal(t, []int64{1, 3, 4}, identifiers(groups[sortPartner1]))
+	assert.Equal(t, []int64{2, 5}, identifiers(groups[sortPartner2]))
+	assert.Empty(t, GroupByPartner(nil))
+}
@@ -0,0 +1,202 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The timeouts of the Raiden node and the bounds of the deployed token networks, in
+// blocks, used when the node does not report them in its settings.
+const (
+	DefaultSettleTimeout    = 500
+	DefaultRevealTimeout    = 50
+	DefaultMinSettleTimeout = 500
+	DefaultMaxSettleTimeout = 555428
+)
+
+type settingsResponse struct {
+	DefaultSettleTimeout int64 `json:"default_settle_timeout"`
+	DefaultRevealTimeout int64 `json:"default_reveal_timeout"`
+	SettlementTimeoutMin int64 `json:"settlement_timeout_min"`
+	SettlementTimeoutMax int64 `json:"settlement_timeout_max"`
+}
+
+// Timeouts are the settle and reveal timeouts the node opens channels with, and the
+// bounds it enforces on the settle timeout of a new channel, in blocks.
+type Timeouts struct {
+	SettleTimeout    int64
+	RevealTimeout    int64
+	MinSettleTimeout int64
+	MaxSettleTimeout int64
+}
+
+// Validate returns a *TimeoutError when the node would refuse to open a channel with
+// the settle timeout, which must be within the bounds of the network and at least
+// twice the reveal timeout.
+func (timeouts *Timeouts) Validate(settleTimeout int64) error {
+	if settleTimeout < timeouts.MinSettleTimeout || settleTimeout > timeouts.MaxSettleTimeout || settleTimeout < 2*timeouts.RevealTimeout {
+		return &TimeoutError{SettleTimeout: settleTimeout, Timeouts: timeouts}
+	}
+
+	return nil
+}
+
+// TimeoutError is a settle timeout the node would refuse, returned before opening the
+// channel instead of the conflict response of the node. It has the
+// raidenerrors.CodeInvalidSettleTimeout code of that response, see
+// raidenerrors.HasCode.
+type TimeoutError struct {
+	SettleTimeout int64
+	Timeouts      *Timeouts
+}
+
+func (err *TimeoutError) Error() string {
+	if err.SettleTimeout < err.Timeouts.MinSettleTimeout || err.SettleTimeout > err.Timeouts.MaxSettleTimeout {
+		return fmt.Sprintf("settle timeout of %d blocks must be between %d and %d blocks", err.SettleTimeout, err.Timeouts.MinSettleTimeout, err.Timeouts.MaxSettleTimeout)
+	}
+
+	return fmt.Sprintf("settle timeout of %d blocks must be at least twice the reveal timeout of %d blocks", err.SettleTimeout, err.Timeouts.RevealTimeout)
+}
+
+// Code returns raidenerrors.CodeInvalidSettleTimeout.
+func (err *TimeoutError) Code() raidenerrors.Code {
+	return raidenerrors.CodeInvalidSettleTimeout
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *TimeoutError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// TimeoutsGetter is a generic interface to get the timeouts of a Raiden node.
+type TimeoutsGetter interface {
+	Timeouts(ctx context.Context) (*Timeouts, error)
+}
+
+// NewTimeoutsGetter creates a new default timeouts getter given a Raiden node
+// configuration and an http client.
+func NewTimeoutsGetter(config *config.Config, httpClient *http.Client) TimeoutsGetter {
+	return &defaultTimeoutsGetter{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultTimeoutsGetter struct {
+	baseClient *util.BaseClient
+}
+
+// Timeouts will return the timeouts the node reports in its settings, the defaults of
+// the Raiden node standing in for the ones it does not report, or for all of them on
+// nodes without the settings endpoint.
+func (getter *defaultTimeoutsGetter) Timeouts(ctx context.Context) (*Timeouts, error) {
+	var (
+		err        error
+		requestURL *url.URL
+		settings   = &settingsResponse{}
+		timeouts   = &Timeouts{
+			SettleTimeout:    DefaultSettleTimeout,
+			RevealTimeout:    DefaultRevealTimeout,
+			MinSettleTimeout: DefaultMinSettleTimeout,
+			MaxSettleTimeout: DefaultMaxSettleTimeout,
+		}
+	)
+
+	if requestURL, err = getter.baseClient.Endpoint("settings"); err != nil {
+		return nil, err
+	}
+
+	if err = getter.baseClient.Call(ctx, "GET", requestURL, nil, settings); err != nil {
+		if raidenerrors.StatusCode(err) == http.StatusNotFound {
+			return timeouts, nil
+		}
+
+		return nil, err
+	}
+
+	if settings.DefaultSettleTimeout > 0 {
+		timeouts.SettleTimeout = settings.DefaultSettleTimeout
+	}
+
+	if settings.DefaultRevealTimeout > 0 {
+		timeouts.RevealTimeout = settings.DefaultRevealTimeout
+	}
+
+	if settings.SettlementTimeoutMin > 0 {
+		timeouts.MinSettleTimeout = settings.SettlementTimeoutMin
+	}
+
+	if settings.SettlementTimeoutMax > 0 {
+		timeouts.MaxSettleTimeout = settings.SettlementTimeoutMax
+	}
+
+	return timeouts, nil
+}
+
+// NewCheckedOpener creates a channel opener that validates the settle timeout of
+// every channel against the timeouts of the node before opening it, a zero settle
+// timeout being the default one of the node. The timeouts are fetched on the first
+// open and kept for the lifetime of the opener.
+func NewCheckedOpener(config *config.Config, httpClient *http.Client) Opener {
+	return &checkedOpener{
+		opener:         NewOpener(config, httpClient),
+		timeoutsGetter: NewTimeoutsGetter(config, httpClient),
+	}
+}
+
+type checkedOpener struct {
+	opener         Opener
+	timeoutsGetter TimeoutsGetter
+
+	mutex    sync.Mutex
+	timeouts *Timeouts
+}
+
+// Open will validate the settle timeout and open the channel, see Opener.
+func (opener *checkedOpener) Open(ctx context.Context, tokenAddress, partnerAddress common.Address, deposit *big.Int, settleTimeout int64) (*Channel, error) {
+	var (
+		err      error
+		timeouts *Timeouts
+	)
+
+	if timeouts, err = opener.getTimeouts(ctx); err != nil {
+		return nil, err
+	}
+
+	if settleTimeout == 0 {
+		settleTimeout = timeouts.SettleTimeout
+	}
+
+	if err = timeouts.Validate(settleTimeout); err != nil {
+		return nil, err
+	}
+
+	return opener.opener.Open(ctx, tokenAddress, partnerAddress, deposit, settleTimeout)
+}
+
+func (opener *checkedOpener) getTimeouts(ctx context.Context) (*Timeouts, error) {
+	opener.mutex.Lock()
+	defer opener.mutex.Unlock()
+
+	if opener.timeouts == nil {
+		timeouts, err := opener.timeoutsGetter.Timeouts(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		opener.timeouts = timeouts
+	}
+
+	return opener.timeouts, nil
+}
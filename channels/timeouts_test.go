@@ -0,0 +1,175 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewCheckedOpener() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		opener         = NewCheckedOpener(config, http.DefaultClient)
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	channel, err := opener.Open(context.Background(), tokenAddress, partnerAddress, big.NewInt(1000), 50)
+	if raidenerrors.HasCode(err, raidenerrors.CodeInvalidSettleTimeout) {
+		fmt.Println("choose another settle timeout:", err.Error())
+		return
+	}
+
+	if err != nil {
+		panic(fmt.Sprintf("unable to open channel: %s", err.Error()))
+	}
+
+	fmt.Printf("opened channel: %+v\n", channel)
+}
+
+func TestTimeoutsGetter(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		settingsURL = "http://localhost:5001/api/v1/settings"
+	)
+
+	type testcase struct {
+		name             string
+		prepHTTPMock     func()
+		expectedTimeouts *Timeouts
+		expectedStatus   int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "timeouts reported by the node",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", settingsURL, httpmock.NewStringResponder(http.StatusOK,
+					`{"pathfinding_service_address":"https://pfs.example.com","default_settle_timeout":600,"default_reveal_timeout":60,"settlement_timeout_min":200}`))
+			},
+			expectedTimeouts: &Timeouts{SettleTimeout: 600, RevealTimeout: 60, MinSettleTimeout: 200, MaxSettleTimeout: DefaultMaxSettleTimeout},
+		},
+		testcase{
+			name: "node without the settings endpoint",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", settingsURL, httpmock.NewStringResponder(http.StatusNotFound, ``))
+			},
+			expectedTimeouts: &Timeouts{SettleTimeout: DefaultSettleTimeout, RevealTimeout: DefaultRevealTimeout, MinSettleTimeout: DefaultMinSettleTimeout, MaxSettleTimeout: DefaultMaxSettleTimeout},
+		},
+		testcase{
+			name: "unavailable node",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", settingsURL, httpmock.NewStringResponder(http.StatusServiceUnavailable, ``))
+			},
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			timeouts, err := NewTimeoutsGetter(config, http.DefaultClient).Timeouts(context.Background())
+
+			if tc.expectedStatus != 0 {
+				assert.Equal(t, tc.expectedStatus, raidenerrors.StatusCode(err))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTimeouts, timeouts)
+		})
+	}
+}
+
+func TestCheckedOpener(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	type testcase struct {
+		name            string
+		settleTimeout   int64
+		expectedRequest string
+		expectedError   string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:            "default settle timeout",
+			expectedRequest: `{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","total_deposit":100,"settle_timeout":600}`,
+		},
+		testcase{
+			name:            "settle timeout within the bounds",
+			settleTimeout:   1000,
+			expectedRequest: `{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","total_deposit":100,"settle_timeout":1000}`,
+		},
+		testcase{
+			name:          "settle timeout below the minimum",
+			settleTimeout: 100,
+			expectedError: "settle timeout of 100 blocks must be between 200 and 555428 blocks",
+		},
+		testcase{
+			name:          "settle timeout below twice the reveal timeout",
+			settleTimeout: 300,
+			expectedError: "settle timeout of 300 blocks must be at least twice the reveal timeout of 160 blocks",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requestBody string
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/settings", httpmock.NewStringResponder(http.StatusOK,
+				`{"default_settle_timeout":600,"default_reveal_timeout":160,"settlement_timeout_min":200}`))
+			httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", func(request *http.Request) (*http.Response, error) {
+				body, _ := ioutil.ReadAll(request.Body)
+				requestBody = string(body)
+
+				return httpmock.NewStringResponse(http.StatusCreated, `{"channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":100,"total_deposit":100,"state":"opened"}`), nil
+			})
+
+			channel, err := NewCheckedOpener(config, http.DefaultClient).Open(context.Background(), tokenAddress, partnerAddress, big.NewInt(100), tc.settleTimeout)
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedError, err.Error())
+				assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodeInvalidSettleTimeout))
+				assert.True(t, err.(*TimeoutError).Is(raidenerrors.ErrInvalidSettleTimeout))
+				assert.Zero(t, httpmock.GetCallCountInfo()["PUT http://localhost:5001/api/v1/channels"])
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expectedRequest, requestBody)
+			assert.Equal(t, int64(20), channel.ChannelIdentifier)
+		})
+	}
+}
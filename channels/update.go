@@ -0,0 +1,85 @@
+package channels
+
+import (
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The kinds of the updates of a channel between two lists of channels.
+const (
+	// UpdateAdded is a channel that was not listed before, e.g. a channel just opened.
+	UpdateAdded = "added"
+	// UpdateChanged is a channel whose state, balance, deposit or withdraw changed.
+	UpdateChanged = "changed"
+	// UpdateRemoved is a channel that is no longer listed, e.g. a settled channel.
+	UpdateRemoved = "removed"
+)
+
+// ChannelUpdate is a change of a channel between two lists of the channels of a node:
+// Previous is the channel as it was listed before, nil when it was added, and
+// Current the channel as it is listed now, nil when it was removed.
+type ChannelUpdate struct {
+	Kind     string
+	Previous *Channel
+	Current  *Channel
+}
+
+// StateChanged tells whether the update changed the state of the channel, e.g. from
+// "opened" to "closed", an added or removed channel changing it as well.
+func (update *ChannelUpdate) StateChanged() bool {
+	return update.Previous == nil || update.Current == nil || update.Previous.State != update.Current.State
+}
+
+// channelKey identifies a channel across lists: a channel with a partner that was
+// settled and opened again is another channel.
+type channelKey struct {
+	tokenNetwork      address.TokenNetworkAddress
+	tokenAddress      common.Address
+	partnerAddress    common.Address
+	channelIdentifier int64
+}
+
+func keyOf(channel *Channel) channelKey {
+	return channelKey{
+		tokenNetwork:      channel.TokenNetworkIdentifier,
+		tokenAddress:      channel.TokenAddress,
+		partnerAddress:    channel.PartnerAddress,
+		channelIdentifier: channel.ChannelIdentifier,
+	}
+}
+
+// Diff returns the updates of the channels from the previous list to the current
+// one, see Channel.Equal: the added and changed channels in the order of the current
+// list, then the removed ones in the order of the previous list.
+func Diff(previous, current []*Channel) []*ChannelUpdate {
+	var (
+		updates = make([]*ChannelUpdate, 0)
+		before  = make(map[channelKey]*Channel, len(previous))
+		listed  = make(map[channelKey]bool, len(current))
+	)
+
+	for _, channel := range previous {
+		before[keyOf(channel)] = channel
+	}
+
+	for _, channel := range current {
+		var key = keyOf(channel)
+
+		listed[key] = true
+
+		switch was, ok := before[key]; {
+		case !ok:
+			updates = append(updates, &ChannelUpdate{Kind: UpdateAdded, Current: channel})
+		case !was.Equal(channel):
+			updates = append(updates, &ChannelUpdate{Kind: UpdateChanged, Previous: was, Current: channel})
+		}
+	}
+
+	for _, channel := range previous {
+		if !listed[keyOf(channel)] {
+			updates = append(updates, &ChannelUpdate{Kind: UpdateRemoved, Previous: channel})
+		}
+	}
+
+	return updates
+}
@@ -0,0 +1,106 @@
+package channels
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	type testcase struct {
+		name            string
+		previous        []*Channel
+		current         []*Channel
+		expectedKinds   []string
+		expectedChannel []int64
+	}
+
+	var (
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		channel      = func(identifier int64, balance int64, state string) *Channel {
+			return &Channel{
+				ChannelIdentifier: identifier,
+				TokenAddress:      tokenAddress,
+				PartnerAddress:    common.BigToAddress(big.NewInt(identifier)),
+				Balance:           big.NewInt(balance),
+				TotalDeposit:      big.NewInt(100),
+				State:             state,
+			}
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:            "first list",
+			current:         []*Channel{channel(1, 100, "opened"), channel(2, 100, "opened")},
+			expectedKinds:   []string{UpdateAdded, UpdateAdded},
+			expectedChannel: []int64{1, 2},
+		},
+		testcase{
+			name:          "unchanged",
+			previous:      []*Channel{channel(1, 100, "opened")},
+			current:       []*Channel{channel(1, 100, "opened")},
+			expectedKinds: []string{},
+		},
+		testcase{
+			name:            "balance and state changes",
+			previous:        []*Channel{channel(1, 100, "opened"), channel(2, 100, "opened")},
+			current:         []*Channel{channel(1, 90, "opened"), channel(2, 100, "closed")},
+			expectedKinds:   []string{UpdateChanged, UpdateChanged},
+			expectedChannel: []int64{1, 2},
+		},
+		testcase{
+			name:            "opened and settled",
+			previous:        []*Channel{channel(1, 100, "opened"), channel(2, 100, "closed")},
+			current:         []*Channel{channel(1, 100, "opened"), channel(3, 100, "opened")},
+			expectedKinds:   []string{UpdateAdded, UpdateRemoved},
+			expectedChannel: []int64{3, 2},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				updates     = Diff(tc.previous, tc.current)
+				kinds       = make([]string, 0, len(updates))
+				identifiers = make([]int64, 0, len(updates))
+			)
+
+			for _, update := range updates {
+				kinds = append(kinds, update.Kind)
+
+				switch update.Kind {
+				case UpdateRemoved:
+					assert.Nil(t, update.Current)
+					identifiers = append(identifiers, update.Previous.ChannelIdentifier)
+				case UpdateAdded:
+					assert.Nil(t, update.Previous)
+					identifiers = append(identifiers, update.Current.ChannelIdentifier)
+				default:
+					identifiers = append(identifiers, update.Current.ChannelIdentifier)
+				}
+			}
+
+			assert.Equal(t, tc.expectedKinds, kinds)
+
+			if len(tc.expectedChannel) > 0 {
+				assert.Equal(t, tc.expectedChannel, identifiers)
+			}
+		})
+	}
+}
+
+func TestChannelUpdateStateChanged(t *testing.T) {
+	var (
+		opened = &Channel{ChannelIdentifier: 1, State: "opened", Balance: big.NewInt(100)}
+		spent  = &Channel{ChannelIdentifier: 1, State: "opened", Balance: big.NewInt(90)}
+		closed = &Channel{ChannelIdentifier: 1, State: "closed", Balance: big.NewInt(90)}
+	)
+
+	assert.False(t, (&ChannelUpdate{Kind: UpdateChanged, Previous: opened, Current: spent}).StateChanged())
+	assert.True(t, (&ChannelUpdate{Kind: UpdateChanged, Previous: spent, Current: closed}).StateChanged())
+	assert.True(t, (&ChannelUpdate{Kind: UpdateAdded, Current: opened}).StateChanged())
+	assert.True(t, (&ChannelUpdate{Kind: UpdateRemoved, Previous: closed}).StateChanged())
+}
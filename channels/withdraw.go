@@ -0,0 +1,309 @@
+package channels
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/progress"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultWithdrawTimeout is how long the partner has to confirm a withdraw before
+	// it is considered expired when no timeout is given.
+	DefaultWithdrawTimeout = 5 * time.Minute
+	// DefaultWithdrawInterval is the time between two checks of the channel while
+	// waiting for a withdraw when no interval is given.
+	DefaultWithdrawInterval = 5 * time.Second
+)
+
+// WithdrawReason tells why a withdraw failed.
+type WithdrawReason string
+
+const (
+	// WithdrawExpired is a withdraw the partner did not confirm before it expired.
+	WithdrawExpired WithdrawReason = "expired"
+	// WithdrawRejected is a withdraw the node refused, e.g. more than the balance.
+	WithdrawRejected WithdrawReason = "rejected"
+	// WithdrawChannelNotOpen is a withdraw from a channel that is, or got, closed.
+	WithdrawChannelNotOpen WithdrawReason = "channel_not_open"
+	// WithdrawChannelNotFound is a withdraw from a channel that does not exist.
+	WithdrawChannelNotFound WithdrawReason = "channel_not_found"
+)
+
+// WithdrawError is returned when a coordinated withdraw failed, with the reason it
+// failed, the number of times it was submitted and the last error of the node, if
+// any.
+type WithdrawError struct {
+	Reason         WithdrawReason
+	TokenAddress   common.Address
+	PartnerAddress common.Address
+	TotalWithdraw  *big.Int
+	Attempts       int
+	Err            error
+}
+
+func (err *WithdrawError) Error() string {
+	var message = fmt.Sprintf("withdraw of a total of %s from the channel with %s failed after %d attempts: %s", amounts.OrZero(err.TotalWithdraw), err.PartnerAddress.Hex(), err.Attempts, err.Reason)
+
+	if err.Err != nil {
+		message += ": " + err.Err.Error()
+	}
+
+	return message
+}
+
+// Code returns the code of the failure, see raidenerrors.HasCode.
+func (err *WithdrawError) Code() raidenerrors.Code {
+	switch err.Reason {
+	case WithdrawExpired:
+		return raidenerrors.CodeWithdrawExpired
+	case WithdrawChannelNotOpen:
+		return raidenerrors.CodeChannelNotOpen
+	case WithdrawChannelNotFound:
+		return raidenerrors.CodeChannelNotFound
+	}
+
+	if coded, ok := err.Err.(interface{ Code() raidenerrors.Code }); ok {
+		return coded.Code()
+	}
+
+	return raidenerrors.CodeUnknown
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *WithdrawError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+type withdrawRequest struct {
+	TotalWithdraw json.Number `json:"total_withdraw"`
+}
+
+// Withdrawer represents a generic interface to withdraw tokens from a payment channel
+// given a token and partner address, up to a new total withdraw.
+type Withdrawer interface {
+	Withdraw(ctx context.Context, tokenAddress, partnerAddress common.Address, totalWithdraw *big.Int) (*Channel, error)
+}
+
+// NewWithdrawer creates a new default channel withdrawer given a Raiden node
+// configuration and an http client.
+func NewWithdrawer(config *config.Config, httpClient *http.Client) Withdrawer {
+	return &defaultWithdrawer{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultWithdrawer struct {
+	baseClient *util.BaseClient
+}
+
+// Withdraw will request the withdraw of tokens from the channel, which the partner
+// has to confirm before the node makes it on chain. Its progress is reported to the
+// progress options of the context, if any. It waits for the other mutations of the
+// channel by the process to be done first.
+func (withdrawer *defaultWithdrawer) Withdraw(ctx context.Context, tokenAddress, partnerAddress common.Address, totalWithdraw *big.Int) (*Channel, error) {
+	var (
+		err        error
+		unlock     func()
+		requestURL *url.URL
+		channel    = &channel{}
+	)
+
+	if requestURL, err = withdrawer.baseClient.Endpoint("channels/%s/%s", tokenAddress.Hex(), partnerAddress.Hex()); err != nil {
+		return nil, err
+	}
+
+	if unlock, err = mutations.lock(ctx, requestURL); err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	mined := func(channel *Channel) bool {
+		return withdrawn(channel, totalWithdraw)
+	}
+
+	if err = progress.Track(ctx, progress.OperationChannelWithdraw, progress.TransactionStages, pollMined(withdrawer.baseClient, tokenAddress, partnerAddress, mined), func(ctx context.Context) error {
+		return withdrawer.baseClient.Call(ctx, "PATCH", requestURL, &withdrawRequest{TotalWithdraw: amounts.Number(totalWithdraw)}, channel)
+	}); err != nil {
+		return nil, err
+	}
+
+	return indexed(withdrawer.baseClient.Config, channel)
+}
+
+// WithdrawOptions configures a coordinated withdraw. Every attempt waits up to
+// Timeout for the partner to confirm the withdraw, checking the channel every
+// Interval, and an expired attempt is submitted again up to Retries times.
+type WithdrawOptions struct {
+	Timeout  time.Duration
+	Interval time.Duration
+	Retries  int
+}
+
+// WithdrawCoordinator is a generic interface to withdraw tokens from a payment channel
+// and wait for the withdraw to be made.
+type WithdrawCoordinator interface {
+	WithdrawAndWait(ctx context.Context, tokenAddress, partnerAddress common.Address, totalWithdraw *big.Int, options *WithdrawOptions) (*Channel, error)
+}
+
+// NewWithdrawCoordinator creates a new default withdraw coordinator given a Raiden
+// node configuration and an http client. The channel is checked on the Clock of the
+// configuration.
+func NewWithdrawCoordinator(config *config.Config, httpClient *http.Client) WithdrawCoordinator {
+	return &defaultWithdrawCoordinator{
+		withdrawer: NewWithdrawer(config, httpClient),
+		lister:     NewLister(config, httpClient),
+		clock:      config.Time(),
+	}
+}
+
+type defaultWithdrawCoordinator struct {
+	withdrawer Withdrawer
+	lister     Lister
+	clock      clock.Clock
+}
+
+// WithdrawAndWait will submit the withdraw and watch the channel until its total
+// withdraw reaches the requested one, submitting the withdraw again when the partner
+// did not confirm it in time. Failures are returned as a *WithdrawError, except for
+// the context being done.
+func (coordinator *defaultWithdrawCoordinator) WithdrawAndWait(ctx context.Context, tokenAddress, partnerAddress common.Address, totalWithdraw *big.Int, options *WithdrawOptions) (*Channel, error) {
+	var (
+		timeout  = DefaultWithdrawTimeout
+		interval = DefaultWithdrawInterval
+		retries  int
+		failure  = &WithdrawError{
+			TokenAddress:   tokenAddress,
+			PartnerAddress: partnerAddress,
+			TotalWithdraw:  amounts.Copy(totalWithdraw),
+		}
+	)
+
+	if options != nil {
+		retries = options.Retries
+
+		if options.Timeout > 0 {
+			timeout = options.Timeout
+		}
+
+		if options.Interval > 0 {
+			interval = options.Interval
+		}
+	}
+
+	for failure.Attempts <= retries {
+		failure.Attempts++
+
+		channel, reason, err := coordinator.attempt(ctx, tokenAddress, partnerAddress, totalWithdraw, timeout, interval)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if channel != nil {
+			return channel, nil
+		}
+
+		failure.Reason, failure.Err = reason, err
+
+		if reason != WithdrawExpired {
+			return nil, failure
+		}
+	}
+
+	return nil, failure
+}
+
+// attempt submits the withdraw and waits for it until the timeout, returning the
+// channel once the withdraw was made or the reason it was not.
+func (coordinator *defaultWithdrawCoordinator) attempt(ctx context.Context, tokenAddress, partnerAddress common.Address, totalWithdraw *big.Int, timeout, interval time.Duration) (*Channel, WithdrawReason, error) {
+	var (
+		err     error
+		lastErr error
+		channel *Channel
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// the node may only answer once the withdraw was confirmed, or give up waiting
+	// for the partner before the attempt expires
+	if channel, err = coordinator.withdrawer.Withdraw(ctx, tokenAddress, partnerAddress, totalWithdraw); err != nil {
+		switch {
+		case raidenerrors.HasCode(err, raidenerrors.CodeChannelNotOpen):
+			return nil, WithdrawChannelNotOpen, err
+		case raidenerrors.HasCode(err, raidenerrors.CodeChannelNotFound):
+			return nil, WithdrawChannelNotFound, err
+		case raidenerrors.HasCode(err, raidenerrors.CodeWithdrawExpired):
+			return nil, WithdrawExpired, err
+		case ctx.Err() == nil && !raidenerrors.IsRetryable(err):
+			return nil, WithdrawRejected, err
+		}
+
+		lastErr = err
+	} else if withdrawn(channel, totalWithdraw) {
+		return channel, "", nil
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, WithdrawExpired, lastErr
+		case <-coordinator.clock.After(interval):
+		}
+
+		var channelList []*Channel
+
+		if channelList, err = coordinator.lister.ListToken(ctx, tokenAddress); err != nil {
+			lastErr = err
+			continue
+		}
+
+		if channel = findPartner(channelList, partnerAddress); channel == nil {
+			return nil, WithdrawChannelNotFound, nil
+		}
+
+		if withdrawn(channel, totalWithdraw) {
+			return channel, "", nil
+		}
+
+		if channel.State != "opened" {
+			return nil, WithdrawChannelNotOpen, nil
+		}
+	}
+}
+
+func withdrawn(channel *Channel, totalWithdraw *big.Int) bool {
+	return channel.TotalWithdraw != nil && channel.TotalWithdraw.Cmp(amounts.OrZero(totalWithdraw)) >= 0
+}
+
+// findPartner returns the channel with the partner, the opened one when there are
+// several.
+func findPartner(channelList []*Channel, partnerAddress common.Address) *Channel {
+	var found *Channel
+
+	for _, channel := range channelList {
+		if channel.PartnerAddress != partnerAddress {
+			continue
+		}
+
+		if found == nil || channel.State == "opened" {
+			found = channel
+		}
+	}
+
+	return found
+}
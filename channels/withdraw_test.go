@@ -0,0 +1,182 @@
+package channels
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleWithdrawCoordinator() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		coordinator    = NewWithdrawCoordinator(config, http.DefaultClient)
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	channel, err := coordinator.WithdrawAndWait(context.Background(), tokenAddress, partnerAddress, big.NewInt(500), &WithdrawOptions{Retries: 2})
+	if withdrawErr, ok := err.(*WithdrawError); ok && withdrawErr.Reason == WithdrawExpired {
+		fmt.Println("the partner did not confirm the withdraw")
+		return
+	}
+
+	if err != nil {
+		panic(fmt.Sprintf("unable to withdraw: %s", err.Error()))
+	}
+
+	fmt.Printf("withdrawn a total of %s\n", channel.TotalWithdraw)
+}
+
+func TestWithdrawCoordinator(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		channelURL     = "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		listURL        = "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		channelJSON    = `{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":20,"total_deposit":30,"total_withdraw":%d,"state":"%s"}`
+		options        = &WithdrawOptions{Timeout: 30 * time.Millisecond, Interval: time.Millisecond, Retries: 1}
+	)
+
+	type testcase struct {
+		name                  string
+		prepHTTPMock          func()
+		expectedTotalWithdraw int64
+		expectedReason        WithdrawReason
+		expectedCode          raidenerrors.Code
+		expectedAttempts      int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "withdraw made when the node answers",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", channelURL, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(channelJSON, 10, "opened")))
+			},
+			expectedTotalWithdraw: 10,
+			expectedAttempts:      1,
+		},
+		testcase{
+			name: "withdraw confirmed by the partner later",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", channelURL, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(channelJSON, 0, "opened")))
+				httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "["+fmt.Sprintf(channelJSON, 10, "opened")+"]"))
+			},
+			expectedTotalWithdraw: 10,
+			expectedAttempts:      1,
+		},
+		testcase{
+			name: "withdraw expired on every attempt",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", channelURL, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(channelJSON, 0, "opened")))
+				httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "["+fmt.Sprintf(channelJSON, 0, "opened")+"]"))
+			},
+			expectedReason:   WithdrawExpired,
+			expectedCode:     raidenerrors.CodeWithdrawExpired,
+			expectedAttempts: 2,
+		},
+		testcase{
+			name: "withdraw refused by the node",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", channelURL, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Insufficient balance for the withdraw"}`))
+			},
+			expectedReason:   WithdrawRejected,
+			expectedCode:     raidenerrors.CodeInsufficientBalance,
+			expectedAttempts: 1,
+		},
+		testcase{
+			name: "channel closed on submit",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", channelURL, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Channel is not in an open state"}`))
+			},
+			expectedReason:   WithdrawChannelNotOpen,
+			expectedCode:     raidenerrors.CodeChannelNotOpen,
+			expectedAttempts: 1,
+		},
+		testcase{
+			name: "channel closed while waiting",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PATCH", channelURL, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(channelJSON, 0, "opened")))
+				httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "["+fmt.Sprintf(channelJSON, 0, "closed")+"]"))
+			},
+			expectedReason:   WithdrawChannelNotOpen,
+			expectedCode:     raidenerrors.CodeChannelNotOpen,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var coordinator = NewWithdrawCoordinator(config, http.DefaultClient)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			channel, err := coordinator.WithdrawAndWait(context.Background(), tokenAddress, partnerAddress, big.NewInt(10), options)
+
+			assert.Equal(t, tc.expectedAttempts, httpmock.GetCallCountInfo()["PATCH "+channelURL])
+
+			if tc.expectedReason != "" {
+				withdrawErr, ok := err.(*WithdrawError)
+				require.True(t, ok, "%v is not a withdraw error", err)
+
+				assert.Equal(t, tc.expectedReason, withdrawErr.Reason)
+				assert.Equal(t, tc.expectedAttempts, withdrawErr.Attempts)
+				assert.True(t, raidenerrors.HasCode(err, tc.expectedCode))
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, big.NewInt(tc.expectedTotalWithdraw), channel.TotalWithdraw)
+		})
+	}
+}
+
+func TestWithdrawLargeAmount(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		channelURL     = "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		// 20 tokens with 18 decimals, beyond an int64
+		totalWithdraw, _ = new(big.Int).SetString("20000000000000000000", 10)
+		received         string
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("PATCH", channelURL, func(request *http.Request) (*http.Response, error) {
+		body, _ := ioutil.ReadAll(request.Body)
+		received = string(body)
+
+		return httpmock.NewStringResponse(http.StatusConflict, `{"errors":"Channel is not in an open state"}`), nil
+	})
+
+	_, err := NewWithdrawCoordinator(config, http.DefaultClient).WithdrawAndWait(context.Background(), tokenAddress, partnerAddress, totalWithdraw, nil)
+
+	assert.JSONEq(t, `{"total_withdraw":20000000000000000000}`, received)
+	assert.EqualError(t, err, "withdraw of a total of 20000000000000000000 from the channel with 0x61C808D82A3Ac53231750daDc13c777b59310bD9 failed after 1 attempts: channel_not_open: raiden node error 409: Channel is not in an open state")
+}
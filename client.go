@@ -9,15 +9,26 @@ import (
 	"github.com/cpurta/go-raiden-client/channels"
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/cpurta/go-raiden-client/connections"
+	"github.com/cpurta/go-raiden-client/liquidity"
+	"github.com/cpurta/go-raiden-client/node"
 	"github.com/cpurta/go-raiden-client/payments"
 	"github.com/cpurta/go-raiden-client/pending_transfers"
 	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/cpurta/go-raiden-client/userdeposit"
 )
 
 // NewClient will return a Raiden client that is able to access all of the API
 // calls that are currently available on a Raiden node. This provides access to
-// the various sub-clients that correspond to the various API calls available.
+// the various sub-clients that correspond to the various API calls available,
+// which all share the configuration, with its middlewares, and the http client,
+// with its connection pool. A nil http client is http.DefaultClient. The client is
+// cheap to create and safe for concurrent use, a web server can build one when it
+// starts and share it between its requests.
 func NewClient(config *config.Config, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
 	return &Client{
 		AddressClient:          address.NewClient(config, httpClient),
 		TokensClient:           tokens.NewClient(config, httpClient),
@@ -25,11 +36,17 @@ func NewClient(config *config.Config, httpClient *http.Client) *Client {
 		PaymentsClient:         payments.NewClient(config, httpClient),
 		ConnectionsClient:      connections.NewClient(config, httpClient),
 		PendingTransfersClient: pendingtransfers.NewClient(config, httpClient),
+		NodeClient:             node.NewClient(config, httpClient),
+		UserDepositClient:      userdeposit.NewClient(config, httpClient),
+		LiquidityClient:        liquidity.NewClient(config, httpClient),
+		config:                 config,
+		httpClient:             httpClient,
 	}
 }
 
 // Client provides access to API sub-clients that correspond to the various API
-// calls that a Raiden node supports.
+// calls that a Raiden node supports. A sub-client replaced in its field before the
+// client is used, e.g. by a fake in tests, is returned by its accessor.
 type Client struct {
 	AddressClient          *address.Client
 	TokensClient           *tokens.Client
@@ -37,6 +54,12 @@ type Client struct {
 	PaymentsClient         *payments.Client
 	ConnectionsClient      *connections.Client
 	PendingTransfersClient *pendingtransfers.Client
+	NodeClient             *node.Client
+	UserDepositClient      *userdeposit.Client
+	LiquidityClient        *liquidity.Client
+
+	config     *config.Config
+	httpClient *http.Client
 }
 
 // Address returns the Address sub-client to access the address being used by the
@@ -74,3 +97,22 @@ func (client *Client) Connections() *connections.Client {
 func (client *Client) PendingTransfers() *pendingtransfers.Client {
 	return client.PendingTransfersClient
 }
+
+// Node returns the Node sub-client that will be able to get the address, version,
+// status and settings of the Raiden node, and to shut it down.
+func (client *Client) Node() *node.Client {
+	return client.NodeClient
+}
+
+// UserDeposit returns the UserDeposit sub-client that will be able to deposit in and
+// withdraw from the user deposit contract.
+func (client *Client) UserDeposit() *userdeposit.Client {
+	return client.UserDepositClient
+}
+
+// Liquidity returns the Liquidity sub-client that will be able to get the liquidity
+// of the node in every token, its balances net of the amounts locked by pending
+// transfers.
+func (client *Client) Liquidity() *liquidity.Client {
+	return client.LiquidityClient
+}
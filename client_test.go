@@ -4,9 +4,16 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
 
+	"github.com/cpurta/go-raiden-client/channels"
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/tokens"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Example() {
@@ -29,3 +36,90 @@ func Example() {
 
 	log.Println("raiden token address:", address.Hex())
 }
+
+// countingTransport counts the requests sent through it.
+type countingTransport struct {
+	mutex    sync.Mutex
+	requests int
+}
+
+func (transport *countingTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	transport.mutex.Lock()
+	transport.requests++
+	transport.mutex.Unlock()
+
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+func TestClientSubClients(t *testing.T) {
+	var (
+		node = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			switch request.URL.Path {
+			case "/api/v1/address":
+				writer.Write([]byte(`{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+			default:
+				writer.Write([]byte(`[]`))
+			}
+		}))
+		transport    = &countingTransport{}
+		raidenClient = NewClient(&config.Config{Host: node.URL, APIVersion: "v1"}, &http.Client{Transport: transport})
+		channelsSeen = make(chan *channels.Client, 10)
+		waitGroup    sync.WaitGroup
+	)
+	defer node.Close()
+
+	// every goroutine gets the same sub-client
+	for i := 0; i < cap(channelsSeen); i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+			channelsSeen <- raidenClient.Channels()
+		}()
+	}
+
+	waitGroup.Wait()
+	close(channelsSeen)
+
+	first := <-channelsSeen
+	for channelClient := range channelsSeen {
+		assert.True(t, first == channelClient)
+	}
+
+	// the sub-clients share the http client
+	_, err := raidenClient.Address().Get(context.Background())
+	require.NoError(t, err)
+
+	_, err = raidenClient.Channels().ListAll(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, transport.requests)
+
+	t.Run("sub-client fields", func(t *testing.T) {
+		var raidenClient = NewClient(&config.Config{Host: node.URL, APIVersion: "v1"}, nil)
+
+		// callers reading the fields rather than calling the accessors
+		assert.NotNil(t, raidenClient.AddressClient)
+		assert.NotNil(t, raidenClient.ChannelsClient)
+		assert.NotNil(t, raidenClient.PendingTransfersClient)
+		assert.NotNil(t, raidenClient.LiquidityClient)
+		assert.True(t, raidenClient.ChannelsClient == raidenClient.Channels())
+	})
+
+	t.Run("sub-client set beforehand", func(t *testing.T) {
+		var (
+			preset       = &tokens.Client{}
+			raidenClient = NewClient(&config.Config{Host: node.URL, APIVersion: "v1"}, nil)
+		)
+
+		raidenClient.TokensClient = preset
+
+		assert.True(t, preset == raidenClient.Tokens())
+		assert.NotNil(t, raidenClient.PendingTransfers())
+		assert.NotNil(t, raidenClient.Connections())
+		assert.NotNil(t, raidenClient.Payments())
+		assert.NotNil(t, raidenClient.Node())
+		assert.NotNil(t, raidenClient.UserDeposit())
+		assert.NotNil(t, raidenClient.Liquidity())
+	})
+}
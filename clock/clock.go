@@ -0,0 +1,132 @@
+// Package clock is the source of time of the watchers, schedulers, retries and caches
+// of the client, so that tests can replace the system time with a Manual clock and
+// move it forward instead of sleeping.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits for it to pass.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// Real returns a clock following the system time.
+func Real() Clock {
+	return &realClock{}
+}
+
+// Or returns the clock, or a real one when it is nil, for the options leaving their
+// clock unset.
+func Or(clock Clock) Clock {
+	if clock == nil {
+		return Real()
+	}
+
+	return clock
+}
+
+type realClock struct{}
+
+func (clock *realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (clock *realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewManual creates a clock standing still at start until it is advanced.
+func NewManual(start time.Time) *Manual {
+	return &Manual{
+		now:     start,
+		waiters: make([]*waiter, 0),
+		changed: make(chan struct{}),
+	}
+}
+
+// Manual is a clock which only moves when advanced, making timed tests deterministic.
+type Manual struct {
+	mutex   sync.Mutex
+	now     time.Time
+	waiters []*waiter
+	changed chan struct{}
+}
+
+type waiter struct {
+	deadline time.Time
+	channel  chan time.Time
+}
+
+// Now returns the current time of the clock.
+func (clock *Manual) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	return clock.now
+}
+
+// After returns a channel receiving the time once the clock was advanced by d.
+func (clock *Manual) After(d time.Duration) <-chan time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	channel := make(chan time.Time, 1)
+
+	if d <= 0 {
+		channel <- clock.now
+		return channel
+	}
+
+	clock.waiters = append(clock.waiters, &waiter{deadline: clock.now.Add(d), channel: channel})
+	clock.notify()
+
+	return channel
+}
+
+// Advance moves the clock forward by d, firing the channels of After whose time has
+// come.
+func (clock *Manual) Advance(d time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+
+	clock.now = clock.now.Add(d)
+
+	waiting := clock.waiters[:0]
+
+	for _, waiter := range clock.waiters {
+		if waiter.deadline.After(clock.now) {
+			waiting = append(waiting, waiter)
+			continue
+		}
+
+		waiter.channel <- clock.now
+	}
+
+	clock.waiters = waiting
+	clock.notify()
+}
+
+// BlockUntil waits until n callers are waiting on the channels of After, so that a
+// test can advance the clock only once the code it tests is waiting on it.
+func (clock *Manual) BlockUntil(n int) {
+	for {
+		clock.mutex.Lock()
+		waiting, changed := len(clock.waiters), clock.changed
+		clock.mutex.Unlock()
+
+		if waiting >= n {
+			return
+		}
+
+		<-changed
+	}
+}
+
+func (clock *Manual) notify() {
+	close(clock.changed)
+	clock.changed = make(chan struct{})
+}
@@ -0,0 +1,96 @@
+package clock
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleManual() {
+	var (
+		manual = NewManual(time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC))
+		done   = make(chan struct{})
+	)
+
+	go func() {
+		<-manual.After(time.Minute)
+		fmt.Println("a minute passed")
+		close(done)
+	}()
+
+	// the minute passes as soon as the goroutine waits for it
+	manual.BlockUntil(1)
+	manual.Advance(time.Minute)
+	<-done
+}
+
+func TestManual(t *testing.T) {
+	type testcase struct {
+		name          string
+		wait          time.Duration
+		advance       []time.Duration
+		expectedFired bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:          "not yet",
+			wait:          time.Minute,
+			advance:       []time.Duration{30 * time.Second},
+			expectedFired: false,
+		},
+		testcase{
+			name:          "exactly",
+			wait:          time.Minute,
+			advance:       []time.Duration{time.Minute},
+			expectedFired: true,
+		},
+		testcase{
+			name:          "several advances",
+			wait:          time.Minute,
+			advance:       []time.Duration{30 * time.Second, 45 * time.Second},
+			expectedFired: true,
+		},
+		testcase{
+			name:          "no wait",
+			wait:          0,
+			expectedFired: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				start  = time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC)
+				manual = NewManual(start)
+				after  = manual.After(tc.wait)
+				total  time.Duration
+			)
+
+			for _, d := range tc.advance {
+				manual.Advance(d)
+				total += d
+			}
+
+			assert.Equal(t, start.Add(total), manual.Now())
+
+			select {
+			case fired := <-after:
+				assert.True(t, tc.expectedFired)
+				assert.Equal(t, start.Add(total), fired)
+			default:
+				assert.False(t, tc.expectedFired)
+			}
+		})
+	}
+}
+
+func TestOr(t *testing.T) {
+	var manual = NewManual(time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC))
+
+	assert.Equal(t, manual, Or(manual))
+	assert.Equal(t, Real(), Or(nil))
+	assert.WithinDuration(t, time.Now(), Or(nil).Now(), time.Minute)
+}
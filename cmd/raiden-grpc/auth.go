@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// serverOptions returns the options of the gRPC server listening on the address,
+// serving TLS with the certificate and key files when given, only accepting the
+// clients with a certificate signed by the authorities of the client CA file when it
+// is given, and only accepting the calls carrying the token when it is not empty. The
+// gateway moves the funds of the node, so listening on anything but a loopback
+// address requires the clients to authenticate, with the token or a certificate, TLS
+// alone only encrypting the calls of anyone.
+func serverOptions(listen, certFile, keyFile, clientCAFile, token string) ([]grpc.ServerOption, error) {
+	var options = make([]grpc.ServerOption, 0, 3)
+
+	if (certFile == "") != (keyFile == "") {
+		return nil, errors.New("-tls-cert and -tls-key must be given together")
+	}
+
+	if clientCAFile != "" && certFile == "" {
+		return nil, errors.New("-tls-client-ca requires -tls-cert and -tls-key")
+	}
+
+	if clientCAFile == "" && token == "" && !loopback(listen) {
+		return nil, fmt.Errorf("refusing to listen on %s without $RAIDEN_GRPC_TOKEN or -tls-client-ca, listen on a loopback address such as 127.0.0.1:50051 instead", listen)
+	}
+
+	if certFile != "" {
+		creds, err := serverCredentials(certFile, keyFile, clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, grpc.Creds(creds))
+	}
+
+	if token != "" {
+		options = append(options, tokenAuth(token)...)
+	}
+
+	return options, nil
+}
+
+// serverCredentials returns the TLS credentials of the certificate and key files,
+// requiring a client certificate signed by the authorities of the client CA file when
+// it is given.
+func serverCredentials(certFile, keyFile, clientCAFile string) (credentials.TransportCredentials, error) {
+	if clientCAFile == "" {
+		return credentials.NewServerTLSFromFile(certFile, keyFile)
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := ioutil.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	authorities := x509.NewCertPool()
+	if !authorities.AppendCertsFromPEM(contents) {
+		return nil, fmt.Errorf("no certificate in %s", clientCAFile)
+	}
+
+	return credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{certificate},
+		ClientCAs:    authorities,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	}), nil
+}
+
+// loopback reports whether the listen address only accepts connections from the
+// local host. An address without a host listens on every interface.
+func loopback(listen string) bool {
+	host, _, err := net.SplitHostPort(listen)
+	if err != nil {
+		return false
+	}
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+
+	return ip != nil && ip.IsLoopback()
+}
+
+// tokenAuth returns the interceptors refusing the calls whose "authorization"
+// metadata is not the bearer token.
+func tokenAuth(token string) []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, request interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := authorize(ctx, token); err != nil {
+				return nil, err
+			}
+
+			return handler(ctx, request)
+		}),
+		grpc.StreamInterceptor(func(server interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := authorize(stream.Context(), token); err != nil {
+				return err
+			}
+
+			return handler(server, stream)
+		}),
+	}
+}
+
+func authorize(ctx context.Context, token string) error {
+	var given string
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			given = strings.TrimPrefix(values[0], "Bearer ")
+		}
+	}
+
+	if given == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+
+	return nil
+}
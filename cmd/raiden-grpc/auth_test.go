@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/cmd/raiden-grpc/raidenpb"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// writeCertificate writes a self-signed certificate and its key as PEM files in dir.
+func writeCertificate(t *testing.T, dir string) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "raiden-grpc"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	require.NoError(t, ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600))
+
+	return certFile, keyFile
+}
+
+func TestServerOptions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raiden-grpc")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	certFile, keyFile := writeCertificate(t, dir)
+
+	type testcase struct {
+		name          string
+		listen        string
+		certFile      string
+		keyFile       string
+		clientCAFile  string
+		token         string
+		expectedError bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:          "loopback",
+			listen:        "127.0.0.1:50051",
+			expectedError: false,
+		},
+		testcase{
+			name:          "localhost",
+			listen:        "localhost:50051",
+			expectedError: false,
+		},
+		testcase{
+			name:          "ipv6 loopback",
+			listen:        "[::1]:50051",
+			expectedError: false,
+		},
+		testcase{
+			name:          "every interface",
+			listen:        ":50051",
+			expectedError: true,
+		},
+		testcase{
+			name:          "public address",
+			listen:        "10.0.0.7:50051",
+			expectedError: true,
+		},
+		testcase{
+			name:          "public address with a token",
+			listen:        "10.0.0.7:50051",
+			token:         "secret",
+			expectedError: false,
+		},
+		testcase{
+			name:          "public address with TLS alone",
+			listen:        "10.0.0.7:50051",
+			certFile:      certFile,
+			keyFile:       keyFile,
+			expectedError: true,
+		},
+		testcase{
+			name:          "public address with TLS and a token",
+			listen:        "10.0.0.7:50051",
+			certFile:      certFile,
+			keyFile:       keyFile,
+			token:         "secret",
+			expectedError: false,
+		},
+		testcase{
+			name:          "public address with client certificates",
+			listen:        ":50051",
+			certFile:      certFile,
+			keyFile:       keyFile,
+			clientCAFile:  certFile,
+			expectedError: false,
+		},
+		testcase{
+			name:          "client certificates without TLS",
+			listen:        ":50051",
+			clientCAFile:  certFile,
+			expectedError: true,
+		},
+		testcase{
+			name:          "client certificate authorities file holding no certificate",
+			listen:        ":50051",
+			certFile:      certFile,
+			keyFile:       keyFile,
+			clientCAFile:  keyFile,
+			expectedError: true,
+		},
+		testcase{
+			name:          "certificate without key",
+			listen:        "127.0.0.1:50051",
+			certFile:      "cert.pem",
+			expectedError: true,
+		},
+		testcase{
+			name:          "missing certificate",
+			listen:        ":50051",
+			certFile:      "missing-cert.pem",
+			keyFile:       "missing-key.pem",
+			token:         "secret",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := serverOptions(tc.listen, tc.certFile, tc.keyFile, tc.clientCAFile, tc.token)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestTokenAuth(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var tokenClient = raidenpb.NewTokenServiceClient(dialGateway(t, tokenAuth("secret")...))
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/tokens", httpmock.NewStringResponder(http.StatusOK, `["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"]`))
+
+	type testcase struct {
+		name         string
+		metadata     metadata.MD
+		expectedCode codes.Code
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "valid token",
+			metadata:     metadata.Pairs("authorization", "Bearer secret"),
+			expectedCode: codes.OK,
+		},
+		testcase{
+			name:         "invalid token",
+			metadata:     metadata.Pairs("authorization", "Bearer guess"),
+			expectedCode: codes.Unauthenticated,
+		},
+		testcase{
+			name:         "missing token",
+			metadata:     metadata.MD{},
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := metadata.NewOutgoingContext(context.Background(), tc.metadata)
+
+			_, err := tokenClient.ListTokens(ctx, &raidenpb.ListTokensRequest{})
+			require.Equal(t, tc.expectedCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+// Command raiden-grpc is a gRPC gateway to a Raiden node, exposing its channels,
+// payments and tokens as the services of raidenpb/raiden.proto, so that services in
+// any language with gRPC support can use the node without a REST client of their own.
+//
+// Usage:
+//
+//	raiden-grpc [-listen 127.0.0.1:50051] [-host http://localhost:5001] [-api-version v1] [-tls-cert cert.pem -tls-key key.pem [-tls-client-ca ca.pem]]
+//
+// The gateway listens on the loopback interface by default. Listening on any other
+// address requires the clients to authenticate, with a bearer token in
+// $RAIDEN_GRPC_TOKEN which every call must then carry in its "authorization" metadata,
+// or with a TLS certificate signed by the authorities of -tls-client-ca.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/config"
+	"google.golang.org/grpc"
+)
+
+const (
+	defaultListen     = "127.0.0.1:50051"
+	defaultHost       = "http://localhost:5001"
+	defaultAPIVersion = "v1"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run parses the flags and serves the gateway until interrupted, returning the exit
+// code of the process.
+func run(args []string, stderr io.Writer) int {
+	var (
+		err        error
+		listener   net.Listener
+		options    []grpc.ServerOption
+		flags      = flag.NewFlagSet("raiden-grpc", flag.ContinueOnError)
+		listen     = flags.String("listen", defaultListen, "address the gRPC server listens on")
+		host       = flags.String("host", "", "address of the Raiden node API (default "+defaultHost+", or $RAIDEN_HOST)")
+		apiVersion = flags.String("api-version", defaultAPIVersion, "version of the Raiden node API")
+		certFile   = flags.String("tls-cert", "", "certificate file to serve TLS with")
+		keyFile    = flags.String("tls-key", "", "private key file of the TLS certificate")
+		clientCA   = flags.String("tls-client-ca", "", "certificate authorities file the TLS certificates of the clients must be signed by")
+	)
+
+	flags.SetOutput(stderr)
+
+	if err = flags.Parse(args); err != nil {
+		return 2
+	}
+
+	nodeConfig := &config.Config{
+		Host:       firstOf(*host, os.Getenv("RAIDEN_HOST"), defaultHost),
+		APIVersion: *apiVersion,
+	}
+
+	if options, err = serverOptions(*listen, *certFile, *keyFile, *clientCA, os.Getenv("RAIDEN_GRPC_TOKEN")); err != nil {
+		fmt.Fprintln(stderr, "raiden-grpc:", err.Error())
+		return 2
+	}
+
+	if listener, err = net.Listen("tcp", *listen); err != nil {
+		fmt.Fprintln(stderr, "raiden-grpc:", err.Error())
+		return 1
+	}
+
+	server := grpc.NewServer(options...)
+	register(server, raidenclient.NewClient(nodeConfig, http.DefaultClient))
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	go func() {
+		<-interrupts
+		server.GracefulStop()
+	}()
+
+	fmt.Fprintf(stderr, "raiden-grpc: serving %s on %s\n", nodeConfig.Host, listener.Addr())
+
+	if err = server.Serve(listener); err != nil {
+		fmt.Fprintln(stderr, "raiden-grpc:", err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,5 @@
+// Package raidenpb holds the protocol buffers and gRPC services of the Raiden
+// gateway, generated from raiden.proto.
+package raidenpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative raiden.proto
@@ -0,0 +1,372 @@
+package main
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/cmd/raiden-grpc/raidenpb"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// register registers the services of the gateway, backed by the client, with the
+// server.
+func register(server *grpc.Server, client *raidenclient.Client) {
+	raidenpb.RegisterChannelServiceServer(server, &channelServer{client: client})
+	raidenpb.RegisterPaymentServiceServer(server, &paymentServer{client: client})
+	raidenpb.RegisterTokenServiceServer(server, &tokenServer{client: client})
+}
+
+type channelServer struct {
+	raidenpb.UnimplementedChannelServiceServer
+	client *raidenclient.Client
+}
+
+func (server *channelServer) ListChannels(ctx context.Context, request *raidenpb.ListChannelsRequest) (*raidenpb.ListChannelsResponse, error) {
+	var (
+		err          error
+		channelList  []*channels.Channel
+		tokenAddress common.Address
+	)
+
+	if request.TokenAddress == "" {
+		channelList, err = server.client.Channels().ListAll(ctx)
+	} else if tokenAddress, err = parseAddress("token_address", request.TokenAddress); err == nil {
+		channelList, err = server.client.Channels().ListToken(ctx, tokenAddress)
+	}
+
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &raidenpb.ListChannelsResponse{Channels: make([]*raidenpb.Channel, 0, len(channelList))}
+	for _, channel := range channelList {
+		response.Channels = append(response.Channels, toChannel(channel))
+	}
+
+	return response, nil
+}
+
+func (server *channelServer) OpenChannel(ctx context.Context, request *raidenpb.OpenChannelRequest) (*raidenpb.Channel, error) {
+	var (
+		err            error
+		channel        *channels.Channel
+		tokenAddress   common.Address
+		partnerAddress common.Address
+		deposit        *big.Int
+	)
+
+	if tokenAddress, err = parseAddress("token_address", request.TokenAddress); err != nil {
+		return nil, err
+	}
+
+	if partnerAddress, err = parseAddress("partner_address", request.PartnerAddress); err != nil {
+		return nil, err
+	}
+
+	if deposit, err = parseAmount("total_deposit", request.TotalDeposit); err != nil {
+		return nil, err
+	}
+
+	if channel, err = server.client.Channels().Open(ctx, tokenAddress, partnerAddress, deposit, request.SettleTimeout); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toChannel(channel), nil
+}
+
+func (server *channelServer) CloseChannel(ctx context.Context, request *raidenpb.CloseChannelRequest) (*raidenpb.Channel, error) {
+	var (
+		err            error
+		channel        *channels.Channel
+		tokenAddress   common.Address
+		partnerAddress common.Address
+	)
+
+	if tokenAddress, err = parseAddress("token_address", request.TokenAddress); err != nil {
+		return nil, err
+	}
+
+	if partnerAddress, err = parseAddress("partner_address", request.PartnerAddress); err != nil {
+		return nil, err
+	}
+
+	if channel, err = server.client.Channels().Close(ctx, tokenAddress, partnerAddress); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toChannel(channel), nil
+}
+
+func (server *channelServer) IncreaseDeposit(ctx context.Context, request *raidenpb.IncreaseDepositRequest) (*raidenpb.Channel, error) {
+	var (
+		err            error
+		channel        *channels.Channel
+		tokenAddress   common.Address
+		partnerAddress common.Address
+		deposit        *big.Int
+	)
+
+	if tokenAddress, err = parseAddress("token_address", request.TokenAddress); err != nil {
+		return nil, err
+	}
+
+	if partnerAddress, err = parseAddress("partner_address", request.PartnerAddress); err != nil {
+		return nil, err
+	}
+
+	if deposit, err = parseAmount("total_deposit", request.TotalDeposit); err != nil {
+		return nil, err
+	}
+
+	if channel, err = server.client.Channels().IncreaseDeposit(ctx, tokenAddress, partnerAddress, deposit); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return toChannel(channel), nil
+}
+
+type paymentServer struct {
+	raidenpb.UnimplementedPaymentServiceServer
+	client *raidenclient.Client
+}
+
+func (server *paymentServer) InitiatePayment(ctx context.Context, request *raidenpb.InitiatePaymentRequest) (*raidenpb.Payment, error) {
+	var (
+		err           error
+		payment       *payments.Payment
+		tokenAddress  common.Address
+		targetAddress common.Address
+		amount        *big.Int
+	)
+
+	if tokenAddress, err = parseAddress("token_address", request.TokenAddress); err != nil {
+		return nil, err
+	}
+
+	if targetAddress, err = parseAddress("target_address", request.TargetAddress); err != nil {
+		return nil, err
+	}
+
+	if amount, err = parseAmount("amount", request.Amount); err != nil {
+		return nil, err
+	}
+
+	if payment, err = server.client.Payments().InitiateWithIdentifier(ctx, tokenAddress, targetAddress, amount, request.Identifier); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &raidenpb.Payment{
+		InitiatorAddress: payment.InitiatorAddress.Hex(),
+		TargetAddress:    payment.TargetAddress.Hex(),
+		TokenAddress:     payment.TokenAddress.Hex(),
+		Amount:           formatAmount(payment.Amount),
+		Identifier:       payment.Identifier,
+	}, nil
+}
+
+func (server *paymentServer) ListPaymentEvents(ctx context.Context, request *raidenpb.ListPaymentEventsRequest) (*raidenpb.ListPaymentEventsResponse, error) {
+	var (
+		err           error
+		events        []*payments.Event
+		tokenAddress  common.Address
+		targetAddress common.Address
+	)
+
+	if tokenAddress, err = parseAddress("token_address", request.TokenAddress); err != nil {
+		return nil, err
+	}
+
+	if targetAddress, err = parseAddress("target_address", request.TargetAddress); err != nil {
+		return nil, err
+	}
+
+	if events, err = server.client.Payments().List(ctx, tokenAddress, targetAddress); err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &raidenpb.ListPaymentEventsResponse{Events: make([]*raidenpb.PaymentEvent, 0, len(events))}
+	for _, event := range events {
+		paymentEvent := &raidenpb.PaymentEvent{
+			Event:      event.EventName,
+			Amount:     formatAmount(event.Amount),
+			Identifier: event.Identifier,
+		}
+
+		if event.Initiator != (common.Address{}) {
+			paymentEvent.Initiator = event.Initiator.Hex()
+		}
+
+		if event.Target != (common.Address{}) {
+			paymentEvent.Target = event.Target.Hex()
+		}
+
+		if !event.LogTime.IsZero() {
+			paymentEvent.LogTime = timestamppb.New(event.LogTime)
+		}
+
+		response.Events = append(response.Events, paymentEvent)
+	}
+
+	return response, nil
+}
+
+type tokenServer struct {
+	raidenpb.UnimplementedTokenServiceServer
+	client *raidenclient.Client
+}
+
+func (server *tokenServer) ListTokens(ctx context.Context, request *raidenpb.ListTokensRequest) (*raidenpb.ListTokensResponse, error) {
+	tokenList, err := server.client.Tokens().List(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &raidenpb.ListTokensResponse{TokenAddresses: make([]string, 0, len(tokenList))}
+	for _, token := range tokenList {
+		response.TokenAddresses = append(response.TokenAddresses, token.Hex())
+	}
+
+	return response, nil
+}
+
+func (server *tokenServer) GetTokenNetwork(ctx context.Context, request *raidenpb.GetTokenNetworkRequest) (*raidenpb.TokenNetwork, error) {
+	var (
+		err            error
+		tokenAddress   common.Address
+		networkAddress address.TokenNetworkAddress
+	)
+
+	if tokenAddress, err = parseAddress("token_address", request.TokenAddress); err != nil {
+		return nil, err
+	}
+
+	if networkAddress, err = server.client.Tokens().Get(ctx, tokenAddress); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &raidenpb.TokenNetwork{TokenAddress: tokenAddress.Hex(), TokenNetworkAddress: networkAddress.Hex()}, nil
+}
+
+func (server *tokenServer) RegisterToken(ctx context.Context, request *raidenpb.RegisterTokenRequest) (*raidenpb.TokenNetwork, error) {
+	var (
+		err            error
+		tokenAddress   common.Address
+		networkAddress address.TokenNetworkAddress
+	)
+
+	if tokenAddress, err = parseAddress("token_address", request.TokenAddress); err != nil {
+		return nil, err
+	}
+
+	if networkAddress, err = server.client.Tokens().Register(ctx, tokenAddress); err != nil {
+		return nil, toStatus(err)
+	}
+
+	return &raidenpb.TokenNetwork{TokenAddress: tokenAddress.Hex(), TokenNetworkAddress: networkAddress.Hex()}, nil
+}
+
+func (server *tokenServer) ListPartners(ctx context.Context, request *raidenpb.ListPartnersRequest) (*raidenpb.ListPartnersResponse, error) {
+	tokenAddress, err := parseAddress("token_address", request.TokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	partners, err := server.client.Tokens().ListPartners(ctx, tokenAddress)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+
+	response := &raidenpb.ListPartnersResponse{Partners: make([]*raidenpb.Partner, 0, len(partners))}
+	for _, partner := range partners {
+		response.Partners = append(response.Partners, &raidenpb.Partner{
+			PartnerAddress: partner.Address.Hex(),
+			Channel:        partner.ChannelURI,
+		})
+	}
+
+	return response, nil
+}
+
+func toChannel(channel *channels.Channel) *raidenpb.Channel {
+	return &raidenpb.Channel{
+		TokenNetworkIdentifier: channel.TokenNetworkIdentifier.Hex(),
+		ChannelIdentifier:      channel.ChannelIdentifier,
+		PartnerAddress:         channel.PartnerAddress.Hex(),
+		TokenAddress:           channel.TokenAddress.Hex(),
+		Balance:                formatAmount(channel.Balance),
+		TotalDeposit:           formatAmount(channel.TotalDeposit),
+		State:                  channel.State,
+		SettleTimeout:          channel.SettleTimeout,
+		RevealTimeout:          channel.RevealTimeout,
+	}
+}
+
+// parseAddress parses the hex address of a field of a request, a malformed address
+// being an InvalidArgument error.
+func parseAddress(field, value string) (common.Address, error) {
+	if !common.IsHexAddress(value) {
+		return common.Address{}, status.Errorf(codes.InvalidArgument, "%s: invalid address %q", field, value)
+	}
+
+	return common.HexToAddress(value), nil
+}
+
+// parseAmount parses the decimal amount of a field of a request, a malformed or
+// negative amount being an InvalidArgument error.
+func parseAmount(field, value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "%s: invalid amount %q", field, value)
+	}
+
+	return amount, nil
+}
+
+func formatAmount(amount *big.Int) string {
+	if amount == nil {
+		return "0"
+	}
+
+	return amount.String()
+}
+
+// toStatus converts an error of the client into a gRPC status, by the status code
+// of the response of the node, unreachable nodes being Unavailable.
+func toStatus(err error) error {
+	var code = codes.Unknown
+
+	switch raidenerrors.StatusCode(err) {
+	case 0:
+		if raidenerrors.IsRetryable(err) {
+			code = codes.Unavailable
+		}
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusPaymentRequired, http.StatusConflict:
+		code = codes.FailedPrecondition
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+		code = codes.DeadlineExceeded
+	case http.StatusNotImplemented:
+		code = codes.Unimplemented
+	default:
+		if raidenerrors.IsRetryable(err) {
+			code = codes.Unavailable
+		} else {
+			code = codes.Internal
+		}
+	}
+
+	return status.Error(code, err.Error())
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/cmd/raiden-grpc/raidenpb"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialGateway serves the gateway with the options, backed by a client of the mocked
+// node, over an in-memory connection.
+func dialGateway(t *testing.T, options ...grpc.ServerOption) *grpc.ClientConn {
+	var (
+		listener = bufconn.Listen(1 << 20)
+		server   = grpc.NewServer(options...)
+		client   = raidenclient.NewClient(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient)
+	)
+
+	register(server, client)
+
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func TestChannelService(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var (
+		ctx           = context.Background()
+		channelClient = raidenpb.NewChannelServiceClient(dialGateway(t))
+		channelJSON   = `{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000000000000000000,"total_deposit":35000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}`
+		expected      = &raidenpb.Channel{
+			TokenNetworkIdentifier: "0xE5637F0103794C7e05469A9964E4563089a5E6f2",
+			ChannelIdentifier:      20,
+			PartnerAddress:         "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			TokenAddress:           "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			Balance:                "25000000000000000000000",
+			TotalDeposit:           "35000000",
+			State:                  "opened",
+			SettleTimeout:          500,
+			RevealTimeout:          30,
+		}
+	)
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, "["+channelJSON+"]"))
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusCreated, channelJSON))
+	httpmock.RegisterResponder("PATCH", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Channel is not in an open state"}`))
+
+	t.Run("list channels", func(t *testing.T) {
+		response, err := channelClient.ListChannels(ctx, &raidenpb.ListChannelsRequest{})
+		require.NoError(t, err)
+
+		require.Len(t, response.Channels, 1)
+		assert.Equal(t, expected.String(), response.Channels[0].String())
+	})
+
+	t.Run("open channel", func(t *testing.T) {
+		channel, err := channelClient.OpenChannel(ctx, &raidenpb.OpenChannelRequest{
+			TokenAddress:   "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			PartnerAddress: "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			TotalDeposit:   "35000000",
+			SettleTimeout:  500,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, expected.String(), channel.String())
+	})
+
+	t.Run("invalid address", func(t *testing.T) {
+		_, err := channelClient.CloseChannel(ctx, &raidenpb.CloseChannelRequest{
+			TokenAddress:   "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			PartnerAddress: "not an address",
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("invalid amount", func(t *testing.T) {
+		_, err := channelClient.IncreaseDeposit(ctx, &raidenpb.IncreaseDepositRequest{
+			TokenAddress:   "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			PartnerAddress: "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			TotalDeposit:   "-1",
+		})
+
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("error of the node", func(t *testing.T) {
+		_, err := channelClient.IncreaseDeposit(ctx, &raidenpb.IncreaseDepositRequest{
+			TokenAddress:   "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			PartnerAddress: "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			TotalDeposit:   "50000000",
+		})
+
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+		assert.Contains(t, status.Convert(err).Message(), "Channel is not in an open state")
+	})
+}
+
+func TestPaymentService(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var (
+		ctx           = context.Background()
+		paymentClient = raidenpb.NewPaymentServiceClient(dialGateway(t))
+	)
+
+	httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		httpmock.NewStringResponder(http.StatusOK, `{"initiator_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","amount":10,"identifier":42}`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		httpmock.NewStringResponder(http.StatusOK, `[{"event":"EventPaymentSentSuccess","amount":10,"target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","identifier":42,"log_time":"2018-10-30T07:03:52.193"}]`))
+
+	t.Run("initiate payment", func(t *testing.T) {
+		payment, err := paymentClient.InitiatePayment(ctx, &raidenpb.InitiatePaymentRequest{
+			TokenAddress:  "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			TargetAddress: "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			Amount:        "10",
+			Identifier:    42,
+		})
+		require.NoError(t, err)
+
+		assert.Equal(t, "0x2a65Aca4D5fC5B5C859090a6c34d164135398226", payment.InitiatorAddress)
+		assert.Equal(t, "10", payment.Amount)
+		assert.Equal(t, int64(42), payment.Identifier)
+	})
+
+	t.Run("list payment events", func(t *testing.T) {
+		response, err := paymentClient.ListPaymentEvents(ctx, &raidenpb.ListPaymentEventsRequest{
+			TokenAddress:  "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			TargetAddress: "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		})
+		require.NoError(t, err)
+
+		require.Len(t, response.Events, 1)
+		assert.Equal(t, "EventPaymentSentSuccess", response.Events[0].Event)
+		assert.Equal(t, "0x61C808D82A3Ac53231750daDc13c777b59310bD9", response.Events[0].Target)
+		assert.Empty(t, response.Events[0].Initiator)
+		assert.Equal(t, int64(1540883032), response.Events[0].LogTime.GetSeconds())
+	})
+}
+
+func TestTokenService(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var (
+		ctx         = context.Background()
+		tokenClient = raidenpb.NewTokenServiceClient(dialGateway(t))
+	)
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/tokens", httpmock.NewStringResponder(http.StatusOK, `["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"]`))
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/tokens/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+		httpmock.NewStringResponder(http.StatusCreated, `{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2"}`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/tokens/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/partners", httpmock.NewStringResponder(http.StatusServiceUnavailable, ``))
+
+	t.Run("list tokens", func(t *testing.T) {
+		response, err := tokenClient.ListTokens(ctx, &raidenpb.ListTokensRequest{})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"}, response.TokenAddresses)
+	})
+
+	t.Run("register token", func(t *testing.T) {
+		network, err := tokenClient.RegisterToken(ctx, &raidenpb.RegisterTokenRequest{TokenAddress: "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"})
+		require.NoError(t, err)
+
+		assert.Equal(t, "0xE5637F0103794C7e05469A9964E4563089a5E6f2", network.TokenNetworkAddress)
+	})
+
+	t.Run("unavailable node", func(t *testing.T) {
+		_, err := tokenClient.ListPartners(ctx, &raidenpb.ListPartnersRequest{TokenAddress: "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"})
+
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
@@ -0,0 +1,57 @@
+// Command raiden-mock serves a fake Raiden node answering with canned but consistent
+// responses, so that the examples of the client and demos built on it run without a
+// real node. The node starts with the channel of the fixtures and keeps the changes
+// made by the requests in memory, e.g. a payment lowers the balance of the channel it
+// is made over.
+//
+// Usage:
+//
+//	raiden-mock [-listen :5001] [-address 0x2a65...]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/cpurta/go-raiden-client/raidentest/fixtures"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const defaultListen = ":5001"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run parses the flags and serves the fake node, returning the exit code of the
+// process.
+func run(args []string, stderr io.Writer) int {
+	var (
+		flags   = flag.NewFlagSet("raiden-mock", flag.ContinueOnError)
+		listen  = flags.String("listen", defaultListen, "address the fake node listens on")
+		address = flags.String("address", fixtures.OurAddress.Hex(), "address of the fake node")
+	)
+
+	flags.SetOutput(stderr)
+
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if !common.IsHexAddress(*address) {
+		fmt.Fprintln(stderr, "raiden-mock: invalid -address", *address)
+		return 2
+	}
+
+	fmt.Fprintf(stderr, "raiden-mock: serving %s on %s\n", common.HexToAddress(*address).Hex(), *listen)
+
+	if err := http.ListenAndServe(*listen, newMock(common.HexToAddress(*address))); err != nil {
+		fmt.Fprintln(stderr, "raiden-mock:", err.Error())
+		return 1
+	}
+
+	return 0
+}
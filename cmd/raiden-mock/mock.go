@@ -0,0 +1,28 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/raidentest"
+	"github.com/cpurta/go-raiden-client/raidentest/fixtures"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// newMock creates the handler of a fake node with the given address, holding the
+// token and channel of the fixtures. The node is a raidentest.Server, so that every
+// endpoint tells the same story.
+func newMock(address common.Address) http.Handler {
+	var node = raidentest.NewUnstartedServer(address)
+
+	// the handler of the node is served by the command, not on the listener of the
+	// test server
+	node.Listener.Close()
+
+	node.AddToken(fixtures.TokenAddress, fixtures.TokenNetworkAddress)
+
+	for _, channel := range fixtures.Channels() {
+		node.AddChannel(channel)
+	}
+
+	return node.Handler()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/raidentest/fixtures"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMock(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		server     = httptest.NewServer(newMock(fixtures.OurAddress))
+		client     = raidenclient.NewClient(&config.Config{Host: server.URL, APIVersion: "v1"}, http.DefaultClient)
+		newToken   = common.HexToAddress("0x0f114A1E9Db192502E7856309cc899952b3db1ED")
+		newPartner = common.HexToAddress("0x5A5f458F6c1a034930E45dC9a64B99d7def06D7E")
+	)
+
+	defer server.Close()
+
+	ourAddress, err := client.Address().Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, fixtures.OurAddress, ourAddress)
+
+	// the node starts with the channel of the fixtures
+	channelList, err := client.Channels().ListToken(ctx, fixtures.TokenAddress)
+	require.NoError(t, err)
+	require.Len(t, channelList, 1)
+	assert.Equal(t, fixtures.Channel().Balance, channelList[0].Balance)
+
+	network, err := client.Tokens().Get(ctx, fixtures.TokenAddress)
+	require.NoError(t, err)
+	assert.Equal(t, address.TokenNetworkAddress(fixtures.TokenNetworkAddress), network)
+
+	_, err = client.Channels().Open(ctx, newToken, newPartner, big.NewInt(100), 500)
+	assert.Equal(t, http.StatusConflict, raidenerrors.StatusCode(err))
+
+	_, err = client.Tokens().Register(ctx, newToken)
+	require.NoError(t, err)
+
+	tokenList, err := client.Tokens().List(ctx)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []common.Address{fixtures.TokenAddress, newToken}, tokenList)
+
+	channel, err := client.Channels().Open(ctx, newToken, newPartner, big.NewInt(100), 500)
+	require.NoError(t, err)
+	assert.Equal(t, "opened", channel.State)
+	assert.Equal(t, big.NewInt(100), channel.Balance)
+
+	partners, err := client.Tokens().ListPartners(ctx, newToken)
+	require.NoError(t, err)
+	require.Len(t, partners, 1)
+	assert.Equal(t, newPartner, partners[0].Address)
+
+	_, err = client.Payments().Initiate(ctx, newToken, newPartner, big.NewInt(40))
+	require.NoError(t, err)
+
+	channelList, err = client.Channels().ListToken(ctx, newToken)
+	require.NoError(t, err)
+	require.Len(t, channelList, 1)
+	assert.Equal(t, big.NewInt(60), channelList[0].Balance)
+
+	transfers, err := client.PendingTransfers().ListAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, transfers)
+
+	require.NoError(t, client.Connections().Join(ctx, newToken, big.NewInt(500)))
+
+	connections, err := client.Connections().List(ctx)
+	require.NoError(t, err)
+	require.Contains(t, connections, newToken)
+	assert.Equal(t, big.NewInt(500), connections[newToken].Funds)
+	assert.Equal(t, big.NewInt(100), connections[newToken].SumDeposits)
+	assert.Equal(t, int64(1), connections[newToken].Channels)
+
+	closed, err := client.Connections().Leave(ctx, newToken)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{newPartner}, closed)
+
+	channelList, err = client.Channels().ListToken(ctx, newToken)
+	require.NoError(t, err)
+	require.Len(t, channelList, 1)
+	assert.Equal(t, "closed", channelList[0].State)
+}
+
+func TestRun(t *testing.T) {
+	var stderr = &bytes.Buffer{}
+
+	assert.Equal(t, 2, run([]string{"-address", "not-an-address"}, stderr))
+	assert.Contains(t, stderr.String(), "invalid -address")
+}
@@ -0,0 +1,87 @@
+// Command raiden-proxy serves the API of a Raiden node to several services, each with
+// an API key whose requests are authorized by the policies of the key, so that one
+// node can be safely shared within an organization.
+//
+// Usage:
+//
+//	raiden-proxy -keys keys.json [-listen :5002] [-host http://localhost:5001]
+//
+// The keys file maps every API key to its policies, see proxy.LoadKeys:
+//
+//	{
+//	  "3b1f0c...": {"name": "dashboard", "read_only": true},
+//	  "9a77e2...": {"name": "billing", "allowed_tokens": ["0x6B17..."], "spend_limit": 1000000000000000000000}
+//	}
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/proxy"
+)
+
+const (
+	defaultListen = ":5002"
+	defaultHost   = "http://localhost:5001"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run parses the flags and serves the proxy, returning the exit code of the process.
+func run(args []string, stderr io.Writer) int {
+	var (
+		err      error
+		keys     map[string]*proxy.Key
+		flags    = flag.NewFlagSet("raiden-proxy", flag.ContinueOnError)
+		listen   = flags.String("listen", defaultListen, "address the proxy listens on")
+		host     = flags.String("host", "", "address of the Raiden node API (default "+defaultHost+", or $RAIDEN_HOST)")
+		keysPath = flags.String("keys", "", "path of the JSON file holding the API keys and their policies")
+	)
+
+	flags.SetOutput(stderr)
+
+	if err = flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if *keysPath == "" {
+		fmt.Fprintln(stderr, "raiden-proxy: -keys is required")
+		return 2
+	}
+
+	if keys, err = proxy.LoadKeys(*keysPath); err != nil {
+		fmt.Fprintln(stderr, "raiden-proxy:", err.Error())
+		return 1
+	}
+
+	nodeConfig := &config.Config{
+		Host:       firstOf(*host, os.Getenv("RAIDEN_HOST"), defaultHost),
+		APIVersion: "v1",
+	}
+
+	fmt.Fprintf(stderr, "raiden-proxy: serving %s on %s for %d keys\n", nodeConfig.Host, *listen, len(keys))
+
+	if err = http.ListenAndServe(*listen, proxy.New(nodeConfig, http.DefaultClient, keys)); err != nil {
+		fmt.Fprintln(stderr, "raiden-proxy:", err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"context"
+
+	"github.com/cpurta/go-raiden-client/channels"
+)
+
+var channelsCommand = &command{
+	name: "channels",
+	subcommands: []*command{
+		&command{
+			name:    "list",
+			usage:   "channels list [token]",
+			summary: "list all channels, or the channels of a token",
+			run:     channelsList,
+		},
+		&command{
+			name:    "open",
+			usage:   "channels open [-settle-timeout blocks] <token> <partner> <deposit>",
+			summary: "open a channel with a partner",
+			run:     channelsOpen,
+		},
+		&command{
+			name:    "deposit",
+			usage:   "channels deposit <token> <partner> <total deposit>",
+			summary: "increase the total deposit of a channel",
+			run:     channelsDeposit,
+		},
+		&command{
+			name:    "close",
+			usage:   "channels close <token> <partner>",
+			summary: "close a channel with a partner",
+			run:     channelsClose,
+		},
+		&command{
+			name:    "label",
+			usage:   "channels label [-note text] [-delete] <token> <partner> [name]",
+			summary: "label a channel, e.g. with the name of the partner, kept by raidenctl",
+			run:     channelsLabel,
+		},
+	},
+}
+
+var channelColumns = []column{
+	{"ID", "channel_identifier"},
+	{"TOKEN", "token_address"},
+	{"PARTNER", "partner_address"},
+	{"STATE", "state"},
+	{"BALANCE", "balance"},
+	{"TOTAL DEPOSIT", "total_deposit"},
+	{"SETTLE TIMEOUT", "settle_timeout"},
+	{"LABEL", "label"},
+}
+
+func channelRows(app *app, channelList ...*channels.Channel) [][]interface{} {
+	rows := make([][]interface{}, 0, len(channelList))
+
+	for _, channel := range channelList {
+		rows = append(rows, []interface{}{
+			channel.ChannelIdentifier,
+			channel.TokenAddress,
+			channel.PartnerAddress,
+			channel.State,
+			app.amount(channel.TokenAddress, channel.Balance),
+			app.amount(channel.TokenAddress, channel.TotalDeposit),
+			channel.SettleTimeout,
+			app.label(channel),
+		})
+	}
+
+	return rows
+}
+
+func channelsList(ctx context.Context, app *app, args []string) error {
+	var channelList []*channels.Channel
+
+	args, err := parseArgs(app.flags("list"), args, 0, 1)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := parseAddresses([]string{"token"}, args)
+	if err != nil {
+		return err
+	}
+
+	if len(addresses) == 0 {
+		channelList, err = app.client.Channels().ListAll(ctx)
+	} else {
+		channelList, err = app.client.Channels().ListToken(ctx, addresses[0])
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return app.print(channelColumns, channelRows(app, channelList...))
+}
+
+func channelsOpen(ctx context.Context, app *app, args []string) error {
+	var (
+		flags         = app.flags("open")
+		settleTimeout = flags.Int64("settle-timeout", 500, "number of blocks before a closed channel can be settled")
+	)
+
+	args, err := parseArgs(flags, args, 3, 3)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	partner, err := parseAddress("partner", args[1])
+	if err != nil {
+		return err
+	}
+
+	deposit, err := parseAmount("deposit", args[2])
+	if err != nil {
+		return err
+	}
+
+	if err = checkAllowed(app, token, partner); err != nil {
+		return err
+	}
+
+	channel, err := app.client.Channels().Open(ctx, token, partner, deposit, *settleTimeout)
+	if err != nil {
+		return err
+	}
+
+	return app.printOne(channelColumns, channelRows(app, channel)[0])
+}
+
+func channelsDeposit(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("deposit"), args, 3, 3)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	partner, err := parseAddress("partner", args[1])
+	if err != nil {
+		return err
+	}
+
+	deposit, err := parseAmount("total deposit", args[2])
+	if err != nil {
+		return err
+	}
+
+	if err = checkAllowed(app, token, partner); err != nil {
+		return err
+	}
+
+	channel, err := app.client.Channels().IncreaseDeposit(ctx, token, partner, deposit)
+	if err != nil {
+		return err
+	}
+
+	return app.printOne(channelColumns, channelRows(app, channel)[0])
+}
+
+func channelsClose(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("close"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	partner, err := parseAddress("partner", args[1])
+	if err != nil {
+		return err
+	}
+
+	channel, err := app.client.Channels().Close(ctx, token, partner)
+	if err != nil {
+		return err
+	}
+
+	return app.printOne(channelColumns, channelRows(app, channel)[0])
+}
+
+func channelsLabel(ctx context.Context, app *app, args []string) error {
+	var (
+		flags  = app.flags("label")
+		note   = flags.String("note", "", "note kept along with the name of the label")
+		remove = flags.Bool("delete", false, "remove the label of the channel")
+		label  *channels.Label
+	)
+
+	args, err := parseArgs(flags, args, 2, 3)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := parseAddresses([]string{"token", "partner"}, args[:2])
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case *remove:
+	case len(args) == 3:
+		label = &channels.Label{Name: args[2], Note: *note}
+	default:
+		return &usageError{message: "a label needs a name, or -delete to remove it"}
+	}
+
+	if err = app.labels.Set(addresses[0], addresses[1], label); err != nil {
+		return err
+	}
+
+	if label == nil {
+		label = &channels.Label{}
+	}
+
+	return app.printOne(labelColumns, []interface{}{addresses[0], addresses[1], label.Name, label.Note})
+}
+
+var labelColumns = []column{
+	{"TOKEN", "token_address"},
+	{"PARTNER", "partner_address"},
+	{"LABEL", "label"},
+	{"NOTE", "note"},
+}
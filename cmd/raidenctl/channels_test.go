@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChannelsLabel(t *testing.T) {
+	var (
+		tokenAddress   = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		partnerAddress = "0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		dir, err       = ioutil.TempDir("", "raidenctl")
+		configPath     string
+	)
+
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configPath = filepath.Join(dir, "raidenctl.json")
+
+	require.NoError(t, ioutil.WriteFile(configPath, []byte(`{"default_profile": "dev", "profiles": {"dev": {"host": "http://localhost:5001"}}}`), 0600))
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"partner_address":"`+partnerAddress+`","token_address":"`+tokenAddress+`","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`))
+
+	type testcase struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedStdout []string
+		missingStdout  []string
+	}
+
+	// the cases share the labels file next to the configuration file
+	testcases := []testcase{
+		testcase{
+			name:           "label a channel",
+			args:           []string{"channels", "label", "-note", "ask the exchange desk", tokenAddress, partnerAddress, "Binance hot wallet"},
+			expectedStdout: []string{"LABEL", "Binance hot wallet", "ask the exchange desk"},
+		},
+		testcase{
+			name:           "label listed",
+			args:           []string{"channels", "list"},
+			expectedStdout: []string{"LABEL", "Binance hot wallet"},
+		},
+		testcase{
+			name:         "label without a name",
+			args:         []string{"channels", "label", tokenAddress, partnerAddress},
+			expectedCode: 2,
+		},
+		testcase{
+			name:          "delete the label",
+			args:          []string{"channels", "label", "-delete", tokenAddress, partnerAddress},
+			missingStdout: []string{"Binance hot wallet"},
+		},
+		testcase{
+			name:          "label deleted",
+			args:          []string{"channels", "list"},
+			missingStdout: []string{"Binance hot wallet"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				stdout = &bytes.Buffer{}
+				stderr = &bytes.Buffer{}
+			)
+
+			code := run(append([]string{"-config", configPath}, tc.args...), nil, stdout, stderr)
+
+			assert.Equal(t, tc.expectedCode, code, stderr.String())
+
+			for _, expected := range tc.expectedStdout {
+				assert.Contains(t, stdout.String(), expected)
+			}
+
+			for _, missing := range tc.missingStdout {
+				assert.NotContains(t, stdout.String(), missing)
+			}
+		})
+	}
+
+	assert.FileExists(t, filepath.Join(dir, "raidenctl.labels.json"))
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// command is either a group of subcommands, such as "channels", or a runnable
+// command, such as "channels list". Streaming commands run until interrupted and
+// apply the timeout to each of their requests instead of the whole command, as do
+// interactive commands, which wait for the operator in between.
+type command struct {
+	name        string
+	usage       string
+	summary     string
+	streaming   bool
+	interactive bool
+	subcommands []*command
+	run         func(ctx context.Context, app *app, args []string) error
+}
+
+// usageError is returned by a command that was invoked with invalid arguments.
+type usageError struct {
+	usage   string
+	message string
+}
+
+func (err *usageError) Error() string {
+	return err.message
+}
+
+func dispatch(ctx context.Context, app *app, commands []*command, args []string) error {
+	if len(args) == 0 {
+		printCommands(app.stderr, commands)
+		return &usageError{usage: "<command>", message: "missing command"}
+	}
+
+	for _, command := range commands {
+		if command.name != args[0] {
+			continue
+		}
+
+		if command.run != nil {
+			if !command.streaming && !command.interactive {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, app.timeout)
+				defer cancel()
+			}
+
+			err := command.run(ctx, app, args[1:])
+
+			if usageErr, ok := err.(*usageError); ok && usageErr.usage == "" {
+				usageErr.usage = command.usage
+			}
+
+			return err
+		}
+
+		if len(args) == 1 {
+			printCommands(app.stderr, command.subcommands)
+			return &usageError{usage: command.name + " <subcommand>", message: "missing subcommand"}
+		}
+
+		return dispatch(ctx, app, command.subcommands, args[1:])
+	}
+
+	return &usageError{usage: "<command>", message: fmt.Sprintf("unknown command: %s", args[0])}
+}
+
+func printCommands(writer io.Writer, commands []*command) {
+	fmt.Fprintln(writer, "commands:")
+
+	for _, command := range commands {
+		if command.run != nil {
+			fmt.Fprintf(writer, "  %-50s %s\n", command.usage, command.summary)
+			continue
+		}
+
+		for _, subcommand := range command.subcommands {
+			fmt.Fprintf(writer, "  %-50s %s\n", subcommand.usage, subcommand.summary)
+		}
+	}
+}
+
+// parseArgs will parse the flags of a command and ensure that it received between min
+// and max positional arguments, a negative max allowing any number of them.
+func parseArgs(flags *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	flags.SetOutput(ioutil.Discard)
+
+	if err := flags.Parse(args); err != nil {
+		return nil, &usageError{message: err.Error()}
+	}
+
+	if flags.NArg() < min || (max >= 0 && flags.NArg() > max) {
+		return nil, &usageError{message: "wrong number of arguments"}
+	}
+
+	return flags.Args(), nil
+}
+
+func parseAddress(name, value string) (common.Address, error) {
+	if !common.IsHexAddress(value) {
+		return common.Address{}, fmt.Errorf("invalid %s address: %s", name, value)
+	}
+
+	return common.HexToAddress(value), nil
+}
+
+func parseAmount(name, value string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid %s: %s", name, value)
+	}
+
+	return amount, nil
+}
+
+// parseAddresses parses each argument as the address of the same position in names.
+func parseAddresses(names []string, args []string) ([]common.Address, error) {
+	addresses := make([]common.Address, 0, len(args))
+
+	for i, arg := range args {
+		address, err := parseAddress(names[i], arg)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses = append(addresses, address)
+	}
+
+	return addresses, nil
+}
+
+// checkAllowed refuses a token, and the partners or targets used with it, that are
+// not on the allowlists of the selected profile.
+func checkAllowed(app *app, tokenAddress common.Address, partnerAddresses ...common.Address) error {
+	if !app.profile.AllowsToken(tokenAddress) {
+		return fmt.Errorf("token %s is not allowed by profile %s", tokenAddress.Hex(), app.profile.Name)
+	}
+
+	for _, partnerAddress := range partnerAddresses {
+		if !app.profile.AllowsPartner(partnerAddress) {
+			return fmt.Errorf("partner %s is not allowed by profile %s", partnerAddress.Hex(), app.profile.Name)
+		}
+	}
+
+	return nil
+}
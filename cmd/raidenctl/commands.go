@@ -0,0 +1,18 @@
+package main
+
+// commands lists every command of raidenctl, grouped by the resource they manage.
+var commands = []*command{
+	nodeCommand,
+	tokensCommand,
+	channelsCommand,
+	paymentsCommand,
+	connectionsCommand,
+	pendingCommand,
+	watchCommand,
+	dashboardCommand,
+	payCommand,
+	smokeCommand,
+	supportCommand,
+	snapshotCommand,
+	verifyRecoveryCommand,
+}
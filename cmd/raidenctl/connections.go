@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var connectionsCommand = &command{
+	name: "connections",
+	subcommands: []*command{
+		&command{
+			name:    "list",
+			usage:   "connections list",
+			summary: "list the token networks the node is connected to",
+			run:     connectionsList,
+		},
+		&command{
+			name:    "join",
+			usage:   "connections join <token> <funds>",
+			summary: "join a token network, opening channels with the funds",
+			run:     connectionsJoin,
+		},
+		&command{
+			name:    "leave",
+			usage:   "connections leave <token>",
+			summary: "leave a token network, closing all of its channels",
+			run:     connectionsLeave,
+		},
+	},
+}
+
+var connectionColumns = []column{
+	{"TOKEN", "token_address"},
+	{"FUNDS", "funds"},
+	{"SUM DEPOSITS", "sum_deposits"},
+	{"CHANNELS", "channels"},
+}
+
+func connectionsList(ctx context.Context, app *app, args []string) error {
+	if _, err := parseArgs(app.flags("list"), args, 0, 0); err != nil {
+		return err
+	}
+
+	connections, err := app.client.Connections().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	tokens := make([]common.Address, 0, len(connections))
+	for token := range connections {
+		tokens = append(tokens, token)
+	}
+
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].Hex() < tokens[j].Hex() })
+
+	rows := make([][]interface{}, 0, len(connections))
+	for _, token := range tokens {
+		rows = append(rows, []interface{}{
+			token,
+			app.amount(token, connections[token].Funds),
+			app.amount(token, connections[token].SumDeposits),
+			connections[token].Channels,
+		})
+	}
+
+	return app.print(connectionColumns, rows)
+}
+
+func connectionsJoin(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("join"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	funds, err := parseAmount("funds", args[1])
+	if err != nil {
+		return err
+	}
+
+	if err = checkAllowed(app, token); err != nil {
+		return err
+	}
+
+	return app.client.Connections().Join(ctx, token, funds)
+}
+
+func connectionsLeave(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("leave"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	closed, err := app.client.Connections().Leave(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]interface{}, 0, len(closed))
+	for _, address := range closed {
+		rows = append(rows, []interface{}{address})
+	}
+
+	return app.print([]column{{"CLOSED CHANNEL", "channel_address"}}, rows)
+}
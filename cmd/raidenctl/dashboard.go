@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/ethereum/go-ethereum/common"
+	"golang.org/x/term"
+)
+
+var dashboardCommand = &command{
+	name:      "dashboard",
+	usage:     "dashboard [-interval d] [-feed n]",
+	summary:   "show a live dashboard of the node, its channels, pending transfers and payments",
+	streaming: true,
+	run:       dashboard,
+}
+
+var feedColumns = []column{
+	{"TIME", "log_time"},
+	{"EVENT", "event"},
+	{"TOKEN", "token_address"},
+	{"PARTNER", "partner_address"},
+	{"IDENTIFIER", "identifier"},
+	{"AMOUNT", "amount"},
+}
+
+// dashboardState is what the dashboard renders, refreshed at every interval while
+// payment events are added to the feed as soon as they are received.
+type dashboardState struct {
+	app       *app
+	mutex     sync.Mutex
+	address   common.Address
+	latency   time.Duration
+	err       error
+	refreshed time.Time
+	paused    bool
+	channels  []*channels.Channel
+	transfers []*pendingtransfers.Transfer
+	feed      []*events.PaymentEvent
+	feedSize  int
+}
+
+func dashboard(ctx context.Context, app *app, args []string) error {
+	var (
+		flags    = app.flags("dashboard")
+		interval = flags.Duration("interval", 2*time.Second, "time between two refreshes")
+		feedSize = flags.Int("feed", 10, "number of payment events kept in the feed")
+		count    = flags.Int("count", 0, "number of refreshes before exiting, 0 to keep refreshing until quit")
+		raw      bool
+	)
+
+	if _, err := parseArgs(flags, args, 0, 0); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		state        = &dashboardState{app: app, feedSize: *feedSize}
+		ticker       = time.NewTicker(*interval)
+		redraw       = make(chan struct{}, 1)
+		subscription = events.NewSubscriber(app.config, http.DefaultClient).SubscribePayments(ctx, &events.SubscribeOptions{Interval: *interval})
+	)
+
+	defer ticker.Stop()
+
+	if stdin, ok := interactive(app); ok {
+		oldState, err := term.MakeRaw(int(stdin.Fd()))
+		if err == nil {
+			raw = true
+			defer term.Restore(int(stdin.Fd()), oldState)
+
+			go readKeys(stdin, state, cancel, redraw)
+		}
+	}
+
+	feedDone := make(chan struct{})
+
+	go func() {
+		defer close(feedDone)
+
+		for event := range subscription.Events {
+			state.add(event)
+		}
+	}()
+
+	// the subscription must be done polling the node once the dashboard returns
+	defer func() {
+		cancel()
+		<-feedDone
+	}()
+
+	for frames := 1; ; frames++ {
+		state.refresh(ctx, app)
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err := state.render(app.stdout, app.config.BaseURL(), raw, isTerminal(app.stdout)); err != nil {
+			return err
+		}
+
+		if *count > 0 && frames >= *count {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-redraw:
+		}
+	}
+}
+
+// interactive returns the standard input when both it and the output are terminals,
+// in which case the dashboard reads single key presses.
+func interactive(app *app) (*os.File, bool) {
+	stdin, ok := app.stdin.(*os.File)
+	if !ok || !isTerminal(app.stdout) || !isTerminal(stdin) {
+		return nil, false
+	}
+
+	return stdin, true
+}
+
+// readKeys handles the key presses of the dashboard: q (or ctrl-c) quits, p pauses
+// refreshing and r refreshes right away.
+func readKeys(reader io.Reader, state *dashboardState, quit context.CancelFunc, redraw chan<- struct{}) {
+	var key = make([]byte, 1)
+
+	for {
+		if _, err := reader.Read(key); err != nil {
+			return
+		}
+
+		switch key[0] {
+		case 'q', 3:
+			quit()
+			return
+		case 'p':
+			state.mutex.Lock()
+			state.paused = !state.paused
+			state.mutex.Unlock()
+		}
+
+		select {
+		case redraw <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (state *dashboardState) add(event *events.PaymentEvent) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	state.feed = append(state.feed, event)
+
+	if len(state.feed) > state.feedSize {
+		state.feed = state.feed[len(state.feed)-state.feedSize:]
+	}
+}
+
+func (state *dashboardState) refresh(ctx context.Context, app *app) {
+	state.mutex.Lock()
+	paused := state.paused
+	state.mutex.Unlock()
+
+	if paused {
+		return
+	}
+
+	var (
+		started     = time.Now()
+		address     common.Address
+		channelList []*channels.Channel
+		transfers   []*pendingtransfers.Transfer
+		err         error
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, app.timeout)
+	defer cancel()
+
+	if address, err = app.client.Address().Get(ctx); err == nil {
+		if channelList, err = app.client.Channels().ListAll(ctx); err == nil {
+			transfers, err = app.client.PendingTransfers().ListAll(ctx)
+		}
+	}
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	state.err = err
+	state.refreshed = time.Now()
+
+	if err != nil {
+		return
+	}
+
+	state.latency = time.Since(started)
+	state.address = address
+	state.channels = channelList
+	state.transfers = transfers
+}
+
+func (state *dashboardState) render(writer io.Writer, host string, raw, terminal bool) error {
+	var (
+		buffer = &bytes.Buffer{}
+		status = fmt.Sprintf("up, %s", state.latency.Round(time.Millisecond))
+		feed   = make([][]interface{}, 0)
+	)
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if terminal {
+		buffer.WriteString(clearScreen)
+	}
+
+	if state.err != nil {
+		status = "unreachable: " + state.err.Error()
+	}
+
+	if state.paused {
+		status += " (paused)"
+	}
+
+	fmt.Fprintf(buffer, "raidenctl dashboard  %s  %s\n", host, state.refreshed.Format("15:04:05"))
+	fmt.Fprintf(buffer, "node %s  %s\n\n", state.address.Hex(), status)
+
+	fmt.Fprintf(buffer, "CHANNELS (%d)\n", len(state.channels))
+	printTable(buffer, channelColumns, channelRows(state.app, state.channels...))
+
+	fmt.Fprintf(buffer, "\nPENDING TRANSFERS (%d)\n", len(state.transfers))
+	printTable(buffer, transferColumns, transferRows(state.app, state.transfers...))
+
+	for i := len(state.feed) - 1; i >= 0; i-- {
+		event := state.feed[i]
+
+		feed = append(feed, []interface{}{
+			event.LogTime.Format(time.RFC3339),
+			event.EventName,
+			event.TokenAddress,
+			event.PartnerAddress,
+			event.Identifier,
+			state.app.amount(event.TokenAddress, event.Amount),
+		})
+	}
+
+	fmt.Fprintf(buffer, "\nPAYMENTS (last %d)\n", state.feedSize)
+	printTable(buffer, feedColumns, feed)
+
+	if raw {
+		buffer.WriteString("\nq quit  p pause  r refresh\n")
+	}
+
+	output := buffer.String()
+
+	// a terminal in raw mode does not return the carriage on a new line
+	if raw {
+		output = strings.Replace(output, "\n", "\r\n", -1)
+	}
+
+	_, err := io.WriteString(writer, output)
+
+	return err
+}
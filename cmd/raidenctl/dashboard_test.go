@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDashboard(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"role":"initiator","payment_identifier":42,"locked_amount":5}]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK, `[]`))
+
+	t.Run("renders node status, channels and pending transfers", func(t *testing.T) {
+		var stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+
+		code := run([]string{"-config", "/nonexistent", "dashboard", "-interval", "1ms", "-count", "2"}, nil, stdout, stderr)
+
+		require.Equal(t, 0, code, stderr.String())
+		assert.Equal(t, 2, strings.Count(stdout.String(), "raidenctl dashboard"))
+		assert.Contains(t, stdout.String(), "node 0x2a65Aca4D5fC5B5C859090a6c34d164135398226  up")
+		assert.Contains(t, stdout.String(), "CHANNELS (1)")
+		assert.Contains(t, stdout.String(), "PENDING TRANSFERS (1)")
+		assert.Contains(t, stdout.String(), "initiator")
+	})
+
+	t.Run("feed keeps the latest events first", func(t *testing.T) {
+		var (
+			stdout = &bytes.Buffer{}
+			state  = &dashboardState{feedSize: 2}
+		)
+
+		for identifier := int64(1); identifier <= 3; identifier++ {
+			state.add(&events.PaymentEvent{
+				TokenAddress:   common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+				PartnerAddress: common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+				Event:          &payments.Event{EventName: "EventPaymentReceivedSuccess", Identifier: identifier, Amount: big.NewInt(identifier * 10), LogTime: time.Now()},
+			})
+		}
+
+		require.NoError(t, state.render(stdout, "http://localhost:5001", true, false))
+
+		output := stdout.String()
+		assert.Contains(t, output, "\r\n", "new lines return the carriage in raw mode")
+		assert.NotContains(t, output, " 10\r\n")
+		assert.True(t, strings.Index(output, " 30\r\n") < strings.Index(output, " 20\r\n"))
+	})
+}
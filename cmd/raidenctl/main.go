@@ -0,0 +1,165 @@
+// Command raidenctl is a command-line tool exposing the Raiden client, to manage the
+// channels, payments, tokens and connections of a Raiden node.
+//
+// Usage:
+//
+//	raidenctl [global flags] <command> [subcommand] [flags] [arguments]
+//
+// Global flags must come before the command, command flags before the arguments:
+//
+//	raidenctl -host http://localhost:5001 channels list
+//	raidenctl -profile mainnet payments send -identifier 42 <token> <target> <amount>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/pfs"
+	"github.com/cpurta/go-raiden-client/storage"
+)
+
+const (
+	defaultHost       = "http://localhost:5001"
+	defaultAPIVersion = "v1"
+)
+
+// app holds what every command needs to talk to the Raiden node and print results.
+type app struct {
+	config  *config.Config
+	profile *config.Profile
+	client  *raidenclient.Client
+	pfs     *pfs.Client
+	labels  *channels.LabelStore
+	timeout time.Duration
+	output  string
+	raw     bool
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run parses the global flags and runs the command given in args, returning the exit
+// code of the process.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	var (
+		err        error
+		profile    *config.Profile
+		flags      = flag.NewFlagSet("raidenctl", flag.ContinueOnError)
+		host       = flags.String("host", "", "address of the Raiden node API (default "+defaultHost+", or $RAIDEN_HOST)")
+		apiVersion = flags.String("api-version", "", "version of the Raiden node API (default "+defaultAPIVersion+")")
+		name       = flags.String("profile", os.Getenv("RAIDENCTL_PROFILE"), "named profile of the configuration file to use")
+		pfsHost    = flags.String("pfs", "", "address of the pathfinding service used to estimate routes and fees")
+		path       = flags.String("config", config.DefaultPath(), "path of the configuration file holding the profiles, or $RAIDEN_CONFIG")
+		timeout    = flags.Duration("timeout", 30*time.Second, "time allowed for each command, or each request of a watch and each step of a payment around its confirmation, to complete")
+		raw        = flags.Bool("raw", false, "show amounts as integers in the smallest unit of their token, even for the tokens of the profile")
+		output     = formatTable
+	)
+
+	flags.Var((*outputFlag)(&output), "output", "output format: table, json, yaml or csv")
+
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "usage: raidenctl [global flags] <command> [subcommand] [flags] [arguments]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "global flags:")
+		flags.PrintDefaults()
+		fmt.Fprintln(stderr)
+		printCommands(stderr, commands)
+	}
+
+	if err = flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if profile, err = config.LoadProfileFile(*path, *name); err != nil {
+		fmt.Fprintln(stderr, "raidenctl:", err.Error())
+		return 1
+	}
+
+	nodeConfig := profile.Config()
+	nodeConfig.Host = firstOf(*host, os.Getenv("RAIDEN_HOST"), profile.Host, defaultHost)
+	nodeConfig.APIVersion = firstOf(*apiVersion, profile.APIVersion, defaultAPIVersion)
+
+	// the host may be the full URL of a node behind a gateway, e.g.
+	// https://gateway.example.com/raiden/node-3/api/v1
+	if host, basePath, version, err := config.SplitURL(nodeConfig.Host); err == nil && basePath != "" {
+		nodeConfig.Host, nodeConfig.BasePath = host, basePath
+		nodeConfig.APIVersion = firstOf(*apiVersion, version, nodeConfig.APIVersion)
+	}
+
+	app := &app{
+		config:  nodeConfig,
+		profile: profile,
+		client:  raidenclient.NewClient(nodeConfig, profile.HTTPClient(http.DefaultClient)),
+		labels:  channels.NewLabelStore(storage.NewFileStore(firstOf(profile.LabelsFile, labelsPath(*path)))),
+		timeout: *timeout,
+		output:  output,
+		raw:     *raw,
+		stdin:   stdin,
+		stdout:  stdout,
+		stderr:  stderr,
+	}
+
+	if host := firstOf(*pfsHost, profile.PFS); host != "" {
+		app.pfs = pfs.NewClient(&config.Config{Host: host, APIVersion: defaultAPIVersion}, http.DefaultClient)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt)
+	defer signal.Stop(interrupts)
+
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	if err = dispatch(ctx, app, commands, flags.Args()); err != nil {
+		if usageErr, ok := err.(*usageError); ok {
+			fmt.Fprintln(stderr, "raidenctl:", usageErr.message)
+			fmt.Fprintln(stderr, "usage: raidenctl", usageErr.usage)
+			return 2
+		}
+
+		fmt.Fprintln(stderr, "raidenctl:", err.Error())
+		return 1
+	}
+
+	return 0
+}
+
+// labelsPath returns the path of the file holding the channel labels next to the
+// configuration file, e.g. ~/.raidenctl.labels.json.
+func labelsPath(configPath string) string {
+	return strings.TrimSuffix(configPath, ".json") + ".labels.json"
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
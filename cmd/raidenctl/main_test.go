@@ -0,0 +1,318 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	var (
+		dir, err   = ioutil.TempDir("", "raidenctl")
+		configPath string
+	)
+
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configPath = filepath.Join(dir, "raidenctl.json")
+
+	require.NoError(t, ioutil.WriteFile(configPath, []byte(`{
+		"default_profile": "dev",
+		"profiles": {
+			"dev": {"host": "http://localhost:5001"},
+			"testnet": {"host": "http://testnet:5001", "api_version": "v1"},
+			"gateway": {"host": "https://gateway.example.com", "base_path": "/raiden/node-3"},
+			"restricted": {
+				"host": "http://restricted:5001",
+				"auth": {"token": "secret"},
+				"allowed_tokens": ["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"],
+				"allowed_partners": ["0x61C808D82A3Ac53231750daDc13c777b59310bD9"]
+			},
+			"units": {
+				"host": "http://units:5001",
+				"tokens": {"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8": {"symbol": "TTT", "decimals": 2}}
+			},
+			"lowercase": {"host": "http://lowercase:5001", "address_format": "lowercase"}
+		}
+	}`), 0600))
+
+	type testcase struct {
+		name           string
+		args           []string
+		prepHTTPMock   func()
+		expectedCode   int
+		expectedStdout []string
+		expectedStderr []string
+		missingStdout  []string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "node info",
+			args: []string{"node", "info"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"ADDRESS", "0x2a65Aca4D5fC5B5C859090a6c34d164135398226", "http://localhost:5001"},
+		},
+		testcase{
+			name: "profile selects the node",
+			args: []string{"-profile", "testnet", "channels", "list"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://testnet:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"PARTNER", "0x61C808D82A3Ac53231750daDc13c777b59310bD9", "opened", "25"},
+		},
+		testcase{
+			name: "host flag overrides the profile",
+			args: []string{"-host", "http://other:5001", "tokens", "list"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://other:5001/api/v1/tokens", httpmock.NewStringResponder(http.StatusOK, `["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"]`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"TOKEN", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"},
+		},
+		testcase{
+			name: "host flag with a base path",
+			args: []string{"-host", "https://gateway.example.com/raiden/node-3/api/v1", "node", "info"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "https://gateway.example.com/raiden/node-3/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"0x2a65Aca4D5fC5B5C859090a6c34d164135398226", "https://gateway.example.com/raiden/node-3"},
+		},
+		testcase{
+			name: "profile with a base path",
+			args: []string{"-profile", "gateway", "tokens", "list"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "https://gateway.example.com/raiden/node-3/api/v1/tokens", httpmock.NewStringResponder(http.StatusOK, `["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"]`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"TOKEN", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"},
+		},
+		testcase{
+			name: "send payment",
+			args: []string{"payments", "send", "-identifier", "42", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", "0x61C808D82A3Ac53231750daDc13c777b59310bD9", "10"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK, `{"target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","amount":10,"identifier":42}`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"IDENTIFIER", "42", "10"},
+		},
+		testcase{
+			name: "lowercase addresses",
+			args: []string{"-profile", "lowercase", "payments", "send", "-identifier", "42", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", "0x61C808D82A3Ac53231750daDc13c777b59310bD9", "10"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", "http://lowercase:5001/api/v1/payments/0xea674fdde714fd979de3edf0f56aa9716b898ec8/0x61c808d82a3ac53231750dadc13c777b59310bd9", httpmock.NewStringResponder(http.StatusOK, `{"target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","amount":10,"identifier":42}`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"IDENTIFIER", "42", "10"},
+		},
+		testcase{
+			name:           "invalid address",
+			args:           []string{"channels", "close", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", "not-an-address"},
+			prepHTTPMock:   func() {},
+			expectedCode:   1,
+			expectedStderr: []string{"invalid partner address: not-an-address"},
+		},
+		testcase{
+			name:           "wrong number of arguments",
+			args:           []string{"channels", "open", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"},
+			prepHTTPMock:   func() {},
+			expectedCode:   2,
+			expectedStderr: []string{"wrong number of arguments", "usage: raidenctl channels open"},
+		},
+		testcase{
+			name:           "unknown command",
+			args:           []string{"channel"},
+			prepHTTPMock:   func() {},
+			expectedCode:   2,
+			expectedStderr: []string{"unknown command: channel"},
+		},
+		testcase{
+			name:           "missing subcommand",
+			args:           []string{"payments"},
+			prepHTTPMock:   func() {},
+			expectedCode:   2,
+			expectedStderr: []string{"payments send", "payments history", "missing subcommand"},
+		},
+		testcase{
+			name: "profile credentials are sent to the node",
+			args: []string{"-profile", "restricted", "tokens", "list"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://restricted:5001/api/v1/tokens", func(request *http.Request) (*http.Response, error) {
+					if request.Header.Get("Authorization") != "Bearer secret" {
+						return httpmock.NewStringResponse(http.StatusUnauthorized, ""), nil
+					}
+
+					return httpmock.NewStringResponse(http.StatusOK, `["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"]`), nil
+				})
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"},
+		},
+		testcase{
+			name:           "token outside the allowlist of the profile",
+			args:           []string{"-profile", "restricted", "payments", "send", "0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359", "0x61C808D82A3Ac53231750daDc13c777b59310bD9", "10"},
+			prepHTTPMock:   func() {},
+			expectedCode:   1,
+			expectedStderr: []string{"token 0x89d24A6b4CcB1B6fAA2625fE562bDD9a23260359 is not allowed by profile restricted"},
+		},
+		testcase{
+			name:           "partner outside the allowlist of the profile",
+			args:           []string{"-profile", "restricted", "channels", "open", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", "0x1f7402f55e142820ea3812106d0657103fc1709e", "10"},
+			prepHTTPMock:   func() {},
+			expectedCode:   1,
+			expectedStderr: []string{"partner 0x1f7402f55e142820EA3812106D0657103fC1709e is not allowed by profile restricted"},
+		},
+		testcase{
+			name: "amounts in units of the tokens of the profile",
+			args: []string{"-profile", "units", "channels", "list"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://units:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"0.25 TTT", "0.35 TTT"},
+		},
+		testcase{
+			name: "raw amounts",
+			args: []string{"-profile", "units", "-raw", "channels", "list"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://units:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"25", "35"},
+			missingStdout:  []string{"TTT"},
+		},
+		testcase{
+			name: "machine-readable amounts stay integers",
+			args: []string{"-profile", "units", "-output", "json", "channels", "list"},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://units:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"25", "35"},
+			missingStdout:  []string{"TTT", "0.25"},
+		},
+		testcase{
+			name:           "unknown profile",
+			args:           []string{"-profile", "mainnet", "node", "info"},
+			prepHTTPMock:   func() {},
+			expectedCode:   1,
+			expectedStderr: []string{"unknown profile mainnet"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				stdout = &bytes.Buffer{}
+				stderr = &bytes.Buffer{}
+				code   int
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			code = run(append([]string{"-config", configPath}, tc.args...), nil, stdout, stderr)
+
+			assert.Equal(t, tc.expectedCode, code, stderr.String())
+
+			for _, expected := range tc.expectedStdout {
+				assert.Contains(t, stdout.String(), expected)
+			}
+
+			for _, expected := range tc.expectedStderr {
+				assert.Contains(t, stderr.String(), expected)
+			}
+
+			for _, missing := range tc.missingStdout {
+				assert.NotContains(t, stdout.String(), missing)
+			}
+		})
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	var channelsJSON = `[{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`
+
+	type testcase struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedStdout string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "json list",
+			args: []string{"-output", "json", "channels", "list"},
+			expectedStdout: `[
+  {
+    "channel_identifier": 7,
+    "token_address": "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+    "partner_address": "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+    "state": "opened",
+    "balance": 25,
+    "total_deposit": 35,
+    "settle_timeout": 500,
+    "label": ""
+  }
+]
+`,
+		},
+		testcase{
+			name: "yaml object given after the command",
+			args: []string{"node", "info", "--output", "yaml"},
+			expectedStdout: `our_address: 0x2a65Aca4D5fC5B5C859090a6c34d164135398226
+host: http://localhost:5001
+api_version: v1
+`,
+		},
+		testcase{
+			name: "csv",
+			args: []string{"channels", "list", "-output=csv"},
+			expectedStdout: `channel_identifier,token_address,partner_address,state,balance,total_deposit,settle_timeout,label
+7,0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8,0x61C808D82A3Ac53231750daDc13c777b59310bD9,opened,25,35,500,
+`,
+		},
+		testcase{
+			name:         "unknown format",
+			args:         []string{"-output", "xml", "channels", "list"},
+			expectedCode: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				stdout = &bytes.Buffer{}
+				stderr = &bytes.Buffer{}
+				code   int
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, channelsJSON))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+
+			code = run(append([]string{"-config", "/nonexistent"}, tc.args...), nil, stdout, stderr)
+
+			assert.Equal(t, tc.expectedCode, code, stderr.String())
+			assert.Equal(t, tc.expectedStdout, stdout.String())
+		})
+	}
+}
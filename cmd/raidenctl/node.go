@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+)
+
+var nodeCommand = &command{
+	name: "node",
+	subcommands: []*command{
+		&command{
+			name:    "info",
+			usage:   "node info",
+			summary: "show the address and API of the Raiden node",
+			run:     nodeInfo,
+		},
+	},
+}
+
+func nodeInfo(ctx context.Context, app *app, args []string) error {
+	if _, err := parseArgs(app.flags("info"), args, 0, 0); err != nil {
+		return err
+	}
+
+	address, err := app.client.Address().Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	return app.printOne([]column{
+		{"ADDRESS", "our_address"},
+		{"HOST", "host"},
+		{"API VERSION", "api_version"},
+	}, []interface{}{address, app.config.BaseURL(), app.config.APIVersion})
+}
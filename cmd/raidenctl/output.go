@@ -0,0 +1,295 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/ethereum/go-ethereum/common"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Output formats of the -output flag. Every format but the table uses the stable field
+// names of the columns, which follow the field names of the Raiden API.
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	formatCSV   = "csv"
+)
+
+// column is a column of the output, with its header in a table and its field name in
+// every machine-readable format.
+type column struct {
+	header string
+	field  string
+}
+
+// flags creates the flag set of a command, which accepts the -output flag like the
+// global flags so that it can be given after the command as well.
+func (app *app) flags(name string) *flag.FlagSet {
+	var flags = flag.NewFlagSet(name, flag.ContinueOnError)
+
+	flags.Var((*outputFlag)(&app.output), "output", "output format: table, json, yaml or csv")
+
+	return flags
+}
+
+// outputFlag is a flag.Value only accepting the known output formats.
+type outputFlag string
+
+func (output *outputFlag) String() string {
+	return string(*output)
+}
+
+func (output *outputFlag) Set(value string) error {
+	switch value {
+	case formatTable, formatJSON, formatYAML, formatCSV:
+		*output = outputFlag(value)
+		return nil
+	}
+
+	return fmt.Errorf("unknown output format: %s", value)
+}
+
+// print writes a list of rows in the output format of the app, as a table, a JSON or
+// YAML list of objects, or CSV records with a header of field names.
+func (app *app) print(columns []column, rows [][]interface{}) error {
+	switch app.output {
+	case formatJSON:
+		return printJSON(app.stdout, records(columns, rows))
+	case formatYAML:
+		return printYAML(app.stdout, records(columns, rows))
+	case formatCSV:
+		return printCSV(app.stdout, columns, rows, true)
+	}
+
+	return printTable(app.stdout, columns, rows)
+}
+
+// printOne writes a single row in the output format of the app, as a single object
+// rather than a list for JSON and YAML.
+func (app *app) printOne(columns []column, row []interface{}) error {
+	switch app.output {
+	case formatJSON:
+		return printJSON(app.stdout, records(columns, [][]interface{}{row})[0])
+	case formatYAML:
+		return printYAML(app.stdout, records(columns, [][]interface{}{row})[0])
+	}
+
+	return app.print(columns, [][]interface{}{row})
+}
+
+// printTable writes rows as aligned columns under their headers.
+func printTable(writer io.Writer, columns []column, rows [][]interface{}) error {
+	var (
+		table   = tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+		headers = make([]string, len(columns))
+	)
+
+	for i, column := range columns {
+		headers[i] = column.header
+	}
+
+	fmt.Fprintln(table, strings.Join(headers, "\t"))
+
+	for _, row := range rows {
+		fmt.Fprintln(table, strings.Join(cells(row, true), "\t"))
+	}
+
+	return table.Flush()
+}
+
+func printJSON(writer io.Writer, value interface{}) error {
+	var encoder = json.NewEncoder(writer)
+
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(value)
+}
+
+func printYAML(writer io.Writer, value interface{}) error {
+	contents, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(contents)
+
+	return err
+}
+
+func printCSV(writer io.Writer, columns []column, rows [][]interface{}, header bool) error {
+	var csvWriter = csv.NewWriter(writer)
+
+	if header {
+		fields := make([]string, len(columns))
+
+		for i, column := range columns {
+			fields[i] = column.field
+		}
+
+		if err := csvWriter.Write(fields); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		if err := csvWriter.Write(cells(row, false)); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// cells returns the values of a row as text, amounts in whole tokens when human.
+func cells(row []interface{}, human bool) []string {
+	var cells = make([]string, len(row))
+
+	for i, value := range row {
+		if amount, ok := value.(tokenAmount); ok && !human {
+			value = amount.amount
+		}
+
+		cells[i] = fmt.Sprint(value)
+	}
+
+	return cells
+}
+
+// tokenAmount is an amount of a token whose decimals are known, shown in whole tokens
+// with the symbol of the token in tables, and as the integer it is in every
+// machine-readable format.
+type tokenAmount struct {
+	amount *big.Int
+	token  *amounts.Token
+}
+
+func (amount tokenAmount) String() string {
+	return amount.token.Format(amount.amount)
+}
+
+// amount returns an amount of the token as a value of a row, a tokenAmount when the
+// profile holds the decimals of the token and raw amounts were not asked for.
+func (app *app) amount(tokenAddress common.Address, amount *big.Int) interface{} {
+	if token := app.token(tokenAddress); token != nil {
+		return tokenAmount{amount: amounts.OrZero(amount), token: token}
+	}
+
+	return amount
+}
+
+// label returns the name of the label of the channel, empty when it has none or the
+// labels can't be read, which is no reason to fail a command.
+func (app *app) label(channel *channels.Channel) string {
+	if channel.Label != nil {
+		return channel.Label.Name
+	}
+
+	if app == nil || app.labels == nil {
+		return ""
+	}
+
+	label, err := app.labels.Get(channel.TokenAddress, channel.PartnerAddress)
+	if err != nil || label == nil {
+		return ""
+	}
+
+	return label.Name
+}
+
+// token returns the unit of the amounts of a token of the profile, nil when the
+// profile does not have it or raw amounts were asked for.
+func (app *app) token(tokenAddress common.Address) *amounts.Token {
+	if app == nil || app.raw || app.profile == nil {
+		return nil
+	}
+
+	return app.profile.Token(tokenAddress)
+}
+
+// record is a row keyed by the field names of its columns, which keeps the order of
+// the columns when marshalled.
+type record struct {
+	columns []column
+	values  []interface{}
+}
+
+func records(columns []column, rows [][]interface{}) []*record {
+	var records = make([]*record, 0, len(rows))
+
+	for _, row := range rows {
+		values := make([]interface{}, len(row))
+
+		for i, value := range row {
+			if address, ok := value.(common.Address); ok {
+				value = address.Hex()
+			}
+
+			if network, ok := value.(address.TokenNetworkAddress); ok {
+				value = network.Hex()
+			}
+
+			if amount, ok := value.(tokenAmount); ok {
+				value = amount.amount
+			}
+
+			values[i] = value
+		}
+
+		records = append(records, &record{columns: columns, values: values})
+	}
+
+	return records
+}
+
+func (record *record) MarshalJSON() ([]byte, error) {
+	var buffer strings.Builder
+
+	buffer.WriteString("{")
+
+	for i, column := range record.columns {
+		value, err := json.Marshal(record.values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			buffer.WriteString(",")
+		}
+
+		fmt.Fprintf(&buffer, "%q:%s", column.field, value)
+	}
+
+	buffer.WriteString("}")
+
+	return []byte(buffer.String()), nil
+}
+
+func (record *record) MarshalYAML() (interface{}, error) {
+	var items = make(yaml.MapSlice, len(record.columns))
+
+	for i, column := range record.columns {
+		value := record.values[i]
+
+		// amounts are marshalled as text, keep them integers unless they are too large
+		if amount, ok := value.(*big.Int); ok && amount.IsInt64() {
+			value = amount.Int64()
+		}
+
+		items[i] = yaml.MapItem{Key: column.field, Value: value}
+	}
+
+	return items, nil
+}
@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pfs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// errPaymentCancelled is returned when the payment was not confirmed.
+var errPaymentCancelled = errors.New("payment cancelled")
+
+var payCommand = &command{
+	name:        "pay",
+	usage:       "pay [-yes] [-dry-run] [-identifier id] [-decimals n] <token> <target> <amount>",
+	summary:     "preview a payment and send it once confirmed, or simulate it",
+	interactive: true,
+	run:         pay,
+}
+
+// paymentPreview is everything shown to the operator before a payment is sent.
+type paymentPreview struct {
+	ourAddress   common.Address
+	tokenNetwork address.TokenNetworkAddress
+	amount       *big.Int
+	channel      *channels.Channel
+	routes       []*pfs.Route
+	routeErr     error
+}
+
+func pay(ctx context.Context, app *app, args []string) error {
+	var (
+		flags      = app.flags("pay")
+		yes        = flags.Bool("yes", false, "send the payment without asking for confirmation")
+		dryRun     = flags.Bool("dry-run", false, "simulate the payment, with its route, fee and predicted outcome, without sending it")
+		identifier = flags.Int64("identifier", 0, "payment identifier, generated by the node when not given")
+		decimals   = flags.Int("decimals", 0, "decimals of the token, the amount is then given in whole tokens such as 1.5, by default those of the tokens of the profile")
+	)
+
+	args, err := parseArgs(flags, args, 3, 3)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := parseAddresses([]string{"token", "target"}, args[:2])
+	if err != nil {
+		return err
+	}
+
+	token := app.token(addresses[0])
+	if *decimals > 0 {
+		token = &amounts.Token{Decimals: *decimals}
+	}
+
+	amount, err := parseTokenAmount(args[2], token)
+	if err != nil {
+		return err
+	}
+
+	if err = checkAllowed(app, addresses[0], addresses[1]); err != nil {
+		return err
+	}
+
+	previewCtx, cancelPreview := context.WithTimeout(ctx, app.timeout)
+	defer cancelPreview()
+
+	if *dryRun {
+		return simulatePayment(previewCtx, app, addresses[0], addresses[1], amount)
+	}
+
+	preview, err := previewPayment(previewCtx, app, addresses[0], addresses[1], amount)
+	if err != nil {
+		return err
+	}
+
+	printPreview(app, preview, addresses[0], addresses[1], *identifier, token)
+
+	if !*yes && !confirm(app, "Send this payment? [y/N] ") {
+		return errPaymentCancelled
+	}
+
+	// the timeout of the payment starts once it is confirmed, however long the
+	// operator took to answer
+	sendCtx, cancelSend := context.WithTimeout(ctx, app.timeout)
+	defer cancelSend()
+
+	payment, err := app.client.Payments().InitiateWithIdentifier(sendCtx, addresses[0], addresses[1], amount, *identifier)
+	if err != nil {
+		return err
+	}
+
+	return app.printOne(paymentColumns, []interface{}{
+		payment.Identifier,
+		payment.TokenAddress,
+		payment.InitiatorAddress,
+		payment.TargetAddress,
+		payment.Amount,
+	})
+}
+
+// previewPayment resolves the addresses involved in the payment, looks for a direct
+// channel with the target and asks the pathfinding service, when one is configured,
+// for the routes and fees of the payment.
+func previewPayment(ctx context.Context, app *app, token, target common.Address, amount *big.Int) (*paymentPreview, error) {
+	var (
+		err         error
+		channelList []*channels.Channel
+		preview     = &paymentPreview{amount: amount}
+	)
+
+	if preview.ourAddress, err = app.client.Address().Get(ctx); err != nil {
+		return nil, err
+	}
+
+	if preview.tokenNetwork, err = app.client.Tokens().Get(ctx, token); err != nil {
+		return nil, fmt.Errorf("unable to resolve the token network of %s: %s", token.Hex(), err.Error())
+	}
+
+	if channelList, err = app.client.Channels().ListToken(ctx, token); err != nil {
+		return nil, err
+	}
+
+	for _, channel := range channelList {
+		if channel.PartnerAddress == target && channel.State == "opened" {
+			preview.channel = channel
+		}
+	}
+
+	if app.pfs != nil {
+		preview.routes, preview.routeErr = app.pfs.FindPaths(ctx, preview.tokenNetwork, preview.ourAddress, target, amount, 3)
+	}
+
+	return preview, nil
+}
+
+var outcomeColumns = []column{
+	{"TOKEN", "token_address"},
+	{"TARGET", "target_address"},
+	{"AMOUNT", "amount"},
+	{"HOPS", "hops"},
+	{"FEE", "estimated_fee"},
+	{"LATENCY", "estimated_latency"},
+	{"CAPACITY", "capacity"},
+	{"SUCCESS", "success"},
+	{"REASON", "reason"},
+}
+
+// simulatePayment prints the simulated outcome of the payment, with the routes of the
+// pathfinding service when one is configured and through direct channels otherwise.
+func simulatePayment(ctx context.Context, app *app, token, target common.Address, amount *big.Int) error {
+	var (
+		pathFinder pfs.PathFinder
+		hops       int
+		fee        interface{} = ""
+		capacity   interface{} = ""
+	)
+
+	// a nil client must not be given as a non nil path finder
+	if app.pfs != nil {
+		pathFinder = app.pfs
+	}
+
+	outcome, err := payments.NewSimulator(app.config, pathFinder, nil, app.profile.HTTPClient(http.DefaultClient)).Simulate(ctx, token, target, amount)
+	if err != nil {
+		return err
+	}
+
+	if outcome.Route != nil {
+		hops = outcome.Route.Hops()
+		fee = app.amount(token, outcome.EstimatedFee)
+	}
+
+	if outcome.Capacity != nil {
+		capacity = app.amount(token, outcome.Capacity)
+	}
+
+	return app.printOne(outcomeColumns, []interface{}{
+		outcome.TokenAddress,
+		outcome.TargetAddress,
+		app.amount(token, outcome.Amount),
+		hops,
+		fee,
+		outcome.EstimatedLatency.String(),
+		capacity,
+		outcome.Success,
+		outcome.Reason,
+	})
+}
+
+func printPreview(app *app, preview *paymentPreview, tokenAddress, target common.Address, identifier int64, token *amounts.Token) {
+	var (
+		identifierText = "assigned by the node"
+		channelText    = "none, the payment will be mediated"
+		routeText      = "not estimated, no pathfinding service configured"
+		warnings       = make([]string, 0)
+	)
+
+	if identifier != 0 {
+		identifierText = fmt.Sprint(identifier)
+	}
+
+	if preview.channel != nil {
+		channelText = fmt.Sprintf("%d, balance %s", preview.channel.ChannelIdentifier, token.Format(preview.channel.Balance))
+
+		if preview.channel.Balance.Cmp(preview.amount) < 0 {
+			warnings = append(warnings, "the balance of the direct channel does not cover the amount")
+		}
+	}
+
+	switch {
+	case preview.routeErr != nil:
+		routeText = "unavailable: " + preview.routeErr.Error()
+		warnings = append(warnings, "the pathfinding service found no route, the payment is likely to fail")
+	case app.pfs != nil && len(preview.routes) == 0:
+		routeText = "no route found"
+		warnings = append(warnings, "the pathfinding service found no route, the payment is likely to fail")
+	case len(preview.routes) > 0:
+		route := preview.routes[0]
+		routeText = fmt.Sprintf("%d hops, estimated fee %s", route.Hops(), token.Format(route.EstimatedFee))
+	}
+
+	writer := tabwriter.NewWriter(app.stderr, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(writer, "Payment preview")
+	fmt.Fprintf(writer, "  from\t%s\n", preview.ourAddress.Hex())
+	fmt.Fprintf(writer, "  token\t%s\n", tokenAddress.Hex())
+	fmt.Fprintf(writer, "  token network\t%s\n", preview.tokenNetwork.Hex())
+	fmt.Fprintf(writer, "  target\t%s\n", target.Hex())
+	fmt.Fprintf(writer, "  amount\t%s (%s in the smallest unit)\n", token.Format(preview.amount), preview.amount)
+	fmt.Fprintf(writer, "  identifier\t%s\n", identifierText)
+	fmt.Fprintf(writer, "  direct channel\t%s\n", channelText)
+	fmt.Fprintf(writer, "  route\t%s\n", routeText)
+	writer.Flush()
+
+	for _, warning := range warnings {
+		fmt.Fprintln(app.stderr, "warning:", warning)
+	}
+}
+
+// confirm asks a yes or no question on the standard input, anything but yes being
+// taken as a no.
+func confirm(app *app, question string) bool {
+	if app.stdin == nil {
+		return false
+	}
+
+	fmt.Fprint(app.stderr, question)
+
+	answer, _ := bufio.NewReader(app.stdin).ReadString('\n')
+
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	}
+
+	return false
+}
+
+// parseTokenAmount parses an amount given in whole tokens of the token, such as 1.5,
+// into its smallest unit. A nil token parses the amount as an integer.
+func parseTokenAmount(value string, token *amounts.Token) (*big.Int, error) {
+	if token == nil || token.Decimals <= 0 {
+		return parseAmount("amount", value)
+	}
+
+	return token.Parse(value)
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPay(t *testing.T) {
+	var (
+		tokenAddress  = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		targetAddress = "0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		paymentURL    = "http://localhost:5001/api/v1/payments/" + tokenAddress + "/" + targetAddress
+		prepNode      = func() {
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/tokens/"+tokenAddress, httpmock.NewStringResponder(http.StatusOK, `"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"`))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/"+tokenAddress, httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"partner_address":"`+targetAddress+`","token_address":"`+tokenAddress+`","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/_debug/reachability/"+targetAddress, httpmock.NewStringResponder(http.StatusOK, `{"reachability":"reachable"}`))
+			httpmock.RegisterResponder("POST", paymentURL, httpmock.NewStringResponder(http.StatusOK, `{"target_address":"`+targetAddress+`","amount":10,"identifier":42}`))
+		}
+	)
+
+	type testcase struct {
+		name           string
+		args           []string
+		stdin          io.Reader
+		prepHTTPMock   func()
+		expectedCode   int
+		expectedPosts  int
+		expectedStdout []string
+		expectedStderr []string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "confirmed payment",
+			args:           []string{"pay", "-identifier", "42", tokenAddress, targetAddress, "10"},
+			stdin:          strings.NewReader("y\n"),
+			prepHTTPMock:   prepNode,
+			expectedCode:   0,
+			expectedPosts:  1,
+			expectedStdout: []string{"IDENTIFIER", "42"},
+			expectedStderr: []string{"Payment preview", "0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6", "7, balance 25", "not estimated", "Send this payment?"},
+		},
+		testcase{
+			name:           "confirmed after the timeout",
+			args:           []string{"-timeout", "50ms", "pay", "-identifier", "42", tokenAddress, targetAddress, "10"},
+			stdin:          &slowReader{delay: 100 * time.Millisecond, answer: "y\n"},
+			prepHTTPMock:   prepNode,
+			expectedCode:   0,
+			expectedPosts:  1,
+			expectedStdout: []string{"IDENTIFIER", "42"},
+		},
+		testcase{
+			name:           "declined payment",
+			args:           []string{"pay", tokenAddress, targetAddress, "10"},
+			stdin:          strings.NewReader("n\n"),
+			prepHTTPMock:   prepNode,
+			expectedCode:   1,
+			expectedPosts:  0,
+			expectedStderr: []string{"Payment preview", "payment cancelled"},
+		},
+		testcase{
+			name:           "no terminal to confirm on",
+			args:           []string{"pay", tokenAddress, targetAddress, "10"},
+			prepHTTPMock:   prepNode,
+			expectedCode:   1,
+			expectedPosts:  0,
+			expectedStderr: []string{"payment cancelled"},
+		},
+		testcase{
+			name:           "yes skips the confirmation",
+			args:           []string{"pay", "-yes", tokenAddress, targetAddress, "10"},
+			prepHTTPMock:   prepNode,
+			expectedCode:   0,
+			expectedPosts:  1,
+			expectedStdout: []string{"IDENTIFIER", "42"},
+		},
+		testcase{
+			name:           "amount in whole tokens",
+			args:           []string{"pay", "-yes", "-decimals", "2", tokenAddress, targetAddress, "0.1"},
+			prepHTTPMock:   prepNode,
+			expectedCode:   0,
+			expectedPosts:  1,
+			expectedStderr: []string{"0.1 (10 in the smallest unit)", "7, balance 0.25"},
+		},
+		testcase{
+			name:           "too many decimals",
+			args:           []string{"pay", "-yes", "-decimals", "2", tokenAddress, targetAddress, "0.105"},
+			prepHTTPMock:   prepNode,
+			expectedCode:   1,
+			expectedStderr: []string{"more than 2 decimals"},
+		},
+		testcase{
+			name: "route and fee from the pathfinding service",
+			args: []string{"-pfs", "http://pfs:6000", "pay", "-yes", tokenAddress, targetAddress, "10"},
+			prepHTTPMock: func() {
+				prepNode()
+				httpmock.RegisterResponder("POST", "http://pfs:6000/api/v1/0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6/paths", httpmock.NewStringResponder(http.StatusOK, `{"result":[{"path":["0x2a65Aca4D5fC5B5C859090a6c34d164135398226","0x1f7402f55e142820ea3812106d0657103fc1709e","`+targetAddress+`"],"estimated_fee":3}]}`))
+			},
+			expectedCode:   0,
+			expectedPosts:  1,
+			expectedStderr: []string{"2 hops, estimated fee 3"},
+		},
+		testcase{
+			name: "no route from the pathfinding service",
+			args: []string{"-pfs", "http://pfs:6000", "pay", "-yes", tokenAddress, targetAddress, "10"},
+			prepHTTPMock: func() {
+				prepNode()
+				httpmock.RegisterResponder("POST", "http://pfs:6000/api/v1/0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6/paths", httpmock.NewStringResponder(http.StatusBadRequest, `{"error_code":2201,"errors":"No route between nodes found."}`))
+			},
+			expectedCode:   0,
+			expectedPosts:  1,
+			expectedStderr: []string{"No route between nodes found.", "warning:"},
+		},
+		testcase{
+			name:           "dry run through the direct channel",
+			args:           []string{"pay", "-dry-run", tokenAddress, targetAddress, "10"},
+			prepHTTPMock:   prepNode,
+			expectedCode:   0,
+			expectedPosts:  0,
+			expectedStdout: []string{"SUCCESS", "true", "500ms"},
+		},
+		testcase{
+			name: "dry run beyond the capacity",
+			args: []string{"-pfs", "http://pfs:6000", "pay", "-dry-run", tokenAddress, targetAddress, "24"},
+			prepHTTPMock: func() {
+				prepNode()
+				httpmock.RegisterResponder("POST", "http://pfs:6000/api/v1/0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6/paths", httpmock.NewStringResponder(http.StatusOK, `{"result":[{"path":["0x2a65Aca4D5fC5B5C859090a6c34d164135398226","`+targetAddress+`"],"estimated_fee":3}]}`))
+			},
+			expectedCode:   0,
+			expectedPosts:  0,
+			expectedStdout: []string{"false", "insufficient_capacity"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				stdout = &bytes.Buffer{}
+				stderr = &bytes.Buffer{}
+				code   int
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			code = run(append([]string{"-config", "/nonexistent"}, tc.args...), tc.stdin, stdout, stderr)
+
+			assert.Equal(t, tc.expectedCode, code, stderr.String())
+			assert.Equal(t, tc.expectedPosts, httpmock.GetCallCountInfo()["POST "+paymentURL])
+
+			for _, expected := range tc.expectedStdout {
+				assert.Contains(t, stdout.String(), expected)
+			}
+
+			for _, expected := range tc.expectedStderr {
+				assert.Contains(t, stderr.String(), expected)
+			}
+		})
+	}
+}
+
+// slowReader answers after a delay, as an operator taking their time to confirm.
+type slowReader struct {
+	delay  time.Duration
+	answer string
+	read   bool
+}
+
+func (reader *slowReader) Read(p []byte) (int, error) {
+	if reader.read {
+		return 0, io.EOF
+	}
+
+	time.Sleep(reader.delay)
+	reader.read = true
+
+	return copy(p, reader.answer), nil
+}
+
+func TestPayTokenDefaults(t *testing.T) {
+	var (
+		dir, err      = ioutil.TempDir("", "raidenctl")
+		tokenAddress  = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		targetAddress = "0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		configPath    string
+		body          = map[string]interface{}{}
+		stdout        = &bytes.Buffer{}
+		stderr        = &bytes.Buffer{}
+	)
+
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configPath = filepath.Join(dir, "raidenctl.json")
+	require.NoError(t, ioutil.WriteFile(configPath, []byte(`{"default_profile": "dev", "profiles": {"dev": {
+		"host": "http://localhost:5001",
+		"token_defaults": {"`+tokenAddress+`": {"lock_timeout": 60}}
+	}}}`), 0600))
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/tokens/"+tokenAddress, httpmock.NewStringResponder(http.StatusOK, `"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/"+tokenAddress, httpmock.NewStringResponder(http.StatusOK, `[]`))
+	httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/"+tokenAddress+"/"+targetAddress, func(request *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(request.Body).Decode(&body); err != nil {
+			return nil, err
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, `{"target_address":"`+targetAddress+`","amount":10,"identifier":42}`), nil
+	})
+
+	code := run([]string{"-config", configPath, "pay", "-yes", "-identifier", "42", tokenAddress, targetAddress, "10"}, nil, stdout, stderr)
+
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, float64(60), body["lock_timeout"])
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var paymentsCommand = &command{
+	name: "payments",
+	subcommands: []*command{
+		&command{
+			name:    "send",
+			usage:   "payments send [-identifier id] <token> <target> <amount>",
+			summary: "send a payment to a target",
+			run:     paymentsSend,
+		},
+		&command{
+			name:    "history",
+			usage:   "payments history <token> <target>",
+			summary: "list the payment events with a target",
+			run:     paymentsHistory,
+		},
+	},
+}
+
+var paymentColumns = []column{
+	{"IDENTIFIER", "identifier"},
+	{"TOKEN", "token_address"},
+	{"INITIATOR", "initiator_address"},
+	{"TARGET", "target_address"},
+	{"AMOUNT", "amount"},
+}
+
+func paymentsSend(ctx context.Context, app *app, args []string) error {
+	var (
+		flags      = app.flags("send")
+		identifier = flags.Int64("identifier", 0, "payment identifier, generated by the node when not given")
+	)
+
+	args, err := parseArgs(flags, args, 3, 3)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	target, err := parseAddress("target", args[1])
+	if err != nil {
+		return err
+	}
+
+	amount, err := parseAmount("amount", args[2])
+	if err != nil {
+		return err
+	}
+
+	if err = checkAllowed(app, token, target); err != nil {
+		return err
+	}
+
+	payment, err := app.client.Payments().InitiateWithIdentifier(ctx, token, target, amount, *identifier)
+	if err != nil {
+		return err
+	}
+
+	return app.printOne(paymentColumns, []interface{}{
+		payment.Identifier,
+		payment.TokenAddress,
+		payment.InitiatorAddress,
+		payment.TargetAddress,
+		payment.Amount,
+	})
+}
+
+func paymentsHistory(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("history"), args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	target, err := parseAddress("target", args[1])
+	if err != nil {
+		return err
+	}
+
+	events, err := app.client.Payments().List(ctx, token, target)
+	if err != nil {
+		return err
+	}
+
+	return app.print(eventColumns, eventRows(app, token, events...))
+}
+
+var eventColumns = []column{
+	{"TIME", "log_time"},
+	{"EVENT", "event"},
+	{"IDENTIFIER", "identifier"},
+	{"AMOUNT", "amount"},
+	{"INITIATOR", "initiator"},
+	{"TARGET", "target"},
+}
+
+func eventRows(app *app, token common.Address, events ...*payments.Event) [][]interface{} {
+	rows := make([][]interface{}, 0, len(events))
+
+	for _, event := range events {
+		rows = append(rows, []interface{}{
+			event.LogTime.Format(time.RFC3339),
+			event.EventName,
+			event.Identifier,
+			app.amount(token, event.Amount),
+			event.Initiator,
+			event.Target,
+		})
+	}
+
+	return rows
+}
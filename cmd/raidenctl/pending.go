@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+)
+
+var pendingCommand = &command{
+	name: "pending",
+	subcommands: []*command{
+		&command{
+			name:    "list",
+			usage:   "pending list [token [partner]]",
+			summary: "list pending transfers, of a token or a channel",
+			run:     pendingList,
+		},
+	},
+}
+
+var transferColumns = []column{
+	{"CHANNEL", "channel_identifier"},
+	{"ROLE", "role"},
+	{"PAYMENT", "payment_identifier"},
+	{"INITIATOR", "initiator"},
+	{"TARGET", "target"},
+	{"LOCKED", "locked_amount"},
+	{"TRANSFERRED", "transferred_amount"},
+}
+
+func transferRows(app *app, transfers ...*pendingtransfers.Transfer) [][]interface{} {
+	rows := make([][]interface{}, 0, len(transfers))
+
+	for _, transfer := range transfers {
+		rows = append(rows, []interface{}{
+			transfer.ChannelIdentifier,
+			transfer.Role,
+			transfer.PaymentIdentifier,
+			transfer.Initiator,
+			transfer.Target,
+			app.amount(transfer.TokenAddress, transfer.LockedAmount),
+			app.amount(transfer.TokenAddress, transfer.TransferredAmount),
+		})
+	}
+
+	return rows
+}
+
+func pendingList(ctx context.Context, app *app, args []string) error {
+	var transfers []*pendingtransfers.Transfer
+
+	args, err := parseArgs(app.flags("list"), args, 0, 2)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := parseAddresses([]string{"token", "partner"}, args)
+	if err != nil {
+		return err
+	}
+
+	switch len(addresses) {
+	case 0:
+		transfers, err = app.client.PendingTransfers().ListAll(ctx)
+	case 1:
+		transfers, err = app.client.PendingTransfers().ListToken(ctx, addresses[0])
+	default:
+		transfers, err = app.client.PendingTransfers().ListChannel(ctx, addresses[0], addresses[1])
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return app.print(transferColumns, transferRows(app, transfers...))
+}
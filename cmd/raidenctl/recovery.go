@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/recovery"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var snapshotCommand = &command{
+	name:    "snapshot",
+	usage:   "snapshot <file>",
+	summary: "save the channels and pending transfers of the node, e.g. along with a backup",
+	run:     snapshot,
+}
+
+var verifyRecoveryCommand = &command{
+	name:    "verify-recovery",
+	usage:   "verify-recovery <snapshot>",
+	summary: "compare a node restored from a backup with the snapshot saved along with it",
+	run:     verifyRecovery,
+}
+
+// unsettledTransfer is the kind of the rows of the pending transfers of the restored
+// node, the obligations to watch, which are not problems.
+const unsettledTransfer = "unsettled_transfer"
+
+var recoveryColumns = []column{
+	{"KIND", "kind"},
+	{"TOKEN", "token_address"},
+	{"PARTNER", "partner_address"},
+	{"CHANNEL", "channel_identifier"},
+	{"PAYMENT", "payment_identifier"},
+	{"FIELD", "field"},
+	{"EXPECTED", "expected"},
+	{"ACTUAL", "actual"},
+}
+
+func snapshot(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("snapshot"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := recovery.NewVerifier(app.config, app.profile.HTTPClient(http.DefaultClient)).Snapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err = snapshot.Save(args[0]); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(app.stdout, "snapshot of %d channels and %d pending transfers written to %s\n", len(snapshot.Channels), len(snapshot.PendingTransfers), args[0])
+
+	return nil
+}
+
+func verifyRecovery(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("verify-recovery"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := recovery.LoadSnapshot(args[0])
+	if err != nil {
+		return err
+	}
+
+	report, err := recovery.NewVerifier(app.config, app.profile.HTTPClient(http.DefaultClient)).Verify(ctx, snapshot)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]interface{}, 0, len(report.Problems)+len(report.Unsettled))
+
+	for _, problem := range report.Problems {
+		rows = append(rows, []interface{}{
+			problem.Kind,
+			optionalAddress(problem.TokenAddress),
+			optionalAddress(problem.PartnerAddress),
+			optionalIdentifier(problem.ChannelIdentifier),
+			optionalIdentifier(problem.PaymentIdentifier),
+			problem.Field,
+			problem.Expected,
+			problem.Actual,
+		})
+	}
+
+	for _, transfer := range report.Unsettled {
+		rows = append(rows, []interface{}{
+			unsettledTransfer,
+			transfer.TokenAddress.Hex(),
+			"",
+			transfer.ChannelIdentifier,
+			transfer.PaymentIdentifier,
+			"locked_amount",
+			"",
+			app.amount(transfer.TokenAddress, transfer.LockedAmount),
+		})
+	}
+
+	if err = app.print(recoveryColumns, rows); err != nil {
+		return err
+	}
+
+	if app.output == formatTable {
+		fmt.Fprintf(app.stdout, "\n%d channels of the snapshot of %s checked, %d problems, %d unsettled transfers\n", report.Checked, report.SnapshotTakenAt.UTC().Format(time.RFC3339), len(report.Problems), len(report.Unsettled))
+	}
+
+	if !report.OK() {
+		return fmt.Errorf("recovery verification found %d problems", len(report.Problems))
+	}
+
+	return nil
+}
+
+// optionalAddress returns the address, or nothing for the problems without one.
+func optionalAddress(address common.Address) string {
+	if address == (common.Address{}) {
+		return ""
+	}
+
+	return address.Hex()
+}
+
+// optionalIdentifier returns the identifier, or nothing for the problems without one.
+func optionalIdentifier(identifier int64) interface{} {
+	if identifier == 0 {
+		return ""
+	}
+
+	return identifier
+}
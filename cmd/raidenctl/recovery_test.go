@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyRecovery(t *testing.T) {
+	var (
+		dir, err       = ioutil.TempDir("", "raidenctl")
+		tokenAddress   = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		partnerAddress = "0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		channelJSON    = func(balance string) string {
+			return `[{"channel_identifier":7,"partner_address":"` + partnerAddress + `","token_address":"` + tokenAddress + `","balance":` + balance + `,"total_deposit":100,"state":"opened","settle_timeout":500}]`
+		}
+		transferJSON = `[{"channel_identifier":7,"initiator":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","locked_amount":12,"payment_identifier":42,"role":"initiator","target":"` + partnerAddress + `","token_address":"` + tokenAddress + `","transferred_amount":0}]`
+		configPath   string
+		snapshotPath string
+	)
+
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configPath = filepath.Join(dir, "raidenctl.json")
+	snapshotPath = filepath.Join(dir, "snapshot.json")
+
+	require.NoError(t, ioutil.WriteFile(configPath, []byte(`{"default_profile": "dev", "profiles": {"dev": {"host": "http://localhost:5001"}}}`), 0600))
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+
+	type testcase struct {
+		name           string
+		args           []string
+		prepHTTPMock   func()
+		expectedCode   int
+		expectedStdout []string
+		expectedStderr []string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "snapshot written",
+			args: []string{"snapshot", snapshotPath},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, channelJSON("40")))
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", httpmock.NewStringResponder(http.StatusOK, transferJSON))
+			},
+			expectedStdout: []string{"snapshot of 1 channels and 1 pending transfers written to " + snapshotPath},
+		},
+		testcase{
+			name:           "restored node",
+			args:           []string{"verify-recovery", snapshotPath},
+			prepHTTPMock:   func() {},
+			expectedStdout: []string{"unsettled_transfer  " + tokenAddress, "1 channels of the snapshot of", "0 problems, 1 unsettled transfers"},
+		},
+		testcase{
+			name: "lost balance and transfer",
+			args: []string{"verify-recovery", snapshotPath},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, channelJSON("52")))
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", httpmock.NewStringResponder(http.StatusOK, `[]`))
+			},
+			expectedCode:   1,
+			expectedStdout: []string{"balance_mismatch  " + tokenAddress + "  " + partnerAddress + "  7", "balance        40        52", "missing_transfer", "2 problems, 0 unsettled transfers"},
+			expectedStderr: []string{"recovery verification found 2 problems"},
+		},
+		testcase{
+			name:           "json output",
+			args:           []string{"verify-recovery", "-output", "json", snapshotPath},
+			prepHTTPMock:   func() {},
+			expectedCode:   1,
+			expectedStdout: []string{`"kind": "balance_mismatch"`, `"field": "balance"`},
+		},
+		testcase{
+			name:           "missing snapshot",
+			args:           []string{"verify-recovery", filepath.Join(dir, "missing.json")},
+			prepHTTPMock:   func() {},
+			expectedCode:   1,
+			expectedStderr: []string{"missing.json"},
+		},
+		testcase{
+			name:         "missing file",
+			args:         []string{"snapshot"},
+			prepHTTPMock: func() {},
+			expectedCode: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				stdout = &bytes.Buffer{}
+				stderr = &bytes.Buffer{}
+			)
+
+			tc.prepHTTPMock()
+
+			code := run(append([]string{"-config", configPath}, tc.args...), nil, stdout, stderr)
+
+			assert.Equal(t, tc.expectedCode, code, stderr.String())
+
+			for _, expected := range tc.expectedStdout {
+				assert.Contains(t, stdout.String(), expected)
+			}
+
+			for _, expected := range tc.expectedStderr {
+				assert.Contains(t, stderr.String(), expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var smokeCommand = &command{
+	name:      "smoke",
+	usage:     "smoke [-deposit n] [-amount n] [-identifier id] [-settle-timeout blocks] <token> <partner>",
+	summary:   "check a node end to end: register, open, deposit, pay, verify and close",
+	streaming: true,
+	run:       smoke,
+}
+
+var smokeColumns = []column{
+	{"STEP", "step"},
+	{"RESULT", "result"},
+	{"DETAIL", "detail"},
+}
+
+// Results of the steps of a smoke test.
+const (
+	smokePass = "pass"
+	smokeFail = "fail"
+	smokeSkip = "skip"
+)
+
+// smokeStep is a step of a smoke test, returning what it checked when it passed.
+type smokeStep struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+}
+
+// smokeOptions is what the steps of a smoke test are run with.
+type smokeOptions struct {
+	tokenAddress   common.Address
+	partnerAddress common.Address
+	deposit        int64
+	amount         int64
+	identifier     int64
+	settleTimeout  int64
+	interval       time.Duration
+}
+
+func smoke(ctx context.Context, app *app, args []string) error {
+	var (
+		flags     = app.flags("smoke")
+		scenario  = &smokeOptions{}
+		failed    string
+		rows      = make([][]interface{}, 0)
+		addresses []common.Address
+	)
+
+	flags.Int64Var(&scenario.deposit, "deposit", 100, "amount deposited into the channel")
+	flags.Int64Var(&scenario.amount, "amount", 1, "amount paid to the partner")
+	flags.Int64Var(&scenario.identifier, "identifier", 0, "identifier of the payment, derived from the time when not given")
+	flags.Int64Var(&scenario.settleTimeout, "settle-timeout", 500, "number of blocks before the closed channel can be settled")
+	flags.DurationVar(&scenario.interval, "interval", time.Second, "time between two checks of the payment events")
+
+	args, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	if addresses, err = parseAddresses([]string{"token", "partner"}, args); err != nil {
+		return err
+	}
+
+	scenario.tokenAddress, scenario.partnerAddress = addresses[0], addresses[1]
+
+	if scenario.identifier == 0 {
+		scenario.identifier = time.Now().Unix()
+	}
+
+	if err = checkAllowed(app, scenario.tokenAddress, scenario.partnerAddress); err != nil {
+		return err
+	}
+
+	// every step, some waiting for a transaction, gets the timeout of a command
+	for _, step := range smokeSteps(app, scenario) {
+		if failed != "" {
+			rows = append(rows, []interface{}{step.name, smokeSkip, ""})
+			continue
+		}
+
+		stepCtx, cancel := context.WithTimeout(ctx, app.timeout)
+		detail, err := step.run(stepCtx)
+		cancel()
+
+		if err != nil {
+			failed = step.name
+			rows = append(rows, []interface{}{step.name, smokeFail, err.Error()})
+			continue
+		}
+
+		rows = append(rows, []interface{}{step.name, smokePass, detail})
+	}
+
+	if err = app.print(smokeColumns, rows); err != nil {
+		return err
+	}
+
+	if failed != "" {
+		return fmt.Errorf("smoke test failed at step %s", failed)
+	}
+
+	return nil
+}
+
+// smokeSteps returns the steps of the smoke test, which share the channel opened.
+func smokeSteps(app *app, scenario *smokeOptions) []smokeStep {
+	var (
+		channel *channels.Channel
+		client  = app.client.Channels()
+	)
+
+	return []smokeStep{
+		smokeStep{"register", func(ctx context.Context) (string, error) {
+			tokenNetwork, err := app.client.Tokens().Register(ctx, scenario.tokenAddress)
+			if raidenerrors.HasCode(err, raidenerrors.CodeTokenAlreadyRegistered) {
+				return "token already registered", nil
+			}
+
+			if err != nil {
+				return "", err
+			}
+
+			return "token network " + tokenNetwork.Hex(), nil
+		}},
+		smokeStep{"open", func(ctx context.Context) (string, error) {
+			var err error
+
+			// a channel left open by a previous run is used as is
+			if channel, err = channels.OpenOrGet(client, client).Open(ctx, scenario.tokenAddress, scenario.partnerAddress, nil, scenario.settleTimeout); err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("channel %d", channel.ChannelIdentifier), nil
+		}},
+		smokeStep{"deposit", func(ctx context.Context) (string, error) {
+			var (
+				err          error
+				totalDeposit = new(big.Int).Add(amounts.OrZero(channel.TotalDeposit), big.NewInt(scenario.deposit))
+			)
+
+			if channel, err = client.IncreaseDeposit(ctx, scenario.tokenAddress, scenario.partnerAddress, totalDeposit); err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("total deposit %s", amounts.Number(channel.TotalDeposit)), nil
+		}},
+		smokeStep{"pay", func(ctx context.Context) (string, error) {
+			payment, err := app.client.Payments().InitiateWithIdentifier(ctx, scenario.tokenAddress, scenario.partnerAddress, big.NewInt(scenario.amount), scenario.identifier)
+			if err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("payment %d of %s", payment.Identifier, amounts.Number(payment.Amount)), nil
+		}},
+		smokeStep{"verify", func(ctx context.Context) (string, error) {
+			return waitPaymentEvent(ctx, app, scenario)
+		}},
+		smokeStep{"close", func(ctx context.Context) (string, error) {
+			closed, err := client.Close(ctx, scenario.tokenAddress, scenario.partnerAddress)
+			if err != nil {
+				return "", err
+			}
+
+			return fmt.Sprintf("channel %d %s", closed.ChannelIdentifier, closed.State), nil
+		}},
+	}
+}
+
+// waitPaymentEvent lists the payment events with the partner until the node reports
+// the payment of the smoke test as sent.
+func waitPaymentEvent(ctx context.Context, app *app, scenario *smokeOptions) (string, error) {
+	for {
+		events, err := app.client.Payments().List(ctx, scenario.tokenAddress, scenario.partnerAddress)
+		if err != nil {
+			return "", err
+		}
+
+		for _, event := range events {
+			if event.Identifier == scenario.identifier && event.EventName == payments.EventPaymentSentSuccess {
+				return event.String(), nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("no %s event for payment %d: %s", payments.EventPaymentSentSuccess, scenario.identifier, ctx.Err().Error())
+		case <-time.After(scenario.interval):
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmoke(t *testing.T) {
+	var (
+		tokenAddress   = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		partnerAddress = "0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		channelURL     = "http://localhost:5001/api/v1/channels/" + tokenAddress + "/" + partnerAddress
+		channelJSON    = func(deposit, state string) string {
+			return `{"channel_identifier":7,"partner_address":"` + partnerAddress + `","token_address":"` + tokenAddress + `","balance":` + deposit + `,"total_deposit":` + deposit + `,"state":"` + state + `","settle_timeout":500}`
+		}
+		prepNode = func() {
+			httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/tokens/"+tokenAddress, httpmock.NewStringResponder(http.StatusCreated, `{"token_network_address":"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"}`))
+			httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusCreated, channelJSON("0", "opened")))
+			httpmock.RegisterResponder("PATCH", channelURL, func(request *http.Request) (*http.Response, error) {
+				var body bytes.Buffer
+
+				body.ReadFrom(request.Body)
+
+				if bytes.Contains(body.Bytes(), []byte(`"closed"`)) {
+					return httpmock.NewStringResponse(http.StatusOK, channelJSON("100", "closed")), nil
+				}
+
+				return httpmock.NewStringResponse(http.StatusOK, channelJSON("100", "opened")), nil
+			})
+			httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/"+tokenAddress+"/"+partnerAddress, httpmock.NewStringResponder(http.StatusOK, `{"target_address":"`+partnerAddress+`","amount":1,"identifier":42}`))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/payments/"+tokenAddress+"/"+partnerAddress, httpmock.NewStringResponder(http.StatusOK, `[{"event":"EventPaymentSentSuccess","amount":1,"target":"`+partnerAddress+`","identifier":42,"log_time":"2019-03-07T18:19:13.976"}]`))
+		}
+	)
+
+	type testcase struct {
+		name           string
+		args           []string
+		prepHTTPMock   func()
+		expectedCode   int
+		expectedStdout []string
+		expectedStderr []string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "every step passes",
+			args:           []string{"smoke", "-identifier", "42", tokenAddress, partnerAddress},
+			prepHTTPMock:   prepNode,
+			expectedCode:   0,
+			expectedStdout: []string{"register  pass    token network 0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6", "open      pass    channel 7", "deposit   pass    total deposit 100", "pay       pass    payment 42 of 1", "verify    pass    EventPaymentSentSuccess 42", "close     pass    channel 7 closed"},
+		},
+		testcase{
+			name: "token already registered",
+			args: []string{"smoke", "-identifier", "42", tokenAddress, partnerAddress},
+			prepHTTPMock: func() {
+				prepNode()
+				httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/tokens/"+tokenAddress, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Token already registered"}`))
+			},
+			expectedCode:   0,
+			expectedStdout: []string{"register  pass    token already registered", "close     pass"},
+		},
+		testcase{
+			name: "failed payment skips the next steps",
+			args: []string{"smoke", "-identifier", "42", tokenAddress, partnerAddress},
+			prepHTTPMock: func() {
+				prepNode()
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/"+tokenAddress+"/"+partnerAddress, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Payment couldn't be completed because: there is no route available"}`))
+			},
+			expectedCode:   1,
+			expectedStdout: []string{"deposit   pass", "pay       fail    raiden node error 409", "verify    skip", "close     skip"},
+			expectedStderr: []string{"smoke test failed at step pay"},
+		},
+		testcase{
+			name:           "missing partner",
+			args:           []string{"smoke", tokenAddress},
+			prepHTTPMock:   func() {},
+			expectedCode:   2,
+			expectedStderr: []string{"wrong number of arguments"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				stdout = &bytes.Buffer{}
+				stderr = &bytes.Buffer{}
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			code := run(append([]string{"-config", "/nonexistent"}, tc.args...), nil, stdout, stderr)
+
+			assert.Equal(t, tc.expectedCode, code, stderr.String())
+
+			for _, expected := range tc.expectedStdout {
+				assert.Contains(t, stdout.String(), expected)
+			}
+
+			for _, expected := range tc.expectedStderr {
+				assert.Contains(t, stderr.String(), expected)
+			}
+		})
+	}
+}
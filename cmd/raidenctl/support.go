@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/cpurta/go-raiden-client/support"
+)
+
+var supportCommand = &command{
+	name: "support",
+	subcommands: []*command{
+		&command{
+			name:    "bundle",
+			usage:   "support bundle [-since 24h] <file>",
+			summary: "write a support bundle of the node to attach to a bug report, - for stdout",
+			run:     supportBundle,
+		},
+	},
+}
+
+func supportBundle(ctx context.Context, app *app, args []string) error {
+	var (
+		flags = app.flags("bundle")
+		since = flags.Duration("since", support.DefaultEventsWindow, "how far back the payment events are gathered")
+	)
+
+	args, err := parseArgs(flags, args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	bundler := support.NewBundler(app.config, &support.Options{EventsWindow: *since}, app.profile.HTTPClient(http.DefaultClient))
+
+	if args[0] == "-" {
+		return bundler.SupportBundle(ctx, app.stdout)
+	}
+
+	file, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+
+	if err = bundler.SupportBundle(ctx, file); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(app.stdout, "support bundle written to", args[0])
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/support"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSupportBundle(t *testing.T) {
+	var (
+		dir, err   = ioutil.TempDir("", "raidenctl")
+		configPath string
+		bundlePath string
+	)
+
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	configPath = filepath.Join(dir, "raidenctl.json")
+	bundlePath = filepath.Join(dir, "bundle.zip")
+
+	require.NoError(t, ioutil.WriteFile(configPath, []byte(`{"default_profile": "dev", "profiles": {"dev": {"host": "http://localhost:5001"}}}`), 0600))
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/connections", httpmock.NewStringResponder(http.StatusOK, `{}`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", httpmock.NewStringResponder(http.StatusOK, `[]`))
+
+	type testcase struct {
+		name           string
+		args           []string
+		expectedCode   int
+		expectedStdout string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "bundle written",
+			args:           []string{"support", "bundle", "-since", "1h", bundlePath},
+			expectedStdout: "support bundle written to " + bundlePath,
+		},
+		testcase{
+			name:         "missing file",
+			args:         []string{"support", "bundle"},
+			expectedCode: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				stdout = &bytes.Buffer{}
+				stderr = &bytes.Buffer{}
+			)
+
+			code := run(append([]string{"-config", configPath}, tc.args...), nil, stdout, stderr)
+
+			assert.Equal(t, tc.expectedCode, code, stderr.String())
+			assert.Contains(t, stdout.String(), tc.expectedStdout)
+		})
+	}
+
+	archive, err := zip.OpenReader(bundlePath)
+	require.NoError(t, err)
+	defer archive.Close()
+
+	names := make([]string, 0, len(archive.File))
+
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+
+	assert.Contains(t, names, support.FileManifest)
+	assert.Contains(t, names, support.FileChannels)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+)
+
+var tokensCommand = &command{
+	name: "tokens",
+	subcommands: []*command{
+		&command{
+			name:    "list",
+			usage:   "tokens list",
+			summary: "list the registered tokens",
+			run:     tokensList,
+		},
+		&command{
+			name:    "get",
+			usage:   "tokens get <token>",
+			summary: "show the token network of a token",
+			run:     tokensGet,
+		},
+		&command{
+			name:    "register",
+			usage:   "tokens register <token>",
+			summary: "register a token, creating its token network",
+			run:     tokensRegister,
+		},
+		&command{
+			name:    "partners",
+			usage:   "tokens partners <token>",
+			summary: "list the channel partners of a token network",
+			run:     tokensPartners,
+		},
+	},
+}
+
+var tokenNetworkColumns = []column{
+	{"TOKEN", "token_address"},
+	{"TOKEN NETWORK", "token_network_address"},
+}
+
+func tokensList(ctx context.Context, app *app, args []string) error {
+	if _, err := parseArgs(app.flags("list"), args, 0, 0); err != nil {
+		return err
+	}
+
+	tokens, err := app.client.Tokens().List(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]interface{}, 0, len(tokens))
+	for _, token := range tokens {
+		rows = append(rows, []interface{}{token})
+	}
+
+	return app.print([]column{{"TOKEN", "token_address"}}, rows)
+}
+
+func tokensGet(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("get"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	network, err := app.client.Tokens().Get(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return app.printOne(tokenNetworkColumns, []interface{}{token, network})
+}
+
+func tokensRegister(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("register"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	network, err := app.client.Tokens().Register(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	return app.printOne(tokenNetworkColumns, []interface{}{token, network})
+}
+
+func tokensPartners(ctx context.Context, app *app, args []string) error {
+	args, err := parseArgs(app.flags("partners"), args, 1, 1)
+	if err != nil {
+		return err
+	}
+
+	token, err := parseAddress("token", args[0])
+	if err != nil {
+		return err
+	}
+
+	partners, err := app.client.Tokens().ListPartners(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	rows := make([][]interface{}, 0, len(partners))
+	for _, partner := range partners {
+		rows = append(rows, []interface{}{partner.Address, partner.ChannelURI})
+	}
+
+	return app.print([]column{{"PARTNER", "partner_address"}, {"CHANNEL", "channel"}}, rows)
+}
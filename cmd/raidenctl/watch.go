@@ -0,0 +1,257 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/changefeed"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+)
+
+// clearScreen moves the cursor home and clears the terminal before a table is
+// rendered again.
+const clearScreen = "\033[H\033[2J"
+
+var watchCommand = &command{
+	name: "watch",
+	subcommands: []*command{
+		&command{
+			name:      "channels",
+			usage:     "watch channels [-interval d] [-json] [token]",
+			summary:   "keep refreshing the channels, or the channels of a token",
+			streaming: true,
+			run:       watchChannels,
+		},
+		&command{
+			name:      "payments",
+			usage:     "watch payments [-interval d] [-json] <token> <target>",
+			summary:   "keep refreshing the payment events with a target",
+			streaming: true,
+			run:       watchPayments,
+		},
+		&command{
+			name:      "pending",
+			usage:     "watch pending [-interval d] [-json] [token [partner]]",
+			summary:   "keep refreshing the pending transfers",
+			streaming: true,
+			run:       watchPending,
+		},
+	},
+}
+
+// view fetches the current state of what is being watched as table rows.
+type view func(ctx context.Context) ([][]interface{}, error)
+
+type watchOptions struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	jsonLines   bool
+	count       int
+}
+
+func watchFlags(app *app, name string) (*flag.FlagSet, *watchOptions) {
+	var (
+		flags   = app.flags(name)
+		options = &watchOptions{}
+	)
+
+	flags.DurationVar(&options.interval, "interval", 2*time.Second, "time between two refreshes while the view changes")
+	flags.DurationVar(&options.maxInterval, "max-interval", 0, "time between two refreshes the interval grows to while the view does not change, 8 times -interval by default")
+	flags.BoolVar(&options.jsonLines, "json", false, "emit every row as a line of JSON, same as -output json")
+	flags.IntVar(&options.count, "count", 0, "number of refreshes before exiting, 0 to keep refreshing until interrupted")
+
+	return flags, options
+}
+
+func watchChannels(ctx context.Context, app *app, args []string) error {
+	flags, options := watchFlags(app, "channels")
+
+	args, err := parseArgs(flags, args, 0, 1)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := parseAddresses([]string{"token"}, args)
+	if err != nil {
+		return err
+	}
+
+	return watch(ctx, app, options, "channels", channelColumns, func(ctx context.Context) ([][]interface{}, error) {
+		var (
+			err         error
+			channelList []*channels.Channel
+		)
+
+		if len(addresses) == 0 {
+			channelList, err = app.client.Channels().ListAll(ctx)
+		} else {
+			channelList, err = app.client.Channels().ListToken(ctx, addresses[0])
+		}
+
+		return channelRows(app, channelList...), err
+	})
+}
+
+func watchPayments(ctx context.Context, app *app, args []string) error {
+	flags, options := watchFlags(app, "payments")
+
+	args, err := parseArgs(flags, args, 2, 2)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := parseAddresses([]string{"token", "target"}, args)
+	if err != nil {
+		return err
+	}
+
+	return watch(ctx, app, options, "payments", eventColumns, func(ctx context.Context) ([][]interface{}, error) {
+		events, err := app.client.Payments().List(ctx, addresses[0], addresses[1])
+
+		return eventRows(app, addresses[0], events...), err
+	})
+}
+
+func watchPending(ctx context.Context, app *app, args []string) error {
+	flags, options := watchFlags(app, "pending")
+
+	args, err := parseArgs(flags, args, 0, 2)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := parseAddresses([]string{"token", "partner"}, args)
+	if err != nil {
+		return err
+	}
+
+	return watch(ctx, app, options, "pending", transferColumns, func(ctx context.Context) ([][]interface{}, error) {
+		var (
+			err       error
+			transfers []*pendingtransfers.Transfer
+		)
+
+		switch len(addresses) {
+		case 0:
+			transfers, err = app.client.PendingTransfers().ListAll(ctx)
+		case 1:
+			transfers, err = app.client.PendingTransfers().ListToken(ctx, addresses[0])
+		default:
+			transfers, err = app.client.PendingTransfers().ListChannel(ctx, addresses[0], addresses[1])
+		}
+
+		return transferRows(app, transfers...), err
+	})
+}
+
+// watch will poll the view until the context is done, rendering it as a table or in
+// the output format of the app: JSON lines, a YAML document or CSV records for every
+// refresh. The view is polled at the configured interval while it changes, and less
+// and less often up to the maximum interval while it does not or while the node is
+// slow or failing, see changefeed.Feed.
+// A failed poll is reported and the view is polled again at the next interval.
+func watch(ctx context.Context, app *app, options *watchOptions, name string, columns []column, view view) error {
+	var (
+		feed     = changefeed.New(&changefeed.Options{MinInterval: options.interval, MaxInterval: options.maxInterval})
+		terminal = isTerminal(app.stdout)
+		watched  = append([]column{{"VIEW", "view"}, {"TIME", "time"}}, columns...)
+		last     string
+	)
+
+	if options.jsonLines {
+		app.output = formatJSON
+	}
+
+	for refreshes := 1; ; refreshes++ {
+		pollCtx, cancel := context.WithTimeout(ctx, app.timeout)
+		started := feed.Now()
+		rows, err := view(pollCtx)
+		feed.Observe(started, err)
+		cancel()
+
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		var changed bool
+
+		if err != nil {
+			fmt.Fprintf(app.stderr, "%s unable to refresh %s: %s\n", time.Now().Format(time.RFC3339), name, err.Error())
+		} else if err = app.refresh(options, watched, name, rows, refreshes == 1, terminal); err != nil {
+			return err
+		} else if current := fmt.Sprint(rows); current != last {
+			changed, last = true, current
+		}
+
+		if options.count > 0 && refreshes >= options.count {
+			return nil
+		}
+
+		if !feed.Wait(ctx, changed) {
+			return nil
+		}
+	}
+}
+
+func (app *app) refresh(options *watchOptions, watched []column, name string, rows [][]interface{}, first, terminal bool) error {
+	var (
+		now     = time.Now().UTC().Format(time.RFC3339)
+		stamped = make([][]interface{}, len(rows))
+	)
+
+	for i, row := range rows {
+		stamped[i] = append([]interface{}{name, now}, row...)
+	}
+
+	switch app.output {
+	case formatJSON:
+		encoder := json.NewEncoder(app.stdout)
+
+		for _, record := range records(watched, stamped) {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case formatYAML:
+		fmt.Fprintln(app.stdout, "---")
+
+		return printYAML(app.stdout, records(watched, stamped))
+	case formatCSV:
+		return printCSV(app.stdout, watched, stamped, first)
+	}
+
+	if terminal {
+		fmt.Fprint(app.stdout, clearScreen)
+	}
+
+	fmt.Fprintf(app.stdout, "%s every %s, %s\n\n", name, options.interval, now)
+
+	if err := printTable(app.stdout, watched[2:], rows); err != nil {
+		return err
+	}
+
+	if !terminal {
+		fmt.Fprintln(app.stdout)
+	}
+
+	return nil
+}
+
+func isTerminal(writer io.Writer) bool {
+	file, ok := writer.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatch(t *testing.T) {
+	var channelsJSON = `[{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, channelsJSON))
+
+	t.Run("tables", func(t *testing.T) {
+		var stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+
+		code := run([]string{"-config", "/nonexistent", "watch", "channels", "-interval", "1ms", "-count", "3"}, nil, stdout, stderr)
+
+		require.Equal(t, 0, code, stderr.String())
+		assert.Equal(t, 3, strings.Count(stdout.String(), "channels every 1ms"))
+		assert.Equal(t, 3, strings.Count(stdout.String(), "0x61C808D82A3Ac53231750daDc13c777b59310bD9"))
+		assert.NotContains(t, stdout.String(), clearScreen, "the screen is only cleared on a terminal")
+	})
+
+	t.Run("json lines", func(t *testing.T) {
+		var (
+			stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+			lines          = 0
+		)
+
+		code := run([]string{"-config", "/nonexistent", "watch", "channels", "-interval", "1ms", "-count", "2", "-json"}, nil, stdout, stderr)
+		require.Equal(t, 0, code, stderr.String())
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			var object map[string]interface{}
+
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &object))
+			assert.Equal(t, "channels", object["view"])
+			assert.Equal(t, "0x61C808D82A3Ac53231750daDc13c777b59310bD9", object["partner_address"])
+			assert.Equal(t, float64(35), object["total_deposit"])
+			assert.Contains(t, object, "time")
+
+			lines++
+		}
+
+		assert.Equal(t, 2, lines)
+	})
+
+	t.Run("failed refreshes are reported", func(t *testing.T) {
+		var stdout, stderr = &bytes.Buffer{}, &bytes.Buffer{}
+
+		code := run([]string{"-config", "/nonexistent", "watch", "pending", "-interval", "1ms", "-count", "2"}, nil, stdout, stderr)
+
+		require.Equal(t, 0, code)
+		assert.Equal(t, 2, strings.Count(stderr.String(), "unable to refresh pending"))
+	})
+}
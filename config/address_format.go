@@ -0,0 +1,83 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddressFormat selects how the addresses sent to a Raiden node are rendered, for the
+// proxies and older nodes matching paths case-sensitively.
+type AddressFormat int
+
+const (
+	// Checksummed addresses are the EIP-55 mixed-case hex strings the Raiden node
+	// sends, e.g. 0x2a65Aca4D5fC5B5C859090a6c34d164135398226.
+	Checksummed AddressFormat = iota
+	// Lowercase addresses are the all lowercase hex strings, e.g.
+	// 0x2a65aca4d5fc5b5c859090a6c34d164135398226.
+	Lowercase
+)
+
+// addressPattern matches the hex strings of addresses, and not longer hex strings
+// such as transaction hashes.
+var addressPattern = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+
+// Format returns the hex string of the address in the format.
+func (format AddressFormat) Format(address common.Address) string {
+	if format == Lowercase {
+		return strings.ToLower(address.Hex())
+	}
+
+	return address.Hex()
+}
+
+// Rewrite returns a copy of the text with the addresses it holds, 0x followed by 40
+// hex digits, rendered in the format, e.g. a path or a JSON body.
+func (format AddressFormat) Rewrite(text []byte) []byte {
+	return addressPattern.ReplaceAllFunc(text, func(match []byte) []byte {
+		return []byte(format.Format(common.HexToAddress(string(match))))
+	})
+}
+
+// Marshal returns the JSON encoding of the value with its addresses rendered in the
+// format, e.g. to print channels for tools expecting lowercase addresses.
+func (format AddressFormat) Marshal(value interface{}) ([]byte, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return format.Rewrite(data), nil
+}
+
+// String returns the name of the format, as in the address_format of a Profile.
+func (format AddressFormat) String() string {
+	if format == Lowercase {
+		return "lowercase"
+	}
+
+	return "checksummed"
+}
+
+// MarshalText returns the name of the format.
+func (format AddressFormat) MarshalText() ([]byte, error) {
+	return []byte(format.String()), nil
+}
+
+// UnmarshalText parses the name of a format, the empty name being Checksummed.
+func (format *AddressFormat) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "", "checksummed":
+		*format = Checksummed
+	case "lowercase":
+		*format = Lowercase
+	default:
+		return fmt.Errorf("unknown address format %q, expected checksummed or lowercase", string(text))
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddressFormat(t *testing.T) {
+	type testcase struct {
+		name            string
+		format          AddressFormat
+		expectedAddress string
+		expectedRewrite string
+		expectedText    string
+	}
+
+	var (
+		address = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		hash    = "0x5a7b3e6bbb4a1ed6a4ab0b7d2e0e7bfe8f3ab1d2a1c7e54a5f4bd5c0e3a2a1b7"
+		text    = `{"partner_address":"0x2a65aca4d5fc5b5c859090a6c34d164135398226","channel":"/api/v1/channels/0x2A65ACA4D5FC5B5C859090A6C34D164135398226","transaction_hash":"` + hash + `"}`
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:            "checksummed",
+			format:          Checksummed,
+			expectedAddress: "0x2a65Aca4D5fC5B5C859090a6c34d164135398226",
+			expectedRewrite: `{"partner_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","channel":"/api/v1/channels/0x2a65Aca4D5fC5B5C859090a6c34d164135398226","transaction_hash":"` + hash + `"}`,
+			expectedText:    `"checksummed"`,
+		},
+		testcase{
+			name:            "lowercase",
+			format:          Lowercase,
+			expectedAddress: "0x2a65aca4d5fc5b5c859090a6c34d164135398226",
+			expectedRewrite: `{"partner_address":"0x2a65aca4d5fc5b5c859090a6c34d164135398226","channel":"/api/v1/channels/0x2a65aca4d5fc5b5c859090a6c34d164135398226","transaction_hash":"` + hash + `"}`,
+			expectedText:    `"lowercase"`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var parsed AddressFormat
+
+			assert.Equal(t, tc.expectedAddress, tc.format.Format(address))
+			assert.Equal(t, tc.expectedRewrite, string(tc.format.Rewrite([]byte(text))), "hashes are left as is")
+
+			marshalled, err := tc.format.Marshal(map[string]common.Address{"partner_address": address})
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"partner_address":"`+tc.expectedAddress+`"}`, string(marshalled))
+
+			data, err := json.Marshal(tc.format)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedText, string(data))
+
+			require.NoError(t, json.Unmarshal(data, &parsed))
+			assert.Equal(t, tc.format, parsed)
+		})
+	}
+
+	var parsed AddressFormat
+
+	assert.EqualError(t, json.Unmarshal([]byte(`"upper"`), &parsed), `unknown address format "upper", expected checksummed or lowercase`)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BaseURL returns the URL the Raiden node API is mounted under, the Host followed by
+// the BasePath, without a trailing slash, e.g. "https://gateway.example.com/raiden/node-3".
+func (config *Config) BaseURL() string {
+	var basePath = strings.Trim(config.BasePath, "/")
+
+	if basePath == "" {
+		return strings.TrimSuffix(config.Host, "/")
+	}
+
+	return strings.TrimSuffix(config.Host, "/") + "/" + basePath
+}
+
+// APIURL returns the root of the endpoints of the Raiden node API of the APIVersion,
+// e.g. "https://gateway.example.com/raiden/node-3/api/v1", see BaseURL.
+func (config *Config) APIURL() string {
+	return config.BaseURL() + "/api/" + config.APIVersion
+}
+
+// SplitURL splits the URL of a Raiden node into the Host, the BasePath and, when the
+// URL is the root of its API, the APIVersion of a configuration, e.g.
+// "https://gateway.example.com/raiden/node-3/api/v1" into
+// "https://gateway.example.com", "/raiden/node-3" and "v1".
+func SplitURL(rawURL string) (host, basePath, apiVersion string, err error) {
+	var parsed *url.URL
+
+	if parsed, err = url.Parse(rawURL); err != nil {
+		return "", "", "", err
+	}
+
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return "", "", "", fmt.Errorf("invalid raiden node URL %q: not an absolute URL", rawURL)
+	}
+
+	basePath = strings.TrimSuffix(parsed.Path, "/")
+
+	if prefix, version, relative, ok := SplitAPIPath(basePath); ok && relative == "" {
+		basePath, apiVersion = prefix, version
+	}
+
+	parsed.Path, parsed.RawPath, parsed.RawQuery, parsed.Fragment = "", "", "", ""
+
+	return parsed.String(), basePath, apiVersion, nil
+}
+
+// SplitAPIPath splits the path of a URL of the Raiden node API, e.g.
+// "/raiden/node-3/api/v1/channels/0x…", into the base path the API is mounted under,
+// "/raiden/node-3", the version of the API, "v1", and the path relative to the root of
+// the API, "channels/0x…". The root is the last "api" segment followed by a version,
+// so that base paths may have "api" segments of their own, and ok is false for the
+// paths without one.
+func SplitAPIPath(path string) (basePath, version, relative string, ok bool) {
+	var segments = strings.Split(strings.Trim(path, "/"), "/")
+
+	for i := len(segments) - 2; i >= 0; i-- {
+		if segments[i] != "api" || !isVersion(segments[i+1]) {
+			continue
+		}
+
+		if i > 0 {
+			basePath = "/" + strings.Join(segments[:i], "/")
+		}
+
+		return basePath, segments[i+1], strings.Join(segments[i+2:], "/"), true
+	}
+
+	return "", "", "", false
+}
+
+// isVersion tells whether the path segment is a version of the API, e.g. "v1".
+func isVersion(segment string) bool {
+	if len(segment) < 2 || segment[0] != 'v' {
+		return false
+	}
+
+	for _, digit := range segment[1:] {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseURL(t *testing.T) {
+	type testcase struct {
+		name            string
+		config          *Config
+		expectedBaseURL string
+		expectedAPIURL  string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:            "no base path",
+			config:          &Config{Host: "http://localhost:5001", APIVersion: "v1"},
+			expectedBaseURL: "http://localhost:5001",
+			expectedAPIURL:  "http://localhost:5001/api/v1",
+		},
+		testcase{
+			name:            "base path",
+			config:          &Config{Host: "https://gateway.example.com", BasePath: "/raiden/node-3", APIVersion: "v1"},
+			expectedBaseURL: "https://gateway.example.com/raiden/node-3",
+			expectedAPIURL:  "https://gateway.example.com/raiden/node-3/api/v1",
+		},
+		testcase{
+			name:            "slashes",
+			config:          &Config{Host: "https://gateway.example.com/", BasePath: "raiden/node-3/", APIVersion: "v1"},
+			expectedBaseURL: "https://gateway.example.com/raiden/node-3",
+			expectedAPIURL:  "https://gateway.example.com/raiden/node-3/api/v1",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedBaseURL, tc.config.BaseURL())
+			assert.Equal(t, tc.expectedAPIURL, tc.config.APIURL())
+		})
+	}
+}
+
+func TestSplitURL(t *testing.T) {
+	host, basePath, apiVersion, err := SplitURL("https://gateway.example.com/raiden/node-3/api/v1")
+	require.NoError(t, err)
+	assert.Equal(t, "https://gateway.example.com", host)
+	assert.Equal(t, "/raiden/node-3", basePath)
+	assert.Equal(t, "v1", apiVersion)
+
+	host, basePath, apiVersion, err = SplitURL("https://gateway.example.com/raiden/node-3/")
+	require.NoError(t, err)
+	assert.Equal(t, "https://gateway.example.com", host)
+	assert.Equal(t, "/raiden/node-3", basePath)
+	assert.Empty(t, apiVersion)
+
+	host, basePath, _, err = SplitURL("http://localhost:5001")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:5001", host)
+	assert.Empty(t, basePath)
+
+	_, _, _, err = SplitURL("gateway.example.com/raiden")
+	assert.EqualError(t, err, `invalid raiden node URL "gateway.example.com/raiden": not an absolute URL`)
+}
+
+func TestSplitAPIPath(t *testing.T) {
+	type testcase struct {
+		name             string
+		path             string
+		expectedBasePath string
+		expectedVersion  string
+		expectedRelative string
+		expectedOk       bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:             "root",
+			path:             "/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			expectedVersion:  "v1",
+			expectedRelative: "channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			expectedOk:       true,
+		},
+		testcase{
+			name:             "base path",
+			path:             "/raiden/node-3/api/v1/payments",
+			expectedBasePath: "/raiden/node-3",
+			expectedVersion:  "v1",
+			expectedRelative: "payments",
+			expectedOk:       true,
+		},
+		testcase{
+			name:             "base path with an api segment",
+			path:             "/api/raiden/api/v2/address",
+			expectedBasePath: "/api/raiden",
+			expectedVersion:  "v2",
+			expectedRelative: "address",
+			expectedOk:       true,
+		},
+		testcase{
+			name:             "root of the api",
+			path:             "/raiden/api/v1/",
+			expectedBasePath: "/raiden",
+			expectedVersion:  "v1",
+			expectedOk:       true,
+		},
+		testcase{
+			name: "not an endpoint",
+			path: "/metrics",
+		},
+		testcase{
+			name: "no version",
+			path: "/api/channels",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			basePath, version, relative, ok := SplitAPIPath(tc.path)
+
+			assert.Equal(t, tc.expectedBasePath, basePath)
+			assert.Equal(t, tc.expectedVersion, version)
+			assert.Equal(t, tc.expectedRelative, relative)
+			assert.Equal(t, tc.expectedOk, ok)
+		})
+	}
+}
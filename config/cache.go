@@ -0,0 +1,75 @@
+package config
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Cache holds what the sub-clients of a configuration learn about the node and reuse,
+// e.g. the channels of channels.IndexOf and the token networks of the tokens, so that
+// every sub-client of a client reads the same state instead of keeping a copy of its
+// own that drifts. Entries are kept by token, for the sub-clients to invalidate what
+// they know of a token at once. A Cache is safe for concurrent use.
+type Cache struct {
+	mutex   sync.RWMutex
+	entries map[common.Address]map[string]interface{}
+}
+
+// NewCache creates an empty cache.
+func NewCache() *Cache {
+	return &Cache{
+		entries: make(map[common.Address]map[string]interface{}),
+	}
+}
+
+// Load returns the value stored at the key of the token.
+func (cache *Cache) Load(tokenAddress common.Address, key string) (interface{}, bool) {
+	cache.mutex.RLock()
+	defer cache.mutex.RUnlock()
+
+	value, ok := cache.entries[tokenAddress][key]
+
+	return value, ok
+}
+
+// Store stores the value at the key of the token, replacing the one stored before.
+func (cache *Cache) Store(tokenAddress common.Address, key string, value interface{}) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if cache.entries[tokenAddress] == nil {
+		cache.entries[tokenAddress] = make(map[string]interface{})
+	}
+
+	cache.entries[tokenAddress][key] = value
+}
+
+// Invalidate removes the entries of the token whose key starts with the prefix, every
+// entry of the token when it is empty, e.g. after the token network changed outside
+// of the client.
+func (cache *Cache) Invalidate(tokenAddress common.Address, prefix string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	if prefix == "" {
+		delete(cache.entries, tokenAddress)
+		return
+	}
+
+	for key := range cache.entries[tokenAddress] {
+		if strings.HasPrefix(key, prefix) {
+			delete(cache.entries[tokenAddress], key)
+		}
+	}
+}
+
+// InvalidateAll removes every entry of the cache, e.g. after the node was restored
+// from a backup.
+func (cache *Cache) InvalidateAll() {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+
+	cache.entries = make(map[common.Address]map[string]interface{})
+}
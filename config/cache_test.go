@@ -0,0 +1,60 @@
+package config
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCache(t *testing.T) {
+	var (
+		cache        = NewCache()
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		otherToken   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359")
+		group        sync.WaitGroup
+	)
+
+	for i := 0; i < 10; i++ {
+		group.Add(1)
+
+		go func(i int) {
+			defer group.Done()
+
+			cache.Store(tokenAddress, "channels/1", i)
+			cache.Load(tokenAddress, "channels/1")
+		}(i)
+	}
+
+	group.Wait()
+
+	_, ok := cache.Load(tokenAddress, "channels/1")
+	assert.True(t, ok)
+
+	cache.Store(tokenAddress, "channels/2", 2)
+	cache.Store(tokenAddress, "tokens/network", "network")
+	cache.Store(otherToken, "channels/1", 1)
+
+	cache.Invalidate(tokenAddress, "channels/")
+
+	_, ok = cache.Load(tokenAddress, "channels/2")
+	assert.False(t, ok)
+
+	value, ok := cache.Load(tokenAddress, "tokens/network")
+	assert.True(t, ok, "the other entries of the token are kept")
+	assert.Equal(t, "network", value)
+
+	cache.Invalidate(tokenAddress, "")
+
+	_, ok = cache.Load(tokenAddress, "tokens/network")
+	assert.False(t, ok)
+
+	_, ok = cache.Load(otherToken, "channels/1")
+	assert.True(t, ok, "the entries of the other tokens are kept")
+
+	cache.InvalidateAll()
+
+	_, ok = cache.Load(otherToken, "channels/1")
+	assert.False(t, ok)
+}
@@ -1,8 +1,219 @@
 package config
 
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DecodeMode selects how the JSON responses of a Raiden node are decoded.
+type DecodeMode int
+
+const (
+	// Lenient decoding tolerates unknown fields and nulls, leaving the zero value in
+	// place of a null, so that clients keep working against newer nodes.
+	Lenient DecodeMode = iota
+	// Strict decoding fails on unknown fields and on nulls in required fields, those
+	// without omitempty that are not pointers, to catch drift of the API early.
+	Strict
+)
+
+// Endpoint is a class of endpoints of the Raiden node API whose requests take about
+// as long to complete.
+type Endpoint string
+
+const (
+	// EndpointRead is every GET request, which the node answers right away.
+	EndpointRead Endpoint = "read"
+	// EndpointChannelOpen is opening a channel, which waits for the on-chain
+	// confirmation of the channel and of its deposit.
+	EndpointChannelOpen Endpoint = "channel_open"
+	// EndpointChannelUpdate is closing a channel or changing its deposit, which
+	// waits for an on-chain transaction.
+	EndpointChannelUpdate Endpoint = "channel_update"
+	// EndpointPayment is initiating a payment, which waits until the payment is
+	// completed or failed.
+	EndpointPayment Endpoint = "payment"
+	// EndpointConnection is joining or leaving a token network, which opens or
+	// closes several channels.
+	EndpointConnection Endpoint = "connection"
+	// EndpointTokenRegistration is registering a token, which deploys its token
+	// network.
+	EndpointTokenRegistration Endpoint = "token_registration"
+)
+
+// Doer sends a request to the Raiden node and returns its response.
+type Doer func(request *http.Request) (*http.Response, error)
+
+// Middleware wraps the sending of every request made by a client, to add behavior
+// such as retries, metrics or logging around it.
+type Middleware func(next Doer) Doer
+
+// SyncRetry configures the retries of the requests a Raiden node rejects because it
+// is still syncing with the chain, e.g. while it starts next to the services using
+// it. Rejected requests are sent again after a backoff growing from InitialBackoff
+// up to MaxBackoff, jittered, until Budget has elapsed since the first attempt, when
+// the last rejection is returned. The backoff defaults to a second growing up to 30
+// seconds. Requests rejected with 429 Too Many Requests by a rate limiting proxy in
+// front of the node are retried the same way, and the Retry-After of a rejection is
+// waited for in place of the backoff, the rejection being returned when it would
+// outlast the budget. The budget and the backoffs are measured on Clock, the system
+// time when nil.
+type SyncRetry struct {
+	Budget         time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Clock          clock.Clock
+}
+
+// AcceptedPolling configures the polling of the outcome of the requests a Raiden node,
+// or a proxy in front of it, answers with 202 Accepted, see raidenerrors.Pending. The
+// Location of the response is polled every Interval, a second when zero, or after
+// the Retry-After of the last response, until it answers with anything but 202
+// Accepted, which is then the response of the request, or Timeout has elapsed since
+// the first response, when the last *raidenerrors.Pending is returned. A zero
+// Timeout polls until the context of the request is done. The interval and the
+// timeout are measured on Clock, the system time when nil.
+type AcceptedPolling struct {
+	Interval time.Duration
+	Timeout  time.Duration
+	Clock    clock.Clock
+}
+
+// TokenDefaults are the options applied to every payment of a token, so that
+// applications paying in several tokens do not give them with every payment:
+//
+// LockTimeout is the expiration of the lock of the payments, in blocks, the node
+// choosing it when zero. MaxFee is the largest mediation fee a payment is estimated
+// to cost, payments.Simulator predicting the ones whose route costs more to fail.
+// IdentifierPrefix is put in the high 16 bits of the identifiers generated for the
+// payments given none, so that the payments of an application can be told apart in
+// the events of the node, see payments.IdentifierPrefix, the node generating the
+// identifiers when zero. Decimals overrides the decimals of the token of a Profile,
+// e.g. for a token whose contract reports the wrong ones. Rounding is how the parts of
+// the payments split evenly by payments.Splitter are rounded, down when empty.
+type TokenDefaults struct {
+	LockTimeout      int64            `json:"lock_timeout"`
+	MaxFee           *big.Int         `json:"max_fee"`
+	IdentifierPrefix uint16           `json:"identifier_prefix"`
+	Decimals         *int             `json:"decimals"`
+	Rounding         amounts.Rounding `json:"rounding,omitempty"`
+}
+
+// IdentifierSource generates the identifiers of the payments sent without one, e.g.
+// sequential or time ordered ones, for organizations whose identifiers must be
+// unique or ordered in their own way, see payments.RandomIdentifiers,
+// payments.NewSequentialIdentifiers and payments.NewSnowflakeIdentifiers. It must be
+// safe for concurrent use.
+type IdentifierSource interface {
+	NextIdentifier(ctx context.Context, tokenAddress common.Address) (int64, error)
+}
+
 // Config holds the needed information for a Raiden client to make API requests
 // to a Raiden node.
 type Config struct {
-	Host       string
+	Host string
+	// BasePath is the path the Raiden node API is mounted under on the Host, e.g.
+	// "/raiden/node-3" for a node behind a gateway serving it at
+	// https://gateway.example.com/raiden/node-3/api/v1, the root of the host when
+	// empty. Every URL of the client is built from it, see BaseURL and SplitURL.
+	BasePath   string
 	APIVersion string
+	DecodeMode DecodeMode
+	// NodeVersion is the version of the Raiden node, e.g. "0.100.3" or "1.1.1", the
+	// payloads of Raiden 1.x being decoded into the same types as the ones of 0.100.x.
+	// Payloads are decoded as 0.100.x ones when it is empty, see version.Detect.
+	NodeVersion string
+	// AddressFormat is how the addresses in the paths and bodies of the requests are
+	// rendered, checksummed when zero, e.g. Lowercase for a proxy matching lowercase
+	// paths only. The paths and bodies of raw calls are sent as given.
+	AddressFormat AddressFormat
+	// FieldAliases maps the fields of the payloads of a fork of the Raiden node that
+	// renamed them, by resource of the endpoint, e.g. "channels", from the name the
+	// fork sends to the name the sub-clients decode, whatever the NodeVersion, so
+	// that the client can be pointed at the fork without recompiling it. The aliases
+	// of util.DefaultFieldAliases are used when it is nil, an empty map applies none.
+	FieldAliases map[string]map[string]string
+	// Validation checks the responses of the node for values the API does not allow,
+	// see util.Validate. Responses are not checked when it is nil.
+	Validation *Validation
+	// RequestTimeout is the deadline given to requests whose context has none, so
+	// that calls made with context.Background do not hang against a wedged node. No
+	// deadline is applied when it is zero. See also EndpointTimeouts.
+	RequestTimeout time.Duration
+	// EndpointTimeouts overrides the RequestTimeout for the endpoints of a class,
+	// e.g. a minute for reads but ten for channel opens. A zero timeout applies no
+	// deadline to the class.
+	EndpointTimeouts map[Endpoint]time.Duration
+	// Middlewares wrap the sending of every request, the first one being the
+	// outermost, within the RequestTimeout deadline.
+	Middlewares []Middleware
+	// MetadataHeaders maps the fields attached to the context of a call, see
+	// meta.WithFields, to the request headers they are sent in, meta.DefaultHeaders
+	// being used when it is nil. An empty map sends no fields.
+	MetadataHeaders map[string]string
+	// Tenant is the tenant of the calls whose context carries none, see
+	// meta.WithTenant, sent in the MetadataHeaders and recorded with the payment
+	// identifiers and audit records of the client, so that a payment service shared
+	// by internal customers can tell their activity apart.
+	Tenant string
+	// MarkerHeader is the request header every call is marked in, e.g.
+	// meta.DefaultMarkerHeader, with the marker of its context, see meta.WithMarker,
+	// or one generated for the call, so that the call can be found in the logs of the
+	// node or of its proxy, see the nodelog package. Calls are not marked when it is
+	// empty.
+	MarkerHeader string
+	// SyncRetry retries the requests rejected while the node is syncing, every
+	// attempt getting its own RequestTimeout. Requests are not retried when it is nil.
+	SyncRetry *SyncRetry
+	// AcceptedPolling polls the outcome of the requests answered with 202 Accepted
+	// until they complete. Such requests return a *raidenerrors.Pending at once when
+	// it is nil.
+	AcceptedPolling *AcceptedPolling
+	// TokenDefaults are the options applied to the payments of a token, see
+	// Defaults.
+	TokenDefaults map[common.Address]*TokenDefaults
+	// IdentifierSource generates the identifiers of the payments given none, in place
+	// of the random ones carrying the IdentifierPrefix of their token, or the ones
+	// chosen by the node when the token has no prefix. The payments, their splits,
+	// the payments watched after a timeout and the rebalancing moves take their
+	// identifiers from it.
+	IdentifierSource IdentifierSource
+	// Cache is shared by the sub-clients created with the configuration, e.g. the
+	// channels of channels.IndexOf and the token networks of tokens.NewGetter, which
+	// don't share what they learn when it is nil. raidenclient.New gives the
+	// configuration of its client a cache of its own.
+	Cache *Cache
+	// Clock is the source of time of the schedulers, coordinators and maintenance
+	// routines created with the configuration, e.g. batch.NewExecutor, so that tests
+	// can move it forward with a clock.Manual instead of sleeping. The system time is
+	// used when it is nil.
+	Clock clock.Clock
+}
+
+// Time returns the Clock of the configuration, which may be nil, or the system time
+// when it has none.
+func (config *Config) Time() clock.Clock {
+	if config == nil {
+		return clock.Real()
+	}
+
+	return clock.Or(config.Clock)
+}
+
+// Defaults returns the options applied to the payments of the token, empty ones when
+// the configuration, which may be nil, has none for it.
+func (config *Config) Defaults(tokenAddress common.Address) *TokenDefaults {
+	if config != nil {
+		if defaults := config.TokenDefaults[tokenAddress]; defaults != nil {
+			return defaults
+		}
+	}
+
+	return &TokenDefaults{}
 }
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Keyring locates a secret in the keyring of the operating system, i.e. the macOS
+// keychain, read with security, or the Secret Service of Linux desktops, read with
+// secret-tool. The secret is stored beforehand with e.g.
+//
+//	security add-generic-password -s raidenctl -a mainnet -w
+//	secret-tool store --label raidenctl service raidenctl account mainnet
+type Keyring struct {
+	Service string `json:"service"`
+	Account string `json:"account"`
+}
+
+// keyringCommand returns the command printing the secret of the keyring on the
+// operating system.
+var keyringCommand = func(keyring *Keyring) ([]string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		return []string{"security", "find-generic-password", "-s", keyring.Service, "-a", keyring.Account, "-w"}, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		return []string{"secret-tool", "lookup", "service", keyring.Service, "account", keyring.Account}, nil
+	default:
+		return nil, fmt.Errorf("no keyring support on %s, use a secret command instead", runtime.GOOS)
+	}
+}
+
+// Resolve returns the credentials with the secret read from the keyring or the secret
+// command when either is set, the secret being the bearer token, or the password when
+// a username is set. Credentials without either are returned as they are.
+func (auth *Auth) Resolve(ctx context.Context) (*Auth, error) {
+	var (
+		err      error
+		command  []string
+		secret   string
+		resolved = *auth
+	)
+
+	switch {
+	case auth.Keyring != nil:
+		if command, err = keyringCommand(auth.Keyring); err != nil {
+			return nil, err
+		}
+	case len(auth.Command) > 0:
+		command = auth.Command
+	default:
+		return auth, nil
+	}
+
+	if secret, err = runSecretCommand(ctx, command); err != nil {
+		return nil, err
+	}
+
+	if resolved.Username != "" {
+		resolved.Password = secret
+	} else {
+		resolved.Token = secret
+	}
+
+	resolved.Keyring, resolved.Command = nil, nil
+
+	return &resolved, nil
+}
+
+// runSecretCommand runs the command and returns its output without the trailing
+// newline.
+func runSecretCommand(ctx context.Context, command []string) (string, error) {
+	var (
+		stdout bytes.Buffer
+		stderr bytes.Buffer
+		cmd    = exec.CommandContext(ctx, command[0], command[1:]...)
+	)
+
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	if err := cmd.Run(); err != nil {
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			return "", fmt.Errorf("unable to read secret with %s: %s: %s", command[0], err.Error(), message)
+		}
+
+		return "", fmt.Errorf("unable to read secret with %s: %s", command[0], err.Error())
+	}
+
+	secret := strings.TrimRight(stdout.String(), "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("unable to read secret with %s: empty secret", command[0])
+	}
+
+	return secret, nil
+}
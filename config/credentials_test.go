@@ -0,0 +1,98 @@
+package config
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthResolve(t *testing.T) {
+	defer func(original func(*Keyring) ([]string, error)) { keyringCommand = original }(keyringCommand)
+
+	keyringCommand = func(keyring *Keyring) ([]string, error) {
+		return []string{"echo", keyring.Service + "/" + keyring.Account}, nil
+	}
+
+	type testcase struct {
+		name          string
+		auth          *Auth
+		expectedAuth  *Auth
+		expectedError string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "plain text token",
+			auth:         &Auth{Token: "secret"},
+			expectedAuth: &Auth{Token: "secret"},
+		},
+		testcase{
+			name:         "token in the keyring",
+			auth:         &Auth{Keyring: &Keyring{Service: "raidenctl", Account: "mainnet"}},
+			expectedAuth: &Auth{Token: "raidenctl/mainnet"},
+		},
+		testcase{
+			name:         "password of a secret command",
+			auth:         &Auth{Username: "raiden", Command: []string{"sh", "-c", "printf 'secret\\n'"}},
+			expectedAuth: &Auth{Username: "raiden", Password: "secret"},
+		},
+		testcase{
+			name:          "failing secret command",
+			auth:          &Auth{Command: []string{"sh", "-c", "echo locked >&2; exit 1"}},
+			expectedError: "unable to read secret with sh: exit status 1: locked",
+		},
+		testcase{
+			name:          "empty secret",
+			auth:          &Auth{Command: []string{"true"}},
+			expectedError: "unable to read secret with true: empty secret",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			auth, err := tc.auth.Resolve(context.Background())
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAuth, auth)
+		})
+	}
+}
+
+func TestProfileHTTPClientSecretCommand(t *testing.T) {
+	var (
+		calls   int
+		profile = &Profile{Auth: &Auth{Command: []string{"echo", "secret"}}}
+		client  = profile.HTTPClient(&http.Client{Transport: roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+			calls++
+			assert.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+
+			return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: request}, nil
+		})})
+		failing = (&Profile{Auth: &Auth{Command: []string{"false"}}}).HTTPClient(&http.Client{})
+	)
+
+	for i := 0; i < 2; i++ {
+		response, err := client.Get("http://localhost:5001/api/v1/address")
+		require.NoError(t, err)
+		response.Body.Close()
+	}
+
+	assert.Equal(t, 2, calls)
+
+	_, err := failing.Get("http://localhost:5001/api/v1/address")
+	assert.Contains(t, err.Error(), "unable to read secret with false: exit status 1")
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
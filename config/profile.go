@@ -0,0 +1,301 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Auth holds the credentials sent to a Raiden node behind an authenticating proxy,
+// either a bearer token or a username and password for basic authentication.
+//
+// Rather than kept in plain text, the token or password can be read from the keyring
+// of the operating system, or printed by a secret command such as
+// ["pass", "show", "raiden/mainnet"], when the first request is made, see Resolve.
+type Auth struct {
+	Token    string   `json:"token"`
+	Username string   `json:"username"`
+	Password string   `json:"password"`
+	Keyring  *Keyring `json:"keyring"`
+	Command  []string `json:"command"`
+}
+
+// Profile is a named set of settings for a Raiden node, so that users of several
+// nodes, e.g. on a development chain, a testnet and mainnet, can switch between them
+// by name instead of editing their configuration.
+type Profile struct {
+	Name       string `json:"-"`
+	Host       string `json:"host"`
+	BasePath   string `json:"base_path"`
+	APIVersion string `json:"api_version"`
+	PFS        string `json:"pfs"`
+	Auth       *Auth  `json:"auth"`
+	// AllowedTokens and AllowedPartners restrict the tokens and partners that may be
+	// used by the profile, an empty list allows any.
+	AllowedTokens   []common.Address `json:"allowed_tokens"`
+	AllowedPartners []common.Address `json:"allowed_partners"`
+	// DeniedPartners are partners that may never be used by the profile, even when
+	// they are allowed, e.g. to comply with a sanctions list.
+	DeniedPartners []common.Address `json:"denied_partners"`
+	// Tokens holds the symbol and decimals of the tokens of the profile, which the
+	// node does not know of, for amounts to be shown in whole tokens.
+	Tokens map[common.Address]*amounts.Token `json:"tokens"`
+	// TokenDefaults are the options applied to the payments of a token with the
+	// profile, see Config.TokenDefaults.
+	TokenDefaults map[common.Address]*TokenDefaults `json:"token_defaults"`
+	// LabelsFile is the file the labels of the channels of the profile are stored in,
+	// next to the configuration file when empty.
+	LabelsFile string `json:"labels_file"`
+	// FieldAliases are the fields renamed by the fork of the Raiden node the profile
+	// is for, see Config.FieldAliases.
+	FieldAliases map[string]map[string]string `json:"field_aliases"`
+	// AddressFormat is how the addresses sent to the node of the profile are
+	// rendered, "checksummed" or "lowercase", see Config.AddressFormat.
+	AddressFormat AddressFormat `json:"address_format"`
+	// Hosts and DNS are the addresses the host of the node is dialed at and the DNS
+	// server looking it up, see Resolver.
+	Hosts map[string]string `json:"hosts"`
+	DNS   string            `json:"dns"`
+}
+
+// Config returns the configuration of the Raiden node of the profile.
+func (profile *Profile) Config() *Config {
+	return &Config{
+		Host:          profile.Host,
+		BasePath:      profile.BasePath,
+		APIVersion:    profile.APIVersion,
+		FieldAliases:  profile.FieldAliases,
+		TokenDefaults: profile.TokenDefaults,
+		AddressFormat: profile.AddressFormat,
+	}
+}
+
+// HTTPClient returns a copy of the http client sending the credentials of the
+// profile with every request, or the http client itself when the profile has none.
+// With Hosts or DNS, the copy dials the node as they configure through the
+// Transport of a Resolver, in place of the transport of the http client.
+func (profile *Profile) HTTPClient(httpClient *http.Client) *http.Client {
+	var resolves = len(profile.Hosts) > 0 || profile.DNS != ""
+
+	if profile.Auth == nil && !resolves {
+		return httpClient
+	}
+
+	var (
+		client    = *httpClient
+		transport = client.Transport
+	)
+
+	if resolves {
+		transport = (&Resolver{Hosts: profile.Hosts, DNS: profile.DNS}).Transport()
+	}
+
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if profile.Auth != nil {
+		transport = &authTransport{auth: profile.Auth, transport: transport}
+	}
+
+	client.Transport = transport
+
+	return &client
+}
+
+// AllowsToken reports whether the token may be used with the profile.
+func (profile *Profile) AllowsToken(tokenAddress common.Address) bool {
+	return allows(profile.AllowedTokens, tokenAddress)
+}
+
+// Token returns the symbol and decimals of the token, the decimals of its
+// TokenDefaults overriding the ones of its Tokens, or nil when the profile holds
+// neither, see amounts.Token.Format.
+func (profile *Profile) Token(tokenAddress common.Address) *amounts.Token {
+	var (
+		token    = profile.Tokens[tokenAddress]
+		defaults = profile.TokenDefaults[tokenAddress]
+	)
+
+	if defaults == nil || defaults.Decimals == nil {
+		return token
+	}
+
+	overridden := &amounts.Token{Decimals: *defaults.Decimals}
+	if token != nil {
+		overridden.Symbol = token.Symbol
+	}
+
+	return overridden
+}
+
+// AllowsPartner reports whether the partner, or target of a payment, may be used with
+// the profile, i.e. it is allowed and not denied.
+func (profile *Profile) AllowsPartner(partnerAddress common.Address) bool {
+	for _, denied := range profile.DeniedPartners {
+		if denied == partnerAddress {
+			return false
+		}
+	}
+
+	return allows(profile.AllowedPartners, partnerAddress)
+}
+
+func allows(allowlist []common.Address, address common.Address) bool {
+	if len(allowlist) == 0 {
+		return true
+	}
+
+	for _, allowed := range allowlist {
+		if allowed == address {
+			return true
+		}
+	}
+
+	return false
+}
+
+// File is the configuration file holding the profiles, e.g.
+//
+//	{
+//	  "default_profile": "dev",
+//	  "profiles": {
+//	    "dev": {"host": "http://localhost:5001"},
+//	    "mainnet": {
+//	      "host": "https://raiden.example.com",
+//	      "auth": {"keyring": {"service": "raidenctl", "account": "mainnet"}},
+//	      "allowed_tokens": ["0x6B175474E89094C44Da98b954EedeAC495271d0F"],
+//	      "tokens": {"0x6B175474E89094C44Da98b954EedeAC495271d0F": {"symbol": "DAI", "decimals": 18}}
+//	    }
+//	  }
+//	}
+type File struct {
+	DefaultProfile string              `json:"default_profile"`
+	Profiles       map[string]*Profile `json:"profiles"`
+}
+
+// DefaultPath returns the path of the configuration file, given by the RAIDEN_CONFIG
+// environment variable or ~/.raidenctl.json otherwise.
+func DefaultPath() string {
+	if path := os.Getenv("RAIDEN_CONFIG"); path != "" {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ".raidenctl.json"
+	}
+
+	return filepath.Join(home, ".raidenctl.json")
+}
+
+// LoadProfile will read the named profile from the configuration file at the default
+// path, or its default profile when no name is given.
+func LoadProfile(name string) (*Profile, error) {
+	return LoadProfileFile(DefaultPath(), name)
+}
+
+// LoadProfileFile will read the named profile from the configuration file at path, or
+// its default profile when no name is given. A missing file, or a file without a
+// default profile, results in an empty profile unless a profile was explicitly asked
+// for.
+func LoadProfileFile(path, name string) (*Profile, error) {
+	var (
+		err      error
+		contents []byte
+		file     = &File{}
+	)
+
+	if contents, err = ioutil.ReadFile(path); err != nil {
+		if os.IsNotExist(err) && name == "" {
+			return &Profile{}, nil
+		}
+
+		return nil, err
+	}
+
+	if err = json.Unmarshal(contents, file); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", path, err.Error())
+	}
+
+	if name == "" {
+		name = file.DefaultProfile
+	}
+
+	if name == "" {
+		return &Profile{}, nil
+	}
+
+	profile, ok := file.Profiles[name]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("unknown profile %s in %s", name, path)
+	}
+
+	profile.Name = name
+
+	return profile, nil
+}
+
+// authTransport adds the credentials of a profile to every request, resolved once
+// when the first request is made.
+type authTransport struct {
+	auth      *Auth
+	transport http.RoundTripper
+
+	mutex    sync.Mutex
+	resolved *Auth
+}
+
+func (transport *authTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var (
+		err    error
+		auth   *Auth
+		header = make(http.Header, len(request.Header)+1)
+	)
+
+	if auth, err = transport.resolve(request.Context()); err != nil {
+		return nil, err
+	}
+
+	for key, values := range request.Header {
+		header[key] = values
+	}
+
+	request = request.WithContext(request.Context())
+	request.Header = header
+
+	switch {
+	case auth.Token != "":
+		request.Header.Set("Authorization", "Bearer "+auth.Token)
+	case auth.Username != "":
+		request.SetBasicAuth(auth.Username, auth.Password)
+	}
+
+	return transport.transport.RoundTrip(request)
+}
+
+// resolve returns the credentials of the profile, reading their secret the first
+// time. Failures are not remembered, so that e.g. a locked keyring can be unlocked
+// before the next request.
+func (transport *authTransport) resolve(ctx context.Context) (*Auth, error) {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+
+	if transport.resolved == nil {
+		var err error
+
+		if transport.resolved, err = transport.auth.Resolve(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	return transport.resolved, nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Resolver dials the Raiden node at the addresses configured for its host name, for
+// containers and split-horizon DNS where the name of the certificate of the node does
+// not resolve, or not to the node: the requests keep the host name of the node, which
+// its certificate is checked against, while the connections go to the address found.
+//
+// Hosts maps host names to the address they are dialed at, an IP address or a host,
+// with a port replacing the one of the request, e.g. {"raiden.example.com":
+// "10.0.0.5"}. The other host names are looked up by the DNS server, e.g.
+// "10.0.0.2:53", the port defaulting to 53, or by Resolver when given, with the
+// resolver of the system when both are empty.
+type Resolver struct {
+	Hosts    map[string]string
+	DNS      string
+	Resolver *net.Resolver
+}
+
+// DialContext connects to the address on the named network like net.Dialer, with
+// the host of the address mapped and looked up as configured.
+func (resolver *Resolver) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	var dialer = &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Resolver:  resolver.resolver(),
+	}
+
+	if host, port, err := net.SplitHostPort(address); err == nil {
+		if mapped, ok := resolver.Hosts[host]; ok {
+			address = net.JoinHostPort(mapped, port)
+
+			if _, _, err = net.SplitHostPort(mapped); err == nil {
+				address = mapped
+			}
+		}
+	}
+
+	return dialer.DialContext(ctx, network, address)
+}
+
+// Transport returns a transport configured like http.DefaultTransport, dialing as
+// configured.
+func (resolver *Resolver) Transport() *http.Transport {
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           resolver.DialContext,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// resolver returns the resolver looking up the host names which are not mapped, nil
+// for the resolver of the system.
+func (resolver *Resolver) resolver() *net.Resolver {
+	if resolver.Resolver != nil || resolver.DNS == "" {
+		return resolver.Resolver
+	}
+
+	var server = resolver.DNS
+
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			var dialer net.Dialer
+
+			return dialer.DialContext(ctx, network, server)
+		},
+	}
+}
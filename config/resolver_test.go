@@ -0,0 +1,151 @@
+package config
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleResolver() {
+	var httpClient = &http.Client{
+		Transport: (&Resolver{Hosts: map[string]string{"raiden.example.com": "10.0.0.5"}}).Transport(),
+	}
+
+	// the request goes to 10.0.0.5, the certificate of the node being checked
+	// against raiden.example.com
+	response, err := httpClient.Get("https://raiden.example.com/api/v1/address")
+	if err != nil {
+		panic(fmt.Sprintf("unable to reach the raiden node: %s", err.Error()))
+	}
+
+	response.Body.Close()
+}
+
+// serveDNS answers the A queries sent to the returned address with 127.0.0.1, and
+// the other queries with no answer, until the connection is closed.
+func serveDNS(t *testing.T) net.PacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() {
+		var buffer = make([]byte, 512)
+
+		for {
+			n, from, err := conn.ReadFrom(buffer)
+			if err != nil {
+				return
+			}
+
+			var (
+				query    = buffer[:n]
+				question = query[12:]
+				end      = 0
+			)
+
+			for question[end] != 0 {
+				end += int(question[end]) + 1
+			}
+
+			question = question[:end+5]
+
+			response := append([]byte{}, query[:2]...)
+			response = append(response, 0x81, 0x80, 0, 1, 0, 0, 0, 0, 0, 0)
+			response = append(response, question...)
+
+			if binary.BigEndian.Uint16(question[end+1:]) == 1 {
+				response[7] = 1
+				response = append(response, 0xc0, 0x0c, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 127, 0, 0, 1)
+			}
+
+			conn.WriteTo(response, from)
+		}
+	}()
+
+	return conn
+}
+
+func TestResolver(t *testing.T) {
+	type testcase struct {
+		name     string
+		host     string
+		resolver func(port string) *Resolver
+	}
+
+	var (
+		dns    = serveDNS(t)
+		hosts  = make(chan string, 1)
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hosts <- r.Host
+		}))
+	)
+	defer server.Close()
+	defer dns.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	testcases := []testcase{
+		testcase{
+			name: "mapped host",
+			host: "raiden.example.com:" + serverURL.Port(),
+			resolver: func(port string) *Resolver {
+				return &Resolver{Hosts: map[string]string{"raiden.example.com": "127.0.0.1"}}
+			},
+		},
+		testcase{
+			name: "mapped host and port",
+			host: "raiden.example.com:5001",
+			resolver: func(port string) *Resolver {
+				return &Resolver{Hosts: map[string]string{"raiden.example.com": "127.0.0.1:" + port}}
+			},
+		},
+		testcase{
+			name: "dns server",
+			host: "raiden.test.:" + serverURL.Port(),
+			resolver: func(port string) *Resolver {
+				return &Resolver{DNS: dns.LocalAddr().String()}
+			},
+		},
+		testcase{
+			name: "unmapped host",
+			host: "127.0.0.1:" + serverURL.Port(),
+			resolver: func(port string) *Resolver {
+				return &Resolver{Hosts: map[string]string{"raiden.example.com": "10.0.0.5"}}
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var httpClient = &http.Client{Transport: tc.resolver(serverURL.Port()).Transport()}
+
+			request, err := http.NewRequest("GET", "http://"+tc.host+"/api/v1/address", nil)
+			require.NoError(t, err)
+
+			response, err := httpClient.Do(request.WithContext(context.Background()))
+			require.NoError(t, err)
+			response.Body.Close()
+
+			// the request keeps the host of the node
+			assert.Equal(t, tc.host, <-hosts)
+		})
+	}
+
+	t.Run("profile", func(t *testing.T) {
+		var profile = &Profile{Hosts: map[string]string{"raiden.example.com": "127.0.0.1"}, Auth: &Auth{Token: "secret"}}
+
+		response, err := profile.HTTPClient(http.DefaultClient).Get("http://raiden.example.com:" + serverURL.Port())
+		require.NoError(t, err)
+		response.Body.Close()
+
+		assert.Equal(t, "raiden.example.com:"+serverURL.Port(), <-hosts)
+	})
+}
@@ -0,0 +1,24 @@
+package config
+
+// Drift is a value of a response of the Raiden node that the API does not allow, e.g.
+// a negative amount, a zero address in a required field or an unknown state, found
+// in the Field of the payload, e.g. "[0].balance", of the response to the request to
+// Path. Drift means that the node has a bug or speaks another version of the API
+// than the client expects.
+type Drift struct {
+	Resource string
+	Path     string
+	Field    string
+	Value    string
+	Reason   string
+}
+
+// Validation configures the checks of the responses of the Raiden node for drift,
+// before they are decoded: OnDrift, when not nil, is called with every drift found,
+// e.g. to log it or count it, and the calls returning drift fail with a
+// *util.DriftError when Reject is set, rather than handing the impossible values on
+// to the application.
+type Validation struct {
+	OnDrift func(drift *Drift)
+	Reject  bool
+}
@@ -4,21 +4,24 @@ import (
 	"net/http"
 
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
 )
 
 var (
-	_ Lister = &Client{}
-	_ Leaver = &Client{}
-	_ Joiner = &Client{}
+	_ Lister         = &Client{}
+	_ Leaver         = &Client{}
+	_ Joiner         = &Client{}
+	_ util.RawCaller = &Client{}
 )
 
 // NewClient creates a new Connections client that will be able to List all Open
 // connections, Join and Leave connections for a Raiden node.
 func NewClient(config *config.Config, httpClient *http.Client) *Client {
 	return &Client{
-		Lister: NewLister(config, httpClient),
-		Leaver: NewLeaver(config, httpClient),
-		Joiner: NewJoiner(config, httpClient),
+		Lister:    NewLister(config, httpClient),
+		Leaver:    NewLeaver(config, httpClient),
+		Joiner:    NewJoiner(config, httpClient),
+		RawCaller: util.NewRawCaller(config, httpClient),
 	}
 }
 
@@ -27,4 +30,5 @@ type Client struct {
 	Lister
 	Leaver
 	Joiner
+	util.RawCaller
 }
@@ -1,9 +1,85 @@
 package connections
 
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+)
+
+type connection struct {
+	Funds       json.Number `json:"funds"`
+	SumDeposits json.Number `json:"sum_deposits"`
+	Channels    int64       `json:"channels"`
+}
+
+func (connection *connection) toConnection() (*Connection, error) {
+	var (
+		err         error
+		funds       *big.Int
+		sumDeposits *big.Int
+	)
+
+	if funds, err = amounts.Parse(connection.Funds); err != nil {
+		return nil, err
+	}
+
+	if sumDeposits, err = amounts.Parse(connection.SumDeposits); err != nil {
+		return nil, err
+	}
+
+	return &Connection{
+		Funds:       funds,
+		SumDeposits: sumDeposits,
+		Channels:    connection.Channels,
+	}, nil
+}
+
 // Connection represents a high level information about the Funds, Total deposits
 // and numbers of channels for a given network.
 type Connection struct {
-	Funds       int64 `json:"funds"`
-	SumDeposits int64 `json:"sum_deposits"`
-	Channels    int64 `json:"channels"`
+	Funds       *big.Int `json:"funds"`
+	SumDeposits *big.Int `json:"sum_deposits"`
+	Channels    int64    `json:"channels"`
+}
+
+// MarshalJSON encodes the connection in the format of the Raiden node API, with the
+// amounts as JSON numbers.
+func (connection Connection) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fromConnection(&connection))
+}
+
+// UnmarshalJSON decodes a connection in the format of the Raiden node API, which is
+// the format connections are marshalled in.
+func (connection *Connection) UnmarshalJSON(data []byte) error {
+	var (
+		err     error
+		decoded *Connection
+	)
+
+	if decoded, err = decodeConnection(data); err != nil {
+		return err
+	}
+
+	*connection = *decoded
+
+	return nil
+}
+
+func decodeConnection(data []byte) (*Connection, error) {
+	var response = &connection{}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return nil, err
+	}
+
+	return response.toConnection()
+}
+
+func fromConnection(value *Connection) *connection {
+	return &connection{
+		Funds:       amounts.Number(value.Funds),
+		SumDeposits: amounts.Number(value.SumDeposits),
+		Channels:    value.Channels,
+	}
 }
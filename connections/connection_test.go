@@ -0,0 +1,24 @@
+package connections
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConnectionJSONRoundTrip(t *testing.T) {
+	var (
+		data       = `{"funds":100000000000000000000,"sum_deposits":67,"channels":3}`
+		connection = &Connection{}
+	)
+
+	require.NoError(t, json.Unmarshal([]byte(data), connection))
+	assert.Equal(t, "100000000000000000000", connection.Funds.String())
+
+	encoded, err := json.Marshal(connection)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, data, string(encoded))
+}
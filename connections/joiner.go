@@ -5,23 +5,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"net/url"
-	"strings"
 
+	"github.com/cpurta/go-raiden-client/amounts"
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
 	"github.com/cpurta/go-raiden-client/util"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 type joinRequest struct {
-	Funds int64 `json:"funds"`
+	Funds json.Number `json:"funds"`
 }
 
 // Joiner is an interface to allow for a Raiden node to join a new token network
 // with a given number of funds.
 type Joiner interface {
-	Join(ctx context.Context, tokenAddress common.Address, funds int64) error
+	Join(ctx context.Context, tokenAddress common.Address, funds *big.Int) error
 }
 
 // NewJoiner will create a default joiner that will allow access to join a new token
@@ -40,34 +42,27 @@ type defaultJoiner struct {
 }
 
 // Join will join a new token network given a token network address and a given number of funds.
-func (joiner *defaultJoiner) Join(ctx context.Context, tokenAddress common.Address, funds int64) error {
+func (joiner *defaultJoiner) Join(ctx context.Context, tokenAddress common.Address, funds *big.Int) error {
 	var (
 		err          error
 		requestURL   *url.URL
 		request      *http.Request
 		response     *http.Response
-		requestBody  []byte
 		responseBody []byte
 		joinRequest  = &joinRequest{
-			Funds: funds,
+			Funds: amounts.Number(funds),
 		}
 	)
 
-	if requestURL, err = joiner.getRequestURL(tokenAddress); err != nil {
+	if requestURL, err = joiner.baseClient.Endpoint("connections/%s", tokenAddress.Hex()); err != nil {
 		return err
 	}
 
-	if requestBody, err = json.Marshal(joinRequest); err != nil {
+	if request, err = joiner.baseClient.NewRequest(ctx, "PUT", requestURL, joinRequest); err != nil {
 		return err
 	}
 
-	if request, err = http.NewRequest("PUT", requestURL.String(), strings.NewReader(string(requestBody))); err != nil {
-		return err
-	}
-
-	request = request.WithContext(ctx)
-
-	if response, err = joiner.baseClient.HTTPClient.Do(request); err != nil {
+	if response, err = joiner.baseClient.Do(request); err != nil {
 		return err
 	}
 
@@ -78,22 +73,12 @@ func (joiner *defaultJoiner) Join(ctx context.Context, tokenAddress common.Addre
 	}
 
 	if response.StatusCode != http.StatusNoContent {
-		return fmt.Errorf("recieved %d status code: %s", response.StatusCode, string(responseBody))
-	}
-
-	return nil
-}
-
-func (joiner *defaultJoiner) getRequestURL(tokenAddress common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/connections/%s", joiner.baseClient.Config.Host, joiner.baseClient.Config.APIVersion, tokenAddress.Hex())
-		requestURL *url.URL
-	)
+		if apiErr := raidenerrors.Parse(response.StatusCode, responseBody); apiErr != nil {
+			return apiErr
+		}
 
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
+		return raidenerrors.New(response.StatusCode, fmt.Errorf("recieved %d status code: %s", response.StatusCode, string(responseBody)))
 	}
 
-	return requestURL, nil
+	return nil
 }
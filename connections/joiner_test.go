@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"os"
 	"testing"
@@ -29,7 +30,7 @@ func ExampleJoiner() {
 
 	connClient = NewClient(config, http.DefaultClient)
 
-	if err = connClient.Join(context.Background(), tokenAddress, funds); err != nil {
+	if err = connClient.Join(context.Background(), tokenAddress, big.NewInt(funds)); err != nil {
 		panic(fmt.Sprintf("unable to join connection: %s", err.Error()))
 	}
 
@@ -84,6 +85,20 @@ func TestCloser(t *testing.T) {
 			},
 			expectedError: errors.New("recieved 500 status code: "),
 		},
+		testcase{
+			name: "error described by the node",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder(
+					"PUT",
+					"http://localhost:5001/api/v1/connections/0x2a65Aca4D5fC5B5C859090a6c34d164135398226",
+					httpmock.NewStringResponder(
+						http.StatusPaymentRequired,
+						`{"errors":"Not enough balance to deposit. 1337 tokens available"}`,
+					),
+				)
+			},
+			expectedError: errors.New("raiden node error 402: Not enough balance to deposit. 1337 tokens available"),
+		},
 		testcase{
 			name: "unable to make http request",
 			prepHTTPMock: func() {
@@ -108,7 +123,7 @@ func TestCloser(t *testing.T) {
 
 			tc.prepHTTPMock()
 
-			err = joiner.Join(ctx, tokenAddress, 1337)
+			err = joiner.Join(ctx, tokenAddress, big.NewInt(1337))
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
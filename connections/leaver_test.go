@@ -123,6 +123,7 @@ func TestLeaver(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, addresses)
 				return
 			}
 
@@ -2,8 +2,6 @@ package connections
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -39,51 +37,29 @@ type defaultLister struct {
 func (lister *defaultLister) List(ctx context.Context) (Connections, error) {
 	var (
 		err         error
-		channels    = make(map[string]*Connection)
+		channels    = make(map[string]*connection)
 		connections = make(map[common.Address]*Connection)
 
 		requestURL *url.URL
-		request    *http.Request
-		response   *http.Response
 	)
 
-	if requestURL, err = lister.getRequestURL(); err != nil {
+	if requestURL, err = lister.baseClient.Endpoint("connections"); err != nil {
 		return nil, err
 	}
 
-	if request, err = http.NewRequest("GET", requestURL.String(), nil); err != nil {
+	if err = lister.baseClient.Call(ctx, "GET", requestURL, nil, &channels); err != nil {
 		return nil, err
 	}
 
-	request = request.WithContext(ctx)
+	for tokenAddress, connectionResponse := range channels {
+		var connection *Connection
 
-	if response, err = lister.baseClient.HTTPClient.Do(request); err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&channels); err != nil {
-		return nil, err
-	}
+		if connection, err = connectionResponse.toConnection(); err != nil {
+			return nil, err
+		}
 
-	for tokenAddress, connection := range channels {
 		connections[common.HexToAddress(tokenAddress)] = connection
 	}
 
 	return connections, nil
 }
-
-func (lister *defaultLister) getRequestURL() (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/connections", lister.baseClient.Config.Host, lister.baseClient.Config.APIVersion)
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
-	}
-
-	return requestURL, nil
-}
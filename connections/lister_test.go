@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"os"
 	"testing"
@@ -72,13 +73,13 @@ func TestLister(t *testing.T) {
 			},
 			expectedConnections: Connections{
 				common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"): &Connection{
-					Funds:       int64(100),
-					SumDeposits: int64(67),
+					Funds:       big.NewInt(100),
+					SumDeposits: big.NewInt(67),
 					Channels:    int64(3),
 				},
 				common.HexToAddress("0x0f114A1E9Db192502E7856309cc899952b3db1ED"): &Connection{
-					Funds:       int64(49),
-					SumDeposits: int64(31),
+					Funds:       big.NewInt(49),
+					SumDeposits: big.NewInt(31),
 					Channels:    int64(1),
 				},
 			},
@@ -128,6 +129,7 @@ func TestLister(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, connections)
 				return
 			}
 
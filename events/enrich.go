@@ -0,0 +1,222 @@
+package events
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// EnrichedEvent is a payment event along with what is needed to show it to a person:
+// the channel it was made in as the node lists it, the symbol and decimals of its
+// token and the label of the channel. Channel, Token and Label are nil when they are
+// not known.
+type EnrichedEvent struct {
+	*PaymentEvent
+	Channel *channels.Channel
+	Token   *amounts.Token
+	Label   *channels.Label
+}
+
+// Counterparty returns the address the payment was sent to or received from, the
+// target of sent payments and the initiator of received ones.
+func (event *EnrichedEvent) Counterparty() common.Address {
+	if event.Event != nil && event.EventName == payments.EventPaymentReceivedSuccess {
+		return event.Initiator
+	}
+
+	if event.Event != nil && event.Target != (common.Address{}) {
+		return event.Target
+	}
+
+	return event.PartnerAddress
+}
+
+// CounterpartyName returns the name of the label of the channel when the
+// counterparty is the partner of the channel, or its abbreviated address otherwise.
+func (event *EnrichedEvent) CounterpartyName() string {
+	var counterparty = event.Counterparty()
+
+	if event.Label != nil && event.Label.Name != "" && counterparty == event.PartnerAddress {
+		return event.Label.Name
+	}
+
+	return util.ShortAddress(counterparty)
+}
+
+// FormattedAmount returns the amount of the payment in whole tokens with the symbol
+// of the token, see amounts.Token.Format.
+func (event *EnrichedEvent) FormattedAmount() string {
+	var amount *big.Int
+
+	if event.Event != nil {
+		amount = event.Amount
+	}
+
+	return event.Token.Format(amount)
+}
+
+// String returns a description of the event for notifications, e.g. "received 1.5 DAI
+// from Binance hot wallet" or "failed to send to 0x61C8…".
+func (event *EnrichedEvent) String() string {
+	var name string
+
+	if event.Event != nil {
+		name = event.EventName
+	}
+
+	switch name {
+	case payments.EventPaymentSentSuccess:
+		return "sent " + event.FormattedAmount() + " to " + event.CounterpartyName()
+	case payments.EventPaymentSentFailed:
+		return "failed to send to " + event.CounterpartyName()
+	case payments.EventPaymentReceivedSuccess:
+		return "received " + event.FormattedAmount() + " from " + event.CounterpartyName()
+	default:
+		return event.PaymentEvent.String()
+	}
+}
+
+// EnrichedSubscription delivers the enriched events of a subscription until its
+// context is done, when both channels are closed, see Subscription. Failing to enrich
+// an event is delivered on Errors, the event being delivered with what could be
+// resolved.
+type EnrichedSubscription struct {
+	Events <-chan *EnrichedEvent
+	Errors <-chan error
+
+	subscription *Subscription
+}
+
+// ResumeToken returns the position of the subscription after the events delivered on
+// Events so far, see Subscription.ResumeToken. It may cover the event being enriched,
+// the token is exact once Events is closed and drained.
+func (subscription *EnrichedSubscription) ResumeToken() ResumeToken {
+	return subscription.subscription.ResumeToken()
+}
+
+// Enricher enriches the payment events of a Raiden node with the channels the node
+// lists, the tokens of the application, e.g. the ones of a config.Profile, and the
+// labels of a channels.LabelStore, either of which may be nil.
+type Enricher struct {
+	subscriber    Subscriber
+	channelLister channels.Lister
+	tokens        map[common.Address]*amounts.Token
+	labels        *channels.LabelStore
+}
+
+// NewEnricher creates an enricher of the payment events of the Raiden node of the
+// configuration with the tokens and labels.
+func NewEnricher(config *config.Config, tokens map[common.Address]*amounts.Token, labels *channels.LabelStore, httpClient *http.Client) *Enricher {
+	return &Enricher{
+		subscriber:    NewSubscriber(config, httpClient),
+		channelLister: channels.NewLister(config, httpClient),
+		tokens:        tokens,
+		labels:        labels,
+	}
+}
+
+// Enrich returns the event with its channel, token and label. The channels of the
+// token are listed for every event, so that the channel is the one the node has at
+// the time. The event is returned with what could be resolved along with the first
+// error, if any.
+func (enricher *Enricher) Enrich(ctx context.Context, event *PaymentEvent) (*EnrichedEvent, error) {
+	var (
+		firstErr error
+		enriched = &EnrichedEvent{
+			PaymentEvent: event,
+			Token:        enricher.tokens[event.TokenAddress],
+		}
+	)
+
+	channelList, err := enricher.channelLister.ListToken(ctx, event.TokenAddress)
+	if err != nil {
+		firstErr = err
+	}
+
+	for _, channel := range channelList {
+		if channel.PartnerAddress == event.PartnerAddress {
+			enriched.Channel = channel
+			break
+		}
+	}
+
+	if enricher.labels != nil {
+		if enriched.Label, err = enricher.labels.Get(event.TokenAddress, event.PartnerAddress); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return enriched, firstErr
+}
+
+// SubscribePayments subscribes to the payment events of the node as configured by
+// the options, see Subscriber, delivering them enriched.
+func (enricher *Enricher) SubscribePayments(ctx context.Context, options *SubscribeOptions) *EnrichedSubscription {
+	var (
+		subscription = enricher.subscriber.SubscribePayments(ctx, options)
+		buffer       int
+	)
+
+	if options != nil {
+		buffer = options.Buffer
+	}
+
+	var (
+		events   = make(chan *EnrichedEvent, buffer)
+		errs     = make(chan error, 1)
+		upstream = subscription.Errors
+	)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		for {
+			select {
+			case err, ok := <-upstream:
+				if !ok {
+					upstream = nil
+					continue
+				}
+
+				deliverError(errs, err)
+			case event, ok := <-subscription.Events:
+				if !ok {
+					return
+				}
+
+				enriched, err := enricher.Enrich(ctx, event)
+				if err != nil && ctx.Err() == nil {
+					deliverError(errs, err)
+				}
+
+				select {
+				case events <- enriched:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return &EnrichedSubscription{
+		Events:       events,
+		Errors:       errs,
+		subscription: subscription,
+	}
+}
+
+// deliverError delivers the error unless nobody is receiving the errors.
+func deliverError(errs chan<- error, err error) {
+	select {
+	case errs <- err:
+	default:
+	}
+}
@@ -0,0 +1,168 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleEnricher() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokens = map[common.Address]*amounts.Token{
+			common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F"): &amounts.Token{Symbol: "DAI", Decimals: 18},
+		}
+		enricher     = NewEnricher(config, tokens, channels.NewLabelStore(storage.NewFileStore("labels.json")), http.DefaultClient)
+		ctx, cancel  = context.WithTimeout(context.Background(), time.Minute)
+		subscription = enricher.SubscribePayments(ctx, &SubscribeOptions{Since: time.Now()})
+	)
+
+	defer cancel()
+
+	for event := range subscription.Events {
+		fmt.Println(event)
+	}
+}
+
+func TestEnrichedEvent(t *testing.T) {
+	var (
+		partner = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		other   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		dai     = &amounts.Token{Symbol: "DAI", Decimals: 18}
+		label   = &channels.Label{Name: "Binance hot wallet"}
+	)
+
+	type testcase struct {
+		name     string
+		event    *payments.Event
+		token    *amounts.Token
+		label    *channels.Label
+		expected string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:     "received from the labelled partner",
+			event:    &payments.Event{EventName: payments.EventPaymentReceivedSuccess, Amount: big.NewInt(1500000000000000000), Initiator: partner},
+			token:    dai,
+			label:    label,
+			expected: "received 1.5 DAI from Binance hot wallet",
+		},
+		testcase{
+			name:     "sent through the partner",
+			event:    &payments.Event{EventName: payments.EventPaymentSentSuccess, Amount: big.NewInt(2000000000000000000), Target: other},
+			token:    dai,
+			label:    label,
+			expected: "sent 2 DAI to 0x2a65…",
+		},
+		testcase{
+			name:     "sent in an unknown token",
+			event:    &payments.Event{EventName: payments.EventPaymentSentSuccess, Amount: big.NewInt(200), Target: partner},
+			expected: "sent 200 to 0x61C8…",
+		},
+		testcase{
+			name:     "failed",
+			event:    &payments.Event{EventName: payments.EventPaymentSentFailed, Target: partner},
+			label:    label,
+			expected: "failed to send to Binance hot wallet",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			event := &EnrichedEvent{
+				PaymentEvent: &PaymentEvent{PartnerAddress: partner, Event: tc.event},
+				Token:        tc.token,
+				Label:        tc.label,
+			}
+
+			assert.Equal(t, tc.expected, event.String())
+		})
+	}
+}
+
+func TestEnricher(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partner      = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		tokens       = map[common.Address]*amounts.Token{tokenAddress: &amounts.Token{Symbol: "TTT", Decimals: 2}}
+		labels       = channels.NewLabelStore(storage.NewMemoryStore())
+		enricher     = NewEnricher(config, tokens, labels, http.DefaultClient)
+	)
+
+	require.NoError(t, labels.Set(tokenAddress, partner, &channels.Label{Name: "shop"}))
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":90,"state":"opened"}]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":90,"state":"opened"}]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK, `[{"event":"EventPaymentReceivedSuccess","amount":150,"initiator":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","identifier":1,"log_time":"2018-10-30T07:03:52Z"}]`))
+
+	t.Run("subscription", func(t *testing.T) {
+		var (
+			ctx, cancel  = context.WithCancel(context.Background())
+			subscription = enricher.SubscribePayments(ctx, &SubscribeOptions{Interval: time.Millisecond})
+		)
+
+		defer cancel()
+
+		event := <-subscription.Events
+
+		require.NotNil(t, event.Channel)
+		assert.Equal(t, int64(1), event.Channel.ChannelIdentifier)
+		assert.Equal(t, big.NewInt(90), event.Channel.Balance)
+		assert.Equal(t, "shop", event.Label.Name)
+		assert.Equal(t, "received 1.5 TTT from shop", event.String())
+
+		cancel()
+
+		for range subscription.Events {
+		}
+
+		assert.NotEmpty(t, subscription.ResumeToken())
+	})
+
+	t.Run("channel of an unknown partner", func(t *testing.T) {
+		event, err := enricher.Enrich(context.Background(), &PaymentEvent{
+			TokenAddress:   tokenAddress,
+			PartnerAddress: common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"),
+			Event:          &payments.Event{EventName: payments.EventPaymentSentFailed},
+		})
+
+		require.NoError(t, err)
+		assert.Nil(t, event.Channel)
+		assert.Nil(t, event.Label)
+		assert.Equal(t, tokens[tokenAddress], event.Token)
+	})
+
+	t.Run("node unreachable", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusInternalServerError, `{"errors":"internal error"}`))
+
+		event, err := enricher.Enrich(context.Background(), &PaymentEvent{TokenAddress: tokenAddress, PartnerAddress: partner, Event: &payments.Event{}})
+
+		assert.Error(t, err)
+		assert.Nil(t, event.Channel)
+		assert.Equal(t, "shop", event.Label.Name)
+	})
+}
@@ -0,0 +1,23 @@
+//go:build go1.21
+// +build go1.21
+
+package events
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer, logging the event along with the token and
+// partner of the channel it was logged for, see payments.Event.LogValue.
+func (event PaymentEvent) LogValue() slog.Value {
+	var attrs = []slog.Attr{
+		slog.String("token_address", event.TokenAddress.Hex()),
+		slog.String("partner_address", event.PartnerAddress.Hex()),
+	}
+
+	if event.Event != nil {
+		if group := event.Event.LogValue(); group.Kind() == slog.KindGroup {
+			attrs = append(attrs, group.Group()...)
+		}
+	}
+
+	return slog.GroupValue(attrs...)
+}
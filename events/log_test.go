@@ -0,0 +1,31 @@
+//go:build go1.21
+// +build go1.21
+
+package events
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentEventLogValue(t *testing.T) {
+	var (
+		event = &PaymentEvent{
+			TokenAddress:   common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+			PartnerAddress: common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+			Event:          &payments.Event{EventName: payments.EventPaymentReceivedSuccess, Amount: big.NewInt(5), Identifier: 7},
+		}
+		keys []string
+	)
+
+	for _, attr := range event.LogValue().Group() {
+		keys = append(keys, attr.Key)
+	}
+
+	assert.Equal(t, []string{"token_address", "partner_address", "event", "identifier", "amount", "log_time"}, keys)
+	assert.Equal(t, "EventPaymentReceivedSuccess 7 amount 5 at 0001-01-01T00:00:00Z token 0xEA67… partner 0x61C8…", event.String())
+}
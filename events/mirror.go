@@ -0,0 +1,266 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// mirrorPrefix is the prefix of the keys of the mirrored events in a storage.Store.
+const mirrorPrefix = "events/payments/"
+
+// mirrorTimeFormat is the fixed width format of the log times in the keys of the
+// mirrored events, so that the keys sort in the order of the events.
+const mirrorTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// mirroredEvent is a payment event as kept in a storage.Store, with its log time at
+// full precision.
+type mirroredEvent struct {
+	TokenAddress   common.Address  `json:"token_address"`
+	PartnerAddress common.Address  `json:"partner_address"`
+	LogTime        time.Time       `json:"log_time"`
+	Event          *payments.Event `json:"event"`
+}
+
+// ReplayOptions configures a replay of the mirrored events. Only events logged after
+// Since and, when it is not zero, at or before Until are delivered. A replay given
+// the ResumeToken of a previous subscription, live or replayed, delivers the events
+// that followed the ones it delivered instead, ignoring Since.
+type ReplayOptions struct {
+	Since       time.Time
+	Until       time.Time
+	ResumeToken ResumeToken
+	Buffer      int
+}
+
+// Mirror keeps the payment events of the node in a storage.Store, e.g. SQLite with
+// storage.NewSQLiteStore, so that a consumer recovering from downtime replays them
+// through a Subscription like the live events, even those the node no longer knows.
+// The subscriptions of the mirror are the ones of its subscriber, recording every
+// event before delivering it.
+type Mirror struct {
+	subscriber Subscriber
+	store      storage.Store
+}
+
+// NewMirror creates a mirror of the events of the subscriber into the store.
+func NewMirror(subscriber Subscriber, store storage.Store) *Mirror {
+	return &Mirror{
+		subscriber: subscriber,
+		store:      store,
+	}
+}
+
+// SubscribePayments subscribes to the payment events of the node as configured by
+// the options, see Subscriber, recording the events in the store before delivering
+// them. Failing to record an event is delivered on Errors, the event being delivered
+// anyway.
+func (mirror *Mirror) SubscribePayments(ctx context.Context, options *SubscribeOptions) *Subscription {
+	var (
+		subscription = mirror.subscriber.SubscribePayments(ctx, options)
+		buffer       int
+	)
+
+	if options != nil {
+		buffer = options.Buffer
+	}
+
+	var (
+		events   = make(chan *PaymentEvent, buffer)
+		errs     = make(chan error, 1)
+		upstream = subscription.Errors
+	)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		for {
+			select {
+			case err, ok := <-upstream:
+				if !ok {
+					upstream = nil
+					continue
+				}
+
+				deliverError(errs, err)
+			case event, ok := <-subscription.Events:
+				if !ok {
+					return
+				}
+
+				if err := mirror.Record(event); err != nil {
+					deliverError(errs, err)
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return &Subscription{
+		Events: events,
+		Errors: errs,
+		cursor: subscription.cursor,
+	}
+}
+
+// Record will keep the event in the store, recording an event again replacing it.
+func (mirror *Mirror) Record(event *PaymentEvent) error {
+	var key = counterparty{event.TokenAddress, event.PartnerAddress}
+
+	value, err := json.Marshal(&mirroredEvent{
+		TokenAddress:   event.TokenAddress,
+		PartnerAddress: event.PartnerAddress,
+		LogTime:        event.LogTime,
+		Event:          event.Event,
+	})
+	if err != nil {
+		return err
+	}
+
+	return mirror.store.Put(mirrorPrefix+event.LogTime.UTC().Format(mirrorTimeFormat)+"/"+eventID(key, event.Event), value)
+}
+
+// PaymentEvents will return the mirrored payment events of the token with every
+// partner, oldest first, so that the mirror is the payments.EventSource of a
+// payments.NewStatusResolver.
+func (mirror *Mirror) PaymentEvents(tokenAddress common.Address) ([]*payments.Event, error) {
+	var paymentEvents = make([]*payments.Event, 0)
+
+	entries, err := mirror.store.List(mirrorPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		var mirrored = &mirroredEvent{}
+
+		if err = json.Unmarshal(entry.Value, mirrored); err != nil || mirrored.Event == nil || mirrored.TokenAddress != tokenAddress {
+			continue
+		}
+
+		mirrored.Event.LogTime = mirrored.LogTime
+		paymentEvents = append(paymentEvents, mirrored.Event)
+	}
+
+	return paymentEvents, nil
+}
+
+// Replay will deliver the mirrored events selected by the options, oldest first,
+// closing both channels of the subscription once they are delivered or its context
+// is done. The ResumeToken of the subscription, once Events is drained, resumes a
+// live subscription right after the replayed events.
+func (mirror *Mirror) Replay(ctx context.Context, options *ReplayOptions) *Subscription {
+	var (
+		since   time.Time
+		until   time.Time
+		token   ResumeToken
+		buffer  int
+		seen    = make(map[string]bool)
+		errs    = make(chan error, 1)
+		tracked = &cursor{}
+	)
+
+	if options != nil {
+		since = options.Since
+		until = options.Until
+		token = options.ResumeToken
+		buffer = options.Buffer
+	}
+
+	var events = make(chan *PaymentEvent, buffer)
+
+	tracked.current = position{Since: since}
+
+	if token != "" {
+		resumed, err := token.position()
+		if err != nil {
+			errs <- err
+			close(errs)
+			close(events)
+
+			return &Subscription{Events: events, Errors: errs, cursor: tracked}
+		}
+
+		tracked.current = *resumed
+		since = resumed.Since.Add(-time.Nanosecond)
+
+		for _, id := range resumed.Seen {
+			seen[id] = true
+		}
+	}
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		entries, err := mirror.store.List(mirrorPrefix)
+		if err != nil {
+			deliverError(errs, err)
+			return
+		}
+
+		for _, entry := range entries {
+			var (
+				mirrored = &mirroredEvent{}
+				id       string
+			)
+
+			if err = json.Unmarshal(entry.Value, mirrored); err != nil || mirrored.Event == nil {
+				continue
+			}
+
+			mirrored.Event.LogTime = mirrored.LogTime
+			id = eventID(counterparty{mirrored.TokenAddress, mirrored.PartnerAddress}, mirrored.Event)
+
+			if !mirrored.LogTime.After(since) || seen[id] {
+				continue
+			}
+
+			if !until.IsZero() && mirrored.LogTime.After(until) {
+				return
+			}
+
+			select {
+			case events <- &PaymentEvent{TokenAddress: mirrored.TokenAddress, PartnerAddress: mirrored.PartnerAddress, Event: mirrored.Event}:
+				tracked.delivered(id, mirrored.LogTime)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return &Subscription{
+		Events: events,
+		Errors: errs,
+		cursor: tracked,
+	}
+}
+
+// MirrorRetentionPolicy returns the retention of the events kept in a storage.Store
+// by a Mirror, the events logged more than maxAge ago being deleted, see
+// storage.Collector.
+func MirrorRetentionPolicy(maxAge time.Duration) *storage.Policy {
+	return &storage.Policy{
+		Prefix: mirrorPrefix,
+		MaxAge: maxAge,
+		Time: func(entry *storage.Entry) (time.Time, bool) {
+			var mirrored = &mirroredEvent{}
+
+			if err := json.Unmarshal(entry.Value, mirrored); err != nil {
+				return time.Time{}, false
+			}
+
+			return mirrored.LogTime, true
+		},
+	}
+}
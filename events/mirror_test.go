@@ -0,0 +1,148 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleMirror() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		mirror = NewMirror(NewSubscriber(config, http.DefaultClient), storage.NewMemoryStore())
+		since  = time.Now().Add(-24 * time.Hour)
+	)
+
+	// the events of the last day, as recorded while the consumer was down
+	replay := mirror.Replay(context.Background(), &ReplayOptions{Since: since})
+
+	for event := range replay.Events {
+		fmt.Println("missed:", event)
+	}
+
+	// then the live events that follow
+	subscription := mirror.SubscribePayments(context.Background(), &SubscribeOptions{ResumeToken: replay.ResumeToken()})
+
+	for event := range subscription.Events {
+		fmt.Println("payment event:", event)
+	}
+}
+
+func TestMirror(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		store       = storage.NewMemoryStore()
+		mirror      = NewMirror(NewSubscriber(config, http.DefaultClient), store)
+		ctx, cancel = context.WithCancel(context.Background())
+		replayed    = func(subscription *Subscription) []int64 {
+			var identifiers = make([]int64, 0)
+
+			for event := range subscription.Events {
+				identifiers = append(identifiers, event.Identifier)
+			}
+
+			return identifiers
+		}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[
+		{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","state":"opened"}
+	]`))
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(http.StatusOK, `[
+		{"event":"EventPaymentSentSuccess","amount":5,"identifier":1,"target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","log_time":"2018-10-30T07:03:52.193Z"},
+		{"event":"EventPaymentReceivedSuccess","amount":7,"identifier":2,"initiator":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","log_time":"2018-10-30T07:04:52.193Z"},
+		{"event":"EventPaymentReceivedSuccess","amount":9,"identifier":3,"initiator":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","log_time":"2018-10-30T07:04:52.193Z"},
+		{"event":"EventPaymentSentFailed","identifier":4,"log_time":"2018-10-30T07:05:52.193Z"}
+	]`))
+
+	subscription := mirror.SubscribePayments(ctx, &SubscribeOptions{Interval: time.Millisecond})
+
+	live := make([]*PaymentEvent, 0)
+	for len(live) < 4 {
+		live = append(live, <-subscription.Events)
+	}
+
+	cancel()
+
+	for range subscription.Events {
+	}
+
+	liveToken := subscription.ResumeToken()
+
+	entries, err := store.List("events/")
+	require.NoError(t, err)
+	assert.Len(t, entries, 4)
+
+	t.Run("every event", func(t *testing.T) {
+		var replay = mirror.Replay(context.Background(), nil)
+
+		first := <-replay.Events
+		assert.Equal(t, live[0].TokenAddress, first.TokenAddress)
+		assert.Equal(t, live[0].PartnerAddress, first.PartnerAddress)
+		assert.True(t, live[0].Event.Equal(first.Event), "the event is replayed as it was delivered")
+		assert.Equal(t, []int64{2, 3, 4}, replayed(replay))
+	})
+
+	t.Run("time range", func(t *testing.T) {
+		var (
+			since = time.Date(2018, 10, 30, 7, 3, 52, 193000000, time.UTC)
+			until = time.Date(2018, 10, 30, 7, 4, 52, 193000000, time.UTC)
+		)
+
+		assert.Equal(t, []int64{2, 3}, replayed(mirror.Replay(context.Background(), &ReplayOptions{Since: since, Until: until})))
+	})
+
+	t.Run("resumed", func(t *testing.T) {
+		var replay = mirror.Replay(context.Background(), &ReplayOptions{Until: time.Date(2018, 10, 30, 7, 4, 0, 0, time.UTC)})
+
+		assert.Equal(t, []int64{1}, replayed(replay))
+		assert.Equal(t, []int64{2, 3, 4}, replayed(mirror.Replay(context.Background(), &ReplayOptions{ResumeToken: replay.ResumeToken()})))
+		assert.Empty(t, replayed(mirror.Replay(context.Background(), &ReplayOptions{ResumeToken: liveToken})), "the live subscription delivered every event")
+	})
+
+	t.Run("invalid resume token", func(t *testing.T) {
+		var replay = mirror.Replay(context.Background(), &ReplayOptions{ResumeToken: "not a token"})
+
+		assert.Equal(t, ErrInvalidResumeToken, <-replay.Errors)
+		assert.Empty(t, replayed(replay))
+	})
+
+	t.Run("payment events of a token", func(t *testing.T) {
+		paymentEvents, err := mirror.PaymentEvents(common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"))
+		require.NoError(t, err)
+		require.Len(t, paymentEvents, 4)
+		assert.True(t, live[0].Event.Equal(paymentEvents[0]))
+
+		paymentEvents, err = mirror.PaymentEvents(common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"))
+		require.NoError(t, err)
+		assert.Empty(t, paymentEvents)
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		var collector = storage.NewCollector(store, MirrorRetentionPolicy(time.Hour))
+
+		collection, err := collector.Collect(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 4, collection.Results[0].Deleted)
+		assert.Empty(t, replayed(mirror.Replay(context.Background(), nil)))
+	})
+}
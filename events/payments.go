@@ -0,0 +1,286 @@
+// Package events streams the activity of a Raiden node, polling its API and
+// delivering every new event exactly once over a channel.
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/changefeed"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultInterval is the time between two polls of the Raiden node when no interval
+// is given.
+const DefaultInterval = 2 * time.Second
+
+// PaymentEvent is a payment event along with the channel it was made in.
+type PaymentEvent struct {
+	TokenAddress   common.Address
+	PartnerAddress common.Address
+	*payments.Event
+}
+
+// String returns a concise description of the event for logs, see payments.Event,
+// with the token and partner of the channel it was logged for.
+func (event PaymentEvent) String() string {
+	var description = "payment event"
+
+	if event.Event != nil {
+		description = event.Event.String()
+	}
+
+	return description + " token " + util.ShortAddress(event.TokenAddress) + " partner " + util.ShortAddress(event.PartnerAddress)
+}
+
+// SubscribeOptions configures a subscription. Only events logged after Since are
+// delivered, a zero Since delivering every event the node still knows of. A
+// subscription given the ResumeToken of a previous one delivers the events that
+// followed the ones the previous subscription delivered instead, ignoring Since. The
+// node is polled every Interval while new events come in, the interval growing up to
+// MaxInterval while there are none or while the polls are slower than SlowResponse
+// or failing with a 5xx or 429 status, see changefeed.Options. The intervals are
+// waited for on Clock, the system time when nil.
+type SubscribeOptions struct {
+	Interval     time.Duration
+	MaxInterval  time.Duration
+	SlowResponse time.Duration
+	Since        time.Time
+	ResumeToken  ResumeToken
+	Buffer       int
+	Clock        clock.Clock
+}
+
+// Subscription delivers the events of a subscription until its context is done, when
+// both channels are closed. Errors of single polls are delivered on Errors, without
+// ending the subscription, and dropped when nobody is receiving them.
+type Subscription struct {
+	Events <-chan *PaymentEvent
+	Errors <-chan error
+
+	cursor *cursor
+}
+
+// ResumeToken returns the position of the subscription after the events delivered on
+// Events so far, to resume from in a new subscription, see SubscribeOptions. Once
+// Events is closed and drained, the token covers every event of the subscription.
+// Subscriptions not made by a Subscriber have no position and return an empty token.
+func (subscription *Subscription) ResumeToken() ResumeToken {
+	if subscription.cursor == nil {
+		return ""
+	}
+
+	return subscription.cursor.token()
+}
+
+// Subscriber is a generic interface to subscribe to the payment events of a Raiden
+// node.
+type Subscriber interface {
+	SubscribePayments(ctx context.Context, options *SubscribeOptions) *Subscription
+}
+
+// NewSubscriber creates a new default payment event subscriber given a Raiden node
+// configuration and an http client.
+func NewSubscriber(config *config.Config, httpClient *http.Client) Subscriber {
+	return &defaultSubscriber{
+		channelLister: channels.NewLister(config, httpClient),
+		paymentLister: payments.NewLister(config, httpClient),
+	}
+}
+
+type defaultSubscriber struct {
+	channelLister channels.Lister
+	paymentLister payments.Lister
+}
+
+type counterparty struct {
+	tokenAddress   common.Address
+	partnerAddress common.Address
+}
+
+// SubscribePayments will poll the payment events of every channel of the node, the
+// channels being listed again at each poll so that new channels are picked up.
+func (subscriber *defaultSubscriber) SubscribePayments(ctx context.Context, options *SubscribeOptions) *Subscription {
+	var (
+		interval     = DefaultInterval
+		maxInterval  time.Duration
+		slowResponse time.Duration
+		since        time.Time
+		token        ResumeToken
+		buffer       int
+		pace         clock.Clock
+	)
+
+	if options != nil {
+		maxInterval = options.MaxInterval
+		slowResponse = options.SlowResponse
+		pace = options.Clock
+		since = options.Since
+		token = options.ResumeToken
+		buffer = options.Buffer
+
+		if options.Interval > 0 {
+			interval = options.Interval
+		}
+	}
+
+	var (
+		events  = make(chan *PaymentEvent, buffer)
+		errs    = make(chan error, 1)
+		seen    = make(map[string]time.Time)
+		tracked = &cursor{current: position{Since: since}}
+		latest  time.Time
+	)
+
+	if token != "" {
+		resumed, err := token.position()
+		if err != nil {
+			// the subscription ends right away rather than delivering from the start
+			errs <- err
+			close(errs)
+			close(events)
+
+			return &Subscription{Events: events, Errors: errs, cursor: tracked}
+		}
+
+		// the events logged at the time of the position that were not delivered yet
+		// are delivered, the others are known from the token
+		tracked.current = *resumed
+		since = resumed.Since.Add(-time.Nanosecond)
+
+		for _, id := range resumed.Seen {
+			seen[id] = resumed.Since
+		}
+	}
+
+	go func() {
+		var feed = changefeed.New(&changefeed.Options{MinInterval: interval, MaxInterval: maxInterval, SlowResponse: slowResponse, Clock: pace})
+
+		defer close(events)
+		defer close(errs)
+
+		for {
+			started := feed.Now()
+
+			newEvents, err := subscriber.poll(ctx, since, seen)
+			feed.Observe(started, err)
+
+			if err != nil && ctx.Err() == nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+
+			for _, event := range newEvents {
+				select {
+				case events <- event:
+					tracked.delivered(eventID(counterparty{event.TokenAddress, event.PartnerAddress}, event.Event), event.LogTime)
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// an event logged while a channel was listed before the others is only
+			// listed by the next poll, so since lags a poll behind the latest event
+			// and only the events at or after it are remembered
+			if err == nil {
+				if latest.After(since) {
+					since = latest.Add(-time.Nanosecond)
+					forget(seen, since)
+				}
+
+				if n := len(newEvents); n > 0 && newEvents[n-1].LogTime.After(latest) {
+					latest = newEvents[n-1].LogTime
+				}
+			}
+
+			if !feed.Wait(ctx, len(newEvents) > 0) {
+				return
+			}
+		}
+	}()
+
+	return &Subscription{
+		Events: events,
+		Errors: errs,
+		cursor: tracked,
+	}
+}
+
+// poll lists the payment events of every channel and returns the ones that were not
+// seen yet, oldest first. Events of the channels that could be listed are returned
+// even when listing another channel failed.
+func (subscriber *defaultSubscriber) poll(ctx context.Context, since time.Time, seen map[string]time.Time) ([]*PaymentEvent, error) {
+	var (
+		err            error
+		firstErr       error
+		allChannels    []*channels.Channel
+		counterparties = make(map[counterparty]bool)
+		newEvents      = make([]*PaymentEvent, 0)
+	)
+
+	if allChannels, err = subscriber.channelLister.ListAll(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, channel := range allChannels {
+		var (
+			paymentEvents []*payments.Event
+			key           = counterparty{tokenAddress: channel.TokenAddress, partnerAddress: channel.PartnerAddress}
+		)
+
+		if counterparties[key] {
+			continue
+		}
+
+		counterparties[key] = true
+
+		if paymentEvents, err = subscriber.paymentLister.List(ctx, key.tokenAddress, key.partnerAddress); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		for _, event := range paymentEvents {
+			id := eventID(key, event)
+
+			if _, ok := seen[id]; ok || !event.LogTime.After(since) {
+				continue
+			}
+
+			seen[id] = event.LogTime
+			newEvents = append(newEvents, &PaymentEvent{
+				TokenAddress:   key.tokenAddress,
+				PartnerAddress: key.partnerAddress,
+				Event:          event,
+			})
+		}
+	}
+
+	SortByTime(newEvents)
+
+	return newEvents, firstErr
+}
+
+// forget removes the events logged before since, which poll skips anyway.
+func forget(seen map[string]time.Time, since time.Time) {
+	for id, logTime := range seen {
+		if !logTime.After(since) {
+			delete(seen, id)
+		}
+	}
+}
+
+func eventID(key counterparty, event *payments.Event) string {
+	return fmt.Sprintf("%s/%s/%s/%d/%d", key.tokenAddress.Hex(), key.partnerAddress.Hex(), event.EventName, event.Identifier, event.LogTime.UnixNano())
+}
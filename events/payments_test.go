@@ -0,0 +1,146 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSubscriber() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		subscriber   = NewSubscriber(config, http.DefaultClient)
+		ctx, cancel  = context.WithTimeout(context.Background(), time.Minute)
+		subscription = subscriber.SubscribePayments(ctx, &SubscribeOptions{Since: time.Now()})
+	)
+
+	defer cancel()
+
+	for event := range subscription.Events {
+		fmt.Printf("%s: payment %d of %d with %s\n", event.EventName, event.Identifier, event.Amount, event.PartnerAddress.Hex())
+	}
+}
+
+func TestSubscriber(t *testing.T) {
+	var (
+		unreachable = &config.Config{
+			Host:       "http://unreachable:5001",
+			APIVersion: "v1",
+		}
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		paymentsURL = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		mutex       sync.Mutex
+		eventsJSON  = `{"event":"EventPaymentSentSuccess","amount":5,"identifier":1,"log_time":"2018-10-30T07:03:52Z"},{"event":"EventPaymentReceivedSuccess","amount":7,"identifier":2,"log_time":"2018-10-30T07:04:52Z"}`
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[
+		{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","state":"opened"},
+		{"channel_identifier":2,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","state":"closed"}
+	]`))
+
+	httpmock.RegisterResponder("GET", paymentsURL, func(request *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return httpmock.NewStringResponse(http.StatusOK, "["+eventsJSON+"]"), nil
+	})
+
+	t.Run("every event is delivered once, oldest first", func(t *testing.T) {
+		var (
+			ctx, cancel  = context.WithCancel(context.Background())
+			subscription = NewSubscriber(config, http.DefaultClient).SubscribePayments(ctx, &SubscribeOptions{Interval: time.Millisecond})
+			identifiers  = make([]int64, 0)
+		)
+
+		defer cancel()
+
+		for len(identifiers) < 2 {
+			identifiers = append(identifiers, (<-subscription.Events).Identifier)
+		}
+
+		mutex.Lock()
+		eventsJSON = `{"event":"EventPaymentSentFailed","identifier":3,"log_time":"2018-10-30T07:05:52Z"},` + eventsJSON
+		mutex.Unlock()
+
+		event := <-subscription.Events
+		assert.Equal(t, int64(3), event.Identifier)
+		assert.Equal(t, "0x61C808D82A3Ac53231750daDc13c777b59310bD9", event.PartnerAddress.Hex())
+
+		assert.Equal(t, []int64{1, 2}, identifiers)
+
+		select {
+		case event = <-subscription.Events:
+			t.Fatalf("unexpected duplicate event %d", event.Identifier)
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		cancel()
+
+		for range subscription.Events {
+		}
+	})
+
+	t.Run("events before since are skipped", func(t *testing.T) {
+		var (
+			ctx, cancel  = context.WithCancel(context.Background())
+			since, _     = time.Parse(time.RFC3339, "2018-10-30T07:04:00Z")
+			subscription = NewSubscriber(config, http.DefaultClient).SubscribePayments(ctx, &SubscribeOptions{Interval: time.Millisecond, Since: since})
+		)
+
+		defer cancel()
+
+		assert.Equal(t, int64(2), (<-subscription.Events).Identifier)
+		assert.Equal(t, int64(3), (<-subscription.Events).Identifier)
+	})
+
+	t.Run("errors do not end the subscription", func(t *testing.T) {
+		var (
+			ctx, cancel  = context.WithCancel(context.Background())
+			subscription = NewSubscriber(unreachable, http.DefaultClient).SubscribePayments(ctx, &SubscribeOptions{Interval: time.Millisecond})
+		)
+
+		require.Error(t, <-subscription.Errors)
+		require.Error(t, <-subscription.Errors)
+
+		cancel()
+
+		_, open := <-subscription.Events
+		assert.False(t, open)
+	})
+}
+
+func TestForget(t *testing.T) {
+	var (
+		first  = time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC)
+		second = first.Add(time.Minute)
+		seen   = map[string]time.Time{
+			"1": first,
+			"2": second,
+			"3": second,
+		}
+	)
+
+	// the subscription advances since to just before the latest event of a poll
+	forget(seen, second.Add(-time.Nanosecond))
+	assert.Equal(t, map[string]time.Time{"2": second, "3": second}, seen)
+
+	forget(seen, second)
+	assert.Empty(t, seen)
+}
@@ -0,0 +1,197 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrExpectationTimeout is given to the callback of an expectation that no payment
+// matched within its timeout.
+var ErrExpectationTimeout = errors.New("no matching payment was received in time")
+
+// Expectation describes a payment the node expects to receive, e.g. the payment of
+// an order at checkout. The zero value of a field matches any payment: a zero
+// TokenAddress matches payments of every token, a zero Identifier any identifier, a
+// zero Initiator any initiator and a nil Amount any amount.
+//
+// Callback is called exactly once: with the event of the first received payment
+// matching the expectation, or with ErrExpectationTimeout when none did within
+// Timeout, a zero Timeout waiting until the expectation is cancelled.
+type Expectation struct {
+	TokenAddress common.Address
+	Initiator    common.Address
+	Identifier   int64
+	Amount       *big.Int
+	Timeout      time.Duration
+	Callback     func(event *PaymentEvent, err error)
+}
+
+// Matches tells whether the received payment event fulfils the expectation.
+func (expectation *Expectation) Matches(event *PaymentEvent) bool {
+	switch {
+	case event.EventName != payments.EventPaymentReceivedSuccess:
+		return false
+	case expectation.TokenAddress != (common.Address{}) && expectation.TokenAddress != event.TokenAddress:
+		return false
+	case expectation.Initiator != (common.Address{}) && expectation.Initiator != event.Initiator:
+		return false
+	case expectation.Identifier != 0 && expectation.Identifier != event.Identifier:
+		return false
+	case expectation.Amount != nil && (event.Amount == nil || expectation.Amount.Cmp(event.Amount) != 0):
+		return false
+	}
+
+	return true
+}
+
+// Receiver watches the payments received by a Raiden node and calls back the
+// expectations they match, the receiving half of a checkout flow. A received payment
+// fulfils a single expectation, the one registered first among those it matches.
+type Receiver struct {
+	subscriber Subscriber
+	options    *SubscribeOptions
+
+	mutex        sync.Mutex
+	expectations []*pendingExpectation
+}
+
+type pendingExpectation struct {
+	*Expectation
+	done chan struct{}
+}
+
+// NewReceiver creates a receiver of the payments of the Raiden node of the
+// configuration, polled as configured by the options, see SubscribeOptions. Only
+// payments received after Run was called, or after Since when given, are matched. The
+// timeouts of the expectations run on the Clock of the options too.
+func NewReceiver(config *config.Config, httpClient *http.Client, options *SubscribeOptions) *Receiver {
+	return &Receiver{
+		subscriber: NewSubscriber(config, httpClient),
+		options:    options,
+	}
+}
+
+// Expect registers the expectation until a payment matches it, it times out or the
+// returned func cancels it. A cancelled expectation is not called back.
+func (receiver *Receiver) Expect(expectation *Expectation) (cancel func()) {
+	var pending = &pendingExpectation{Expectation: expectation, done: make(chan struct{})}
+
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	if expectation.Timeout > 0 {
+		timeout := receiver.clock().After(expectation.Timeout)
+
+		go func() {
+			select {
+			case <-pending.done:
+			case <-timeout:
+				if receiver.remove(pending) {
+					expectation.Callback(nil, ErrExpectationTimeout)
+				}
+			}
+		}()
+	}
+
+	receiver.expectations = append(receiver.expectations, pending)
+
+	return func() {
+		receiver.remove(pending)
+	}
+}
+
+// Pending returns the number of expectations waiting for a payment.
+func (receiver *Receiver) Pending() int {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	return len(receiver.expectations)
+}
+
+// Run matches the payments received by the node against the expectations until the
+// context is done. Polls failing, e.g. while the node is unreachable, are retried at
+// the next interval, the timeouts of the expectations still running.
+func (receiver *Receiver) Run(ctx context.Context) error {
+	var options = &SubscribeOptions{Since: receiver.clock().Now(), Clock: receiver.clock()}
+
+	if receiver.options != nil {
+		options.Interval = receiver.options.Interval
+		options.MaxInterval = receiver.options.MaxInterval
+		options.Buffer = receiver.options.Buffer
+
+		if !receiver.options.Since.IsZero() {
+			options.Since = receiver.options.Since
+		}
+	}
+
+	subscription := receiver.subscriber.SubscribePayments(ctx, options)
+
+	go func() {
+		for range subscription.Errors {
+		}
+	}()
+
+	for event := range subscription.Events {
+		if pending := receiver.match(event); pending != nil {
+			pending.Callback(event, nil)
+		}
+	}
+
+	return ctx.Err()
+}
+
+// match removes and returns the first expectation the event matches, nil when it
+// matches none.
+func (receiver *Receiver) match(event *PaymentEvent) *pendingExpectation {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	for i, pending := range receiver.expectations {
+		if pending.Matches(event) {
+			receiver.expectations = append(receiver.expectations[:i], receiver.expectations[i+1:]...)
+			pending.stop()
+
+			return pending
+		}
+	}
+
+	return nil
+}
+
+// remove removes the expectation, returning whether it was still pending.
+func (receiver *Receiver) remove(pending *pendingExpectation) bool {
+	receiver.mutex.Lock()
+	defer receiver.mutex.Unlock()
+
+	for i, candidate := range receiver.expectations {
+		if candidate == pending {
+			receiver.expectations = append(receiver.expectations[:i], receiver.expectations[i+1:]...)
+			pending.stop()
+
+			return true
+		}
+	}
+
+	return false
+}
+
+func (receiver *Receiver) clock() clock.Clock {
+	if receiver.options == nil {
+		return clock.Real()
+	}
+
+	return clock.Or(receiver.options.Clock)
+}
+
+func (pending *pendingExpectation) stop() {
+	close(pending.done)
+}
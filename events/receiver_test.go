@@ -0,0 +1,145 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleReceiver() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		receiver    = NewReceiver(config, http.DefaultClient, nil)
+		ctx, cancel = context.WithCancel(context.Background())
+		paid        = make(chan error, 1)
+	)
+
+	defer cancel()
+
+	go receiver.Run(ctx)
+
+	receiver.Expect(&Expectation{
+		Identifier: 42,
+		Amount:     big.NewInt(1000),
+		Timeout:    15 * time.Minute,
+		Callback: func(event *PaymentEvent, err error) {
+			paid <- err
+		},
+	})
+
+	if err := <-paid; err != nil {
+		fmt.Println("order 42 was not paid:", err.Error())
+		return
+	}
+
+	fmt.Println("order 42 was paid")
+}
+
+func TestReceiver(t *testing.T) {
+	type result struct {
+		event *PaymentEvent
+		err   error
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		initiator   = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		paymentsURL = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		results     = make(map[string]chan result)
+		receiver    = NewReceiver(config, http.DefaultClient, &SubscribeOptions{Interval: time.Millisecond, Since: time.Date(2018, 10, 30, 0, 0, 0, 0, time.UTC)})
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[
+		{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","state":"opened"}
+	]`))
+	httpmock.RegisterResponder("GET", paymentsURL, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`[
+		{"event":"EventPaymentSentSuccess","amount":10,"identifier":42,"log_time":"2018-10-30T07:03:52Z"},
+		{"event":"EventPaymentReceivedSuccess","amount":10,"identifier":42,"initiator":"%s","log_time":"2018-10-30T07:04:52Z"},
+		{"event":"EventPaymentReceivedSuccess","amount":5,"identifier":43,"initiator":"%s","log_time":"2018-10-30T07:05:52Z"}
+	]`, initiator.Hex(), initiator.Hex())))
+
+	expect := func(name string, expectation *Expectation) func() {
+		results[name] = make(chan result, 2)
+		expectation.Callback = func(event *PaymentEvent, err error) {
+			results[name] <- result{event: event, err: err}
+		}
+
+		return receiver.Expect(expectation)
+	}
+
+	expect("paid", &Expectation{Identifier: 42, Amount: big.NewInt(10), Initiator: initiator})
+	expect("paid twice", &Expectation{Identifier: 42, Timeout: 50 * time.Millisecond})
+	expect("wrong amount", &Expectation{Identifier: 43, Amount: big.NewInt(10), Timeout: 50 * time.Millisecond})
+	cancelled := expect("cancelled", &Expectation{Identifier: 43})
+	cancelled()
+	expect("any payment", &Expectation{Timeout: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+
+	go func() { done <- receiver.Run(ctx) }()
+
+	paid := <-results["paid"]
+	require.NoError(t, paid.err)
+	assert.Equal(t, int64(42), paid.event.Identifier)
+
+	assert.Equal(t, ErrExpectationTimeout, (<-results["paid twice"]).err, "a payment fulfils a single expectation")
+	assert.Equal(t, ErrExpectationTimeout, (<-results["wrong amount"]).err)
+
+	anyPayment := <-results["any payment"]
+	require.NoError(t, anyPayment.err)
+	assert.Equal(t, int64(43), anyPayment.event.Identifier)
+
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+
+	assert.Empty(t, results["cancelled"])
+	assert.Equal(t, 0, receiver.Pending())
+
+	for name, callbacks := range results {
+		assert.Empty(t, callbacks, "%s called back more than once", name)
+	}
+}
+
+func TestReceiverClock(t *testing.T) {
+	var (
+		manual   = clock.NewManual(time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC))
+		receiver = NewReceiver(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient, &SubscribeOptions{Clock: manual})
+		timedOut = make(chan error, 1)
+	)
+
+	receiver.Expect(&Expectation{
+		Identifier: 42,
+		Timeout:    15 * time.Minute,
+		Callback: func(event *PaymentEvent, err error) {
+			timedOut <- err
+		},
+	})
+
+	manual.BlockUntil(1)
+	manual.Advance(14 * time.Minute)
+	assert.Equal(t, 1, receiver.Pending())
+
+	manual.Advance(time.Minute)
+	assert.Equal(t, ErrExpectationTimeout, <-timedOut)
+	assert.Equal(t, 0, receiver.Pending())
+}
@@ -0,0 +1,70 @@
+package events
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrInvalidResumeToken is returned on the Errors of a subscription given a resume
+// token that was not returned by a subscription, which then ends right away.
+var ErrInvalidResumeToken = errors.New("invalid resume token")
+
+// ResumeToken is the position of a subscription in the events of the node, returned
+// by Subscription.ResumeToken once the subscription ended, to be given back in the
+// options of a new subscription, e.g. after a restart, so that it delivers the events
+// that follow without a gap nor a duplicate. It is an opaque string, safe to store.
+type ResumeToken string
+
+// position is the content of a resume token: the log time of the latest event
+// delivered and the events delivered at that time, which share it with events that
+// were not delivered yet.
+type position struct {
+	Since time.Time `json:"since"`
+	Seen  []string  `json:"seen,omitempty"`
+}
+
+func (token ResumeToken) position() (*position, error) {
+	var resumed = &position{}
+
+	contents, err := base64.RawURLEncoding.DecodeString(string(token))
+	if err != nil {
+		return nil, ErrInvalidResumeToken
+	}
+
+	if err = json.Unmarshal(contents, resumed); err != nil {
+		return nil, ErrInvalidResumeToken
+	}
+
+	return resumed, nil
+}
+
+// cursor tracks the position of a subscription as its events are delivered.
+type cursor struct {
+	mutex   sync.Mutex
+	current position
+}
+
+// delivered moves the cursor past the event with the ID and log time.
+func (cursor *cursor) delivered(id string, logTime time.Time) {
+	cursor.mutex.Lock()
+	defer cursor.mutex.Unlock()
+
+	switch {
+	case logTime.After(cursor.current.Since):
+		cursor.current = position{Since: logTime, Seen: []string{id}}
+	case logTime.Equal(cursor.current.Since):
+		cursor.current.Seen = append(cursor.current.Seen, id)
+	}
+}
+
+func (cursor *cursor) token() ResumeToken {
+	cursor.mutex.Lock()
+	defer cursor.mutex.Unlock()
+
+	contents, _ := json.Marshal(cursor.current)
+
+	return ResumeToken(base64.RawURLEncoding.EncodeToString(contents))
+}
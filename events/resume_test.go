@@ -0,0 +1,99 @@
+package events
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscriptionResumeToken(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		paymentsURL = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		mutex       sync.Mutex
+		eventsJSON  = `{"event":"EventPaymentSentSuccess","amount":5,"identifier":1,"log_time":"2018-10-30T07:03:52Z"},{"event":"EventPaymentReceivedSuccess","amount":7,"identifier":2,"log_time":"2018-10-30T07:04:52Z"}`
+		collect     = func(subscription *Subscription, count int) []int64 {
+			var identifiers = make([]int64, 0)
+
+			for len(identifiers) < count {
+				identifiers = append(identifiers, (<-subscription.Events).Identifier)
+			}
+
+			return identifiers
+		}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[
+		{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","state":"opened"}
+	]`))
+
+	httpmock.RegisterResponder("GET", paymentsURL, func(request *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return httpmock.NewStringResponse(http.StatusOK, "["+eventsJSON+"]"), nil
+	})
+
+	var (
+		subscriber   = NewSubscriber(config, http.DefaultClient)
+		ctx, cancel  = context.WithCancel(context.Background())
+		subscription = subscriber.SubscribePayments(ctx, &SubscribeOptions{Interval: time.Millisecond})
+	)
+
+	assert.Equal(t, []int64{1, 2}, collect(subscription, 2))
+
+	cancel()
+
+	for range subscription.Events {
+	}
+
+	token := subscription.ResumeToken()
+	assert.NotEmpty(t, token)
+
+	// an event logged at the same time as the last one delivered, and a later one
+	mutex.Lock()
+	eventsJSON += `,{"event":"EventPaymentReceivedSuccess","amount":9,"identifier":3,"log_time":"2018-10-30T07:04:52Z"},{"event":"EventPaymentSentFailed","identifier":4,"log_time":"2018-10-30T07:05:52Z"}`
+	mutex.Unlock()
+
+	ctx, cancel = context.WithCancel(context.Background())
+
+	subscription = subscriber.SubscribePayments(ctx, &SubscribeOptions{Interval: time.Millisecond, ResumeToken: token})
+
+	assert.Equal(t, []int64{3, 4}, collect(subscription, 2))
+
+	select {
+	case event := <-subscription.Events:
+		t.Fatalf("unexpected duplicate event %d", event.Identifier)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+
+	for range subscription.Events {
+	}
+}
+
+func TestSubscriptionInvalidResumeToken(t *testing.T) {
+	var subscription = NewSubscriber(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient).SubscribePayments(context.Background(), &SubscribeOptions{ResumeToken: "not a token"})
+
+	assert.Equal(t, ErrInvalidResumeToken, <-subscription.Errors)
+
+	_, open := <-subscription.Events
+	assert.False(t, open)
+}
+
+func TestSubscriptionWithoutSubscriber(t *testing.T) {
+	assert.Empty(t, (&Subscription{}).ResumeToken())
+}
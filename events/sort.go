@@ -0,0 +1,39 @@
+package events
+
+import (
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SortByTime sorts the payment events by log time, the oldest first. The sort is
+// stable, so events logged at the same time keep their order.
+func SortByTime(events []*PaymentEvent) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LogTime.Before(events[j].LogTime)
+	})
+}
+
+// GroupByToken groups the payment events by token address, every group keeping the
+// order of the events.
+func GroupByToken(events []*PaymentEvent) map[common.Address][]*PaymentEvent {
+	var groups = make(map[common.Address][]*PaymentEvent)
+
+	for _, event := range events {
+		groups[event.TokenAddress] = append(groups[event.TokenAddress], event)
+	}
+
+	return groups
+}
+
+// GroupByPartner groups the payment events by partner address, every group keeping
+// the order of the events.
+func GroupByPartner(events []*PaymentEvent) map[common.Address][]*PaymentEvent {
+	var groups = make(map[common.Address][]*PaymentEvent)
+
+	for _, event := range events {
+		groups[event.PartnerAddress] = append(groups[event.PartnerAddress], event)
+	}
+
+	return groups
+}
@@ -0,0 +1,37 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortAndGroup(t *testing.T) {
+	var (
+		now      = time.Date(2019, time.March, 7, 18, 19, 13, 0, time.UTC)
+		token1   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		token2   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		partner1 = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		partner2 = common.HexToAddress("0x00AF5cBfc8dC76cd599aF623E60F763228906F3E")
+		first    = &PaymentEvent{TokenAddress: token1, PartnerAddress: partner1, Event: &payments.Event{Identifier: 1, LogTime: now}}
+		second   = &PaymentEvent{TokenAddress: token2, PartnerAddress: partner1, Event: &payments.Event{Identifier: 2, LogTime: now.Add(time.Second)}}
+		third    = &PaymentEvent{TokenAddress: token1, PartnerAddress: partner2, Event: &payments.Event{Identifier: 3, LogTime: now.Add(2 * time.Second)}}
+		events   = []*PaymentEvent{third, first, second}
+	)
+
+	SortByTime(events)
+	assert.Equal(t, []*PaymentEvent{first, second, third}, events)
+
+	assert.Equal(t, map[common.Address][]*PaymentEvent{
+		token1: []*PaymentEvent{first, third},
+		token2: []*PaymentEvent{second},
+	}, GroupByToken(events))
+
+	assert.Equal(t, map[common.Address][]*PaymentEvent{
+		partner1: []*PaymentEvent{first, second},
+		partner2: []*PaymentEvent{third},
+	}, GroupByPartner(events))
+}
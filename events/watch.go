@@ -0,0 +1,209 @@
+package events
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/changefeed"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/transport"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// WatchOptions configures a watch. The node is polled every Interval while changes
+// come in, DefaultInterval when zero, the interval growing up to MaxInterval while
+// there are none or while the polls are slower than SlowResponse or failing with a
+// 5xx or 429 status, see changefeed.Options. Only the payment events logged after Since
+// are delivered, a zero Since delivering every event the node still knows of. The
+// intervals are waited for on Clock, the system time when nil.
+type WatchOptions struct {
+	Interval     time.Duration
+	MaxInterval  time.Duration
+	SlowResponse time.Duration
+	Since        time.Time
+	Buffer       int
+	Clock        clock.Clock
+}
+
+// Watcher is a generic interface to watch the payment events of a channel and the
+// updates of the channels of a Raiden node. The events and updates are delivered
+// until the context is done, when both channels are closed. Errors of single polls
+// are delivered on the error channel, without ending the watch, and dropped when
+// nobody is receiving them.
+type Watcher interface {
+	WatchPayments(ctx context.Context, tokenAddress, partnerAddress common.Address, options *WatchOptions) (<-chan *payments.Event, <-chan error)
+	WatchChannels(ctx context.Context, options *WatchOptions) (<-chan *channels.ChannelUpdate, <-chan error)
+}
+
+// NewWatcher creates a new default watcher given a Raiden node configuration and an
+// http client. Every watch polls through a conditional transport of its own, see
+// transport.NewConditional, so that the polls answered with what the previous one
+// was are not decoded again.
+func NewWatcher(config *config.Config, httpClient *http.Client) Watcher {
+	return &defaultWatcher{
+		config:     config,
+		httpClient: httpClient,
+	}
+}
+
+type defaultWatcher struct {
+	config     *config.Config
+	httpClient *http.Client
+}
+
+// pollClient returns a copy of the http client of the watcher sending its requests
+// through a new conditional transport, on the clock of the configuration.
+func (watcher *defaultWatcher) pollClient() *http.Client {
+	var client = *watcher.httpClient
+
+	client.Transport = transport.NewConditionalWithClock(client.Transport, watcher.config.Time())
+
+	return &client
+}
+
+// WatchPayments will poll the payment events of the channel with the token and
+// partner, delivering every event once, oldest first.
+func (watcher *defaultWatcher) WatchPayments(ctx context.Context, tokenAddress, partnerAddress common.Address, options *WatchOptions) (<-chan *payments.Event, <-chan error) {
+	var (
+		feed, buffer, since = watchFeed(options)
+		events              = make(chan *payments.Event, buffer)
+		errs                = make(chan error, 1)
+		seen                = make(map[string]time.Time)
+		key                 = counterparty{tokenAddress: tokenAddress, partnerAddress: partnerAddress}
+		paymentLister       = payments.NewLister(watcher.config, watcher.pollClient())
+	)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		for {
+			started := feed.Now()
+
+			paymentEvents, err := paymentLister.List(util.SkipUnchanged(ctx), tokenAddress, partnerAddress)
+			if err == util.ErrUnchanged {
+				err = nil
+			}
+
+			feed.Observe(started, err)
+
+			if err != nil && ctx.Err() == nil {
+				deliverError(errs, err)
+			}
+
+			newEvents := make([]*payments.Event, 0)
+			for _, event := range paymentEvents {
+				id := eventID(key, event)
+
+				if _, ok := seen[id]; ok || !event.LogTime.After(since) {
+					continue
+				}
+
+				seen[id] = event.LogTime
+				newEvents = append(newEvents, event)
+			}
+
+			payments.SortByTime(newEvents)
+
+			for _, event := range newEvents {
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// the node logs the events of a channel in order, so only the events
+			// logged at the time of the latest one may still be listed again
+			if n := len(newEvents); n > 0 && newEvents[n-1].LogTime.After(since) {
+				since = newEvents[n-1].LogTime.Add(-time.Nanosecond)
+				forget(seen, since)
+			}
+
+			if !feed.Wait(ctx, len(newEvents) > 0) {
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
+
+// WatchChannels will poll the channels of the node, delivering their updates since
+// the previous poll, see channels.Diff. The channels of the first poll are delivered
+// as added.
+func (watcher *defaultWatcher) WatchChannels(ctx context.Context, options *WatchOptions) (<-chan *channels.ChannelUpdate, <-chan error) {
+	var (
+		feed, buffer, _ = watchFeed(options)
+		updates         = make(chan *channels.ChannelUpdate, buffer)
+		errs            = make(chan error, 1)
+		previous        []*channels.Channel
+		channelLister   = channels.NewLister(watcher.config, watcher.pollClient())
+	)
+
+	go func() {
+		defer close(updates)
+		defer close(errs)
+
+		for {
+			var newUpdates []*channels.ChannelUpdate
+
+			started := feed.Now()
+
+			current, err := channelLister.ListAll(util.SkipUnchanged(ctx))
+			if err == util.ErrUnchanged {
+				err, current = nil, previous
+			}
+
+			feed.Observe(started, err)
+
+			if err != nil {
+				if ctx.Err() == nil {
+					deliverError(errs, err)
+				}
+			} else {
+				newUpdates = channels.Diff(previous, current)
+				previous = current
+			}
+
+			for _, update := range newUpdates {
+				select {
+				case updates <- update:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if !feed.Wait(ctx, len(newUpdates) > 0) {
+				return
+			}
+		}
+	}()
+
+	return updates, errs
+}
+
+// watchFeed returns the feed pacing a watch with the options, along with the buffer
+// of its channel and the time its events are delivered after.
+func watchFeed(options *WatchOptions) (*changefeed.Feed, int, time.Time) {
+	var feedOptions = &changefeed.Options{MinInterval: DefaultInterval}
+
+	if options == nil {
+		return changefeed.New(feedOptions), 0, time.Time{}
+	}
+
+	feedOptions.MaxInterval = options.MaxInterval
+	feedOptions.SlowResponse = options.SlowResponse
+	feedOptions.Clock = options.Clock
+
+	if options.Interval > 0 {
+		feedOptions.MinInterval = options.Interval
+	}
+
+	return changefeed.New(feedOptions), options.Buffer, options.Since
+}
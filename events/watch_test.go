@@ -0,0 +1,194 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleWatcher() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		watcher        = NewWatcher(config, http.DefaultClient)
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		partnerAddress = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+	)
+
+	paymentEvents, _ := watcher.WatchPayments(context.Background(), tokenAddress, partnerAddress, &WatchOptions{Since: time.Now()})
+
+	for event := range paymentEvents {
+		fmt.Println("new payment event:", event)
+	}
+}
+
+func TestWatchPayments(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		mutex          sync.Mutex
+		eventsJSON     = `{"event":"EventPaymentSentSuccess","amount":5,"identifier":1,"log_time":"2018-10-30T07:03:52.193Z"},{"event":"EventPaymentReceivedSuccess","amount":7,"identifier":2,"log_time":"2018-10-30T07:04:52.193Z"}`
+		ctx, cancel    = context.WithCancel(context.Background())
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/payments/"+tokenAddress.Hex()+"/"+partnerAddress.Hex(), func(request *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return httpmock.NewStringResponse(http.StatusOK, "["+eventsJSON+"]"), nil
+	})
+
+	paymentEvents, _ := NewWatcher(config, http.DefaultClient).WatchPayments(ctx, tokenAddress, partnerAddress, &WatchOptions{Interval: time.Millisecond})
+
+	assert.Equal(t, int64(1), (<-paymentEvents).Identifier)
+	assert.Equal(t, int64(2), (<-paymentEvents).Identifier)
+
+	// an event logged at the time of the latest one, and a later one
+	mutex.Lock()
+	eventsJSON += `,{"event":"EventPaymentReceivedSuccess","amount":9,"identifier":3,"log_time":"2018-10-30T07:04:52.193Z"},{"event":"EventPaymentSentFailed","identifier":4,"log_time":"2018-10-30T07:05:52.193Z"}`
+	mutex.Unlock()
+
+	assert.Equal(t, int64(3), (<-paymentEvents).Identifier)
+	assert.Equal(t, int64(4), (<-paymentEvents).Identifier)
+
+	select {
+	case event := <-paymentEvents:
+		t.Fatalf("unexpected duplicate event %d", event.Identifier)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+
+	for range paymentEvents {
+	}
+}
+
+func TestWatchChannels(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		mutex        sync.Mutex
+		channelsJSON = `[{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":100,"total_deposit":100,"state":"opened"}]`
+		ctx, cancel  = context.WithCancel(context.Background())
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", func(request *http.Request) (*http.Response, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		return httpmock.NewStringResponse(http.StatusOK, channelsJSON), nil
+	})
+
+	updates, errs := NewWatcher(config, http.DefaultClient).WatchChannels(ctx, &WatchOptions{Interval: time.Millisecond})
+
+	update := <-updates
+	assert.Equal(t, channels.UpdateAdded, update.Kind)
+	assert.Equal(t, int64(1), update.Current.ChannelIdentifier)
+
+	mutex.Lock()
+	channelsJSON = `[{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":100,"total_deposit":100,"state":"closed"}]`
+	mutex.Unlock()
+
+	update = <-updates
+	assert.Equal(t, channels.UpdateChanged, update.Kind)
+	assert.True(t, update.StateChanged())
+	assert.Equal(t, "opened", update.Previous.State)
+	assert.Equal(t, "closed", update.Current.State)
+
+	// a failed poll is reported, the next one diffs against the last channels listed
+	mutex.Lock()
+	channelsJSON = `not json`
+	mutex.Unlock()
+
+	assert.Error(t, <-errs)
+
+	mutex.Lock()
+	channelsJSON = `[]`
+	mutex.Unlock()
+
+	update = <-updates
+	assert.Equal(t, channels.UpdateRemoved, update.Kind)
+	assert.Equal(t, "closed", update.Previous.State)
+
+	cancel()
+
+	for range updates {
+	}
+}
+
+func TestWatchChannelsUnchanged(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		channelsJSON = `[{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":100,"total_deposit":100,"state":"opened"}]`
+		revalidated  = make(chan string, 3)
+		ctx, cancel  = context.WithCancel(context.Background())
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", func(request *http.Request) (*http.Response, error) {
+		if etag := request.Header.Get("If-None-Match"); etag != "" {
+			select {
+			case revalidated <- etag:
+			default:
+			}
+
+			return httpmock.NewStringResponse(http.StatusNotModified, ""), nil
+		}
+
+		response := httpmock.NewStringResponse(http.StatusOK, channelsJSON)
+		response.Header.Set("ETag", `"1"`)
+
+		return response, nil
+	})
+
+	updates, errs := NewWatcher(config, http.DefaultClient).WatchChannels(ctx, &WatchOptions{Interval: time.Millisecond})
+
+	update := <-updates
+	assert.Equal(t, channels.UpdateAdded, update.Kind)
+
+	// the polls are revalidated, answered with the channels already listed
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, `"1"`, <-revalidated)
+	}
+
+	select {
+	case update := <-updates:
+		t.Fatalf("unexpected update of channel %d", update.Current.ChannelIdentifier)
+	case err := <-errs:
+		t.Fatalf("unexpected error: %s", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+
+	for range updates {
+	}
+}
@@ -0,0 +1,271 @@
+// Package fanout fetches the per-token data of a Raiden node, such as the channels,
+// partners and pending transfers of every token network, with concurrent requests
+// instead of one round trip after another.
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultWorkers is the number of concurrent requests made by a Fetcher when its
+// Workers are not set.
+const DefaultWorkers = 4
+
+// TokenNetwork is the data of a Raiden node for a single token network.
+type TokenNetwork struct {
+	Token            common.Address
+	Channels         []*channels.Channel
+	Partners         []*tokens.Partner
+	PendingTransfers []*pendingtransfers.Transfer
+}
+
+// Error holds the errors of every token network that could not be fetched, keyed by
+// token address.
+type Error struct {
+	Errors map[common.Address]error
+}
+
+func (err *Error) Error() string {
+	var messages = make([]string, 0, len(err.Errors))
+
+	for token, tokenErr := range err.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", token.Hex(), tokenErr.Error()))
+	}
+
+	sort.Strings(messages)
+
+	return fmt.Sprintf("unable to fetch %d token network(s): %s", len(err.Errors), strings.Join(messages, ", "))
+}
+
+// Code returns raidenerrors.CodePartialFailure, see raidenerrors.HasCode.
+func (err *Error) Code() raidenerrors.Code {
+	return raidenerrors.CodePartialFailure
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *Error) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// NewFetcher creates a Fetcher that makes up to DefaultWorkers concurrent requests to
+// the Raiden node.
+func NewFetcher(config *config.Config, httpClient *http.Client) *Fetcher {
+	return &Fetcher{
+		TokenLister:    tokens.NewLister(config, httpClient),
+		PartnerLister:  tokens.NewPartnerLister(config, httpClient),
+		ChannelLister:  channels.NewLister(config, httpClient),
+		ChannelGetter:  channels.NewGetter(config, httpClient),
+		TransferLister: pendingtransfers.NewLister(config, httpClient),
+		Workers:        DefaultWorkers,
+	}
+}
+
+// Fetcher lists the registered tokens of a Raiden node, then fetches the data of
+// every token network concurrently with a pool of Workers. Results are merged in the
+// order of the tokens. When some token networks fail the results of the others are
+// still returned along with an *Error.
+type Fetcher struct {
+	TokenLister    tokens.Lister
+	PartnerLister  tokens.PartnerLister
+	ChannelLister  channels.Lister
+	ChannelGetter  channels.Getter
+	TransferLister pendingtransfers.Lister
+	Workers        int
+}
+
+// Channels will list the payment channels of every token network.
+func (fetcher *Fetcher) Channels(ctx context.Context) ([]*channels.Channel, error) {
+	var (
+		err         error
+		tokenList   []common.Address
+		allChannels = make([]*channels.Channel, 0)
+	)
+
+	if tokenList, err = fetcher.TokenLister.List(ctx); err != nil {
+		return nil, err
+	}
+
+	results := make([][]*channels.Channel, len(tokenList))
+
+	err = fetcher.each(ctx, tokenList, func(ctx context.Context, i int, token common.Address) error {
+		var err error
+
+		results[i], err = fetcher.ChannelLister.ListToken(ctx, token)
+
+		return err
+	})
+
+	for _, result := range results {
+		allChannels = append(allChannels, result...)
+	}
+
+	return allChannels, err
+}
+
+// Partners will list the partners of every token network, keyed by token address.
+func (fetcher *Fetcher) Partners(ctx context.Context) (map[common.Address][]*tokens.Partner, error) {
+	var (
+		err       error
+		mutex     sync.Mutex
+		tokenList []common.Address
+		partners  = make(map[common.Address][]*tokens.Partner)
+	)
+
+	if tokenList, err = fetcher.TokenLister.List(ctx); err != nil {
+		return nil, err
+	}
+
+	err = fetcher.each(ctx, tokenList, func(ctx context.Context, i int, token common.Address) error {
+		tokenPartners, err := fetcher.PartnerLister.ListPartners(ctx, token)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		partners[token] = tokenPartners
+
+		return nil
+	})
+
+	return partners, err
+}
+
+// PendingTransfers will list the pending transfers of every token network.
+func (fetcher *Fetcher) PendingTransfers(ctx context.Context) ([]*pendingtransfers.Transfer, error) {
+	var (
+		err       error
+		tokenList []common.Address
+		transfers = make([]*pendingtransfers.Transfer, 0)
+	)
+
+	if tokenList, err = fetcher.TokenLister.List(ctx); err != nil {
+		return nil, err
+	}
+
+	results := make([][]*pendingtransfers.Transfer, len(tokenList))
+
+	err = fetcher.each(ctx, tokenList, func(ctx context.Context, i int, token common.Address) error {
+		var err error
+
+		results[i], err = fetcher.TransferLister.ListToken(ctx, token)
+
+		return err
+	})
+
+	for _, result := range results {
+		transfers = append(transfers, result...)
+	}
+
+	return transfers, err
+}
+
+// Networks will fetch the channels, partners and pending transfers of every token
+// network at once. A token network is only returned when all of its data could be
+// fetched.
+func (fetcher *Fetcher) Networks(ctx context.Context) ([]*TokenNetwork, error) {
+	var (
+		err       error
+		tokenList []common.Address
+		networks  = make([]*TokenNetwork, 0)
+	)
+
+	if tokenList, err = fetcher.TokenLister.List(ctx); err != nil {
+		return nil, err
+	}
+
+	results := make([]*TokenNetwork, len(tokenList))
+
+	err = fetcher.each(ctx, tokenList, func(ctx context.Context, i int, token common.Address) error {
+		var (
+			err     error
+			network = &TokenNetwork{Token: token}
+		)
+
+		if network.Channels, err = fetcher.ChannelLister.ListToken(ctx, token); err != nil {
+			return err
+		}
+
+		if network.Partners, err = fetcher.PartnerLister.ListPartners(ctx, token); err != nil {
+			return err
+		}
+
+		if network.PendingTransfers, err = fetcher.TransferLister.ListToken(ctx, token); err != nil {
+			return err
+		}
+
+		results[i] = network
+
+		return nil
+	})
+
+	for _, network := range results {
+		if network != nil {
+			networks = append(networks, network)
+		}
+	}
+
+	return networks, err
+}
+
+// each will call fn for every token from a bounded pool of workers, collecting the
+// errors by token.
+func (fetcher *Fetcher) each(ctx context.Context, tokenList []common.Address, fn func(ctx context.Context, i int, token common.Address) error) error {
+	var (
+		mutex     sync.Mutex
+		waitGroup sync.WaitGroup
+		indexes   = make(chan int)
+		errs      = make(map[common.Address]error)
+		workers   = fetcher.Workers
+	)
+
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	if workers > len(tokenList) {
+		workers = len(tokenList)
+	}
+
+	for worker := 0; worker < workers; worker++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			for i := range indexes {
+				if err := fn(ctx, i, tokenList[i]); err != nil {
+					mutex.Lock()
+					errs[tokenList[i]] = err
+					mutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range tokenList {
+		indexes <- i
+	}
+
+	close(indexes)
+	waitGroup.Wait()
+
+	if len(errs) > 0 {
+		return &Error{Errors: errs}
+	}
+
+	return nil
+}
@@ -0,0 +1,172 @@
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleFetcher() {
+	var (
+		fetcher = NewFetcher(&config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}, http.DefaultClient)
+		networks []*TokenNetwork
+		err      error
+	)
+
+	if networks, err = fetcher.Networks(context.Background()); err != nil {
+		fmt.Println("some token networks could not be fetched:", err.Error())
+	}
+
+	for _, network := range networks {
+		fmt.Printf("%s: %d channels, %d partners, %d pending transfers\n", network.Token.Hex(), len(network.Channels), len(network.Partners), len(network.PendingTransfers))
+	}
+}
+
+func TestFetcher(t *testing.T) {
+	var (
+		host    = "http://localhost:5001/api/v1"
+		first   = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		second  = "0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		broken  = "0x2a65Aca4D5fC5B5C859090a6c34d164135398226"
+		fetcher = NewFetcher(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient)
+		ctx     = context.Background()
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", host+"/tokens", httpmock.NewStringResponder(http.StatusOK,
+		fmt.Sprintf(`["%s","%s","%s"]`, first, second, broken)))
+
+	for i, token := range []string{first, second} {
+		httpmock.RegisterResponder("GET", host+"/channels/"+token, httpmock.NewStringResponder(http.StatusOK,
+			fmt.Sprintf(`[{"channel_identifier":%d,"token_address":"%s","state":"opened","balance":10}]`, i+1, token)))
+
+		httpmock.RegisterResponder("GET", host+"/tokens/"+token+"/partners", httpmock.NewStringResponder(http.StatusOK,
+			fmt.Sprintf(`[{"partner_address":"0x82641569b2062B545431cF6D7F0A418582865ba7","channel":"/api/v1/channels/%s/0x82641569b2062B545431cF6D7F0A418582865ba7"}]`, token)))
+
+		httpmock.RegisterResponder("GET", host+"/pending_transfers/"+token, httpmock.NewStringResponder(http.StatusOK,
+			fmt.Sprintf(`[{"channel_identifier":%d,"locked_amount":5}]`, i+1)))
+	}
+
+	t.Run("channels in token order", func(t *testing.T) {
+		channels, err := fetcher.Channels(ctx)
+
+		require.Error(t, err)
+		require.IsType(t, &Error{}, err)
+		assert.Contains(t, err.(*Error).Errors, common.HexToAddress(broken))
+		assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodePartialFailure))
+
+		require.Len(t, channels, 2)
+		assert.Equal(t, int64(1), channels[0].ChannelIdentifier)
+		assert.Equal(t, int64(2), channels[1].ChannelIdentifier)
+	})
+
+	t.Run("partners by token", func(t *testing.T) {
+		partners, err := fetcher.Partners(ctx)
+
+		require.Error(t, err)
+		require.Len(t, partners, 2)
+		assert.Len(t, partners[common.HexToAddress(first)], 1)
+		assert.Len(t, partners[common.HexToAddress(second)], 1)
+	})
+
+	t.Run("pending transfers in token order", func(t *testing.T) {
+		transfers, err := fetcher.PendingTransfers(ctx)
+
+		require.Error(t, err)
+		require.Len(t, transfers, 2)
+		assert.Equal(t, int64(1), transfers[0].ChannelIdentifier)
+		assert.Equal(t, int64(2), transfers[1].ChannelIdentifier)
+	})
+
+	t.Run("networks", func(t *testing.T) {
+		networks, err := fetcher.Networks(ctx)
+
+		require.Error(t, err)
+		assert.Len(t, err.(*Error).Errors, 1)
+
+		require.Len(t, networks, 2)
+		assert.Equal(t, common.HexToAddress(first), networks[0].Token)
+		assert.Len(t, networks[0].Channels, 1)
+		assert.Len(t, networks[0].Partners, 1)
+		assert.Len(t, networks[0].PendingTransfers, 1)
+		assert.Equal(t, common.HexToAddress(second), networks[1].Token)
+	})
+}
+
+type fakeTokenLister struct {
+	tokens []common.Address
+}
+
+func (lister *fakeTokenLister) List(ctx context.Context) ([]common.Address, error) {
+	return lister.tokens, nil
+}
+
+type slowChannelLister struct {
+	mutex   sync.Mutex
+	current int
+	maximum int
+}
+
+func (lister *slowChannelLister) ListAll(ctx context.Context) ([]*channels.Channel, error) {
+	return nil, nil
+}
+
+func (lister *slowChannelLister) ListToken(ctx context.Context, tokenAddress common.Address) ([]*channels.Channel, error) {
+	lister.mutex.Lock()
+	lister.current++
+	if lister.current > lister.maximum {
+		lister.maximum = lister.current
+	}
+	lister.mutex.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	lister.mutex.Lock()
+	lister.current--
+	lister.mutex.Unlock()
+
+	return []*channels.Channel{&channels.Channel{TokenAddress: tokenAddress}}, nil
+}
+
+func TestFetcherWorkers(t *testing.T) {
+	var (
+		tokenList = make([]common.Address, 10)
+		lister    = &slowChannelLister{}
+		fetcher   = &Fetcher{
+			TokenLister:   &fakeTokenLister{tokens: tokenList},
+			ChannelLister: lister,
+			Workers:       3,
+		}
+	)
+
+	for i := range tokenList {
+		tokenList[i] = common.BigToAddress(big.NewInt(int64(i + 1)))
+	}
+
+	channels, err := fetcher.Channels(context.Background())
+
+	require.NoError(t, err)
+	require.Len(t, channels, len(tokenList))
+	assert.Equal(t, 3, lister.maximum)
+
+	for i, channel := range channels {
+		assert.Equal(t, tokenList[i], channel.TokenAddress)
+	}
+}
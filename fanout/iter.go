@@ -0,0 +1,32 @@
+//go:build go1.23
+// +build go1.23
+
+package fanout
+
+import (
+	"context"
+	"iter"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	pendingtransfers "github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+// AllChannels returns an iterator over the payment channels of every token network,
+// fetched concurrently once the iteration starts, see Channels. When some token
+// networks fail, the channels of the others are yielded before the *Error.
+func (fetcher *Fetcher) AllChannels(ctx context.Context) iter.Seq2[*channels.Channel, error] {
+	return util.Iterate(ctx, fetcher.Channels)
+}
+
+// AllPendingTransfers returns an iterator over the pending transfers of every token
+// network, see AllChannels.
+func (fetcher *Fetcher) AllPendingTransfers(ctx context.Context) iter.Seq2[*pendingtransfers.Transfer, error] {
+	return util.Iterate(ctx, fetcher.PendingTransfers)
+}
+
+// AllNetworks returns an iterator over the data of every token network, see Networks
+// and AllChannels.
+func (fetcher *Fetcher) AllNetworks(ctx context.Context) iter.Seq2[*TokenNetwork, error] {
+	return util.Iterate(ctx, fetcher.Networks)
+}
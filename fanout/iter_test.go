@@ -0,0 +1,51 @@
+//go:build go1.23
+// +build go1.23
+
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetcherAllChannels(t *testing.T) {
+	var (
+		host    = "http://localhost:5001/api/v1"
+		first   = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		broken  = "0x2a65Aca4D5fC5B5C859090a6c34d164135398226"
+		fetcher = NewFetcher(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient)
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", host+"/tokens", httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`["%s","%s"]`, first, broken)))
+	httpmock.RegisterResponder("GET", host+"/channels/"+first, httpmock.NewStringResponder(http.StatusOK,
+		fmt.Sprintf(`[{"channel_identifier":1,"token_address":"%s","state":"opened","balance":10}]`, first)))
+	httpmock.RegisterResponder("GET", host+"/channels/"+broken, httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+
+	var (
+		identifiers []int64
+		errs        []error
+	)
+
+	for channel, err := range fetcher.AllChannels(context.Background()) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		identifiers = append(identifiers, channel.ChannelIdentifier)
+	}
+
+	assert.Equal(t, []int64{1}, identifiers)
+	if assert.Len(t, errs, 1) {
+		assert.IsType(t, &Error{}, errs[0])
+	}
+}
@@ -0,0 +1,257 @@
+package fanout
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultPageSize is the number of results of a page when no size is given.
+const DefaultPageSize = 100
+
+// ErrInvalidPageToken is returned for a page token that was not returned with a page.
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// PageToken is the position of the next page in the results of a node, returned with
+// every page but the last one, to be given back for the next page, e.g. by the client
+// of a dashboard. It is an opaque string, the empty token being the first page.
+type PageToken string
+
+// pagePosition is the content of a page token: the token network of the first result
+// of the page and the number of its results on the previous pages.
+type pagePosition struct {
+	Token  common.Address `json:"token"`
+	Offset int            `json:"offset"`
+}
+
+func (pageToken PageToken) position() (*pagePosition, error) {
+	var position = &pagePosition{}
+
+	if pageToken == "" {
+		return position, nil
+	}
+
+	contents, err := base64.RawURLEncoding.DecodeString(string(pageToken))
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+
+	if err = json.Unmarshal(contents, position); err != nil || position.Offset < 0 {
+		return nil, ErrInvalidPageToken
+	}
+
+	return position, nil
+}
+
+func (position *pagePosition) token() PageToken {
+	contents, _ := json.Marshal(position)
+
+	return PageToken(base64.RawURLEncoding.EncodeToString(contents))
+}
+
+// ChannelPage is a page of the channels of a node, and the token of the next page,
+// empty on the last page.
+type ChannelPage struct {
+	Channels []*channels.Channel
+	Next     PageToken
+}
+
+// TransferPage is a page of the pending transfers of a node, see ChannelPage.
+type TransferPage struct {
+	PendingTransfers []*pendingtransfers.Transfer
+	Next             PageToken
+}
+
+// ChannelPage will return the page of up to size channels at the page token,
+// DefaultPageSize when size is zero, for nodes with too many channels to hold them
+// all at once: the channels are listed per token network, Workers token networks at
+// a time, until the page is full, so that at most Workers token networks are held in
+// memory. The channels are in the order of their token, then of their partner.
+// Channels opened or closed between two pages may be missed or seen twice.
+func (fetcher *Fetcher) ChannelPage(ctx context.Context, pageToken PageToken, size int) (*ChannelPage, error) {
+	var (
+		err    error
+		page   = &ChannelPage{Channels: make([]*channels.Channel, 0)}
+		mutex  sync.Mutex
+		subset = make(map[int][]*channels.Channel)
+	)
+
+	page.Next, err = fetcher.paginate(ctx, pageToken, size,
+		func(ctx context.Context, i int, token common.Address) (int, error) {
+			channelList, err := fetcher.ChannelLister.ListToken(ctx, token)
+			if err != nil {
+				return 0, err
+			}
+
+			sort.SliceStable(channelList, func(i, j int) bool {
+				return bytes.Compare(channelList[i].PartnerAddress[:], channelList[j].PartnerAddress[:]) < 0
+			})
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			subset[i] = channelList
+
+			return len(channelList), nil
+		},
+		func(i, from, to int) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			page.Channels = append(page.Channels, subset[i][from:to]...)
+			delete(subset, i)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// TransferPage will return the page of up to size pending transfers at the page
+// token, see ChannelPage. The transfers are in the order of their token, then of
+// their channel and payment.
+func (fetcher *Fetcher) TransferPage(ctx context.Context, pageToken PageToken, size int) (*TransferPage, error) {
+	var (
+		err    error
+		page   = &TransferPage{PendingTransfers: make([]*pendingtransfers.Transfer, 0)}
+		mutex  sync.Mutex
+		subset = make(map[int][]*pendingtransfers.Transfer)
+	)
+
+	page.Next, err = fetcher.paginate(ctx, pageToken, size,
+		func(ctx context.Context, i int, token common.Address) (int, error) {
+			transfers, err := fetcher.TransferLister.ListToken(ctx, token)
+			if err != nil {
+				return 0, err
+			}
+
+			sort.SliceStable(transfers, func(i, j int) bool {
+				if transfers[i].ChannelIdentifier != transfers[j].ChannelIdentifier {
+					return transfers[i].ChannelIdentifier < transfers[j].ChannelIdentifier
+				}
+
+				return transfers[i].PaymentIdentifier < transfers[j].PaymentIdentifier
+			})
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			subset[i] = transfers
+
+			return len(transfers), nil
+		},
+		func(i, from, to int) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			page.PendingTransfers = append(page.PendingTransfers, subset[i][from:to]...)
+			delete(subset, i)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// paginate lists the tokens of the node in the order of their address and fetches
+// the results of the tokens from the position of the page token on, Workers tokens
+// at a time, until size results are taken. fetch stores the results of the i-th
+// token and returns how many there are, take adds the results from and up to the
+// given ones of the i-th token to the page. The token of the next page is returned,
+// empty when there is none. A page fails as a whole when a token network fails.
+func (fetcher *Fetcher) paginate(ctx context.Context, pageToken PageToken, size int, fetch func(ctx context.Context, i int, token common.Address) (int, error), take func(i, from, to int)) (PageToken, error) {
+	var (
+		err       error
+		position  *pagePosition
+		tokenList []common.Address
+		taken     int
+		workers   = fetcher.Workers
+	)
+
+	if position, err = pageToken.position(); err != nil {
+		return "", err
+	}
+
+	if size <= 0 {
+		size = DefaultPageSize
+	}
+
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+
+	if tokenList, err = fetcher.TokenLister.List(ctx); err != nil {
+		return "", err
+	}
+
+	sort.Slice(tokenList, func(i, j int) bool {
+		return bytes.Compare(tokenList[i][:], tokenList[j][:]) < 0
+	})
+
+	// the page starts at its token, or the one after it when it is no longer
+	// registered
+	start := sort.Search(len(tokenList), func(i int) bool {
+		return bytes.Compare(tokenList[i][:], position.Token[:]) >= 0
+	})
+
+	for window := start; window < len(tokenList); window += workers {
+		var (
+			end     = window + workers
+			lengths = make([]int, workers)
+		)
+
+		if end > len(tokenList) {
+			end = len(tokenList)
+		}
+
+		if err = fetcher.each(ctx, tokenList[window:end], func(ctx context.Context, i int, token common.Address) error {
+			var err error
+
+			lengths[i], err = fetch(ctx, window+i, token)
+
+			return err
+		}); err != nil {
+			return "", err
+		}
+
+		for i := window; i < end; i++ {
+			var from, to = 0, lengths[i-window]
+
+			if i == start && tokenList[i] == position.Token {
+				from = position.Offset
+			}
+
+			if from > to {
+				from = to
+			}
+
+			if to-from > size-taken {
+				to = from + size - taken
+			}
+
+			take(i, from, to)
+			taken += to - from
+
+			switch {
+			case to < lengths[i-window]:
+				return (&pagePosition{Token: tokenList[i], Offset: to}).token(), nil
+			case taken == size && i+1 < len(tokenList):
+				return (&pagePosition{Token: tokenList[i+1]}).token(), nil
+			case taken == size:
+				return "", nil
+			}
+		}
+	}
+
+	return "", nil
+}
@@ -0,0 +1,181 @@
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleFetcher_ChannelPage() {
+	var (
+		fetcher = NewFetcher(&config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}, http.DefaultClient)
+		pageToken PageToken
+	)
+
+	for {
+		page, err := fetcher.ChannelPage(context.Background(), pageToken, 500)
+		if err != nil {
+			fmt.Println("unable to fetch channels:", err.Error())
+			return
+		}
+
+		for _, channel := range page.Channels {
+			fmt.Printf("%s: %d\n", channel.PartnerAddress.Hex(), channel.Balance)
+		}
+
+		if pageToken = page.Next; pageToken == "" {
+			break
+		}
+	}
+}
+
+func TestFetcherPages(t *testing.T) {
+	type testcase struct {
+		name     string
+		size     int
+		expected [][]int64
+	}
+
+	var (
+		host = "http://localhost:5001/api/v1"
+		// in the order of their hex addresses: second, first, third
+		first   = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		second  = "0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		third   = common.HexToAddress("0xf2a65aca4d5fc5b5c859090a6c34d16413539822").Hex()
+		fetcher = NewFetcher(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient)
+		ctx     = context.Background()
+	)
+
+	fetcher.Workers = 2
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", host+"/tokens", httpmock.NewStringResponder(http.StatusOK,
+		fmt.Sprintf(`["%s","%s","%s"]`, first, second, third)))
+
+	for token, identifiers := range map[string][]int64{first: []int64{3, 4, 5}, second: []int64{1, 2}, third: []int64{6}} {
+		var (
+			channelList  = make([]string, 0, len(identifiers))
+			transferList = make([]string, 0, len(identifiers))
+		)
+
+		// the node returns the channels in no particular order
+		for i := len(identifiers) - 1; i >= 0; i-- {
+			channelList = append(channelList, fmt.Sprintf(`{"channel_identifier":%d,"partner_address":"0x%040x","token_address":"%s","state":"opened"}`, identifiers[i], identifiers[i], token))
+			transferList = append(transferList, fmt.Sprintf(`{"channel_identifier":%d,"payment_identifier":1,"locked_amount":5}`, identifiers[i]))
+		}
+
+		httpmock.RegisterResponder("GET", host+"/channels/"+token, httpmock.NewStringResponder(http.StatusOK,
+			"["+strings.Join(channelList, ",")+"]"))
+
+		httpmock.RegisterResponder("GET", host+"/pending_transfers/"+token, httpmock.NewStringResponder(http.StatusOK,
+			"["+strings.Join(transferList, ",")+"]"))
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:     "pages within tokens",
+			size:     2,
+			expected: [][]int64{[]int64{1, 2}, []int64{3, 4}, []int64{5, 6}},
+		},
+		testcase{
+			name:     "pages across tokens",
+			size:     4,
+			expected: [][]int64{[]int64{1, 2, 3, 4}, []int64{5, 6}},
+		},
+		testcase{
+			name:     "single page",
+			size:     0,
+			expected: [][]int64{[]int64{1, 2, 3, 4, 5, 6}},
+		},
+		testcase{
+			name:     "exact page",
+			size:     6,
+			expected: [][]int64{[]int64{1, 2, 3, 4, 5, 6}},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				pageToken PageToken
+				pages     = make([][]int64, 0)
+			)
+
+			for {
+				page, err := fetcher.ChannelPage(ctx, pageToken, tc.size)
+				require.NoError(t, err)
+
+				identifiers := make([]int64, 0, len(page.Channels))
+				for _, channel := range page.Channels {
+					identifiers = append(identifiers, channel.ChannelIdentifier)
+				}
+
+				pages = append(pages, identifiers)
+
+				if pageToken = page.Next; pageToken == "" {
+					break
+				}
+			}
+
+			assert.Equal(t, tc.expected, pages)
+
+			pages, pageToken = make([][]int64, 0), ""
+
+			for {
+				page, err := fetcher.TransferPage(ctx, pageToken, tc.size)
+				require.NoError(t, err)
+
+				identifiers := make([]int64, 0, len(page.PendingTransfers))
+				for _, transfer := range page.PendingTransfers {
+					identifiers = append(identifiers, transfer.ChannelIdentifier)
+				}
+
+				pages = append(pages, identifiers)
+
+				if pageToken = page.Next; pageToken == "" {
+					break
+				}
+			}
+
+			assert.Equal(t, tc.expected, pages)
+		})
+	}
+
+	t.Run("invalid page token", func(t *testing.T) {
+		_, err := fetcher.ChannelPage(ctx, PageToken("not a page"), 2)
+
+		assert.Equal(t, ErrInvalidPageToken, err)
+	})
+
+	t.Run("unregistered page token", func(t *testing.T) {
+		// the page of a token no longer registered starts at the token after it
+		page, err := fetcher.ChannelPage(ctx, (&pagePosition{Token: common.HexToAddress("0x8000000000000000000000000000000000000000"), Offset: 1}).token(), 2)
+		require.NoError(t, err)
+
+		require.Len(t, page.Channels, 2)
+		assert.Equal(t, int64(3), page.Channels[0].ChannelIdentifier)
+	})
+
+	t.Run("failing token network", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", host+"/pending_transfers/"+third, httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+
+		_, err := fetcher.TransferPage(ctx, "", 10)
+
+		require.Error(t, err)
+		require.IsType(t, &Error{}, err)
+		assert.Contains(t, err.(*Error).Errors, common.HexToAddress(third))
+	})
+}
@@ -0,0 +1,126 @@
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PartnerChannel is a partner of the node in a token network along with the channel
+// its channel URI refers to.
+type PartnerChannel struct {
+	Partner *tokens.Partner
+	Channel *channels.Channel
+}
+
+// PartnerError holds the errors of every partner whose channel could not be resolved,
+// keyed by partner address.
+type PartnerError struct {
+	Errors map[common.Address]error
+}
+
+func (err *PartnerError) Error() string {
+	var messages = make([]string, 0, len(err.Errors))
+
+	for partner, partnerErr := range err.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", partner.Hex(), partnerErr.Error()))
+	}
+
+	sort.Strings(messages)
+
+	return fmt.Sprintf("unable to resolve %d partner channel(s): %s", len(err.Errors), strings.Join(messages, ", "))
+}
+
+// Code returns raidenerrors.CodePartialFailure, see raidenerrors.HasCode.
+func (err *PartnerError) Code() raidenerrors.Code {
+	return raidenerrors.CodePartialFailure
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *PartnerError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// PartnerChannels will list the partners of the node in the token network and resolve
+// their channels, see ResolvePartners.
+func (fetcher *Fetcher) PartnerChannels(ctx context.Context, tokenAddress common.Address) ([]*PartnerChannel, error) {
+	partners, err := fetcher.PartnerLister.ListPartners(ctx, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return fetcher.ResolvePartners(ctx, partners)
+}
+
+// ResolvePartners will get the channels the channel URIs of the partners refer to,
+// with a pool of Workers instead of one request after another, in the order of the
+// partners. When some channels can't be resolved the others are still returned along
+// with a *PartnerError.
+func (fetcher *Fetcher) ResolvePartners(ctx context.Context, partners []*tokens.Partner) ([]*PartnerChannel, error) {
+	var (
+		addresses = make([]common.Address, len(partners))
+		results   = make([]*PartnerChannel, len(partners))
+		resolved  = make([]*PartnerChannel, 0, len(partners))
+		err       error
+	)
+
+	for i, partner := range partners {
+		addresses[i] = partner.Address
+	}
+
+	if err = fetcher.each(ctx, addresses, func(ctx context.Context, i int, _ common.Address) error {
+		tokenAddress, partnerAddress, err := channelAddresses(partners[i].ChannelURI)
+		if err != nil {
+			return err
+		}
+
+		channel, err := fetcher.ChannelGetter.Get(ctx, tokenAddress, partnerAddress)
+		if err != nil {
+			return err
+		}
+
+		results[i] = &PartnerChannel{Partner: partners[i], Channel: channel}
+
+		return nil
+	}); err != nil {
+		err = &PartnerError{Errors: err.(*Error).Errors}
+	}
+
+	for _, result := range results {
+		if result != nil {
+			resolved = append(resolved, result)
+		}
+	}
+
+	return resolved, err
+}
+
+// channelAddresses returns the token and partner of a channel URI, whose path ends
+// with them, e.g. /api/v1/channels/<token>/<partner>.
+func channelAddresses(channelURI string) (common.Address, common.Address, error) {
+	var invalid = fmt.Errorf("invalid channel URI %q", channelURI)
+
+	parsed, err := url.Parse(channelURI)
+	if err != nil {
+		return common.Address{}, common.Address{}, invalid
+	}
+
+	segments := strings.Split(strings.TrimSuffix(parsed.Path, "/"), "/")
+	if len(segments) < 2 {
+		return common.Address{}, common.Address{}, invalid
+	}
+
+	tokenHex, partnerHex := segments[len(segments)-2], segments[len(segments)-1]
+	if !common.IsHexAddress(tokenHex) || !common.IsHexAddress(partnerHex) {
+		return common.Address{}, common.Address{}, invalid
+	}
+
+	return common.HexToAddress(tokenHex), common.HexToAddress(partnerHex), nil
+}
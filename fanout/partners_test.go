@@ -0,0 +1,95 @@
+package fanout
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleFetcher_PartnerChannels() {
+	var (
+		fetcher = NewFetcher(&config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}, http.DefaultClient)
+		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+	)
+
+	partnerChannels, err := fetcher.PartnerChannels(context.Background(), tokenAddress)
+	if err != nil {
+		fmt.Println("some partner channels could not be resolved:", err.Error())
+	}
+
+	for _, partnerChannel := range partnerChannels {
+		fmt.Printf("%s: %s, %d\n", partnerChannel.Partner.Address.Hex(), partnerChannel.Channel.State, partnerChannel.Channel.Balance)
+	}
+}
+
+func TestFetcherPartnerChannels(t *testing.T) {
+	var (
+		host         = "http://localhost:5001/api/v1"
+		tokenAddress = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		partners     = []string{
+			"0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			"0x2a65Aca4D5fC5B5C859090a6c34d164135398226",
+			"0x82641569b2062B545431cF6D7F0A418582865ba7",
+		}
+		broken  = partners[1]
+		fetcher = NewFetcher(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient)
+		ctx     = context.Background()
+	)
+
+	fetcher.Workers = 2
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	partnerList := make([]string, 0, len(partners))
+
+	for i, partner := range partners {
+		partnerList = append(partnerList, fmt.Sprintf(`{"partner_address":"%s","channel":"/api/v1/channels/%s/%s"}`, partner, tokenAddress, partner))
+
+		if partner != broken {
+			httpmock.RegisterResponder("GET", host+"/channels/"+tokenAddress+"/"+partner, httpmock.NewStringResponder(http.StatusOK,
+				fmt.Sprintf(`{"channel_identifier":%d,"partner_address":"%s","token_address":"%s","state":"opened","balance":10}`, i+1, partner, tokenAddress)))
+		}
+	}
+
+	httpmock.RegisterResponder("GET", host+"/tokens/"+tokenAddress+"/partners", httpmock.NewStringResponder(http.StatusOK,
+		"["+strings.Join(partnerList, ",")+"]"))
+
+	t.Run("partner channels in partner order", func(t *testing.T) {
+		partnerChannels, err := fetcher.PartnerChannels(ctx, common.HexToAddress(tokenAddress))
+
+		require.Error(t, err)
+		require.IsType(t, &PartnerError{}, err)
+		assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodePartialFailure))
+		assert.Contains(t, err.(*PartnerError).Errors, common.HexToAddress(broken))
+
+		require.Len(t, partnerChannels, 2)
+		assert.Equal(t, common.HexToAddress(partners[0]), partnerChannels[0].Partner.Address)
+		assert.Equal(t, int64(1), partnerChannels[0].Channel.ChannelIdentifier)
+		assert.Equal(t, common.HexToAddress(partners[2]), partnerChannels[1].Partner.Address)
+		assert.Equal(t, int64(3), partnerChannels[1].Channel.ChannelIdentifier)
+	})
+
+	t.Run("invalid channel URI", func(t *testing.T) {
+		partnerChannels, err := fetcher.ResolvePartners(ctx, []*tokens.Partner{
+			&tokens.Partner{Address: common.HexToAddress(partners[0]), ChannelURI: "/api/v1/channels"},
+		})
+
+		require.Error(t, err)
+		assert.EqualError(t, err.(*PartnerError).Errors[common.HexToAddress(partners[0])], `invalid channel URI "/api/v1/channels"`)
+		assert.Empty(t, partnerChannels)
+	})
+}
@@ -0,0 +1,204 @@
+// Package history records the balances of the channels of a Raiden node over time, so
+// that liquidity trends, e.g. the balance of a channel over the last 30 days, can be
+// analyzed without external tooling.
+package history
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/changefeed"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultInterval is the time between two samples of the balances when no interval
+// is given.
+const DefaultInterval = 5 * time.Minute
+
+// RecordOptions configures a recording. Balances are sampled every Interval while
+// they change, the interval growing up to MaxInterval while they do not or while the
+// samples are slower than SlowResponse or failing with a 5xx or 429 status, see
+// changefeed.Options. Samples older than Retention are pruned from the store after
+// every sample, a zero Retention keeping them forever.
+type RecordOptions struct {
+	Interval     time.Duration
+	MaxInterval  time.Duration
+	SlowResponse time.Duration
+	Retention    time.Duration
+}
+
+// Summary describes the balances of a channel over a period.
+type Summary struct {
+	First  *Sample
+	Last   *Sample
+	Min    *big.Int
+	Max    *big.Int
+	Change *big.Int
+}
+
+// Recorder samples the balances of every channel of a Raiden node into a Store. Its
+// Clock, which times the samples and the intervals between them, can be replaced
+// before it is used, e.g. by a clock.Manual in tests.
+type Recorder struct {
+	Clock clock.Clock
+
+	channelLister channels.Lister
+	store         Store
+}
+
+// NewRecorder creates a recorder of the balances of the channels of the Raiden node
+// into the store, given a Raiden node configuration and an http client.
+func NewRecorder(config *config.Config, httpClient *http.Client, store Store) *Recorder {
+	return &Recorder{
+		channelLister: channels.NewLister(config, httpClient),
+		store:         store,
+		Clock:         clock.Real(),
+	}
+}
+
+// Sample will list the channels of the node and append their balances to the store,
+// all of them with the same time.
+func (recorder *Recorder) Sample(ctx context.Context) ([]*Sample, error) {
+	var (
+		err         error
+		allChannels []*channels.Channel
+		now         = recorder.Clock.Now()
+	)
+
+	if allChannels, err = recorder.channelLister.ListAll(ctx); err != nil {
+		return nil, err
+	}
+
+	samples := make([]*Sample, 0, len(allChannels))
+	for _, channel := range allChannels {
+		samples = append(samples, &Sample{
+			TokenAddress:      channel.TokenAddress,
+			PartnerAddress:    channel.PartnerAddress,
+			ChannelIdentifier: channel.ChannelIdentifier,
+			Balance:           amounts.OrZero(channel.Balance),
+			TotalDeposit:      amounts.OrZero(channel.TotalDeposit),
+			State:             channel.State,
+			Time:              now,
+		})
+	}
+
+	if err = recorder.store.Append(samples); err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+// Run will sample the balances until the context is done, when the returned channel
+// is closed. Errors of single samples are delivered on it, without ending the
+// recording, and dropped when nobody is receiving them.
+func (recorder *Recorder) Run(ctx context.Context, options *RecordOptions) <-chan error {
+	var (
+		feedOptions = &changefeed.Options{MinInterval: DefaultInterval, Clock: recorder.Clock}
+		retention   time.Duration
+		last        = make(map[string]string)
+	)
+
+	if options != nil {
+		feedOptions.MaxInterval = options.MaxInterval
+		feedOptions.SlowResponse = options.SlowResponse
+		retention = options.Retention
+
+		if options.Interval > 0 {
+			feedOptions.MinInterval = options.Interval
+		}
+	}
+
+	return changefeed.Run(ctx, feedOptions, func(ctx context.Context) (bool, error) {
+		samples, err := recorder.Sample(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		if retention > 0 {
+			if err = recorder.store.Prune(recorder.Clock.Now().Add(-retention)); err != nil {
+				return false, err
+			}
+		}
+
+		return changed(last, samples), nil
+	})
+}
+
+// changed tells whether the balances, deposits or states of the samples differ from
+// the last ones of their channels, remembering them as the last ones.
+func changed(last map[string]string, samples []*Sample) bool {
+	var (
+		result  = len(samples) != len(last)
+		current = make(map[string]string, len(samples))
+	)
+
+	for _, sample := range samples {
+		var (
+			key   = fmt.Sprintf("%s/%s/%d", sample.TokenAddress.Hex(), sample.PartnerAddress.Hex(), sample.ChannelIdentifier)
+			value = fmt.Sprintf("%s/%s/%s", sample.Balance, sample.TotalDeposit, sample.State)
+		)
+
+		if last[key] != value {
+			result = true
+		}
+
+		current[key] = value
+	}
+
+	for key := range last {
+		delete(last, key)
+	}
+
+	for key, value := range current {
+		last[key] = value
+	}
+
+	return result
+}
+
+// Balances will return the samples of the channel with the token and partner taken
+// within the last period, oldest first.
+func (recorder *Recorder) Balances(tokenAddress, partnerAddress common.Address, period time.Duration) ([]*Sample, error) {
+	var now = recorder.Clock.Now()
+
+	return recorder.store.Query(tokenAddress, partnerAddress, now.Add(-period), now.Add(time.Nanosecond))
+}
+
+// Summarize describes the samples of a channel, oldest first, or returns nil when
+// there are none.
+func Summarize(samples []*Sample) *Summary {
+	if len(samples) == 0 {
+		return nil
+	}
+
+	var summary = &Summary{
+		First: samples[0],
+		Last:  samples[len(samples)-1],
+		Min:   new(big.Int).Set(amounts.OrZero(samples[0].Balance)),
+		Max:   new(big.Int).Set(amounts.OrZero(samples[0].Balance)),
+	}
+
+	for _, sample := range samples[1:] {
+		balance := amounts.OrZero(sample.Balance)
+
+		if balance.Cmp(summary.Min) < 0 {
+			summary.Min.Set(balance)
+		}
+
+		if balance.Cmp(summary.Max) > 0 {
+			summary.Max.Set(balance)
+		}
+	}
+
+	summary.Change = new(big.Int).Sub(amounts.OrZero(summary.Last.Balance), amounts.OrZero(summary.First.Balance))
+
+	return summary
+}
@@ -0,0 +1,116 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleRecorder() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		recorder = NewRecorder(config, http.DefaultClient, NewFileStore("balances.json"))
+		ctx      = context.Background()
+	)
+
+	go recorder.Run(ctx, &RecordOptions{Interval: 10 * time.Minute, Retention: 90 * 24 * time.Hour})
+
+	var (
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	samples, err := recorder.Balances(tokenAddress, partnerAddress, 30*24*time.Hour)
+	if err != nil {
+		panic(fmt.Sprintf("unable to query the balances: %s", err.Error()))
+	}
+
+	if summary := Summarize(samples); summary != nil {
+		fmt.Printf("balance changed by %s over 30 days, between %s and %s\n", summary.Change, summary.Min, summary.Max)
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var (
+		balance  int
+		now      = time.Date(2018, 10, 30, 0, 0, 0, 0, time.UTC)
+		manual   = clock.NewManual(now.AddDate(0, 0, -40))
+		token    = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partner  = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		store    = NewMemoryStore()
+		recorder = NewRecorder(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient, store)
+		ctx      = context.Background()
+	)
+
+	recorder.Clock = manual
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", func(request *http.Request) (*http.Response, error) {
+		return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`[
+			{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":%d,"total_deposit":50,"state":"opened"},
+			{"channel_identifier":2,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","state":"opened"}
+		]`, balance)), nil
+	})
+
+	for _, day := range [][2]int{{-40, 10}, {-20, 25}, {-10, 15}, {0, 40}} {
+		manual.Advance(now.AddDate(0, 0, day[0]).Sub(manual.Now()))
+		balance = day[1]
+
+		samples, err := recorder.Sample(ctx)
+		require.NoError(t, err)
+		require.Len(t, samples, 2)
+		assert.Equal(t, big.NewInt(0), samples[1].Balance)
+	}
+
+	samples, err := recorder.Balances(token, partner, 30*24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, samples, 3)
+
+	assert.Equal(t, &Summary{
+		First:  samples[0],
+		Last:   samples[2],
+		Min:    big.NewInt(15),
+		Max:    big.NewInt(40),
+		Change: big.NewInt(15),
+	}, Summarize(samples))
+	assert.Nil(t, Summarize(nil))
+
+	t.Run("run with retention", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+
+		errs := recorder.Run(ctx, &RecordOptions{Interval: time.Hour, Retention: 15 * 24 * time.Hour})
+
+		// the first sample is taken once the recorder waits for the next one
+		manual.BlockUntil(1)
+		cancel()
+
+		for range errs {
+		}
+
+		samples, err := store.Query(token, partner, time.Time{}, now.Add(time.Hour))
+		require.NoError(t, err)
+		assert.Len(t, samples, 3)
+	})
+
+	t.Run("unreachable node", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+
+		_, err := recorder.Sample(ctx)
+		assert.Error(t, err)
+	})
+}
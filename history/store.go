@@ -0,0 +1,219 @@
+package history
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Sample is the balance of a channel at the time it was sampled.
+type Sample struct {
+	TokenAddress      common.Address `json:"token_address"`
+	PartnerAddress    common.Address `json:"partner_address"`
+	ChannelIdentifier int64          `json:"channel_identifier"`
+	Balance           *big.Int       `json:"balance"`
+	TotalDeposit      *big.Int       `json:"total_deposit"`
+	State             string         `json:"state"`
+	Time              time.Time      `json:"time"`
+}
+
+// Store is a generic interface to persist balance samples. Query must return the
+// samples of the channel with the token and partner taken within [from, to), oldest
+// first, and Prune drop the samples taken before the given time.
+type Store interface {
+	Append(samples []*Sample) error
+	Query(tokenAddress, partnerAddress common.Address, from, to time.Time) ([]*Sample, error)
+	Prune(before time.Time) error
+}
+
+// NewMemoryStore creates a Store that only keeps samples in memory, samples will not
+// survive a restart of the process.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		samples: make([]*Sample, 0),
+	}
+}
+
+type memoryStore struct {
+	mutex   sync.Mutex
+	samples []*Sample
+}
+
+func (store *memoryStore) Append(samples []*Sample) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.samples = append(store.samples, samples...)
+
+	return nil
+}
+
+func (store *memoryStore) Query(tokenAddress, partnerAddress common.Address, from, to time.Time) ([]*Sample, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return query(store.samples, tokenAddress, partnerAddress, from, to), nil
+}
+
+func (store *memoryStore) Prune(before time.Time) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.samples = prune(store.samples, before)
+
+	return nil
+}
+
+// NewFileStore creates a Store that persists samples as JSON in the file at the given
+// path, so that the history of the balances outlives the process, through a
+// storage.NewFileStore, see NewStorageStore. Prune the store regularly, e.g. with the
+// Retention of the recorder, to keep the file small.
+func NewFileStore(path string) Store {
+	return NewStorageStore(storage.NewFileStore(path))
+}
+
+// storagePrefix is the prefix of the keys of the samples in a storage.Store.
+const storagePrefix = "history/"
+
+// NewStorageStore creates a Store that keeps samples as JSON in the storage shared by
+// the subsystems of the application, under the "history/" prefix, see storage.Store.
+// Every sample is stored at its own key, made of the token, partner and time of the
+// sample, so that a query only lists the samples of its channel.
+func NewStorageStore(backend storage.Store) Store {
+	return &storageStore{
+		backend: backend,
+	}
+}
+
+type storageStore struct {
+	backend storage.Store
+}
+
+// RetentionPolicy returns the retention of the samples kept in a storage.Store by
+// NewStorageStore, the samples older than maxAge being deleted, see storage.Collector.
+func RetentionPolicy(maxAge time.Duration) *storage.Policy {
+	return &storage.Policy{
+		Prefix: storagePrefix,
+		MaxAge: maxAge,
+		Time: func(entry *storage.Entry) (time.Time, bool) {
+			var sample = &Sample{}
+
+			if err := json.Unmarshal(entry.Value, sample); err != nil {
+				return time.Time{}, false
+			}
+
+			return sample.Time, true
+		},
+	}
+}
+
+func (store *storageStore) Append(samples []*Sample) error {
+	for _, sample := range samples {
+		value, err := json.Marshal(sample)
+		if err != nil {
+			return err
+		}
+
+		key := fmt.Sprintf("%s%020d/%d", channelPrefix(sample.TokenAddress, sample.PartnerAddress), sample.Time.UnixNano(), sample.ChannelIdentifier)
+
+		if err = store.backend.Put(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (store *storageStore) Query(tokenAddress, partnerAddress common.Address, from, to time.Time) ([]*Sample, error) {
+	_, samples, err := store.list(channelPrefix(tokenAddress, partnerAddress))
+	if err != nil {
+		return nil, err
+	}
+
+	return query(samples, tokenAddress, partnerAddress, from, to), nil
+}
+
+func (store *storageStore) Prune(before time.Time) error {
+	entries, samples, err := store.list(storagePrefix)
+	if err != nil {
+		return err
+	}
+
+	for i, sample := range samples {
+		if !sample.Time.Before(before) {
+			continue
+		}
+
+		if err = store.backend.Delete(entries[i].Key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// list returns the entries with the prefix along with their sample.
+func (store *storageStore) list(prefix string) ([]*storage.Entry, []*Sample, error) {
+	entries, err := store.backend.List(prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var samples = make([]*Sample, 0, len(entries))
+
+	for _, entry := range entries {
+		var sample = &Sample{}
+
+		if err = json.Unmarshal(entry.Value, sample); err != nil {
+			return nil, nil, err
+		}
+
+		samples = append(samples, sample)
+	}
+
+	return entries, samples, nil
+}
+
+func channelPrefix(tokenAddress, partnerAddress common.Address) string {
+	return storagePrefix + tokenAddress.Hex() + "/" + partnerAddress.Hex() + "/"
+}
+
+func query(samples []*Sample, tokenAddress, partnerAddress common.Address, from, to time.Time) []*Sample {
+	var matching = make([]*Sample, 0)
+
+	for _, sample := range samples {
+		if sample.TokenAddress != tokenAddress || sample.PartnerAddress != partnerAddress {
+			continue
+		}
+
+		if sample.Time.Before(from) || !sample.Time.Before(to) {
+			continue
+		}
+
+		matching = append(matching, sample)
+	}
+
+	sort.SliceStable(matching, func(i, j int) bool {
+		return matching[i].Time.Before(matching[j].Time)
+	})
+
+	return matching
+}
+
+func prune(samples []*Sample, before time.Time) []*Sample {
+	var kept = make([]*Sample, 0, len(samples))
+
+	for _, sample := range samples {
+		if !sample.Time.Before(before) {
+			kept = append(kept, sample)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,107 @@
+package history
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	var (
+		dir, err = ioutil.TempDir("", "history")
+		path     string
+		token    = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partner  = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		other    = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		start    = time.Date(2018, 10, 1, 0, 0, 0, 0, time.UTC)
+	)
+
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path = filepath.Join(dir, "history.json")
+
+	stores := map[string]func() Store{
+		"memory": NewMemoryStore,
+		"file": func() Store {
+			return NewFileStore(path)
+		},
+		"storage": func() Store {
+			return NewStorageStore(storage.NewMemoryStore())
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			var (
+				store   = newStore()
+				samples []*Sample
+				err     error
+			)
+
+			for day := 0; day < 4; day++ {
+				require.NoError(t, store.Append([]*Sample{
+					&Sample{TokenAddress: token, PartnerAddress: partner, Balance: big.NewInt(int64(10 * day)), Time: start.AddDate(0, 0, day)},
+					&Sample{TokenAddress: token, PartnerAddress: other, Balance: big.NewInt(1), Time: start.AddDate(0, 0, day)},
+				}))
+			}
+
+			samples, err = store.Query(token, partner, start.AddDate(0, 0, 1), start.AddDate(0, 0, 3))
+			require.NoError(t, err)
+			require.Len(t, samples, 2)
+			assert.Equal(t, big.NewInt(10), samples[0].Balance)
+			assert.Equal(t, big.NewInt(20), samples[1].Balance)
+
+			require.NoError(t, store.Prune(start.AddDate(0, 0, 2)))
+
+			samples, err = store.Query(token, partner, start, start.AddDate(1, 0, 0))
+			require.NoError(t, err)
+			require.Len(t, samples, 2)
+			assert.True(t, samples[0].Time.Equal(start.AddDate(0, 0, 2)))
+		})
+	}
+
+	t.Run("file store survives a restart", func(t *testing.T) {
+		samples, err := NewFileStore(path).Query(token, other, start, start.AddDate(1, 0, 0))
+
+		require.NoError(t, err)
+		assert.Len(t, samples, 2)
+	})
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	var (
+		now       = time.Date(2018, 10, 30, 0, 0, 0, 0, time.UTC)
+		backend   = storage.NewMemoryStore()
+		store     = NewStorageStore(backend)
+		collector = storage.NewCollector(backend, RetentionPolicy(30*24*time.Hour))
+		token     = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partner   = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	collector.Clock = clock.NewManual(now)
+
+	require.NoError(t, store.Append([]*Sample{
+		&Sample{TokenAddress: token, PartnerAddress: partner, Balance: big.NewInt(10), Time: now.AddDate(0, 0, -40)},
+		&Sample{TokenAddress: token, PartnerAddress: partner, Balance: big.NewInt(20), Time: now.AddDate(0, 0, -10)},
+	}))
+
+	collection, err := collector.Collect(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, collection.Results[0].Deleted)
+
+	samples, err := store.Query(token, partner, time.Time{}, now)
+	require.NoError(t, err)
+	require.Len(t, samples, 1)
+	assert.Equal(t, big.NewInt(20), samples[0].Balance)
+}
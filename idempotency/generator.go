@@ -0,0 +1,99 @@
+package idempotency
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/clock"
+)
+
+// ErrIdentifierCollision is returned when a business key hashes to an identifier
+// that is already tracked for a different business key.
+var ErrIdentifierCollision = errors.New("payment identifier already generated for a different business key")
+
+// NewGenerator creates a generator deriving payment identifiers from business keys
+// within the given namespace, e.g. "orders", checking for collisions in the store.
+func NewGenerator(namespace string, store Store) *Generator {
+	return &Generator{
+		Namespace: namespace,
+		Store:     store,
+	}
+}
+
+// Generator derives payment identifiers deterministically from caller supplied
+// business keys, such as an order ID, so that paying for the same order always uses
+// the same identifier and can be sent through SendIdempotent without keeping track of
+// the identifier separately. The identifiers of a Tenant are derived apart from
+// the ones of other tenants, and recorded as theirs. The reservations are timed by
+// Clock, the system time when nil.
+type Generator struct {
+	Namespace string
+	Tenant    string
+	Store     Store
+	Clock     clock.Clock
+
+	mutex sync.Mutex
+}
+
+// Identifier returns the payment identifier for the business key, reserving it in the
+// store the first time it is generated.
+func (generator *Generator) Identifier(key string) (int64, error) {
+	var (
+		err        error
+		record     *Record
+		identifier = Derive(generator.namespace(), key)
+	)
+
+	generator.mutex.Lock()
+	defer generator.mutex.Unlock()
+
+	record, err = generator.Store.Get(identifier)
+
+	switch {
+	case err == ErrRecordNotFound:
+		return identifier, generator.Store.Put(&Record{
+			Identifier: identifier,
+			Key:        key,
+			Tenant:     generator.Tenant,
+			Status:     Reserved,
+			Updated:    clock.Or(generator.Clock).Now(),
+		})
+	case err != nil:
+		return 0, err
+	case record.Key != key || record.Tenant != generator.Tenant:
+		return 0, ErrIdentifierCollision
+	}
+
+	return identifier, nil
+}
+
+// namespace returns the namespace of the generator within its tenant, the tenant
+// being left out when empty so that identifiers derived before tenants don't change.
+func (generator *Generator) namespace() string {
+	return tenantNamespace(generator.Tenant, generator.Namespace)
+}
+
+func tenantNamespace(tenant, namespace string) string {
+	if tenant == "" {
+		return namespace
+	}
+
+	return tenant + "\x00" + namespace
+}
+
+// Derive hashes the namespace and business key into the positive, non zero range of
+// payment identifiers, without consulting any store.
+func Derive(namespace, key string) int64 {
+	var (
+		hash       = sha256.Sum256([]byte(namespace + "\x00" + key))
+		identifier = int64(binary.BigEndian.Uint64(hash[:8]) & 0x7fffffffffffffff)
+	)
+
+	if identifier == 0 {
+		return 1
+	}
+
+	return identifier
+}
@@ -0,0 +1,90 @@
+package idempotency
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleGenerator() {
+	var (
+		store      = NewFileStore("/var/lib/payouts/identifiers.json")
+		generator  = NewGenerator("orders", store)
+		identifier int64
+		err        error
+	)
+
+	if identifier, err = generator.Identifier("order-1337"); err != nil {
+		panic(fmt.Sprintf("unable to generate payment identifier: %s", err.Error()))
+	}
+
+	fmt.Println("paying order-1337 with identifier", identifier)
+}
+
+func TestGenerator(t *testing.T) {
+	var (
+		store     = NewMemoryStore()
+		generator = NewGenerator("orders", store)
+	)
+
+	t.Run("deterministic across generators", func(t *testing.T) {
+		first, err := generator.Identifier("order-1")
+		require.NoError(t, err)
+
+		second, err := NewGenerator("orders", NewMemoryStore()).Identifier("order-1")
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+		assert.True(t, first > 0)
+	})
+
+	t.Run("repeated keys keep their identifier", func(t *testing.T) {
+		first, err := generator.Identifier("order-2")
+		require.NoError(t, err)
+
+		second, err := generator.Identifier("order-2")
+		require.NoError(t, err)
+
+		assert.Equal(t, first, second)
+
+		record, err := store.Get(first)
+		require.NoError(t, err)
+		assert.Equal(t, "order-2", record.Key)
+		assert.Equal(t, Reserved, record.Status)
+	})
+
+	t.Run("namespaces and keys are distinct", func(t *testing.T) {
+		assert.NotEqual(t, Derive("orders", "order-1"), Derive("orders", "order-2"))
+		assert.NotEqual(t, Derive("orders", "order-1"), Derive("refunds", "order-1"))
+	})
+
+	t.Run("collision with another business key", func(t *testing.T) {
+		require.NoError(t, store.Put(&Record{Identifier: Derive("orders", "order-3"), Key: "order-from-elsewhere", Status: Succeeded}))
+
+		_, err := generator.Identifier("order-3")
+
+		assert.Equal(t, ErrIdentifierCollision, err)
+	})
+
+	t.Run("tenants are distinct", func(t *testing.T) {
+		var tenantGenerator = NewGenerator("orders", store)
+
+		tenantGenerator.Tenant = "acme"
+
+		identifier, err := tenantGenerator.Identifier("order-1")
+		require.NoError(t, err)
+		assert.NotEqual(t, Derive("orders", "order-1"), identifier)
+
+		record, err := store.Get(identifier)
+		require.NoError(t, err)
+		assert.Equal(t, "acme", record.Tenant)
+
+		tenantGenerator.Tenant = "globex"
+		require.NoError(t, store.Put(&Record{Identifier: Derive("globex\x00orders", "order-4"), Key: "order-4", Tenant: "acme", Status: Reserved}))
+
+		_, err = tenantGenerator.Identifier("order-4")
+		assert.Equal(t, ErrIdentifierCollision, err)
+	})
+}
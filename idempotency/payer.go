@@ -0,0 +1,214 @@
+// Package idempotency tracks payment identifiers and their outcome in a persistent
+// store, so that automated payout systems never pay twice for the same identifier,
+// even after the process crashed in the middle of a payment.
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrMissingIdentifier is returned when sending a payment without an identifier,
+	// which cannot be made idempotent.
+	ErrMissingIdentifier = errors.New("idempotent payments require a non zero identifier")
+	// ErrIdentifierConflict is returned when an identifier is reused for a payment
+	// with a different token, target or amount.
+	ErrIdentifierConflict = errors.New("payment identifier already used for a different payment")
+)
+
+// Payer is a generic interface to send payments at most once per identifier.
+type Payer interface {
+	SendIdempotent(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64) (*payments.Payment, error)
+}
+
+// NewPayer creates a new default idempotent payer given a Raiden node configuration,
+// an http client and the store tracking the payment identifiers. Payments are
+// recorded for the tenant of their context, or the Tenant of the configuration.
+func NewPayer(config *config.Config, httpClient *http.Client, store Store) Payer {
+	var payer = &defaultPayer{
+		initiator: payments.NewInitiator(config, httpClient),
+		lister:    payments.NewLister(config, httpClient),
+		store:     store,
+		locks:     make(map[int64]*identifierLock),
+		clock:     config.Time(),
+	}
+
+	if config != nil {
+		payer.tenant = config.Tenant
+	}
+
+	return payer
+}
+
+type defaultPayer struct {
+	initiator payments.Initiator
+	lister    payments.Lister
+	store     Store
+	tenant    string
+	clock     clock.Clock
+
+	mutex sync.Mutex
+	locks map[int64]*identifierLock
+}
+
+// identifierLock serializes the payments of an identifier, users counting the
+// payments holding or waiting for it so that it is dropped once none is left.
+type identifierLock struct {
+	sync.Mutex
+	users int
+}
+
+// SendIdempotent will send the payment unless the store knows it already succeeded,
+// in which case the recorded payment is returned. A payment that was left pending,
+// e.g. by a crash, is first reconciled against the payment events of the Raiden node
+// and only sent again if the node never completed it, Raiden itself refuses a second
+// payment with an identifier that is still in flight. An identifier recorded for
+// another tenant is a conflict, like one recorded for a different payment.
+// Concurrent payments with the same identifier are sent one after the other, so
+// that only the first of them reaches the node.
+func (payer *defaultPayer) SendIdempotent(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64) (*payments.Payment, error) {
+	var (
+		err     error
+		record  *Record
+		payment *payments.Payment
+		tenant  = meta.Tenant(ctx, payer.tenant)
+	)
+
+	if identifier == 0 {
+		return nil, ErrMissingIdentifier
+	}
+
+	defer payer.lock(identifier)()
+
+	record, err = payer.store.Get(identifier)
+
+	switch {
+	case err == ErrRecordNotFound:
+		record = &Record{
+			Identifier:    identifier,
+			Tenant:        tenant,
+			TokenAddress:  tokenAddress,
+			TargetAddress: targetAddress,
+			Amount:        amounts.Copy(amount),
+		}
+	case err != nil:
+		return nil, err
+	case record.Tenant != tenant:
+		return nil, ErrIdentifierConflict
+	case record.Status == Reserved:
+		record.TokenAddress = tokenAddress
+		record.TargetAddress = targetAddress
+		record.Amount = amounts.Copy(amount)
+	case record.TokenAddress != tokenAddress || record.TargetAddress != targetAddress || !amounts.Equal(record.Amount, amount):
+		return nil, ErrIdentifierConflict
+	}
+
+	if record.Status == Pending {
+		if err = payer.reconcile(ctx, record); err != nil {
+			return nil, err
+		}
+	}
+
+	if record.Status == Succeeded {
+		return record.Payment, nil
+	}
+
+	if err = payer.put(record, Pending); err != nil {
+		return nil, err
+	}
+
+	// any error leaves the record pending, the payment may have reached the node and
+	// is reconciled the next time it is sent
+	if payment, err = payer.initiator.InitiateWithIdentifier(ctx, tokenAddress, targetAddress, amount, identifier); err != nil {
+		return nil, err
+	}
+
+	record.Payment = payment
+
+	if err = payer.put(record, Succeeded); err != nil {
+		return nil, err
+	}
+
+	return payment, nil
+}
+
+// reconcile will look for the outcome of a pending payment in the payment events of
+// the Raiden node, updating the record when one is found.
+func (payer *defaultPayer) reconcile(ctx context.Context, record *Record) error {
+	var (
+		err    error
+		events []*payments.Event
+	)
+
+	if events, err = payer.lister.List(ctx, record.TokenAddress, record.TargetAddress); err != nil {
+		return fmt.Errorf("unable to reconcile pending payment %d: %s", record.Identifier, err.Error())
+	}
+
+	for _, event := range events {
+		if event.Identifier != record.Identifier {
+			continue
+		}
+
+		switch event.EventName {
+		case payments.EventPaymentSentSuccess:
+			record.Payment = &payments.Payment{
+				TargetAddress: record.TargetAddress,
+				TokenAddress:  record.TokenAddress,
+				Amount:        event.Amount,
+				Identifier:    event.Identifier,
+			}
+
+			return payer.put(record, Succeeded)
+		case payments.EventPaymentSentFailed:
+			return payer.put(record, Failed)
+		}
+	}
+
+	return nil
+}
+
+// lock will acquire the lock of the identifier, returning the function releasing it.
+func (payer *defaultPayer) lock(identifier int64) func() {
+	payer.mutex.Lock()
+
+	lock, ok := payer.locks[identifier]
+	if !ok {
+		lock = &identifierLock{}
+		payer.locks[identifier] = lock
+	}
+
+	lock.users++
+	payer.mutex.Unlock()
+
+	lock.Lock()
+
+	return func() {
+		lock.Unlock()
+
+		payer.mutex.Lock()
+		defer payer.mutex.Unlock()
+
+		if lock.users--; lock.users == 0 {
+			delete(payer.locks, identifier)
+		}
+	}
+}
+
+func (payer *defaultPayer) put(record *Record, status Status) error {
+	record.Status = status
+	record.Updated = payer.clock.Now()
+
+	return payer.store.Put(record)
+}
@@ -0,0 +1,289 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExamplePayer() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		payer         = NewPayer(config, http.DefaultClient, NewFileStore("/var/lib/payouts/identifiers.json"))
+		tokenAddress  = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		targetAddress = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+		payment       *payments.Payment
+		err           error
+	)
+
+	if payment, err = payer.SendIdempotent(context.Background(), tokenAddress, targetAddress, big.NewInt(1000), 42); err != nil {
+		panic(fmt.Sprintf("unable to send payment: %s", err.Error()))
+	}
+
+	fmt.Printf("payment %d of %d sent\n", payment.Identifier, payment.Amount)
+}
+
+func TestPayer(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		paymentURL    = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+	)
+
+	type testcase struct {
+		name            string
+		prepStore       func(store Store)
+		prepHTTPMock    func()
+		amount          int64
+		identifier      int64
+		expectedPayment *payments.Payment
+		expectedError   error
+		expectedStatus  Status
+		expectedPosts   int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:      "new identifier is paid",
+			prepStore: func(store Store) {},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", paymentURL, httpmock.NewStringResponder(http.StatusOK, `{"amount":10,"identifier":1}`))
+			},
+			amount:          10,
+			identifier:      1,
+			expectedPayment: &payments.Payment{Amount: big.NewInt(10), Identifier: 1},
+			expectedStatus:  Succeeded,
+			expectedPosts:   1,
+		},
+		testcase{
+			name: "reserved identifier is paid",
+			prepStore: func(store Store) {
+				store.Put(&Record{Identifier: 1, Key: "order-1", Status: Reserved})
+			},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", paymentURL, httpmock.NewStringResponder(http.StatusOK, `{"amount":10,"identifier":1}`))
+			},
+			amount:          10,
+			identifier:      1,
+			expectedPayment: &payments.Payment{Amount: big.NewInt(10), Identifier: 1},
+			expectedStatus:  Succeeded,
+			expectedPosts:   1,
+		},
+		testcase{
+			name: "succeeded identifier is not paid again",
+			prepStore: func(store Store) {
+				store.Put(&Record{Identifier: 1, TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: big.NewInt(10), Status: Succeeded, Payment: &payments.Payment{Amount: big.NewInt(10), Identifier: 1}})
+			},
+			prepHTTPMock:    func() {},
+			amount:          10,
+			identifier:      1,
+			expectedPayment: &payments.Payment{Amount: big.NewInt(10), Identifier: 1},
+			expectedStatus:  Succeeded,
+			expectedPosts:   0,
+		},
+		testcase{
+			name: "pending identifier completed by the node is not paid again",
+			prepStore: func(store Store) {
+				store.Put(&Record{Identifier: 1, TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: big.NewInt(10), Status: Pending})
+			},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", paymentURL, httpmock.NewStringResponder(http.StatusOK, `[{"event":"EventPaymentSentSuccess","amount":10,"identifier":1,"log_time":"2018-10-30T07:03:52.193Z"}]`))
+			},
+			amount:     10,
+			identifier: 1,
+			expectedPayment: &payments.Payment{
+				TokenAddress:  tokenAddress,
+				TargetAddress: targetAddress,
+				Amount:        big.NewInt(10),
+				Identifier:    1,
+			},
+			expectedStatus: Succeeded,
+			expectedPosts:  0,
+		},
+		testcase{
+			name: "pending identifier failed on the node is paid again",
+			prepStore: func(store Store) {
+				store.Put(&Record{Identifier: 1, TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: big.NewInt(10), Status: Pending})
+			},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", paymentURL, httpmock.NewStringResponder(http.StatusOK, `[{"event":"EventPaymentSentFailed","identifier":1,"log_time":"2018-10-30T07:03:52.193Z"}]`))
+				httpmock.RegisterResponder("POST", paymentURL, httpmock.NewStringResponder(http.StatusOK, `{"amount":10,"identifier":1}`))
+			},
+			amount:          10,
+			identifier:      1,
+			expectedPayment: &payments.Payment{Amount: big.NewInt(10), Identifier: 1},
+			expectedStatus:  Succeeded,
+			expectedPosts:   1,
+		},
+		testcase{
+			name: "identifier reused for a different amount",
+			prepStore: func(store Store) {
+				store.Put(&Record{Identifier: 1, TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: big.NewInt(10), Status: Succeeded})
+			},
+			prepHTTPMock:   func() {},
+			amount:         20,
+			identifier:     1,
+			expectedError:  ErrIdentifierConflict,
+			expectedStatus: Succeeded,
+			expectedPosts:  0,
+		},
+		testcase{
+			name:          "missing identifier",
+			prepStore:     func(store Store) {},
+			prepHTTPMock:  func() {},
+			amount:        10,
+			identifier:    0,
+			expectedError: ErrMissingIdentifier,
+			expectedPosts: 0,
+		},
+		testcase{
+			name:      "unreachable node leaves the identifier pending",
+			prepStore: func(store Store) {},
+			prepHTTPMock: func() {
+				httpmock.RegisterNoResponder(httpmock.ConnectionFailure)
+			},
+			amount:         10,
+			identifier:     1,
+			expectedError:  fmt.Errorf("Post %q: no responder found", paymentURL),
+			expectedStatus: Pending,
+			expectedPosts:  0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err     error
+				payment *payments.Payment
+				record  *Record
+				store   = NewMemoryStore()
+				payer   = NewPayer(config, http.DefaultClient, store)
+				ctx     = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepStore(store)
+			tc.prepHTTPMock()
+
+			payment, err = payer.SendIdempotent(ctx, tokenAddress, targetAddress, big.NewInt(tc.amount), tc.identifier)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedPayment, payment)
+			}
+
+			assert.Equal(t, tc.expectedPosts, httpmock.GetCallCountInfo()["POST "+paymentURL])
+
+			if tc.expectedStatus != "" {
+				record, err = store.Get(tc.identifier)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedStatus, record.Status)
+			}
+		})
+	}
+}
+
+func TestPayerConcurrent(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		paymentURL    = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		payer         = NewPayer(config, http.DefaultClient, NewMemoryStore())
+		wg            sync.WaitGroup
+		errs          = make(chan error, 10)
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// the payment takes a while, so that the other payments are sent while it is in flight
+	httpmock.RegisterResponder("POST", paymentURL, func(request *http.Request) (*http.Response, error) {
+		time.Sleep(20 * time.Millisecond)
+		return httpmock.NewStringResponse(http.StatusOK, `{"amount":10,"identifier":1}`), nil
+	})
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			_, err := payer.SendIdempotent(context.Background(), tokenAddress, targetAddress, big.NewInt(10), 1)
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST "+paymentURL], "concurrent payments with an identifier must be sent once")
+	assert.Empty(t, payer.(*defaultPayer).locks)
+}
+
+func TestPayerTenant(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+			Tenant:     "acme",
+		}
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		paymentURL    = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		store         = NewMemoryStore()
+		payer         = NewPayer(config, http.DefaultClient, store)
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", paymentURL, httpmock.NewStringResponder(http.StatusOK, `{"amount":10,"identifier":1}`))
+
+	_, err := payer.SendIdempotent(context.Background(), tokenAddress, targetAddress, big.NewInt(10), 1)
+	require.NoError(t, err)
+
+	_, err = payer.SendIdempotent(meta.WithTenant(context.Background(), "globex"), tokenAddress, targetAddress, big.NewInt(10), 2)
+	require.NoError(t, err)
+
+	for identifier, tenant := range map[int64]string{1: "acme", 2: "globex"} {
+		record, err := store.Get(identifier)
+		require.NoError(t, err)
+		assert.Equal(t, tenant, record.Tenant)
+	}
+
+	// the identifier of one tenant is not paid again, or returned, for another
+	_, err = payer.SendIdempotent(meta.WithTenant(context.Background(), "globex"), tokenAddress, targetAddress, big.NewInt(10), 1)
+	assert.Equal(t, ErrIdentifierConflict, err)
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()["POST "+paymentURL])
+}
@@ -0,0 +1,234 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Expected is a payment an external system, such as an accounting or a bank-style
+// statement, expects to have been made for a business key.
+type Expected struct {
+	Key           string
+	TokenAddress  common.Address
+	TargetAddress common.Address
+	Amount        *big.Int
+}
+
+// expectedColumns are the columns ReadExpected requires, in any order.
+var expectedColumns = []string{"key", "token_address", "target_address", "amount"}
+
+// ReadExpected reads the expected payments of a CSV file whose header names the key,
+// token_address, target_address and amount columns, in any order and along with any
+// other column, the amounts being in the smallest unit of the token.
+func ReadExpected(reader io.Reader) ([]*Expected, error) {
+	var (
+		err      error
+		header   []string
+		row      []string
+		columns  = make(map[string]int)
+		expected = make([]*Expected, 0)
+		csvFile  = csv.NewReader(reader)
+	)
+
+	if header, err = csvFile.Read(); err != nil {
+		return nil, fmt.Errorf("unable to read the header of the expected payments: %s", err.Error())
+	}
+
+	for index, column := range header {
+		columns[strings.TrimSpace(strings.ToLower(column))] = index
+	}
+
+	for _, column := range expectedColumns {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("the expected payments have no %s column", column)
+		}
+	}
+
+	for line := 2; ; line++ {
+		var payment *Expected
+
+		if row, err = csvFile.Read(); err == io.EOF {
+			return expected, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if payment, err = parseExpected(row, columns); err != nil {
+			return nil, fmt.Errorf("line %d of the expected payments: %s", line, err.Error())
+		}
+
+		expected = append(expected, payment)
+	}
+}
+
+func parseExpected(row []string, columns map[string]int) (*Expected, error) {
+	var (
+		err      error
+		expected = &Expected{Key: strings.TrimSpace(row[columns["key"]])}
+	)
+
+	if expected.Key == "" {
+		return nil, errors.New("empty key")
+	}
+
+	for _, address := range []struct {
+		column  string
+		address *common.Address
+	}{
+		{"token_address", &expected.TokenAddress},
+		{"target_address", &expected.TargetAddress},
+	} {
+		var value = strings.TrimSpace(row[columns[address.column]])
+
+		if !common.IsHexAddress(value) {
+			return nil, fmt.Errorf("invalid %s %q", address.column, value)
+		}
+
+		*address.address = common.HexToAddress(value)
+	}
+
+	if expected.Amount, err = amounts.Parse(json.Number(strings.TrimSpace(row[columns["amount"]]))); err != nil {
+		return nil, fmt.Errorf("invalid amount: %s", err.Error())
+	}
+
+	return expected, nil
+}
+
+// Match is an expected payment along with the successful payment event of the node
+// for the identifier of its business key.
+type Match struct {
+	Expected   *Expected
+	Identifier int64
+	Event      *payments.Event
+}
+
+// Reconciliation is the outcome of matching expected payments against the payment
+// history of the node. Matched payments were sent for their identifier with their
+// amount, Mismatched ones with another amount. Missing payments were never sent
+// successfully, whether they failed or were never attempted, and Unexpected are the
+// successful payments to the same tokens and targets for no business key expected
+// to be paid to them.
+type Reconciliation struct {
+	Matched    []*Match
+	Mismatched []*Match
+	Missing    []*Expected
+	Unexpected []*payments.Event
+}
+
+// Reconciler matches expected payments against the payment history of a Raiden node,
+// using the identifiers a Generator derives from their business keys in Namespace,
+// for Tenant.
+type Reconciler struct {
+	Namespace string
+	Tenant    string
+	Lister    payments.Lister
+}
+
+// NewReconciler creates a reconciler of the payments with identifiers derived in the
+// namespace, for the Tenant of the configuration if any.
+func NewReconciler(config *config.Config, httpClient *http.Client, namespace string) *Reconciler {
+	var reconciler = &Reconciler{
+		Namespace: namespace,
+		Lister:    payments.NewLister(config, httpClient),
+	}
+
+	if config != nil {
+		reconciler.Tenant = config.Tenant
+	}
+
+	return reconciler
+}
+
+// target is the token and target address whose payment history is listed once.
+type target struct {
+	tokenAddress  common.Address
+	targetAddress common.Address
+}
+
+// Reconcile will list the payment history of every token and target of the expected
+// payments and match the expected payments against it, in their order. Unexpected
+// payments are ordered by identifier.
+func (reconciler *Reconciler) Reconcile(ctx context.Context, expected []*Expected) (*Reconciliation, error) {
+	var (
+		err            error
+		namespace      = tenantNamespace(reconciler.Tenant, reconciler.Namespace)
+		identifiers    = make(map[target]map[int64]bool)
+		targets        = make([]target, 0)
+		sent           = make(map[target]map[int64]*payments.Event)
+		reconciliation = &Reconciliation{
+			Matched:    make([]*Match, 0),
+			Mismatched: make([]*Match, 0),
+			Missing:    make([]*Expected, 0),
+			Unexpected: make([]*payments.Event, 0),
+		}
+	)
+
+	for _, payment := range expected {
+		var key = target{tokenAddress: payment.TokenAddress, targetAddress: payment.TargetAddress}
+
+		if _, ok := sent[key]; !ok {
+			identifiers[key] = make(map[int64]bool)
+			sent[key] = make(map[int64]*payments.Event)
+			targets = append(targets, key)
+		}
+
+		identifiers[key][Derive(namespace, payment.Key)] = true
+	}
+
+	for _, key := range targets {
+		var events []*payments.Event
+
+		if events, err = reconciler.Lister.List(ctx, key.tokenAddress, key.targetAddress); err != nil {
+			return nil, fmt.Errorf("unable to list the payments of token %s to %s: %s", key.tokenAddress.Hex(), key.targetAddress.Hex(), err.Error())
+		}
+
+		for _, event := range events {
+			if event.EventName == payments.EventPaymentSentSuccess {
+				sent[key][event.Identifier] = event
+			}
+		}
+	}
+
+	for _, payment := range expected {
+		var (
+			identifier = Derive(namespace, payment.Key)
+			event, ok  = sent[target{tokenAddress: payment.TokenAddress, targetAddress: payment.TargetAddress}][identifier]
+		)
+
+		switch {
+		case !ok:
+			reconciliation.Missing = append(reconciliation.Missing, payment)
+		case amounts.Equal(event.Amount, payment.Amount):
+			reconciliation.Matched = append(reconciliation.Matched, &Match{Expected: payment, Identifier: identifier, Event: event})
+		default:
+			reconciliation.Mismatched = append(reconciliation.Mismatched, &Match{Expected: payment, Identifier: identifier, Event: event})
+		}
+	}
+
+	for _, key := range targets {
+		for identifier, event := range sent[key] {
+			if !identifiers[key][identifier] {
+				reconciliation.Unexpected = append(reconciliation.Unexpected, event)
+			}
+		}
+	}
+
+	sort.SliceStable(reconciliation.Unexpected, func(i, j int) bool {
+		return reconciliation.Unexpected[i].Identifier < reconciliation.Unexpected[j].Identifier
+	})
+
+	return reconciliation, nil
+}
@@ -0,0 +1,163 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleReconciler() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		reconciler     = NewReconciler(config, http.DefaultClient, "orders")
+		expected       []*Expected
+		reconciliation *Reconciliation
+	)
+
+	statement, err := os.Open("/var/lib/payouts/statement.csv")
+	if err != nil {
+		panic(fmt.Sprintf("unable to open statement: %s", err.Error()))
+	}
+	defer statement.Close()
+
+	if expected, err = ReadExpected(statement); err != nil {
+		panic(fmt.Sprintf("unable to read statement: %s", err.Error()))
+	}
+
+	if reconciliation, err = reconciler.Reconcile(context.Background(), expected); err != nil {
+		panic(fmt.Sprintf("unable to reconcile payments: %s", err.Error()))
+	}
+
+	for _, missing := range reconciliation.Missing {
+		fmt.Println("order never paid:", missing.Key)
+	}
+}
+
+func TestReadExpected(t *testing.T) {
+	type testcase struct {
+		name          string
+		csv           string
+		expectedKeys  []string
+		expectedError string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "columns in any order",
+			csv:          "amount,Key,target_address,token_address,note\n10,order-1,0x1f7402f55e142820ea3812106d0657103fc1709e,0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359,first\n100000000000000000000,order-2,0x1f7402f55e142820ea3812106d0657103fc1709e,0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359,\n",
+			expectedKeys: []string{"order-1", "order-2"},
+		},
+		testcase{
+			name:          "missing column",
+			csv:           "key,token_address,amount\norder-1,0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359,10\n",
+			expectedError: "the expected payments have no target_address column",
+		},
+		testcase{
+			name:          "invalid address",
+			csv:           "key,token_address,target_address,amount\norder-1,0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359,dai,10\n",
+			expectedError: `line 2 of the expected payments: invalid target_address "dai"`,
+		},
+		testcase{
+			name:          "empty key",
+			csv:           "key,token_address,target_address,amount\n,0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359,0x1f7402f55e142820ea3812106d0657103fc1709e,10\n",
+			expectedError: "line 2 of the expected payments: empty key",
+		},
+		testcase{
+			name:          "empty file",
+			csv:           "",
+			expectedError: "unable to read the header of the expected payments: EOF",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			expected, err := ReadExpected(strings.NewReader(tc.csv))
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Nil(t, expected)
+				return
+			}
+
+			require.NoError(t, err)
+
+			keys := make([]string, 0, len(expected))
+			for _, payment := range expected {
+				keys = append(keys, payment.Key)
+			}
+
+			assert.Equal(t, tc.expectedKeys, keys)
+			assert.Equal(t, common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359"), expected[0].TokenAddress)
+			assert.Equal(t, "100000000000000000000", expected[1].Amount.String())
+		})
+	}
+}
+
+func TestReconciler(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = "0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359"
+		targetAddress = "0x1f7402f55e142820ea3812106d0657103fc1709e"
+		paymentURL    = fmt.Sprintf("http://localhost:5001/api/v1/payments/%s/%s", common.HexToAddress(tokenAddress).Hex(), common.HexToAddress(targetAddress).Hex())
+		statement     = fmt.Sprintf("key,token_address,target_address,amount\norder-1,%[1]s,%[2]s,10\norder-2,%[1]s,%[2]s,20\norder-3,%[1]s,%[2]s,30\norder-4,%[1]s,%[2]s,40\n", tokenAddress, targetAddress)
+		events        = fmt.Sprintf(`[
+			{"event":"EventPaymentSentSuccess","amount":10,"identifier":%d,"log_time":"2018-10-30T07:03:52.193Z"},
+			{"event":"EventPaymentSentSuccess","amount":25,"identifier":%d,"log_time":"2018-10-30T07:04:52.193Z"},
+			{"event":"EventPaymentSentFailed","identifier":%d,"log_time":"2018-10-30T07:05:52.193Z"},
+			{"event":"EventPaymentSentSuccess","amount":5,"identifier":7,"log_time":"2018-10-30T07:06:52.193Z"},
+			{"event":"EventPaymentReceivedSuccess","amount":5,"identifier":8,"log_time":"2018-10-30T07:07:52.193Z"}
+		]`, Derive("orders", "order-1"), Derive("orders", "order-2"), Derive("orders", "order-3"))
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", paymentURL, httpmock.NewStringResponder(http.StatusOK, events))
+
+	expected, err := ReadExpected(strings.NewReader(statement))
+	require.NoError(t, err)
+
+	reconciliation, err := NewReconciler(config, http.DefaultClient, "orders").Reconcile(context.Background(), expected)
+	require.NoError(t, err)
+
+	require.Len(t, reconciliation.Matched, 1)
+	assert.Equal(t, "order-1", reconciliation.Matched[0].Expected.Key)
+	assert.Equal(t, Derive("orders", "order-1"), reconciliation.Matched[0].Identifier)
+
+	require.Len(t, reconciliation.Mismatched, 1)
+	assert.Equal(t, "order-2", reconciliation.Mismatched[0].Expected.Key)
+	assert.Equal(t, int64(25), reconciliation.Mismatched[0].Event.Amount.Int64())
+
+	require.Len(t, reconciliation.Missing, 2)
+	assert.Equal(t, "order-3", reconciliation.Missing[0].Key)
+	assert.Equal(t, "order-4", reconciliation.Missing[1].Key)
+
+	require.Len(t, reconciliation.Unexpected, 1)
+	assert.Equal(t, int64(7), reconciliation.Unexpected[0].Identifier)
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+paymentURL], "the history of a token and target is listed once")
+
+	t.Run("list error", func(t *testing.T) {
+		httpmock.Reset()
+		httpmock.RegisterNoResponder(httpmock.ConnectionFailure)
+
+		reconciliation, err := NewReconciler(config, http.DefaultClient, "orders").Reconcile(context.Background(), expected)
+		assert.Error(t, err)
+		assert.Nil(t, reconciliation)
+	})
+}
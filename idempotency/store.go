@@ -0,0 +1,162 @@
+package idempotency
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrRecordNotFound is returned by a Store when no record exists for an identifier.
+var ErrRecordNotFound = errors.New("no record for payment identifier")
+
+// Status is the known outcome of a payment tracked in a Store.
+type Status string
+
+const (
+	// Reserved is an identifier generated for a business key that was never sent.
+	Reserved Status = "reserved"
+	// Pending is a payment that was about to be sent, or was sent without knowing
+	// whether the Raiden node received it.
+	Pending Status = "pending"
+	// Succeeded is a payment the Raiden node has completed.
+	Succeeded Status = "succeeded"
+	// Failed is a payment the Raiden node reported as failed, it is safe to send again.
+	Failed Status = "failed"
+)
+
+// Record tracks a single payment identifier and its outcome.
+type Record struct {
+	Identifier    int64             `json:"identifier"`
+	Key           string            `json:"key,omitempty"`
+	Tenant        string            `json:"tenant,omitempty"`
+	TokenAddress  common.Address    `json:"token_address"`
+	TargetAddress common.Address    `json:"target_address"`
+	Amount        *big.Int          `json:"amount"`
+	Status        Status            `json:"status"`
+	Payment       *payments.Payment `json:"payment,omitempty"`
+	Updated       time.Time         `json:"updated"`
+}
+
+// Store is a generic interface to persist payment identifiers and their outcome, Get
+// returns ErrRecordNotFound for an identifier that was never stored.
+type Store interface {
+	Get(identifier int64) (*Record, error)
+	Put(record *Record) error
+}
+
+// NewMemoryStore creates a Store that only keeps records in memory, records will not
+// survive a restart of the process.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		records: make(map[int64]*Record),
+	}
+}
+
+type memoryStore struct {
+	mutex   sync.Mutex
+	records map[int64]*Record
+}
+
+func (store *memoryStore) Get(identifier int64) (*Record, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	record, ok := store.records[identifier]
+	if !ok {
+		return nil, ErrRecordNotFound
+	}
+
+	copied := *record
+
+	return &copied, nil
+}
+
+func (store *memoryStore) Put(record *Record) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	copied := *record
+	store.records[record.Identifier] = &copied
+
+	return nil
+}
+
+// NewFileStore creates a Store that persists records as JSON in the file at the given
+// path, so that payment outcomes are known even after the process restarts, through a
+// storage.NewFileStore, see NewStorageStore.
+func NewFileStore(path string) Store {
+	return NewStorageStore(storage.NewFileStore(path))
+}
+
+// storagePrefix is the prefix of the keys of the records in a storage.Store.
+const storagePrefix = "idempotency/"
+
+// NewStorageStore creates a Store that keeps records as JSON in the storage shared by
+// the subsystems of the application, under the "idempotency/" prefix, see
+// storage.Store.
+func NewStorageStore(backend storage.Store) Store {
+	return &storageStore{
+		backend: backend,
+	}
+}
+
+type storageStore struct {
+	backend storage.Store
+}
+
+// RetentionPolicy returns the retention of the records kept in a storage.Store by
+// NewStorageStore, the records of the payments that succeeded or failed more than
+// maxAge ago being deleted, see storage.Collector. Reserved and pending records are
+// never deleted, since sending their payment again could pay twice. A payment sent
+// again once its record was deleted is sent as a new one, so maxAge must outlast the
+// retries of the application.
+func RetentionPolicy(maxAge time.Duration) *storage.Policy {
+	return &storage.Policy{
+		Prefix: storagePrefix,
+		MaxAge: maxAge,
+		Time: func(entry *storage.Entry) (time.Time, bool) {
+			var record = &Record{}
+
+			if err := json.Unmarshal(entry.Value, record); err != nil {
+				return time.Time{}, false
+			}
+
+			return record.Updated, record.Status == Succeeded || record.Status == Failed
+		},
+	}
+}
+
+func (store *storageStore) Get(identifier int64) (*Record, error) {
+	var record = &Record{}
+
+	value, err := store.backend.Get(storagePrefix + strconv.FormatInt(identifier, 10))
+	if err == storage.ErrNotFound {
+		return nil, ErrRecordNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(value, record); err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+func (store *storageStore) Put(record *Record) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	return store.backend.Put(storagePrefix+strconv.FormatInt(record.Identifier, 10), value)
+}
@@ -0,0 +1,104 @@
+package idempotency
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	var (
+		dir, err = ioutil.TempDir("", "idempotency")
+		path     string
+	)
+
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path = filepath.Join(dir, "identifiers.json")
+
+	stores := map[string]func() Store{
+		"memory": NewMemoryStore,
+		"file": func() Store {
+			return NewFileStore(path)
+		},
+		"storage": func() Store {
+			return NewStorageStore(storage.NewMemoryStore())
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			var (
+				store  = newStore()
+				record *Record
+				err    error
+			)
+
+			_, err = store.Get(1)
+			assert.Equal(t, ErrRecordNotFound, err)
+
+			require.NoError(t, store.Put(&Record{Identifier: 1, Amount: big.NewInt(10), Status: Pending}))
+			require.NoError(t, store.Put(&Record{Identifier: 1, Amount: big.NewInt(10), Status: Succeeded}))
+			require.NoError(t, store.Put(&Record{Identifier: 2, Amount: big.NewInt(20), Status: Failed}))
+
+			record, err = store.Get(1)
+			require.NoError(t, err)
+			assert.Equal(t, Succeeded, record.Status)
+
+			record, err = store.Get(2)
+			require.NoError(t, err)
+			assert.Equal(t, int64(20), record.Amount.Int64())
+		})
+	}
+
+	t.Run("file store survives a restart", func(t *testing.T) {
+		record, err := NewFileStore(path).Get(1)
+
+		require.NoError(t, err)
+		assert.Equal(t, Succeeded, record.Status)
+	})
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	var (
+		now       = time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC)
+		backend   = storage.NewMemoryStore()
+		store     = NewStorageStore(backend)
+		collector = storage.NewCollector(backend, RetentionPolicy(24*time.Hour))
+	)
+
+	collector.Clock = clock.NewManual(now)
+
+	for _, record := range []*Record{
+		&Record{Identifier: 1, Status: Succeeded, Updated: now.Add(-48 * time.Hour)},
+		&Record{Identifier: 2, Status: Failed, Updated: now.Add(-48 * time.Hour)},
+		&Record{Identifier: 3, Status: Pending, Updated: now.Add(-48 * time.Hour)},
+		&Record{Identifier: 4, Status: Reserved, Updated: now.Add(-48 * time.Hour)},
+		&Record{Identifier: 5, Status: Succeeded, Updated: now.Add(-time.Hour)},
+	} {
+		require.NoError(t, store.Put(record))
+	}
+
+	_, err := collector.Collect(context.Background())
+	require.NoError(t, err)
+
+	for identifier, kept := range map[int64]bool{1: false, 2: false, 3: true, 4: true, 5: true} {
+		_, err := store.Get(identifier)
+
+		if kept {
+			assert.NoError(t, err, "record %d is kept", identifier)
+		} else {
+			assert.Equal(t, ErrRecordNotFound, err, "record %d is deleted", identifier)
+		}
+	}
+}
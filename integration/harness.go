@@ -0,0 +1,244 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const (
+	// DefaultChainImage is a development chain whose developer account, funded with
+	// ether, is the account of the Raiden node.
+	DefaultChainImage = "ethereum/client-go:v1.10.26"
+	// DefaultNodeImage is the Raiden node started against the chain.
+	DefaultNodeImage = "raidennetwork/raiden:v3.0.1"
+	// DefaultReadyTimeout is the time allowed for the node to become ready.
+	DefaultReadyTimeout = 5 * time.Minute
+
+	chainAlias = "chain"
+	chainPort  = "8545/tcp"
+	nodePort   = "5001/tcp"
+)
+
+// ErrMissingKeystore is returned when starting a harness without the keystore of the
+// node account.
+var ErrMissingKeystore = errors.New("the harness requires the keystore file, password and address of the node account")
+
+// Options configures the containers of a harness. The account of the node is given
+// as a keystore file and its password, it is unlocked as the developer account of
+// the chain so that it holds ether from the first block. A complete Raiden setup
+// also needs the Raiden contracts deployed on the chain and a matrix server, NodeArgs
+// are appended to the arguments of the node to point it to them, or images bundling
+// them can be used instead.
+type Options struct {
+	ChainImage   string
+	NodeImage    string
+	KeystoreFile string
+	Password     string
+	Address      common.Address
+	NodeArgs     []string
+	ReadyTimeout time.Duration
+	HTTPClient   *http.Client
+}
+
+// Harness is a running Raiden node and development chain, with a client configured
+// for the node.
+type Harness struct {
+	Config   *config.Config
+	Client   *raidenclient.Client
+	Address  common.Address
+	ChainURL string
+
+	httpClient *http.Client
+	network    *testcontainers.DockerNetwork
+	chain      testcontainers.Container
+	node       testcontainers.Container
+}
+
+// Start will start the development chain and the Raiden node, wait for the node to
+// be ready and return a harness with a client for it. The containers are removed
+// again when starting fails, otherwise Terminate must be called once done.
+func Start(ctx context.Context, options *Options) (*Harness, error) {
+	var (
+		err     error
+		harness = &Harness{}
+	)
+
+	if options == nil || options.KeystoreFile == "" || options.Password == "" || options.Address == (common.Address{}) {
+		return nil, ErrMissingKeystore
+	}
+
+	harness.Address = options.Address
+	harness.httpClient = options.HTTPClient
+
+	if harness.httpClient == nil {
+		harness.httpClient = http.DefaultClient
+	}
+
+	if err = harness.start(ctx, options); err != nil {
+		harness.Terminate(context.Background())
+		return nil, err
+	}
+
+	return harness, nil
+}
+
+func (harness *Harness) start(ctx context.Context, options *Options) error {
+	var (
+		err          error
+		host         string
+		chainURL     string
+		nodeURL      string
+		readyTimeout = options.ReadyTimeout
+		files        = []testcontainers.ContainerFile{
+			testcontainers.ContainerFile{
+				HostFilePath:      options.KeystoreFile,
+				ContainerFilePath: "/keystore/" + filepath.Base(options.KeystoreFile),
+				FileMode:          0600,
+			},
+			testcontainers.ContainerFile{
+				Reader:            strings.NewReader(options.Password),
+				ContainerFilePath: "/password",
+				FileMode:          0600,
+			},
+		}
+	)
+
+	if readyTimeout <= 0 {
+		readyTimeout = DefaultReadyTimeout
+	}
+
+	if harness.network, err = network.New(ctx); err != nil {
+		return fmt.Errorf("unable to create the harness network: %s", err.Error())
+	}
+
+	if harness.chain, err = testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:          firstImage(options.ChainImage, DefaultChainImage),
+			ExposedPorts:   []string{chainPort},
+			Networks:       []string{harness.network.Name},
+			NetworkAliases: map[string][]string{harness.network.Name: []string{chainAlias}},
+			Files:          files,
+			Cmd: []string{
+				"--dev", "--dev.period", "1",
+				"--keystore", "/keystore",
+				"--unlock", options.Address.Hex(),
+				"--password", "/password",
+				"--allow-insecure-unlock",
+				"--http", "--http.addr", "0.0.0.0", "--http.vhosts", "*",
+				"--http.api", "eth,net,web3,txpool",
+			},
+			WaitingFor: wait.ForListeningPort(chainPort).WithStartupTimeout(readyTimeout),
+		},
+		Started: true,
+	}); err != nil {
+		return fmt.Errorf("unable to start the development chain: %s", err.Error())
+	}
+
+	if harness.node, err = testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: testcontainers.ContainerRequest{
+			Image:        firstImage(options.NodeImage, DefaultNodeImage),
+			ExposedPorts: []string{nodePort},
+			Networks:     []string{harness.network.Name},
+			Files:        files,
+			Cmd: append([]string{
+				"--keystore-path", "/keystore",
+				"--address", options.Address.Hex(),
+				"--password-file", "/password",
+				"--eth-rpc-endpoint", fmt.Sprintf("http://%s:8545", chainAlias),
+				"--environment-type", "development",
+				"--api-address", "0.0.0.0:5001",
+				"--accept-disclaimer",
+			}, options.NodeArgs...),
+			WaitingFor: wait.ForListeningPort(nodePort).WithStartupTimeout(readyTimeout),
+		},
+		Started: true,
+	}); err != nil {
+		return fmt.Errorf("unable to start the raiden node: %s", err.Error())
+	}
+
+	if host, err = harness.node.Host(ctx); err != nil {
+		return err
+	}
+
+	if chainURL, err = mappedURL(ctx, harness.chain, host, chainPort); err != nil {
+		return err
+	}
+
+	if nodeURL, err = mappedURL(ctx, harness.node, host, nodePort); err != nil {
+		return err
+	}
+
+	harness.ChainURL = chainURL
+	harness.Config = &config.Config{
+		Host:       nodeURL,
+		APIVersion: "v1",
+	}
+	harness.Client = raidenclient.NewClient(harness.Config, harness.httpClient)
+
+	readyCtx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+
+	return WaitReady(readyCtx, harness.Config, harness.httpClient, DefaultReadyInterval)
+}
+
+// Fund will mint value of a test token to the address, e.g. to the node itself
+// before opening channels.
+func (harness *Harness) Fund(ctx context.Context, tokenAddress, to common.Address, value int64) (common.Hash, error) {
+	return Mint(ctx, harness.Config, harness.httpClient, tokenAddress, to, value)
+}
+
+// Terminate will stop and remove the containers and the network of the harness.
+func (harness *Harness) Terminate(ctx context.Context) error {
+	var errs = make([]string, 0)
+
+	for _, container := range []testcontainers.Container{harness.node, harness.chain} {
+		if container == nil {
+			continue
+		}
+
+		if err := container.Terminate(ctx); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if harness.network != nil {
+		if err := harness.network.Remove(ctx); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("unable to terminate the harness: %s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func mappedURL(ctx context.Context, container testcontainers.Container, host, port string) (string, error) {
+	mapped, err := container.MappedPort(ctx, port)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("http://%s:%s", host, mapped.Port()), nil
+}
+
+func firstImage(image, defaultImage string) string {
+	if image == "" {
+		return defaultImage
+	}
+
+	return image
+}
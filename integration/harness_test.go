@@ -0,0 +1,53 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleStart() {
+	var (
+		ctx          = context.Background()
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		options      = &Options{
+			KeystoreFile: "testdata/keystore/UTC--2018-10-30T07-03-52Z--2a65aca4d5fc5b5c859090a6c34d164135398226",
+			Password:     "password",
+			Address:      common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"),
+		}
+		harness *Harness
+		err     error
+	)
+
+	if harness, err = Start(ctx, options); err != nil {
+		panic(fmt.Sprintf("unable to start the harness: %s", err.Error()))
+	}
+
+	defer harness.Terminate(ctx)
+
+	if _, err = harness.Fund(ctx, tokenAddress, harness.Address, 1000); err != nil {
+		panic(fmt.Sprintf("unable to fund the node: %s", err.Error()))
+	}
+
+	channelList, _ := harness.Client.Channels().ListAll(ctx)
+
+	fmt.Println("node has", len(channelList), "channels")
+}
+
+func TestStartWithoutKeystore(t *testing.T) {
+	testcases := []*Options{
+		nil,
+		&Options{},
+		&Options{KeystoreFile: "keystore", Password: "password"},
+	}
+
+	for _, options := range testcases {
+		harness, err := Start(context.Background(), options)
+
+		assert.Nil(t, harness)
+		assert.Equal(t, ErrMissingKeystore, err)
+	}
+}
@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type mintRequest struct {
+	To    string `json:"to"`
+	Value int64  `json:"value"`
+}
+
+type mintResponse struct {
+	TransactionHash string `json:"transaction_hash"`
+}
+
+// Mint will mint value of a test token to the address through the testing endpoint
+// of the Raiden node, which is only served by nodes on development chains, returning
+// the hash of the minting transaction.
+func Mint(ctx context.Context, config *config.Config, httpClient *http.Client, tokenAddress, to common.Address, value int64) (common.Hash, error) {
+	var (
+		err        error
+		hash       = common.Hash{}
+		requestURL *url.URL
+		request    *http.Request
+		response   *http.Response
+		baseClient = &util.BaseClient{Config: config, HTTPClient: httpClient}
+		minted     = &mintResponse{}
+	)
+
+	if requestURL, err = baseClient.Endpoint("_testing/tokens/%s/mint", tokenAddress.Hex()); err != nil {
+		return hash, err
+	}
+
+	if request, err = baseClient.NewRequest(ctx, "POST", requestURL, &mintRequest{To: to.Hex(), Value: value}); err != nil {
+		return hash, err
+	}
+
+	if response, err = baseClient.Do(request); err != nil {
+		return hash, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return hash, raidenerrors.New(response.StatusCode, fmt.Errorf("recieved %d status code when minting tokens", response.StatusCode))
+	}
+
+	if err = baseClient.Decode(response.Body, minted); err != nil {
+		return hash, util.ResponseError(ctx, response.StatusCode, err)
+	}
+
+	return common.HexToHash(minted.TransactionHash), nil
+}
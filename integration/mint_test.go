@@ -0,0 +1,97 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleMint() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		hash         common.Hash
+		err          error
+	)
+
+	if hash, err = Mint(context.Background(), config, http.DefaultClient, tokenAddress, ourAddress, 1000); err != nil {
+		panic(fmt.Sprintf("unable to mint tokens: %s", err.Error()))
+	}
+
+	fmt.Println("minted tokens in", hash.Hex())
+}
+
+func TestMint(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		mintURL      = "http://localhost:5001/api/v1/_testing/tokens/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/mint"
+	)
+
+	type testcase struct {
+		name          string
+		prepHTTPMock  func()
+		expectedHash  common.Hash
+		expectedError error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully minted tokens",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", mintURL, func(request *http.Request) (*http.Response, error) {
+					body := &mintRequest{}
+
+					if err := json.NewDecoder(request.Body).Decode(body); err != nil || body.To != ourAddress.Hex() || body.Value != 1000 {
+						return httpmock.NewStringResponse(http.StatusBadRequest, ""), nil
+					}
+
+					return httpmock.NewStringResponse(http.StatusOK, `{"transaction_hash":"0x6a3e2bbd8a8b2f2b29c2c0b4d5a1e6f0e9b4b7c1b2a3f4e5d6c7b8a9f0e1d2c3"}`), nil
+				})
+			},
+			expectedHash: common.HexToHash("0x6a3e2bbd8a8b2f2b29c2c0b4d5a1e6f0e9b4b7c1b2a3f4e5d6c7b8a9f0e1d2c3"),
+		},
+		testcase{
+			name: "node without the testing endpoint",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", mintURL, httpmock.NewStringResponder(http.StatusNotFound, ""))
+			},
+			expectedError: errors.New("recieved 404 status code when minting tokens"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			hash, err := Mint(context.Background(), config, http.DefaultClient, tokenAddress, ourAddress, 1000)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedHash, hash)
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Package integration runs a Raiden node, and the development chain it is connected
+// to, in containers so that code built on the client can be tested end to end
+// against a real node instead of mocked responses.
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultReadyInterval is the interval at which WaitReady polls the node when no
+// interval is given.
+const DefaultReadyInterval = time.Second
+
+// WaitReady will poll the Raiden node until its API answers with the address of the
+// node, which it only does once the node has synchronized with the chain, or until
+// the context is done.
+func WaitReady(ctx context.Context, config *config.Config, httpClient *http.Client, interval time.Duration) error {
+	var (
+		err     error
+		lastErr error
+		ticker  *time.Ticker
+	)
+
+	if interval <= 0 {
+		interval = DefaultReadyInterval
+	}
+
+	ticker = time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err = ready(ctx, config, httpClient); err == nil {
+			return nil
+		}
+
+		// keep the reason the node was not ready over the request cut short by the context
+		if ctx.Err() == nil || lastErr == nil {
+			lastErr = err
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("raiden node at %s is not ready: %s", config.Host, lastErr.Error())
+		case <-ticker.C:
+		}
+	}
+}
+
+func ready(ctx context.Context, config *config.Config, httpClient *http.Client) error {
+	var (
+		err             error
+		requestURL      *url.URL
+		request         *http.Request
+		response        *http.Response
+		baseClient      = &util.BaseClient{Config: config, HTTPClient: httpClient}
+		addressResponse = &struct {
+			OurAddress string `json:"our_address"`
+		}{}
+	)
+
+	if requestURL, err = baseClient.Endpoint("address"); err != nil {
+		return err
+	}
+
+	if request, err = baseClient.NewRequest(ctx, "GET", requestURL, nil); err != nil {
+		return err
+	}
+
+	if response, err = baseClient.Do(request); err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("recieved %d status code from raiden node", response.StatusCode)
+	}
+
+	if err = baseClient.Decode(response.Body, addressResponse); err != nil {
+		return err
+	}
+
+	if !common.IsHexAddress(addressResponse.OurAddress) {
+		return fmt.Errorf("raiden node returned an invalid address: %q", addressResponse.OurAddress)
+	}
+
+	return nil
+}
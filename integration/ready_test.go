@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleWaitReady() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), time.Minute)
+	)
+
+	defer cancel()
+
+	if err := WaitReady(ctx, config, http.DefaultClient, time.Second); err != nil {
+		panic(err.Error())
+	}
+
+	fmt.Println("raiden node is ready")
+}
+
+func TestWaitReady(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		addressURL = "http://localhost:5001/api/v1/address"
+	)
+
+	type testcase struct {
+		name          string
+		prepHTTPMock  func()
+		expectedError error
+		expectedCalls int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "node ready",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", addressURL, httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+			},
+			expectedCalls: 1,
+		},
+		testcase{
+			name: "node ready once synchronized",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", addressURL, httpmock.ResponderFromMultipleResponses([]*http.Response{
+					httpmock.NewStringResponse(http.StatusServiceUnavailable, ""),
+					httpmock.NewStringResponse(http.StatusServiceUnavailable, ""),
+					httpmock.NewStringResponse(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`),
+				}))
+			},
+			expectedCalls: 3,
+		},
+		testcase{
+			name: "node never ready",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", addressURL, httpmock.NewStringResponder(http.StatusServiceUnavailable, ""))
+			},
+			expectedError: fmt.Errorf("raiden node at http://localhost:5001 is not ready: recieved 503 status code from raiden node"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			err := WaitReady(ctx, config, http.DefaultClient, time.Millisecond)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedCalls, httpmock.GetTotalCallCount())
+		})
+	}
+}
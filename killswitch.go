@@ -0,0 +1,113 @@
+package raidenclient
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+)
+
+// ErrSuspended is returned by the mutating calls of a client whose kill switch is
+// engaged, which are not sent to the node. Its code is
+// raidenerrors.CodeClientSuspended.
+var ErrSuspended = raidenerrors.ErrClientSuspended
+
+// KillSwitch blocks every mutating operation of the clients it is given to at once,
+// the payments, deposits, channel changes and token registrations, while letting
+// their reads through, e.g. so that an on-call engineer halts automated spending
+// during an incident without stopping the services. Only GET and HEAD requests are
+// sent while it is engaged, every other one returning ErrSuspended, route queries to
+// pathfinding services included.
+//
+// Besides Suspend, the switch is engaged while the File exists, e.g. touched on every
+// host of a service by a runbook, and while the environment variable Env is set to
+// anything but "", "0" or "false", e.g. to restart a service suspended. Both are
+// checked before every mutating request, and neither is checked when empty.
+type KillSwitch struct {
+	File string
+	Env  string
+
+	mutex     sync.RWMutex
+	suspended bool
+}
+
+// NewKillSwitch creates a kill switch, not engaged, also engaged while the file
+// exists or the environment variable is set, either of them being empty to disable
+// that trigger.
+func NewKillSwitch(file, env string) *KillSwitch {
+	return &KillSwitch{
+		File: file,
+		Env:  env,
+	}
+}
+
+// Suspend engages the switch, blocking the mutating operations until Resume.
+func (killSwitch *KillSwitch) Suspend() {
+	killSwitch.mutex.Lock()
+	defer killSwitch.mutex.Unlock()
+
+	killSwitch.suspended = true
+}
+
+// Resume releases the switch engaged by Suspend. The switch stays engaged while its
+// file or environment variable say so.
+func (killSwitch *KillSwitch) Resume() {
+	killSwitch.mutex.Lock()
+	defer killSwitch.mutex.Unlock()
+
+	killSwitch.suspended = false
+}
+
+// Suspended tells whether the mutating operations are blocked, by Suspend, the file
+// or the environment variable of the switch.
+func (killSwitch *KillSwitch) Suspended() bool {
+	killSwitch.mutex.RLock()
+	suspended := killSwitch.suspended
+	killSwitch.mutex.RUnlock()
+
+	if suspended {
+		return true
+	}
+
+	if killSwitch.Env != "" {
+		switch strings.ToLower(strings.TrimSpace(os.Getenv(killSwitch.Env))) {
+		case "", "0", "false":
+		default:
+			return true
+		}
+	}
+
+	if killSwitch.File != "" {
+		if _, err := os.Stat(killSwitch.File); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Middleware returns a middleware refusing the mutating requests with ErrSuspended
+// while the switch is engaged, see WithKillSwitch.
+func (killSwitch *KillSwitch) Middleware() config.Middleware {
+	return func(next config.Doer) config.Doer {
+		return func(request *http.Request) (*http.Response, error) {
+			if request.Method != "GET" && request.Method != "HEAD" && killSwitch.Suspended() {
+				return nil, ErrSuspended
+			}
+
+			return next(request)
+		}
+	}
+}
+
+// WithKillSwitch blocks the mutating operations of the client while the switch is
+// engaged. The switch is outermost whatever its place among the options, so that the
+// blocked requests are not retried or observed as sent.
+func WithKillSwitch(killSwitch *KillSwitch) Option {
+	return func(options *clientOptions) {
+		options.config.Middlewares = append([]config.Middleware{killSwitch.Middleware()}, options.config.Middlewares...)
+	}
+}
@@ -0,0 +1,107 @@
+package raidenclient
+
+import (
+	"context"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleKillSwitch() {
+	var (
+		killSwitch   = NewKillSwitch("/var/run/payouts/suspended", "PAYOUTS_SUSPENDED")
+		raidenClient = New(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, WithKillSwitch(killSwitch))
+	)
+
+	// e.g. an admin endpoint of the service calls it during an incident
+	killSwitch.Suspend()
+
+	_, err := raidenClient.Payments().Initiate(context.Background(), common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359"), common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"), big.NewInt(100))
+	if err == ErrSuspended {
+		log.Println("payouts are suspended")
+	}
+}
+
+func TestKillSwitch(t *testing.T) {
+	var (
+		mutations int32
+		node      = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			switch request.URL.Path {
+			case "/api/v1/address":
+				writer.Write([]byte(`{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+			default:
+				atomic.AddInt32(&mutations, 1)
+				writer.Write([]byte(`{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2"}`))
+			}
+		}))
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	)
+	defer node.Close()
+
+	dir, err := ioutil.TempDir("", "killswitch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		file         = filepath.Join(dir, "suspended")
+		env          = "RAIDEN_TEST_SUSPENDED"
+		killSwitch   = NewKillSwitch(file, env)
+		raidenClient = New(&config.Config{Host: node.URL, APIVersion: "v1"}, WithRetry(&Retry{Attempts: 3}), WithKillSwitch(killSwitch))
+		ctx          = context.Background()
+	)
+
+	register := func() error {
+		_, err := raidenClient.Tokens().Register(ctx, tokenAddress)
+		return err
+	}
+
+	require.NoError(t, register())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&mutations))
+
+	killSwitch.Suspend()
+	assert.True(t, killSwitch.Suspended())
+
+	// the reads go on, the mutations are not sent
+	_, err = raidenClient.Address().Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, ErrSuspended, register())
+	assert.True(t, raidenerrors.HasCode(register(), raidenerrors.CodeClientSuspended))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&mutations))
+
+	killSwitch.Resume()
+	require.NoError(t, register())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&mutations))
+
+	t.Run("file", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(file, nil, 0600))
+		assert.Equal(t, ErrSuspended, register())
+
+		// resuming does not release the file
+		killSwitch.Resume()
+		assert.True(t, killSwitch.Suspended())
+
+		require.NoError(t, os.Remove(file))
+		assert.NoError(t, register())
+	})
+
+	t.Run("environment", func(t *testing.T) {
+		defer os.Unsetenv(env)
+
+		for value, expected := range map[string]bool{"1": true, "yes": true, "false": false, "0": false, "": false} {
+			require.NoError(t, os.Setenv(env, value))
+			assert.Equal(t, expected, killSwitch.Suspended(), value)
+		}
+	})
+}
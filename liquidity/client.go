@@ -0,0 +1,24 @@
+package liquidity
+
+import (
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+var (
+	_ Getter = &Client{}
+)
+
+// NewClient creates a new liquidity client that derives the liquidity of a Raiden
+// node from its channels and pending transfers.
+func NewClient(config *config.Config, httpClient *http.Client) *Client {
+	return &Client{
+		Getter: NewGetter(config, httpClient),
+	}
+}
+
+// Client is a liquidity client that allows access to the liquidity of a Raiden node.
+type Client struct {
+	Getter
+}
@@ -0,0 +1,159 @@
+// Package liquidity derives the liquidity of a Raiden node from the balances of its
+// channels and the locks of its pending transfers, since the balances alone count
+// the amounts locked by payments still in flight as spendable and mislead capacity
+// planning.
+package liquidity
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net/http"
+	"sort"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Stats is the liquidity of a node in a token over its open Channels, in the
+// smallest unit of the token. Outbound is what the node can pay, its balances less
+// the amounts it locked towards its partners, counting the locks of the transfers it
+// mediates as the node does not tell their direction. Inbound is what the partners
+// can pay the node at least, the amounts the node moved to their side of the
+// channels, as the node does not report the deposits of its partners. Locked is the
+// amount locked by the pending transfers of the token, whatever their direction.
+type Stats struct {
+	TokenAddress common.Address `json:"token_address"`
+	Channels     int            `json:"channels"`
+	Outbound     *big.Int       `json:"outbound"`
+	Inbound      *big.Int       `json:"inbound"`
+	Locked       *big.Int       `json:"locked"`
+}
+
+// Derive returns the liquidity of the node in every token it has an open channel or
+// a pending transfer in, ordered by token address.
+func Derive(allChannels []*channels.Channel, transfers []*pendingtransfers.Transfer) []*Stats {
+	var (
+		byToken  = make(map[common.Address]*Stats)
+		outgoing = make(map[channelKey]*big.Int)
+		all      = make([]*Stats, 0)
+	)
+
+	stats := func(tokenAddress common.Address) *Stats {
+		if _, ok := byToken[tokenAddress]; !ok {
+			byToken[tokenAddress] = &Stats{
+				TokenAddress: tokenAddress,
+				Outbound:     new(big.Int),
+				Inbound:      new(big.Int),
+				Locked:       new(big.Int),
+			}
+		}
+
+		return byToken[tokenAddress]
+	}
+
+	for _, transfer := range transfers {
+		var locked = amounts.OrZero(transfer.LockedAmount)
+
+		stats(transfer.TokenAddress).Locked.Add(stats(transfer.TokenAddress).Locked, locked)
+
+		if transfer.Role != pendingtransfers.RoleTarget {
+			key := channelKey{transfer.TokenAddress, transfer.ChannelIdentifier}
+
+			if outgoing[key] == nil {
+				outgoing[key] = new(big.Int)
+			}
+
+			outgoing[key].Add(outgoing[key], locked)
+		}
+	}
+
+	for _, channel := range allChannels {
+		if channel.State != "opened" {
+			continue
+		}
+
+		var (
+			tokenStats = stats(channel.TokenAddress)
+			balance    = amounts.OrZero(channel.Balance)
+			funds      = new(big.Int).Sub(amounts.OrZero(channel.TotalDeposit), amounts.OrZero(channel.TotalWithdraw))
+			outbound   = new(big.Int).Set(balance)
+			moved      = new(big.Int).Sub(funds, balance)
+		)
+
+		if locked := outgoing[channelKey{channel.TokenAddress, channel.ChannelIdentifier}]; locked != nil {
+			outbound.Sub(outbound, locked)
+		}
+
+		tokenStats.Channels++
+		tokenStats.Outbound.Add(tokenStats.Outbound, positive(outbound))
+		tokenStats.Inbound.Add(tokenStats.Inbound, positive(moved))
+	}
+
+	for _, tokenStats := range byToken {
+		all = append(all, tokenStats)
+	}
+
+	sort.Slice(all, func(i, j int) bool {
+		return bytes.Compare(all[i].TokenAddress.Bytes(), all[j].TokenAddress.Bytes()) < 0
+	})
+
+	return all
+}
+
+// Getter is a generic interface to get the liquidity of a Raiden node.
+type Getter interface {
+	Stats(ctx context.Context) ([]*Stats, error)
+}
+
+var _ Getter = &defaultGetter{}
+
+// NewGetter creates a new default liquidity getter given a Raiden node configuration
+// and an http client.
+func NewGetter(config *config.Config, httpClient *http.Client) Getter {
+	return &defaultGetter{
+		channelLister:  channels.NewLister(config, httpClient),
+		transferLister: pendingtransfers.NewLister(config, httpClient),
+	}
+}
+
+type defaultGetter struct {
+	channelLister  channels.Lister
+	transferLister pendingtransfers.Lister
+}
+
+// Stats will list the channels and the pending transfers of the node and return its
+// liquidity in every token, see Derive.
+func (getter *defaultGetter) Stats(ctx context.Context) ([]*Stats, error) {
+	var (
+		err         error
+		allChannels []*channels.Channel
+		transfers   []*pendingtransfers.Transfer
+	)
+
+	if allChannels, err = getter.channelLister.ListAll(ctx); err != nil {
+		return nil, err
+	}
+
+	if transfers, err = getter.transferLister.ListAll(ctx); err != nil {
+		return nil, err
+	}
+
+	return Derive(allChannels, transfers), nil
+}
+
+type channelKey struct {
+	tokenAddress common.Address
+	identifier   int64
+}
+
+func positive(amount *big.Int) *big.Int {
+	if amount.Sign() < 0 {
+		return new(big.Int)
+	}
+
+	return amount
+}
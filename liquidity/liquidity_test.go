@@ -0,0 +1,91 @@
+package liquidity
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewGetter() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		getter = NewGetter(config, http.DefaultClient)
+	)
+
+	stats, err := getter.Stats(context.Background())
+	if err != nil {
+		fmt.Println("unable to get the liquidity:", err.Error())
+		return
+	}
+
+	for _, tokenStats := range stats {
+		fmt.Printf("%s: %s outbound, %s inbound, %s locked\n", tokenStats.TokenAddress.Hex(), tokenStats.Outbound, tokenStats.Inbound, tokenStats.Locked)
+	}
+}
+
+func TestDerive(t *testing.T) {
+	var (
+		dai  = common.HexToAddress("0x89d24A6b4CcB1B6fAA2625fE562bDD9a23260359")
+		weth = common.HexToAddress("0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+		hub  = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		shop = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+	)
+
+	stats := Derive([]*channels.Channel{
+		// 100 of the 1000 deposited were paid to the hub, 30 of the balance are locked
+		&channels.Channel{TokenAddress: weth, PartnerAddress: hub, ChannelIdentifier: 1, Balance: big.NewInt(900), TotalDeposit: big.NewInt(1000), State: "opened"},
+		// the shop paid 50 more than the node withdrew
+		&channels.Channel{TokenAddress: weth, PartnerAddress: shop, ChannelIdentifier: 2, Balance: big.NewInt(250), TotalDeposit: big.NewInt(300), TotalWithdraw: big.NewInt(100), State: "opened"},
+		&channels.Channel{TokenAddress: weth, PartnerAddress: hub, ChannelIdentifier: 3, Balance: big.NewInt(500), TotalDeposit: big.NewInt(500), State: "closed"},
+	}, []*pendingtransfers.Transfer{
+		&pendingtransfers.Transfer{TokenAddress: weth, ChannelIdentifier: 1, Role: pendingtransfers.RoleInitiator, LockedAmount: big.NewInt(30)},
+		&pendingtransfers.Transfer{TokenAddress: weth, ChannelIdentifier: 2, Role: pendingtransfers.RoleTarget, LockedAmount: big.NewInt(5)},
+		&pendingtransfers.Transfer{TokenAddress: dai, ChannelIdentifier: 7, Role: pendingtransfers.RoleMediator, LockedAmount: big.NewInt(12)},
+	})
+
+	assert.Equal(t, []*Stats{
+		&Stats{TokenAddress: dai, Channels: 0, Outbound: big.NewInt(0), Inbound: big.NewInt(0), Locked: big.NewInt(12)},
+		&Stats{TokenAddress: weth, Channels: 2, Outbound: big.NewInt(1120), Inbound: big.NewInt(100), Locked: big.NewInt(35)},
+	}, stats)
+}
+
+func TestGetter(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, `[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000,"total_deposit":35000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":20,"initiator":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","locked_amount":119,"payment_identifier":1,"role":"initiator","target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","transferred_amount":331}]`))
+
+	stats, err := NewGetter(config, http.DefaultClient).Stats(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Stats{
+		&Stats{TokenAddress: tokenAddress, Channels: 1, Outbound: big.NewInt(24999881), Inbound: big.NewInt(10000000), Locked: big.NewInt(119)},
+	}, stats)
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+
+	_, err = NewGetter(config, http.DefaultClient).Stats(context.Background())
+	assert.EqualError(t, err, "EOF")
+}
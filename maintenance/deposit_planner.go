@@ -0,0 +1,250 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/history"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultCoverage is how long the initial deposit of a channel should cover its
+	// payments when no coverage is given.
+	DefaultCoverage = 7 * 24 * time.Hour
+	// DefaultLeadTime is the time a deposit takes to be made on chain, during which
+	// payments keep draining the channel, when no lead time is given.
+	DefaultLeadTime = time.Hour
+	// DefaultHistoryWindow is how far back balance samples are read when no window is
+	// given.
+	DefaultHistoryWindow = 30 * 24 * time.Hour
+)
+
+// ErrNoExpectedPayments is returned when planning deposits without the expected size
+// and frequency of the payments of the token.
+var ErrNoExpectedPayments = errors.New("deposit planning requires the expected size and frequency of payments")
+
+// PlanOptions configures the deposit planner. PaymentSize and PaymentsPerDay are the
+// expected payments of the token, shared evenly by its open channels unless History
+// holds balance samples of a channel, see history.Recorder, in which case the
+// payments observed within HistoryWindow are used instead.
+type PlanOptions struct {
+	PaymentSize    int64
+	PaymentsPerDay float64
+	Coverage       time.Duration
+	LeadTime       time.Duration
+	History        history.Store
+	HistoryWindow  time.Duration
+}
+
+// DepositRecommendation is the recommended deposit of a channel: InitialDeposit
+// covers its outflow for the coverage period, and a top-up is due once its balance
+// falls below TopUpThreshold, which covers its largest payment and its outflow during
+// the lead time of a deposit. FromHistory tells whether the outflow and largest
+// payment were observed in the balance history of the channel.
+type DepositRecommendation struct {
+	PartnerAddress    common.Address
+	ChannelIdentifier int64
+	DailyOutflow      *big.Int
+	LargestPayment    *big.Int
+	InitialDeposit    *big.Int
+	TopUpThreshold    *big.Int
+	FromHistory       bool
+}
+
+// DepositPlan holds the recommended deposits of the open channels of a token, and of
+// a new channel taking its share of the expected payments.
+type DepositPlan struct {
+	TokenAddress common.Address
+	NewChannel   *DepositRecommendation
+	Channels     []*DepositRecommendation
+}
+
+// DepositPlanner is a generic interface to recommend the deposits of the channels of
+// a token.
+type DepositPlanner interface {
+	Plan(ctx context.Context, tokenAddress common.Address, options *PlanOptions) (*DepositPlan, error)
+}
+
+// NewDepositPlanner creates a new default deposit planner given a Raiden node
+// configuration and an http client.
+func NewDepositPlanner(config *config.Config, httpClient *http.Client) DepositPlanner {
+	return &defaultDepositPlanner{
+		channelLister: channels.NewLister(config, httpClient),
+		now:           config.Time().Now,
+	}
+}
+
+type defaultDepositPlanner struct {
+	channelLister channels.Lister
+	now           func() time.Time
+}
+
+// Plan will recommend the initial deposit and top-up threshold of every open channel
+// of the token, and of a new one. Failing to read the history of a channel falls back
+// to the expected payments.
+func (planner *defaultDepositPlanner) Plan(ctx context.Context, tokenAddress common.Address, options *PlanOptions) (*DepositPlan, error) {
+	var (
+		err         error
+		channelList []*channels.Channel
+		open        = make([]*channels.Channel, 0)
+		plan        = &DepositPlan{
+			TokenAddress: tokenAddress,
+			Channels:     make([]*DepositRecommendation, 0),
+		}
+	)
+
+	if options == nil || options.PaymentSize <= 0 || options.PaymentsPerDay <= 0 {
+		return nil, ErrNoExpectedPayments
+	}
+
+	if channelList, err = planner.channelLister.ListToken(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	for _, channel := range channelList {
+		if channel.State == "opened" {
+			open = append(open, channel)
+		}
+	}
+
+	// the expected payments of the token are shared by the open channels and the new one
+	expected := expectedOutflow(options, len(open)+1)
+
+	plan.NewChannel = recommend(options, expected, big.NewInt(options.PaymentSize), false)
+
+	for _, channel := range open {
+		var (
+			outflow     = expected
+			largest     = big.NewInt(options.PaymentSize)
+			fromHistory bool
+		)
+
+		if options.History != nil {
+			var samples []*history.Sample
+
+			if samples, err = options.History.Query(tokenAddress, channel.PartnerAddress, planner.now().Add(-historyWindow(options)), planner.now()); err == nil {
+				if observed, observedLargest, ok := observedOutflow(samples); ok {
+					outflow, largest, fromHistory = observed, observedLargest, true
+				}
+			}
+		}
+
+		recommendation := recommend(options, outflow, largest, fromHistory)
+		recommendation.PartnerAddress = channel.PartnerAddress
+		recommendation.ChannelIdentifier = channel.ChannelIdentifier
+
+		plan.Channels = append(plan.Channels, recommendation)
+	}
+
+	return plan, nil
+}
+
+// recommend returns the recommended deposit of a channel with the daily outflow and
+// largest payment.
+func recommend(options *PlanOptions, dailyOutflow, largestPayment *big.Int, fromHistory bool) *DepositRecommendation {
+	var (
+		coverage = options.Coverage
+		leadTime = options.LeadTime
+	)
+
+	if coverage <= 0 {
+		coverage = DefaultCoverage
+	}
+
+	if leadTime <= 0 {
+		leadTime = DefaultLeadTime
+	}
+
+	initialDeposit := over(dailyOutflow, coverage)
+	if initialDeposit.Cmp(largestPayment) < 0 {
+		initialDeposit.Set(largestPayment)
+	}
+
+	topUpThreshold := over(dailyOutflow, leadTime)
+	topUpThreshold.Add(topUpThreshold, largestPayment)
+
+	return &DepositRecommendation{
+		DailyOutflow:   dailyOutflow,
+		LargestPayment: largestPayment,
+		InitialDeposit: initialDeposit,
+		TopUpThreshold: topUpThreshold,
+		FromHistory:    fromHistory,
+	}
+}
+
+// expectedOutflow returns the daily outflow of a channel sharing the expected payments
+// of the token with the given number of channels.
+func expectedOutflow(options *PlanOptions, shares int) *big.Int {
+	var outflow = new(big.Float).Mul(big.NewFloat(float64(options.PaymentSize)), big.NewFloat(options.PaymentsPerDay))
+
+	outflow.Quo(outflow, big.NewFloat(float64(shares)))
+
+	result, _ := outflow.Int(nil)
+
+	return result
+}
+
+// observedOutflow returns the daily outflow of a channel and its largest payment from
+// its balance samples, oldest first: every decrease of the balance between two samples
+// with the same total deposit is taken as payments. The outflow is not observed when
+// the samples do not span any time or show no payment.
+func observedOutflow(samples []*history.Sample) (*big.Int, *big.Int, bool) {
+	var (
+		total   = new(big.Int)
+		largest = new(big.Int)
+	)
+
+	if len(samples) < 2 {
+		return nil, nil, false
+	}
+
+	for i := 1; i < len(samples); i++ {
+		previous, current := samples[i-1], samples[i]
+
+		if previous.Balance == nil || current.Balance == nil || previous.TotalDeposit == nil || current.TotalDeposit == nil {
+			continue
+		}
+
+		if previous.TotalDeposit.Cmp(current.TotalDeposit) != 0 || current.Balance.Cmp(previous.Balance) >= 0 {
+			continue
+		}
+
+		decrease := new(big.Int).Sub(previous.Balance, current.Balance)
+		total.Add(total, decrease)
+
+		if decrease.Cmp(largest) > 0 {
+			largest.Set(decrease)
+		}
+	}
+
+	span := samples[len(samples)-1].Time.Sub(samples[0].Time)
+
+	if span <= 0 || total.Sign() == 0 {
+		return nil, nil, false
+	}
+
+	daily := new(big.Int).Mul(total, big.NewInt(int64(24*time.Hour)))
+
+	return daily.Quo(daily, big.NewInt(int64(span))), largest, true
+}
+
+// over returns the outflow of a period given the daily outflow.
+func over(dailyOutflow *big.Int, period time.Duration) *big.Int {
+	var amount = new(big.Int).Mul(dailyOutflow, big.NewInt(int64(period)))
+
+	return amount.Quo(amount, big.NewInt(int64(24*time.Hour)))
+}
+
+func historyWindow(options *PlanOptions) time.Duration {
+	if options.HistoryWindow <= 0 {
+		return DefaultHistoryWindow
+	}
+
+	return options.HistoryWindow
+}
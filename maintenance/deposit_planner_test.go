@@ -0,0 +1,165 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/history"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleDepositPlanner() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		planner      = NewDepositPlanner(config, http.DefaultClient)
+		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		plan         *DepositPlan
+		err          error
+	)
+
+	if plan, err = planner.Plan(context.Background(), tokenAddress, &PlanOptions{
+		PaymentSize:    1000,
+		PaymentsPerDay: 200,
+		History:        history.NewFileStore("balances.json"),
+	}); err != nil {
+		panic(fmt.Sprintf("unable to plan deposits: %s", err.Error()))
+	}
+
+	fmt.Printf("deposit %d in new channels, top up below %d\n", plan.NewChannel.InitialDeposit, plan.NewChannel.TopUpThreshold)
+
+	for _, channel := range plan.Channels {
+		fmt.Printf("%s: deposit %d, top up below %d\n", channel.PartnerAddress.Hex(), channel.InitialDeposit, channel.TopUpThreshold)
+	}
+}
+
+func TestDepositPlanner(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		first        = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		second       = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		channelsURL  = "http://localhost:5001/api/v1/channels/" + tokenAddress.Hex()
+		channelsJSON = fmt.Sprintf(`[
+			{"channel_identifier":1,"partner_address":"%s","balance":150,"total_deposit":200,"state":"opened"},
+			{"channel_identifier":2,"partner_address":"%s","balance":10,"total_deposit":100,"state":"opened"},
+			{"channel_identifier":3,"partner_address":"0x0000000000000000000000000000000000000003","balance":0,"total_deposit":100,"state":"closed"}
+		]`, first.Hex(), second.Hex())
+		now      = time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+		expected = &DepositRecommendation{
+			DailyOutflow:   big.NewInt(100),
+			LargestPayment: big.NewInt(10),
+			InitialDeposit: big.NewInt(700),
+			TopUpThreshold: big.NewInt(14),
+		}
+	)
+
+	sample := func(age time.Duration, balance, totalDeposit int64) *history.Sample {
+		return &history.Sample{
+			TokenAddress:   tokenAddress,
+			PartnerAddress: first,
+			Balance:        big.NewInt(balance),
+			TotalDeposit:   big.NewInt(totalDeposit),
+			Time:           now.Add(-age),
+		}
+	}
+
+	withChannel := func(recommendation DepositRecommendation, partner common.Address, identifier int64) *DepositRecommendation {
+		recommendation.PartnerAddress = partner
+		recommendation.ChannelIdentifier = identifier
+
+		return &recommendation
+	}
+
+	type testcase struct {
+		name          string
+		options       *PlanOptions
+		expectedPlan  *DepositPlan
+		expectedError error
+	}
+
+	store := history.NewMemoryStore()
+	require.NoError(t, store.Append([]*history.Sample{
+		sample(48*time.Hour, 100, 100),
+		sample(36*time.Hour, 80, 100),
+		sample(24*time.Hour, 80, 100),
+		sample(12*time.Hour, 180, 200),
+		sample(0, 150, 200),
+	}))
+
+	testcases := []testcase{
+		testcase{
+			name:    "expected payments shared by the channels",
+			options: &PlanOptions{PaymentSize: 10, PaymentsPerDay: 30},
+			expectedPlan: &DepositPlan{
+				TokenAddress: tokenAddress,
+				NewChannel:   expected,
+				Channels: []*DepositRecommendation{
+					withChannel(*expected, first, 1),
+					withChannel(*expected, second, 2),
+				},
+			},
+		},
+		testcase{
+			name:    "payments observed in the balance history",
+			options: &PlanOptions{PaymentSize: 10, PaymentsPerDay: 30, History: store, HistoryWindow: 72 * time.Hour},
+			expectedPlan: &DepositPlan{
+				TokenAddress: tokenAddress,
+				NewChannel:   expected,
+				Channels: []*DepositRecommendation{
+					&DepositRecommendation{
+						PartnerAddress:    first,
+						ChannelIdentifier: 1,
+						DailyOutflow:      big.NewInt(25),
+						LargestPayment:    big.NewInt(30),
+						InitialDeposit:    big.NewInt(175),
+						TopUpThreshold:    big.NewInt(31),
+						FromHistory:       true,
+					},
+					withChannel(*expected, second, 2),
+				},
+			},
+		},
+		testcase{
+			name:          "no expected payments",
+			options:       &PlanOptions{PaymentSize: 10},
+			expectedError: ErrNoExpectedPayments,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var planner = NewDepositPlanner(config, http.DefaultClient).(*defaultDepositPlanner)
+
+			planner.now = func() time.Time { return now.Add(time.Second) }
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", channelsURL, httpmock.NewStringResponder(http.StatusOK, channelsJSON))
+
+			plan, err := planner.Plan(context.Background(), tokenAddress, tc.options)
+
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedPlan, plan)
+		})
+	}
+}
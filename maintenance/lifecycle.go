@@ -0,0 +1,272 @@
+package maintenance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/connections"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The kinds of the actions of a lifecycle plan.
+const (
+	ActionOpen    ActionKind = "open"
+	ActionDeposit ActionKind = "deposit"
+	ActionJoin    ActionKind = "join"
+	ActionClose   ActionKind = "close"
+)
+
+// ActionKind is what an action of a lifecycle plan does.
+type ActionKind string
+
+// Spec is the desired state of the channels of a Raiden node, e.g. read from a JSON
+// file kept under version control.
+type Spec struct {
+	Tokens []*TokenSpec `json:"tokens"`
+}
+
+// TokenSpec is the desired state of a token network. The node has an open channel
+// with the partner of every one of Channels, holding at least its total deposit, and
+// joined the network with Funds when they are not nil. The channels with other
+// partners are closed when CloseOthers is set, and left alone otherwise.
+type TokenSpec struct {
+	TokenAddress common.Address `json:"token_address"`
+	Channels     []*ChannelSpec `json:"channels,omitempty"`
+	Funds        *big.Int       `json:"funds,omitempty"`
+	CloseOthers  bool           `json:"close_others,omitempty"`
+}
+
+// ChannelSpec is the desired state of a channel with a partner, opened with the
+// SettleTimeout, channels.DefaultSettleTimeout when zero.
+type ChannelSpec struct {
+	PartnerAddress common.Address `json:"partner_address"`
+	TotalDeposit   *big.Int       `json:"total_deposit"`
+	SettleTimeout  int64          `json:"settle_timeout,omitempty"`
+}
+
+// Action is a change of the state of the node bringing it to its spec. The deposit
+// of a channel goes from Before to TotalDeposit, Before being nil for a channel to
+// open, and Funds are the funds of a network to join.
+type Action struct {
+	Kind           ActionKind
+	TokenAddress   common.Address
+	PartnerAddress common.Address
+	Before         *big.Int
+	TotalDeposit   *big.Int
+	SettleTimeout  int64
+	Funds          *big.Int
+	// Applied is set once the action was applied, and Err when it failed.
+	Applied bool
+	Err     error
+}
+
+func (action *Action) String() string {
+	switch action.Kind {
+	case ActionJoin:
+		return fmt.Sprintf("join token network %s", action.TokenAddress.Hex())
+	case ActionDeposit:
+		return fmt.Sprintf("deposit to channel with %s in %s", action.PartnerAddress.Hex(), action.TokenAddress.Hex())
+	default:
+		return fmt.Sprintf("%s channel with %s in %s", action.Kind, action.PartnerAddress.Hex(), action.TokenAddress.Hex())
+	}
+}
+
+// LifecyclePlan lists the actions bringing the node to a spec, for operators to review
+// before they are applied.
+type LifecyclePlan struct {
+	Actions []*Action
+}
+
+// Empty tells whether the node is in the state of the spec.
+func (plan *LifecyclePlan) Empty() bool {
+	return len(plan.Actions) == 0
+}
+
+// String returns the plan as a diff in the style of terraform: a line for every
+// action, starting with "+" for the channels to open and the networks to join, "~"
+// for the deposits and "-" for the channels to close, followed by the amounts it
+// changes, then a summary line counting the actions of every kind.
+func (plan *LifecyclePlan) String() string {
+	var (
+		buffer = &bytes.Buffer{}
+		counts = make(map[ActionKind]int)
+	)
+
+	if plan.Empty() {
+		return "No changes. The node is in the state of the spec.\n"
+	}
+
+	for _, action := range plan.Actions {
+		counts[action.Kind]++
+
+		switch action.Kind {
+		case ActionOpen:
+			fmt.Fprintf(buffer, "+ channel with %s in %s\n", action.PartnerAddress.Hex(), action.TokenAddress.Hex())
+			fmt.Fprintf(buffer, "    total_deposit: %s\n", amounts.OrZero(action.TotalDeposit))
+			fmt.Fprintf(buffer, "    settle_timeout: %d\n", action.SettleTimeout)
+		case ActionDeposit:
+			fmt.Fprintf(buffer, "~ channel with %s in %s\n", action.PartnerAddress.Hex(), action.TokenAddress.Hex())
+			fmt.Fprintf(buffer, "    total_deposit: %s -> %s\n", amounts.OrZero(action.Before), amounts.OrZero(action.TotalDeposit))
+		case ActionJoin:
+			fmt.Fprintf(buffer, "+ connection to %s\n", action.TokenAddress.Hex())
+			fmt.Fprintf(buffer, "    funds: %s\n", amounts.OrZero(action.Funds))
+		case ActionClose:
+			fmt.Fprintf(buffer, "- channel with %s in %s\n", action.PartnerAddress.Hex(), action.TokenAddress.Hex())
+			fmt.Fprintf(buffer, "    total_deposit: %s\n", amounts.OrZero(action.Before))
+		}
+	}
+
+	fmt.Fprintf(buffer, "\nPlan: %d to open, %d to deposit, %d to join, %d to close.\n", counts[ActionOpen], counts[ActionDeposit], counts[ActionJoin], counts[ActionClose])
+
+	return buffer.String()
+}
+
+// Lifecycle is a generic interface to bring the channels of a Raiden node to a spec,
+// planning the actions first so that they can be reviewed before they are applied.
+type Lifecycle interface {
+	Plan(ctx context.Context, spec *Spec) (*LifecyclePlan, error)
+	Apply(ctx context.Context, plan *LifecyclePlan) error
+}
+
+// NewLifecycle creates a new default lifecycle manager given a Raiden node
+// configuration and an http client.
+func NewLifecycle(config *config.Config, httpClient *http.Client) Lifecycle {
+	return &defaultLifecycle{
+		channelClient:    channels.NewClient(config, httpClient),
+		connectionLister: connections.NewLister(config, httpClient),
+		joiner:           connections.NewJoiner(config, httpClient),
+	}
+}
+
+type defaultLifecycle struct {
+	channelClient    *channels.Client
+	connectionLister connections.Lister
+	joiner           connections.Joiner
+}
+
+// Plan will compare the channels and connections of the node with the spec and
+// return the actions bringing the node to it, token by token in the order of the
+// spec: the channels to open and deposits to add, the networks to join, then the
+// channels to close, by partner address. Deposits are never lowered, nor the
+// channels which are not open touched.
+func (lifecycle *defaultLifecycle) Plan(ctx context.Context, spec *Spec) (*LifecyclePlan, error) {
+	var (
+		err         error
+		plan        = &LifecyclePlan{Actions: make([]*Action, 0)}
+		connected   connections.Connections
+		channelList []*channels.Channel
+	)
+
+	if spec == nil {
+		return plan, nil
+	}
+
+	for _, tokenSpec := range spec.Tokens {
+		if tokenSpec.Funds != nil && connected == nil {
+			if connected, err = lifecycle.connectionLister.List(ctx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, tokenSpec := range spec.Tokens {
+		var (
+			opened  = make(map[common.Address]*channels.Channel)
+			wanted  = make(map[common.Address]bool)
+			closing = make([]*Action, 0)
+		)
+
+		if channelList, err = lifecycle.channelClient.ListToken(ctx, tokenSpec.TokenAddress); err != nil {
+			return nil, err
+		}
+
+		for _, channel := range channelList {
+			if channel.State == "opened" {
+				opened[channel.PartnerAddress] = channel
+			}
+		}
+
+		for _, channelSpec := range tokenSpec.Channels {
+			var (
+				channel, ok = opened[channelSpec.PartnerAddress]
+				desired     = amounts.OrZero(channelSpec.TotalDeposit)
+			)
+
+			wanted[channelSpec.PartnerAddress] = true
+
+			switch {
+			case !ok:
+				var settleTimeout = channelSpec.SettleTimeout
+
+				if settleTimeout == 0 {
+					settleTimeout = channels.DefaultSettleTimeout
+				}
+
+				plan.Actions = append(plan.Actions, &Action{Kind: ActionOpen, TokenAddress: tokenSpec.TokenAddress, PartnerAddress: channelSpec.PartnerAddress, TotalDeposit: desired, SettleTimeout: settleTimeout})
+			case desired.Cmp(amounts.OrZero(channel.TotalDeposit)) > 0:
+				plan.Actions = append(plan.Actions, &Action{Kind: ActionDeposit, TokenAddress: tokenSpec.TokenAddress, PartnerAddress: channelSpec.PartnerAddress, Before: amounts.OrZero(channel.TotalDeposit), TotalDeposit: desired})
+			}
+		}
+
+		if tokenSpec.Funds != nil {
+			if _, ok := connected[tokenSpec.TokenAddress]; !ok {
+				plan.Actions = append(plan.Actions, &Action{Kind: ActionJoin, TokenAddress: tokenSpec.TokenAddress, Funds: tokenSpec.Funds})
+			}
+		}
+
+		if tokenSpec.CloseOthers {
+			for partnerAddress, channel := range opened {
+				if !wanted[partnerAddress] {
+					closing = append(closing, &Action{Kind: ActionClose, TokenAddress: tokenSpec.TokenAddress, PartnerAddress: partnerAddress, Before: amounts.OrZero(channel.TotalDeposit)})
+				}
+			}
+
+			sort.Slice(closing, func(i, j int) bool {
+				return bytes.Compare(closing[i].PartnerAddress.Bytes(), closing[j].PartnerAddress.Bytes()) < 0
+			})
+		}
+
+		plan.Actions = append(plan.Actions, closing...)
+	}
+
+	return plan, nil
+}
+
+// Apply will apply the actions of the plan in order, stopping at the first one that
+// failed, whose Err is set, with an error naming it. The actions already Applied are
+// skipped, so that a plan is applied again once the cause of a failure is fixed.
+func (lifecycle *defaultLifecycle) Apply(ctx context.Context, plan *LifecyclePlan) error {
+	for _, action := range plan.Actions {
+		if action.Applied {
+			continue
+		}
+
+		switch action.Kind {
+		case ActionOpen:
+			_, action.Err = lifecycle.channelClient.Open(ctx, action.TokenAddress, action.PartnerAddress, action.TotalDeposit, action.SettleTimeout)
+		case ActionDeposit:
+			_, action.Err = lifecycle.channelClient.IncreaseDeposit(ctx, action.TokenAddress, action.PartnerAddress, action.TotalDeposit)
+		case ActionJoin:
+			action.Err = lifecycle.joiner.Join(ctx, action.TokenAddress, action.Funds)
+		case ActionClose:
+			_, action.Err = lifecycle.channelClient.Close(ctx, action.TokenAddress, action.PartnerAddress)
+		default:
+			action.Err = fmt.Errorf("unknown action %q", action.Kind)
+		}
+
+		if action.Err != nil {
+			return fmt.Errorf("unable to %s: %s", action, action.Err.Error())
+		}
+
+		action.Applied = true
+	}
+
+	return nil
+}
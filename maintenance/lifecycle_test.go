@@ -0,0 +1,133 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleLifecycle() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		lifecycle = NewLifecycle(config, http.DefaultClient)
+		spec      = &Spec{Tokens: []*TokenSpec{
+			&TokenSpec{
+				TokenAddress: common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359"), // DAI Stablecoin
+				Channels: []*ChannelSpec{
+					&ChannelSpec{PartnerAddress: common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"), TotalDeposit: big.NewInt(1000)},
+				},
+			},
+		}}
+	)
+
+	plan, err := lifecycle.Plan(context.Background(), spec)
+	if err != nil {
+		panic(fmt.Sprintf("unable to plan: %s", err.Error()))
+	}
+
+	// the operator reviews the plan before it is applied
+	fmt.Print(plan)
+
+	if err = lifecycle.Apply(context.Background(), plan); err != nil {
+		fmt.Fprintln(os.Stderr, err.Error())
+	}
+}
+
+func TestLifecycle(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		token    = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		partners = []string{
+			"0x0000000000000000000000000000000000000001",
+			"0x0000000000000000000000000000000000000002",
+			"0x0000000000000000000000000000000000000003",
+			"0x0000000000000000000000000000000000000004",
+			"0x0000000000000000000000000000000000000005",
+		}
+		channelsJSON = fmt.Sprintf(`[
+			{"channel_identifier":2,"token_address":"%[1]s","partner_address":"%[3]s","total_deposit":50,"state":"opened"},
+			{"channel_identifier":3,"token_address":"%[1]s","partner_address":"%[4]s","total_deposit":200,"state":"opened"},
+			{"channel_identifier":4,"token_address":"%[1]s","partner_address":"%[5]s","total_deposit":30,"state":"opened"},
+			{"channel_identifier":5,"token_address":"%[1]s","partner_address":"%[6]s","total_deposit":10,"state":"closed"}
+		]`, token, partners[0], partners[1], partners[2], partners[3], partners[4])
+		spec = &Spec{Tokens: []*TokenSpec{
+			&TokenSpec{
+				TokenAddress: common.HexToAddress(token),
+				Channels: []*ChannelSpec{
+					&ChannelSpec{PartnerAddress: common.HexToAddress(partners[0]), TotalDeposit: big.NewInt(100)},
+					&ChannelSpec{PartnerAddress: common.HexToAddress(partners[1]), TotalDeposit: big.NewInt(100)},
+					&ChannelSpec{PartnerAddress: common.HexToAddress(partners[2]), TotalDeposit: big.NewInt(100)},
+				},
+				Funds:       big.NewInt(1000),
+				CloseOthers: true,
+			},
+		}}
+		lifecycle = NewLifecycle(config, http.DefaultClient)
+		ctx       = context.Background()
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/"+token, httpmock.NewStringResponder(http.StatusOK, channelsJSON))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/connections", httpmock.NewStringResponder(http.StatusOK, `{}`))
+
+	plan, err := lifecycle.Plan(ctx, spec)
+	require.NoError(t, err)
+
+	// the deposit of partner 3 is never lowered, the closed channel with partner 5 is
+	// left alone
+	assert.Equal(t, `+ channel with 0x0000000000000000000000000000000000000001 in 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8
+    total_deposit: 100
+    settle_timeout: 500
+~ channel with 0x0000000000000000000000000000000000000002 in 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8
+    total_deposit: 50 -> 100
++ connection to 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8
+    funds: 1000
+- channel with 0x0000000000000000000000000000000000000004 in 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8
+    total_deposit: 30
+
+Plan: 1 to open, 1 to deposit, 1 to join, 1 to close.
+`, plan.String())
+
+	empty, err := lifecycle.Plan(ctx, &Spec{Tokens: []*TokenSpec{&TokenSpec{TokenAddress: common.HexToAddress(token)}}})
+	require.NoError(t, err)
+	assert.True(t, empty.Empty())
+	assert.Equal(t, "No changes. The node is in the state of the spec.\n", empty.String())
+
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusCreated, fmt.Sprintf(`{"token_address":"%s","partner_address":"%s","total_deposit":100,"state":"opened"}`, token, partners[0])))
+	httpmock.RegisterResponder("PATCH", fmt.Sprintf("http://localhost:5001/api/v1/channels/%s/%s", token, partners[1]), httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`{"token_address":"%s","partner_address":"%s","total_deposit":100,"state":"opened"}`, token, partners[1])))
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/connections/"+token, httpmock.NewStringResponder(http.StatusConflict, `{"errors":"insufficient funds"}`))
+
+	err = lifecycle.Apply(ctx, plan)
+	assert.EqualError(t, err, "unable to join token network 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8: raiden node error 409: insufficient funds")
+	assert.True(t, plan.Actions[0].Applied)
+	assert.True(t, plan.Actions[1].Applied)
+	assert.False(t, plan.Actions[2].Applied)
+	assert.Error(t, plan.Actions[2].Err)
+	assert.False(t, plan.Actions[3].Applied, "the actions after the failed one are not applied")
+
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/connections/"+token, httpmock.NewStringResponder(http.StatusNoContent, ``))
+	httpmock.RegisterResponder("PATCH", fmt.Sprintf("http://localhost:5001/api/v1/channels/%s/%s", token, partners[3]), httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`{"token_address":"%s","partner_address":"%s","state":"closed"}`, token, partners[3])))
+
+	require.NoError(t, lifecycle.Apply(ctx, plan))
+
+	calls := httpmock.GetCallCountInfo()
+	assert.Equal(t, 1, calls["PUT http://localhost:5001/api/v1/channels"], "the applied actions are not applied again")
+	assert.Equal(t, 1, calls[fmt.Sprintf("PATCH http://localhost:5001/api/v1/channels/%s/%s", token, partners[3])])
+}
@@ -0,0 +1,159 @@
+// Package maintenance provides routines keeping the channels of a Raiden node in good
+// shape, such as closing dead channels to reclaim the deposits locked in them.
+package maintenance
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+)
+
+// DefaultIdlePeriod is the period without activity after which an empty channel is
+// considered stale when no period is given.
+const DefaultIdlePeriod = 30 * 24 * time.Hour
+
+// PruneOptions configures which channels are pruned. Channels are only closed when
+// DryRun is false.
+type PruneOptions struct {
+	IdlePeriod time.Duration
+	DryRun     bool
+}
+
+// StaleChannel is an opened channel with a zero balance and no activity within the
+// idle period. LastActivity is the time of the latest payment made in it.
+type StaleChannel struct {
+	*channels.Channel
+	LastActivity time.Time
+	Closed       bool
+	Err          error
+}
+
+// PruneReport lists the stale channels found, and whether each of them was closed.
+type PruneReport struct {
+	DryRun   bool
+	Channels []*StaleChannel
+}
+
+// Pruner is a generic interface to find and close stale channels.
+type Pruner interface {
+	Prune(ctx context.Context, options *PruneOptions) (*PruneReport, error)
+}
+
+// NewPruner creates a new default stale channel pruner given a Raiden node
+// configuration and an http client. The idle periods are measured on the Clock of the
+// configuration.
+func NewPruner(config *config.Config, httpClient *http.Client) Pruner {
+	return &defaultPruner{
+		channelClient:  channels.NewClient(config, httpClient),
+		paymentLister:  payments.NewLister(config, httpClient),
+		transferLister: pendingtransfers.NewLister(config, httpClient),
+		now:            config.Time().Now,
+	}
+}
+
+type defaultPruner struct {
+	channelClient  *channels.Client
+	paymentLister  payments.Lister
+	transferLister pendingtransfers.Lister
+	now            func() time.Time
+}
+
+// Prune will close every opened channel that has a zero balance, no pending transfers
+// and no payment events within the idle period. A channel without any payment event
+// is left open, as the node does not tell when it was opened and it may just have
+// been. Failing to query the activity of a channel is recorded on it and the channel
+// is left open.
+func (pruner *defaultPruner) Prune(ctx context.Context, options *PruneOptions) (*PruneReport, error) {
+	var (
+		err         error
+		allChannels []*channels.Channel
+		idlePeriod  = DefaultIdlePeriod
+		report      = &PruneReport{
+			DryRun:   options != nil && options.DryRun,
+			Channels: make([]*StaleChannel, 0),
+		}
+	)
+
+	if options != nil && options.IdlePeriod > 0 {
+		idlePeriod = options.IdlePeriod
+	}
+
+	if allChannels, err = pruner.channelClient.ListAll(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, channel := range allChannels {
+		var (
+			stale        bool
+			lastActivity time.Time
+		)
+
+		if channel.State != "opened" || amounts.OrZero(channel.Balance).Sign() != 0 {
+			continue
+		}
+
+		if stale, lastActivity, err = pruner.stale(ctx, channel, idlePeriod); err != nil {
+			report.Channels = append(report.Channels, &StaleChannel{Channel: channel, Err: err})
+			continue
+		}
+
+		if !stale {
+			continue
+		}
+
+		staleChannel := &StaleChannel{
+			Channel:      channel,
+			LastActivity: lastActivity,
+		}
+
+		if !report.DryRun {
+			if _, staleChannel.Err = pruner.channelClient.Close(ctx, channel.TokenAddress, channel.PartnerAddress); staleChannel.Err == nil {
+				staleChannel.Closed = true
+			}
+		}
+
+		report.Channels = append(report.Channels, staleChannel)
+	}
+
+	return report, nil
+}
+
+func (pruner *defaultPruner) stale(ctx context.Context, channel *channels.Channel, idlePeriod time.Duration) (bool, time.Time, error) {
+	var (
+		err          error
+		lastActivity time.Time
+		transfers    []*pendingtransfers.Transfer
+		events       []*payments.Event
+	)
+
+	if transfers, err = pruner.transferLister.ListChannel(ctx, channel.TokenAddress, channel.PartnerAddress); err != nil {
+		return false, lastActivity, err
+	}
+
+	if len(transfers) > 0 {
+		return false, lastActivity, nil
+	}
+
+	if events, err = pruner.paymentLister.List(ctx, channel.TokenAddress, channel.PartnerAddress); err != nil {
+		return false, lastActivity, err
+	}
+
+	for _, event := range events {
+		if event.LogTime.After(lastActivity) {
+			lastActivity = event.LogTime
+		}
+	}
+
+	// without any activity the age of the channel is unknown, a new channel is not stale
+	if lastActivity.IsZero() {
+		return false, lastActivity, nil
+	}
+
+	return pruner.now().Sub(lastActivity) >= idlePeriod, lastActivity, nil
+}
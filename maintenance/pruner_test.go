@@ -0,0 +1,148 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExamplePruner() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		pruner = NewPruner(config, http.DefaultClient)
+		report *PruneReport
+		err    error
+	)
+
+	if report, err = pruner.Prune(context.Background(), &PruneOptions{IdlePeriod: 90 * 24 * time.Hour, DryRun: true}); err != nil {
+		panic(fmt.Sprintf("unable to prune channels: %s", err.Error()))
+	}
+
+	for _, channel := range report.Channels {
+		fmt.Printf("channel %d with %s is stale since %s\n", channel.ChannelIdentifier, channel.PartnerAddress.Hex(), channel.LastActivity)
+	}
+}
+
+func TestPruner(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		token    = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		partners = []string{
+			"0x0000000000000000000000000000000000000001",
+			"0x0000000000000000000000000000000000000002",
+			"0x0000000000000000000000000000000000000003",
+			"0x0000000000000000000000000000000000000004",
+			"0x0000000000000000000000000000000000000005",
+			"0x0000000000000000000000000000000000000006",
+		}
+		channelsJSON = fmt.Sprintf(`[
+			{"channel_identifier":1,"token_address":"%[1]s","partner_address":"%[2]s","balance":0,"state":"opened"},
+			{"channel_identifier":2,"token_address":"%[1]s","partner_address":"%[3]s","balance":0,"state":"opened"},
+			{"channel_identifier":3,"token_address":"%[1]s","partner_address":"%[4]s","balance":0,"state":"opened"},
+			{"channel_identifier":4,"token_address":"%[1]s","partner_address":"%[5]s","balance":10,"state":"opened"},
+			{"channel_identifier":5,"token_address":"%[1]s","partner_address":"%[6]s","balance":0,"state":"opened"},
+			{"channel_identifier":6,"token_address":"%[1]s","partner_address":"%[7]s","balance":0,"state":"closed"}
+		]`, token, partners[0], partners[1], partners[2], partners[3], partners[4], partners[5])
+		oldEvent    = `[{"event":"EventPaymentSentSuccess","amount":5,"identifier":1,"log_time":"2018-10-30T07:03:52Z"}]`
+		recentEvent = fmt.Sprintf(`[{"event":"EventPaymentReceivedSuccess","amount":5,"identifier":2,"log_time":"%s"}]`, time.Now().UTC().Format(time.RFC3339))
+	)
+
+	type testcase struct {
+		name           string
+		options        *PruneOptions
+		expectedStale  []int64
+		expectedClosed bool
+		expectedCloses int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "dry run",
+			options:        &PruneOptions{IdlePeriod: 24 * time.Hour, DryRun: true},
+			expectedStale:  []int64{5},
+			expectedClosed: false,
+			expectedCloses: 0,
+		},
+		testcase{
+			name:           "closes stale channels",
+			options:        &PruneOptions{IdlePeriod: 24 * time.Hour},
+			expectedStale:  []int64{5},
+			expectedClosed: true,
+			expectedCloses: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err    error
+				report *PruneReport
+				pruner = NewPruner(config, http.DefaultClient)
+				ctx    = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, channelsJSON))
+
+			for i, partner := range partners {
+				var (
+					events    = `[]`
+					transfers = `[]`
+				)
+
+				switch i {
+				case 1:
+					events = recentEvent
+				case 2:
+					transfers = `[{"channel_identifier":3,"locked_amount":5}]`
+				case 4:
+					events = oldEvent
+				}
+
+				httpmock.RegisterResponder("GET", fmt.Sprintf("http://localhost:5001/api/v1/payments/%s/%s", token, partner), httpmock.NewStringResponder(http.StatusOK, events))
+				httpmock.RegisterResponder("GET", fmt.Sprintf("http://localhost:5001/api/v1/pending_transfers/%s/%s", token, partner), httpmock.NewStringResponder(http.StatusOK, transfers))
+				httpmock.RegisterResponder("PATCH", fmt.Sprintf("http://localhost:5001/api/v1/channels/%s/%s", token, partner), httpmock.NewStringResponder(http.StatusOK, `{"state":"closed"}`))
+			}
+
+			report, err = pruner.Prune(ctx, tc.options)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.options.DryRun, report.DryRun)
+			require.Len(t, report.Channels, len(tc.expectedStale))
+
+			for i, channel := range report.Channels {
+				assert.NoError(t, channel.Err)
+				assert.Equal(t, tc.expectedStale[i], channel.ChannelIdentifier)
+				assert.Equal(t, tc.expectedClosed, channel.Closed)
+			}
+
+			// channel 1 has no payment event, it may just have been opened
+			assert.Equal(t, 2018, report.Channels[0].LastActivity.Year())
+
+			closes := 0
+			for call, count := range httpmock.GetCallCountInfo() {
+				if strings.HasPrefix(call, "PATCH ") {
+					closes += count
+				}
+			}
+
+			assert.Equal(t, tc.expectedCloses, closes)
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultTargetRatio is the share of its total deposit that the rebalancer aims to
+// keep as balance in every channel when no ratio is given.
+const DefaultTargetRatio = 0.5
+
+// ErrNoLimits is returned when rebalancing without a limit on the amount moved, which
+// would let a bad plan drain every channel of a token network.
+var ErrNoLimits = errors.New("rebalancing requires a maximum amount per move and per run")
+
+// RebalanceOptions configures the rebalancer. MaxMove and MaxTotal are required
+// safety limits on the amount moved by a single payment and by a whole run, moves
+// smaller than MinMove are not worth their fees and are skipped.
+type RebalanceOptions struct {
+	TargetRatio float64
+	MinMove     *big.Int
+	MaxMove     *big.Int
+	MaxTotal    *big.Int
+	DryRun      bool
+	// Audit is called with every move once it was made, or planned on a dry run.
+	Audit func(move *Move)
+	// Route returns the route of a move from our address back to it, see
+	// payments.SelfPayer, the node choosing the route when it is nil or returns none.
+	Route func(ctx context.Context, ourAddress common.Address, move *Move) ([]common.Address, error)
+}
+
+// Move is a single circular payment moving balance from a channel with a surplus to
+// a channel with a deficit of the same token network. The balances of both channels
+// are read again after the payment, since the node chooses the actual route, and
+// BalancesRead is false when they could not be.
+type Move struct {
+	TokenAddress common.Address
+	From         common.Address
+	To           common.Address
+	Amount       *big.Int
+	Identifier   int64
+	Route        []common.Address
+	FromBefore   *big.Int
+	ToBefore     *big.Int
+	FromAfter    *big.Int
+	ToAfter      *big.Int
+	Payment      *payments.Payment
+	Err          error
+	Started      time.Time
+	Finished     time.Time
+	DryRun       bool
+	BalancesRead bool
+	// Fields are the fields of the context of the run, see meta.WithFields, for the
+	// audit log to tell who made the move, with the Tenant of the configuration when
+	// the context has none.
+	Fields meta.Fields
+}
+
+// RebalanceReport lists every move made, or planned on a dry run, and the total
+// amount moved.
+type RebalanceReport struct {
+	Moves []*Move
+	Moved *big.Int
+}
+
+// Rebalancer is a generic interface to rebalance the channels of a Raiden node.
+type Rebalancer interface {
+	Rebalance(ctx context.Context, tokenAddress common.Address, options *RebalanceOptions) (*RebalanceReport, error)
+}
+
+// NewRebalancer creates a new default rebalancer given a Raiden node configuration
+// and an http client. The moves are timed on the Clock of the configuration.
+func NewRebalancer(config *config.Config, httpClient *http.Client) Rebalancer {
+	var rebalancer = &defaultRebalancer{
+		addressGetter: address.NewGetter(config, httpClient),
+		channelLister: channels.NewLister(config, httpClient),
+		selfPayer:     payments.NewSelfPayer(config, httpClient),
+		clock:         config.Time(),
+	}
+
+	if config != nil {
+		rebalancer.tenant = config.Tenant
+		rebalancer.identifiers = config.IdentifierSource
+	}
+
+	return rebalancer
+}
+
+type defaultRebalancer struct {
+	addressGetter address.Getter
+	channelLister channels.Lister
+	selfPayer     payments.SelfPayer
+	identifiers   config.IdentifierSource
+	tenant        string
+	clock         clock.Clock
+}
+
+type imbalance struct {
+	channel *channels.Channel
+	amount  *big.Int
+}
+
+// Rebalance will plan moves from the channels of the token network holding more than
+// the target ratio of their deposit to the channels holding less, and execute them as
+// payments from the node to itself. A failed move is recorded and does not count
+// towards the total, the remaining moves are still attempted.
+func (rebalancer *defaultRebalancer) Rebalance(ctx context.Context, tokenAddress common.Address, options *RebalanceOptions) (*RebalanceReport, error) {
+	var (
+		err         error
+		ourAddress  common.Address
+		channelList []*channels.Channel
+		moves       []*Move
+		report      = &RebalanceReport{
+			Moves: make([]*Move, 0),
+			Moved: new(big.Int),
+		}
+	)
+
+	if options == nil || amounts.OrZero(options.MaxMove).Sign() <= 0 || amounts.OrZero(options.MaxTotal).Sign() <= 0 {
+		return nil, ErrNoLimits
+	}
+
+	if channelList, err = rebalancer.channelLister.ListToken(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	moves = plan(tokenAddress, channelList, options)
+
+	for _, move := range moves {
+		move.Fields = meta.TenantFields(ctx, rebalancer.tenant)
+	}
+
+	if options.DryRun {
+		for _, move := range moves {
+			move.DryRun = true
+			report.Moves = append(report.Moves, move)
+			report.Moved.Add(report.Moved, move.Amount)
+
+			if options.Audit != nil {
+				options.Audit(move)
+			}
+		}
+
+		return report, nil
+	}
+
+	if ourAddress, err = rebalancer.addressGetter.Get(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, move := range moves {
+		move.Identifier = payments.RandomIdentifier()
+		move.Started = rebalancer.clock.Now()
+
+		if rebalancer.identifiers != nil {
+			move.Identifier, move.Err = rebalancer.identifiers.NextIdentifier(ctx, tokenAddress)
+		}
+
+		if options.Route != nil && move.Err == nil {
+			move.Route, move.Err = options.Route(ctx, ourAddress, move)
+		}
+
+		if move.Err == nil {
+			move.Payment, move.Err = rebalancer.selfPayer.PaySelf(ctx, tokenAddress, move.Route, move.Amount, move.Identifier)
+		}
+
+		move.Finished = rebalancer.clock.Now()
+
+		if move.Err == nil {
+			report.Moved.Add(report.Moved, move.Amount)
+			rebalancer.readBalances(ctx, move)
+		}
+
+		report.Moves = append(report.Moves, move)
+
+		if options.Audit != nil {
+			options.Audit(move)
+		}
+	}
+
+	return report, nil
+}
+
+func (rebalancer *defaultRebalancer) readBalances(ctx context.Context, move *Move) {
+	channelList, err := rebalancer.channelLister.ListToken(ctx, move.TokenAddress)
+	if err != nil {
+		return
+	}
+
+	for _, channel := range channelList {
+		switch channel.PartnerAddress {
+		case move.From:
+			move.FromAfter = channel.Balance
+		case move.To:
+			move.ToAfter = channel.Balance
+		}
+	}
+
+	move.BalancesRead = true
+}
+
+// plan matches the largest surpluses with the largest deficits, within the limits of
+// the options.
+func plan(tokenAddress common.Address, channelList []*channels.Channel, options *RebalanceOptions) []*Move {
+	var (
+		surpluses = make([]*imbalance, 0)
+		deficits  = make([]*imbalance, 0)
+		ratio     = options.TargetRatio
+		remaining = new(big.Int).Set(options.MaxTotal)
+		moves     = make([]*Move, 0)
+	)
+
+	if ratio <= 0 || ratio > 1 {
+		ratio = DefaultTargetRatio
+	}
+
+	for _, channel := range channelList {
+		if channel.State != "opened" {
+			continue
+		}
+
+		var (
+			balance   = amounts.OrZero(channel.Balance)
+			target, _ = new(big.Float).Mul(new(big.Float).SetInt(amounts.OrZero(channel.TotalDeposit)), big.NewFloat(ratio)).Int(nil)
+		)
+
+		switch balance.Cmp(target) {
+		case 1:
+			surpluses = append(surpluses, &imbalance{channel: channel, amount: new(big.Int).Sub(balance, target)})
+		case -1:
+			deficits = append(deficits, &imbalance{channel: channel, amount: new(big.Int).Sub(target, balance)})
+		}
+	}
+
+	sort.SliceStable(surpluses, func(i, j int) bool { return surpluses[i].amount.Cmp(surpluses[j].amount) > 0 })
+	sort.SliceStable(deficits, func(i, j int) bool { return deficits[i].amount.Cmp(deficits[j].amount) > 0 })
+
+	for _, deficit := range deficits {
+		for _, surplus := range surpluses {
+			amount := new(big.Int).Set(minAmount(deficit.amount, surplus.amount, options.MaxMove, remaining))
+
+			if amount.Sign() <= 0 || amount.Cmp(amounts.OrZero(options.MinMove)) < 0 {
+				continue
+			}
+
+			moves = append(moves, &Move{
+				TokenAddress: tokenAddress,
+				From:         surplus.channel.PartnerAddress,
+				To:           deficit.channel.PartnerAddress,
+				Amount:       amount,
+				FromBefore:   surplus.channel.Balance,
+				ToBefore:     deficit.channel.Balance,
+			})
+
+			deficit.amount.Sub(deficit.amount, amount)
+			surplus.amount.Sub(surplus.amount, amount)
+			remaining.Sub(remaining, amount)
+		}
+	}
+
+	return moves
+}
+
+func minAmount(values ...*big.Int) *big.Int {
+	var minimum = values[0]
+
+	for _, value := range values[1:] {
+		if value.Cmp(minimum) < 0 {
+			minimum = value
+		}
+	}
+
+	return minimum
+}
@@ -0,0 +1,205 @@
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleRebalancer() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		rebalancer   = NewRebalancer(config, http.DefaultClient)
+		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		report       *RebalanceReport
+		err          error
+	)
+
+	if report, err = rebalancer.Rebalance(context.Background(), tokenAddress, &RebalanceOptions{
+		MinMove:  big.NewInt(10),
+		MaxMove:  big.NewInt(500),
+		MaxTotal: big.NewInt(2000),
+		Audit: func(move *Move) {
+			fmt.Printf("moved %d from %s to %s: %v\n", move.Amount, move.From.Hex(), move.To.Hex(), move.Err)
+		},
+	}); err != nil {
+		panic(fmt.Sprintf("unable to rebalance channels: %s", err.Error()))
+	}
+
+	fmt.Println("total moved:", report.Moved)
+}
+
+func TestRebalancer(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		ourAddress   = "0x2a65Aca4D5fC5B5C859090a6c34d164135398226"
+		first        = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		second       = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		third        = common.HexToAddress("0x0000000000000000000000000000000000000003")
+		paymentURL   = fmt.Sprintf("http://localhost:5001/api/v1/payments/%s/%s", tokenAddress.Hex(), ourAddress)
+		channelsJSON = fmt.Sprintf(`[
+			{"channel_identifier":1,"partner_address":"%s","balance":90,"total_deposit":100,"state":"opened"},
+			{"channel_identifier":2,"partner_address":"%s","balance":10,"total_deposit":100,"state":"opened"},
+			{"channel_identifier":3,"partner_address":"%s","balance":40,"total_deposit":100,"state":"opened"},
+			{"channel_identifier":4,"partner_address":"0x0000000000000000000000000000000000000004","balance":0,"total_deposit":100,"state":"closed"}
+		]`, first.Hex(), second.Hex(), third.Hex())
+		options = &RebalanceOptions{MaxMove: big.NewInt(30), MaxTotal: big.NewInt(35)}
+	)
+
+	type testcase struct {
+		name          string
+		options       *RebalanceOptions
+		expectedMoves []*Move
+		expectedMoved int64
+		expectedPosts int
+		expectedError error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:          "missing limits",
+			options:       &RebalanceOptions{MaxMove: big.NewInt(30)},
+			expectedError: ErrNoLimits,
+		},
+		testcase{
+			name:    "dry run",
+			options: &RebalanceOptions{MaxMove: options.MaxMove, MaxTotal: options.MaxTotal, DryRun: true},
+			expectedMoves: []*Move{
+				&Move{TokenAddress: tokenAddress, From: first, To: second, Amount: big.NewInt(30), FromBefore: big.NewInt(90), ToBefore: big.NewInt(10), DryRun: true},
+				&Move{TokenAddress: tokenAddress, From: first, To: third, Amount: big.NewInt(5), FromBefore: big.NewInt(90), ToBefore: big.NewInt(40), DryRun: true},
+			},
+			expectedMoved: 35,
+			expectedPosts: 0,
+		},
+		testcase{
+			name:          "skips moves below the minimum",
+			options:       &RebalanceOptions{MinMove: big.NewInt(10), MaxMove: options.MaxMove, MaxTotal: options.MaxTotal, DryRun: true},
+			expectedMoves: []*Move{&Move{TokenAddress: tokenAddress, From: first, To: second, Amount: big.NewInt(30), FromBefore: big.NewInt(90), ToBefore: big.NewInt(10), DryRun: true}},
+			expectedMoved: 30,
+			expectedPosts: 0,
+		},
+		testcase{
+			name:          "executes moves as payments to ourselves",
+			options:       options,
+			expectedMoved: 35,
+			expectedPosts: 2,
+		},
+		testcase{
+			name: "executes moves along their routes",
+			options: &RebalanceOptions{MaxMove: options.MaxMove, MaxTotal: options.MaxTotal, Route: func(ctx context.Context, ourAddress common.Address, move *Move) ([]common.Address, error) {
+				return []common.Address{ourAddress, move.From, move.To, ourAddress}, nil
+			}},
+			expectedMoved: 35,
+			expectedPosts: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err        error
+				report     *RebalanceReport
+				audited    []*Move
+				rebalancer = NewRebalancer(config, http.DefaultClient)
+				ctx        = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`{"our_address":"%s"}`, ourAddress)))
+			httpmock.RegisterResponder("GET", fmt.Sprintf("http://localhost:5001/api/v1/channels/%s", tokenAddress.Hex()), httpmock.NewStringResponder(http.StatusOK, channelsJSON))
+			httpmock.RegisterResponder("POST", paymentURL, httpmock.NewStringResponder(http.StatusOK, `{"amount":30}`))
+
+			tc.options.Audit = func(move *Move) {
+				audited = append(audited, move)
+			}
+
+			report, err = rebalancer.Rebalance(ctx, tokenAddress, tc.options)
+
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMoved, report.Moved.Int64())
+			assert.Equal(t, report.Moves, audited)
+			assert.Equal(t, tc.expectedPosts, httpmock.GetCallCountInfo()["POST "+paymentURL])
+
+			if tc.expectedMoves != nil {
+				assert.Equal(t, tc.expectedMoves, report.Moves)
+			}
+
+			for _, move := range report.Moves {
+				assert.NoError(t, move.Err)
+				assert.Equal(t, !tc.options.DryRun, move.BalancesRead)
+
+				if !tc.options.DryRun {
+					assert.NotZero(t, move.Identifier)
+					assert.NotNil(t, move.Payment)
+				}
+			}
+		})
+	}
+}
+
+func TestRebalancerTenant(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+			Tenant:     "acme",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		channelsJSON = `[
+			{"channel_identifier":1,"partner_address":"0x0000000000000000000000000000000000000001","balance":90,"total_deposit":100,"state":"opened"},
+			{"channel_identifier":2,"partner_address":"0x0000000000000000000000000000000000000002","balance":10,"total_deposit":100,"state":"opened"}
+		]`
+		rebalancer = NewRebalancer(config, http.DefaultClient)
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", fmt.Sprintf("http://localhost:5001/api/v1/channels/%s", tokenAddress.Hex()), httpmock.NewStringResponder(http.StatusOK, channelsJSON))
+
+	type testcase struct {
+		ctx            context.Context
+		expectedFields meta.Fields
+	}
+
+	testcases := []testcase{
+		testcase{
+			ctx:            meta.WithFields(context.Background(), meta.Fields{meta.FieldActor: "billing"}),
+			expectedFields: meta.Fields{meta.FieldActor: "billing", meta.FieldTenant: "acme"},
+		},
+		testcase{
+			ctx:            meta.WithTenant(context.Background(), "globex"),
+			expectedFields: meta.Fields{meta.FieldTenant: "globex"},
+		},
+	}
+
+	for _, tc := range testcases {
+		report, err := rebalancer.Rebalance(tc.ctx, tokenAddress, &RebalanceOptions{MaxMove: big.NewInt(30), MaxTotal: big.NewInt(30), DryRun: true})
+		require.NoError(t, err)
+		require.Len(t, report.Moves, 1)
+		assert.Equal(t, tc.expectedFields, report.Moves[0].Fields)
+	}
+}
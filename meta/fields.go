@@ -0,0 +1,123 @@
+package meta
+
+import (
+	"context"
+	"net/http"
+)
+
+type fieldsKey struct{}
+
+// The fields shared by the services using the client, sent in the DefaultHeaders.
+const (
+	FieldActor         = "actor"
+	FieldCorrelationID = "correlation_id"
+	FieldTenant        = "tenant"
+)
+
+// DefaultHeaders maps the fields of a context to the request headers they are sent
+// in when the configuration of the client maps none, see config.Config.
+var DefaultHeaders = map[string]string{
+	FieldActor:         "X-Actor",
+	FieldCorrelationID: "X-Correlation-ID",
+	FieldTenant:        "X-Tenant",
+}
+
+// Fields is metadata attached by the caller to the calls made with a context, e.g. who
+// made them and on behalf of which tenant, sent to the node in request headers and
+// given to the audit logs of the client, so that a call can be traced across the
+// services sharing the client.
+type Fields map[string]string
+
+// WithFields returns a context carrying the fields along with the ones of the parent
+// context, the given fields taking precedence.
+//
+//	ctx = meta.WithFields(ctx, meta.Fields{meta.FieldActor: "billing", meta.FieldCorrelationID: requestID})
+//	payment, err := paymentClient.Initiate(ctx, tokenAddress, targetAddress, amount)
+func WithFields(ctx context.Context, fields Fields) context.Context {
+	var merged = make(Fields)
+
+	for name, value := range FieldsFromContext(ctx) {
+		merged[name] = value
+	}
+
+	for name, value := range fields {
+		merged[name] = value
+	}
+
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// WithTenant returns a context carrying the tenant field, see WithFields, e.g. the
+// internal customer a shared payment service makes the calls of the context for.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return WithFields(ctx, Fields{FieldTenant: tenant})
+}
+
+// Tenant returns the tenant field carried by the context, or the fallback when it
+// carries none, e.g. the Tenant of the configuration of the client.
+func Tenant(ctx context.Context, fallback string) string {
+	if tenant := FieldsFromContext(ctx)[FieldTenant]; tenant != "" {
+		return tenant
+	}
+
+	return fallback
+}
+
+// TenantFields returns the fields carried by the context along with the tenant when
+// the context carries none, without modifying the fields of the context.
+func TenantFields(ctx context.Context, tenant string) Fields {
+	var fields = FieldsFromContext(ctx)
+
+	if tenant == "" || fields[FieldTenant] != "" {
+		return fields
+	}
+
+	merged := Fields{FieldTenant: tenant}
+
+	for name, value := range fields {
+		merged[name] = value
+	}
+
+	return merged
+}
+
+// FieldsFromContext returns the fields carried by the context, or nil when it carries
+// none. The fields are not to be modified.
+func FieldsFromContext(ctx context.Context) Fields {
+	fields, _ := ctx.Value(fieldsKey{}).(Fields)
+
+	return fields
+}
+
+// FieldsFromHeader returns the fields sent in the headers, by the mapping of fields to
+// headers, DefaultHeaders when nil. It is the counterpart of SetHeaders for services
+// receiving the requests of the client.
+func FieldsFromHeader(header http.Header, headers map[string]string) Fields {
+	var fields = make(Fields)
+
+	if headers == nil {
+		headers = DefaultHeaders
+	}
+
+	for name, headerName := range headers {
+		if value := header.Get(headerName); value != "" {
+			fields[name] = value
+		}
+	}
+
+	return fields
+}
+
+// SetHeaders sets the headers of the fields, by the mapping of fields to headers,
+// DefaultHeaders when nil. Fields without a header are not sent.
+func (fields Fields) SetHeaders(header http.Header, headers map[string]string) {
+	if headers == nil {
+		headers = DefaultHeaders
+	}
+
+	for name, value := range fields {
+		if headerName, ok := headers[name]; ok && value != "" {
+			header.Set(headerName, value)
+		}
+	}
+}
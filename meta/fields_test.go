@@ -0,0 +1,90 @@
+package meta
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFields(t *testing.T) {
+	assert.Nil(t, FieldsFromContext(context.Background()))
+
+	var (
+		parent = WithFields(context.Background(), Fields{FieldActor: "billing", FieldTenant: "acme"})
+		ctx    = WithFields(parent, Fields{FieldCorrelationID: "42", FieldTenant: "globex"})
+	)
+
+	assert.Equal(t, Fields{FieldActor: "billing", FieldTenant: "acme"}, FieldsFromContext(parent))
+	assert.Equal(t, Fields{FieldActor: "billing", FieldCorrelationID: "42", FieldTenant: "globex"}, FieldsFromContext(ctx))
+}
+
+func TestTenant(t *testing.T) {
+	var (
+		background = context.Background()
+		ctx        = WithTenant(WithFields(background, Fields{FieldActor: "billing"}), "globex")
+	)
+
+	assert.Equal(t, "acme", Tenant(background, "acme"))
+	assert.Equal(t, "globex", Tenant(ctx, "acme"))
+
+	assert.Nil(t, TenantFields(background, ""))
+	assert.Equal(t, Fields{FieldTenant: "acme"}, TenantFields(background, "acme"))
+	assert.Equal(t, Fields{FieldActor: "billing", FieldTenant: "globex"}, TenantFields(ctx, "acme"))
+
+	actorCtx := WithFields(background, Fields{FieldActor: "billing"})
+	assert.Equal(t, Fields{FieldActor: "billing", FieldTenant: "acme"}, TenantFields(actorCtx, "acme"))
+	assert.Equal(t, Fields{FieldActor: "billing"}, FieldsFromContext(actorCtx))
+}
+
+func TestFieldsHeaders(t *testing.T) {
+	type testcase struct {
+		name           string
+		fields         Fields
+		headers        map[string]string
+		expectedHeader http.Header
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:   "default headers",
+			fields: Fields{FieldActor: "billing", FieldCorrelationID: "42", "unmapped": "value"},
+			expectedHeader: http.Header{
+				"X-Actor":          []string{"billing"},
+				"X-Correlation-Id": []string{"42"},
+			},
+		},
+		testcase{
+			name:           "configured headers",
+			fields:         Fields{FieldActor: "billing", FieldCorrelationID: "42"},
+			headers:        map[string]string{FieldCorrelationID: "X-Request-ID"},
+			expectedHeader: http.Header{"X-Request-Id": []string{"42"}},
+		},
+		testcase{
+			name:           "no headers",
+			fields:         Fields{FieldActor: "billing"},
+			headers:        map[string]string{},
+			expectedHeader: http.Header{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var header = make(http.Header)
+
+			tc.fields.SetHeaders(header, tc.headers)
+			assert.Equal(t, tc.expectedHeader, header)
+
+			for name := range tc.fields {
+				if _, ok := tc.headers[name]; tc.headers != nil && !ok {
+					delete(tc.fields, name)
+				} else if _, ok := DefaultHeaders[name]; tc.headers == nil && !ok {
+					delete(tc.fields, name)
+				}
+			}
+
+			assert.Equal(t, tc.fields, FieldsFromHeader(header, tc.headers))
+		})
+	}
+}
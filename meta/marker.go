@@ -0,0 +1,37 @@
+package meta
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+type markerKey struct{}
+
+// DefaultMarkerHeader is the request header the marker of a call is sent in by
+// convention, see config.Config.MarkerHeader.
+const DefaultMarkerHeader = "X-Raiden-Client-Marker"
+
+// WithMarker returns a context whose calls are marked with the marker, e.g. the ID of
+// the job making them, rather than with a marker generated for every call.
+func WithMarker(ctx context.Context, marker string) context.Context {
+	return context.WithValue(ctx, markerKey{}, marker)
+}
+
+// MarkerFromContext returns the marker of the calls made with the context, or an
+// empty string when it has none.
+func MarkerFromContext(ctx context.Context) string {
+	marker, _ := ctx.Value(markerKey{}).(string)
+
+	return marker
+}
+
+// NewMarker returns a random marker, unique to a call, e.g. "rc-5f2b9c0e4d7a1b3c".
+func NewMarker() string {
+	var random = make([]byte, 8)
+
+	// the reader of crypto/rand does not fail on the supported platforms
+	_, _ = rand.Read(random)
+
+	return "rc-" + hex.EncodeToString(random)
+}
@@ -0,0 +1,18 @@
+package meta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarker(t *testing.T) {
+	assert.Empty(t, MarkerFromContext(context.Background()))
+	assert.Equal(t, "payout-42", MarkerFromContext(WithMarker(context.Background(), "payout-42")))
+
+	marker := NewMarker()
+
+	assert.Regexp(t, "^rc-[0-9a-f]{16}$", marker)
+	assert.NotEqual(t, marker, NewMarker())
+}
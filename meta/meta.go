@@ -0,0 +1,66 @@
+// Package meta records metadata about the calls made with a client, such as how long
+// they took and which node served them, for SLO tracking in calling services.
+package meta
+
+import (
+	"context"
+	"time"
+)
+
+type contextKey struct{}
+
+// Meta is the metadata of a call, filled in by the client as the requests of the call
+// are sent to the Raiden node.
+type Meta struct {
+	// Duration is the time spent sending the requests of the call and receiving their
+	// responses, retries included.
+	Duration time.Duration
+	// Attempts is the number of requests sent, more than one when a middleware
+	// retried or a pool failed over to another node.
+	Attempts int
+	// Node is the name of the node that served the call when made through a
+	// multinode pool, and the host of the node otherwise.
+	Node string
+	// StatusCode is the status code of the last response, zero when no node
+	// responded.
+	StatusCode int
+	// Marker is the marker the requests of the call were sent with, to find them in
+	// the logs of the node, empty when the client sends no marker, see
+	// config.Config.MarkerHeader.
+	Marker string
+	// RateLimit is the quota left to the client by the rate limiting proxy in front
+	// of the node, as told by the headers of the last response, nil when it told
+	// none.
+	RateLimit *RateLimit
+}
+
+// RateLimit is the quota of requests a rate limiting proxy grants a client, from the
+// X-RateLimit-* or RateLimit-* headers of its responses. Limit and Remaining are -1
+// when the proxy did not tell them, and Reset is zero when it did not tell when the
+// quota is granted again.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// WithMeta returns a context recording the metadata of the call it is given to. A
+// context records a single call at a time, it is not to be shared by concurrent
+// calls.
+//
+//	ctx, callMeta := meta.WithMeta(ctx)
+//	channels, err := channelClient.ListAll(ctx)
+//	observe(callMeta.Duration, callMeta.StatusCode, err)
+func WithMeta(ctx context.Context) (context.Context, *Meta) {
+	var callMeta = &Meta{}
+
+	return context.WithValue(ctx, contextKey{}, callMeta), callMeta
+}
+
+// FromContext returns the metadata recorded by the context, or nil when the context
+// records none.
+func FromContext(ctx context.Context) *Meta {
+	callMeta, _ := ctx.Value(contextKey{}).(*Meta)
+
+	return callMeta
+}
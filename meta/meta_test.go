@@ -0,0 +1,18 @@
+package meta
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Nil(t, FromContext(context.Background()))
+
+	ctx, callMeta := WithMeta(context.Background())
+
+	assert.Equal(t, &Meta{}, callMeta)
+	assert.True(t, FromContext(ctx) == callMeta)
+	assert.True(t, FromContext(context.WithValue(ctx, struct{}{}, "unrelated")) == callMeta)
+}
@@ -0,0 +1,206 @@
+// Package migration moves the traffic of an application from one Raiden node to
+// another without downtime: the old node is drained of its payments in flight, the
+// new node is verified to have the channels and balances of the old one, and the
+// shared facade of the application is switched over to the new node at once.
+package migration
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/progress"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultDrainInterval is the time between two listings of the pending transfers of
+// the old node while draining it, when the migrator has no interval.
+const DefaultDrainInterval = 5 * time.Second
+
+// Reasons the new node does not have the channels of the old one.
+const (
+	// ReasonMissingChannel is an open channel of the old node the new node has no
+	// open channel of the token with the partner for.
+	ReasonMissingChannel = "missing_channel"
+	// ReasonLowerBalance is a channel of the new node whose balance is lower than the
+	// one of the channel of the old node with the same partner.
+	ReasonLowerBalance = "lower_balance"
+)
+
+// Mismatch is an open channel of the old node the new node does not match, with the
+// balances of both, a nil NewBalance for a missing channel.
+type Mismatch struct {
+	TokenAddress   common.Address
+	PartnerAddress common.Address
+	OldBalance     *big.Int
+	NewBalance     *big.Int
+	Reason         string
+}
+
+// ParityError is returned when the new node does not have the open channels and
+// balances of the old one, the traffic is then not switched over.
+type ParityError struct {
+	Mismatches []*Mismatch
+}
+
+func (err *ParityError) Error() string {
+	var mismatches = make([]string, 0, len(err.Mismatches))
+
+	for _, mismatch := range err.Mismatches {
+		mismatches = append(mismatches, fmt.Sprintf("%s/%s (%s)", mismatch.TokenAddress.Hex(), mismatch.PartnerAddress.Hex(), mismatch.Reason))
+	}
+
+	return fmt.Sprintf("new node does not match the channels of the old node: %s", strings.Join(mismatches, ", "))
+}
+
+// Code returns raidenerrors.CodeParityMismatch, see raidenerrors.HasCode.
+func (err *ParityError) Code() raidenerrors.Code {
+	return raidenerrors.CodeParityMismatch
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *ParityError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// Migrator migrates the traffic of the Switch from the Old node to the New node,
+// listing the pending transfers of the old node every Interval of Clock, the system
+// time when nil, while draining it.
+type Migrator struct {
+	Old      *raidenclient.Client
+	New      *raidenclient.Client
+	Switch   *raidenclient.Switch
+	Interval time.Duration
+	Clock    clock.Clock
+}
+
+// NewMigrator creates a migrator of the traffic of the facade from the old node to
+// the new one.
+func NewMigrator(oldClient, newClient *raidenclient.Client, facade *raidenclient.Switch) *Migrator {
+	return &Migrator{
+		Old:      oldClient,
+		New:      newClient,
+		Switch:   facade,
+		Interval: DefaultDrainInterval,
+	}
+}
+
+// Migrate will drain the old node, verify the parity of the new node and switch the
+// traffic over to it, reporting the stages of the progress.MigrationStages to the
+// progress options of the context, if any. The traffic is left on the old node when
+// any step fails, a *ParityError telling what the new node lacks. ctx bounds how
+// long the old node is drained for.
+func (migrator *Migrator) Migrate(ctx context.Context) error {
+	var reporter = progress.Start(ctx, progress.OperationMigration, progress.MigrationStages)
+
+	if err := migrator.Drain(ctx); err != nil {
+		reporter.Fail(err)
+		return err
+	}
+
+	reporter.Reach(progress.Drained)
+
+	if err := migrator.Verify(ctx); err != nil {
+		reporter.Fail(err)
+		return err
+	}
+
+	reporter.Reach(progress.Verified)
+
+	migrator.Switch.Swap(migrator.New)
+
+	reporter.Reach(progress.Switched)
+
+	return nil
+}
+
+// Drain will wait until the old node has no pending transfers left. Payments keep
+// reaching the old node through the switch while it drains, the application should
+// hold new payments back during a migration for the drain to end.
+func (migrator *Migrator) Drain(ctx context.Context) error {
+	var interval = migrator.Interval
+
+	if interval <= 0 {
+		interval = DefaultDrainInterval
+	}
+
+	for {
+		transfers, err := migrator.Old.PendingTransfers().ListAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(transfers) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("old node still has %d pending transfers: %s", len(transfers), ctx.Err().Error())
+		case <-clock.Or(migrator.Clock).After(interval):
+		}
+	}
+}
+
+// Verify will return a *ParityError when an open channel of the old node has no open
+// channel of the token with the same partner on the new node, or one with a lower
+// balance.
+func (migrator *Migrator) Verify(ctx context.Context) error {
+	var (
+		err         error
+		oldChannels []*channels.Channel
+		newChannels []*channels.Channel
+		parityError = &ParityError{}
+		newBalances = make(map[[2]common.Address]*big.Int)
+	)
+
+	if oldChannels, err = migrator.Old.Channels().ListAll(ctx); err != nil {
+		return err
+	}
+
+	if newChannels, err = migrator.New.Channels().ListAll(ctx); err != nil {
+		return err
+	}
+
+	for _, channel := range newChannels {
+		if channel.State == "opened" {
+			newBalances[[2]common.Address{channel.TokenAddress, channel.PartnerAddress}] = amounts.OrZero(channel.Balance)
+		}
+	}
+
+	for _, channel := range oldChannels {
+		if channel.State != "opened" {
+			continue
+		}
+
+		var (
+			oldBalance     = amounts.OrZero(channel.Balance)
+			newBalance, ok = newBalances[[2]common.Address{channel.TokenAddress, channel.PartnerAddress}]
+			mismatch       = &Mismatch{TokenAddress: channel.TokenAddress, PartnerAddress: channel.PartnerAddress, OldBalance: oldBalance, NewBalance: newBalance}
+		)
+
+		switch {
+		case !ok:
+			mismatch.Reason = ReasonMissingChannel
+		case newBalance.Cmp(oldBalance) < 0:
+			mismatch.Reason = ReasonLowerBalance
+		default:
+			continue
+		}
+
+		parityError.Mismatches = append(parityError.Mismatches, mismatch)
+	}
+
+	if len(parityError.Mismatches) > 0 {
+		return parityError
+	}
+
+	return nil
+}
@@ -0,0 +1,182 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/progress"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleMigrator() {
+	var (
+		oldClient = raidenclient.NewClient(&config.Config{Host: "http://raiden-1:5001", APIVersion: "v1"}, http.DefaultClient)
+		newClient = raidenclient.NewClient(&config.Config{Host: "http://raiden-2:5001", APIVersion: "v1"}, http.DefaultClient)
+		// the facade the handlers of the application get their client from
+		facade   = raidenclient.NewSwitch(oldClient)
+		migrator = NewMigrator(oldClient, newClient, facade)
+		ctx      = progress.WithOptions(context.Background(), &progress.Options{Report: func(event *progress.Event) {
+			fmt.Println("migration", event.Stage, event.Err)
+		}})
+	)
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	if err := migrator.Migrate(ctx); err != nil {
+		panic(fmt.Sprintf("unable to migrate: %s", err.Error()))
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	var (
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partner      = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		other        = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		oldChannels  = `[{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":25,"state":"opened"},{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","balance":10,"state":"opened"},{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x0000000000000000000000000000000000000001","balance":70,"state":"closed"}]`
+		pending      = `[{"channel_identifier":7,"initiator":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","locked_amount":10,"payment_identifier":42,"role":"initiator","target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","token_network_identifier":"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6","transferred_amount":0}]`
+	)
+
+	type testcase struct {
+		name               string
+		pendingListings    []string
+		newChannels        string
+		timeout            time.Duration
+		expectedStages     []progress.Stage
+		expectedMismatches []*Mismatch
+		expectedError      bool
+		expectedSwitched   bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:             "drained, verified and switched",
+			pendingListings:  []string{pending, `[]`},
+			newChannels:      `[{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":30,"state":"opened"},{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","balance":10,"state":"opened"}]`,
+			expectedStages:   []progress.Stage{progress.Draining, progress.Drained, progress.Verified, progress.Switched},
+			expectedSwitched: true,
+		},
+		testcase{
+			name:            "new node lacking channels",
+			pendingListings: []string{`[]`},
+			newChannels:     `[{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":20,"state":"opened"},{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","balance":10,"state":"closed"}]`,
+			expectedStages:  []progress.Stage{progress.Draining, progress.Drained, progress.Drained},
+			expectedMismatches: []*Mismatch{
+				&Mismatch{TokenAddress: tokenAddress, PartnerAddress: partner, OldBalance: big.NewInt(25), NewBalance: big.NewInt(20), Reason: ReasonLowerBalance},
+				&Mismatch{TokenAddress: tokenAddress, PartnerAddress: other, OldBalance: big.NewInt(10), Reason: ReasonMissingChannel},
+			},
+			expectedError: true,
+		},
+		testcase{
+			name:            "old node never drained",
+			pendingListings: []string{pending},
+			newChannels:     `[]`,
+			timeout:         50 * time.Millisecond,
+			expectedStages:  []progress.Stage{progress.Draining, progress.Draining},
+			expectedError:   true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				listings  int
+				stages    []progress.Stage
+				oldClient = raidenclient.NewClient(&config.Config{Host: "http://old:5001", APIVersion: "v1"}, http.DefaultClient)
+				newClient = raidenclient.NewClient(&config.Config{Host: "http://new:5001", APIVersion: "v1"}, http.DefaultClient)
+				facade    = raidenclient.NewSwitch(oldClient)
+				migrator  = NewMigrator(oldClient, newClient, facade)
+				ctx       = progress.WithOptions(context.Background(), &progress.Options{Report: func(event *progress.Event) {
+					stages = append(stages, event.Stage)
+				}})
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://old:5001/api/v1/pending_transfers", func(request *http.Request) (*http.Response, error) {
+				listing := tc.pendingListings[len(tc.pendingListings)-1]
+				if listings < len(tc.pendingListings) {
+					listing = tc.pendingListings[listings]
+				}
+
+				listings++
+
+				return httpmock.NewStringResponse(http.StatusOK, listing), nil
+			})
+			httpmock.RegisterResponder("GET", "http://old:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, oldChannels))
+			httpmock.RegisterResponder("GET", "http://new:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, tc.newChannels))
+
+			if tc.timeout > 0 {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, tc.timeout)
+				defer cancel()
+			}
+
+			migrator.Interval = 10 * time.Millisecond
+
+			err := migrator.Migrate(ctx)
+
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			if tc.expectedMismatches != nil {
+				require.IsType(t, &ParityError{}, err)
+				assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodeParityMismatch))
+				assert.Equal(t, tc.expectedMismatches, err.(*ParityError).Mismatches)
+			}
+
+			assert.Equal(t, tc.expectedStages, stages)
+			assert.Equal(t, tc.expectedSwitched, facade.Client() == newClient)
+		})
+	}
+}
+
+func TestDrainClock(t *testing.T) {
+	var (
+		listings  int
+		oldClient = raidenclient.NewClient(&config.Config{Host: "http://old:5001", APIVersion: "v1"}, http.DefaultClient)
+		migrator  = NewMigrator(oldClient, nil, raidenclient.NewSwitch(oldClient))
+		manual    = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+		drained   = make(chan error, 1)
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// the pending transfer of the old node completes before the second listing
+	httpmock.RegisterResponder("GET", "http://old:5001/api/v1/pending_transfers", func(request *http.Request) (*http.Response, error) {
+		if listings++; listings == 1 {
+			return httpmock.NewStringResponse(http.StatusOK, `[{"channel_identifier":7,"locked_amount":10,"payment_identifier":42,"role":"initiator"}]`), nil
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, `[]`), nil
+	})
+
+	migrator.Clock = manual
+
+	go func() { drained <- migrator.Drain(context.Background()) }()
+
+	manual.BlockUntil(1)
+	assert.Equal(t, 1, listings)
+
+	manual.Advance(DefaultDrainInterval)
+
+	require.NoError(t, <-drained)
+	assert.Equal(t, 2, listings)
+}
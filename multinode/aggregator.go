@@ -0,0 +1,211 @@
+package multinode
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NodeChannel is a payment channel along with the name of the node it belongs to.
+type NodeChannel struct {
+	Node string
+	*channels.Channel
+}
+
+// NodeEvent is a payment event along with the name of the node that logged it.
+type NodeEvent struct {
+	Node string
+	*payments.Event
+}
+
+// NodeTransfer is a pending transfer along with the name of the node it is pending on.
+type NodeTransfer struct {
+	Node string
+	*pendingtransfers.Transfer
+}
+
+// AggregateError holds the errors of every node that could not be queried while
+// aggregating results, keyed by node name.
+type AggregateError struct {
+	Errors map[string]error
+}
+
+func (err *AggregateError) Error() string {
+	var messages = make([]string, 0, len(err.Errors))
+
+	for node, nodeErr := range err.Errors {
+		messages = append(messages, fmt.Sprintf("%s: %s", node, nodeErr.Error()))
+	}
+
+	sort.Strings(messages)
+
+	return fmt.Sprintf("unable to query %d raiden node(s): %s", len(err.Errors), strings.Join(messages, ", "))
+}
+
+// Code returns raidenerrors.CodePartialFailure, see raidenerrors.HasCode.
+func (err *AggregateError) Code() raidenerrors.Code {
+	return raidenerrors.CodePartialFailure
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *AggregateError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// NewAggregator creates an Aggregator that merges the results of several Raiden nodes.
+func NewAggregator(nodes ...*Node) *Aggregator {
+	return &Aggregator{
+		Nodes: nodes,
+	}
+}
+
+// Aggregator queries every one of its nodes concurrently and merges the results,
+// recording which node every record came from. When some nodes fail the results of
+// the other nodes are still returned along with an *AggregateError.
+type Aggregator struct {
+	Nodes []*Node
+}
+
+// Channels will list the payment channels of every node.
+func (aggregator *Aggregator) Channels(ctx context.Context) ([]*NodeChannel, error) {
+	var (
+		mutex    sync.Mutex
+		order    = aggregator.order()
+		channels = make([]*NodeChannel, 0)
+	)
+
+	err := aggregator.each(ctx, func(ctx context.Context, node *Node) error {
+		nodeChannels, err := node.ChannelsClient.ListAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		for _, channel := range nodeChannels {
+			channels = append(channels, &NodeChannel{Node: node.Name, Channel: channel})
+		}
+
+		return nil
+	})
+
+	sort.SliceStable(channels, func(i, j int) bool {
+		return order[channels[i].Node] < order[channels[j].Node]
+	})
+
+	return channels, err
+}
+
+// Payments will list the payment events between the token and target of every node.
+func (aggregator *Aggregator) Payments(ctx context.Context, tokenAddress, targetAddress common.Address) ([]*NodeEvent, error) {
+	var (
+		mutex  sync.Mutex
+		order  = aggregator.order()
+		events = make([]*NodeEvent, 0)
+	)
+
+	err := aggregator.each(ctx, func(ctx context.Context, node *Node) error {
+		nodeEvents, err := node.PaymentsClient.List(ctx, tokenAddress, targetAddress)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		for _, event := range nodeEvents {
+			events = append(events, &NodeEvent{Node: node.Name, Event: event})
+		}
+
+		return nil
+	})
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return order[events[i].Node] < order[events[j].Node]
+	})
+
+	return events, err
+}
+
+// PendingTransfers will list all of the pending transfers of every node.
+func (aggregator *Aggregator) PendingTransfers(ctx context.Context) ([]*NodeTransfer, error) {
+	var (
+		mutex     sync.Mutex
+		order     = aggregator.order()
+		transfers = make([]*NodeTransfer, 0)
+	)
+
+	err := aggregator.each(ctx, func(ctx context.Context, node *Node) error {
+		nodeTransfers, err := node.PendingTransfersClient.ListAll(ctx)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		for _, transfer := range nodeTransfers {
+			transfers = append(transfers, &NodeTransfer{Node: node.Name, Transfer: transfer})
+		}
+
+		return nil
+	})
+
+	sort.SliceStable(transfers, func(i, j int) bool {
+		return order[transfers[i].Node] < order[transfers[j].Node]
+	})
+
+	return transfers, err
+}
+
+// each will call fn concurrently for every node, collecting the errors by node name.
+func (aggregator *Aggregator) each(ctx context.Context, fn func(ctx context.Context, node *Node) error) error {
+	var (
+		mutex     sync.Mutex
+		waitGroup sync.WaitGroup
+		errs      = make(map[string]error)
+	)
+
+	for _, node := range aggregator.Nodes {
+		waitGroup.Add(1)
+
+		go func(node *Node) {
+			defer waitGroup.Done()
+
+			if err := fn(ctx, node); err != nil {
+				mutex.Lock()
+				errs[node.Name] = err
+				mutex.Unlock()
+			}
+		}(node)
+	}
+
+	waitGroup.Wait()
+
+	if len(errs) > 0 {
+		return &AggregateError{Errors: errs}
+	}
+
+	return nil
+}
+
+// order returns the position of every node by name, results are sorted by the order
+// of the nodes so that they are stable between calls.
+func (aggregator *Aggregator) order() map[string]int {
+	var order = make(map[string]int)
+
+	for i, node := range aggregator.Nodes {
+		order[node.Name] = i
+	}
+
+	return order
+}
@@ -0,0 +1,108 @@
+package multinode
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleAggregator() {
+	var (
+		aggregator = NewAggregator(
+			NewNode("raiden-1", &config.Config{Host: "http://raiden-1:5001", APIVersion: "v1"}, http.DefaultClient),
+			NewNode("raiden-2", &config.Config{Host: "http://raiden-2:5001", APIVersion: "v1"}, http.DefaultClient),
+		)
+		channels []*NodeChannel
+		err      error
+	)
+
+	if channels, err = aggregator.Channels(context.Background()); err != nil {
+		fmt.Println("some nodes could not be queried:", err.Error())
+	}
+
+	for _, channel := range channels {
+		fmt.Printf("%s: channel %d with %s\n", channel.Node, channel.ChannelIdentifier, channel.PartnerAddress.Hex())
+	}
+}
+
+func TestAggregator(t *testing.T) {
+	var (
+		first  = NewNode("first", &config.Config{Host: "http://raiden-1:5001", APIVersion: "v1"}, http.DefaultClient)
+		second = NewNode("second", &config.Config{Host: "http://raiden-2:5001", APIVersion: "v1"}, http.DefaultClient)
+		third  = NewNode("third", &config.Config{Host: "http://raiden-3:5001", APIVersion: "v1"}, http.DefaultClient)
+
+		aggregator = NewAggregator(first, second, third)
+		ctx        = context.Background()
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	for i, host := range []string{"http://raiden-1:5001", "http://raiden-2:5001"} {
+		httpmock.RegisterResponder("GET", host+"/api/v1/channels", httpmock.NewStringResponder(
+			http.StatusOK,
+			fmt.Sprintf(`[{"channel_identifier":%d,"state":"opened","balance":10},{"channel_identifier":%d,"state":"closed","balance":0}]`, i*10+1, i*10+2),
+		))
+
+		httpmock.RegisterResponder("GET", host+"/api/v1/pending_transfers", httpmock.NewStringResponder(
+			http.StatusOK,
+			fmt.Sprintf(`[{"channel_identifier":%d,"locked_amount":5}]`, i*10+1),
+		))
+
+		httpmock.RegisterResponder("GET", host+"/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", httpmock.NewStringResponder(
+			http.StatusOK,
+			fmt.Sprintf(`[{"event":"EventPaymentSentSuccess","amount":5,"identifier":%d,"log_time":"2018-10-30T07:03:52.193Z"}]`, i+1),
+		))
+	}
+
+	t.Run("channels with node provenance", func(t *testing.T) {
+		channels, err := aggregator.Channels(ctx)
+
+		require.Error(t, err)
+		require.IsType(t, &AggregateError{}, err)
+		assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodePartialFailure))
+		assert.Contains(t, err.(*AggregateError).Errors, "third")
+
+		require.Len(t, channels, 4)
+		assert.Equal(t, "first", channels[0].Node)
+		assert.Equal(t, int64(1), channels[0].ChannelIdentifier)
+		assert.Equal(t, "first", channels[1].Node)
+		assert.Equal(t, "second", channels[2].Node)
+		assert.Equal(t, int64(11), channels[2].ChannelIdentifier)
+		assert.Equal(t, "second", channels[3].Node)
+	})
+
+	t.Run("pending transfers with node provenance", func(t *testing.T) {
+		transfers, err := NewAggregator(first, second).PendingTransfers(ctx)
+
+		require.NoError(t, err)
+		require.Len(t, transfers, 2)
+		assert.Equal(t, "first", transfers[0].Node)
+		assert.Equal(t, int64(1), transfers[0].ChannelIdentifier)
+		assert.Equal(t, "second", transfers[1].Node)
+		assert.Equal(t, int64(11), transfers[1].ChannelIdentifier)
+	})
+
+	t.Run("payments with node provenance", func(t *testing.T) {
+		events, err := NewAggregator(second, first).Payments(
+			ctx,
+			common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+			common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+		)
+
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, "second", events[0].Node)
+		assert.Equal(t, int64(2), events[0].Identifier)
+		assert.Equal(t, "first", events[1].Node)
+		assert.Equal(t, int64(1), events[1].Identifier)
+	})
+}
@@ -0,0 +1,33 @@
+//go:build go1.23
+// +build go1.23
+
+package multinode
+
+import (
+	"context"
+	"iter"
+
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AllChannels returns an iterator over the payment channels of every node, queried
+// concurrently once the iteration starts, see Channels. When some nodes fail, the
+// channels of the others are yielded before the *AggregateError.
+func (aggregator *Aggregator) AllChannels(ctx context.Context) iter.Seq2[*NodeChannel, error] {
+	return util.Iterate(ctx, aggregator.Channels)
+}
+
+// AllPayments returns an iterator over the payment events between the token and
+// target of every node, see AllChannels.
+func (aggregator *Aggregator) AllPayments(ctx context.Context, tokenAddress, targetAddress common.Address) iter.Seq2[*NodeEvent, error] {
+	return util.Iterate(ctx, func(ctx context.Context) ([]*NodeEvent, error) {
+		return aggregator.Payments(ctx, tokenAddress, targetAddress)
+	})
+}
+
+// AllPendingTransfers returns an iterator over the pending transfers of every node,
+// see AllChannels.
+func (aggregator *Aggregator) AllPendingTransfers(ctx context.Context) iter.Seq2[*NodeTransfer, error] {
+	return util.Iterate(ctx, aggregator.PendingTransfers)
+}
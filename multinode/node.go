@@ -0,0 +1,43 @@
+// Package multinode allows a set of Raiden nodes to be used as a pool, where each
+// call is served by a node picked by a selection strategy and fails over to the
+// next node when one is unreachable.
+package multinode
+
+import (
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/connections"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/cpurta/go-raiden-client/tokens"
+)
+
+// NewNode creates a named Raiden node that can be added to a Pool, along with all
+// of the sub-clients used to make calls to it.
+func NewNode(name string, config *config.Config, httpClient *http.Client) *Node {
+	return &Node{
+		Name:                   name,
+		Config:                 config,
+		AddressClient:          address.NewClient(config, httpClient),
+		TokensClient:           tokens.NewClient(config, httpClient),
+		ChannelsClient:         channels.NewClient(config, httpClient),
+		PaymentsClient:         payments.NewClient(config, httpClient),
+		ConnectionsClient:      connections.NewClient(config, httpClient),
+		PendingTransfersClient: pendingtransfers.NewClient(config, httpClient),
+	}
+}
+
+// Node is a single Raiden node within a Pool.
+type Node struct {
+	Name                   string
+	Config                 *config.Config
+	AddressClient          *address.Client
+	TokensClient           *tokens.Client
+	ChannelsClient         *channels.Client
+	PaymentsClient         *payments.Client
+	ConnectionsClient      *connections.Client
+	PendingTransfersClient *pendingtransfers.Client
+}
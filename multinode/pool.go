@@ -0,0 +1,101 @@
+package multinode
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrNoNodes is returned when a call is made against a Pool without any nodes.
+var ErrNoNodes = errors.New("no raiden nodes in pool")
+
+// NewPool creates a Pool of Raiden nodes that uses the given Selector to decide
+// which node serves each call.
+func NewPool(selector Selector, nodes ...*Node) *Pool {
+	return &Pool{
+		Nodes:    nodes,
+		Selector: selector,
+		Failover: IsUnreachable,
+	}
+}
+
+// Pool is a set of Raiden nodes that are used interchangeably. Failover decides if
+// an error returned by a node means the call should be tried against the next node,
+// by default only the calls that provably never reached the node are failed over, see
+// IsUnreachable, so that calls the node may have acted upon, such as payments, are not
+// repeated.
+type Pool struct {
+	Nodes    []*Node
+	Selector Selector
+	Failover func(err error) bool
+}
+
+// Do will call fn with the nodes chosen by the Selector until one of them succeeds
+// or returns an error that is not failed over. The node that served the call is
+// returned along with the error of the last attempt, and is the Node of the
+// metadata of the context when it has some. Only the nodes the context is routed to
+// serve the call, see WithNodes.
+func (pool *Pool) Do(ctx context.Context, key common.Address, fn func(ctx context.Context, node *Node) error) (*Node, error) {
+	var (
+		err   error
+		nodes = pool.Selector.Select(pool.Nodes, key)
+	)
+
+	if len(nodes) == 0 {
+		return nil, ErrNoNodes
+	}
+
+	if nodes, err = route(ctx, nodes); err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		var started = time.Now()
+
+		err = fn(ctx, node)
+
+		pool.Selector.Observe(node, time.Since(started), err)
+
+		if callMeta := meta.FromContext(ctx); callMeta != nil {
+			callMeta.Node = node.Name
+		}
+
+		if err == nil {
+			return node, nil
+		}
+
+		if ctx.Err() != nil || !pool.Failover(err) {
+			return node, err
+		}
+	}
+
+	return nil, &AllFailedError{Err: err}
+}
+
+// AllFailedError is returned by Pool.Do when every node failed the call over, with
+// the error of the last node, which it wraps so that it can still be inspected with
+// raidenerrors.HasCode or raidenerrors.StatusCode.
+type AllFailedError struct {
+	Err error
+}
+
+func (err *AllFailedError) Error() string {
+	return "all raiden nodes failed: " + err.Err.Error()
+}
+
+// Unwrap returns the error of the last node.
+func (err *AllFailedError) Unwrap() error {
+	return err.Err
+}
+
+// IsUnreachable reports whether the error was caused by being unable to reach a
+// Raiden node, the call provably never leaving, see raidenerrors.IsUnsent. Timeouts
+// and connections broken once the request was written are not, the node may have
+// acted upon the call, and failing them over could e.g. send a payment twice.
+func IsUnreachable(err error) bool {
+	return raidenerrors.IsUnsent(err)
+}
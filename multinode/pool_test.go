@@ -0,0 +1,204 @@
+package multinode
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExamplePool() {
+	var (
+		pool = NewPool(
+			PrimaryWithFallback(),
+			NewNode("primary", &config.Config{Host: "http://raiden-1:5001", APIVersion: "v1"}, http.DefaultClient),
+			NewNode("secondary", &config.Config{Host: "http://raiden-2:5001", APIVersion: "v1"}, http.DefaultClient),
+		)
+		address common.Address
+		served  *Node
+		err     error
+	)
+
+	if served, err = pool.Do(context.Background(), common.Address{}, func(ctx context.Context, node *Node) error {
+		address, err = node.AddressClient.Get(ctx)
+		return err
+	}); err != nil {
+		panic(fmt.Sprintf("unable to get raiden address: %s", err.Error()))
+	}
+
+	fmt.Printf("raiden address %s served by %s\n", address.Hex(), served.Name)
+}
+
+func TestPool(t *testing.T) {
+	var (
+		primary   = NewNode("primary", &config.Config{Host: "http://raiden-1:5001", APIVersion: "v1"}, http.DefaultClient)
+		secondary = NewNode("secondary", &config.Config{Host: "http://raiden-2:5001", APIVersion: "v1"}, http.DefaultClient)
+		refused   = httpmock.NewErrorResponder(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")})
+	)
+
+	type testcase struct {
+		name            string
+		prepHTTPMock    func()
+		expectedNode    *Node
+		expectedAddress common.Address
+		expectedError   error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "served by the primary node",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://raiden-1:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+				httpmock.RegisterResponder("GET", "http://raiden-2:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9"}`))
+			},
+			expectedNode:    primary,
+			expectedAddress: common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"),
+		},
+		testcase{
+			name: "failed over to the secondary node",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://raiden-1:5001/api/v1/address", refused)
+				httpmock.RegisterResponder("GET", "http://raiden-2:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9"}`))
+			},
+			expectedNode:    secondary,
+			expectedAddress: common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+		},
+		testcase{
+			name: "node responded with an error",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://raiden-1:5001/api/v1/address", httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+				httpmock.RegisterResponder("GET", "http://raiden-2:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9"}`))
+			},
+			expectedNode:  primary,
+			expectedError: errors.New("EOF"),
+		},
+		testcase{
+			name: "timed out call not failed over",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://raiden-1:5001/api/v1/address", httpmock.NewErrorResponder(&net.OpError{Op: "read", Net: "tcp", Err: errors.New("i/o timeout")}))
+				httpmock.RegisterResponder("GET", "http://raiden-2:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9"}`))
+			},
+			expectedNode:  primary,
+			expectedError: errors.New("i/o timeout"),
+		},
+		testcase{
+			name: "all nodes unreachable",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://raiden-1:5001/api/v1/address", refused)
+				httpmock.RegisterResponder("GET", "http://raiden-2:5001/api/v1/address", refused)
+			},
+			expectedNode:  nil,
+			expectedError: errors.New("all raiden nodes failed"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err     error
+				served  *Node
+				address common.Address
+				pool    = NewPool(PrimaryWithFallback(), primary, secondary)
+				ctx     = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			served, err = pool.Do(ctx, common.Address{}, func(ctx context.Context, node *Node) error {
+				var err error
+
+				address, err = node.AddressClient.Get(ctx)
+
+				return err
+			})
+
+			assert.Equal(t, tc.expectedNode, served)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAddress, address)
+		})
+	}
+}
+
+func TestPoolWithoutNodes(t *testing.T) {
+	var pool = NewPool(PrimaryWithFallback())
+
+	_, err := pool.Do(context.Background(), common.Address{}, func(ctx context.Context, node *Node) error {
+		return nil
+	})
+
+	assert.Equal(t, ErrNoNodes, err)
+}
+
+func TestPoolMeta(t *testing.T) {
+	var (
+		primary   = NewNode("primary", &config.Config{Host: "http://raiden-1:5001", APIVersion: "v1"}, http.DefaultClient)
+		secondary = NewNode("secondary", &config.Config{Host: "http://raiden-2:5001", APIVersion: "v1"}, http.DefaultClient)
+		pool      = NewPool(PrimaryWithFallback(), primary, secondary)
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://raiden-1:5001/api/v1/address", httpmock.NewErrorResponder(&net.DNSError{Err: "no such host", Name: "raiden-1"}))
+	httpmock.RegisterResponder("GET", "http://raiden-2:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9"}`))
+
+	ctx, callMeta := meta.WithMeta(context.Background())
+
+	_, err := pool.Do(ctx, common.Address{}, func(ctx context.Context, node *Node) error {
+		_, err := node.AddressClient.Get(ctx)
+		return err
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, callMeta.Attempts)
+	assert.Equal(t, "secondary", callMeta.Node)
+	assert.Equal(t, http.StatusOK, callMeta.StatusCode)
+}
+
+func TestPoolAllFailed(t *testing.T) {
+	var (
+		primary   = NewNode("primary", &config.Config{Host: "http://raiden-1:5001", APIVersion: "v1"}, http.DefaultClient)
+		secondary = NewNode("secondary", &config.Config{Host: "http://raiden-2:5001", APIVersion: "v1"}, http.DefaultClient)
+		pool      = NewPool(PrimaryWithFallback(), primary, secondary)
+	)
+
+	pool.Failover = func(err error) bool {
+		return raidenerrors.StatusCode(err) == http.StatusServiceUnavailable
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://raiden-1:5001/api/v1/address", httpmock.NewStringResponder(http.StatusServiceUnavailable, `{"errors":"node is still syncing"}`))
+	httpmock.RegisterResponder("GET", "http://raiden-2:5001/api/v1/address", httpmock.NewStringResponder(http.StatusServiceUnavailable, `{"errors":"node is still syncing"}`))
+
+	_, err := pool.Do(context.Background(), common.Address{}, func(ctx context.Context, node *Node) error {
+		_, err := node.AddressClient.Get(ctx)
+		return err
+	})
+
+	require.IsType(t, &AllFailedError{}, err)
+	assert.Equal(t, "all raiden nodes failed: raiden node error 503: node is still syncing", err.Error())
+	assert.Equal(t, http.StatusServiceUnavailable, raidenerrors.StatusCode(err), "the error of the last node can still be inspected")
+	assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodeNodeSyncing))
+}
@@ -0,0 +1,58 @@
+package multinode
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+type routeKey struct{}
+
+// WithNodes returns a context whose calls through a Pool are only served by the nodes
+// with the names, tried in the order of the Selector and failing over between them
+// alone, e.g. so that payments are only sent by the node holding the funds while the
+// other nodes serve the reads. A call routed to no node of the pool fails.
+//
+//	ctx = multinode.WithNodes(ctx, "payments")
+//	_, err := pool.Do(ctx, tokenAddress, pay)
+func WithNodes(ctx context.Context, names ...string) context.Context {
+	return context.WithValue(ctx, routeKey{}, append([]string(nil), names...))
+}
+
+// NodesFromContext returns the names of the nodes the calls made with the context
+// are routed to, nil when they may be served by any node.
+func NodesFromContext(ctx context.Context) []string {
+	names, _ := ctx.Value(routeKey{}).([]string)
+
+	return names
+}
+
+// route returns the nodes among the selected ones the calls made with the context are
+// routed to, in their order.
+func route(ctx context.Context, nodes []*Node) ([]*Node, error) {
+	var (
+		names  = NodesFromContext(ctx)
+		routed = make([]*Node, 0, len(names))
+		named  = make(map[string]bool, len(names))
+	)
+
+	if names == nil {
+		return nodes, nil
+	}
+
+	for _, name := range names {
+		named[name] = true
+	}
+
+	for _, node := range nodes {
+		if named[node.Name] {
+			routed = append(routed, node)
+		}
+	}
+
+	if len(routed) == 0 {
+		return nil, fmt.Errorf("no raiden node named %s in pool", strings.Join(names, ", "))
+	}
+
+	return routed, nil
+}
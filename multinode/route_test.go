@@ -0,0 +1,98 @@
+package multinode
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithNodes(t *testing.T) {
+	type testcase struct {
+		name          string
+		names         []string
+		failing       map[string]bool
+		expectedTried []string
+		expectedError string
+	}
+
+	var (
+		reads    = NewNode("reads", &config.Config{Host: "http://raiden-1:5001", APIVersion: "v1"}, http.DefaultClient)
+		payments = NewNode("payments", &config.Config{Host: "http://raiden-2:5001", APIVersion: "v1"}, http.DefaultClient)
+		standby  = NewNode("standby", &config.Config{Host: "http://raiden-3:5001", APIVersion: "v1"}, http.DefaultClient)
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:          "any node",
+			expectedTried: []string{"reads"},
+		},
+		testcase{
+			name:          "routed to a node",
+			names:         []string{"payments"},
+			expectedTried: []string{"payments"},
+		},
+		testcase{
+			name:          "failed over between the routed nodes alone",
+			names:         []string{"standby", "payments"},
+			failing:       map[string]bool{"payments": true},
+			expectedTried: []string{"payments", "standby"},
+		},
+		testcase{
+			name:          "routed node unreachable",
+			names:         []string{"payments"},
+			failing:       map[string]bool{"payments": true},
+			expectedTried: []string{"payments"},
+			expectedError: "all raiden nodes failed",
+		},
+		testcase{
+			name:          "routed to no node of the pool",
+			names:         []string{"archive"},
+			expectedTried: []string{},
+			expectedError: "no raiden node named archive in pool",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				pool  = NewPool(PrimaryWithFallback(), reads, payments, standby)
+				ctx   = context.Background()
+				tried = make([]string, 0)
+			)
+
+			if tc.names != nil {
+				ctx = WithNodes(ctx, tc.names...)
+			}
+
+			assert.Equal(t, tc.names, NodesFromContext(ctx))
+
+			_, err := pool.Do(ctx, common.Address{}, func(ctx context.Context, node *Node) error {
+				tried = append(tried, node.Name)
+
+				if tc.failing[node.Name] {
+					return &url.Error{Op: "Get", URL: node.Config.Host, Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+				}
+
+				return nil
+			})
+
+			assert.Equal(t, tc.expectedTried, tried)
+
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package multinode
+
+import (
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Selector is a strategy that decides in which order the nodes of a Pool are tried
+// for a call. The key is the token address the call is for, or the zero address when
+// the call is not specific to a token. Observe is called with the outcome of every
+// attempt so that strategies can adapt to the health of each node.
+type Selector interface {
+	Select(nodes []*Node, key common.Address) []*Node
+	Observe(node *Node, latency time.Duration, err error)
+}
+
+// PrimaryWithFallback returns a Selector that always tries the nodes in the order
+// they were added to the pool, so the first node serves every call while it is up.
+func PrimaryWithFallback() Selector {
+	return &primarySelector{}
+}
+
+type primarySelector struct{}
+
+func (selector *primarySelector) Select(nodes []*Node, key common.Address) []*Node {
+	return nodes
+}
+
+func (selector *primarySelector) Observe(node *Node, latency time.Duration, err error) {}
+
+// LowestLatency returns a Selector that tries the node with the lowest average
+// latency first. Nodes that have not served a call yet are tried before any measured
+// node so that every node gets measured, and a failed call counts as the given
+// penalty latency.
+func LowestLatency(penalty time.Duration) Selector {
+	return &latencySelector{
+		penalty:   penalty,
+		latencies: make(map[*Node]time.Duration),
+	}
+}
+
+// latencyWeight is the weight given to the latest observation in the moving average.
+const latencyWeight = 0.3
+
+type latencySelector struct {
+	mutex     sync.RWMutex
+	penalty   time.Duration
+	latencies map[*Node]time.Duration
+}
+
+func (selector *latencySelector) Select(nodes []*Node, key common.Address) []*Node {
+	var ordered = make([]*Node, len(nodes))
+
+	copy(ordered, nodes)
+
+	selector.mutex.RLock()
+	defer selector.mutex.RUnlock()
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		var (
+			latencyI, measuredI = selector.latencies[ordered[i]]
+			latencyJ, measuredJ = selector.latencies[ordered[j]]
+		)
+
+		if measuredI != measuredJ {
+			return !measuredI
+		}
+
+		return latencyI < latencyJ
+	})
+
+	return ordered
+}
+
+func (selector *latencySelector) Observe(node *Node, latency time.Duration, err error) {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	if err != nil && latency < selector.penalty {
+		latency = selector.penalty
+	}
+
+	if average, ok := selector.latencies[node]; ok {
+		latency = time.Duration(latencyWeight*float64(latency) + (1-latencyWeight)*float64(average))
+	}
+
+	selector.latencies[node] = latency
+}
+
+// StickyByToken returns a Selector that consistently routes the calls for a given
+// token to the same node, spreading tokens across the pool. The remaining nodes are
+// used as fallbacks in a consistent order, so a token only moves when its node fails.
+func StickyByToken() Selector {
+	return &stickySelector{}
+}
+
+type stickySelector struct{}
+
+func (selector *stickySelector) Select(nodes []*Node, key common.Address) []*Node {
+	var (
+		ordered = make([]*Node, len(nodes))
+		weights = make(map[*Node]uint64)
+	)
+
+	copy(ordered, nodes)
+
+	for _, node := range nodes {
+		var hash = fnv.New64a()
+
+		hash.Write(key.Bytes())
+		hash.Write([]byte(node.Name))
+
+		weights[node] = hash.Sum64()
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return weights[ordered[i]] > weights[ordered[j]]
+	})
+
+	return ordered
+}
+
+func (selector *stickySelector) Observe(node *Node, latency time.Duration, err error) {}
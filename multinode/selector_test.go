@@ -0,0 +1,63 @@
+package multinode
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectors(t *testing.T) {
+	var (
+		first  = NewNode("first", &config.Config{Host: "http://raiden-1:5001", APIVersion: "v1"}, http.DefaultClient)
+		second = NewNode("second", &config.Config{Host: "http://raiden-2:5001", APIVersion: "v1"}, http.DefaultClient)
+		third  = NewNode("third", &config.Config{Host: "http://raiden-3:5001", APIVersion: "v1"}, http.DefaultClient)
+		nodes  = []*Node{first, second, third}
+		token  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	)
+
+	t.Run("primary with fallback keeps the configured order", func(t *testing.T) {
+		var selector = PrimaryWithFallback()
+
+		selector.Observe(first, time.Second, errors.New("unreachable"))
+
+		assert.Equal(t, nodes, selector.Select(nodes, token))
+	})
+
+	t.Run("lowest latency prefers unmeasured then fastest nodes", func(t *testing.T) {
+		var selector = LowestLatency(5 * time.Second)
+
+		selector.Observe(first, 300*time.Millisecond, nil)
+		selector.Observe(second, 100*time.Millisecond, nil)
+
+		assert.Equal(t, []*Node{third, second, first}, selector.Select(nodes, token))
+
+		selector.Observe(third, 10*time.Millisecond, errors.New("unreachable"))
+
+		assert.Equal(t, []*Node{second, first, third}, selector.Select(nodes, token))
+	})
+
+	t.Run("sticky by token is consistent", func(t *testing.T) {
+		var (
+			selector   = StickyByToken()
+			ordered    = selector.Select(nodes, token)
+			otherToken = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		)
+
+		assert.Len(t, ordered, len(nodes))
+		assert.ElementsMatch(t, nodes, ordered)
+
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, ordered, selector.Select(nodes, token))
+		}
+
+		// removing a node other than the preferred one must not move the token
+		assert.Equal(t, ordered[0], selector.Select([]*Node{ordered[2], ordered[0]}, token)[0])
+
+		assert.ElementsMatch(t, nodes, selector.Select(nodes, otherToken))
+	})
+}
@@ -0,0 +1,57 @@
+package node
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/version"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	_ StatusGetter       = &Client{}
+	_ SyncInfoGetter     = &Client{}
+	_ SettingsGetter     = &Client{}
+	_ Shutdowner         = &Client{}
+	_ ReachabilityGetter = &Client{}
+)
+
+// NewClient creates a new node client that provides access to the address, version,
+// status, block synchronization and settings of a Raiden node, the reachability of
+// its partners, and to shutting it down.
+func NewClient(config *config.Config, httpClient *http.Client) *Client {
+	return &Client{
+		StatusGetter:       NewStatusGetter(config, httpClient),
+		SyncInfoGetter:     NewSyncInfoGetter(config, httpClient),
+		SettingsGetter:     NewSettingsGetter(config, httpClient),
+		Shutdowner:         NewShutdowner(config, httpClient),
+		ReachabilityGetter: NewReachabilityGetter(config, httpClient),
+		addressGetter:      address.NewGetter(config, httpClient),
+		versionGetter:      version.NewGetter(config, httpClient),
+	}
+}
+
+// Client is a node client that allows access to the HTTP calls about the Raiden node
+// itself.
+type Client struct {
+	StatusGetter
+	SyncInfoGetter
+	SettingsGetter
+	Shutdowner
+	ReachabilityGetter
+
+	addressGetter address.Getter
+	versionGetter version.Getter
+}
+
+// Address returns the Ethereum address of the node, see address.Getter.
+func (client *Client) Address(ctx context.Context) (common.Address, error) {
+	return client.addressGetter.Get(ctx)
+}
+
+// Version returns the version of the node, see version.Getter.
+func (client *Client) Version(ctx context.Context) (string, error) {
+	return client.versionGetter.Get(ctx)
+}
@@ -0,0 +1,31 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+func ExampleClient() {
+	var (
+		nodeClient *Client
+		config     = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		status *Status
+		err    error
+	)
+
+	nodeClient = NewClient(config, http.DefaultClient)
+
+	if status, err = nodeClient.Status(context.Background()); err != nil {
+		panic(fmt.Sprintf("unable to get the status of the raiden node: %s", err.Error()))
+	}
+
+	if !status.Ready() {
+		fmt.Printf("raiden node not ready: %s, %d blocks to sync\n", status.Status, status.BlocksToSync)
+	}
+}
@@ -0,0 +1,96 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The reachability of a partner of a Raiden node, i.e. whether its presence in the
+// matrix transport is online, as the node sees it.
+const (
+	// ReachabilityReachable is a partner online, which payments can be sent to.
+	ReachabilityReachable = "reachable"
+	// ReachabilityUnreachable is a partner offline, which payments to wait for until
+	// they expire.
+	ReachabilityUnreachable = "unreachable"
+	// ReachabilityUnknown is a partner whose presence the node did not see yet, and
+	// the reachability of the partners of the nodes without the reachability
+	// endpoint.
+	ReachabilityUnknown = "unknown"
+)
+
+// Reachability is the reachability of a partner of a Raiden node.
+type Reachability struct {
+	Address      common.Address `json:"address"`
+	Reachability string         `json:"reachability"`
+}
+
+// Reachable tells whether the partner is online.
+func (reachability *Reachability) Reachable() bool {
+	return reachability.Reachability == ReachabilityReachable
+}
+
+// Unreachable tells whether the partner is known to be offline, unlike a partner
+// whose reachability is unknown.
+func (reachability *Reachability) Unreachable() bool {
+	return reachability.Reachability == ReachabilityUnreachable
+}
+
+// ReachabilityGetter is a generic interface to get the reachability of the partners
+// of a Raiden node, e.g. before paying them, paying offline targets being the most
+// common failure of payments.
+type ReachabilityGetter interface {
+	PartnerReachable(ctx context.Context, partnerAddress common.Address) (*Reachability, error)
+}
+
+// NewReachabilityGetter creates a new default reachability getter given a Raiden node
+// configuration and an http client.
+func NewReachabilityGetter(config *config.Config, httpClient *http.Client) ReachabilityGetter {
+	return &defaultReachabilityGetter{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultReachabilityGetter struct {
+	baseClient *util.BaseClient
+}
+
+// PartnerReachable will return the reachability of the partner from the debug
+// endpoint of the node exposing the presence its transport sees. A node without the
+// endpoint answers that the reachability of the partner is ReachabilityUnknown.
+func (getter *defaultReachabilityGetter) PartnerReachable(ctx context.Context, partnerAddress common.Address) (*Reachability, error) {
+	var (
+		err          error
+		requestURL   *url.URL
+		reachability = &Reachability{}
+	)
+
+	if requestURL, err = getter.baseClient.Endpoint("_debug/reachability/%s", partnerAddress.Hex()); err != nil {
+		return nil, err
+	}
+
+	if err = getter.baseClient.Call(ctx, "GET", requestURL, nil, reachability); err != nil {
+		if raidenerrors.StatusCode(err) == http.StatusNotFound {
+			return &Reachability{Address: partnerAddress, Reachability: ReachabilityUnknown}, nil
+		}
+
+		return nil, err
+	}
+
+	reachability.Address = partnerAddress
+
+	if reachability.Reachability == "" {
+		reachability.Reachability = ReachabilityUnknown
+	}
+
+	return reachability, nil
+}
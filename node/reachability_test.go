@@ -0,0 +1,118 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleReachabilityGetter() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	reachability, err := NewClient(config, http.DefaultClient).PartnerReachable(context.Background(), targetAddress)
+	if err != nil {
+		panic(fmt.Sprintf("unable to get reachability: %s", err.Error()))
+	}
+
+	if reachability.Unreachable() {
+		fmt.Println("not paying offline target", targetAddress.Hex())
+	}
+}
+
+func TestReachabilityGetter(t *testing.T) {
+	type testcase struct {
+		name                 string
+		prepHTTPMock         func()
+		expectedReachability string
+		expectedReachable    bool
+		expectedError        error
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		partnerAddress  = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		reachabilityURL = "http://localhost:5001/api/v1/_debug/reachability/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+	)
+
+	testcases := []testcase{
+		testcase{
+			name: "reachable",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", reachabilityURL, httpmock.NewStringResponder(http.StatusOK, `{"reachability":"reachable"}`))
+			},
+			expectedReachability: ReachabilityReachable,
+			expectedReachable:    true,
+		},
+		testcase{
+			name: "unreachable",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", reachabilityURL, httpmock.NewStringResponder(http.StatusOK, `{"reachability":"unreachable"}`))
+			},
+			expectedReachability: ReachabilityUnreachable,
+		},
+		testcase{
+			name: "presence not seen yet",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", reachabilityURL, httpmock.NewStringResponder(http.StatusOK, `{}`))
+			},
+			expectedReachability: ReachabilityUnknown,
+		},
+		testcase{
+			name: "node without the reachability endpoint",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", reachabilityURL, httpmock.NewStringResponder(http.StatusNotFound, ``))
+			},
+			expectedReachability: ReachabilityUnknown,
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", reachabilityURL, httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+			},
+			expectedError: errors.New("EOF"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				getter = NewReachabilityGetter(config, http.DefaultClient)
+				ctx    = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.Deactivate()
+
+			tc.prepHTTPMock()
+
+			reachability, err := getter.PartnerReachable(ctx, partnerAddress)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, reachability)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, &Reachability{Address: partnerAddress, Reachability: tc.expectedReachability}, reachability)
+			assert.Equal(t, tc.expectedReachable, reachability.Reachable())
+		})
+	}
+}
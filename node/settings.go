@@ -0,0 +1,60 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+// Settings are the settings the Raiden node reports: the pathfinding service it
+// uses, empty when it uses none, and its timeouts, in blocks, zero when the node does
+// not report them, see channels.Timeouts for the defaults.
+type Settings struct {
+	PathfindingServiceAddress string `json:"pathfinding_service_address"`
+	DefaultSettleTimeout      int64  `json:"default_settle_timeout,omitempty"`
+	DefaultRevealTimeout      int64  `json:"default_reveal_timeout,omitempty"`
+	SettlementTimeoutMin      int64  `json:"settlement_timeout_min,omitempty"`
+	SettlementTimeoutMax      int64  `json:"settlement_timeout_max,omitempty"`
+}
+
+// SettingsGetter is a generic interface to get the settings of a Raiden node.
+type SettingsGetter interface {
+	Settings(ctx context.Context) (*Settings, error)
+}
+
+// NewSettingsGetter creates a new default settings getter given a Raiden node
+// configuration and an http client.
+func NewSettingsGetter(config *config.Config, httpClient *http.Client) SettingsGetter {
+	return &defaultSettingsGetter{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultSettingsGetter struct {
+	baseClient *util.BaseClient
+}
+
+// Settings will return the settings of the node.
+func (getter *defaultSettingsGetter) Settings(ctx context.Context) (*Settings, error) {
+	var (
+		err        error
+		requestURL *url.URL
+		settings   = &Settings{}
+	)
+
+	if requestURL, err = getter.baseClient.Endpoint("settings"); err != nil {
+		return nil, err
+	}
+
+	if err = getter.baseClient.Call(ctx, "GET", requestURL, nil, settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
@@ -0,0 +1,85 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsGetter(t *testing.T) {
+	type testcase struct {
+		name             string
+		prepHTTPMock     func()
+		expectedSettings *Settings
+		expectedError    error
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully got settings",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder(
+					"GET",
+					"http://localhost:5001/api/v1/settings",
+					httpmock.NewStringResponder(
+						http.StatusOK,
+						`{"pathfinding_service_address":"https://pfs.example.com","default_settle_timeout":500,"default_reveal_timeout":50}`,
+					),
+				)
+			},
+			expectedSettings: &Settings{
+				PathfindingServiceAddress: "https://pfs.example.com",
+				DefaultSettleTimeout:      500,
+				DefaultRevealTimeout:      50,
+			},
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/settings", httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+			},
+			expectedError: errors.New("EOF"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err      error
+				settings *Settings
+
+				getter = NewSettingsGetter(config, http.DefaultClient)
+				ctx    = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.Deactivate()
+
+			tc.prepHTTPMock()
+
+			settings, err = getter.Settings(ctx)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, settings)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSettings, settings)
+		})
+	}
+}
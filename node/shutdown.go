@@ -0,0 +1,49 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+type shutdownResponse struct {
+	Status string `json:"status"`
+}
+
+// Shutdowner is a generic interface to shut a Raiden node down.
+type Shutdowner interface {
+	Shutdown(ctx context.Context) error
+}
+
+// NewShutdowner creates a new default shutdowner given a Raiden node configuration
+// and an http client.
+func NewShutdowner(config *config.Config, httpClient *http.Client) Shutdowner {
+	return &defaultShutdowner{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultShutdowner struct {
+	baseClient *util.BaseClient
+}
+
+// Shutdown will ask the node to shut down gracefully, which it does once it answered.
+func (shutdowner *defaultShutdowner) Shutdown(ctx context.Context) error {
+	var (
+		err              error
+		requestURL       *url.URL
+		shutdownResponse = &shutdownResponse{}
+	)
+
+	if requestURL, err = shutdowner.baseClient.Endpoint("shutdown"); err != nil {
+		return err
+	}
+
+	return shutdowner.baseClient.Call(ctx, "POST", requestURL, nil, shutdownResponse)
+}
@@ -0,0 +1,67 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdowner(t *testing.T) {
+	type testcase struct {
+		name          string
+		prepHTTPMock  func()
+		expectedError error
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully shut down",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/shutdown", httpmock.NewStringResponder(http.StatusOK, `{"status":"shutdown"}`))
+			},
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/shutdown", httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+			},
+			expectedError: errors.New("EOF"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				shutdowner = NewShutdowner(config, http.DefaultClient)
+				ctx        = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.Deactivate()
+
+			tc.prepHTTPMock()
+
+			err := shutdowner.Shutdown(ctx)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Package node queries and manages the Raiden node itself: its address, version,
+// sync status and settings, and shutting it down, e.g. for health checks before
+// payments are sent to a node that may still be syncing.
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+// The statuses of a Raiden node.
+const (
+	// StatusReady is a node synced with the chain, ready for payments.
+	StatusReady = "ready"
+	// StatusSyncing is a node catching up with the chain, which refuses payments.
+	StatusSyncing = "syncing"
+	// StatusUnavailable is a node that can't serve requests, e.g. while it starts.
+	StatusUnavailable = "unavailable"
+	// StatusUnknown is the status of nodes without the status endpoint, which was
+	// added in Raiden 1.1.
+	StatusUnknown = ""
+)
+
+// Status is the sync status of a Raiden node, BlocksToSync being the number of
+// blocks a syncing node is behind the chain. The nodes reporting them give the last
+// block they confirmed and the latest block of the chain they saw, see SyncInfo.
+type Status struct {
+	Status         string `json:"status"`
+	BlocksToSync   int64  `json:"blocks_to_sync,omitempty"`
+	ConfirmedBlock int64  `json:"confirmed_block_number,omitempty"`
+	LatestBlock    int64  `json:"latest_block_number,omitempty"`
+}
+
+// Ready tells whether the node is synced with the chain.
+func (status *Status) Ready() bool {
+	return status.Status == StatusReady
+}
+
+// StatusGetter is a generic interface to get the sync status of a Raiden node.
+type StatusGetter interface {
+	Status(ctx context.Context) (*Status, error)
+}
+
+// NewStatusGetter creates a new default status getter given a Raiden node
+// configuration and an http client.
+func NewStatusGetter(config *config.Config, httpClient *http.Client) StatusGetter {
+	return &defaultStatusGetter{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultStatusGetter struct {
+	baseClient *util.BaseClient
+}
+
+// Status will return the sync status of the node. A node answering that it is
+// unavailable is not an error, its status being StatusUnavailable, and a node
+// without the status endpoint has the StatusUnknown status.
+func (getter *defaultStatusGetter) Status(ctx context.Context) (*Status, error) {
+	var (
+		err        error
+		requestURL *url.URL
+		status     = &Status{}
+	)
+
+	if requestURL, err = getter.baseClient.Endpoint("status"); err != nil {
+		return nil, err
+	}
+
+	if err = getter.baseClient.Call(ctx, "GET", requestURL, nil, status); err != nil {
+		switch raidenerrors.StatusCode(err) {
+		case http.StatusServiceUnavailable:
+			return &Status{Status: StatusUnavailable}, nil
+		case http.StatusNotFound:
+			return &Status{Status: StatusUnknown}, nil
+		}
+
+		return nil, err
+	}
+
+	return status, nil
+}
@@ -0,0 +1,98 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusGetter(t *testing.T) {
+	type testcase struct {
+		name           string
+		prepHTTPMock   func()
+		expectedStatus *Status
+		expectedReady  bool
+		expectedError  error
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name: "ready",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusOK, `{"status":"ready"}`))
+			},
+			expectedStatus: &Status{Status: StatusReady},
+			expectedReady:  true,
+		},
+		testcase{
+			name: "syncing",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusOK, `{"status":"syncing","blocks_to_sync":1200}`))
+			},
+			expectedStatus: &Status{Status: StatusSyncing, BlocksToSync: 1200},
+		},
+		testcase{
+			name: "unavailable",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusServiceUnavailable, ``))
+			},
+			expectedStatus: &Status{Status: StatusUnavailable},
+		},
+		testcase{
+			name: "node without the status endpoint",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusNotFound, ``))
+			},
+			expectedStatus: &Status{Status: StatusUnknown},
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+			},
+			expectedError: errors.New("EOF"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err    error
+				status *Status
+
+				getter = NewStatusGetter(config, http.DefaultClient)
+				ctx    = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.Deactivate()
+
+			tc.prepHTTPMock()
+
+			status, err = getter.Status(ctx)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, status)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedStatus, status)
+			assert.Equal(t, tc.expectedReady, status.Ready())
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+// SyncInfo is how far a Raiden node followed the chain: the last block it confirmed,
+// which is the block height of what it reports, the latest block of the chain it saw,
+// zero when the node does not report them, and the number of blocks it is behind. A
+// node whose confirmed block stops moving while the chain does is silently falling
+// behind, even when its status is still ready.
+type SyncInfo struct {
+	Status         string `json:"status"`
+	ConfirmedBlock int64  `json:"confirmed_block_number,omitempty"`
+	LatestBlock    int64  `json:"latest_block_number,omitempty"`
+	BlocksBehind   int64  `json:"blocks_behind"`
+}
+
+// Lagging tells whether the node is more than blocks behind the chain.
+func (info *SyncInfo) Lagging(blocks int64) bool {
+	return info.BlocksBehind > blocks
+}
+
+// SyncInfoGetter is a generic interface to get the block synchronization details of a
+// Raiden node.
+type SyncInfoGetter interface {
+	SyncInfo(ctx context.Context) (*SyncInfo, error)
+}
+
+// NewSyncInfoGetter creates a new default sync info getter given a Raiden node
+// configuration and an http client.
+func NewSyncInfoGetter(config *config.Config, httpClient *http.Client) SyncInfoGetter {
+	return &defaultSyncInfoGetter{
+		statusGetter: NewStatusGetter(config, httpClient),
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultSyncInfoGetter struct {
+	statusGetter StatusGetter
+	baseClient   *util.BaseClient
+}
+
+// blockNumbers is the reply of the blocks debug endpoint.
+type blockNumbers struct {
+	ConfirmedBlock int64 `json:"confirmed_block_number"`
+	LatestBlock    int64 `json:"latest_block_number"`
+}
+
+// SyncInfo will return the block synchronization details of the node, from its status
+// and, when the status does not give the block numbers, from its blocks debug
+// endpoint. The block numbers of a node without either are zero, the blocks behind
+// then being the blocks to sync of its status.
+func (getter *defaultSyncInfoGetter) SyncInfo(ctx context.Context) (*SyncInfo, error) {
+	var (
+		err        error
+		requestURL *url.URL
+		status     *Status
+		numbers    = &blockNumbers{}
+	)
+
+	if status, err = getter.statusGetter.Status(ctx); err != nil {
+		return nil, err
+	}
+
+	numbers.ConfirmedBlock, numbers.LatestBlock = status.ConfirmedBlock, status.LatestBlock
+
+	if numbers.LatestBlock == 0 && status.Status != StatusUnavailable {
+		if requestURL, err = getter.baseClient.Endpoint("_debug/blocks"); err != nil {
+			return nil, err
+		}
+
+		if err = getter.baseClient.Call(ctx, "GET", requestURL, nil, numbers); err != nil && raidenerrors.StatusCode(err) != http.StatusNotFound {
+			return nil, err
+		}
+	}
+
+	info := &SyncInfo{
+		Status:         status.Status,
+		ConfirmedBlock: numbers.ConfirmedBlock,
+		LatestBlock:    numbers.LatestBlock,
+		BlocksBehind:   status.BlocksToSync,
+	}
+
+	if info.LatestBlock > info.ConfirmedBlock && info.ConfirmedBlock > 0 {
+		info.BlocksBehind = info.LatestBlock - info.ConfirmedBlock
+	}
+
+	return info, nil
+}
@@ -0,0 +1,122 @@
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSyncInfoGetter() {
+	var (
+		getter = NewSyncInfoGetter(&config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}, http.DefaultClient)
+		info *SyncInfo
+		err  error
+	)
+
+	if info, err = getter.SyncInfo(context.Background()); err != nil {
+		panic(fmt.Sprintf("unable to get the sync info of the raiden node: %s", err.Error()))
+	}
+
+	if info.Lagging(10) {
+		fmt.Printf("raiden node at block %d, %d blocks behind the chain\n", info.ConfirmedBlock, info.BlocksBehind)
+	}
+}
+
+func TestSyncInfoGetter(t *testing.T) {
+	type testcase struct {
+		name            string
+		prepHTTPMock    func()
+		expectedInfo    *SyncInfo
+		expectedLagging bool
+		expectedError   error
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name: "block numbers in the status",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusOK, `{"status":"ready","confirmed_block_number":7281900,"latest_block_number":7281905}`))
+			},
+			expectedInfo: &SyncInfo{Status: StatusReady, ConfirmedBlock: 7281900, LatestBlock: 7281905, BlocksBehind: 5},
+		},
+		testcase{
+			name: "block numbers from the debug endpoint",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusOK, `{"status":"ready"}`))
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/_debug/blocks", httpmock.NewStringResponder(http.StatusOK, `{"confirmed_block_number":7281880,"latest_block_number":7281905}`))
+			},
+			expectedInfo:    &SyncInfo{Status: StatusReady, ConfirmedBlock: 7281880, LatestBlock: 7281905, BlocksBehind: 25},
+			expectedLagging: true,
+		},
+		testcase{
+			name: "node without block numbers",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusOK, `{"status":"syncing","blocks_to_sync":1200}`))
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/_debug/blocks", httpmock.NewStringResponder(http.StatusNotFound, ``))
+			},
+			expectedInfo:    &SyncInfo{Status: StatusSyncing, BlocksBehind: 1200},
+			expectedLagging: true,
+		},
+		testcase{
+			name: "unavailable node",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusServiceUnavailable, ``))
+			},
+			expectedInfo: &SyncInfo{Status: StatusUnavailable},
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusOK, `{"status":"ready"}`))
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/_debug/blocks", httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+			},
+			expectedError: errors.New("EOF"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err  error
+				info *SyncInfo
+
+				getter = NewSyncInfoGetter(config, http.DefaultClient)
+				ctx    = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			info, err = getter.SyncInfo(ctx)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, info)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedInfo, info)
+			assert.Equal(t, tc.expectedLagging, info.Lagging(10))
+		})
+	}
+}
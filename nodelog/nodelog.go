@@ -0,0 +1,120 @@
+// Package nodelog finds the calls of a client in the JSON logs of a Raiden node, or of
+// the proxy in front of it, by the marker the client sent them with, see
+// config.Config.MarkerHeader, to follow a failed payment from the client into the
+// node.
+package nodelog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLine is the longest line of a log that is parsed, longer lines being skipped.
+const maxLine = 1 << 20
+
+// timeLayouts are the layouts of the timestamps of the logs, the one of the Raiden
+// node first.
+var timeLayouts = []string{
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02T15:04:05.999999",
+	time.RFC3339Nano,
+}
+
+// Entry is a line of a JSON log, as logged by the structlog logger of the Raiden node.
+// Fields holds every field of the line, the known ones included.
+type Entry struct {
+	Line   int
+	Time   time.Time
+	Level  string
+	Logger string
+	Event  string
+	Fields map[string]interface{}
+}
+
+// Parse parses a line of a JSON log. The time is zero when the line has no timestamp
+// in a known layout.
+func Parse(line []byte) (*Entry, error) {
+	var entry = &Entry{Fields: make(map[string]interface{})}
+
+	if err := json.Unmarshal(line, &entry.Fields); err != nil {
+		return nil, fmt.Errorf("invalid log line: %s", err.Error())
+	}
+
+	entry.Level, _ = entry.Fields["level"].(string)
+	entry.Logger, _ = entry.Fields["logger"].(string)
+	entry.Event, _ = entry.Fields["event"].(string)
+
+	if timestamp, ok := entry.Fields["timestamp"].(string); ok {
+		for _, layout := range timeLayouts {
+			if parsed, err := time.Parse(layout, timestamp); err == nil {
+				entry.Time = parsed
+				break
+			}
+		}
+	}
+
+	return entry, nil
+}
+
+// Find returns the entries of the log read from the reader that mention the marker in
+// any of their values, in the order they were logged. Lines that are not JSON, such
+// as the output of the node before its logging is set up, are skipped.
+func Find(reader io.Reader, marker string) ([]*Entry, error) {
+	var (
+		scanner = bufio.NewScanner(reader)
+		entries = make([]*Entry, 0)
+		line    int
+	)
+
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLine)
+
+	for scanner.Scan() {
+		line++
+
+		if !bytes.Contains(scanner.Bytes(), []byte(marker)) {
+			continue
+		}
+
+		entry, err := Parse(scanner.Bytes())
+		if err != nil || !mentions(entry.Fields, marker) {
+			continue
+		}
+
+		entry.Line = line
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// mentions tells whether a value of the field, or of its nested fields, contains the
+// marker.
+func mentions(value interface{}, marker string) bool {
+	switch value := value.(type) {
+	case string:
+		return strings.Contains(value, marker)
+	case map[string]interface{}:
+		for _, field := range value {
+			if mentions(field, marker) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, field := range value {
+			if mentions(field, marker) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
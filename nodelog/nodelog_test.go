@@ -0,0 +1,70 @@
+package nodelog
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleFind() {
+	file, err := os.Open("/var/log/raiden/node.log")
+	if err != nil {
+		panic(err)
+	}
+
+	defer file.Close()
+
+	// the marker of a failed call, see meta.Meta
+	entries, err := Find(file, "rc-5f2b9c0e4d7a1b3c")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		fmt.Println(entry.Time, entry.Level, entry.Event)
+	}
+}
+
+func TestFind(t *testing.T) {
+	var log = strings.Join([]string{
+		`Welcome to Raiden, version 0.100.3`,
+		`{"event": "Request", "level": "debug", "logger": "raiden.api.rest", "timestamp": "2019-03-07 18:19:13.976123", "headers": {"X-Raiden-Client-Marker": "rc-5f2b9c0e4d7a1b3c"}}`,
+		`{"event": "Request", "level": "debug", "logger": "raiden.api.rest", "timestamp": "2019-03-07 18:19:14.000000", "headers": {"X-Raiden-Client-Marker": "rc-0000000000000000"}}`,
+		`not json rc-5f2b9c0e4d7a1b3c`,
+		`{"event": "Payment failed", "level": "error", "logger": "raiden.api.python", "timestamp": "2019-03-07T18:19:15.5Z", "marker": "rc-5f2b9c0e4d7a1b3c", "reasons": ["no route"]}`,
+		`{"event": "rc-5f2b9c0e4d7a1b3c", "marker": 5}`,
+	}, "\n")
+
+	entries, err := Find(strings.NewReader(log), "rc-5f2b9c0e4d7a1b3c")
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	assert.Equal(t, 2, entries[0].Line)
+	assert.Equal(t, "Request", entries[0].Event)
+	assert.Equal(t, "debug", entries[0].Level)
+	assert.Equal(t, "raiden.api.rest", entries[0].Logger)
+	assert.Equal(t, time.Date(2019, 3, 7, 18, 19, 13, 976123000, time.UTC), entries[0].Time)
+
+	assert.Equal(t, 5, entries[1].Line)
+	assert.Equal(t, "Payment failed", entries[1].Event)
+	assert.Equal(t, time.Date(2019, 3, 7, 18, 19, 15, 500000000, time.UTC), entries[1].Time)
+	assert.Equal(t, []interface{}{"no route"}, entries[1].Fields["reasons"])
+
+	assert.Equal(t, 6, entries[2].Line)
+	assert.True(t, entries[2].Time.IsZero())
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse([]byte("Welcome to Raiden"))
+	assert.Error(t, err)
+
+	entry, err := Parse([]byte(`{"event": "Started", "timestamp": "2019-03-07T18:19:13.976"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Started", entry.Event)
+	assert.Equal(t, time.Date(2019, 3, 7, 18, 19, 13, 976000000, time.UTC), entry.Time)
+}
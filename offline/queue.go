@@ -0,0 +1,383 @@
+// Package offline provides an optional durable queue for mutating requests made
+// against a Raiden node. Requests that cannot reach the node are persisted and then
+// replayed once the node is healthy again.
+package offline
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/changefeed"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+)
+
+// ErrQueued is returned for a mutating request that could not reach the Raiden node
+// and has been queued to be replayed later.
+var ErrQueued = errors.New("raiden node unreachable, request queued for replay")
+
+// IsQueued reports whether the error returned by an http client, or any of the
+// Raiden clients using it, means that the request was queued.
+func IsQueued(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+
+	return err == ErrQueued
+}
+
+// Resolution is the decision of a ConflictHandler on what to do with a replayed
+// request that the Raiden node rejected.
+type Resolution int
+
+const (
+	// Discard removes the request from the queue.
+	Discard Resolution = iota
+	// Keep leaves the request in the queue to be replayed again later.
+	Keep
+)
+
+// ConflictHandler is called when the Raiden node responds to a replayed request with
+// an error status, such as a 409 when a payment identifier was already used.
+type ConflictHandler func(request *Request, response *http.Response) Resolution
+
+// DefaultConflictHandler keeps the idempotent requests the node failed to process
+// (5xx), e.g. a deposit setting the total deposit of a channel, so they are retried,
+// and discards any request the node refused (4xx). A POST the node failed with a 5xx,
+// e.g. a payment, may have been processed nonetheless and is discarded rather than
+// replayed, its outcome being for the application to check, see ReplayReport.
+func DefaultConflictHandler(request *Request, response *http.Response) Resolution {
+	if response.StatusCode >= http.StatusInternalServerError && request.Method != "POST" {
+		return Keep
+	}
+
+	return Discard
+}
+
+// ReplayReport describes the outcome of replaying the queue.
+type ReplayReport struct {
+	Replayed  []*Request
+	Discarded []*Request
+	Pending   []*Request
+}
+
+// NewQueue creates a queue for requests made to the configured Raiden node. Requests
+// are sent using the base round tripper, http.DefaultTransport when nil, and queued
+// requests are persisted in the given Store.
+func NewQueue(config *config.Config, base http.RoundTripper, store Store) *Queue {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Queue{
+		Config:          config,
+		Base:            base,
+		Store:           store,
+		ConflictHandler: DefaultConflictHandler,
+		IdempotencyKey:  DefaultIdempotencyKey,
+	}
+}
+
+// Queue is an http.RoundTripper that queues mutating requests (POST, PUT, PATCH
+// and DELETE) when the Raiden node cannot be reached, read requests are never queued.
+// A request is only queued when the connection to the node could not be established,
+// so that a request the node may have processed is never replayed, and payments are
+// only queued with an identifier, which keeps the node from paying twice.
+//
+// A request is not queued twice if a request with the same idempotency key, such as
+// a payment with the same identifier, is already waiting in the queue. The credential
+// headers of the requests, such as Authorization, are not persisted, the Base round
+// tripper is expected to add them again when replaying, e.g. the one of
+// config.Profile. The queueing times and the replays of Run are timed by Clock, the
+// system time when nil.
+type Queue struct {
+	Config          *config.Config
+	Base            http.RoundTripper
+	Store           Store
+	ConflictHandler ConflictHandler
+	IdempotencyKey  func(method, url string, body []byte) string
+	Clock           clock.Clock
+
+	mutex sync.Mutex
+}
+
+var _ http.RoundTripper = &Queue{}
+
+// HTTPClient returns an http client that sends its requests through the queue, to
+// be passed to any of the Raiden clients.
+func (queue *Queue) HTTPClient() *http.Client {
+	return &http.Client{
+		Transport: queue,
+	}
+}
+
+// RoundTrip will send the request to the Raiden node, queueing it if it is mutating
+// and the connection to the node could not be established. Any other error of the
+// base round tripper is returned as is.
+func (queue *Queue) RoundTrip(request *http.Request) (*http.Response, error) {
+	var (
+		err      error
+		body     []byte
+		response *http.Response
+	)
+
+	if !isMutating(request.Method) {
+		return queue.Base.RoundTrip(request)
+	}
+
+	if request.Body != nil {
+		if body, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+
+		request.Body.Close()
+
+		request = request.WithContext(request.Context())
+		request.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	if response, err = queue.Base.RoundTrip(request); err == nil {
+		return response, nil
+	}
+
+	if request.Context().Err() != nil || !raidenerrors.IsUnsent(err) {
+		return nil, err
+	}
+
+	if payment, identifier := paymentIdentifier(request.Method, request.URL.String(), body); payment && identifier == 0 {
+		return nil, err
+	}
+
+	if err = queue.enqueue(request, body); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrQueued
+}
+
+func (queue *Queue) enqueue(request *http.Request, body []byte) error {
+	var (
+		err      error
+		requests []*Request
+		key      = queue.IdempotencyKey(request.Method, request.URL.String(), body)
+		now      = clock.Or(queue.Clock).Now()
+	)
+
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if requests, err = queue.Store.List(); err != nil {
+		return err
+	}
+
+	for _, queued := range requests {
+		if queued.IdempotencyKey == key {
+			return nil
+		}
+	}
+
+	return queue.Store.Enqueue(&Request{
+		ID:             fmt.Sprintf("%d-%s", now.UnixNano(), key[:8]),
+		IdempotencyKey: key,
+		Method:         request.Method,
+		URL:            request.URL.String(),
+		Header:         withoutCredentials(request.Header),
+		Body:           body,
+		QueuedAt:       now,
+	})
+}
+
+// Healthy reports whether the Raiden node can be reached, by querying its address.
+func (queue *Queue) Healthy(ctx context.Context) error {
+	var (
+		err      error
+		request  *http.Request
+		response *http.Response
+		endpoint = queue.Config.APIURL() + "/address"
+	)
+
+	if request, err = http.NewRequest("GET", endpoint, nil); err != nil {
+		return err
+	}
+
+	if response, err = queue.Base.RoundTrip(request.WithContext(ctx)); err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("recieved %d status code from raiden node", response.StatusCode)
+	}
+
+	return nil
+}
+
+// Replay will send every queued request to the Raiden node in the order in which
+// they were queued, once the node is healthy. Replaying stops at the first request
+// that still cannot reach the node, or that the ConflictHandler keeps, leaving it and
+// the following requests queued.
+func (queue *Queue) Replay(ctx context.Context) (*ReplayReport, error) {
+	var (
+		err      error
+		requests []*Request
+		report   = &ReplayReport{}
+	)
+
+	if err = queue.Healthy(ctx); err != nil {
+		return nil, err
+	}
+
+	queue.mutex.Lock()
+	defer queue.mutex.Unlock()
+
+	if requests, err = queue.Store.List(); err != nil {
+		return nil, err
+	}
+
+	for i, queued := range requests {
+		var response *http.Response
+
+		if response, err = queue.send(ctx, queued); err != nil {
+			report.Pending = append(report.Pending, requests[i:]...)
+			return report, err
+		}
+
+		if response.StatusCode < http.StatusBadRequest {
+			response.Body.Close()
+			report.Replayed = append(report.Replayed, queued)
+
+			if err = queue.Store.Remove(queued.ID); err != nil {
+				return report, err
+			}
+
+			continue
+		}
+
+		resolution := queue.ConflictHandler(queued, response)
+		response.Body.Close()
+
+		// the requests after a kept one wait for it, to be replayed in the order queued
+		if resolution == Keep {
+			report.Pending = append(report.Pending, requests[i:]...)
+
+			return report, queue.Store.Update(queued)
+		}
+
+		report.Discarded = append(report.Discarded, queued)
+
+		if err = queue.Store.Remove(queued.ID); err != nil {
+			return report, err
+		}
+	}
+
+	return report, nil
+}
+
+// Run will replay the queue until the context is done, errors from a replay are
+// ignored since the queue is simply replayed again at the next tick. The queue is
+// replayed at the given interval while requests go through, and less and less often
+// while none does, e.g. while the node stays unreachable, see changefeed.Feed.
+func (queue *Queue) Run(ctx context.Context, interval time.Duration) error {
+	var (
+		feed    = changefeed.New(&changefeed.Options{MinInterval: interval, Clock: queue.Clock})
+		changed = true
+	)
+
+	for feed.Wait(ctx, changed) {
+		report, err := queue.Replay(ctx)
+		changed = err == nil && len(report.Replayed) > 0
+	}
+
+	return ctx.Err()
+}
+
+func (queue *Queue) send(ctx context.Context, queued *Request) (*http.Response, error) {
+	var (
+		err     error
+		request *http.Request
+	)
+
+	queued.Attempts++
+
+	if request, err = http.NewRequest(queued.Method, queued.URL, bytes.NewReader(queued.Body)); err != nil {
+		return nil, err
+	}
+
+	for name, values := range queued.Header {
+		request.Header[name] = values
+	}
+
+	return queue.Base.RoundTrip(request.WithContext(ctx))
+}
+
+// DefaultIdempotencyKey uses the payment identifier of a payment request as its
+// idempotency key, any other request is keyed by its method, url and body.
+func DefaultIdempotencyKey(method, url string, body []byte) string {
+	var hash = sha256.New()
+
+	if payment, identifier := paymentIdentifier(method, url, body); payment && identifier != 0 {
+		fmt.Fprintf(hash, "%s %d", url, identifier)
+	} else {
+		fmt.Fprintf(hash, "%s %s %s", method, url, body)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// paymentIdentifier tells whether the request initiates a payment, and the payment
+// identifier it carries, zero when it has none.
+func paymentIdentifier(method, url string, body []byte) (bool, int64) {
+	var payment = &struct {
+		Identifier int64 `json:"identifier"`
+	}{}
+
+	if method != "POST" || !strings.Contains(url, "/payments/") {
+		return false, 0
+	}
+
+	if json.Unmarshal(body, payment) != nil {
+		return true, 0
+	}
+
+	return true, payment.Identifier
+}
+
+// credentialHeaders are the headers which are never persisted with a queued request.
+var credentialHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// withoutCredentials returns a copy of the header without its credential headers.
+func withoutCredentials(header http.Header) http.Header {
+	var stripped = make(http.Header, len(header))
+
+	for name, values := range header {
+		stripped[name] = values
+	}
+
+	for _, name := range credentialHeaders {
+		stripped.Del(name)
+	}
+
+	return stripped
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case "POST", "PUT", "PATCH", "DELETE":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,264 @@
+package offline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNode struct {
+	mutex    sync.Mutex
+	down     bool
+	broken   bool
+	statuses map[string]int
+	received []string
+}
+
+func (node *fakeNode) RoundTrip(request *http.Request) (*http.Response, error) {
+	node.mutex.Lock()
+	defer node.mutex.Unlock()
+
+	if node.down {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: &os.SyscallError{Syscall: "connect", Err: syscall.ECONNREFUSED}}
+	}
+
+	if node.broken {
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}
+	}
+
+	var status = http.StatusOK
+
+	if request.Method != "GET" {
+		body, _ := ioutil.ReadAll(request.Body)
+		node.received = append(node.received, string(body))
+
+		if configured, ok := node.statuses[string(body)]; ok {
+			status = configured
+		}
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`)),
+		Header:     make(http.Header),
+		Request:    request,
+	}, nil
+}
+
+func ExampleQueue() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		queue         = NewQueue(config, http.DefaultTransport, NewFileStore("/var/lib/payouts/raiden-queue.json"))
+		paymentClient = payments.NewClient(config, queue.HTTPClient())
+		tokenAddress  = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		targetAddress = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+		err           error
+	)
+
+	if _, err = paymentClient.InitiateWithIdentifier(context.Background(), tokenAddress, targetAddress, big.NewInt(1000), 42); IsQueued(err) {
+		fmt.Println("raiden node is down, payment will be replayed")
+	}
+
+	if _, err = queue.Replay(context.Background()); err != nil {
+		fmt.Println("unable to replay queued requests:", err.Error())
+	}
+}
+
+func TestQueue(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		node = &fakeNode{
+			down: true,
+			statuses: map[string]int{
+				`{"amount":20,"identifier":2}`: http.StatusConflict,
+				`{"amount":30,"identifier":3}`: http.StatusInternalServerError,
+			},
+		}
+		queue         = NewQueue(config, node, NewMemoryStore())
+		paymentClient = payments.NewClient(config, queue.HTTPClient())
+		tokenAddress  = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		ctx           = context.Background()
+		report        *ReplayReport
+		queued        []*Request
+		err           error
+	)
+
+	for _, identifier := range []int64{1, 2, 3, 1} {
+		_, err = paymentClient.InitiateWithIdentifier(ctx, tokenAddress, targetAddress, big.NewInt(identifier*10), identifier)
+
+		assert.True(t, IsQueued(err))
+	}
+
+	queued, err = queue.Store.List()
+	require.NoError(t, err)
+	require.Len(t, queued, 3, "payment with a duplicate identifier must only be queued once")
+
+	_, err = paymentClient.List(ctx, tokenAddress, targetAddress)
+	assert.False(t, IsQueued(err), "read requests are never queued")
+
+	_, err = queue.Replay(ctx)
+	assert.Error(t, err, "replay must not happen while the node is down")
+
+	node.down = false
+
+	report, err = queue.Replay(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`{"amount":10,"identifier":1}`, `{"amount":20,"identifier":2}`, `{"amount":30,"identifier":3}`}, node.received)
+	require.Len(t, report.Replayed, 1)
+	require.Len(t, report.Discarded, 2, "a payment failed with a 5xx may have been made and is not replayed again")
+	assert.Equal(t, `{"amount":30,"identifier":3}`, string(report.Discarded[1].Body))
+	assert.Empty(t, report.Pending)
+
+	queued, err = queue.Store.List()
+	require.NoError(t, err)
+	assert.Empty(t, queued)
+}
+
+func TestQueueReplayOrder(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		node = &fakeNode{
+			statuses: map[string]int{
+				`{"total_deposit":20}`: http.StatusServiceUnavailable,
+			},
+		}
+		queue      = NewQueue(config, node, NewMemoryStore())
+		channelURL = "http://localhost:5001/api/v1/channels/0x2a65Aca4D5fC5B5C859090a6c34d164135398226/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		paymentURL = "http://localhost:5001/api/v1/payments/0x2a65Aca4D5fC5B5C859090a6c34d164135398226/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+	)
+
+	require.NoError(t, queue.Store.Enqueue(&Request{ID: "1", Method: "PATCH", URL: channelURL, Body: []byte(`{"total_deposit":20}`)}))
+	require.NoError(t, queue.Store.Enqueue(&Request{ID: "2", Method: "POST", URL: paymentURL, Body: []byte(`{"amount":20,"identifier":1}`)}))
+
+	report, err := queue.Replay(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{`{"total_deposit":20}`}, node.received, "the payment waits for the deposit queued before it")
+	assert.Empty(t, report.Replayed)
+	require.Len(t, report.Pending, 2)
+	assert.Equal(t, "1", report.Pending[0].ID)
+	assert.Equal(t, 1, report.Pending[0].Attempts)
+
+	delete(node.statuses, `{"total_deposit":20}`)
+
+	report, err = queue.Replay(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, report.Replayed, 2)
+	assert.Equal(t, []string{`{"total_deposit":20}`, `{"total_deposit":20}`, `{"amount":20,"identifier":1}`}, node.received)
+}
+
+func TestQueueNotQueued(t *testing.T) {
+	type testcase struct {
+		name           string
+		broken         bool
+		body           string
+		expectedQueued bool
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		paymentURL = "http://localhost:5001/api/v1/payments/0x2a65Aca4D5fC5B5C859090a6c34d164135398226/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:           "unreachable",
+			body:           `{"amount":10,"identifier":1}`,
+			expectedQueued: true,
+		},
+		testcase{
+			name:           "connection broken after sending",
+			broken:         true,
+			body:           `{"amount":10,"identifier":1}`,
+			expectedQueued: false,
+		},
+		testcase{
+			name:           "payment without identifier",
+			body:           `{"amount":10}`,
+			expectedQueued: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				node   = &fakeNode{down: !tc.broken, broken: tc.broken}
+				queue  = NewQueue(config, node, NewMemoryStore())
+				queued []*Request
+			)
+
+			request, err := http.NewRequest("POST", paymentURL, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			request.Header.Set("Content-Type", "application/json")
+			request.Header.Set("Authorization", "Bearer secret")
+			request.Header.Set("Cookie", "session=secret")
+
+			_, err = queue.HTTPClient().Do(request)
+			require.Error(t, err)
+			assert.Equal(t, tc.expectedQueued, IsQueued(err))
+
+			queued, err = queue.Store.List()
+			require.NoError(t, err)
+
+			if !tc.expectedQueued {
+				assert.Empty(t, queued)
+				return
+			}
+
+			require.Len(t, queued, 1)
+			assert.Equal(t, "application/json", queued[0].Header.Get("Content-Type"))
+			assert.Empty(t, queued[0].Header.Get("Authorization"), "credentials must not be persisted")
+			assert.Empty(t, queued[0].Header.Get("Cookie"), "credentials must not be persisted")
+			assert.Equal(t, "Bearer secret", request.Header.Get("Authorization"), "the header of the request is left as is")
+		})
+	}
+}
+
+func TestDefaultIdempotencyKey(t *testing.T) {
+	var paymentURL = "http://localhost:5001/api/v1/payments/0x2a65Aca4D5fC5B5C859090a6c34d164135398226/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+
+	assert.Equal(t,
+		DefaultIdempotencyKey("POST", paymentURL, []byte(`{"amount":10,"identifier":1}`)),
+		DefaultIdempotencyKey("POST", paymentURL, []byte(`{"amount":20,"identifier":1}`)),
+	)
+
+	assert.NotEqual(t,
+		DefaultIdempotencyKey("POST", paymentURL, []byte(`{"amount":10,"identifier":1}`)),
+		DefaultIdempotencyKey("POST", paymentURL, []byte(`{"amount":10,"identifier":2}`)),
+	)
+
+	assert.NotEqual(t,
+		DefaultIdempotencyKey("PUT", "http://localhost:5001/api/v1/channels", []byte(`{"total_deposit":10}`)),
+		DefaultIdempotencyKey("PUT", "http://localhost:5001/api/v1/channels", []byte(`{"total_deposit":20}`)),
+	)
+}
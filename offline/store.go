@@ -0,0 +1,210 @@
+package offline
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/storage"
+)
+
+// Request is a mutating request that could not reach the Raiden node and has been
+// queued to be replayed later.
+type Request struct {
+	ID             string      `json:"id"`
+	IdempotencyKey string      `json:"idempotency_key"`
+	Method         string      `json:"method"`
+	URL            string      `json:"url"`
+	Header         http.Header `json:"header"`
+	Body           []byte      `json:"body"`
+	QueuedAt       time.Time   `json:"queued_at"`
+	Attempts       int         `json:"attempts"`
+}
+
+// Store is a generic interface to persist queued requests. List must return the
+// requests in the order they were queued.
+type Store interface {
+	Enqueue(request *Request) error
+	Update(request *Request) error
+	List() ([]*Request, error)
+	Remove(id string) error
+}
+
+// NewMemoryStore creates a Store that only keeps queued requests in memory, requests
+// will not survive a restart of the process.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		requests: make([]*Request, 0),
+	}
+}
+
+type memoryStore struct {
+	mutex    sync.Mutex
+	requests []*Request
+}
+
+func (store *memoryStore) Enqueue(request *Request) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.requests = append(store.requests, request)
+
+	return nil
+}
+
+func (store *memoryStore) Update(request *Request) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for i, queued := range store.requests {
+		if queued.ID == request.ID {
+			store.requests[i] = request
+		}
+	}
+
+	return nil
+}
+
+func (store *memoryStore) List() ([]*Request, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	var requests = make([]*Request, len(store.requests))
+
+	copy(requests, store.requests)
+
+	return requests, nil
+}
+
+func (store *memoryStore) Remove(id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	for i, queued := range store.requests {
+		if queued.ID == id {
+			store.requests = append(store.requests[:i], store.requests[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// NewFileStore creates a Store that persists queued requests as JSON in the file at
+// the given path, so that they are replayed even after the process restarts, through
+// a storage.NewFileStore, see NewStorageStore.
+func NewFileStore(path string) Store {
+	return NewStorageStore(storage.NewFileStore(path))
+}
+
+// storagePrefix is the prefix of the keys of the queued requests in a storage.Store.
+const storagePrefix = "offline/"
+
+// NewStorageStore creates a Store that keeps queued requests as JSON in the storage
+// shared by the subsystems of the application, under the "offline/" prefix, see
+// storage.Store. The keys of the requests start with the time they were enqueued, so
+// that listing them returns them in the order they were queued.
+func NewStorageStore(backend storage.Store) Store {
+	return &storageStore{
+		backend: backend,
+		now:     time.Now,
+	}
+}
+
+type storageStore struct {
+	mutex   sync.Mutex
+	backend storage.Store
+	now     func() time.Time
+	// sequence is the time of the last request enqueued, in nanoseconds
+	sequence int64
+}
+
+func (store *storageStore) Enqueue(request *Request) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	value, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	// requests enqueued within the resolution of the clock keep their order
+	sequence := store.now().UnixNano()
+	if sequence <= store.sequence {
+		sequence = store.sequence + 1
+	}
+
+	store.sequence = sequence
+
+	return store.backend.Put(fmt.Sprintf("%s%020d/%s", storagePrefix, sequence, request.ID), value)
+}
+
+func (store *storageStore) Update(request *Request) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	key, err := store.key(request.ID)
+	if err != nil || key == "" {
+		return err
+	}
+
+	value, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	return store.backend.Put(key, value)
+}
+
+func (store *storageStore) List() ([]*Request, error) {
+	entries, err := store.backend.List(storagePrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var requests = make([]*Request, 0, len(entries))
+
+	for _, entry := range entries {
+		var request = &Request{}
+
+		if err = json.Unmarshal(entry.Value, request); err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests, nil
+}
+
+func (store *storageStore) Remove(id string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	key, err := store.key(id)
+	if err != nil || key == "" {
+		return err
+	}
+
+	return store.backend.Delete(key)
+}
+
+// key returns the key of the queued request with the ID, or an empty string when no
+// request has it.
+func (store *storageStore) key(id string) (string, error) {
+	entries, err := store.backend.List(storagePrefix)
+	if err != nil {
+		return "", err
+	}
+
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Key, "/"+id) {
+			return entry.Key, nil
+		}
+	}
+
+	return "", nil
+}
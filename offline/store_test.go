@@ -0,0 +1,70 @@
+package offline
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStores(t *testing.T) {
+	var (
+		dir, err = ioutil.TempDir("", "offline")
+		path     string
+	)
+
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path = filepath.Join(dir, "queue.json")
+
+	stores := map[string]func() Store{
+		"memory": NewMemoryStore,
+		"file": func() Store {
+			return NewFileStore(path)
+		},
+		"storage": func() Store {
+			return NewStorageStore(storage.NewMemoryStore())
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			var (
+				store    = newStore()
+				requests []*Request
+				err      error
+			)
+
+			requests, err = store.List()
+			require.NoError(t, err)
+			assert.Empty(t, requests)
+
+			for _, id := range []string{"a", "b", "c"} {
+				require.NoError(t, store.Enqueue(&Request{ID: id, Method: "POST", QueuedAt: time.Now()}))
+			}
+
+			require.NoError(t, store.Remove("b"))
+			require.NoError(t, store.Update(&Request{ID: "c", Method: "POST", Attempts: 2}))
+
+			requests, err = store.List()
+			require.NoError(t, err)
+			require.Len(t, requests, 2)
+			assert.Equal(t, "a", requests[0].ID)
+			assert.Equal(t, "c", requests[1].ID)
+			assert.Equal(t, 2, requests[1].Attempts)
+		})
+	}
+
+	t.Run("file store survives a restart", func(t *testing.T) {
+		requests, err := NewFileStore(path).List()
+
+		require.NoError(t, err)
+		assert.Len(t, requests, 2)
+	})
+}
@@ -0,0 +1,258 @@
+package raidenclient
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+const (
+	// DefaultRetryInitialBackoff is the first backoff of a retried request when no
+	// backoff is given.
+	DefaultRetryInitialBackoff = 100 * time.Millisecond
+	// DefaultRetryMaxBackoff is the longest backoff of a retried request when no
+	// maximum is given.
+	DefaultRetryMaxBackoff = 10 * time.Second
+)
+
+// Option configures a client created with New.
+type Option func(options *clientOptions)
+
+type clientOptions struct {
+	config     *config.Config
+	httpClient *http.Client
+	resolver   *config.Resolver
+}
+
+// New will return a Raiden client like NewClient, configured with the options
+// instead of the http client alone, e.g.
+//
+//	raidenclient.New(config,
+//		raidenclient.WithRequestTimeout(30*time.Second),
+//		raidenclient.WithRetry(&raidenclient.Retry{Attempts: 3}),
+//		raidenclient.WithHeaders(map[string]string{"Authorization": "Bearer " + token}),
+//	)
+//
+// The options apply to a copy of the configuration, which is left as is, and the
+// middlewares they add wrap the Middlewares of the configuration, in the order of
+// the options, so that every sub-client gets them alike. The copy is given a Cache
+// when it has none, see Client.InvalidateToken.
+func New(config *config.Config, opts ...Option) *Client {
+	var (
+		copied  = *config
+		options = &clientOptions{config: &copied}
+	)
+
+	copied.Middlewares = append(copied.Middlewares[:0:0], config.Middlewares...)
+
+	if copied.Cache == nil {
+		copied.Cache = newCache()
+	}
+
+	for _, option := range opts {
+		option(options)
+	}
+
+	if options.resolver != nil {
+		var httpClient = http.Client{}
+
+		if options.httpClient != nil {
+			httpClient = *options.httpClient
+		}
+
+		httpClient.Transport = options.resolver.Transport()
+		options.httpClient = &httpClient
+	}
+
+	return NewClient(options.config, options.httpClient)
+}
+
+// WithHTTPClient sends the requests with the http client, http.DefaultClient when
+// not given.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(options *clientOptions) {
+		options.httpClient = httpClient
+	}
+}
+
+// WithResolver dials the node at the addresses of the Hosts of the resolver, or the
+// ones its DNS server finds, e.g. in containers where the name of the certificate of
+// the node does not resolve, see config.Resolver. The requests are sent through the
+// Transport of the resolver, in place of the transport of the http client.
+func WithResolver(resolver *config.Resolver) Option {
+	return func(options *clientOptions) {
+		options.resolver = resolver
+	}
+}
+
+// WithRequestTimeout gives the requests whose context has no deadline the timeout,
+// see config.Config.RequestTimeout.
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(options *clientOptions) {
+		options.config.RequestTimeout = timeout
+	}
+}
+
+// WithAddressFormat renders the addresses of the paths and bodies of the requests in
+// the format, see config.Config.AddressFormat.
+func WithAddressFormat(format config.AddressFormat) Option {
+	return func(options *clientOptions) {
+		options.config.AddressFormat = format
+	}
+}
+
+// WithValidation checks the responses of the node for values the API does not allow,
+// see config.Validation.
+func WithValidation(validation *config.Validation) Option {
+	return func(options *clientOptions) {
+		options.config.Validation = validation
+	}
+}
+
+// WithMiddleware wraps the sending of every request with the middlewares, e.g. to
+// log the requests or measure them, the first one being the outermost.
+func WithMiddleware(middlewares ...config.Middleware) Option {
+	return func(options *clientOptions) {
+		options.config.Middlewares = append(options.config.Middlewares, middlewares...)
+	}
+}
+
+// Observer is called with every request sent by a client, along with its response
+// or error and how long it took, e.g. to log it or to export metrics.
+type Observer func(request *http.Request, response *http.Response, err error, elapsed time.Duration)
+
+// WithObserver calls the observer with every request once it is answered. Requests
+// retried by an earlier WithRetry option are observed once, with the outcome of
+// their last attempt, and once per attempt by a later one.
+func WithObserver(observer Observer) Option {
+	return WithMiddleware(func(next config.Doer) config.Doer {
+		return func(request *http.Request) (*http.Response, error) {
+			var (
+				start         = time.Now()
+				response, err = next(request)
+			)
+
+			observer(request, response, err, time.Since(start))
+
+			return response, err
+		}
+	})
+}
+
+// WithHeaders sends the headers with every request, e.g. the credentials of an
+// authenticating proxy in front of the node, replacing the values the client sets.
+func WithHeaders(headers map[string]string) Option {
+	return WithMiddleware(func(next config.Doer) config.Doer {
+		return func(request *http.Request) (*http.Response, error) {
+			var withHeaders = request.WithContext(request.Context())
+
+			withHeaders.Header = make(http.Header, len(request.Header)+len(headers))
+			for name, values := range request.Header {
+				withHeaders.Header[name] = values
+			}
+
+			for name, value := range headers {
+				withHeaders.Header.Set(name, value)
+			}
+
+			return next(withHeaders)
+		}
+	})
+}
+
+// Retry configures the retries of the requests failing to reach the node or
+// answered with a server error. A request is sent up to Attempts times, after a
+// backoff growing exponentially from InitialBackoff up to MaxBackoff, jittered,
+// DefaultRetryInitialBackoff and DefaultRetryMaxBackoff when zero, or the
+// Retry-After of the response, e.g. of a 503, in place of the backoff. Payments, and
+// every other POST, are never retried since the node may have made them before
+// failing: see idempotency.NewPayer to retry payments safely. Requests are sent once
+// when Attempts is below two. All the attempts fit within the request timeout, the
+// last response being returned when the wait would not, the backoffs being waited
+// for on Clock, the system time when nil.
+type Retry struct {
+	Attempts       int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Clock          clock.Clock
+}
+
+// WithRetry retries the requests as configured.
+func WithRetry(retry *Retry) Option {
+	return WithMiddleware(retry.middleware)
+}
+
+func (retry *Retry) middleware(next config.Doer) config.Doer {
+	return func(request *http.Request) (*http.Response, error) {
+		var (
+			initial = retry.InitialBackoff
+			max     = retry.MaxBackoff
+			pace    = clock.Or(retry.Clock)
+		)
+
+		if request.Method == "POST" || retry.Attempts <= 1 {
+			return next(request)
+		}
+
+		if initial <= 0 {
+			initial = DefaultRetryInitialBackoff
+		}
+
+		if max <= 0 {
+			max = DefaultRetryMaxBackoff
+		}
+
+		var (
+			feed    = util.Backoff(initial, max, pace)
+			backoff = feed.Next(true)
+			attempt = request
+		)
+
+		for attempts := 1; ; attempts++ {
+			response, err := next(attempt)
+			if !retryable(response, err) || request.Context().Err() != nil || attempts >= retry.Attempts {
+				return response, err
+			}
+
+			if request.Body != nil && request.GetBody == nil {
+				return response, err
+			}
+
+			wait := backoff
+			if response != nil {
+				if retryAfter, ok := util.RetryAfter(response.Header, pace.Now()); ok {
+					wait = retryAfter
+				}
+			}
+
+			if deadline, ok := request.Context().Deadline(); ok && pace.Now().Add(wait).After(deadline) {
+				return response, err
+			}
+
+			if response != nil {
+				response.Body.Close()
+			}
+
+			select {
+			case <-request.Context().Done():
+				return nil, request.Context().Err()
+			case <-pace.After(wait):
+			}
+
+			if attempt, err = util.Resend(request); err != nil {
+				return nil, err
+			}
+
+			backoff = feed.Next(false)
+		}
+	}
+}
+
+// retryable tells whether the request failed to reach the node or was answered with
+// a server error.
+func retryable(response *http.Response, err error) bool {
+	return err != nil || response.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,258 @@
+package raidenclient
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNew() {
+	var (
+		raidenConfig = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		raidenClient = New(raidenConfig,
+			WithRequestTimeout(30*time.Second),
+			WithRetry(&Retry{Attempts: 3}),
+			WithHeaders(map[string]string{"Authorization": "Bearer secret"}),
+			WithObserver(func(request *http.Request, response *http.Response, err error, elapsed time.Duration) {
+				log.Println(request.Method, request.URL.Path, elapsed)
+			}),
+		)
+	)
+
+	channels, err := raidenClient.Channels().ListAll(context.Background())
+	if err != nil {
+		log.Println("unable to list channels:", err.Error())
+	}
+
+	fmt.Println("channels:", len(channels))
+}
+
+func TestNew(t *testing.T) {
+	var (
+		mutex    sync.Mutex
+		requests = make(map[string]int)
+		headers  = make(chan http.Header, 10)
+		node     = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+
+			mutex.Lock()
+			requests[request.Method+" "+request.URL.Path]++
+			attempt := requests[request.Method+" "+request.URL.Path]
+			mutex.Unlock()
+
+			headers <- request.Header
+
+			switch {
+			case request.URL.Path == "/api/v1/address" && attempt < 3:
+				writer.WriteHeader(http.StatusBadGateway)
+			case request.URL.Path == "/api/v1/address":
+				writer.Write([]byte(`{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+			case request.Method == "PATCH" && attempt < 2:
+				writer.WriteHeader(http.StatusInternalServerError)
+			case request.Method == "PATCH":
+				assert.JSONEq(t, `{"total_deposit":100}`, string(body), "the body is sent again")
+				writer.Write([]byte(`{"token_address":"0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359","partner_address":"0x1f7402f55e142820ea3812106d0657103fc1709e","total_deposit":100,"state":"opened"}`))
+			default:
+				writer.WriteHeader(http.StatusInternalServerError)
+			}
+		}))
+		count = func(call string) int {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			return requests[call]
+		}
+		raidenConfig   = &config.Config{Host: node.URL, APIVersion: "v1"}
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359")
+		partnerAddress = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+		observed       = make([]int, 0)
+	)
+	defer node.Close()
+
+	raidenClient := New(raidenConfig,
+		WithObserver(func(request *http.Request, response *http.Response, err error, elapsed time.Duration) {
+			require.NoError(t, err)
+			observed = append(observed, response.StatusCode)
+		}),
+		WithRetry(&Retry{Attempts: 3, Clock: newInstantClock()}),
+		WithHeaders(map[string]string{"Authorization": "Bearer secret"}),
+		WithRequestTimeout(time.Minute),
+	)
+
+	assert.Empty(t, raidenConfig.Middlewares, "the configuration is left as is")
+	assert.Zero(t, raidenConfig.RequestTimeout)
+
+	t.Run("retried until answered", func(t *testing.T) {
+		address, err := raidenClient.Address().Get(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"), address)
+		assert.Equal(t, 3, count("GET /api/v1/address"))
+		assert.Equal(t, []int{http.StatusOK}, observed, "the retries are within the observer")
+
+		for i := 0; i < 3; i++ {
+			assert.Equal(t, "Bearer secret", (<-headers).Get("Authorization"))
+		}
+	})
+
+	t.Run("body sent again", func(t *testing.T) {
+		_, err := raidenClient.Channels().IncreaseDeposit(context.Background(), tokenAddress, partnerAddress, big.NewInt(100))
+		require.NoError(t, err)
+		assert.Equal(t, 2, count("PATCH /api/v1/channels/"+tokenAddress.Hex()+"/"+partnerAddress.Hex()))
+
+		for i := 0; i < 2; i++ {
+			<-headers
+		}
+	})
+
+	t.Run("payments not retried", func(t *testing.T) {
+		_, err := raidenClient.Payments().Initiate(context.Background(), tokenAddress, partnerAddress, big.NewInt(10))
+		assert.Error(t, err)
+		assert.Equal(t, 1, count("POST /api/v1/payments/"+tokenAddress.Hex()+"/"+partnerAddress.Hex()))
+	})
+}
+
+// instantClock is a clock whose waits are over at once.
+type instantClock struct{}
+
+func newInstantClock() clock.Clock {
+	return instantClock{}
+}
+
+func (instantClock) Now() time.Time {
+	return time.Now()
+}
+
+func (instantClock) After(d time.Duration) <-chan time.Time {
+	var after = make(chan time.Time, 1)
+
+	after <- time.Now()
+
+	return after
+}
+
+func TestWithResolver(t *testing.T) {
+	var (
+		hosts = make(chan string, 1)
+		node  = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			hosts <- request.Host
+			writer.Write([]byte(`{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+		}))
+	)
+	defer node.Close()
+
+	nodeURL, err := url.Parse(node.URL)
+	require.NoError(t, err)
+
+	raidenClient := New(&config.Config{Host: "http://raiden.example.com:" + nodeURL.Port(), APIVersion: "v1"},
+		WithHTTPClient(&http.Client{Timeout: time.Minute}),
+		WithResolver(&config.Resolver{Hosts: map[string]string{"raiden.example.com": "127.0.0.1"}}),
+	)
+
+	_, err = raidenClient.Address().Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "raiden.example.com:"+nodeURL.Port(), <-hosts)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	var (
+		attempts int
+		node     = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			attempts++
+			writer.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		raidenClient = New(&config.Config{Host: node.URL, APIVersion: "v1"}, WithRetry(&Retry{Attempts: 4, Clock: newInstantClock()}))
+	)
+	defer node.Close()
+
+	_, err := raidenClient.Address().Get(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 4, attempts)
+}
+
+func TestRetryAfter(t *testing.T) {
+	var (
+		attempts   int
+		retryAfter string
+		node       = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if attempts++; attempts == 1 {
+				writer.Header().Set("Retry-After", retryAfter)
+				writer.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			writer.Write([]byte(`{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+		}))
+		waits        = &waitRecorder{}
+		raidenClient = New(&config.Config{Host: node.URL, APIVersion: "v1"}, WithRetry(&Retry{Attempts: 3, InitialBackoff: time.Millisecond, Clock: waits}), WithRequestTimeout(time.Minute))
+	)
+	defer node.Close()
+
+	type testcase struct {
+		name             string
+		retryAfter       string
+		expectedAttempts int
+		expectedWaits    []time.Duration
+		expectedError    bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:             "retry after in place of the backoff",
+			retryAfter:       "7",
+			expectedAttempts: 2,
+			expectedWaits:    []time.Duration{7 * time.Second},
+		},
+		testcase{
+			name:             "retry after beyond the request timeout",
+			retryAfter:       "120",
+			expectedAttempts: 1,
+			expectedError:    true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts, retryAfter, waits.waits = 0, tc.retryAfter, nil
+
+			_, err := raidenClient.Address().Get(context.Background())
+			assert.Equal(t, tc.expectedError, err != nil, err)
+			assert.Equal(t, tc.expectedAttempts, attempts)
+			assert.Equal(t, tc.expectedWaits, waits.waits)
+		})
+	}
+}
+
+// waitRecorder is a clock recording the waits instead of waiting for them.
+type waitRecorder struct {
+	waits []time.Duration
+}
+
+func (waits *waitRecorder) Now() time.Time {
+	return time.Now()
+}
+
+func (waits *waitRecorder) After(d time.Duration) <-chan time.Time {
+	var after = make(chan time.Time, 1)
+
+	waits.waits = append(waits.waits, d)
+	after <- time.Now()
+
+	return after
+}
@@ -0,0 +1,249 @@
+// Package outbox sends the payment commands an application writes to an outbox, a
+// table or queue of its own written in the same transaction as the business change
+// the payment is for, exactly once, and writes back the outcome of every command.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/idempotency"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultBatchSize is the number of pending commands read from the outbox at once
+	// when the consumer has no batch size.
+	DefaultBatchSize = 100
+	// identifierNamespace is the namespace the payment identifiers of the commands
+	// are derived in from their ID, see idempotency.Generator.
+	identifierNamespace = "outbox"
+	// storagePrefix is the prefix of the keys of the outcomes in a storage.Store.
+	storagePrefix = "outbox/"
+)
+
+// RetentionPolicy returns the retention of the outcomes a consumer keeps in its
+// storage.Store, the outcomes completed more than maxAge ago being deleted, see
+// storage.Collector. A command whose outcome was deleted is sent again if the Source
+// still returns it as pending, so maxAge must outlast the completion of the commands
+// in the Source.
+func RetentionPolicy(maxAge time.Duration) *storage.Policy {
+	return &storage.Policy{
+		Prefix: storagePrefix,
+		MaxAge: maxAge,
+		Time: func(entry *storage.Entry) (time.Time, bool) {
+			var outcome = &Outcome{}
+
+			if err := json.Unmarshal(entry.Value, outcome); err != nil {
+				return time.Time{}, false
+			}
+
+			return outcome.Completed, true
+		},
+	}
+}
+
+// Command is a payment the application asked for, identified by an ID unique within
+// the outbox, e.g. the primary key of its row.
+type Command struct {
+	ID            string
+	TokenAddress  common.Address
+	TargetAddress common.Address
+	Amount        *big.Int
+}
+
+// Outcome is what became of a command: the payment sent for it, or the error the node
+// refused it with, which sending it again would not change.
+type Outcome struct {
+	CommandID  string             `json:"command_id"`
+	Identifier int64              `json:"identifier"`
+	Status     idempotency.Status `json:"status"`
+	Payment    *payments.Payment  `json:"payment,omitempty"`
+	Error      string             `json:"error,omitempty"`
+	Completed  time.Time          `json:"completed"`
+}
+
+// Source is the outbox of the application. Pending returns up to limit commands that
+// were not completed yet, oldest first, and Complete marks the command of the outcome
+// as done, e.g. by writing the outcome to its row. Complete may be called more than
+// once for a command and must then do nothing more.
+type Source interface {
+	Pending(ctx context.Context, limit int) ([]*Command, error)
+	Complete(ctx context.Context, outcome *Outcome) error
+}
+
+// Consumer sends the pending commands of the Source through an idempotent payer, the
+// identifier of every payment being derived from the ID of its command. The outcome
+// of a command is written to the Store in a single Put before the Source is told, so
+// that a command whose outcome is stored is never sent again, even when the process
+// crashed before completing it in the Source. The passes of Run and the completion
+// times of the outcomes are timed by Clock, the system time when nil.
+type Consumer struct {
+	Source    Source
+	Store     storage.Store
+	Payer     idempotency.Payer
+	Generator *idempotency.Generator
+	BatchSize int
+	Clock     clock.Clock
+}
+
+// NewConsumer creates a consumer of the outbox given a Raiden node configuration, an
+// http client and the storage the outcomes and payment identifiers are kept in.
+func NewConsumer(config *config.Config, httpClient *http.Client, source Source, backend storage.Store) *Consumer {
+	var records = idempotency.NewStorageStore(backend)
+
+	return &Consumer{
+		Source:    source,
+		Store:     backend,
+		Payer:     idempotency.NewPayer(config, httpClient, records),
+		Generator: idempotency.NewGenerator(identifierNamespace, records),
+		BatchSize: DefaultBatchSize,
+	}
+}
+
+// Process will send a batch of pending commands in order, returning the outcomes
+// written. It stops at the first command that could not be sent for a reason that
+// may go away, e.g. an unreachable node, returning the outcomes written before along
+// with the error, so that the command and the ones after it are sent on the next
+// pass. Commands whose outcome is already stored are only completed again.
+func (consumer *Consumer) Process(ctx context.Context) ([]*Outcome, error) {
+	var (
+		err       error
+		commands  []*Command
+		outcomes  = make([]*Outcome, 0)
+		batchSize = consumer.BatchSize
+	)
+
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	if commands, err = consumer.Source.Pending(ctx, batchSize); err != nil {
+		return outcomes, err
+	}
+
+	for _, command := range commands {
+		var outcome *Outcome
+
+		if outcome, err = consumer.process(ctx, command); err != nil {
+			return outcomes, err
+		}
+
+		if err = consumer.Source.Complete(ctx, outcome); err != nil {
+			return outcomes, err
+		}
+
+		outcomes = append(outcomes, outcome)
+	}
+
+	return outcomes, nil
+}
+
+// Run will process the outbox every interval until ctx is done. The errors of the
+// passes are returned on the returned channel, which is closed once the consumer
+// stopped. Errors are dropped when nobody is receiving them.
+func (consumer *Consumer) Run(ctx context.Context, interval time.Duration) <-chan error {
+	var errs = make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		for {
+			if _, err := consumer.Process(ctx); err != nil && ctx.Err() == nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-clock.Or(consumer.Clock).After(interval):
+			}
+		}
+	}()
+
+	return errs
+}
+
+// Outcome returns the stored outcome of the command with the ID, or
+// storage.ErrNotFound when it was not processed yet.
+func (consumer *Consumer) Outcome(commandID string) (*Outcome, error) {
+	var outcome = &Outcome{}
+
+	value, err := consumer.Store.Get(storagePrefix + commandID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(value, outcome); err != nil {
+		return nil, err
+	}
+
+	return outcome, nil
+}
+
+// process returns the stored outcome of the command, or sends it and stores its
+// outcome.
+func (consumer *Consumer) process(ctx context.Context, command *Command) (*Outcome, error) {
+	var (
+		err     error
+		value   []byte
+		payment *payments.Payment
+		outcome *Outcome
+	)
+
+	if outcome, err = consumer.Outcome(command.ID); err != storage.ErrNotFound {
+		return outcome, err
+	}
+
+	outcome = &Outcome{CommandID: command.ID}
+
+	if outcome.Identifier, err = consumer.Generator.Identifier(command.ID); err == nil {
+		payment, err = consumer.Payer.SendIdempotent(ctx, command.TokenAddress, command.TargetAddress, command.Amount, outcome.Identifier)
+	}
+
+	switch {
+	case err == nil:
+		outcome.Status, outcome.Payment = idempotency.Succeeded, payment
+	case refused(err):
+		outcome.Status, outcome.Error = idempotency.Failed, err.Error()
+	default:
+		return nil, err
+	}
+
+	outcome.Completed = clock.Or(consumer.Clock).Now()
+
+	if value, err = json.Marshal(outcome); err != nil {
+		return nil, err
+	}
+
+	if err = consumer.Store.Put(storagePrefix+command.ID, value); err != nil {
+		return nil, err
+	}
+
+	return outcome, nil
+}
+
+// refused tells whether the error is a refusal of the payment that sending it again
+// would not change: a command reusing the identifier of a different payment or whose
+// ID hashes to the identifier of another command, or a client error of the node
+// other than a conflict with a payment still in flight.
+func refused(err error) bool {
+	if err == idempotency.ErrIdentifierConflict || err == idempotency.ErrIdentifierCollision {
+		return true
+	}
+
+	statusCode := raidenerrors.StatusCode(err)
+
+	return statusCode >= http.StatusBadRequest && statusCode < http.StatusInternalServerError && statusCode != http.StatusConflict && !raidenerrors.IsRetryable(err)
+}
@@ -0,0 +1,227 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/idempotency"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySource is an outbox in memory, for tests.
+type memorySource struct {
+	commands  []*Command
+	completed map[string]*Outcome
+}
+
+func (source *memorySource) Pending(ctx context.Context, limit int) ([]*Command, error) {
+	var pending = make([]*Command, 0)
+
+	for _, command := range source.commands {
+		if _, ok := source.completed[command.ID]; !ok && len(pending) < limit {
+			pending = append(pending, command)
+		}
+	}
+
+	return pending, nil
+}
+
+func (source *memorySource) Complete(ctx context.Context, outcome *Outcome) error {
+	source.completed[outcome.CommandID] = outcome
+	return nil
+}
+
+func ExampleConsumer() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		// the outbox table of the application, written along with the orders
+		source   Source
+		consumer = NewConsumer(config, http.DefaultClient, source, storage.NewFileStore("/var/lib/payouts/outbox.json"))
+	)
+
+	for err := range consumer.Run(context.Background(), 10*time.Second) {
+		fmt.Println("outbox pass failed:", err.Error())
+	}
+}
+
+func TestConsumerProcess(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		paid         = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		refused      = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		paidURL      = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		refusedURL   = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x2a65Aca4D5fC5B5C859090a6c34d164135398226"
+		commands     = []*Command{
+			&Command{ID: "order-1", TokenAddress: tokenAddress, TargetAddress: paid, Amount: big.NewInt(10)},
+			&Command{ID: "order-2", TokenAddress: tokenAddress, TargetAddress: refused, Amount: big.NewInt(20)},
+		}
+	)
+
+	type testcase struct {
+		name              string
+		prepStore         func(store storage.Store)
+		refusedStatus     int
+		expectedStatuses  map[string]idempotency.Status
+		expectedCompleted []string
+		expectedPosts     map[string]int
+		expectedError     bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:              "commands sent or refused",
+			refusedStatus:     http.StatusPaymentRequired,
+			expectedStatuses:  map[string]idempotency.Status{"order-1": idempotency.Succeeded, "order-2": idempotency.Failed},
+			expectedCompleted: []string{"order-1", "order-2"},
+			expectedPosts:     map[string]int{paidURL: 1, refusedURL: 1},
+		},
+		testcase{
+			name: "outcome stored before a crash",
+			prepStore: func(store storage.Store) {
+				value, _ := json.Marshal(&Outcome{CommandID: "order-1", Status: idempotency.Succeeded})
+				store.Put("outbox/order-1", value)
+			},
+			refusedStatus:     http.StatusPaymentRequired,
+			expectedStatuses:  map[string]idempotency.Status{"order-1": idempotency.Succeeded, "order-2": idempotency.Failed},
+			expectedCompleted: []string{"order-1", "order-2"},
+			expectedPosts:     map[string]int{paidURL: 0, refusedURL: 1},
+		},
+		testcase{
+			name:              "unavailable node",
+			refusedStatus:     http.StatusServiceUnavailable,
+			expectedStatuses:  map[string]idempotency.Status{"order-1": idempotency.Succeeded},
+			expectedCompleted: []string{"order-1"},
+			expectedPosts:     map[string]int{paidURL: 1, refusedURL: 1},
+			expectedError:     true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				store    = storage.NewMemoryStore()
+				source   = &memorySource{commands: commands, completed: make(map[string]*Outcome)}
+				consumer = NewConsumer(config, http.DefaultClient, source, store)
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("POST", paidURL, httpmock.NewStringResponder(http.StatusOK, `{"initiator_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","amount":10}`))
+			httpmock.RegisterResponder("POST", refusedURL, httpmock.NewStringResponder(tc.refusedStatus, `{"errors":"Payment failed"}`))
+
+			if tc.prepStore != nil {
+				tc.prepStore(store)
+			}
+
+			outcomes, err := consumer.Process(context.Background())
+			if tc.expectedError {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+
+			statuses := make(map[string]idempotency.Status)
+			for _, outcome := range outcomes {
+				statuses[outcome.CommandID] = outcome.Status
+			}
+
+			assert.Equal(t, tc.expectedStatuses, statuses)
+
+			completed := make([]string, 0)
+			for _, command := range commands {
+				if _, ok := source.completed[command.ID]; ok {
+					completed = append(completed, command.ID)
+				}
+			}
+
+			assert.Equal(t, tc.expectedCompleted, completed)
+
+			calls := httpmock.GetCallCountInfo()
+			for url, posts := range tc.expectedPosts {
+				assert.Equal(t, posts, calls["POST "+url], url)
+			}
+		})
+	}
+}
+
+func TestConsumerExactlyOnce(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		paymentURL = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		store      = storage.NewMemoryStore()
+		command    = &Command{
+			ID:            "order-1",
+			TokenAddress:  common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+			TargetAddress: common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+			Amount:        big.NewInt(10),
+		}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", paymentURL, httpmock.NewStringResponder(http.StatusOK, `{"target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","amount":10}`))
+
+	// the source losing the completion of the command, e.g. its transaction rolled
+	// back, makes the command pending again
+	for i := 0; i < 3; i++ {
+		source := &memorySource{commands: []*Command{command}, completed: make(map[string]*Outcome)}
+
+		outcomes, err := NewConsumer(config, http.DefaultClient, source, store).Process(context.Background())
+		require.NoError(t, err)
+		require.Len(t, outcomes, 1)
+		assert.Equal(t, idempotency.Derive(identifierNamespace, "order-1"), outcomes[0].Identifier)
+	}
+
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST "+paymentURL])
+}
+
+func TestRetentionPolicy(t *testing.T) {
+	var (
+		now       = time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC)
+		backend   = storage.NewMemoryStore()
+		collector = storage.NewCollector(backend, RetentionPolicy(24*time.Hour))
+	)
+
+	collector.Clock = clock.NewManual(now)
+
+	for id, completed := range map[string]time.Time{"old": now.Add(-48 * time.Hour), "new": now.Add(-time.Hour)} {
+		value, err := json.Marshal(&Outcome{CommandID: id, Status: idempotency.Succeeded, Completed: completed})
+		require.NoError(t, err)
+		require.NoError(t, backend.Put(storagePrefix+id, value))
+	}
+
+	_, err := collector.Collect(context.Background())
+	require.NoError(t, err)
+
+	consumer := &Consumer{Store: backend}
+
+	_, err = consumer.Outcome("old")
+	assert.Equal(t, storage.ErrNotFound, err)
+
+	outcome, err := consumer.Outcome("new")
+	require.NoError(t, err)
+	assert.Equal(t, "new", outcome.CommandID)
+}
@@ -0,0 +1,257 @@
+package payments
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// EventPaymentSentSuccess is the name of the event for a payment that has been
+	// successfully sent by the Raiden node.
+	EventPaymentSentSuccess = "EventPaymentSentSuccess"
+	// EventPaymentSentFailed is the name of the event for a payment the Raiden node
+	// was unable to send.
+	EventPaymentSentFailed = "EventPaymentSentFailed"
+	// EventPaymentReceivedSuccess is the name of the event for a payment that has
+	// been received by the Raiden node.
+	EventPaymentReceivedSuccess = "EventPaymentReceivedSuccess"
+)
+
+// Classification describes how a payment event relates to the payments the caller
+// knows about, so that received payments can be reconciled.
+type Classification string
+
+const (
+	// Outgoing is any event for a payment sent by the Raiden node.
+	Outgoing Classification = "outgoing"
+	// Expected is a received payment that matches a known invoice.
+	Expected Classification = "expected"
+	// Refund is a received payment whose identifier matches an outgoing payment
+	// that previously failed.
+	Refund Classification = "refund"
+	// Unexpected is a received payment that does not match any known invoice.
+	Unexpected Classification = "unexpected"
+)
+
+// ClassifiedEvent is a payment event together with its classification.
+type ClassifiedEvent struct {
+	*Event
+	Classification Classification
+}
+
+// InvoiceBook is a generic interface to look up whether a received payment was
+// expected by the caller.
+type InvoiceBook interface {
+	Expected(event *Event) bool
+}
+
+// Invoice is a payment the caller expects to receive. A zero Initiator matches a
+// payment from any address.
+type Invoice struct {
+	Identifier int64
+	Amount     *big.Int
+	Initiator  common.Address
+}
+
+// Invoices is an in-memory InvoiceBook keyed by payment identifier.
+type Invoices struct {
+	mutex    sync.RWMutex
+	invoices map[int64]*Invoice
+}
+
+var _ InvoiceBook = &Invoices{}
+
+// NewInvoices creates an empty in-memory InvoiceBook.
+func NewInvoices() *Invoices {
+	return &Invoices{
+		invoices: make(map[int64]*Invoice),
+	}
+}
+
+// Add registers an invoice that received payments will be matched against.
+func (invoices *Invoices) Add(invoice *Invoice) {
+	invoices.mutex.Lock()
+	defer invoices.mutex.Unlock()
+
+	invoices.invoices[invoice.Identifier] = invoice
+}
+
+// Remove will forget the invoice with the given payment identifier.
+func (invoices *Invoices) Remove(identifier int64) {
+	invoices.mutex.Lock()
+	defer invoices.mutex.Unlock()
+
+	delete(invoices.invoices, identifier)
+}
+
+// Expected reports whether the event matches the identifier, amount and initiator
+// of a registered invoice.
+func (invoices *Invoices) Expected(event *Event) bool {
+	invoices.mutex.RLock()
+	defer invoices.mutex.RUnlock()
+
+	invoice, ok := invoices.invoices[event.Identifier]
+	if !ok {
+		return false
+	}
+
+	if event.Amount == nil || !amounts.Equal(event.Amount, invoice.Amount) {
+		return false
+	}
+
+	return invoice.Initiator == common.Address{} || invoice.Initiator == event.Initiator
+}
+
+const (
+	// MaxFailedPayments is the number of failed outgoing payments a Classifier
+	// remembers, the oldest ones being forgotten first.
+	MaxFailedPayments = 10000
+	// RefundWindow is how long after a failed outgoing payment was logged a received
+	// payment with its identifier is still classified as a refund.
+	RefundWindow = 24 * time.Hour
+)
+
+// Classifier flags received payments that refund a previously failed outgoing
+// payment, or that do not correspond to any known invoice. Events must be classified
+// in the order the Raiden node logged them, since failed outgoing payments are
+// remembered as they are seen, up to MaxFailedPayments of them for RefundWindow.
+type Classifier struct {
+	mutex    sync.Mutex
+	invoices InvoiceBook
+	failed   map[int64]*failedPayment
+	order    []*failedPayment
+}
+
+// failedPayment is a failed outgoing payment a refund may be received for.
+type failedPayment struct {
+	identifier int64
+	target     common.Address
+	logTime    time.Time
+}
+
+// NewClassifier creates a new Classifier that matches received payments against the
+// given InvoiceBook. A nil InvoiceBook treats every received payment that is not a
+// refund as unexpected.
+func NewClassifier(invoices InvoiceBook) *Classifier {
+	return &Classifier{
+		invoices: invoices,
+		failed:   make(map[int64]*failedPayment),
+	}
+}
+
+// Classify will return the classification of a single payment event.
+func (classifier *Classifier) Classify(event *Event) Classification {
+	classifier.mutex.Lock()
+	defer classifier.mutex.Unlock()
+
+	classifier.expire(event.LogTime)
+
+	switch event.EventName {
+	case EventPaymentReceivedSuccess:
+	case EventPaymentSentFailed:
+		classifier.remember(event)
+		return Outgoing
+	default:
+		return Outgoing
+	}
+
+	if failed, ok := classifier.failed[event.Identifier]; ok && (failed.target == common.Address{} || failed.target == event.Initiator) {
+		return Refund
+	}
+
+	if classifier.invoices != nil && classifier.invoices.Expected(event) {
+		return Expected
+	}
+
+	return Unexpected
+}
+
+// remember records the failed outgoing payment of the event, forgetting the oldest
+// one beyond MaxFailedPayments.
+func (classifier *Classifier) remember(event *Event) {
+	var failed = &failedPayment{identifier: event.Identifier, target: event.Target, logTime: event.LogTime}
+
+	classifier.failed[event.Identifier] = failed
+	classifier.order = append(classifier.order, failed)
+
+	for len(classifier.order) > MaxFailedPayments {
+		classifier.forget()
+	}
+}
+
+// expire forgets the failed outgoing payments logged more than RefundWindow before
+// now, the log time of the event being classified. Events without a log time do
+// not expire anything, failed payments without one expire with the first event
+// that has one.
+func (classifier *Classifier) expire(now time.Time) {
+	if now.IsZero() {
+		return
+	}
+
+	for len(classifier.order) > 0 && now.Sub(classifier.order[0].logTime) > RefundWindow {
+		classifier.forget()
+	}
+}
+
+// forget removes the oldest failed outgoing payment, unless the identifier failed
+// again since.
+func (classifier *Classifier) forget() {
+	var oldest = classifier.order[0]
+
+	classifier.order[0] = nil
+	classifier.order = classifier.order[1:]
+
+	if classifier.failed[oldest.identifier] == oldest {
+		delete(classifier.failed, oldest.identifier)
+	}
+}
+
+// ClassifyAll will classify a list of payment events, such as the result of listing
+// the payments of a token.
+func (classifier *Classifier) ClassifyAll(events []*Event) []*ClassifiedEvent {
+	var classified = make([]*ClassifiedEvent, 0, len(events))
+
+	for _, event := range events {
+		classified = append(classified, &ClassifiedEvent{
+			Event:          event,
+			Classification: classifier.Classify(event),
+		})
+	}
+
+	return classified
+}
+
+// Stream will classify every event received on the given channel until it is closed
+// or the context is done, allowing the classifier to sit behind a stream of payment
+// events. The returned channel is closed once classification stops.
+func (classifier *Classifier) Stream(ctx context.Context, events <-chan *Event) <-chan *ClassifiedEvent {
+	var classified = make(chan *ClassifiedEvent)
+
+	go func() {
+		defer close(classified)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+
+				select {
+				case classified <- &ClassifiedEvent{Event: event, Classification: classifier.Classify(event)}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return classified
+}
@@ -0,0 +1,171 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleClassifier() {
+	var (
+		invoices   = NewInvoices()
+		classifier = NewClassifier(invoices)
+		customer   = common.HexToAddress("0x82641569b2062B545431cF6D7F0A418582865ba7")
+	)
+
+	invoices.Add(&Invoice{Identifier: 1, Amount: big.NewInt(5), Initiator: customer})
+
+	classified := classifier.Classify(&Event{
+		EventName:  EventPaymentReceivedSuccess,
+		Amount:     big.NewInt(5),
+		Initiator:  customer,
+		Identifier: 1,
+	})
+
+	fmt.Println(classified)
+	// Output: expected
+}
+
+func TestClassifier(t *testing.T) {
+	var (
+		// tokens returns the amount of tokens with 18 decimals
+		tokens = func(n int64) *big.Int {
+			return new(big.Int).Mul(big.NewInt(n), new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil))
+		}
+		customer = common.HexToAddress("0x82641569b2062B545431cF6D7F0A418582865ba7")
+		stranger = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	type testcase struct {
+		name                    string
+		invoices                []*Invoice
+		events                  []*Event
+		expectedClassifications []Classification
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "received payment matching an invoice",
+			invoices: []*Invoice{
+				&Invoice{Identifier: 1, Amount: big.NewInt(5), Initiator: customer},
+			},
+			events: []*Event{
+				&Event{EventName: EventPaymentReceivedSuccess, Amount: big.NewInt(5), Initiator: customer, Identifier: 1},
+			},
+			expectedClassifications: []Classification{Expected},
+		},
+		testcase{
+			name: "received payment matching an invoice beyond an int64",
+			invoices: []*Invoice{
+				&Invoice{Identifier: 1, Amount: tokens(20), Initiator: customer},
+			},
+			events: []*Event{
+				&Event{EventName: EventPaymentReceivedSuccess, Amount: tokens(20), Initiator: customer, Identifier: 1},
+				&Event{EventName: EventPaymentReceivedSuccess, Amount: tokens(19), Initiator: customer, Identifier: 1},
+			},
+			expectedClassifications: []Classification{Expected, Unexpected},
+		},
+		testcase{
+			name: "received payment with the wrong amount or initiator",
+			invoices: []*Invoice{
+				&Invoice{Identifier: 1, Amount: big.NewInt(5), Initiator: customer},
+			},
+			events: []*Event{
+				&Event{EventName: EventPaymentReceivedSuccess, Amount: big.NewInt(4), Initiator: customer, Identifier: 1},
+				&Event{EventName: EventPaymentReceivedSuccess, Amount: big.NewInt(5), Initiator: stranger, Identifier: 1},
+				&Event{EventName: EventPaymentReceivedSuccess, Amount: big.NewInt(5), Initiator: customer, Identifier: 2},
+			},
+			expectedClassifications: []Classification{Unexpected, Unexpected, Unexpected},
+		},
+		testcase{
+			name: "received payment refunding a failed outgoing payment",
+			events: []*Event{
+				&Event{EventName: EventPaymentSentFailed, Amount: big.NewInt(35), Target: stranger, Identifier: 7},
+				&Event{EventName: EventPaymentReceivedSuccess, Amount: big.NewInt(35), Initiator: stranger, Identifier: 7},
+				&Event{EventName: EventPaymentReceivedSuccess, Amount: big.NewInt(35), Initiator: customer, Identifier: 7},
+			},
+			expectedClassifications: []Classification{Outgoing, Refund, Unexpected},
+		},
+		testcase{
+			name: "sent payments are outgoing",
+			events: []*Event{
+				&Event{EventName: EventPaymentSentSuccess, Amount: big.NewInt(35), Target: stranger, Identifier: 2},
+			},
+			expectedClassifications: []Classification{Outgoing},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				invoices   = NewInvoices()
+				classifier = NewClassifier(invoices)
+				classified []*ClassifiedEvent
+			)
+
+			for _, invoice := range tc.invoices {
+				invoices.Add(invoice)
+			}
+
+			classified = classifier.ClassifyAll(tc.events)
+
+			for i, event := range classified {
+				assert.Equal(t, tc.events[i], event.Event)
+				assert.Equal(t, tc.expectedClassifications[i], event.Classification)
+			}
+		})
+	}
+}
+
+func TestClassifierStream(t *testing.T) {
+	var (
+		classifier = NewClassifier(nil)
+		events     = make(chan *Event, 2)
+		ctx        = context.Background()
+		classified []Classification
+	)
+
+	events <- &Event{EventName: EventPaymentSentSuccess, Identifier: 1}
+	events <- &Event{EventName: EventPaymentReceivedSuccess, Identifier: 2}
+	close(events)
+
+	for event := range classifier.Stream(ctx, events) {
+		classified = append(classified, event.Classification)
+	}
+
+	assert.Equal(t, []Classification{Outgoing, Unexpected}, classified)
+}
+
+func TestClassifierForgets(t *testing.T) {
+	var (
+		classifier = NewClassifier(nil)
+		stranger   = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		logTime    = time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC)
+	)
+
+	for identifier := int64(1); identifier <= MaxFailedPayments+1; identifier++ {
+		classifier.Classify(&Event{EventName: EventPaymentSentFailed, Target: stranger, Identifier: identifier, LogTime: logTime})
+	}
+
+	assert.Len(t, classifier.failed, MaxFailedPayments)
+	assert.Len(t, classifier.order, MaxFailedPayments)
+
+	// the oldest failed payment was forgotten, the others are still refunded
+	assert.Equal(t, Unexpected, classifier.Classify(&Event{EventName: EventPaymentReceivedSuccess, Initiator: stranger, Identifier: 1, LogTime: logTime}))
+	assert.Equal(t, Refund, classifier.Classify(&Event{EventName: EventPaymentReceivedSuccess, Initiator: stranger, Identifier: 2, LogTime: logTime}))
+
+	// identifier 3 failed again later, so it outlives the first failure
+	classifier.Classify(&Event{EventName: EventPaymentSentFailed, Target: stranger, Identifier: 3, LogTime: logTime.Add(time.Hour)})
+
+	later := logTime.Add(RefundWindow + time.Minute)
+
+	assert.Equal(t, Unexpected, classifier.Classify(&Event{EventName: EventPaymentReceivedSuccess, Initiator: stranger, Identifier: 2, LogTime: later}))
+	assert.Equal(t, Refund, classifier.Classify(&Event{EventName: EventPaymentReceivedSuccess, Initiator: stranger, Identifier: 3, LogTime: later}))
+	assert.Len(t, classifier.failed, 1)
+	assert.Len(t, classifier.order, 1)
+}
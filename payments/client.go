@@ -4,21 +4,43 @@ import (
 	"net/http"
 
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
 )
 
 var (
-	_ Lister    = &Client{}
-	_ Initiator = &Client{}
+	_ Lister          = &Client{}
+	_ Initiator       = &Client{}
+	_ Splitter        = &Client{}
+	_ SelfPayer       = &Client{}
+	_ SecretInitiator = &Client{}
+	_ TimeoutSender   = &Client{}
+	_ HistoryLister   = &Client{}
+	_ StatusResolver  = &Client{}
+	_ util.RawCaller  = &Client{}
 )
 
 func NewClient(config *config.Config, httpClient *http.Client) *Client {
 	return &Client{
-		Lister:    NewLister(config, httpClient),
-		Initiator: NewInitiator(config, httpClient),
+		Lister:          NewLister(config, httpClient),
+		Initiator:       NewInitiator(config, httpClient),
+		Splitter:        NewSplitter(config, httpClient),
+		SelfPayer:       NewSelfPayer(config, httpClient),
+		SecretInitiator: NewSecretInitiator(config, httpClient),
+		TimeoutSender:   NewTimeoutSender(config, httpClient, nil),
+		HistoryLister:   NewHistoryLister(config, httpClient),
+		StatusResolver:  NewStatusResolver(config, httpClient, nil),
+		RawCaller:       util.NewRawCaller(config, httpClient),
 	}
 }
 
 type Client struct {
 	Lister
 	Initiator
+	Splitter
+	SelfPayer
+	SecretInitiator
+	TimeoutSender
+	HistoryLister
+	StatusResolver
+	util.RawCaller
 }
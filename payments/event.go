@@ -1,25 +1,145 @@
 package payments
 
 import (
+	"encoding/json"
+	"fmt"
+	"math/big"
 	"time"
 
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/util"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 type event struct {
-	EventName  string `json:"event"`
-	Amount     int64  `json:"amount"`
-	Initiator  string `json:"initiator"`
-	Target     string `json:"target"`
-	Identifier int64  `json:"identifier"`
-	LogTime    string `json:"log_time"`
+	EventName  string      `json:"event"`
+	Amount     json.Number `json:"amount"`
+	Initiator  string      `json:"initiator,omitempty"`
+	Target     string      `json:"target,omitempty"`
+	Identifier int64       `json:"identifier"`
+	LogTime    logTime     `json:"log_time"`
 }
 
 type Event struct {
 	EventName  string
-	Amount     int64
+	Amount     *big.Int
 	Initiator  common.Address
 	Target     common.Address
 	Identifier int64
 	LogTime    time.Time
 }
+
+// String returns a concise description of the event for logs, e.g.
+// "EventPaymentSentSuccess 42 amount 200 target 0x61C8… at 2019-03-07T18:19:13Z",
+// with the addresses abbreviated and only present when they are known.
+func (event Event) String() string {
+	var description = fmt.Sprintf("%s %d amount %s", event.EventName, event.Identifier, amounts.OrZero(event.Amount).String())
+
+	if event.Initiator != (common.Address{}) {
+		description += " initiator " + util.ShortAddress(event.Initiator)
+	}
+
+	if event.Target != (common.Address{}) {
+		description += " target " + util.ShortAddress(event.Target)
+	}
+
+	return description + " at " + event.LogTime.Format(time.RFC3339)
+}
+
+// MarshalJSON encodes the event in the format of the Raiden node API, with the
+// addresses as checksummed hex strings and the amount as a JSON number. Like the node
+// does, the initiator and target are only present when they are known.
+func (event Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fromEvent(&event))
+}
+
+// UnmarshalJSON decodes an event in the format of the Raiden node API, which is the
+// format events are marshalled in.
+func (event *Event) UnmarshalJSON(data []byte) error {
+	var (
+		err     error
+		decoded *Event
+	)
+
+	if decoded, err = decodeEvent(data); err != nil {
+		return err
+	}
+
+	*event = *decoded
+
+	return nil
+}
+
+func decodeEvent(data []byte) (*Event, error) {
+	var response = &event{}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return nil, err
+	}
+
+	return response.toEvent()
+}
+
+func (event *event) toEvent() (*Event, error) {
+	amount, err := amounts.Parse(event.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		EventName:  event.EventName,
+		Amount:     amount,
+		Initiator:  common.HexToAddress(event.Initiator),
+		Target:     common.HexToAddress(event.Target),
+		Identifier: event.Identifier,
+		LogTime:    event.LogTime.Time,
+	}, nil
+}
+
+func fromEvent(value *Event) *event {
+	var response = &event{
+		EventName:  value.EventName,
+		Amount:     amounts.Number(value.Amount),
+		Identifier: value.Identifier,
+		LogTime:    logTime{Time: value.LogTime},
+	}
+
+	if value.Initiator != (common.Address{}) {
+		response.Initiator = value.Initiator.Hex()
+	}
+
+	if value.Target != (common.Address{}) {
+		response.Target = value.Target.Hex()
+	}
+
+	return response
+}
+
+// Equal tells whether the event is the same as the other, comparing the amount by
+// value, see amounts.Equal, and the log times as instants, see time.Time.Equal. Two
+// nil events are equal.
+func (event *Event) Equal(other *Event) bool {
+	if event == nil || other == nil {
+		return event == other
+	}
+
+	return event.EventName == other.EventName &&
+		amounts.Equal(event.Amount, other.Amount) &&
+		event.Initiator == other.Initiator &&
+		event.Target == other.Target &&
+		event.Identifier == other.Identifier &&
+		event.LogTime.Equal(other.LogTime)
+}
+
+// Clone returns a deep copy of the event, whose amount can be changed without
+// changing that of the event.
+func (event *Event) Clone() *Event {
+	if event == nil {
+		return nil
+	}
+
+	clone := *event
+	clone.Amount = amounts.Copy(event.Amount)
+
+	return &clone
+}
@@ -0,0 +1,33 @@
+package payments
+
+// identifierPrefixShift is the position of the prefix of an identifier generated for
+// a token with an IdentifierPrefix, the 16 high bits of the positive identifiers.
+const identifierPrefixShift = 47
+
+// IdentifierPrefix returns the prefix of an identifier generated for a token with an
+// config.TokenDefaults IdentifierPrefix, e.g. to tell the payments of an application
+// apart in the events of the node. Other identifiers have random prefixes.
+func IdentifierPrefix(identifier int64) uint16 {
+	return uint16(identifier >> identifierPrefixShift)
+}
+
+// RandomIdentifier returns a random positive payment identifier, e.g. for payments
+// the application makes on its own behalf, such as rebalancing ones.
+func RandomIdentifier() int64 {
+	return randomIdentifier()
+}
+
+// prefixedIdentifier returns a random positive identifier with the prefix.
+func prefixedIdentifier(prefix uint16) int64 {
+	return int64(prefix)<<identifierPrefixShift | randomIdentifier()&(1<<identifierPrefixShift-1)
+}
+
+// defaultIdentifier returns the identifier, or one generated with the identifier
+// prefix of the token when it is zero and the token has one.
+func defaultIdentifier(identifier int64, prefix uint16) int64 {
+	if identifier != 0 || prefix == 0 {
+		return identifier
+	}
+
+	return prefixedIdentifier(prefix)
+}
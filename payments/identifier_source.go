@@ -0,0 +1,137 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The layout of a snowflake identifier, from the high bits to the low ones: the
+// milliseconds since SnowflakeEpoch, the worker and the sequence of the identifier
+// within its millisecond.
+const (
+	snowflakeWorkerBits   = 10
+	snowflakeSequenceBits = 12
+	// MaxSnowflakeWorker is the largest worker of a snowflake identifier source.
+	MaxSnowflakeWorker = 1<<snowflakeWorkerBits - 1
+)
+
+// SnowflakeEpoch is the time the milliseconds of snowflake identifiers count from,
+// leaving them about 69 years.
+var SnowflakeEpoch = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrInvalidWorker is returned for a snowflake worker beyond MaxSnowflakeWorker.
+var ErrInvalidWorker = errors.New("snowflake worker out of range")
+
+// nextIdentifier returns the identifier, or one from the identifier source of the
+// configuration when it is zero, or else one generated with the identifier prefix of
+// the token, see defaultIdentifier.
+func nextIdentifier(ctx context.Context, config *config.Config, tokenAddress common.Address, identifier int64) (int64, error) {
+	if identifier != 0 {
+		return identifier, nil
+	}
+
+	if config != nil && config.IdentifierSource != nil {
+		return config.IdentifierSource.NextIdentifier(ctx, tokenAddress)
+	}
+
+	return defaultIdentifier(identifier, config.Defaults(tokenAddress).IdentifierPrefix), nil
+}
+
+// RandomIdentifiers returns an identifier source of random positive identifiers, see
+// RandomIdentifier, e.g. to have the client choose the identifiers of the payments of
+// the tokens without an identifier prefix rather than the node.
+func RandomIdentifiers() config.IdentifierSource {
+	return randomSource{}
+}
+
+type randomSource struct{}
+
+func (randomSource) NextIdentifier(ctx context.Context, tokenAddress common.Address) (int64, error) {
+	return randomIdentifier(), nil
+}
+
+// NewSequentialIdentifiers creates an identifier source of the identifiers following
+// the last one it generated, starting at 1, recorded in the store at the key after
+// every identifier, so that the sequence carries on after a restart. Identifiers are
+// unique to the processes sharing the key one at a time only.
+func NewSequentialIdentifiers(store storage.Store, key string) config.IdentifierSource {
+	return &sequentialSource{store: store, key: key}
+}
+
+type sequentialSource struct {
+	mutex sync.Mutex
+	store storage.Store
+	key   string
+}
+
+func (source *sequentialSource) NextIdentifier(ctx context.Context, tokenAddress common.Address) (int64, error) {
+	var last int64
+
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	value, err := source.store.Get(source.key)
+
+	switch {
+	case err == storage.ErrNotFound:
+	case err != nil:
+		return 0, err
+	default:
+		if last, err = strconv.ParseInt(string(value), 10, 64); err != nil {
+			return 0, err
+		}
+	}
+
+	if err = source.store.Put(source.key, []byte(strconv.FormatInt(last+1, 10))); err != nil {
+		return 0, err
+	}
+
+	return last + 1, nil
+}
+
+// NewSnowflakeIdentifiers creates an identifier source of time ordered identifiers
+// unique across up to MaxSnowflakeWorker+1 processes given distinct workers, without
+// coordination: the milliseconds since SnowflakeEpoch on the clock, the system time
+// when nil, then the worker and a sequence. Beyond 4096 identifiers in a millisecond,
+// or when the clock moves back, the identifiers carry on from the latest millisecond.
+func NewSnowflakeIdentifiers(worker int, pace clock.Clock) (config.IdentifierSource, error) {
+	if worker < 0 || worker > MaxSnowflakeWorker {
+		return nil, ErrInvalidWorker
+	}
+
+	return &snowflakeSource{worker: int64(worker), clock: clock.Or(pace), last: -1}, nil
+}
+
+type snowflakeSource struct {
+	mutex    sync.Mutex
+	worker   int64
+	clock    clock.Clock
+	last     int64
+	sequence int64
+}
+
+func (source *snowflakeSource) NextIdentifier(ctx context.Context, tokenAddress common.Address) (int64, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	millisecond := int64(source.clock.Now().Sub(SnowflakeEpoch) / time.Millisecond)
+	if millisecond < 0 {
+		millisecond = 0
+	}
+
+	if millisecond > source.last {
+		source.last, source.sequence = millisecond, 0
+	} else if source.sequence++; source.sequence >= 1<<snowflakeSequenceBits {
+		source.last, source.sequence = source.last+1, 0
+	}
+
+	return source.last<<(snowflakeWorkerBits+snowflakeSequenceBits) | source.worker<<snowflakeSequenceBits | source.sequence, nil
+}
@@ -0,0 +1,175 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewSnowflakeIdentifiers() {
+	var (
+		// every instance of the payout service has its own worker
+		source, _ = NewSnowflakeIdentifiers(7, nil)
+		config    = &config.Config{
+			Host:             "http://localhost:5001",
+			APIVersion:       "v1",
+			IdentifierSource: source,
+		}
+		tokenAddress  = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	payment, err := NewInitiator(config, http.DefaultClient).Initiate(context.Background(), tokenAddress, targetAddress, big.NewInt(100))
+	if err != nil {
+		fmt.Println("unable to pay:", err.Error())
+		return
+	}
+
+	fmt.Println("paid with identifier", payment.Identifier)
+}
+
+func TestSequentialIdentifiers(t *testing.T) {
+	var (
+		ctx   = context.Background()
+		store = storage.NewMemoryStore()
+		token = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+	)
+
+	source := NewSequentialIdentifiers(store, "payouts/identifier")
+
+	for expected := int64(1); expected <= 3; expected++ {
+		identifier, err := source.NextIdentifier(ctx, token)
+		require.NoError(t, err)
+		assert.Equal(t, expected, identifier)
+	}
+
+	// the sequence carries on after a restart
+	identifier, err := NewSequentialIdentifiers(store, "payouts/identifier").NextIdentifier(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), identifier)
+
+	require.NoError(t, store.Put("payouts/identifier", []byte("not a number")))
+
+	_, err = source.NextIdentifier(ctx, token)
+	assert.Error(t, err)
+}
+
+func TestSnowflakeIdentifiers(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		manual = clock.NewManual(SnowflakeEpoch.Add(time.Hour))
+		token  = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+	)
+
+	_, err := NewSnowflakeIdentifiers(MaxSnowflakeWorker+1, manual)
+	assert.Equal(t, ErrInvalidWorker, err)
+
+	source, err := NewSnowflakeIdentifiers(7, manual)
+	require.NoError(t, err)
+
+	first, err := source.NextIdentifier(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(time.Hour/time.Millisecond)<<22|7<<12, first)
+
+	// the identifiers within a millisecond carry on its sequence
+	second, err := source.NextIdentifier(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, first+1, second)
+
+	manual.Advance(time.Hour)
+
+	third, err := source.NextIdentifier(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2*time.Hour/time.Millisecond)<<22|7<<12, third)
+
+	t.Run("unique under concurrency", func(t *testing.T) {
+		var (
+			mutex       sync.Mutex
+			waitGroup   sync.WaitGroup
+			identifiers = make(map[int64]bool)
+		)
+
+		for i := 0; i < 8; i++ {
+			waitGroup.Add(1)
+
+			go func() {
+				defer waitGroup.Done()
+
+				for j := 0; j < 1000; j++ {
+					identifier, err := source.NextIdentifier(ctx, token)
+					assert.NoError(t, err)
+					assert.True(t, identifier > 0)
+
+					mutex.Lock()
+					identifiers[identifier] = true
+					mutex.Unlock()
+				}
+			}()
+		}
+
+		waitGroup.Wait()
+		assert.Len(t, identifiers, 8000)
+	})
+}
+
+type failingSource struct{}
+
+func (failingSource) NextIdentifier(ctx context.Context, tokenAddress common.Address) (int64, error) {
+	return 0, errors.New("identifier store unavailable")
+}
+
+func TestInitiatorIdentifierSource(t *testing.T) {
+	var (
+		tokenAddress  = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		request       = &initiatePaymentRequest{}
+		config        = &config.Config{
+			Host:             "http://localhost:5001",
+			APIVersion:       "v1",
+			TokenDefaults:    map[common.Address]*config.TokenDefaults{tokenAddress: &config.TokenDefaults{IdentifierPrefix: 42}},
+			IdentifierSource: NewSequentialIdentifiers(storage.NewMemoryStore(), "identifier"),
+		}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:5001/api/v1/payments/%s/%s", tokenAddress.Hex(), targetAddress.Hex()), func(httpRequest *http.Request) (*http.Response, error) {
+		require.NoError(t, json.NewDecoder(httpRequest.Body).Decode(request))
+
+		return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"initiator_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","target_address":"%s","token_address":"%s","amount":200,"identifier":%d}`, targetAddress.Hex(), tokenAddress.Hex(), request.Identifier)), nil
+	})
+
+	initiator := NewInitiator(config, http.DefaultClient)
+
+	// the source takes the place of the prefixed identifiers, given ones are kept
+	for _, identifier := range []int64{0, 0, 7} {
+		_, err := initiator.InitiateWithIdentifier(context.Background(), tokenAddress, targetAddress, big.NewInt(200), identifier)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int64(7), request.Identifier)
+
+	payment, err := initiator.Initiate(context.Background(), tokenAddress, targetAddress, big.NewInt(200))
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), payment.Identifier)
+
+	config.IdentifierSource = failingSource{}
+
+	_, err = initiator.Initiate(context.Background(), tokenAddress, targetAddress, big.NewInt(200))
+	assert.EqualError(t, err, "identifier store unavailable")
+}
@@ -0,0 +1,35 @@
+package payments
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentifierPrefix(t *testing.T) {
+	type testcase struct {
+		name   string
+		prefix uint16
+	}
+
+	testcases := []testcase{
+		testcase{name: "small prefix", prefix: 1},
+		testcase{name: "application prefix", prefix: 42},
+		testcase{name: "largest prefix", prefix: 0xffff},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				identifier := prefixedIdentifier(tc.prefix)
+
+				assert.True(t, identifier > 0)
+				assert.Equal(t, tc.prefix, IdentifierPrefix(identifier))
+			}
+		})
+	}
+
+	assert.Equal(t, int64(12), defaultIdentifier(12, 42))
+	assert.Equal(t, int64(0), defaultIdentifier(0, 0))
+	assert.Equal(t, uint16(42), IdentifierPrefix(defaultIdentifier(0, 42)))
+}
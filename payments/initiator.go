@@ -3,21 +3,29 @@ package payments
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"math/big"
 	"net/http"
 	"net/url"
 
+	"github.com/cpurta/go-raiden-client/amounts"
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/progress"
 	"github.com/cpurta/go-raiden-client/util"
 	"github.com/ethereum/go-ethereum/common"
 )
 
 type initiatePaymentRequest struct {
-	Amount int64 `json:"amount"`
+	Amount      json.Number `json:"amount"`
+	Identifier  int64       `json:"identifier,omitempty"`
+	LockTimeout int64       `json:"lock_timeout,omitempty"`
 }
 
+// Initiator is a generic interface to initiate a payment of a given amount of a
+// token to a target address. A payment identifier can optionally be provided so
+// that the payment can be tracked, otherwise the Raiden node will generate one.
 type Initiator interface {
-	Initiate(ctx context.Context, tokenAddress, targetAddress common.Address, amount int64) (*Payment, error)
+	Initiate(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int) (*Payment, error)
+	InitiateWithIdentifier(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64) (*Payment, error)
 }
 
 func NewInitiator(config *config.Config, httpClient *http.Client) Initiator {
@@ -33,49 +41,43 @@ type defaultInitiator struct {
 	baseClient *util.BaseClient
 }
 
-func (initiator *defaultInitiator) Initiate(ctx context.Context, tokenAddress, targetAddress common.Address, amount int64) (*Payment, error) {
-	var (
-		err     error
-		payment *Payment
+func (initiator *defaultInitiator) Initiate(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int) (*Payment, error) {
+	return initiator.InitiateWithIdentifier(ctx, tokenAddress, targetAddress, amount, 0)
+}
 
-		requestURL *url.URL
-		request    *http.Request
-		response   *http.Response
+// InitiateWithIdentifier will initiate a payment to the target address using the
+// provided payment identifier, reporting its progress to the progress options of the
+// context, if any. The lock timeout and identifier prefix of the token defaults of
+// the configuration are applied, see config.TokenDefaults, and a zero identifier is
+// taken from its IdentifierSource, if any.
+func (initiator *defaultInitiator) InitiateWithIdentifier(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64) (*Payment, error) {
+	var (
+		err      error
+		payment  = &payment{}
+		defaults = initiator.baseClient.Config.Defaults(tokenAddress)
+
+		requestURL             *url.URL
+		initiatePaymentRequest = &initiatePaymentRequest{
+			Amount:      amounts.Number(amount),
+			LockTimeout: defaults.LockTimeout,
+		}
 	)
 
-	if requestURL, err = initiator.getRequestURL(tokenAddress, targetAddress); err != nil {
-		return nil, err
-	}
-
-	if request, err = http.NewRequest("POST", requestURL.String(), nil); err != nil {
+	if identifier, err = nextIdentifier(ctx, initiator.baseClient.Config, tokenAddress, identifier); err != nil {
 		return nil, err
 	}
 
-	request = request.WithContext(ctx)
+	initiatePaymentRequest.Identifier = identifier
 
-	if response, err = initiator.baseClient.HTTPClient.Do(request); err != nil {
+	if requestURL, err = initiator.baseClient.Endpoint("payments/%s/%s", tokenAddress.Hex(), targetAddress.Hex()); err != nil {
 		return nil, err
 	}
 
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&payment); err != nil {
-		return nil, err
-	}
-
-	return payment, nil
-}
-
-func (initiator *defaultInitiator) getRequestURL(tokenAddress, targetAddress common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/payments/%s/%s", initiator.baseClient.Config.Host, initiator.baseClient.Config.APIVersion, tokenAddress.Hex(), targetAddress.Hex())
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
+	if err = progress.Track(ctx, progress.OperationPayment, progress.PaymentStages, pollPayment(initiator.baseClient, tokenAddress, targetAddress, identifier), func(ctx context.Context) error {
+		return initiator.baseClient.Call(ctx, "POST", requestURL, initiatePaymentRequest, payment)
+	}); err != nil {
 		return nil, err
 	}
 
-	return requestURL, nil
+	return payment.toPayment()
 }
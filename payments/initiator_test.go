@@ -2,8 +2,11 @@ package payments
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
+	"math/big"
 	"net/http"
 	"os"
 	"testing"
@@ -31,7 +34,7 @@ func ExampleInitiator() {
 
 	paymentClient = NewClient(config, http.DefaultClient)
 
-	if payment, err = paymentClient.Initiate(context.Background(), tokenAddress, targetAddress, amount); err != nil {
+	if payment, err = paymentClient.Initiate(context.Background(), tokenAddress, targetAddress, big.NewInt(amount)); err != nil {
 		panic(fmt.Sprintf("unable to initiate payment: %s", err.Error()))
 	}
 
@@ -76,7 +79,7 @@ func TestInitiator(t *testing.T) {
 				InitiatorAddress: common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
 				TargetAddress:    common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
 				TokenAddress:     common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"),
-				Amount:           int64(200),
+				Amount:           big.NewInt(200),
 				Identifier:       int64(42),
 			},
 		},
@@ -123,10 +126,11 @@ func TestInitiator(t *testing.T) {
 
 			// test list all
 
-			payment, err = initiator.Initiate(ctx, tokenAddress, partnerAddress, int64(200))
+			payment, err = initiator.Initiate(ctx, tokenAddress, partnerAddress, big.NewInt(200))
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, payment)
 				return
 			}
 
@@ -135,3 +139,102 @@ func TestInitiator(t *testing.T) {
 		})
 	}
 }
+
+func TestInitiatorTokenDefaults(t *testing.T) {
+	var (
+		tokenAddress  = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		otherToken    = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		config        = &config.Config{
+			Host:          "http://localhost:5001",
+			APIVersion:    "v1",
+			TokenDefaults: map[common.Address]*config.TokenDefaults{tokenAddress: &config.TokenDefaults{LockTimeout: 60, IdentifierPrefix: 42}},
+		}
+	)
+
+	type testcase struct {
+		name                string
+		tokenAddress        common.Address
+		identifier          int64
+		expectedLockTimeout int64
+		expectedIdentifier  int64
+		expectedPrefix      uint16
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:                "generated identifier with the prefix of the token",
+			tokenAddress:        tokenAddress,
+			expectedLockTimeout: 60,
+			expectedPrefix:      42,
+		},
+		testcase{
+			name:                "given identifier",
+			tokenAddress:        tokenAddress,
+			identifier:          7,
+			expectedLockTimeout: 60,
+			expectedIdentifier:  7,
+		},
+		testcase{
+			name:         "token without defaults",
+			tokenAddress: otherToken,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var request = &initiatePaymentRequest{}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:5001/api/v1/payments/%s/%s", tc.tokenAddress.Hex(), targetAddress.Hex()), func(httpRequest *http.Request) (*http.Response, error) {
+				require.NoError(t, json.NewDecoder(httpRequest.Body).Decode(request))
+
+				return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"initiator_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","target_address":"%s","token_address":"%s","amount":200,"identifier":%d}`, targetAddress.Hex(), tc.tokenAddress.Hex(), request.Identifier)), nil
+			})
+
+			payment, err := NewInitiator(config, http.DefaultClient).InitiateWithIdentifier(context.Background(), tc.tokenAddress, targetAddress, big.NewInt(200), tc.identifier)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedLockTimeout, request.LockTimeout)
+			assert.Equal(t, request.Identifier, payment.Identifier)
+
+			if tc.expectedPrefix != 0 {
+				assert.Equal(t, tc.expectedPrefix, IdentifierPrefix(request.Identifier))
+				return
+			}
+
+			assert.Equal(t, tc.expectedIdentifier, request.Identifier)
+		})
+	}
+}
+
+func TestInitiatorLargeAmount(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		// 100 tokens of 18 decimals, beyond the range of an int64
+		amount, _ = new(big.Int).SetString("100000000000000000000", 10)
+		body      []byte
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", fmt.Sprintf("http://localhost:5001/api/v1/payments/%s/%s", tokenAddress.Hex(), targetAddress.Hex()), func(request *http.Request) (*http.Response, error) {
+		body, _ = ioutil.ReadAll(request.Body)
+
+		return httpmock.NewStringResponse(http.StatusOK, `{"amount":100000000000000000000,"identifier":1}`), nil
+	})
+
+	payment, err := NewInitiator(config, http.DefaultClient).InitiateWithIdentifier(context.Background(), tokenAddress, targetAddress, amount, 1)
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"amount":100000000000000000000,"identifier":1}`, string(body))
+	assert.Equal(t, 0, amount.Cmp(payment.Amount))
+}
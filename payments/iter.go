@@ -0,0 +1,20 @@
+//go:build go1.23
+// +build go1.23
+
+package payments
+
+import (
+	"context"
+	"iter"
+
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// All returns an iterator over the payment events between the token and target,
+// listed with the lister once the iteration starts, see util.Iterate.
+func All(ctx context.Context, lister Lister, tokenAddress, targetAddress common.Address) iter.Seq2[*Event, error] {
+	return util.Iterate(ctx, func(ctx context.Context) ([]*Event, error) {
+		return lister.List(ctx, tokenAddress, targetAddress)
+	})
+}
@@ -2,11 +2,8 @@ package payments
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
-	"time"
 
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/cpurta/go-raiden-client/util"
@@ -37,61 +34,25 @@ func (lister *defaultLister) List(ctx context.Context, tokenAddress, targetAddre
 		paymentEvents = make([]*Event, 0)
 
 		requestURL *url.URL
-		request    *http.Request
-		response   *http.Response
 	)
 
-	if requestURL, err = lister.getRequestURL(tokenAddress, targetAddress); err != nil {
+	if requestURL, err = lister.baseClient.Endpoint("payments/%s/%s", tokenAddress.Hex(), targetAddress.Hex()); err != nil {
 		return nil, err
 	}
 
-	if request, err = http.NewRequest("GET", requestURL.String(), nil); err != nil {
+	if err = lister.baseClient.Call(ctx, "GET", requestURL, nil, &events); err != nil {
 		return nil, err
 	}
 
-	request = request.WithContext(ctx)
+	for _, eventResponse := range events {
+		var event *Event
 
-	if response, err = lister.baseClient.HTTPClient.Do(request); err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&events); err != nil {
-		return nil, err
-	}
-
-	for _, event := range events {
-		var (
-			logTime time.Time
-		)
-		if logTime, err = time.Parse(time.RFC3339, event.LogTime); err != nil {
-			continue
+		if event, err = eventResponse.toEvent(); err != nil {
+			return nil, err
 		}
 
-		paymentEvents = append(paymentEvents, &Event{
-			EventName:  event.EventName,
-			Amount:     event.Amount,
-			Initiator:  common.HexToAddress(event.Initiator),
-			Target:     common.HexToAddress(event.Target),
-			Identifier: event.Identifier,
-			LogTime:    logTime,
-		})
+		paymentEvents = append(paymentEvents, event)
 	}
 
 	return paymentEvents, nil
 }
-
-func (lister *defaultLister) getRequestURL(tokenAddress, targetAddress common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/payments/%s/%s", lister.baseClient.Config.Host, lister.baseClient.Config.APIVersion, tokenAddress.Hex(), targetAddress.Hex())
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
-	}
-
-	return requestURL, nil
-}
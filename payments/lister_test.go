@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"os"
 	"testing"
@@ -80,21 +81,21 @@ func TestLister(t *testing.T) {
 			expectedEvents: []*Event{
 				&Event{
 					EventName:  "EventPaymentReceivedSuccess",
-					Amount:     int64(5),
+					Amount:     big.NewInt(5),
 					Initiator:  common.HexToAddress("0x82641569b2062B545431cF6D7F0A418582865ba7"),
 					Identifier: int64(1),
 					LogTime:    time1,
 				},
 				&Event{
 					EventName:  "EventPaymentSentSuccess",
-					Amount:     int64(35),
+					Amount:     big.NewInt(35),
 					Target:     common.HexToAddress("0x82641569b2062B545431cF6D7F0A418582865ba7"),
 					Identifier: int64(2),
 					LogTime:    time2,
 				},
 				&Event{
 					EventName:  "EventPaymentSentSuccess",
-					Amount:     int64(20),
+					Amount:     big.NewInt(20),
 					Target:     common.HexToAddress("0x82641569b2062B545431cF6D7F0A418582865ba7"),
 					Identifier: int64(3),
 					LogTime:    time3,
@@ -148,6 +149,7 @@ func TestLister(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, events)
 				return
 			}
 
@@ -0,0 +1,38 @@
+//go:build go1.21
+// +build go1.21
+
+package payments
+
+import (
+	"log/slog"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LogValue implements slog.LogValuer, logging the event as a group of its name,
+// identifier, amount, time and the addresses that are known.
+func (event Event) LogValue() slog.Value {
+	var attrs = []slog.Attr{
+		slog.String("event", event.EventName),
+		slog.Int64("identifier", event.Identifier),
+		slog.String("amount", amounts.OrZero(event.Amount).String()),
+		slog.Time("log_time", event.LogTime),
+	}
+
+	if event.Initiator != (common.Address{}) {
+		attrs = append(attrs, slog.String("initiator", event.Initiator.Hex()))
+	}
+
+	if event.Target != (common.Address{}) {
+		attrs = append(attrs, slog.String("target", event.Target.Hex()))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer, logging the secret as its hash only, see
+// Secret.String.
+func (secret Secret) LogValue() slog.Value {
+	return slog.StringValue(secret.String())
+}
@@ -0,0 +1,53 @@
+//go:build go1.21
+// +build go1.21
+
+package payments
+
+import (
+	"bytes"
+	"log/slog"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventLogValue(t *testing.T) {
+	var event = &Event{
+		EventName:  EventPaymentSentSuccess,
+		Amount:     big.NewInt(200),
+		Target:     common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+		Identifier: 42,
+		LogTime:    time.Date(2019, time.March, 7, 18, 19, 13, 0, time.UTC),
+	}
+
+	var attrs = make(map[string]interface{})
+
+	for _, attr := range event.LogValue().Group() {
+		attrs[attr.Key] = attr.Value.Any()
+	}
+
+	assert.Equal(t, map[string]interface{}{
+		"event":      EventPaymentSentSuccess,
+		"identifier": int64(42),
+		"amount":     "200",
+		"log_time":   event.LogTime,
+		"target":     "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+	}, attrs)
+}
+
+func TestSecretLogValue(t *testing.T) {
+	var buffer bytes.Buffer
+
+	secret, err := NewSecret()
+	require.NoError(t, err)
+
+	slog.New(slog.NewTextHandler(&buffer, nil)).Info("paying", "secret", secret)
+
+	assert.False(t, strings.Contains(buffer.String(), strings.TrimPrefix(secret.Hex(), "0x")))
+	assert.Contains(t, buffer.String(), secret.Hash().Hex())
+}
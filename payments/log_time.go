@@ -0,0 +1,58 @@
+package payments
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// logTimeLayouts are the formats of the log_time of payment events known to be used
+// by the different Raiden versions. Timestamps without a timezone are in UTC.
+var logTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+}
+
+// logTime is the log_time of a payment event, which accepts every format of
+// logTimeLayouts instead of RFC 3339 only.
+type logTime struct {
+	time.Time
+}
+
+func (logTime *logTime) UnmarshalJSON(data []byte) error {
+	var (
+		err   error
+		value *string
+	)
+
+	if err = json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if value == nil || *value == "" {
+		logTime.Time = time.Time{}
+		return nil
+	}
+
+	for _, layout := range logTimeLayouts {
+		var parsed time.Time
+
+		if parsed, err = time.Parse(layout, *value); err == nil {
+			logTime.Time = parsed
+			return nil
+		}
+	}
+
+	return fmt.Errorf("invalid log_time: %s", *value)
+}
+
+// MarshalJSON encodes the log time as an RFC 3339 string, or null when it is unknown.
+func (logTime logTime) MarshalJSON() ([]byte, error) {
+	if logTime.IsZero() {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(logTime.Format(time.RFC3339Nano))
+}
@@ -0,0 +1,84 @@
+package payments
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogTimeUnmarshalJSON(t *testing.T) {
+	type testcase struct {
+		name          string
+		data          string
+		expectedTime  time.Time
+		expectedError error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "RFC 3339 with fractional seconds",
+			data:         `"2018-10-30T07:03:52.193Z"`,
+			expectedTime: time.Date(2018, 10, 30, 7, 3, 52, 193000000, time.UTC),
+		},
+		testcase{
+			name:         "RFC 3339 without fractional seconds",
+			data:         `"2018-10-30T07:03:52Z"`,
+			expectedTime: time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC),
+		},
+		testcase{
+			name:         "timezone offset",
+			data:         `"2018-10-30T09:03:52.193+02:00"`,
+			expectedTime: time.Date(2018, 10, 30, 7, 3, 52, 193000000, time.UTC),
+		},
+		testcase{
+			name:         "no timezone",
+			data:         `"2018-10-30T07:03:52.193000"`,
+			expectedTime: time.Date(2018, 10, 30, 7, 3, 52, 193000000, time.UTC),
+		},
+		testcase{
+			name:         "no timezone and no fractional seconds",
+			data:         `"2018-10-30T07:03:52"`,
+			expectedTime: time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC),
+		},
+		testcase{
+			name:         "space separated",
+			data:         `"2018-10-30 07:03:52.193"`,
+			expectedTime: time.Date(2018, 10, 30, 7, 3, 52, 193000000, time.UTC),
+		},
+		testcase{
+			name:         "null",
+			data:         `null`,
+			expectedTime: time.Time{},
+		},
+		testcase{
+			name:          "unknown format",
+			data:          `"30/10/2018 07:03"`,
+			expectedError: errors.New("invalid log_time: 30/10/2018 07:03"),
+		},
+		testcase{
+			name:          "not a string",
+			data:          `1540883032`,
+			expectedError: errors.New("json: cannot unmarshal number into Go value of type string"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var logTime logTime
+
+			err := json.Unmarshal([]byte(tc.data), &logTime)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tc.expectedTime.Equal(logTime.Time), "expected %s, got %s", tc.expectedTime, logTime.Time)
+		})
+	}
+}
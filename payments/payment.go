@@ -1,11 +1,84 @@
 package payments
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type payment struct {
+	InitiatorAddress util.Address `json:"initiator_address"`
+	TargetAddress    util.Address `json:"target_address"`
+	TokenAddress     util.Address `json:"token_address"`
+	Amount           json.Number  `json:"amount"`
+	Identifier       int64        `json:"identifier"`
+}
+
+func (payment *payment) toPayment() (*Payment, error) {
+	amount, err := amounts.Parse(payment.Amount)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Payment{
+		InitiatorAddress: common.Address(payment.InitiatorAddress),
+		TargetAddress:    common.Address(payment.TargetAddress),
+		TokenAddress:     common.Address(payment.TokenAddress),
+		Amount:           amount,
+		Identifier:       payment.Identifier,
+	}, nil
+}
 
 type Payment struct {
 	InitiatorAddress common.Address `json:"initiator_address"`
 	TargetAddress    common.Address `json:"target_address"`
 	TokenAddress     common.Address `json:"token_address"`
-	Amount           int64          `json:"amount"`
+	Amount           *big.Int       `json:"amount"`
 	Identifier       int64          `json:"identifier"`
 }
+
+// MarshalJSON encodes the payment in the format of the Raiden node API, with the
+// addresses as checksummed hex strings and the amount as a JSON number.
+func (payment Payment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fromPayment(&payment))
+}
+
+// UnmarshalJSON decodes a payment in the format of the Raiden node API, which is the
+// format payments are marshalled in.
+func (payment *Payment) UnmarshalJSON(data []byte) error {
+	var (
+		err     error
+		decoded *Payment
+	)
+
+	if decoded, err = decodePayment(data); err != nil {
+		return err
+	}
+
+	*payment = *decoded
+
+	return nil
+}
+
+func decodePayment(data []byte) (*Payment, error) {
+	var response = &payment{}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return nil, err
+	}
+
+	return response.toPayment()
+}
+
+func fromPayment(value *Payment) *payment {
+	return &payment{
+		InitiatorAddress: util.Address(value.InitiatorAddress),
+		TargetAddress:    util.Address(value.TargetAddress),
+		TokenAddress:     util.Address(value.TokenAddress),
+		Amount:           amounts.Number(value.Amount),
+		Identifier:       value.Identifier,
+	}
+}
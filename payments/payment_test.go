@@ -0,0 +1,123 @@
+package payments
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaymentJSONRoundTrip(t *testing.T) {
+	var (
+		data    = `{"initiator_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","amount":200,"identifier":42}`
+		payment = &Payment{}
+	)
+
+	require.NoError(t, json.Unmarshal([]byte(data), payment))
+
+	assert.Equal(t, &Payment{
+		InitiatorAddress: common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+		TargetAddress:    common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+		TokenAddress:     common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"),
+		Amount:           big.NewInt(200),
+		Identifier:       42,
+	}, payment)
+
+	encoded, err := json.Marshal(payment)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, data, string(encoded))
+}
+
+func TestEventJSONRoundTrip(t *testing.T) {
+	type testcase struct {
+		name          string
+		data          string
+		expectedEvent *Event
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "received payment",
+			data: `{"event":"EventPaymentReceivedSuccess","amount":5,"initiator":"0x82641569b2062B545431cF6D7F0A418582865ba7","identifier":1,"log_time":"2018-10-30T07:03:52.193Z"}`,
+			expectedEvent: &Event{
+				EventName:  "EventPaymentReceivedSuccess",
+				Amount:     big.NewInt(5),
+				Initiator:  common.HexToAddress("0x82641569b2062B545431cF6D7F0A418582865ba7"),
+				Identifier: 1,
+				LogTime:    time.Date(2018, 10, 30, 7, 3, 52, 193000000, time.UTC),
+			},
+		},
+		testcase{
+			name: "sent payment",
+			data: `{"event":"EventPaymentSentSuccess","amount":35,"target":"0x82641569b2062B545431cF6D7F0A418582865ba7","identifier":2,"log_time":"2018-10-30T07:04:22Z"}`,
+			expectedEvent: &Event{
+				EventName:  "EventPaymentSentSuccess",
+				Amount:     big.NewInt(35),
+				Target:     common.HexToAddress("0x82641569b2062B545431cF6D7F0A418582865ba7"),
+				Identifier: 2,
+				LogTime:    time.Date(2018, 10, 30, 7, 4, 22, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var event = &Event{}
+
+			require.NoError(t, json.Unmarshal([]byte(tc.data), event))
+			assert.Equal(t, tc.expectedEvent, event)
+
+			encoded, err := json.Marshal(event)
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.data, string(encoded))
+		})
+	}
+}
+
+func TestEventEqualAndClone(t *testing.T) {
+	var (
+		logTime = time.Date(2019, time.March, 7, 18, 19, 13, 0, time.UTC)
+		event   = &Event{
+			EventName:  EventPaymentSentSuccess,
+			Amount:     big.NewInt(200),
+			Target:     common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+			Identifier: 42,
+			LogTime:    logTime,
+		}
+		clone = event.Clone()
+	)
+
+	assert.True(t, event.Equal(clone))
+
+	clone.LogTime = logTime.In(time.FixedZone("CET", 3600))
+	assert.True(t, event.Equal(clone), "log times are compared as instants")
+
+	clone.Amount.SetInt64(201)
+	assert.Equal(t, int64(200), event.Amount.Int64())
+	assert.False(t, event.Equal(clone))
+
+	assert.True(t, (*Event)(nil).Equal(nil))
+	assert.False(t, event.Equal(nil))
+	assert.Nil(t, (*Event)(nil).Clone())
+}
+
+func TestEventString(t *testing.T) {
+	var event = Event{
+		EventName:  EventPaymentSentSuccess,
+		Amount:     big.NewInt(200),
+		Target:     common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+		Identifier: 42,
+		LogTime:    time.Date(2019, time.March, 7, 18, 19, 13, 0, time.UTC),
+	}
+
+	assert.Equal(t, "EventPaymentSentSuccess 42 amount 200 target 0x61C8… at 2019-03-07T18:19:13Z", event.String())
+
+	event.Initiator, event.Target = event.Target, common.Address{}
+	assert.Equal(t, "EventPaymentSentSuccess 42 amount 200 initiator 0x61C8… at 2019-03-07T18:19:13Z", event.String())
+}
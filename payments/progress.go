@@ -0,0 +1,51 @@
+package payments
+
+import (
+	"context"
+
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/cpurta/go-raiden-client/progress"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// pollPayment returns the poll of the progress of the payment with the identifier,
+// which is locked once the node lists it as a pending transfer it initiated, a route
+// having been found for it, and unlocked once the node reports it as sent. Payments
+// without an identifier can't be told apart from the others and are not polled.
+func pollPayment(baseClient *util.BaseClient, tokenAddress, targetAddress common.Address, identifier int64) progress.Poll {
+	var (
+		lister    = NewLister(baseClient.Config, baseClient.HTTPClient)
+		transfers = pendingtransfers.NewLister(baseClient.Config, baseClient.HTTPClient)
+	)
+
+	if identifier == 0 {
+		return nil
+	}
+
+	return func(ctx context.Context) (progress.Stage, error) {
+		events, err := lister.List(ctx, tokenAddress, targetAddress)
+		if err != nil {
+			return "", err
+		}
+
+		for _, event := range events {
+			if event.Identifier == identifier && event.EventName == EventPaymentSentSuccess {
+				return progress.Unlocked, nil
+			}
+		}
+
+		transferList, err := transfers.ListToken(ctx, tokenAddress)
+		if err != nil {
+			return "", err
+		}
+
+		for _, transfer := range transferList {
+			if transfer.PaymentIdentifier == identifier && transfer.Role == pendingtransfers.RoleInitiator {
+				return progress.Locked, nil
+			}
+		}
+
+		return progress.Submitted, nil
+	}
+}
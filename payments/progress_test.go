@@ -0,0 +1,62 @@
+package payments
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/progress"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitiateProgress(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		eventsURL     = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		locked        = make(chan struct{})
+		lockedOnce    sync.Once
+		mutex         sync.Mutex
+		stages        []progress.Stage
+		ctx           = progress.WithOptions(context.Background(), &progress.Options{
+			Report: func(event *progress.Event) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				stages = append(stages, event.Stage)
+			},
+			Interval: time.Millisecond,
+		})
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// the node answers the payment once it was seen locked in a pending transfer
+	httpmock.RegisterResponder("POST", eventsURL, func(request *http.Request) (*http.Response, error) {
+		<-locked
+		return httpmock.NewStringResponse(http.StatusOK, `{"initiator_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","amount":10,"identifier":42}`), nil
+	})
+	httpmock.RegisterResponder("GET", eventsURL, httpmock.NewStringResponder(http.StatusOK, `[]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", func(request *http.Request) (*http.Response, error) {
+		defer lockedOnce.Do(func() { close(locked) })
+		return httpmock.NewStringResponse(http.StatusOK, `[{"channel_identifier":7,"initiator":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","locked_amount":10,"payment_identifier":42,"role":"initiator","target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","token_network_identifier":"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6","transferred_amount":0}]`), nil
+	})
+
+	payment, err := NewInitiator(config, http.DefaultClient).InitiateWithIdentifier(ctx, tokenAddress, targetAddress, big.NewInt(10), 42)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), payment.Identifier)
+	assert.Equal(t, []progress.Stage{progress.Submitted, progress.RouteFound, progress.Locked, progress.Unlocked}, stages)
+}
@@ -0,0 +1,158 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CapacityError is returned when reserving more than the capacity of a channel the
+// other reservations leave, Available being that capacity, zero when there is no
+// open channel with the partner.
+type CapacityError struct {
+	TokenAddress   common.Address
+	PartnerAddress common.Address
+	Amount         *big.Int
+	Available      *big.Int
+}
+
+func (err *CapacityError) Error() string {
+	return fmt.Sprintf("unable to reserve %s in the channel with %s, %s left", amounts.OrZero(err.Amount), err.PartnerAddress.Hex(), amounts.OrZero(err.Available))
+}
+
+// Code returns raidenerrors.CodeInsufficientBalance, see raidenerrors.HasCode.
+func (err *CapacityError) Code() raidenerrors.Code {
+	return raidenerrors.CodeInsufficientBalance
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *CapacityError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// Reservation is outbound capacity of the channel of a token with a partner held for
+// a planned payment, until it is released.
+type Reservation struct {
+	TokenAddress   common.Address
+	PartnerAddress common.Address
+	Amount         *big.Int
+
+	reserver *Reserver
+	once     sync.Once
+}
+
+// Release gives the capacity back to the other payments. It is to be called once
+// the payment was sent, when the balance of the channel the node lists accounts for
+// it, or given up. Releasing a reservation again does nothing.
+func (reservation *Reservation) Release() {
+	reservation.once.Do(func() {
+		reservation.reserver.release(reservation)
+	})
+}
+
+// Reserver holds the outbound capacity of channels for the payments planned by the
+// concurrent payment flows of a process, so that they don't all count on the same
+// liquidity and then fail when they are sent. The capacity of a channel is its
+// balance as the node lists it, less the capacity reserved by the process.
+type Reserver struct {
+	lister channels.Lister
+
+	mutex    sync.Mutex
+	reserved map[capacityKey]*big.Int
+}
+
+// NewReserver creates a reserver of the capacity of the channels of the Raiden node,
+// given a Raiden node configuration and an http client.
+func NewReserver(config *config.Config, httpClient *http.Client) *Reserver {
+	return &Reserver{
+		lister:   channels.NewLister(config, httpClient),
+		reserved: make(map[capacityKey]*big.Int),
+	}
+}
+
+// Reserve will hold the amount in the open channel of the token with the partner,
+// the first hop of the payment, returning a *CapacityError when the capacity left
+// by the other reservations does not cover it.
+func (reserver *Reserver) Reserve(ctx context.Context, tokenAddress, partnerAddress common.Address, amount *big.Int) (*Reservation, error) {
+	var (
+		key     = capacityKey{tokenAddress, partnerAddress}
+		balance = big.NewInt(0)
+	)
+
+	channelList, err := reserver.lister.ListToken(ctx, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range channelList {
+		if channel.PartnerAddress == partnerAddress && channel.State == "opened" {
+			balance.Set(amounts.OrZero(channel.Balance))
+		}
+	}
+
+	reserver.mutex.Lock()
+	defer reserver.mutex.Unlock()
+
+	available := new(big.Int).Sub(balance, amounts.OrZero(reserver.reserved[key]))
+	if available.Sign() < 0 {
+		available.SetInt64(0)
+	}
+
+	if available.Cmp(amounts.OrZero(amount)) < 0 {
+		return nil, &CapacityError{TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: amounts.Copy(amount), Available: available}
+	}
+
+	if reserver.reserved[key] == nil {
+		reserver.reserved[key] = big.NewInt(0)
+	}
+
+	reserver.reserved[key].Add(reserver.reserved[key], amounts.OrZero(amount))
+
+	return &Reservation{
+		TokenAddress:   tokenAddress,
+		PartnerAddress: partnerAddress,
+		Amount:         amounts.Copy(amounts.OrZero(amount)),
+		reserver:       reserver,
+	}, nil
+}
+
+// ReserveOutcome will reserve the amount and estimated fee of a payment simulated to
+// succeed in the channel the route of the payment leaves the node through, see
+// Simulator.
+func (reserver *Reserver) ReserveOutcome(ctx context.Context, outcome *Outcome) (*Reservation, error) {
+	if outcome.Route == nil || outcome.Route.Hops() == 0 {
+		return nil, &CapacityError{TokenAddress: outcome.TokenAddress, PartnerAddress: outcome.TargetAddress, Amount: amounts.Copy(outcome.Amount), Available: big.NewInt(0)}
+	}
+
+	return reserver.Reserve(ctx, outcome.TokenAddress, outcome.Route.Path[1], new(big.Int).Add(amounts.OrZero(outcome.Amount), amounts.OrZero(outcome.EstimatedFee)))
+}
+
+// Reserved returns the capacity of the channel of the token with the partner held by
+// the reservations that were not released.
+func (reserver *Reserver) Reserved(tokenAddress, partnerAddress common.Address) *big.Int {
+	reserver.mutex.Lock()
+	defer reserver.mutex.Unlock()
+
+	return new(big.Int).Set(amounts.OrZero(reserver.reserved[capacityKey{tokenAddress, partnerAddress}]))
+}
+
+func (reserver *Reserver) release(reservation *Reservation) {
+	var key = capacityKey{reservation.TokenAddress, reservation.PartnerAddress}
+
+	reserver.mutex.Lock()
+	defer reserver.mutex.Unlock()
+
+	if reserved := reserver.reserved[key]; reserved != nil {
+		if reserved.Sub(reserved, reservation.Amount); reserved.Sign() <= 0 {
+			delete(reserver.reserved, key)
+		}
+	}
+}
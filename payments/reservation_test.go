@@ -0,0 +1,136 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/pfs"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleReserver() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		reserver      = NewReserver(config, http.DefaultClient)
+		initiator     = NewInitiator(config, http.DefaultClient)
+		tokenAddress  = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		targetAddress = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+		amount        = big.NewInt(1000)
+	)
+
+	// the direct channel with the target is held for the payment while it is sent
+	reservation, err := reserver.Reserve(context.Background(), tokenAddress, targetAddress, amount)
+	if err != nil {
+		panic(fmt.Sprintf("not enough capacity for the payment: %s", err.Error()))
+	}
+	defer reservation.Release()
+
+	if _, err = initiator.Initiate(context.Background(), tokenAddress, targetAddress, amount); err != nil {
+		panic(fmt.Sprintf("unable to send payment: %s", err.Error()))
+	}
+}
+
+func TestReserver(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		closedPartner  = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		reserver       = NewReserver(config, http.DefaultClient)
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusOK, `[
+		{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":100,"total_deposit":100,"state":"opened"},
+		{"channel_identifier":8,"partner_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":100,"total_deposit":100,"state":"closed"}
+	]`))
+
+	first, err := reserver.Reserve(context.Background(), tokenAddress, partnerAddress, big.NewInt(60))
+	require.NoError(t, err)
+	assert.Equal(t, int64(60), first.Amount.Int64())
+
+	_, err = reserver.Reserve(context.Background(), tokenAddress, partnerAddress, big.NewInt(50))
+	require.Error(t, err)
+	assert.EqualError(t, err, "unable to reserve 50 in the channel with 0x61C808D82A3Ac53231750daDc13c777b59310bD9, 40 left")
+	assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodeInsufficientBalance))
+	assert.True(t, err.(*CapacityError).Is(raidenerrors.ErrInsufficientBalance))
+
+	second, err := reserver.Reserve(context.Background(), tokenAddress, partnerAddress, big.NewInt(40))
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), reserver.Reserved(tokenAddress, partnerAddress).Int64())
+
+	// releasing twice gives the capacity back once
+	first.Release()
+	first.Release()
+	assert.Equal(t, int64(40), reserver.Reserved(tokenAddress, partnerAddress).Int64())
+
+	second.Release()
+	assert.Equal(t, int64(0), reserver.Reserved(tokenAddress, partnerAddress).Int64())
+
+	_, err = reserver.Reserve(context.Background(), tokenAddress, closedPartner, big.NewInt(1))
+	assert.EqualError(t, err, "unable to reserve 1 in the channel with 0x2a65Aca4D5fC5B5C859090a6c34d164135398226, 0 left")
+
+	t.Run("outcome", func(t *testing.T) {
+		var outcome = &Outcome{
+			TokenAddress:  tokenAddress,
+			TargetAddress: closedPartner,
+			Amount:        big.NewInt(90),
+			EstimatedFee:  big.NewInt(10),
+			Route:         &pfs.Route{Path: []common.Address{common.Address{}, partnerAddress, closedPartner}},
+			Success:       true,
+		}
+
+		reservation, err := reserver.ReserveOutcome(context.Background(), outcome)
+		require.NoError(t, err)
+		assert.Equal(t, partnerAddress, reservation.PartnerAddress)
+		assert.Equal(t, int64(100), reservation.Amount.Int64())
+		reservation.Release()
+
+		_, err = reserver.ReserveOutcome(context.Background(), &Outcome{TokenAddress: tokenAddress, TargetAddress: closedPartner, Amount: big.NewInt(1)})
+		assert.Error(t, err)
+	})
+
+	t.Run("concurrent flows", func(t *testing.T) {
+		var (
+			waitGroup sync.WaitGroup
+			mutex     sync.Mutex
+			reserved  int
+		)
+
+		for i := 0; i < 10; i++ {
+			waitGroup.Add(1)
+
+			go func() {
+				defer waitGroup.Done()
+
+				if _, err := reserver.Reserve(context.Background(), tokenAddress, partnerAddress, big.NewInt(30)); err == nil {
+					mutex.Lock()
+					reserved++
+					mutex.Unlock()
+				}
+			}()
+		}
+
+		waitGroup.Wait()
+
+		assert.Equal(t, 3, reserved, "the flows never count on the same capacity")
+		assert.Equal(t, int64(90), reserver.Reserved(tokenAddress, partnerAddress).Int64())
+	})
+}
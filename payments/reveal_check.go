@@ -0,0 +1,273 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// DefaultMinRevealMargin is the time the reveal timeout of a channel must at least
+	// last when the check options give none, enough for the node to register a secret
+	// on chain when a partner does not cooperate.
+	DefaultMinRevealMargin = 10 * time.Minute
+	// DefaultSampledBlocks is the number of blocks the current block time is averaged
+	// over when the check options give none.
+	DefaultSampledBlocks = 100
+	// DefaultBlockTime is the time between two blocks of the chain when it can't be
+	// measured and the check options give none, the one of Ethereum mainnet.
+	DefaultBlockTime = 15 * time.Second
+)
+
+// Reasons the timeouts of a channel make a payment risky.
+const (
+	// ReasonShortRevealTimeout is a reveal timeout lasting less than the minimum margin
+	// at the current block time.
+	ReasonShortRevealTimeout = "short_reveal_timeout"
+	// ReasonShortSettleTimeout is a settle timeout shorter than twice the reveal
+	// timeout, which leaves the lock of a payment no time to expire before settlement.
+	ReasonShortSettleTimeout = "short_settle_timeout"
+)
+
+// HeaderReader is the part of an Ethereum client the block time is measured with,
+// which *ethclient.Client implements.
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// RiskyChannel is a channel a payment could take whose timeouts are too short, with
+// the time its reveal timeout lasts at the current block time.
+type RiskyChannel struct {
+	Channel      *channels.Channel
+	RevealMargin time.Duration
+	Reason       string
+}
+
+// RiskError is returned when a payment is refused because channels it could take
+// have risky timeouts, see RevealCheckOptions.
+type RiskError struct {
+	TokenAddress  common.Address
+	TargetAddress common.Address
+	BlockTime     time.Duration
+	Channels      []*RiskyChannel
+}
+
+func (err *RiskError) Error() string {
+	var partners = make([]string, 0, len(err.Channels))
+
+	for _, risky := range err.Channels {
+		partners = append(partners, fmt.Sprintf("%s (%s)", risky.Channel.PartnerAddress.Hex(), risky.Reason))
+	}
+
+	return fmt.Sprintf("payment to %s could take channels with risky timeouts: %s", err.TargetAddress.Hex(), strings.Join(partners, ", "))
+}
+
+// Code returns raidenerrors.CodeRiskyTimeouts, see raidenerrors.HasCode.
+func (err *RiskError) Code() raidenerrors.Code {
+	return raidenerrors.CodeRiskyTimeouts
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *RiskError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// RevealCheckOptions configures the checks of the timeouts of the channels a payment
+// could take: their reveal timeout must last at least MinRevealMargin at the block
+// time averaged over the last SampledBlocks blocks, BlockTime standing in for it
+// without a header reader. Risky payments are reported to Warn, which may be nil, and
+// only refused when Refuse is set.
+type RevealCheckOptions struct {
+	MinRevealMargin time.Duration
+	SampledBlocks   int64
+	BlockTime       time.Duration
+	Refuse          bool
+	Warn            func(err *RiskError)
+}
+
+// RevealChecker is a generic interface to check the timeouts of the channels a
+// payment could take before sending it, a safety net against misconfigured channels.
+type RevealChecker interface {
+	Check(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int) (*RiskError, error)
+}
+
+// NewRevealChecker creates a new default reveal checker given a Raiden node
+// configuration, the header reader of an Ethereum client, nil to use the block time
+// of the options, the check options, nil for the defaults, and an http client.
+func NewRevealChecker(config *config.Config, headerReader HeaderReader, options *RevealCheckOptions, httpClient *http.Client) RevealChecker {
+	var checker = &defaultRevealChecker{
+		lister:        channels.NewLister(config, httpClient),
+		headerReader:  headerReader,
+		minMargin:     DefaultMinRevealMargin,
+		sampledBlocks: DefaultSampledBlocks,
+		blockTime:     DefaultBlockTime,
+	}
+
+	if options != nil && options.MinRevealMargin > 0 {
+		checker.minMargin = options.MinRevealMargin
+	}
+
+	if options != nil && options.SampledBlocks > 0 {
+		checker.sampledBlocks = options.SampledBlocks
+	}
+
+	if options != nil && options.BlockTime > 0 {
+		checker.blockTime = options.BlockTime
+	}
+
+	return checker
+}
+
+type defaultRevealChecker struct {
+	lister        channels.Lister
+	headerReader  HeaderReader
+	minMargin     time.Duration
+	sampledBlocks int64
+	blockTime     time.Duration
+}
+
+// Check will return a *RiskError listing the channels the payment could take whose
+// timeouts are risky at the current block time, or nil when there are none. The
+// payment could take the open channel with the target when there is one, any open
+// channel of the token whose balance covers the amount otherwise.
+func (checker *defaultRevealChecker) Check(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int) (*RiskError, error) {
+	var (
+		err         error
+		channelList []*channels.Channel
+		riskError   = &RiskError{TokenAddress: tokenAddress, TargetAddress: targetAddress}
+	)
+
+	if riskError.BlockTime, err = checker.currentBlockTime(ctx); err != nil {
+		return nil, err
+	}
+
+	if channelList, err = checker.lister.ListToken(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	for _, channel := range candidates(channelList, targetAddress, amount) {
+		margin := time.Duration(channel.RevealTimeout) * riskError.BlockTime
+
+		switch {
+		case margin < checker.minMargin:
+			riskError.Channels = append(riskError.Channels, &RiskyChannel{Channel: channel, RevealMargin: margin, Reason: ReasonShortRevealTimeout})
+		case channel.SettleTimeout < 2*channel.RevealTimeout:
+			riskError.Channels = append(riskError.Channels, &RiskyChannel{Channel: channel, RevealMargin: margin, Reason: ReasonShortSettleTimeout})
+		}
+	}
+
+	if len(riskError.Channels) == 0 {
+		return nil, nil
+	}
+
+	return riskError, nil
+}
+
+// currentBlockTime returns the average time between the last sampled blocks, or the
+// block time of the options without a header reader or enough blocks.
+func (checker *defaultRevealChecker) currentBlockTime(ctx context.Context) (time.Duration, error) {
+	if checker.headerReader == nil {
+		return checker.blockTime, nil
+	}
+
+	latest, err := checker.headerReader.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	blocks := checker.sampledBlocks
+	if latest.Number.Int64() < blocks {
+		blocks = latest.Number.Int64()
+	}
+
+	if blocks <= 0 {
+		return checker.blockTime, nil
+	}
+
+	earliest, err := checker.headerReader.HeaderByNumber(ctx, new(big.Int).Sub(latest.Number, big.NewInt(blocks)))
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(latest.Time-earliest.Time) * time.Second / time.Duration(blocks), nil
+}
+
+// candidates returns the open channel with the target when there is one, the open
+// channels whose balance covers the amount otherwise.
+func candidates(channelList []*channels.Channel, targetAddress common.Address, amount *big.Int) []*channels.Channel {
+	var found = make([]*channels.Channel, 0, len(channelList))
+
+	for _, channel := range channelList {
+		if channel.State == "opened" && channel.PartnerAddress == targetAddress {
+			return []*channels.Channel{channel}
+		}
+	}
+
+	for _, channel := range channelList {
+		if channel.State == "opened" && amounts.OrZero(channel.Balance).Cmp(amounts.OrZero(amount)) >= 0 {
+			found = append(found, channel)
+		}
+	}
+
+	return found
+}
+
+// NewCheckedInitiator creates a payment initiator that checks the timeouts of the
+// channels every payment could take before initiating it, given a Raiden node
+// configuration, the header reader of an Ethereum client, nil to use the block time
+// of the options, the check options, nil for the defaults, and an http client.
+func NewCheckedInitiator(config *config.Config, headerReader HeaderReader, options *RevealCheckOptions, httpClient *http.Client) Initiator {
+	var initiator = &checkedInitiator{
+		initiator: NewInitiator(config, httpClient),
+		checker:   NewRevealChecker(config, headerReader, options, httpClient),
+	}
+
+	if options != nil {
+		initiator.refuse, initiator.warn = options.Refuse, options.Warn
+	}
+
+	return initiator
+}
+
+type checkedInitiator struct {
+	initiator Initiator
+	checker   RevealChecker
+	refuse    bool
+	warn      func(err *RiskError)
+}
+
+func (initiator *checkedInitiator) Initiate(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int) (*Payment, error) {
+	return initiator.InitiateWithIdentifier(ctx, tokenAddress, targetAddress, amount, 0)
+}
+
+// InitiateWithIdentifier will check the timeouts of the channels the payment could
+// take, reporting a risky payment to the warning callback and returning it as a
+// *RiskError instead of initiating it when risky payments are refused.
+func (initiator *checkedInitiator) InitiateWithIdentifier(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64) (*Payment, error) {
+	riskError, err := initiator.checker.Check(ctx, tokenAddress, targetAddress, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	if riskError != nil {
+		if initiator.warn != nil {
+			initiator.warn(riskError)
+		}
+
+		if initiator.refuse {
+			return nil, riskError
+		}
+	}
+
+	return initiator.initiator.InitiateWithIdentifier(ctx, tokenAddress, targetAddress, amount, identifier)
+}
@@ -0,0 +1,243 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewCheckedInitiator() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		// an *ethclient.Client measures the current block time, nil uses the one of the
+		// options
+		initiator     = NewCheckedInitiator(config, nil, &RevealCheckOptions{BlockTime: 5 * time.Second, Refuse: true}, http.DefaultClient)
+		tokenAddress  = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	payment, err := initiator.Initiate(context.Background(), tokenAddress, targetAddress, big.NewInt(1000))
+	if raidenerrors.HasCode(err, raidenerrors.CodeRiskyTimeouts) {
+		panic(fmt.Sprintf("refused a risky payment: %s", err.Error()))
+	}
+
+	if err != nil {
+		panic(fmt.Sprintf("unable to initiate payment: %s", err.Error()))
+	}
+
+	fmt.Println(payment.Identifier)
+}
+
+// headerReaderFunc reads headers with a function, for tests.
+type headerReaderFunc func(number *big.Int) (*types.Header, error)
+
+func (reader headerReaderFunc) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return reader(number)
+}
+
+// blocksEvery returns a header reader of a chain of 1000 blocks mined every interval.
+func blocksEvery(interval uint64) HeaderReader {
+	return headerReaderFunc(func(number *big.Int) (*types.Header, error) {
+		if number == nil {
+			number = big.NewInt(1000)
+		}
+
+		return &types.Header{Number: number, Time: number.Uint64() * interval}, nil
+	})
+}
+
+func TestRevealChecker(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partner       = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		other         = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		target        = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		failingReader = headerReaderFunc(func(number *big.Int) (*types.Header, error) {
+			return nil, errors.New("connection refused")
+		})
+	)
+
+	type testcase struct {
+		name              string
+		headerReader      HeaderReader
+		options           *RevealCheckOptions
+		targetAddress     common.Address
+		amount            int64
+		channels          string
+		expectedBlockTime time.Duration
+		expectedRisky     map[common.Address]string
+		expectedError     error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:          "safe direct channel",
+			headerReader:  blocksEvery(15),
+			targetAddress: partner,
+			amount:        10,
+			channels:      `[{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":25,"state":"opened","reveal_timeout":50,"settle_timeout":500}]`,
+		},
+		testcase{
+			name:              "reveal timeout too short at the measured block time",
+			headerReader:      blocksEvery(5),
+			targetAddress:     partner,
+			amount:            10,
+			channels:          `[{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":25,"state":"opened","reveal_timeout":50,"settle_timeout":500}]`,
+			expectedBlockTime: 5 * time.Second,
+			expectedRisky:     map[common.Address]string{partner: ReasonShortRevealTimeout},
+		},
+		testcase{
+			name:              "settle timeout shorter than twice the reveal timeout",
+			options:           &RevealCheckOptions{BlockTime: 15 * time.Second},
+			targetAddress:     partner,
+			amount:            10,
+			channels:          `[{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":25,"state":"opened","reveal_timeout":50,"settle_timeout":60}]`,
+			expectedBlockTime: 15 * time.Second,
+			expectedRisky:     map[common.Address]string{partner: ReasonShortSettleTimeout},
+		},
+		testcase{
+			name:              "mediated payment through the channels covering the amount",
+			options:           &RevealCheckOptions{BlockTime: 15 * time.Second, MinRevealMargin: time.Hour},
+			targetAddress:     target,
+			amount:            20,
+			channels:          `[{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":25,"state":"opened","reveal_timeout":50,"settle_timeout":500},{"partner_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","balance":5,"state":"opened","reveal_timeout":10,"settle_timeout":500},{"partner_address":"0x0000000000000000000000000000000000000002","balance":50,"state":"closed","reveal_timeout":10,"settle_timeout":500}]`,
+			expectedBlockTime: 15 * time.Second,
+			expectedRisky:     map[common.Address]string{partner: ReasonShortRevealTimeout},
+		},
+		testcase{
+			name:          "no chain to measure the block time with",
+			targetAddress: other,
+			amount:        1,
+			channels:      `[{"partner_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","balance":5,"state":"opened","reveal_timeout":40,"settle_timeout":500}]`,
+		},
+		testcase{
+			name:          "failing header reader",
+			headerReader:  failingReader,
+			targetAddress: partner,
+			channels:      `[]`,
+			expectedError: errors.New("connection refused"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusOK, tc.channels))
+
+			riskError, err := NewRevealChecker(config, tc.headerReader, tc.options, http.DefaultClient).Check(context.Background(), tokenAddress, tc.targetAddress, big.NewInt(tc.amount))
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+
+			if tc.expectedRisky == nil {
+				assert.Nil(t, riskError)
+				return
+			}
+
+			require.NotNil(t, riskError)
+			assert.Equal(t, tc.expectedBlockTime, riskError.BlockTime)
+
+			risky := make(map[common.Address]string)
+			for _, channel := range riskError.Channels {
+				risky[channel.Channel.PartnerAddress] = channel.Reason
+			}
+
+			assert.Equal(t, tc.expectedRisky, risky)
+		})
+	}
+}
+
+func TestCheckedInitiator(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partner      = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		paymentURL   = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+	)
+
+	type testcase struct {
+		name            string
+		refuse          bool
+		revealTimeout   int64
+		expectedWarned  bool
+		expectedPayment bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:            "safe payment",
+			revealTimeout:   50,
+			expectedPayment: true,
+		},
+		testcase{
+			name:            "risky payment warned about",
+			revealTimeout:   5,
+			expectedWarned:  true,
+			expectedPayment: true,
+		},
+		testcase{
+			name:           "risky payment refused",
+			refuse:         true,
+			revealTimeout:  5,
+			expectedWarned: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var warned *RiskError
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`[{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":25,"state":"opened","reveal_timeout":%d,"settle_timeout":500}]`, tc.revealTimeout)))
+			httpmock.RegisterResponder("POST", paymentURL, httpmock.NewStringResponder(http.StatusOK, `{"initiator_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","amount":10,"identifier":42}`))
+
+			initiator := NewCheckedInitiator(config, blocksEvery(15), &RevealCheckOptions{Refuse: tc.refuse, Warn: func(err *RiskError) { warned = err }}, http.DefaultClient)
+
+			payment, err := initiator.InitiateWithIdentifier(context.Background(), tokenAddress, partner, big.NewInt(10), 42)
+
+			assert.Equal(t, tc.expectedWarned, warned != nil)
+
+			if tc.expectedPayment {
+				require.NoError(t, err)
+				assert.Equal(t, int64(42), payment.Identifier)
+				assert.Equal(t, 1, httpmock.GetCallCountInfo()["POST "+paymentURL])
+				return
+			}
+
+			assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodeRiskyTimeouts))
+			assert.True(t, err.(*RiskError).Is(raidenerrors.ErrRiskyTimeouts))
+			assert.Equal(t, warned, err)
+			assert.Zero(t, httpmock.GetCallCountInfo()["POST "+paymentURL])
+		})
+	}
+}
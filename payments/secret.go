@@ -0,0 +1,162 @@
+package payments
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// SecretLength is the length in bytes of the secret of a hash time locked payment.
+const SecretLength = 32
+
+// ErrNoSecretHash is returned when initiating a payment with an empty secret hash,
+// which the node would take as a payment with a secret of its own.
+var ErrNoSecretHash = errors.New("payment requires a secret hash")
+
+// Secret is the preimage of the hash lock of a payment: the payment only completes
+// once the target learns the secret, which lets the payer or the target choose it,
+// e.g. to release goods against the payment.
+type Secret [SecretLength]byte
+
+// NewSecret generates a secret from a cryptographically secure source of randomness.
+func NewSecret() (Secret, error) {
+	var secret Secret
+
+	if _, err := rand.Read(secret[:]); err != nil {
+		return Secret{}, fmt.Errorf("unable to generate a secret: %s", err.Error())
+	}
+
+	return secret, nil
+}
+
+// ParseSecret parses a secret formatted as 0x prefixed hex, see Secret.Hex.
+func ParseSecret(value string) (Secret, error) {
+	var secret Secret
+
+	decoded, err := hexutil.Decode(value)
+	if err != nil || len(decoded) != SecretLength {
+		return Secret{}, fmt.Errorf("invalid secret: must be %d bytes of 0x prefixed hex", SecretLength)
+	}
+
+	copy(secret[:], decoded)
+
+	return secret, nil
+}
+
+// Hash returns the secret hash the payment is locked with, the sha256 hash of the
+// secret as the Raiden node computes it.
+func (secret Secret) Hash() common.Hash {
+	return common.Hash(sha256.Sum256(secret[:]))
+}
+
+// Hex returns the secret as 0x prefixed hex, the format of the Raiden node API.
+func (secret Secret) Hex() string {
+	return hexutil.Encode(secret[:])
+}
+
+// String does not print the secret, so that it does not end up in logs by mistake.
+func (secret Secret) String() string {
+	return "Secret(" + secret.Hash().Hex() + ")"
+}
+
+// Matches tells whether the secret is the preimage of the secret hash, e.g. to check
+// the secret revealed for a payment that was locked with the hash.
+func (secret Secret) Matches(secretHash common.Hash) bool {
+	return secret.Hash() == secretHash
+}
+
+type secretPaymentRequest struct {
+	Amount      json.Number `json:"amount"`
+	Identifier  int64       `json:"identifier,omitempty"`
+	Secret      string      `json:"secret,omitempty"`
+	SecretHash  string      `json:"secret_hash"`
+	LockTimeout int64       `json:"lock_timeout,omitempty"`
+}
+
+// SecretInitiator is a generic interface to initiate hash time locked payments with a
+// secret chosen by the caller, see NewSecret. InitiateWithSecret sends the secret and
+// its hash, InitiateWithSecretHash only the hash, the target holding the secret.
+type SecretInitiator interface {
+	InitiateWithSecret(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64, secret Secret) (*Payment, error)
+	InitiateWithSecretHash(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64, secretHash common.Hash) (*Payment, error)
+}
+
+// NewSecretInitiator creates a new default secret initiator given a Raiden node
+// configuration and an http client.
+func NewSecretInitiator(config *config.Config, httpClient *http.Client) SecretInitiator {
+	return &defaultSecretInitiator{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultSecretInitiator struct {
+	baseClient *util.BaseClient
+}
+
+// InitiateWithSecret will initiate a payment to the target address locked with the
+// hash of the secret.
+func (initiator *defaultSecretInitiator) InitiateWithSecret(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64, secret Secret) (*Payment, error) {
+	return initiator.initiate(ctx, tokenAddress, targetAddress, &secretPaymentRequest{
+		Amount:     amounts.Number(amount),
+		Identifier: identifier,
+		Secret:     secret.Hex(),
+		SecretHash: secret.Hash().Hex(),
+	})
+}
+
+// InitiateWithSecretHash will initiate a payment to the target address locked with
+// the secret hash, whose secret is not known to the node.
+func (initiator *defaultSecretInitiator) InitiateWithSecretHash(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64, secretHash common.Hash) (*Payment, error) {
+	if secretHash == (common.Hash{}) {
+		return nil, ErrNoSecretHash
+	}
+
+	return initiator.initiate(ctx, tokenAddress, targetAddress, &secretPaymentRequest{
+		Amount:     amounts.Number(amount),
+		Identifier: identifier,
+		SecretHash: secretHash.Hex(),
+	})
+}
+
+// initiate applies the lock timeout and identifier prefix of the token defaults of the
+// configuration to the request, see config.TokenDefaults, or the identifier of its
+// IdentifierSource, and sends it.
+func (initiator *defaultSecretInitiator) initiate(ctx context.Context, tokenAddress, targetAddress common.Address, request *secretPaymentRequest) (*Payment, error) {
+	var (
+		err        error
+		requestURL *url.URL
+		payment    = &payment{}
+		defaults   = initiator.baseClient.Config.Defaults(tokenAddress)
+	)
+
+	if request.Identifier, err = nextIdentifier(ctx, initiator.baseClient.Config, tokenAddress, request.Identifier); err != nil {
+		return nil, err
+	}
+
+	request.LockTimeout = defaults.LockTimeout
+
+	if requestURL, err = initiator.baseClient.Endpoint("payments/%s/%s", tokenAddress.Hex(), targetAddress.Hex()); err != nil {
+		return nil, err
+	}
+
+	if err = initiator.baseClient.Call(ctx, "POST", requestURL, request, payment); err != nil {
+		return nil, err
+	}
+
+	return payment.toPayment()
+}
@@ -0,0 +1,120 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSecretInitiator() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		initiator     = NewSecretInitiator(config, http.DefaultClient)
+		tokenAddress  = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	secret, err := NewSecret()
+	if err != nil {
+		panic(err.Error())
+	}
+
+	if _, err = initiator.InitiateWithSecret(context.Background(), tokenAddress, targetAddress, big.NewInt(1000), 42, secret); err != nil {
+		panic(fmt.Sprintf("unable to initiate payment: %s", err.Error()))
+	}
+
+	fmt.Println("payment locked with", secret.Hash().Hex())
+}
+
+func TestSecret(t *testing.T) {
+	var zero = "0x0000000000000000000000000000000000000000000000000000000000000000"
+
+	t.Run("hash of the secret", func(t *testing.T) {
+		secret, err := ParseSecret(zero)
+		require.NoError(t, err)
+
+		assert.Equal(t, common.HexToHash("0x66687aadf862bd776c8fc18b8e9f8e20089714856ee233b3902a591d0d5f2925"), secret.Hash())
+		assert.Equal(t, zero, secret.Hex())
+		assert.True(t, secret.Matches(secret.Hash()))
+		assert.False(t, secret.Matches(common.Hash{}))
+		assert.NotContains(t, fmt.Sprint(secret), zero)
+	})
+
+	t.Run("generated secrets are distinct", func(t *testing.T) {
+		first, err := NewSecret()
+		require.NoError(t, err)
+
+		second, err := NewSecret()
+		require.NoError(t, err)
+
+		assert.NotEqual(t, first, second)
+		assert.NotEqual(t, Secret{}, first)
+	})
+
+	t.Run("invalid secrets", func(t *testing.T) {
+		for _, value := range []string{"", "0x1234", zero[2:], zero + "00", "0xzz" + zero[4:]} {
+			_, err := ParseSecret(value)
+			assert.Error(t, err, value)
+		}
+	})
+}
+
+func TestSecretInitiator(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		paymentURL    = fmt.Sprintf("http://localhost:5001/api/v1/payments/%s/%s", tokenAddress.Hex(), targetAddress.Hex())
+		secret, _     = ParseSecret("0x0000000000000000000000000000000000000000000000000000000000000001")
+		received      *secretPaymentRequest
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", paymentURL, func(request *http.Request) (*http.Response, error) {
+		received = &secretPaymentRequest{}
+
+		if err := json.NewDecoder(request.Body).Decode(received); err != nil {
+			return httpmock.NewStringResponse(http.StatusBadRequest, ``), nil
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"target_address":"%s","token_address":"%s","amount":10,"identifier":42}`, targetAddress.Hex(), tokenAddress.Hex())), nil
+	})
+
+	t.Run("secret and its hash", func(t *testing.T) {
+		payment, err := NewSecretInitiator(config, http.DefaultClient).InitiateWithSecret(context.Background(), tokenAddress, targetAddress, big.NewInt(10), 42, secret)
+		require.NoError(t, err)
+
+		assert.Equal(t, big.NewInt(10), payment.Amount)
+		assert.Equal(t, &secretPaymentRequest{Amount: "10", Identifier: 42, Secret: secret.Hex(), SecretHash: secret.Hash().Hex()}, received)
+	})
+
+	t.Run("secret hash only", func(t *testing.T) {
+		_, err := NewSecretInitiator(config, http.DefaultClient).InitiateWithSecretHash(context.Background(), tokenAddress, targetAddress, big.NewInt(10), 42, secret.Hash())
+		require.NoError(t, err)
+
+		assert.Equal(t, &secretPaymentRequest{Amount: "10", Identifier: 42, SecretHash: secret.Hash().Hex()}, received)
+	})
+
+	t.Run("empty secret hash", func(t *testing.T) {
+		_, err := NewSecretInitiator(config, http.DefaultClient).InitiateWithSecretHash(context.Background(), tokenAddress, targetAddress, big.NewInt(10), 42, common.Hash{})
+
+		assert.Equal(t, ErrNoSecretHash, err)
+	})
+}
@@ -0,0 +1,118 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInvalidRoute is returned when the route of a self-payment does not start and end
+// at the node, or does not go out and back through two different channels.
+var ErrInvalidRoute = errors.New("a self-payment route must go from the node through two different partners back to the node")
+
+type selfPaymentRequest struct {
+	Amount     json.Number    `json:"amount"`
+	Identifier int64          `json:"identifier,omitempty"`
+	Paths      []*paymentPath `json:"paths,omitempty"`
+}
+
+// paymentPath is a route the node is asked to send a payment along, in the format of
+// the paths of the payments endpoint.
+type paymentPath struct {
+	Route           []string               `json:"route"`
+	AddressMetadata map[string]interface{} `json:"address_metadata"`
+}
+
+// SelfPayer is a generic interface to initiate payments from the Raiden node to
+// itself, moving balance from the channel with the first partner of the route to
+// the channel with its last partner, as circular rebalancing and route testing do.
+type SelfPayer interface {
+	PaySelf(ctx context.Context, tokenAddress common.Address, route []common.Address, amount *big.Int, identifier int64) (*Payment, error)
+}
+
+// NewSelfPayer creates a new default self-payer given a Raiden node configuration
+// and an http client.
+func NewSelfPayer(config *config.Config, httpClient *http.Client) SelfPayer {
+	return &defaultSelfPayer{
+		addressGetter: address.NewGetter(config, httpClient),
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultSelfPayer struct {
+	addressGetter address.Getter
+	baseClient    *util.BaseClient
+}
+
+// PaySelf will initiate a payment from the node to its own address along the route,
+// which starts and ends with the address of the node, e.g. our address, the partner
+// with a surplus, any mediators, the partner with a deficit and our address again.
+// Nodes whose API takes no paths choose the route themselves, as they do for an
+// empty route.
+func (payer *defaultSelfPayer) PaySelf(ctx context.Context, tokenAddress common.Address, route []common.Address, amount *big.Int, identifier int64) (*Payment, error) {
+	var (
+		err        error
+		ourAddress common.Address
+		payment    = &payment{}
+		requestURL *url.URL
+		request    = &selfPaymentRequest{
+			Amount:     amounts.Number(amount),
+			Identifier: identifier,
+		}
+	)
+
+	if ourAddress, err = payer.addressGetter.Get(ctx); err != nil {
+		return nil, err
+	}
+
+	if len(route) > 0 {
+		if !validRoute(ourAddress, route) {
+			return nil, ErrInvalidRoute
+		}
+
+		path := &paymentPath{Route: make([]string, 0, len(route)), AddressMetadata: map[string]interface{}{}}
+		for _, hop := range route {
+			path.Route = append(path.Route, hop.Hex())
+		}
+
+		request.Paths = []*paymentPath{path}
+	}
+
+	if requestURL, err = payer.baseClient.Endpoint("payments/%s/%s", tokenAddress.Hex(), ourAddress.Hex()); err != nil {
+		return nil, err
+	}
+
+	if err = payer.baseClient.Call(ctx, "POST", requestURL, request, payment); err != nil {
+		return nil, err
+	}
+
+	return payment.toPayment()
+}
+
+// validRoute tells whether the route goes from our address out through a partner and
+// back through another one.
+func validRoute(ourAddress common.Address, route []common.Address) bool {
+	if len(route) < 4 || route[0] != ourAddress || route[len(route)-1] != ourAddress {
+		return false
+	}
+
+	for _, hop := range route[1 : len(route)-1] {
+		if hop == ourAddress {
+			return false
+		}
+	}
+
+	return route[1] != route[len(route)-2]
+}
@@ -0,0 +1,110 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSelfPayer() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		selfPayer    = NewSelfPayer(config, http.DefaultClient)
+		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		surplus      = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		deficit      = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	)
+
+	payment, err := selfPayer.PaySelf(context.Background(), tokenAddress, []common.Address{ourAddress, surplus, deficit, ourAddress}, big.NewInt(1000), 42)
+	if err != nil {
+		panic(fmt.Sprintf("unable to pay ourselves: %s", err.Error()))
+	}
+
+	fmt.Printf("moved %s from %s to %s\n", payment.Amount, surplus.Hex(), deficit.Hex())
+}
+
+func TestSelfPayer(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		first        = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		second       = common.HexToAddress("0x0000000000000000000000000000000000000002")
+		paymentURL   = fmt.Sprintf("http://localhost:5001/api/v1/payments/%s/%s", tokenAddress.Hex(), ourAddress.Hex())
+	)
+
+	type testcase struct {
+		name            string
+		route           []common.Address
+		expectedRequest string
+		expectedError   error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:            "route chosen by the node",
+			expectedRequest: `{"amount":30,"identifier":42}`,
+		},
+		testcase{
+			name:            "explicit route",
+			route:           []common.Address{ourAddress, first, second, ourAddress},
+			expectedRequest: fmt.Sprintf(`{"amount":30,"identifier":42,"paths":[{"route":["%s","%s","%s","%s"],"address_metadata":{}}]}`, ourAddress.Hex(), first.Hex(), second.Hex(), ourAddress.Hex()),
+		},
+		testcase{
+			name:          "route not starting at the node",
+			route:         []common.Address{first, second, first},
+			expectedError: ErrInvalidRoute,
+		},
+		testcase{
+			name:          "route back through the same channel",
+			route:         []common.Address{ourAddress, first, second, first, ourAddress},
+			expectedError: ErrInvalidRoute,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var requestBody string
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`{"our_address":"%s"}`, ourAddress.Hex())))
+			httpmock.RegisterResponder("POST", paymentURL, func(request *http.Request) (*http.Response, error) {
+				body, _ := ioutil.ReadAll(request.Body)
+				requestBody = string(body)
+
+				return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"initiator_address":"%s","target_address":"%s","token_address":"%s","amount":30,"identifier":42}`, ourAddress.Hex(), ourAddress.Hex(), tokenAddress.Hex())), nil
+			})
+
+			payment, err := NewSelfPayer(config, http.DefaultClient).PaySelf(context.Background(), tokenAddress, tc.route, big.NewInt(30), 42)
+
+			if tc.expectedError != nil {
+				assert.Equal(t, tc.expectedError, err)
+				assert.Zero(t, httpmock.GetCallCountInfo()["POST "+paymentURL])
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expectedRequest, requestBody)
+			assert.Equal(t, ourAddress, payment.TargetAddress)
+			assert.Equal(t, big.NewInt(30), payment.Amount)
+		})
+	}
+}
@@ -0,0 +1,337 @@
+package payments
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/node"
+	"github.com/cpurta/go-raiden-client/pfs"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultHopLatency is the time a payment is estimated to take per channel of its
+	// route when the simulation options give none.
+	DefaultHopLatency = 500 * time.Millisecond
+	// DefaultSimulatedPaths is the number of routes asked to the pathfinding service
+	// when the simulation options give none.
+	DefaultSimulatedPaths = 3
+)
+
+// Reasons a simulated payment is predicted to fail.
+const (
+	// ReasonNoRoute is a payment the pathfinding service found no route for, or
+	// without a pathfinding service a payment to a partner we have no open channel
+	// with.
+	ReasonNoRoute = "no_route"
+	// ReasonInsufficientCapacity is a payment whose amount and fee the balance of the
+	// channel it leaves the node through does not cover, once the payments simulated
+	// before it in the batch are taken out of it.
+	ReasonInsufficientCapacity = "insufficient_capacity"
+	// ReasonFeeTooHigh is a payment whose cheapest route costs more than the MaxFee
+	// of the token defaults of the configuration, see config.TokenDefaults.
+	ReasonFeeTooHigh = "fee_too_high"
+	// ReasonTargetUnreachable is a payment to a target the node sees offline, see
+	// node.ReachabilityGetter, the most common cause of failed payments.
+	ReasonTargetUnreachable = "target_unreachable"
+)
+
+// SimulationOptions configures the model of simulated payments: the latency of a
+// payment is its hops times HopLatency, and up to MaxPaths routes are asked to the
+// pathfinding service, the cheapest being used.
+type SimulationOptions struct {
+	HopLatency time.Duration
+	MaxPaths   int
+}
+
+// PlannedPayment is a payment of a batch to simulate.
+type PlannedPayment struct {
+	TokenAddress  common.Address
+	TargetAddress common.Address
+	Amount        *big.Int
+}
+
+// Outcome is the simulated outcome of a payment that was not sent: the route it is
+// estimated to take and its fee, nil when no route was found, how long it is
+// estimated to take, the Capacity of the channel it leaves the node through before
+// it, and whether it is predicted to succeed, with the Reason it is not.
+type Outcome struct {
+	TokenAddress     common.Address
+	TargetAddress    common.Address
+	Amount           *big.Int
+	Route            *pfs.Route
+	EstimatedFee     *big.Int
+	EstimatedLatency time.Duration
+	Capacity         *big.Int
+	Success          bool
+	Reason           string
+}
+
+// Simulator is a generic interface to simulate payments without spending, e.g. for
+// planning tools to evaluate a batch of payments before sending it.
+type Simulator interface {
+	Simulate(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int) (*Outcome, error)
+	SimulateBatch(ctx context.Context, payments []*PlannedPayment) ([]*Outcome, error)
+}
+
+// NewSimulator creates a new default payment simulator given a Raiden node
+// configuration, the path finder of a pathfinding service, nil to only simulate
+// payments through direct channels, the simulation options, nil for the defaults,
+// and an http client.
+func NewSimulator(config *config.Config, pathFinder pfs.PathFinder, options *SimulationOptions, httpClient *http.Client) Simulator {
+	var simulator = &defaultSimulator{
+		config:             config,
+		addressGetter:      address.NewGetter(config, httpClient),
+		tokenGetter:        tokens.NewGetter(config, httpClient),
+		lister:             channels.NewLister(config, httpClient),
+		reachabilityGetter: node.NewReachabilityGetter(config, httpClient),
+		pathFinder:         pathFinder,
+		hopLatency:         DefaultHopLatency,
+		maxPaths:           DefaultSimulatedPaths,
+	}
+
+	if options != nil && options.HopLatency > 0 {
+		simulator.hopLatency = options.HopLatency
+	}
+
+	if options != nil && options.MaxPaths > 0 {
+		simulator.maxPaths = options.MaxPaths
+	}
+
+	return simulator
+}
+
+type defaultSimulator struct {
+	config             *config.Config
+	addressGetter      address.Getter
+	tokenGetter        tokens.Getter
+	lister             channels.Lister
+	reachabilityGetter node.ReachabilityGetter
+	pathFinder         pfs.PathFinder
+	hopLatency         time.Duration
+	maxPaths           int
+}
+
+// capacityKey is the channel of a token with a partner.
+type capacityKey struct {
+	tokenAddress   common.Address
+	partnerAddress common.Address
+}
+
+// simulation is the state shared by the payments of a batch, i.e. the capacity of
+// the channels the payments simulated so far used, and the targets seen offline.
+type simulation struct {
+	ourAddress    common.Address
+	tokenNetworks map[common.Address]address.TokenNetworkAddress
+	capacities    map[capacityKey]*big.Int
+	unreachable   map[common.Address]bool
+}
+
+// Simulate will estimate the route, fee and latency of the payment and predict
+// whether it succeeds from the reachability of its target and the capacity of the
+// channel it leaves the node through. Nothing is paid.
+func (simulator *defaultSimulator) Simulate(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int) (*Outcome, error) {
+	outcomes, err := simulator.SimulateBatch(ctx, []*PlannedPayment{&PlannedPayment{TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: amount}})
+	if err != nil {
+		return nil, err
+	}
+
+	return outcomes[0], nil
+}
+
+// SimulateBatch will simulate the payments in order, every payment predicted to
+// succeed taking its amount and fee out of the capacity left to the payments after
+// it. Payments the pathfinding service finds no route for are predicted to fail
+// rather than failing the batch.
+func (simulator *defaultSimulator) SimulateBatch(ctx context.Context, payments []*PlannedPayment) ([]*Outcome, error) {
+	var (
+		err        error
+		outcomes   = make([]*Outcome, 0, len(payments))
+		simulation = &simulation{
+			tokenNetworks: make(map[common.Address]address.TokenNetworkAddress),
+			capacities:    make(map[capacityKey]*big.Int),
+			unreachable:   make(map[common.Address]bool),
+		}
+	)
+
+	if len(payments) == 0 {
+		return outcomes, nil
+	}
+
+	if simulation.ourAddress, err = simulator.addressGetter.Get(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, payment := range payments {
+		var outcome *Outcome
+
+		if outcome, err = simulator.simulate(ctx, simulation, payment); err != nil {
+			return nil, err
+		}
+
+		outcomes = append(outcomes, outcome)
+	}
+
+	return outcomes, nil
+}
+
+func (simulator *defaultSimulator) simulate(ctx context.Context, simulation *simulation, payment *PlannedPayment) (*Outcome, error) {
+	var (
+		err     error
+		outcome = &Outcome{
+			TokenAddress:  payment.TokenAddress,
+			TargetAddress: payment.TargetAddress,
+			Amount:        new(big.Int).Set(amounts.OrZero(payment.Amount)),
+		}
+	)
+
+	if err = simulator.loadCapacities(ctx, simulation, payment.TokenAddress); err != nil {
+		return nil, err
+	}
+
+	if unreachable, err := simulator.unreachable(ctx, simulation, payment.TargetAddress); err != nil {
+		return nil, err
+	} else if unreachable {
+		outcome.Reason = ReasonTargetUnreachable
+		return outcome, nil
+	}
+
+	if outcome.Route, err = simulator.findRoute(ctx, simulation, payment); err != nil {
+		return nil, err
+	}
+
+	if outcome.Route == nil {
+		outcome.Reason = ReasonNoRoute
+		return outcome, nil
+	}
+
+	outcome.EstimatedFee = amounts.OrZero(outcome.Route.EstimatedFee)
+	outcome.EstimatedLatency = time.Duration(outcome.Route.Hops()) * simulator.hopLatency
+
+	if maxFee := simulator.config.Defaults(payment.TokenAddress).MaxFee; maxFee != nil && outcome.EstimatedFee.Cmp(maxFee) > 0 {
+		outcome.Reason = ReasonFeeTooHigh
+		return outcome, nil
+	}
+
+	capacity, ok := simulation.capacities[capacityKey{payment.TokenAddress, outcome.Route.Path[1]}]
+	if !ok {
+		// a route through a partner we have no open channel with can't be taken
+		outcome.Reason = ReasonNoRoute
+		return outcome, nil
+	}
+
+	outcome.Capacity = new(big.Int).Set(capacity)
+
+	spent := new(big.Int).Add(outcome.Amount, outcome.EstimatedFee)
+	if capacity.Cmp(spent) < 0 {
+		outcome.Reason = ReasonInsufficientCapacity
+		return outcome, nil
+	}
+
+	capacity.Sub(capacity, spent)
+	outcome.Success = true
+
+	return outcome, nil
+}
+
+// unreachable tells whether the node sees the target offline, getting its
+// reachability once per batch. Targets whose reachability is unknown, e.g. with
+// nodes not exposing it, are not.
+func (simulator *defaultSimulator) unreachable(ctx context.Context, simulation *simulation, targetAddress common.Address) (bool, error) {
+	if unreachable, ok := simulation.unreachable[targetAddress]; ok {
+		return unreachable, nil
+	}
+
+	reachability, err := simulator.reachabilityGetter.PartnerReachable(ctx, targetAddress)
+	if err != nil {
+		return false, err
+	}
+
+	simulation.unreachable[targetAddress] = reachability.Unreachable()
+
+	return reachability.Unreachable(), nil
+}
+
+// loadCapacities lists the open channels of the token, once per batch, as the
+// capacities the payments of the batch start with.
+func (simulator *defaultSimulator) loadCapacities(ctx context.Context, simulation *simulation, tokenAddress common.Address) error {
+	if _, ok := simulation.tokenNetworks[tokenAddress]; ok {
+		return nil
+	}
+
+	channelList, err := simulator.lister.ListToken(ctx, tokenAddress)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channelList {
+		if channel.State == "opened" {
+			simulation.capacities[capacityKey{tokenAddress, channel.PartnerAddress}] = new(big.Int).Set(amounts.OrZero(channel.Balance))
+		}
+	}
+
+	if simulator.pathFinder == nil {
+		simulation.tokenNetworks[tokenAddress] = address.TokenNetworkAddress{}
+		return nil
+	}
+
+	tokenNetwork, err := simulator.tokenGetter.Get(ctx, tokenAddress)
+	if err != nil {
+		return err
+	}
+
+	simulation.tokenNetworks[tokenAddress] = tokenNetwork
+
+	return nil
+}
+
+// findRoute returns the cheapest route of the payment, a direct one without a
+// pathfinding service, or nil when there is none.
+func (simulator *defaultSimulator) findRoute(ctx context.Context, simulation *simulation, payment *PlannedPayment) (*pfs.Route, error) {
+	if simulator.pathFinder == nil {
+		if _, ok := simulation.capacities[capacityKey{payment.TokenAddress, payment.TargetAddress}]; !ok {
+			return nil, nil
+		}
+
+		return &pfs.Route{Path: []common.Address{simulation.ourAddress, payment.TargetAddress}, EstimatedFee: big.NewInt(0)}, nil
+	}
+
+	routes, err := simulator.pathFinder.FindPaths(ctx, simulation.tokenNetworks[payment.TokenAddress], simulation.ourAddress, payment.TargetAddress, payment.Amount, simulator.maxPaths)
+
+	// the pathfinding service refuses the payments it finds no route for
+	if statusCode := raidenerrors.StatusCode(err); statusCode >= 400 && statusCode < 500 {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return cheapest(routes), nil
+}
+
+// cheapest returns the route with the lowest estimated fee, the first of those
+// with the same fee.
+func cheapest(routes []*pfs.Route) *pfs.Route {
+	var found *pfs.Route
+
+	for _, route := range routes {
+		if route.Hops() == 0 {
+			continue
+		}
+
+		if found == nil || amounts.OrZero(route.EstimatedFee).Cmp(amounts.OrZero(found.EstimatedFee)) < 0 {
+			found = route
+		}
+	}
+
+	return found
+}
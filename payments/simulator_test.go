@@ -0,0 +1,244 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/pfs"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSimulator() {
+	var (
+		pathFinder = pfs.NewPathFinder(&config.Config{Host: "https://pfs.example.com", APIVersion: "v1"}, http.DefaultClient)
+		config     = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		simulator = NewSimulator(config, pathFinder, nil, http.DefaultClient)
+		payouts   = []*PlannedPayment{
+			&PlannedPayment{
+				TokenAddress:  common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359"), // DAI Stablecoin
+				TargetAddress: common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+				Amount:        big.NewInt(100),
+			},
+		}
+	)
+
+	outcomes, err := simulator.SimulateBatch(context.Background(), payouts)
+	if err != nil {
+		panic(fmt.Sprintf("unable to simulate the payouts: %s", err.Error()))
+	}
+
+	for _, outcome := range outcomes {
+		fmt.Println(outcome.TargetAddress.Hex(), outcome.Success, outcome.EstimatedFee, outcome.Reason)
+	}
+}
+
+// pathFinderFunc finds paths with a function, for tests.
+type pathFinderFunc func(value *big.Int) ([]*pfs.Route, error)
+
+func (finder pathFinderFunc) FindPaths(ctx context.Context, tokenNetworkAddress address.TokenNetworkAddress, from, to common.Address, value *big.Int, maxPaths int) ([]*pfs.Route, error) {
+	return finder(value)
+}
+
+func TestSimulator(t *testing.T) {
+	var (
+		// capped is declared before config shadows the package
+		capped = &config.Config{
+			Host:          "http://localhost:5001",
+			APIVersion:    "v1",
+			TokenDefaults: map[common.Address]*config.TokenDefaults{common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"): &config.TokenDefaults{MaxFee: big.NewInt(2)}},
+		}
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		ourAddress      = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		tokenAddress    = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		partnerAddress  = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		targetAddress   = common.HexToAddress("0x1f7402f55E142820Ea3812106D0657103fc1709e")
+		mediatorAddress = common.HexToAddress("0x82641569b2062B545431cF6D7F0A418582865ba7")
+		offlineAddress  = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359")
+		mediated        = pathFinderFunc(func(value *big.Int) ([]*pfs.Route, error) {
+			return []*pfs.Route{
+				&pfs.Route{Path: []common.Address{ourAddress, partnerAddress, targetAddress}, EstimatedFee: big.NewInt(3)},
+				&pfs.Route{Path: []common.Address{ourAddress, partnerAddress, mediatorAddress, targetAddress}, EstimatedFee: big.NewInt(2)},
+			}, nil
+		})
+	)
+
+	type testcase struct {
+		name             string
+		pathFinder       pfs.PathFinder
+		capped           bool
+		payments         []*PlannedPayment
+		expectedOutcomes []*Outcome
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "direct channels without a pathfinding service",
+			payments: []*PlannedPayment{
+				&PlannedPayment{TokenAddress: tokenAddress, TargetAddress: partnerAddress, Amount: big.NewInt(20)},
+				&PlannedPayment{TokenAddress: tokenAddress, TargetAddress: partnerAddress, Amount: big.NewInt(10)},
+				&PlannedPayment{TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: big.NewInt(1)},
+			},
+			expectedOutcomes: []*Outcome{
+				&Outcome{
+					TokenAddress:     tokenAddress,
+					TargetAddress:    partnerAddress,
+					Amount:           big.NewInt(20),
+					Route:            &pfs.Route{Path: []common.Address{ourAddress, partnerAddress}, EstimatedFee: big.NewInt(0)},
+					EstimatedFee:     big.NewInt(0),
+					EstimatedLatency: time.Second,
+					Capacity:         big.NewInt(25),
+					Success:          true,
+				},
+				&Outcome{
+					TokenAddress:     tokenAddress,
+					TargetAddress:    partnerAddress,
+					Amount:           big.NewInt(10),
+					Route:            &pfs.Route{Path: []common.Address{ourAddress, partnerAddress}, EstimatedFee: big.NewInt(0)},
+					EstimatedFee:     big.NewInt(0),
+					EstimatedLatency: time.Second,
+					Capacity:         big.NewInt(5),
+					Reason:           ReasonInsufficientCapacity,
+				},
+				&Outcome{
+					TokenAddress:  tokenAddress,
+					TargetAddress: targetAddress,
+					Amount:        big.NewInt(1),
+					Reason:        ReasonNoRoute,
+				},
+			},
+		},
+		testcase{
+			name:       "cheapest route of the pathfinding service",
+			pathFinder: mediated,
+			payments: []*PlannedPayment{
+				&PlannedPayment{TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: big.NewInt(20)},
+			},
+			expectedOutcomes: []*Outcome{
+				&Outcome{
+					TokenAddress:     tokenAddress,
+					TargetAddress:    targetAddress,
+					Amount:           big.NewInt(20),
+					Route:            &pfs.Route{Path: []common.Address{ourAddress, partnerAddress, mediatorAddress, targetAddress}, EstimatedFee: big.NewInt(2)},
+					EstimatedFee:     big.NewInt(2),
+					EstimatedLatency: 3 * time.Second,
+					Capacity:         big.NewInt(25),
+					Success:          true,
+				},
+			},
+		},
+		testcase{
+			name: "route costing more than the max fee",
+			pathFinder: pathFinderFunc(func(value *big.Int) ([]*pfs.Route, error) {
+				return []*pfs.Route{&pfs.Route{Path: []common.Address{ourAddress, partnerAddress, targetAddress}, EstimatedFee: big.NewInt(3)}}, nil
+			}),
+			capped: true,
+			payments: []*PlannedPayment{
+				&PlannedPayment{TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: big.NewInt(20)},
+			},
+			expectedOutcomes: []*Outcome{
+				&Outcome{
+					TokenAddress:     tokenAddress,
+					TargetAddress:    targetAddress,
+					Amount:           big.NewInt(20),
+					Route:            &pfs.Route{Path: []common.Address{ourAddress, partnerAddress, targetAddress}, EstimatedFee: big.NewInt(3)},
+					EstimatedFee:     big.NewInt(3),
+					EstimatedLatency: 2 * time.Second,
+					Reason:           ReasonFeeTooHigh,
+				},
+			},
+		},
+		testcase{
+			name:       "route within the max fee",
+			pathFinder: mediated,
+			capped:     true,
+			payments: []*PlannedPayment{
+				&PlannedPayment{TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: big.NewInt(20)},
+			},
+			expectedOutcomes: []*Outcome{
+				&Outcome{
+					TokenAddress:     tokenAddress,
+					TargetAddress:    targetAddress,
+					Amount:           big.NewInt(20),
+					Route:            &pfs.Route{Path: []common.Address{ourAddress, partnerAddress, mediatorAddress, targetAddress}, EstimatedFee: big.NewInt(2)},
+					EstimatedFee:     big.NewInt(2),
+					EstimatedLatency: 3 * time.Second,
+					Capacity:         big.NewInt(25),
+					Success:          true,
+				},
+			},
+		},
+		testcase{
+			name:       "offline target",
+			pathFinder: mediated,
+			payments: []*PlannedPayment{
+				&PlannedPayment{TokenAddress: tokenAddress, TargetAddress: offlineAddress, Amount: big.NewInt(20)},
+			},
+			expectedOutcomes: []*Outcome{
+				&Outcome{
+					TokenAddress:  tokenAddress,
+					TargetAddress: offlineAddress,
+					Amount:        big.NewInt(20),
+					Reason:        ReasonTargetUnreachable,
+				},
+			},
+		},
+		testcase{
+			name: "no route found by the pathfinding service",
+			pathFinder: pathFinderFunc(func(value *big.Int) ([]*pfs.Route, error) {
+				return []*pfs.Route{}, nil
+			}),
+			payments: []*PlannedPayment{
+				&PlannedPayment{TokenAddress: tokenAddress, TargetAddress: targetAddress, Amount: big.NewInt(20)},
+			},
+			expectedOutcomes: []*Outcome{
+				&Outcome{
+					TokenAddress:  tokenAddress,
+					TargetAddress: targetAddress,
+					Amount:        big.NewInt(20),
+					Reason:        ReasonNoRoute,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/tokens/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusOK, `"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"`))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":7,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25,"total_deposit":35,"state":"opened","settle_timeout":500}]`))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/_debug/reachability/"+partnerAddress.Hex(), httpmock.NewStringResponder(http.StatusOK, `{"reachability":"reachable"}`))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/_debug/reachability/"+targetAddress.Hex(), httpmock.NewStringResponder(http.StatusNotFound, ``))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/_debug/reachability/"+offlineAddress.Hex(), httpmock.NewStringResponder(http.StatusOK, `{"reachability":"unreachable"}`))
+
+			nodeConfig := config
+			if tc.capped {
+				nodeConfig = capped
+			}
+
+			outcomes, err := NewSimulator(nodeConfig, tc.pathFinder, &SimulationOptions{HopLatency: time.Second}, http.DefaultClient).SimulateBatch(context.Background(), tc.payments)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOutcomes, outcomes)
+
+			// nothing is ever paid
+			assert.Zero(t, httpmock.GetCallCountInfo()["POST http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"])
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package payments
+
+import "sort"
+
+// SortByTime sorts the events by log time, the oldest first. The sort is stable, so
+// events logged at the same time keep their order.
+func SortByTime(events []*Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LogTime.Before(events[j].LogTime)
+	})
+}
@@ -0,0 +1,29 @@
+package payments
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortByTime(t *testing.T) {
+	var (
+		now    = time.Date(2019, time.March, 7, 18, 19, 13, 0, time.UTC)
+		events = []*Event{
+			&Event{Identifier: 1, LogTime: now.Add(time.Minute)},
+			&Event{Identifier: 2, LogTime: now},
+			&Event{Identifier: 3, LogTime: now.Add(-time.Minute)},
+			&Event{Identifier: 4, LogTime: now},
+		}
+		identifiers []int64
+	)
+
+	SortByTime(events)
+
+	for _, event := range events {
+		identifiers = append(identifiers, event.Identifier)
+	}
+
+	assert.Equal(t, []int64{3, 2, 4, 1}, identifiers)
+}
@@ -0,0 +1,237 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInsufficientCapacity is returned when the open channels of a token network do
+// not hold enough balance to cover a payment, even when split across all of them.
+var ErrInsufficientCapacity = errors.New("insufficient channel capacity for payment")
+
+// Splitter is a generic interface to send a payment that is too large for any
+// single channel by dividing it into several smaller payments, each with its own
+// payment identifier.
+type Splitter interface {
+	Split(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, baseIdentifier int64) (*SplitResult, error)
+	SplitEvenly(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, parts int, baseIdentifier int64) (*SplitResult, error)
+}
+
+// SplitPart is one of the payments that make up a split payment.
+type SplitPart struct {
+	Identifier int64
+	Amount     *big.Int
+	Payment    *Payment
+	Err        error
+}
+
+// SplitResult tracks all of the parts of a split payment and the aggregate amount
+// that was sent to the target.
+type SplitResult struct {
+	Amount    *big.Int
+	Sent      *big.Int
+	Remaining *big.Int
+	Parts     []*SplitPart
+}
+
+// Complete reports whether every part of the split payment succeeded.
+func (result *SplitResult) Complete() bool {
+	return amounts.OrZero(result.Remaining).Sign() == 0
+}
+
+// Failed returns the parts of the split payment that were not sent.
+func (result *SplitResult) Failed() []*SplitPart {
+	var failed = make([]*SplitPart, 0)
+
+	for _, part := range result.Parts {
+		if part.Err != nil {
+			failed = append(failed, part)
+		}
+	}
+
+	return failed
+}
+
+// Guidance describes what the caller should do to compensate for a split payment
+// that only partially succeeded.
+func (result *SplitResult) Guidance() string {
+	var succeeded = make([]int64, 0)
+
+	for _, part := range result.Parts {
+		if part.Err == nil {
+			succeeded = append(succeeded, part.Identifier)
+		}
+	}
+
+	switch {
+	case result.Complete():
+		return "payment complete, no action required"
+	case amounts.OrZero(result.Sent).Sign() == 0:
+		return "no funds were sent, the payment can safely be retried"
+	default:
+		return fmt.Sprintf("%s of %s was sent with identifiers %v, either send the remaining %s with new identifiers or ask the target to refund the sent parts", amounts.OrZero(result.Sent), amounts.OrZero(result.Amount), succeeded, amounts.OrZero(result.Remaining))
+	}
+}
+
+// NewSplitter creates a new default payment splitter given a Raiden node configuration
+// and an http client. The open channels of the token network are used to decide how
+// a payment is split.
+func NewSplitter(config *config.Config, httpClient *http.Client) Splitter {
+	var splitter = &defaultSplitter{
+		config:        config,
+		channelLister: channels.NewLister(config, httpClient),
+		initiator:     NewInitiator(config, httpClient),
+	}
+
+	if config != nil {
+		splitter.identifiers = config.IdentifierSource
+	}
+
+	return splitter
+}
+
+type defaultSplitter struct {
+	config        *config.Config
+	channelLister channels.Lister
+	initiator     Initiator
+	identifiers   config.IdentifierSource
+}
+
+// Split will send a payment to the target address, dividing it into several payments
+// when no single open channel has enough balance. Each part is sent with a distinct
+// identifier starting from baseIdentifier, a zero baseIdentifier will be replaced
+// with one derived from the current time, or every part taking its identifier from
+// the identifier source of the configuration if it has one.
+func (splitter *defaultSplitter) Split(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, baseIdentifier int64) (*SplitResult, error) {
+	var (
+		err           error
+		tokenChannels []*channels.Channel
+		partAmounts   []*big.Int
+	)
+
+	if amounts.OrZero(amount).Sign() <= 0 {
+		return nil, fmt.Errorf("invalid payment amount: %s", amounts.OrZero(amount))
+	}
+
+	if tokenChannels, err = splitter.channelLister.ListToken(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	if partAmounts, err = splitAmount(amount, tokenChannels); err != nil {
+		return nil, err
+	}
+
+	return splitter.send(ctx, tokenAddress, targetAddress, amount, partAmounts, baseIdentifier), nil
+}
+
+// SplitEvenly will send a payment to the target address divided into the number of
+// payments of the same amount, e.g. to stay below the largest payment a target
+// accepts, rounded with the Rounding of the token defaults of the configuration, see
+// amounts.Rounding.Split. The parts left without an amount are not sent, and the
+// identifiers are the ones of Split.
+func (splitter *defaultSplitter) SplitEvenly(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, parts int, baseIdentifier int64) (*SplitResult, error) {
+	var partAmounts = make([]*big.Int, 0, parts)
+
+	if amounts.OrZero(amount).Sign() <= 0 {
+		return nil, fmt.Errorf("invalid payment amount: %s", amounts.OrZero(amount))
+	}
+
+	if parts <= 0 {
+		return nil, fmt.Errorf("invalid number of parts: %d", parts)
+	}
+
+	for _, partAmount := range splitter.config.Defaults(tokenAddress).Rounding.Split(amount, parts) {
+		if partAmount.Sign() > 0 {
+			partAmounts = append(partAmounts, partAmount)
+		}
+	}
+
+	return splitter.send(ctx, tokenAddress, targetAddress, amount, partAmounts, baseIdentifier), nil
+}
+
+// send will send the parts of a payment of the amount in order, see Split.
+func (splitter *defaultSplitter) send(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, partAmounts []*big.Int, baseIdentifier int64) *SplitResult {
+	var result = &SplitResult{
+		Amount:    amounts.Copy(amount),
+		Sent:      new(big.Int),
+		Remaining: amounts.Copy(amount),
+		Parts:     make([]*SplitPart, 0),
+	}
+
+	if baseIdentifier == 0 && splitter.identifiers == nil {
+		baseIdentifier = time.Now().UnixNano()
+	}
+
+	for i, partAmount := range partAmounts {
+		var part = &SplitPart{
+			Identifier: baseIdentifier + int64(i),
+			Amount:     partAmount,
+		}
+
+		if baseIdentifier == 0 {
+			part.Identifier, part.Err = splitter.identifiers.NextIdentifier(ctx, tokenAddress)
+		}
+
+		if part.Err == nil {
+			if part.Payment, part.Err = splitter.initiator.InitiateWithIdentifier(ctx, tokenAddress, targetAddress, part.Amount, part.Identifier); part.Err == nil {
+				result.Sent.Add(result.Sent, part.Amount)
+				result.Remaining.Sub(result.Remaining, part.Amount)
+			}
+		}
+
+		result.Parts = append(result.Parts, part)
+	}
+
+	return result
+}
+
+// splitAmount will divide the amount into parts that each fit into the balance of an
+// open channel, largest channels first.
+func splitAmount(amount *big.Int, tokenChannels []*channels.Channel) ([]*big.Int, error) {
+	var (
+		balances    = make([]*big.Int, 0)
+		partAmounts = make([]*big.Int, 0)
+		remaining   = amounts.Copy(amount)
+	)
+
+	for _, channel := range tokenChannels {
+		if channel.State == "opened" && channel.Balance != nil && channel.Balance.Sign() > 0 {
+			balances = append(balances, channel.Balance)
+		}
+	}
+
+	sort.Slice(balances, func(i, j int) bool {
+		return balances[i].Cmp(balances[j]) > 0
+	})
+
+	for _, balance := range balances {
+		if remaining.Sign() == 0 {
+			break
+		}
+
+		part := amounts.Copy(remaining)
+
+		if balance.Cmp(remaining) < 0 {
+			part = amounts.Copy(balance)
+		}
+
+		partAmounts = append(partAmounts, part)
+		remaining.Sub(remaining, part)
+	}
+
+	if remaining.Sign() > 0 {
+		return nil, ErrInsufficientCapacity
+	}
+
+	return partAmounts, nil
+}
@@ -0,0 +1,203 @@
+package payments
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSplitter() {
+	var (
+		paymentClient *Client
+		config        = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		targetAddress = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+		amount        = int64(50000)
+		result        *SplitResult
+		err           error
+	)
+
+	paymentClient = NewClient(config, http.DefaultClient)
+
+	if result, err = paymentClient.Split(context.Background(), tokenAddress, targetAddress, big.NewInt(amount), int64(1000)); err != nil {
+		panic(fmt.Sprintf("unable to split payment: %s", err.Error()))
+	}
+
+	if !result.Complete() {
+		fmt.Println(result.Guidance())
+	}
+
+	fmt.Printf("sent %d of %d in %d parts\n", result.Sent, result.Amount, len(result.Parts))
+}
+
+func TestSplitter(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		channelsResponse = `[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","balance":300,"total_deposit":300,"state":"opened","settle_timeout":500,"reveal_timeout":30},{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":21,"partner_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","token_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","balance":500,"total_deposit":500,"state":"opened","settle_timeout":500,"reveal_timeout":30},{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":22,"partner_address":"0x82641569b2062B545431cF6D7F0A418582865ba7","token_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","balance":1000,"total_deposit":1000,"state":"closed","settle_timeout":500,"reveal_timeout":30}]`
+	)
+
+	type testcase struct {
+		name              string
+		amount            int64
+		failIdentifier    int64
+		expectedAmounts   []int64
+		expectedSent      int64
+		expectedRemaining int64
+		expectedError     error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:              "payment fits into a single channel",
+			amount:            int64(400),
+			expectedAmounts:   []int64{400},
+			expectedSent:      int64(400),
+			expectedRemaining: int64(0),
+		},
+		testcase{
+			name:              "payment split across open channels",
+			amount:            int64(700),
+			expectedAmounts:   []int64{500, 200},
+			expectedSent:      int64(700),
+			expectedRemaining: int64(0),
+		},
+		testcase{
+			name:              "partially failed split payment",
+			amount:            int64(800),
+			failIdentifier:    int64(11),
+			expectedAmounts:   []int64{500, 300},
+			expectedSent:      int64(500),
+			expectedRemaining: int64(300),
+		},
+		testcase{
+			name:          "not enough capacity in open channels",
+			amount:        int64(801),
+			expectedError: ErrInsufficientCapacity,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				err           error
+				result        *SplitResult
+				amounts       = make([]json.Number, 0)
+				splitter      = NewSplitter(config, http.DefaultClient)
+				ctx           = context.Background()
+				tokenAddress  = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+				targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+			)
+
+			httpmock.Activate()
+			defer httpmock.Deactivate()
+
+			httpmock.RegisterResponder(
+				"GET",
+				"http://localhost:5001/api/v1/channels/0x2a65Aca4D5fC5B5C859090a6c34d164135398226",
+				httpmock.NewStringResponder(http.StatusOK, channelsResponse),
+			)
+
+			httpmock.RegisterResponder(
+				"POST",
+				"http://localhost:5001/api/v1/payments/0x2a65Aca4D5fC5B5C859090a6c34d164135398226/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+				func(request *http.Request) (*http.Response, error) {
+					var paymentRequest = &initiatePaymentRequest{}
+
+					if err := json.NewDecoder(request.Body).Decode(paymentRequest); err != nil {
+						return nil, err
+					}
+
+					if paymentRequest.Identifier == tc.failIdentifier {
+						return httpmock.NewStringResponse(http.StatusConflict, ``), nil
+					}
+
+					amounts = append(amounts, paymentRequest.Amount)
+
+					return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"amount":%s,"identifier":%d}`, paymentRequest.Amount, paymentRequest.Identifier)), nil
+				},
+			)
+
+			result, err = splitter.Split(ctx, tokenAddress, targetAddress, big.NewInt(tc.amount), int64(10))
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSent, result.Sent.Int64())
+			assert.Equal(t, tc.expectedRemaining, result.Remaining.Int64())
+			assert.Equal(t, tc.expectedRemaining == 0, result.Complete())
+			assert.Len(t, result.Parts, len(tc.expectedAmounts))
+
+			for i, part := range result.Parts {
+				assert.Equal(t, tc.expectedAmounts[i], part.Amount.Int64())
+				assert.Equal(t, int64(10+i), part.Identifier)
+			}
+
+			if tc.failIdentifier != 0 {
+				assert.Len(t, result.Failed(), 1)
+				assert.Contains(t, result.Guidance(), "remaining 300")
+			}
+		})
+	}
+}
+
+func TestSplitterSplitEvenly(t *testing.T) {
+	var (
+		tokenAddress  = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		config        = &config.Config{
+			Host:          "http://localhost:5001",
+			APIVersion:    "v1",
+			TokenDefaults: map[common.Address]*config.TokenDefaults{tokenAddress: &config.TokenDefaults{Rounding: amounts.RoundCeil}},
+		}
+		sent     = make([]string, 0)
+		splitter = NewSplitter(config, http.DefaultClient)
+		ctx      = context.Background()
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"POST",
+		"http://localhost:5001/api/v1/payments/0x2a65Aca4D5fC5B5C859090a6c34d164135398226/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		func(request *http.Request) (*http.Response, error) {
+			var paymentRequest = &initiatePaymentRequest{}
+
+			if err := json.NewDecoder(request.Body).Decode(paymentRequest); err != nil {
+				return nil, err
+			}
+
+			sent = append(sent, paymentRequest.Amount.String())
+
+			return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"amount":%s,"identifier":%d}`, paymentRequest.Amount, paymentRequest.Identifier)), nil
+		},
+	)
+
+	result, err := splitter.SplitEvenly(ctx, tokenAddress, targetAddress, big.NewInt(5), 4, int64(10))
+	require.NoError(t, err)
+	assert.True(t, result.Complete())
+	assert.Equal(t, []string{"2", "2", "1"}, sent, "the part left without an amount is not sent")
+	assert.Equal(t, int64(12), result.Parts[2].Identifier)
+
+	_, err = splitter.SplitEvenly(ctx, tokenAddress, targetAddress, big.NewInt(5), 0, int64(10))
+	assert.EqualError(t, err, "invalid number of parts: 0")
+}
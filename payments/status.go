@@ -0,0 +1,193 @@
+package payments
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultHistoryPage is the number of events of a page of the payment history of a
+// token when no size is given.
+const DefaultHistoryPage = 500
+
+// Status is the outcome of a payment as told by the payment events of its
+// identifier.
+type Status string
+
+const (
+	// StatusUnknown is an identifier without any payment event, a payment never
+	// attempted or still in flight.
+	StatusUnknown Status = "unknown"
+	// StatusSent is a payment the Raiden node has sent successfully.
+	StatusSent Status = "sent"
+	// StatusReceived is a payment the Raiden node has received.
+	StatusReceived Status = "received"
+	// StatusFailed is a payment the Raiden node was unable to send, and did not send
+	// since with the same identifier.
+	StatusFailed Status = "failed"
+)
+
+// settled tells whether no later event can change the status.
+func (status Status) settled() bool {
+	return status == StatusSent || status == StatusReceived
+}
+
+// EventSource is a local copy of the payment events of the node, e.g. an
+// events.Mirror, looked up before the node.
+type EventSource interface {
+	PaymentEvents(tokenAddress common.Address) ([]*Event, error)
+}
+
+// HistoryLister lists the payment events of a token with every partner, a page at a
+// time: limit events after the first offset ones.
+type HistoryLister interface {
+	ListHistory(ctx context.Context, tokenAddress common.Address, limit, offset int) ([]*Event, error)
+}
+
+// StatusResolver resolves the outcome of many payments of a token at once, e.g. for a
+// job verifying a batch of payouts.
+type StatusResolver interface {
+	StatusByIdentifiers(ctx context.Context, tokenAddress common.Address, identifiers []int64) (map[int64]Status, error)
+}
+
+// NewHistoryLister creates a lister of the payment history of the tokens of the node.
+func NewHistoryLister(config *config.Config, httpClient *http.Client) HistoryLister {
+	return &defaultHistoryLister{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultHistoryLister struct {
+	baseClient *util.BaseClient
+}
+
+func (lister *defaultHistoryLister) ListHistory(ctx context.Context, tokenAddress common.Address, limit, offset int) ([]*Event, error) {
+	var (
+		err           error
+		events        = make([]*event, 0)
+		paymentEvents = make([]*Event, 0)
+		query         = url.Values{}
+
+		requestURL *url.URL
+	)
+
+	if requestURL, err = lister.baseClient.Endpoint("payments/%s", tokenAddress.Hex()); err != nil {
+		return nil, err
+	}
+
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+
+	if offset > 0 {
+		query.Set("offset", strconv.Itoa(offset))
+	}
+
+	if len(query) > 0 {
+		requestURL.RawQuery = query.Encode()
+	}
+
+	if err = lister.baseClient.Call(ctx, "GET", requestURL, nil, &events); err != nil {
+		return nil, err
+	}
+
+	for _, eventResponse := range events {
+		var event *Event
+
+		if event, err = eventResponse.toEvent(); err != nil {
+			return nil, err
+		}
+
+		paymentEvents = append(paymentEvents, event)
+	}
+
+	return paymentEvents, nil
+}
+
+// NewStatusResolver creates a resolver of the outcome of payments through the payment
+// history of the node, looking the identifiers up in the source first when it is not
+// nil.
+func NewStatusResolver(config *config.Config, httpClient *http.Client, source EventSource) StatusResolver {
+	return &defaultStatusResolver{
+		history:  NewHistoryLister(config, httpClient),
+		source:   source,
+		pageSize: DefaultHistoryPage,
+	}
+}
+
+type defaultStatusResolver struct {
+	history  HistoryLister
+	source   EventSource
+	pageSize int
+}
+
+// StatusByIdentifiers will return the status of the payments of the token with every
+// identifier, StatusUnknown for the ones without events. The identifiers the source
+// tells were sent or received are not looked up in the node, whose history is then
+// listed a page at a time until every identifier is sent or received, or the history
+// is exhausted, so that a failed attempt followed by a successful one resolves as
+// sent.
+func (resolver *defaultStatusResolver) StatusByIdentifiers(ctx context.Context, tokenAddress common.Address, identifiers []int64) (map[int64]Status, error) {
+	var (
+		statuses  = make(map[int64]Status, len(identifiers))
+		remaining = 0
+		observe   = func(events []*Event) {
+			for _, event := range events {
+				status, ok := statuses[event.Identifier]
+				if !ok || status.settled() {
+					continue
+				}
+
+				switch event.EventName {
+				case EventPaymentSentSuccess:
+					statuses[event.Identifier] = StatusSent
+					remaining--
+				case EventPaymentReceivedSuccess:
+					statuses[event.Identifier] = StatusReceived
+					remaining--
+				case EventPaymentSentFailed:
+					statuses[event.Identifier] = StatusFailed
+				}
+			}
+		}
+	)
+
+	for _, identifier := range identifiers {
+		if _, ok := statuses[identifier]; !ok {
+			statuses[identifier] = StatusUnknown
+			remaining++
+		}
+	}
+
+	if resolver.source != nil && remaining > 0 {
+		events, err := resolver.source.PaymentEvents(tokenAddress)
+		if err != nil {
+			return nil, err
+		}
+
+		observe(events)
+	}
+
+	for offset := 0; remaining > 0; offset += resolver.pageSize {
+		events, err := resolver.history.ListHistory(ctx, tokenAddress, resolver.pageSize, offset)
+		if err != nil {
+			return nil, err
+		}
+
+		observe(events)
+
+		if len(events) < resolver.pageSize {
+			break
+		}
+	}
+
+	return statuses, nil
+}
@@ -0,0 +1,160 @@
+package payments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleStatusResolver() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		identifiers  = []int64{1001, 1002, 1003}
+	)
+
+	statuses, err := NewClient(config, http.DefaultClient).StatusByIdentifiers(context.Background(), tokenAddress, identifiers)
+	if err != nil {
+		panic(fmt.Sprintf("unable to resolve the payouts: %s", err.Error()))
+	}
+
+	for _, identifier := range identifiers {
+		if statuses[identifier] != StatusSent {
+			fmt.Printf("payout %d is %s\n", identifier, statuses[identifier])
+		}
+	}
+}
+
+type staticSource struct {
+	events []*Event
+	err    error
+}
+
+func (source *staticSource) PaymentEvents(tokenAddress common.Address) ([]*Event, error) {
+	return source.events, source.err
+}
+
+func TestStatusResolver(t *testing.T) {
+	type testcase struct {
+		name             string
+		source           EventSource
+		identifiers      []int64
+		expectedStatuses map[int64]Status
+		expectedPages    []string
+		expectedError    string
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		// the history of the token, two events a page
+		history = []string{
+			`{"event":"EventPaymentSentFailed","identifier":1,"log_time":"2018-10-30T07:03:52.193Z"}`,
+			`{"event":"EventPaymentSentSuccess","amount":5,"identifier":2,"target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","log_time":"2018-10-30T07:04:52.193Z"}`,
+			`{"event":"EventPaymentReceivedSuccess","amount":7,"identifier":3,"initiator":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","log_time":"2018-10-30T07:05:52.193Z"}`,
+			`{"event":"EventPaymentSentSuccess","amount":5,"identifier":1,"target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","log_time":"2018-10-30T07:06:52.193Z"}`,
+			`{"event":"EventPaymentSentFailed","identifier":4,"log_time":"2018-10-30T07:07:52.193Z"}`,
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:             "every page until resolved",
+			identifiers:      []int64{1, 3},
+			expectedStatuses: map[int64]Status{1: StatusSent, 3: StatusReceived},
+			expectedPages:    []string{"limit=2", "limit=2&offset=2"},
+		},
+		testcase{
+			name:             "failed and unknown payments",
+			identifiers:      []int64{2, 4, 5, 2},
+			expectedStatuses: map[int64]Status{2: StatusSent, 4: StatusFailed, 5: StatusUnknown},
+			expectedPages:    []string{"limit=2", "limit=2&offset=2", "limit=2&offset=4"},
+		},
+		testcase{
+			name: "resolved by the source",
+			source: &staticSource{events: []*Event{
+				&Event{EventName: EventPaymentSentSuccess, Identifier: 1},
+				&Event{EventName: EventPaymentReceivedSuccess, Identifier: 3},
+			}},
+			identifiers:      []int64{1, 3},
+			expectedStatuses: map[int64]Status{1: StatusSent, 3: StatusReceived},
+			expectedPages:    []string{},
+		},
+		testcase{
+			name:             "failed in the source",
+			source:           &staticSource{events: []*Event{&Event{EventName: EventPaymentSentFailed, Identifier: 1}}},
+			identifiers:      []int64{1},
+			expectedStatuses: map[int64]Status{1: StatusSent},
+			expectedPages:    []string{"limit=2", "limit=2&offset=2"},
+		},
+		testcase{
+			name:          "failing source",
+			source:        &staticSource{err: errors.New("disk full")},
+			identifiers:   []int64{1},
+			expectedPages: []string{},
+			expectedError: "disk full",
+		},
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				resolver = NewStatusResolver(config, http.DefaultClient, tc.source).(*defaultStatusResolver)
+				pages    = make([]string, 0)
+			)
+
+			resolver.pageSize = 2
+
+			httpmock.Reset()
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", func(request *http.Request) (*http.Response, error) {
+				var (
+					limit, _  = strconv.Atoi(request.URL.Query().Get("limit"))
+					offset, _ = strconv.Atoi(request.URL.Query().Get("offset"))
+					page      = "["
+				)
+
+				pages = append(pages, request.URL.RawQuery)
+
+				for i := offset; i < offset+limit && i < len(history); i++ {
+					if i > offset {
+						page += ","
+					}
+
+					page += history[i]
+				}
+
+				return httpmock.NewStringResponse(http.StatusOK, page+"]"), nil
+			})
+
+			statuses, err := resolver.StatusByIdentifiers(context.Background(), tokenAddress, tc.identifiers)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.Nil(t, statuses)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedStatuses, statuses)
+			}
+
+			assert.Equal(t, tc.expectedPages, pages)
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package payments
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultWatchInterval is the time between two listings of the payment events
+	// while watching a payment that timed out, when the options give none.
+	DefaultWatchInterval = 5 * time.Second
+	// DefaultWatchFor is how long a payment that timed out is watched for, when the
+	// options give none, long enough for the lock of a payment to expire.
+	DefaultWatchFor = 30 * time.Minute
+)
+
+// TimeoutError is returned when the node did not answer a payment in time. The
+// payment is not cancelled: the node keeps sending it, see TimeoutSender.
+type TimeoutError struct {
+	TokenAddress  common.Address
+	TargetAddress common.Address
+	Identifier    int64
+	Timeout       time.Duration
+}
+
+func (err *TimeoutError) Error() string {
+	return fmt.Sprintf("gave up waiting for payment %d to %s after %s, it may still succeed", err.Identifier, err.TargetAddress.Hex(), err.Timeout)
+}
+
+// Code returns raidenerrors.CodePaymentTimeout, see raidenerrors.HasCode.
+func (err *TimeoutError) Code() raidenerrors.Code {
+	return raidenerrors.CodePaymentTimeout
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *TimeoutError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// EventualOutcome is the outcome of a payment that timed out, found by watching its
+// identifier: the event the node reported the payment succeeded or failed with, or
+// the error watching it ended with, e.g. context.DeadlineExceeded when the node
+// reported neither within WatchFor.
+type EventualOutcome struct {
+	Identifier int64
+	Succeeded  bool
+	Event      *Event
+	Err        error
+}
+
+// TimeoutOptions configures how a payment that timed out is watched: its events are
+// listed every Interval for up to WatchFor, measured on Clock, the Clock of the
+// configuration when nil.
+type TimeoutOptions struct {
+	Interval time.Duration
+	WatchFor time.Duration
+	Clock    clock.Clock
+}
+
+// TimeoutSender is a generic interface to send a payment while bounding how long the
+// caller waits for it. Since the node keeps sending a payment the caller gave up
+// waiting for, its eventual outcome is reported to the callback, telling "gave up
+// waiting" apart from "actually failed".
+type TimeoutSender interface {
+	SendWithTimeout(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64, timeout time.Duration, eventual func(outcome *EventualOutcome)) (*Payment, error)
+}
+
+// NewTimeoutSender creates a new default timeout sender given a Raiden node
+// configuration, an http client and the options payments that timed out are watched
+// with, nil for the defaults.
+func NewTimeoutSender(config *config.Config, httpClient *http.Client, options *TimeoutOptions) TimeoutSender {
+	var sender = &defaultTimeoutSender{
+		initiator: NewInitiator(config, httpClient),
+		lister:    NewLister(config, httpClient),
+		interval:  DefaultWatchInterval,
+		watchFor:  DefaultWatchFor,
+		clock:     config.Time(),
+	}
+
+	if config != nil {
+		sender.identifiers = config.IdentifierSource
+	}
+
+	if options != nil && options.Interval > 0 {
+		sender.interval = options.Interval
+	}
+
+	if options != nil && options.WatchFor > 0 {
+		sender.watchFor = options.WatchFor
+	}
+
+	if options != nil && options.Clock != nil {
+		sender.clock = options.Clock
+	}
+
+	return sender
+}
+
+type defaultTimeoutSender struct {
+	initiator   Initiator
+	lister      Lister
+	identifiers config.IdentifierSource
+	interval    time.Duration
+	watchFor    time.Duration
+	clock       clock.Clock
+}
+
+// SendWithTimeout will send the payment, returning a *TimeoutError when the node did
+// not answer within the timeout. The payment is then watched in the background and
+// its eventual outcome reported to the callback, which may be nil, unless ctx is
+// done first. Payments that did not time out, succeeded or failed, are not reported.
+// An identifier is generated when none is given, since a payment can only be watched
+// by its identifier, by the identifier source of the configuration if it has one.
+func (sender *defaultTimeoutSender) SendWithTimeout(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64, timeout time.Duration, eventual func(outcome *EventualOutcome)) (*Payment, error) {
+	var err error
+
+	switch {
+	case identifier != 0:
+	case sender.identifiers != nil:
+		if identifier, err = sender.identifiers.NextIdentifier(ctx, tokenAddress); err != nil {
+			return nil, err
+		}
+	default:
+		identifier = randomIdentifier()
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	payment, err := sender.initiator.InitiateWithIdentifier(sendCtx, tokenAddress, targetAddress, amount, identifier)
+
+	// only the timeout is given up on, a cancelled ctx or a failure is returned as is
+	if err == nil || sendCtx.Err() != context.DeadlineExceeded || ctx.Err() != nil {
+		return payment, err
+	}
+
+	if eventual != nil {
+		go func() {
+			eventual(sender.watch(ctx, tokenAddress, targetAddress, identifier))
+		}()
+	}
+
+	return nil, &TimeoutError{
+		TokenAddress:  tokenAddress,
+		TargetAddress: targetAddress,
+		Identifier:    identifier,
+		Timeout:       timeout,
+	}
+}
+
+// watch lists the payment events with the target until one of them tells the
+// payment with the identifier succeeded or failed.
+func (sender *defaultTimeoutSender) watch(ctx context.Context, tokenAddress, targetAddress common.Address, identifier int64) *EventualOutcome {
+	var (
+		outcome  = &EventualOutcome{Identifier: identifier}
+		deadline = sender.clock.After(sender.watchFor)
+	)
+
+	// the listings are bounded by the system time too, should the node hang
+	ctx, cancel := context.WithTimeout(ctx, sender.watchFor)
+	defer cancel()
+
+	for {
+		// listing errors are retried until the payment is watched for long enough
+		if events, err := sender.lister.List(ctx, tokenAddress, targetAddress); err == nil {
+			for _, event := range events {
+				if event.Identifier != identifier {
+					continue
+				}
+
+				switch event.EventName {
+				case EventPaymentSentSuccess:
+					outcome.Succeeded, outcome.Event = true, event
+					return outcome
+				case EventPaymentSentFailed:
+					outcome.Event = event
+					return outcome
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			outcome.Err = ctx.Err()
+			return outcome
+		case <-deadline:
+			outcome.Err = context.DeadlineExceeded
+			return outcome
+		case <-sender.clock.After(sender.interval):
+		}
+	}
+}
+
+// randomIdentifier returns a random positive payment identifier.
+func randomIdentifier() int64 {
+	var random = make([]byte, 8)
+
+	// the reader of crypto/rand does not fail on the supported platforms
+	_, _ = rand.Read(random)
+
+	return int64(binary.BigEndian.Uint64(random)>>1) | 1
+}
@@ -0,0 +1,266 @@
+package payments
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleTimeoutSender() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		sender        = NewTimeoutSender(config, http.DefaultClient, nil)
+		tokenAddress  = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	payment, err := sender.SendWithTimeout(context.Background(), tokenAddress, targetAddress, big.NewInt(100), 42, 10*time.Second, func(outcome *EventualOutcome) {
+		// the node kept sending the payment after the caller gave up waiting
+		fmt.Println("payment", outcome.Identifier, "eventually succeeded:", outcome.Succeeded)
+	})
+
+	switch {
+	case raidenerrors.HasCode(err, raidenerrors.CodePaymentTimeout):
+		fmt.Println("still pending:", err.Error())
+	case err != nil:
+		panic(fmt.Sprintf("payment failed: %s", err.Error()))
+	default:
+		fmt.Println("paid:", payment.Identifier)
+	}
+}
+
+func TestSendWithTimeout(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		paymentURL    = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		paymentJSON   = `{"initiator_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","amount":10,"identifier":42}`
+		options       = &TimeoutOptions{Interval: time.Millisecond, WatchFor: 100 * time.Millisecond}
+	)
+
+	// slowPayment answers the payment after the timeout of the tests
+	slowPayment := func(request *http.Request) (*http.Response, error) {
+		select {
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		case <-time.After(time.Second):
+		}
+
+		return httpmock.NewStringResponse(http.StatusOK, paymentJSON), nil
+	}
+
+	type testcase struct {
+		name             string
+		paymentResponder httpmock.Responder
+		eventsJSON       string
+		expectedPayment  bool
+		expectedCode     raidenerrors.Code
+		expectedOutcome  *EventualOutcome
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:             "answered in time",
+			paymentResponder: httpmock.NewStringResponder(http.StatusOK, paymentJSON),
+			expectedPayment:  true,
+		},
+		testcase{
+			name:             "failed in time",
+			paymentResponder: httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Payment exceeds the capacity of the channel, insufficient capacity"}`),
+			expectedCode:     raidenerrors.CodeInsufficientBalance,
+		},
+		testcase{
+			name:             "timed out then succeeded",
+			paymentResponder: slowPayment,
+			eventsJSON:       `[{"event":"EventPaymentSentSuccess","amount":10,"target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","identifier":42,"log_time":"2019-03-07T18:19:13.976"}]`,
+			expectedCode:     raidenerrors.CodePaymentTimeout,
+			expectedOutcome:  &EventualOutcome{Identifier: 42, Succeeded: true},
+		},
+		testcase{
+			name:             "timed out then failed",
+			paymentResponder: slowPayment,
+			eventsJSON:       `[{"event":"EventPaymentSentFailed","identifier":41,"log_time":"2019-03-07T18:19:13.976"},{"event":"EventPaymentSentFailed","identifier":42,"log_time":"2019-03-07T18:19:13.976"}]`,
+			expectedCode:     raidenerrors.CodePaymentTimeout,
+			expectedOutcome:  &EventualOutcome{Identifier: 42},
+		},
+		testcase{
+			name:             "timed out and never reported",
+			paymentResponder: slowPayment,
+			eventsJSON:       `[]`,
+			expectedCode:     raidenerrors.CodePaymentTimeout,
+			expectedOutcome:  &EventualOutcome{Identifier: 42, Err: context.DeadlineExceeded},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var outcomes = make(chan *EventualOutcome, 1)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("POST", paymentURL, tc.paymentResponder)
+			httpmock.RegisterResponder("GET", paymentURL, httpmock.NewStringResponder(http.StatusOK, tc.eventsJSON))
+
+			payment, err := NewTimeoutSender(config, http.DefaultClient, options).SendWithTimeout(context.Background(), tokenAddress, targetAddress, big.NewInt(10), 42, 20*time.Millisecond, func(outcome *EventualOutcome) {
+				outcomes <- outcome
+			})
+
+			if tc.expectedPayment {
+				require.NoError(t, err)
+				assert.Equal(t, int64(42), payment.Identifier)
+			} else {
+				assert.Nil(t, payment)
+				assert.True(t, raidenerrors.HasCode(err, tc.expectedCode), "%v", err)
+			}
+
+			if tc.expectedOutcome == nil {
+				select {
+				case outcome := <-outcomes:
+					t.Errorf("unexpected outcome %+v", outcome)
+				case <-time.After(20 * time.Millisecond):
+				}
+
+				return
+			}
+
+			outcome := <-outcomes
+
+			assert.Equal(t, tc.expectedOutcome.Identifier, outcome.Identifier)
+			assert.Equal(t, tc.expectedOutcome.Succeeded, outcome.Succeeded)
+			assert.Equal(t, tc.expectedOutcome.Err, outcome.Err)
+			assert.Equal(t, tc.expectedOutcome.Err == nil, outcome.Event != nil)
+		})
+	}
+}
+
+func TestSendWithTimeoutClock(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress  = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		targetAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		paymentURL    = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+	)
+
+	type testcase struct {
+		name            string
+		advance         []time.Duration
+		expectedListed  int
+		expectedOutcome *EventualOutcome
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:            "reported at the second listing",
+			advance:         []time.Duration{time.Minute},
+			expectedListed:  2,
+			expectedOutcome: &EventualOutcome{Identifier: 42, Succeeded: true},
+		},
+		testcase{
+			name:            "watched for long enough",
+			advance:         []time.Duration{time.Hour},
+			expectedListed:  1,
+			expectedOutcome: &EventualOutcome{Identifier: 42, Err: context.DeadlineExceeded},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mutex    sync.Mutex
+				listed   int
+				manual   = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+				options  = &TimeoutOptions{Interval: time.Minute, WatchFor: time.Hour, Clock: manual}
+				outcomes = make(chan *EventualOutcome, 1)
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("POST", paymentURL, func(request *http.Request) (*http.Response, error) {
+				<-request.Context().Done()
+				return nil, request.Context().Err()
+			})
+
+			// the payment is only reported by the second listing
+			httpmock.RegisterResponder("GET", paymentURL, func(request *http.Request) (*http.Response, error) {
+				mutex.Lock()
+				defer mutex.Unlock()
+
+				if listed++; listed == 1 {
+					return httpmock.NewStringResponse(http.StatusOK, `[]`), nil
+				}
+
+				return httpmock.NewStringResponse(http.StatusOK, `[{"event":"EventPaymentSentSuccess","amount":10,"identifier":42,"log_time":"2019-03-07T18:19:13.976"}]`), nil
+			})
+
+			_, err := NewTimeoutSender(config, http.DefaultClient, options).SendWithTimeout(context.Background(), tokenAddress, targetAddress, big.NewInt(10), 42, 10*time.Millisecond, func(outcome *EventualOutcome) {
+				outcomes <- outcome
+			})
+			assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodePaymentTimeout), "%v", err)
+
+			// the watch waits for both its deadline and its next listing
+			for _, d := range tc.advance {
+				manual.BlockUntil(2)
+				manual.Advance(d)
+			}
+
+			outcome := <-outcomes
+
+			assert.Equal(t, tc.expectedOutcome.Succeeded, outcome.Succeeded)
+			assert.Equal(t, tc.expectedOutcome.Err, outcome.Err)
+
+			mutex.Lock()
+			assert.Equal(t, tc.expectedListed, listed)
+			mutex.Unlock()
+		})
+	}
+}
+
+func TestTimeoutErrorIdentifier(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		identifier int64
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", func(request *http.Request) (*http.Response, error) {
+		<-request.Context().Done()
+		return nil, request.Context().Err()
+	})
+
+	_, err := NewTimeoutSender(config, http.DefaultClient, nil).SendWithTimeout(context.Background(), common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"), common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"), big.NewInt(10), 0, time.Millisecond, nil)
+
+	// the identifier generated for the payment is how the caller can follow it
+	require.IsType(t, &TimeoutError{}, err)
+	identifier = err.(*TimeoutError).Identifier
+	assert.True(t, identifier > 0)
+	assert.Contains(t, err.Error(), fmt.Sprintf("gave up waiting for payment %d", identifier))
+}
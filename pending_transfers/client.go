@@ -4,20 +4,24 @@ import (
 	"net/http"
 
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
 )
 
 var (
-	_ Lister = &Client{}
+	_ Lister         = &Client{}
+	_ util.RawCaller = &Client{}
 )
 
 // NewClient allows for all Pending Transfer operations to be performed.
 func NewClient(config *config.Config, httpClient *http.Client) *Client {
 	return &Client{
-		Lister: NewLister(config, httpClient),
+		Lister:    NewLister(config, httpClient),
+		RawCaller: util.NewRawCaller(config, httpClient),
 	}
 }
 
 // Client is a holder for the Pending Transfers lister.
 type Client struct {
 	Lister
+	util.RawCaller
 }
@@ -0,0 +1,162 @@
+package pendingtransfers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The roles of the node in a pending transfer.
+const (
+	RoleInitiator = "initiator"
+	RoleMediator  = "mediator"
+	RoleTarget    = "target"
+)
+
+// The directions of the liquidity locked by a pending transfer, see Exposure.
+const (
+	// DirectionOutgoing is liquidity of the node locked towards a partner, which the
+	// node loses once the transfer completes, as the initiator of a payment.
+	DirectionOutgoing = "outgoing"
+	// DirectionIncoming is liquidity of a partner locked towards the node, which the
+	// node receives once the transfer completes, as the target of a payment.
+	DirectionIncoming = "incoming"
+	// DirectionMediated is liquidity locked both towards and from the node, as the
+	// mediator of a payment between two of its partners.
+	DirectionMediated = "mediated"
+)
+
+// Exposure is a pending transfer annotated for a report of the liquidity locked by a
+// node, e.g. shared with a risk team: the direction the liquidity is locked in, the
+// amount in whole tokens, the counterparty of the node and a reference to the
+// channel holding the lock, "<token network>/<channel identifier>". Addresses are
+// checksummed hex strings, as reports show them.
+type Exposure struct {
+	PaymentIdentifier int64       `json:"payment_identifier"`
+	Role              string      `json:"role"`
+	Direction         string      `json:"direction"`
+	TokenAddress      string      `json:"token_address"`
+	TokenSymbol       string      `json:"token_symbol,omitempty"`
+	LockedAmount      json.Number `json:"locked_amount"`
+	Locked            string      `json:"locked"`
+	TransferredAmount json.Number `json:"transferred_amount"`
+	Initiator         string      `json:"initiator"`
+	Target            string      `json:"target"`
+	// Counterparty is the other end of the payment from the node, empty for mediated
+	// transfers whose ends are the Initiator and Target.
+	Counterparty      string `json:"counterparty"`
+	TokenNetwork      string `json:"token_network"`
+	ChannelIdentifier int64  `json:"channel_identifier"`
+	Channel           string `json:"channel"`
+}
+
+// exposureColumns are the columns of ExportCSV, in the order of the fields of an
+// Exposure.
+var exposureColumns = []string{
+	"payment_identifier",
+	"role",
+	"direction",
+	"token_address",
+	"token_symbol",
+	"locked_amount",
+	"locked",
+	"transferred_amount",
+	"initiator",
+	"target",
+	"counterparty",
+	"token_network",
+	"channel_identifier",
+	"channel",
+}
+
+// Annotate returns the exposures of the transfers, in order, with the amounts in whole
+// tokens of the tokens known, i.e. in the smallest unit of the others.
+func Annotate(transfers []*Transfer, tokens map[common.Address]*amounts.Token) []*Exposure {
+	var exposures = make([]*Exposure, 0, len(transfers))
+
+	for _, transfer := range transfers {
+		var (
+			token    = tokens[transfer.TokenAddress]
+			exposure = &Exposure{
+				PaymentIdentifier: transfer.PaymentIdentifier,
+				Role:              transfer.Role,
+				TokenAddress:      transfer.TokenAddress.Hex(),
+				LockedAmount:      amounts.Number(transfer.LockedAmount),
+				Locked:            token.Format(transfer.LockedAmount),
+				TransferredAmount: amounts.Number(transfer.TransferredAmount),
+				Initiator:         transfer.Initiator.Hex(),
+				Target:            transfer.Target.Hex(),
+				TokenNetwork:      transfer.TokenNetworkIdentifier.Hex(),
+				ChannelIdentifier: transfer.ChannelIdentifier,
+				Channel:           fmt.Sprintf("%s/%d", transfer.TokenNetworkIdentifier.Hex(), transfer.ChannelIdentifier),
+			}
+		)
+
+		if token != nil {
+			exposure.TokenSymbol = token.Symbol
+		}
+
+		switch transfer.Role {
+		case RoleInitiator:
+			exposure.Direction, exposure.Counterparty = DirectionOutgoing, transfer.Target.Hex()
+		case RoleTarget:
+			exposure.Direction, exposure.Counterparty = DirectionIncoming, transfer.Initiator.Hex()
+		case RoleMediator:
+			exposure.Direction = DirectionMediated
+		}
+
+		exposures = append(exposures, exposure)
+	}
+
+	return exposures
+}
+
+// ExportCSV writes the exposures of the transfers as CSV with a header row, see
+// Annotate.
+func ExportCSV(writer io.Writer, transfers []*Transfer, tokens map[common.Address]*amounts.Token) error {
+	var csvWriter = csv.NewWriter(writer)
+
+	if err := csvWriter.Write(exposureColumns); err != nil {
+		return err
+	}
+
+	for _, exposure := range Annotate(transfers, tokens) {
+		if err := csvWriter.Write([]string{
+			strconv.FormatInt(exposure.PaymentIdentifier, 10),
+			exposure.Role,
+			exposure.Direction,
+			exposure.TokenAddress,
+			exposure.TokenSymbol,
+			exposure.LockedAmount.String(),
+			exposure.Locked,
+			exposure.TransferredAmount.String(),
+			exposure.Initiator,
+			exposure.Target,
+			exposure.Counterparty,
+			exposure.TokenNetwork,
+			strconv.FormatInt(exposure.ChannelIdentifier, 10),
+			exposure.Channel,
+		}); err != nil {
+			return err
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// ExportJSON writes the exposures of the transfers as an indented JSON array, see
+// Annotate.
+func ExportJSON(writer io.Writer, transfers []*Transfer, tokens map[common.Address]*amounts.Token) error {
+	var encoder = json.NewEncoder(writer)
+
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(Annotate(transfers, tokens))
+}
@@ -0,0 +1,102 @@
+package pendingtransfers
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	exportToken   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	exportNetwork = address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2")
+	ourAddress    = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+	partner       = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	exportTokens  = map[common.Address]*amounts.Token{exportToken: &amounts.Token{Symbol: "DAI", Decimals: 18}}
+	exported      = []*Transfer{
+		&Transfer{
+			ChannelIdentifier:      20,
+			Initiator:              ourAddress,
+			LockedAmount:           big.NewInt(1500000000000000000),
+			PaymentIdentifier:      1,
+			Role:                   RoleInitiator,
+			Target:                 partner,
+			TokenAddress:           exportToken,
+			TokenNetworkIdentifier: exportNetwork,
+			TransferredAmount:      big.NewInt(0),
+		},
+		&Transfer{
+			ChannelIdentifier:      21,
+			Initiator:              partner,
+			LockedAmount:           big.NewInt(7),
+			PaymentIdentifier:      2,
+			Role:                   RoleMediator,
+			Target:                 exportToken,
+			TokenAddress:           partner,
+			TokenNetworkIdentifier: exportNetwork,
+		},
+	}
+)
+
+func TestAnnotate(t *testing.T) {
+	exposures := Annotate(exported, exportTokens)
+
+	require.Len(t, exposures, 2)
+	assert.Equal(t, &Exposure{
+		PaymentIdentifier: 1,
+		Role:              RoleInitiator,
+		Direction:         DirectionOutgoing,
+		TokenAddress:      exportToken.Hex(),
+		TokenSymbol:       "DAI",
+		LockedAmount:      "1500000000000000000",
+		Locked:            "1.5 DAI",
+		TransferredAmount: "0",
+		Initiator:         ourAddress.Hex(),
+		Target:            partner.Hex(),
+		Counterparty:      partner.Hex(),
+		TokenNetwork:      exportNetwork.Hex(),
+		ChannelIdentifier: 20,
+		Channel:           "0xE5637F0103794C7e05469A9964E4563089a5E6f2/20",
+	}, exposures[0])
+
+	// the amounts of an unknown token are in its smallest unit
+	assert.Equal(t, DirectionMediated, exposures[1].Direction)
+	assert.Empty(t, exposures[1].Counterparty)
+	assert.Empty(t, exposures[1].TokenSymbol)
+	assert.Equal(t, "7", exposures[1].Locked)
+}
+
+func TestExportCSV(t *testing.T) {
+	var buffer bytes.Buffer
+
+	require.NoError(t, ExportCSV(&buffer, exported[:1], exportTokens))
+	assert.Equal(t, `payment_identifier,role,direction,token_address,token_symbol,locked_amount,locked,transferred_amount,initiator,target,counterparty,token_network,channel_identifier,channel
+1,initiator,outgoing,0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8,DAI,1500000000000000000,1.5 DAI,0,0x2a65Aca4D5fC5B5C859090a6c34d164135398226,0x61C808D82A3Ac53231750daDc13c777b59310bD9,0x61C808D82A3Ac53231750daDc13c777b59310bD9,0xE5637F0103794C7e05469A9964E4563089a5E6f2,20,0xE5637F0103794C7e05469A9964E4563089a5E6f2/20
+`, buffer.String())
+}
+
+func TestExportJSON(t *testing.T) {
+	var buffer bytes.Buffer
+
+	require.NoError(t, ExportJSON(&buffer, exported[1:], nil))
+	assert.JSONEq(t, `[{
+		"payment_identifier": 2,
+		"role": "mediator",
+		"direction": "mediated",
+		"token_address": "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		"locked_amount": 7,
+		"locked": "7",
+		"transferred_amount": 0,
+		"initiator": "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		"target": "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+		"counterparty": "",
+		"token_network": "0xE5637F0103794C7e05469A9964E4563089a5E6f2",
+		"channel_identifier": 21,
+		"channel": "0xE5637F0103794C7e05469A9964E4563089a5E6f2/21"
+	}]`, buffer.String())
+}
@@ -0,0 +1,33 @@
+//go:build go1.23
+// +build go1.23
+
+package pendingtransfers
+
+import (
+	"context"
+	"iter"
+
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// All returns an iterator over every pending transfer of the node, listed with the
+// lister once the iteration starts, see util.Iterate.
+func All(ctx context.Context, lister Lister) iter.Seq2[*Transfer, error] {
+	return util.Iterate(ctx, lister.ListAll)
+}
+
+// AllToken returns an iterator over the pending transfers of the token, see All.
+func AllToken(ctx context.Context, lister Lister, tokenAddress common.Address) iter.Seq2[*Transfer, error] {
+	return util.Iterate(ctx, func(ctx context.Context) ([]*Transfer, error) {
+		return lister.ListToken(ctx, tokenAddress)
+	})
+}
+
+// AllChannel returns an iterator over the pending transfers of the channel with the
+// partner, see All.
+func AllChannel(ctx context.Context, lister Lister, tokenAddress, partnerAddress common.Address) iter.Seq2[*Transfer, error] {
+	return util.Iterate(ctx, func(ctx context.Context) ([]*Transfer, error) {
+		return lister.ListChannel(ctx, tokenAddress, partnerAddress)
+	})
+}
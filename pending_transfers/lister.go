@@ -2,8 +2,6 @@ package pendingtransfers
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -46,7 +44,7 @@ func (lister *defaultLister) ListAll(ctx context.Context) ([]*Transfer, error) {
 		err error
 	)
 
-	if url, err = lister.getAllRequestURL(); err != nil {
+	if url, err = lister.baseClient.Endpoint("pending_transfers"); err != nil {
 		return nil, err
 	}
 
@@ -59,7 +57,7 @@ func (lister *defaultLister) ListToken(ctx context.Context, tokenAddress common.
 		err error
 	)
 
-	if url, err = lister.getTokenRequestURL(tokenAddress); err != nil {
+	if url, err = lister.baseClient.Endpoint("pending_transfers/%s", tokenAddress.Hex()); err != nil {
 		return nil, err
 	}
 
@@ -72,7 +70,7 @@ func (lister *defaultLister) ListChannel(ctx context.Context, tokenAddress commo
 		err error
 	)
 
-	if url, err = lister.getChannelRequestURL(tokenAddress, partnerAddress); err != nil {
+	if url, err = lister.baseClient.Endpoint("pending_transfers/%s/%s", tokenAddress.Hex(), partnerAddress.Hex()); err != nil {
 		return nil, err
 	}
 
@@ -81,70 +79,24 @@ func (lister *defaultLister) ListChannel(ctx context.Context, tokenAddress commo
 
 func (lister *defaultLister) getPendingTransfers(ctx context.Context, url *url.URL) ([]*Transfer, error) {
 	var (
-		err       error
-		transfers = make([]*Transfer, 0)
-
-		request  *http.Request
-		response *http.Response
+		err               error
+		transfers         = make([]*Transfer, 0)
+		transferResponses = make([]*transfer, 0)
 	)
 
-	if request, err = http.NewRequest("GET", url.String(), nil); err != nil {
+	if err = lister.baseClient.Call(ctx, "GET", url, nil, &transferResponses); err != nil {
 		return nil, err
 	}
 
-	request = request.WithContext(ctx)
-
-	if response, err = lister.baseClient.HTTPClient.Do(request); err != nil {
-		return nil, err
-	}
+	for _, transferResponse := range transferResponses {
+		var transfer *Transfer
 
-	defer response.Body.Close()
+		if transfer, err = transferResponse.toTransfer(); err != nil {
+			return nil, err
+		}
 
-	if err = json.NewDecoder(response.Body).Decode(&transfers); err != nil {
-		return nil, err
+		transfers = append(transfers, transfer)
 	}
 
 	return transfers, nil
 }
-
-func (lister *defaultLister) getAllRequestURL() (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/pending_transfers", lister.baseClient.Config.Host, lister.baseClient.Config.APIVersion)
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
-	}
-
-	return requestURL, nil
-}
-
-func (lister *defaultLister) getChannelRequestURL(tokenAddress, partnerAddress common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/pending_transfers/%s/%s", lister.baseClient.Config.Host, lister.baseClient.Config.APIVersion, tokenAddress.Hex(), partnerAddress.Hex())
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
-	}
-
-	return requestURL, nil
-}
-
-func (lister *defaultLister) getTokenRequestURL(tokenAddress common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/pending_transfers/%s", lister.baseClient.Config.Host, lister.baseClient.Config.APIVersion, tokenAddress.Hex())
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
-	}
-
-	return requestURL, nil
-}
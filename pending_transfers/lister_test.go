@@ -4,10 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"net/http"
 	"os"
 	"testing"
 
+	"github.com/cpurta/go-raiden-client/address"
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jarcoal/httpmock"
@@ -105,13 +107,13 @@ func TestLister(t *testing.T) {
 				&Transfer{
 					ChannelIdentifier:      int64(255),
 					Initiator:              common.HexToAddress("0x5E1a3601538f94c9e6D2B40F7589030ac5885FE7"),
-					LockedAmount:           int64(119),
+					LockedAmount:           big.NewInt(119),
 					PaymentIdentifier:      int64(1),
 					Role:                   "initiator",
 					Target:                 common.HexToAddress("0x00AF5cBfc8dC76cd599aF623E60F763228906F3E"),
 					TokenAddress:           common.HexToAddress("0xd0A1E359811322d97991E03f863a0C30C2cF029C"),
-					TokenNetworkIdentifier: common.HexToAddress("0x111157460c0F41EfD9107239B7864c062aA8B978"),
-					TransferredAmount:      int64(331),
+					TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0x111157460c0F41EfD9107239B7864c062aA8B978"),
+					TransferredAmount:      big.NewInt(331),
 				},
 			},
 		},
@@ -180,6 +182,7 @@ func TestLister(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, transfers)
 				return
 			}
 
@@ -192,6 +195,7 @@ func TestLister(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, transfers)
 				return
 			}
 
@@ -203,6 +207,7 @@ func TestLister(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, transfers)
 				return
 			}
 
@@ -210,3 +215,39 @@ func TestLister(t *testing.T) {
 		})
 	}
 }
+
+func TestListerAPIVersion2(t *testing.T) {
+	var config = &config.Config{
+		Host:       "http://localhost:5001",
+		APIVersion: "v2",
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder(
+		"GET",
+		"http://localhost:5001/api/v2/transfers/pending/0xd0A1E359811322d97991E03f863a0C30C2cF029C",
+		httpmock.NewStringResponder(
+			http.StatusOK,
+			`[{"channel_identifier":"255","initiator":"0x5E1a3601538f94c9e6D2B40F7589030ac5885FE7","locked_amount":"119","payment_identifier":"1","role":"initiator","target":"0x00AF5cBfc8dC76cd599aF623E60F763228906F3E","token_address":"0xd0A1E359811322d97991E03f863a0C30C2cF029C","token_network_address":"0x111157460c0F41EfD9107239B7864c062aA8B978","transferred_amount":"331"}]`,
+		),
+	)
+
+	transfers, err := NewLister(config, http.DefaultClient).ListToken(context.Background(), common.HexToAddress("0xd0A1E359811322d97991E03f863a0C30C2cF029C"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Transfer{
+		&Transfer{
+			ChannelIdentifier:      255,
+			Initiator:              common.HexToAddress("0x5E1a3601538f94c9e6D2B40F7589030ac5885FE7"),
+			LockedAmount:           big.NewInt(119),
+			PaymentIdentifier:      1,
+			Role:                   "initiator",
+			Target:                 common.HexToAddress("0x00AF5cBfc8dC76cd599aF623E60F763228906F3E"),
+			TokenAddress:           common.HexToAddress("0xd0A1E359811322d97991E03f863a0C30C2cF029C"),
+			TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0x111157460c0F41EfD9107239B7864c062aA8B978"),
+			TransferredAmount:      big.NewInt(331),
+		},
+	}, transfers)
+}
@@ -0,0 +1,25 @@
+//go:build go1.21
+// +build go1.21
+
+package pendingtransfers
+
+import (
+	"log/slog"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+)
+
+// LogValue implements slog.LogValuer, logging the transfer as a group of its
+// identifiers, role, addresses and amounts rather than a dump of the struct.
+func (transfer Transfer) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int64("payment_identifier", transfer.PaymentIdentifier),
+		slog.Int64("channel_identifier", transfer.ChannelIdentifier),
+		slog.String("role", transfer.Role),
+		slog.String("token_address", transfer.TokenAddress.Hex()),
+		slog.String("initiator", transfer.Initiator.Hex()),
+		slog.String("target", transfer.Target.Hex()),
+		slog.String("locked_amount", amounts.OrZero(transfer.LockedAmount).String()),
+		slog.String("transferred_amount", amounts.OrZero(transfer.TransferredAmount).String()),
+	)
+}
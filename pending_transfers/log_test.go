@@ -0,0 +1,24 @@
+//go:build go1.21
+// +build go1.21
+
+package pendingtransfers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransferLogValue(t *testing.T) {
+	var attrs = make(map[string]interface{})
+
+	for _, attr := range (Transfer{PaymentIdentifier: 1, Role: "mediator", LockedAmount: big.NewInt(119)}).LogValue().Group() {
+		attrs[attr.Key] = attr.Value.Any()
+	}
+
+	assert.Equal(t, int64(1), attrs["payment_identifier"])
+	assert.Equal(t, "mediator", attrs["role"])
+	assert.Equal(t, "119", attrs["locked_amount"])
+	assert.Equal(t, "0", attrs["transferred_amount"])
+}
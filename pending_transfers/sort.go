@@ -0,0 +1,28 @@
+package pendingtransfers
+
+import (
+	"sort"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SortByLockedAmount sorts the transfers by locked amount, the largest first. The sort
+// is stable, so transfers locking the same amount keep their order.
+func SortByLockedAmount(transfers []*Transfer) {
+	sort.SliceStable(transfers, func(i, j int) bool {
+		return amounts.OrZero(transfers[i].LockedAmount).Cmp(amounts.OrZero(transfers[j].LockedAmount)) > 0
+	})
+}
+
+// GroupByToken groups the transfers by token address, every group keeping the order
+// of the transfers.
+func GroupByToken(transfers []*Transfer) map[common.Address][]*Transfer {
+	var groups = make(map[common.Address][]*Transfer)
+
+	for _, transfer := range transfers {
+		groups[transfer.TokenAddress] = append(groups[transfer.TokenAddress], transfer)
+	}
+
+	return groups
+}
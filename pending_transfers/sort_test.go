@@ -0,0 +1,46 @@
+package pendingtransfers
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortByLockedAmount(t *testing.T) {
+	var transfers = []*Transfer{
+		&Transfer{PaymentIdentifier: 1, LockedAmount: big.NewInt(5)},
+		&Transfer{PaymentIdentifier: 2},
+		&Transfer{PaymentIdentifier: 3, LockedAmount: new(big.Int).Lsh(big.NewInt(1), 70)},
+		&Transfer{PaymentIdentifier: 4, LockedAmount: big.NewInt(5)},
+	}
+
+	SortByLockedAmount(transfers)
+
+	var identifiers []int64
+
+	for _, transfer := range transfers {
+		identifiers = append(identifiers, transfer.PaymentIdentifier)
+	}
+
+	assert.Equal(t, []int64{3, 1, 4, 2}, identifiers)
+}
+
+func TestGroupByToken(t *testing.T) {
+	var (
+		token1    = common.HexToAddress("0xd0A1E359811322d97991E03f863a0C30C2cF029C")
+		token2    = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		transfers = []*Transfer{
+			&Transfer{PaymentIdentifier: 1, TokenAddress: token1},
+			&Transfer{PaymentIdentifier: 2, TokenAddress: token2},
+			&Transfer{PaymentIdentifier: 3, TokenAddress: token1},
+		}
+	)
+
+	groups := GroupByToken(transfers)
+
+	assert.Len(t, groups, 2)
+	assert.Equal(t, []*Transfer{transfers[0], transfers[2]}, groups[token1])
+	assert.Equal(t, []*Transfer{transfers[1]}, groups[token2])
+}
@@ -0,0 +1,165 @@
+package pendingtransfers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultBlockTime is the time between two blocks of the chain when no block time is
+// given, the one of Ethereum mainnet.
+const DefaultBlockTime = 15 * time.Second
+
+// TrackedTransfer is a pending transfer along with how long it has been pending and
+// when its lock is estimated to expire. The lock of a transfer expires twice the
+// reveal timeout of its channel after the transfer started, and since a transfer is
+// only seen once it started, the age is a lower bound and the expiry an upper bound.
+type TrackedTransfer struct {
+	*Transfer
+	FirstSeen       time.Time
+	Age             time.Duration
+	EstimatedExpiry time.Time
+}
+
+// Expired tells whether the lock of the transfer is estimated to have expired at the
+// time.
+func (transfer *TrackedTransfer) Expired(now time.Time) bool {
+	return !now.Before(transfer.EstimatedExpiry)
+}
+
+// TrackerOptions configures the estimations of a tracker. The reveal timeout of the
+// channel of a transfer is used when the channel can be listed, RevealTimeout,
+// channels.DefaultRevealTimeout when zero, otherwise.
+type TrackerOptions struct {
+	BlockTime     time.Duration
+	RevealTimeout int64
+}
+
+// Tracker lists the pending transfers of a Raiden node along with the time they were
+// first seen by the tracker, which remembers them for as long as they are pending.
+type Tracker struct {
+	lister  Lister
+	index   *channels.Index
+	options TrackerOptions
+	now     func() time.Time
+
+	mutex     sync.Mutex
+	firstSeen map[string]time.Time
+}
+
+// NewTracker creates a tracker of the pending transfers of the Raiden node, given a
+// Raiden node configuration and an http client.
+func NewTracker(config *config.Config, httpClient *http.Client, options *TrackerOptions) *Tracker {
+	var tracker = &Tracker{
+		lister:    NewLister(config, httpClient),
+		index:     channels.IndexOf(config, httpClient),
+		now:       config.Time().Now,
+		firstSeen: make(map[string]time.Time),
+	}
+
+	if options != nil {
+		tracker.options = *options
+	}
+
+	if tracker.options.BlockTime <= 0 {
+		tracker.options.BlockTime = DefaultBlockTime
+	}
+
+	if tracker.options.RevealTimeout <= 0 {
+		tracker.options.RevealTimeout = channels.DefaultRevealTimeout
+	}
+
+	return tracker
+}
+
+// ListAll will list all the pending transfers of the node, forgetting the transfers
+// that are no longer pending.
+func (tracker *Tracker) ListAll(ctx context.Context) ([]*TrackedTransfer, error) {
+	transfers, err := tracker.lister.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracker.track(ctx, transfers, true), nil
+}
+
+// ListToken will list the pending transfers of a token network.
+func (tracker *Tracker) ListToken(ctx context.Context, tokenAddress common.Address) ([]*TrackedTransfer, error) {
+	transfers, err := tracker.lister.ListToken(ctx, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracker.track(ctx, transfers, false), nil
+}
+
+// ListChannel will list the pending transfers of the channel with a partner.
+func (tracker *Tracker) ListChannel(ctx context.Context, tokenAddress, partnerAddress common.Address) ([]*TrackedTransfer, error) {
+	transfers, err := tracker.lister.ListChannel(ctx, tokenAddress, partnerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracker.track(ctx, transfers, false), nil
+}
+
+// track records the first time the transfers were seen and estimates their expiry.
+// When the transfers are all the pending transfers of the node, the ones that are not
+// among them are forgotten.
+func (tracker *Tracker) track(ctx context.Context, transfers []*Transfer, all bool) []*TrackedTransfer {
+	var (
+		now     = tracker.now()
+		tracked = make([]*TrackedTransfer, 0, len(transfers))
+		pending = make(map[string]bool, len(transfers))
+	)
+
+	for _, transfer := range transfers {
+		var (
+			key           = transferKey(transfer)
+			revealTimeout = tracker.options.RevealTimeout
+		)
+
+		tracker.mutex.Lock()
+		firstSeen, ok := tracker.firstSeen[key]
+		if !ok {
+			firstSeen = now
+			tracker.firstSeen[key] = now
+		}
+		tracker.mutex.Unlock()
+
+		pending[key] = true
+
+		if channel, err := tracker.index.Resolve(ctx, transfer.TokenAddress, transfer.ChannelIdentifier); err == nil && channel.RevealTimeout > 0 {
+			revealTimeout = channel.RevealTimeout
+		}
+
+		tracked = append(tracked, &TrackedTransfer{
+			Transfer:        transfer,
+			FirstSeen:       firstSeen,
+			Age:             now.Sub(firstSeen),
+			EstimatedExpiry: firstSeen.Add(time.Duration(2*revealTimeout) * tracker.options.BlockTime),
+		})
+	}
+
+	if all {
+		tracker.mutex.Lock()
+		for key := range tracker.firstSeen {
+			if !pending[key] {
+				delete(tracker.firstSeen, key)
+			}
+		}
+		tracker.mutex.Unlock()
+	}
+
+	return tracked
+}
+
+func transferKey(transfer *Transfer) string {
+	return fmt.Sprintf("%s/%d/%d/%s", transfer.TokenNetworkIdentifier.Hex(), transfer.ChannelIdentifier, transfer.PaymentIdentifier, transfer.Role)
+}
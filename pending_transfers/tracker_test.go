@@ -0,0 +1,98 @@
+package pendingtransfers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleTracker() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tracker = NewTracker(config, http.DefaultClient, &TrackerOptions{BlockTime: 5 * time.Second})
+	)
+
+	transfers, err := tracker.ListAll(context.Background())
+	if err != nil {
+		panic(fmt.Sprintf("unable to list pending transfers: %s", err.Error()))
+	}
+
+	for _, transfer := range transfers {
+		if transfer.Age > 10*time.Minute || transfer.Expired(time.Now()) {
+			fmt.Printf("transfer %d stuck for %s, expires %s\n", transfer.PaymentIdentifier, transfer.Age, transfer.EstimatedExpiry)
+		}
+	}
+}
+
+func TestTracker(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		ctx          = context.Background()
+		now          = time.Date(2018, 10, 30, 7, 0, 0, 0, time.UTC)
+		tracker      = NewTracker(config, http.DefaultClient, &TrackerOptions{BlockTime: 10 * time.Second, RevealTimeout: 20})
+		transferJSON = `{"channel_identifier":%d,"initiator":"0x5E1a3601538f94c9CDD7F32276115c6D2E8F93E1","locked_amount":100,"payment_identifier":%d,"role":"initiator","target":"0x00AF5cBfc8dC76cd599aF623E60F763228906F3E","token_address":"0xd0A1E359811322d97991E03f863a0C30C2cF029C","token_network_identifier":"0x111157460c0F41EfD9107239B7864c062aA8B978","transferred_amount":0}`
+		transfers    []string
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	tracker.now = func() time.Time { return now }
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", func(request *http.Request) (*http.Response, error) {
+		return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf("[%s]", strings.Join(transfers, ","))), nil
+	})
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/0xd0A1E359811322d97991E03f863a0C30C2cF029C",
+		httpmock.NewStringResponder(http.StatusOK, `[{"channel_identifier":1,"partner_address":"0x00AF5cBfc8dC76cd599aF623E60F763228906F3E","token_address":"0xd0A1E359811322d97991E03f863a0C30C2cF029C","state":"opened","reveal_timeout":30}]`))
+
+	transfers = []string{fmt.Sprintf(transferJSON, 1, 42)}
+
+	tracked, err := tracker.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, tracked, 1)
+	assert.Equal(t, now, tracked[0].FirstSeen)
+	assert.Zero(t, tracked[0].Age)
+	assert.Equal(t, now.Add(10*time.Minute), tracked[0].EstimatedExpiry)
+
+	now = now.Add(3 * time.Minute)
+	transfers = append(transfers, fmt.Sprintf(transferJSON, 2, 43))
+
+	tracked, err = tracker.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, tracked, 2)
+	assert.Equal(t, 3*time.Minute, tracked[0].Age)
+	assert.False(t, tracked[0].Expired(now))
+	assert.Zero(t, tracked[1].Age)
+	// the channel of the second transfer is unknown, the reveal timeout of the options
+	// is used
+	assert.Equal(t, now.Add(400*time.Second), tracked[1].EstimatedExpiry)
+
+	now = now.Add(10 * time.Minute)
+	transfers = transfers[1:]
+
+	tracked, err = tracker.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, tracked, 1)
+	assert.Equal(t, 10*time.Minute, tracked[0].Age)
+	assert.True(t, tracked[0].Expired(now))
+
+	transfers = []string{fmt.Sprintf(transferJSON, 1, 42)}
+
+	tracked, err = tracker.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, tracked[0].Age, "a transfer pending again is a new transfer")
+}
@@ -1,15 +1,150 @@
 package pendingtransfers
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type transfer struct {
+	ChannelIdentifier      int64        `json:"channel_identifier"`
+	Initiator              util.Address `json:"initiator"`
+	LockedAmount           json.Number  `json:"locked_amount"`
+	PaymentIdentifier      int64        `json:"payment_identifier"`
+	Role                   string       `json:"role"`
+	Target                 util.Address `json:"target"`
+	TokenAddress           util.Address `json:"token_address"`
+	TokenNetworkIdentifier util.Address `json:"token_network_identifier"`
+	TransferredAmount      json.Number  `json:"transferred_amount"`
+}
+
+func (transfer *transfer) toTransfer() (*Transfer, error) {
+	var (
+		err               error
+		lockedAmount      *big.Int
+		transferredAmount *big.Int
+	)
+
+	if lockedAmount, err = amounts.Parse(transfer.LockedAmount); err != nil {
+		return nil, err
+	}
+
+	if transferredAmount, err = amounts.Parse(transfer.TransferredAmount); err != nil {
+		return nil, err
+	}
+
+	return &Transfer{
+		ChannelIdentifier:      transfer.ChannelIdentifier,
+		Initiator:              common.Address(transfer.Initiator),
+		LockedAmount:           lockedAmount,
+		PaymentIdentifier:      transfer.PaymentIdentifier,
+		Role:                   transfer.Role,
+		Target:                 common.Address(transfer.Target),
+		TokenAddress:           common.Address(transfer.TokenAddress),
+		TokenNetworkIdentifier: address.TokenNetworkAddress(transfer.TokenNetworkIdentifier),
+		TransferredAmount:      transferredAmount,
+	}, nil
+}
 
 type Transfer struct {
-	ChannelIdentifier      int64          `json:"channel_identifier"`
-	Initiator              common.Address `json:"initiator"`
-	LockedAmount           int64          `json:"locked_amount"`
-	PaymentIdentifier      int64          `json:"payment_identifier"`
-	Role                   string         `json:"role"`
-	Target                 common.Address `json:"target"`
-	TokenAddress           common.Address `json:"token_address"`
-	TokenNetworkIdentifier common.Address `json:"token_network_identifier"`
-	TransferredAmount      int64          `json:"transferred_amount"`
+	ChannelIdentifier      int64                       `json:"channel_identifier"`
+	Initiator              common.Address              `json:"initiator"`
+	LockedAmount           *big.Int                    `json:"locked_amount"`
+	PaymentIdentifier      int64                       `json:"payment_identifier"`
+	Role                   string                      `json:"role"`
+	Target                 common.Address              `json:"target"`
+	TokenAddress           common.Address              `json:"token_address"`
+	TokenNetworkIdentifier address.TokenNetworkAddress `json:"token_network_identifier"`
+	TransferredAmount      *big.Int                    `json:"transferred_amount"`
+}
+
+// String returns a concise description of the transfer for logs, e.g. "transfer 1
+// initiator 0x5E1A…→0x00AF… locked 119 channel 255", with the addresses abbreviated
+// and the amount in the smallest unit of the token.
+func (transfer Transfer) String() string {
+	return fmt.Sprintf("transfer %d %s %s→%s locked %s channel %d", transfer.PaymentIdentifier, transfer.Role, util.ShortAddress(transfer.Initiator), util.ShortAddress(transfer.Target), amounts.OrZero(transfer.LockedAmount).String(), transfer.ChannelIdentifier)
+}
+
+// MarshalJSON encodes the transfer in the format of the Raiden node API, with the
+// addresses as checksummed hex strings and the amounts as JSON numbers.
+func (transfer Transfer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fromTransfer(&transfer))
+}
+
+// UnmarshalJSON decodes a transfer in the format of the Raiden node API, which is
+// the format transfers are marshalled in.
+func (transfer *Transfer) UnmarshalJSON(data []byte) error {
+	var (
+		err     error
+		decoded *Transfer
+	)
+
+	if decoded, err = decodeTransfer(data); err != nil {
+		return err
+	}
+
+	*transfer = *decoded
+
+	return nil
+}
+
+func decodeTransfer(data []byte) (*Transfer, error) {
+	var response = &transfer{}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return nil, err
+	}
+
+	return response.toTransfer()
+}
+
+func fromTransfer(value *Transfer) *transfer {
+	return &transfer{
+		ChannelIdentifier:      value.ChannelIdentifier,
+		Initiator:              util.Address(value.Initiator),
+		LockedAmount:           amounts.Number(value.LockedAmount),
+		PaymentIdentifier:      value.PaymentIdentifier,
+		Role:                   value.Role,
+		Target:                 util.Address(value.Target),
+		TokenAddress:           util.Address(value.TokenAddress),
+		TokenNetworkIdentifier: util.Address(value.TokenNetworkIdentifier),
+		TransferredAmount:      amounts.Number(value.TransferredAmount),
+	}
+}
+
+// Equal tells whether the transfer has the same state as the other, comparing the
+// amounts by value, see amounts.Equal. Two nil transfers are equal.
+func (transfer *Transfer) Equal(other *Transfer) bool {
+	if transfer == nil || other == nil {
+		return transfer == other
+	}
+
+	return transfer.ChannelIdentifier == other.ChannelIdentifier &&
+		transfer.Initiator == other.Initiator &&
+		amounts.Equal(transfer.LockedAmount, other.LockedAmount) &&
+		transfer.PaymentIdentifier == other.PaymentIdentifier &&
+		transfer.Role == other.Role &&
+		transfer.Target == other.Target &&
+		transfer.TokenAddress == other.TokenAddress &&
+		transfer.TokenNetworkIdentifier == other.TokenNetworkIdentifier &&
+		amounts.Equal(transfer.TransferredAmount, other.TransferredAmount)
+}
+
+// Clone returns a deep copy of the transfer, whose amounts can be changed without
+// changing those of the transfer.
+func (transfer *Transfer) Clone() *Transfer {
+	if transfer == nil {
+		return nil
+	}
+
+	clone := *transfer
+	clone.LockedAmount = amounts.Copy(transfer.LockedAmount)
+	clone.TransferredAmount = amounts.Copy(transfer.TransferredAmount)
+
+	return &clone
 }
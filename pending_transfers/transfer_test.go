@@ -0,0 +1,82 @@
+package pendingtransfers
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferJSONRoundTrip(t *testing.T) {
+	var (
+		data     = `{"channel_identifier":255,"initiator":"0x5E1A3601538f94c9cc6d8a6b36dCe77dDabDd463","locked_amount":119,"payment_identifier":1,"role":"initiator","target":"0x00AF5cBfc8dC76cd599aF623E60F763228906F3E","token_address":"0xd0A1E359811322d97991E03f863a0C30C2cF029C","token_network_identifier":"0x111157460c0F41EfD9107239B7864c062aA8B978","transferred_amount":331}`
+		transfer = &Transfer{}
+	)
+
+	require.NoError(t, json.Unmarshal([]byte(data), transfer))
+
+	assert.Equal(t, &Transfer{
+		ChannelIdentifier:      255,
+		Initiator:              common.HexToAddress("0x5E1A3601538f94c9cc6d8a6b36dCe77dDabDd463"),
+		LockedAmount:           big.NewInt(119),
+		PaymentIdentifier:      1,
+		Role:                   "initiator",
+		Target:                 common.HexToAddress("0x00AF5cBfc8dC76cd599aF623E60F763228906F3E"),
+		TokenAddress:           common.HexToAddress("0xd0A1E359811322d97991E03f863a0C30C2cF029C"),
+		TokenNetworkIdentifier: address.HexToTokenNetworkAddress("0x111157460c0F41EfD9107239B7864c062aA8B978"),
+		TransferredAmount:      big.NewInt(331),
+	}, transfer)
+
+	encoded, err := json.Marshal(transfer)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, data, string(encoded))
+}
+
+func TestTransferEqualAndClone(t *testing.T) {
+	var (
+		transfer = &Transfer{
+			ChannelIdentifier: 255,
+			Initiator:         common.HexToAddress("0x5E1A3601538f94c9cc6d8a6b36dCe77dDabDd463"),
+			LockedAmount:      big.NewInt(119),
+			PaymentIdentifier: 1,
+			Role:              "initiator",
+			TransferredAmount: new(big.Int),
+		}
+		clone = transfer.Clone()
+	)
+
+	assert.True(t, transfer.Equal(clone))
+
+	clone.TransferredAmount = nil
+	assert.True(t, transfer.Equal(clone), "a nil amount is zero")
+
+	clone.LockedAmount.SetInt64(120)
+	assert.Equal(t, int64(119), transfer.LockedAmount.Int64())
+	assert.False(t, transfer.Equal(clone))
+
+	clone = transfer.Clone()
+	clone.Role = "mediator"
+	assert.False(t, transfer.Equal(clone))
+
+	assert.True(t, (*Transfer)(nil).Equal(nil))
+	assert.False(t, transfer.Equal(nil))
+	assert.Nil(t, (*Transfer)(nil).Clone())
+}
+
+func TestTransferString(t *testing.T) {
+	var transfer = Transfer{
+		ChannelIdentifier: 255,
+		Initiator:         common.HexToAddress("0x5E1A3601538f94c9cc6d8a6b36dCe77dDabDd463"),
+		LockedAmount:      big.NewInt(119),
+		PaymentIdentifier: 1,
+		Role:              "initiator",
+		Target:            common.HexToAddress("0x00AF5cBfc8dC76cd599aF623E60F763228906F3E"),
+	}
+
+	assert.Equal(t, "transfer 1 initiator 0x5E1A…→0x00AF… locked 119 channel 255", transfer.String())
+}
@@ -0,0 +1,30 @@
+package pfs
+
+import (
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+var (
+	_ PathFinder    = &Client{}
+	_ NetworkGetter = &Client{}
+	_ InfoGetter    = &Client{}
+)
+
+// NewClient creates a new pathfinding service client given its configuration, where
+// Host is the address of the service, and an http client.
+func NewClient(config *config.Config, httpClient *http.Client) *Client {
+	return &Client{
+		PathFinder:    NewPathFinder(config, httpClient),
+		NetworkGetter: NewNetworkGetter(config, httpClient),
+		InfoGetter:    NewInfoGetter(config, httpClient),
+	}
+}
+
+// Client provides access to the API calls of a Raiden pathfinding service.
+type Client struct {
+	PathFinder
+	NetworkGetter
+	InfoGetter
+}
@@ -0,0 +1,112 @@
+package pfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type infoResponse struct {
+	PriceInfo      json.Number `json:"price_info"`
+	Version        string      `json:"version"`
+	Operator       string      `json:"operator"`
+	Message        string      `json:"message"`
+	PaymentAddress string      `json:"payment_address"`
+}
+
+func (info *infoResponse) toInfo() (*Info, error) {
+	price, err := amounts.Parse(info.PriceInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Info{
+		Price:          price,
+		Version:        info.Version,
+		Operator:       info.Operator,
+		Message:        info.Message,
+		PaymentAddress: common.HexToAddress(info.PaymentAddress),
+	}, nil
+}
+
+// Info describes a pathfinding service: the Price it charges for a route request, in
+// the service token, the version it runs, its operator and their message, and the
+// address it is paid at.
+type Info struct {
+	Price          *big.Int
+	Version        string
+	Operator       string
+	Message        string
+	PaymentAddress common.Address
+}
+
+// InfoGetter is a generic interface to get the description of a pathfinding service.
+type InfoGetter interface {
+	GetInfo(ctx context.Context) (*Info, error)
+}
+
+// NewInfoGetter creates a new default info getter given the configuration of a
+// pathfinding service and an http client.
+func NewInfoGetter(config *config.Config, httpClient *http.Client) InfoGetter {
+	return &defaultInfoGetter{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultInfoGetter struct {
+	baseClient *util.BaseClient
+}
+
+// GetInfo will ask the pathfinding service for its description, as served by its info
+// endpoint.
+func (getter *defaultInfoGetter) GetInfo(ctx context.Context) (*Info, error) {
+	var (
+		err        error
+		requestURL *url.URL
+		request    *http.Request
+		response   *http.Response
+		info       = &infoResponse{}
+	)
+
+	if requestURL, err = getter.baseClient.Endpoint("info"); err != nil {
+		return nil, err
+	}
+
+	if request, err = getter.baseClient.NewRequest(ctx, "GET", requestURL, nil); err != nil {
+		return nil, err
+	}
+
+	if response, err = getter.baseClient.Do(request); err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		var pfsErr = &errorResponse{}
+
+		if err = json.NewDecoder(response.Body).Decode(pfsErr); err != nil || pfsErr.Errors == "" {
+			return nil, raidenerrors.New(response.StatusCode, fmt.Errorf("received %d status code from pathfinding service", response.StatusCode))
+		}
+
+		return nil, raidenerrors.New(response.StatusCode, fmt.Errorf("pathfinding service error %d: %s", pfsErr.ErrorCode, pfsErr.Errors))
+	}
+
+	if err = getter.baseClient.Decode(response.Body, info); err != nil {
+		return nil, util.ResponseError(ctx, response.StatusCode, err)
+	}
+
+	return info.toInfo()
+}
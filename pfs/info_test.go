@@ -0,0 +1,101 @@
+package pfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleInfoGetter() {
+	var (
+		pfsConfig = &config.Config{
+			Host:       "https://pfs.transport01.raiden.network",
+			APIVersion: "v1",
+		}
+		info *Info
+		err  error
+	)
+
+	if info, err = NewInfoGetter(pfsConfig, http.DefaultClient).GetInfo(context.Background()); err != nil {
+		panic(fmt.Sprintf("unable to get info: %s", err.Error()))
+	}
+
+	fmt.Printf("%s charges %d per route\n", info.Operator, info.Price)
+}
+
+func TestInfoGetter(t *testing.T) {
+	var (
+		pfsConfig = &config.Config{
+			Host:       "http://localhost:6000",
+			APIVersion: "v1",
+		}
+		infoURL = "http://localhost:6000/api/v1/info"
+	)
+
+	type testcase struct {
+		name          string
+		prepHTTPMock  func()
+		expectedInfo  *Info
+		expectedError error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully got the info",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", infoURL, httpmock.NewStringResponder(http.StatusOK,
+					`{"price_info":100,"network_info":{"chain_id":1},"version":"0.13.1","operator":"Example Operator","message":"fast routes","payment_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`))
+			},
+			expectedInfo: &Info{
+				Price:          big.NewInt(100),
+				Version:        "0.13.1",
+				Operator:       "Example Operator",
+				Message:        "fast routes",
+				PaymentAddress: common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"),
+			},
+		},
+		testcase{
+			name: "error of the service",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", infoURL, httpmock.NewStringResponder(http.StatusServiceUnavailable, `{"error_code":2000,"errors":"service is starting"}`))
+			},
+			expectedError: errors.New("pathfinding service error 2000: service is starting"),
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", infoURL, httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+			},
+			expectedError: errors.New("received 500 status code from pathfinding service"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			info, err := NewInfoGetter(pfsConfig, http.DefaultClient).GetInfo(context.Background())
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, info)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedInfo, info)
+		})
+	}
+}
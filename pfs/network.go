@@ -0,0 +1,136 @@
+package pfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type networkChannel struct {
+	Participant1 string      `json:"participant1"`
+	Participant2 string      `json:"participant2"`
+	Capacity1    json.Number `json:"capacity1"`
+	Capacity2    json.Number `json:"capacity2"`
+}
+
+type networkResponse struct {
+	Channels []*networkChannel `json:"channels"`
+}
+
+func (channel *networkChannel) toChannel() (*NetworkChannel, error) {
+	var (
+		err       error
+		capacity1 *big.Int
+		capacity2 *big.Int
+	)
+
+	if capacity1, err = amounts.Parse(channel.Capacity1); err != nil {
+		return nil, err
+	}
+
+	if capacity2, err = amounts.Parse(channel.Capacity2); err != nil {
+		return nil, err
+	}
+
+	return &NetworkChannel{
+		Participant1: common.HexToAddress(channel.Participant1),
+		Participant2: common.HexToAddress(channel.Participant2),
+		Capacity1:    capacity1,
+		Capacity2:    capacity2,
+	}, nil
+}
+
+// NetworkChannel is an open channel of a token network as seen by a pathfinding
+// service, with the capacity each participant can send through it.
+type NetworkChannel struct {
+	Participant1 common.Address
+	Participant2 common.Address
+	Capacity1    *big.Int
+	Capacity2    *big.Int
+}
+
+// NetworkGetter is a generic interface to get the open channels of a token network a
+// pathfinding service knows of.
+type NetworkGetter interface {
+	GetNetwork(ctx context.Context, tokenNetworkAddress address.TokenNetworkAddress) ([]*NetworkChannel, error)
+}
+
+// NewNetworkGetter creates a new default network getter given the configuration of a
+// pathfinding service and an http client.
+func NewNetworkGetter(config *config.Config, httpClient *http.Client) NetworkGetter {
+	return &defaultNetworkGetter{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultNetworkGetter struct {
+	baseClient *util.BaseClient
+}
+
+// GetNetwork will ask the pathfinding service for the open channels of the token
+// network, as served by its network endpoint.
+func (getter *defaultNetworkGetter) GetNetwork(ctx context.Context, tokenNetworkAddress address.TokenNetworkAddress) ([]*NetworkChannel, error) {
+	var (
+		err        error
+		requestURL *url.URL
+		request    *http.Request
+		response   *http.Response
+		network    = &networkResponse{}
+		channels   []*NetworkChannel
+	)
+
+	if requestURL, err = getter.baseClient.Endpoint("%s/network", tokenNetworkAddress.Hex()); err != nil {
+		return nil, err
+	}
+
+	if request, err = getter.baseClient.NewRequest(ctx, "GET", requestURL, nil); err != nil {
+		return nil, err
+	}
+
+	if response, err = getter.baseClient.Do(request); err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		var pfsErr = &errorResponse{}
+
+		if err = json.NewDecoder(response.Body).Decode(pfsErr); err != nil || pfsErr.Errors == "" {
+			return nil, raidenerrors.New(response.StatusCode, fmt.Errorf("received %d status code from pathfinding service", response.StatusCode))
+		}
+
+		return nil, raidenerrors.New(response.StatusCode, fmt.Errorf("pathfinding service error %d: %s", pfsErr.ErrorCode, pfsErr.Errors))
+	}
+
+	if err = getter.baseClient.Decode(response.Body, network); err != nil {
+		return nil, util.ResponseError(ctx, response.StatusCode, err)
+	}
+
+	channels = make([]*NetworkChannel, 0, len(network.Channels))
+
+	for _, channelResponse := range network.Channels {
+		var channel *NetworkChannel
+
+		if channel, err = channelResponse.toChannel(); err != nil {
+			return nil, err
+		}
+
+		channels = append(channels, channel)
+	}
+
+	return channels, nil
+}
@@ -0,0 +1,137 @@
+package pfs
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"net/http"
+	"sort"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Suggestion is a node of a token network suggested as a partner to open a channel
+// with, with the number of open channels it has and the capacity it can send
+// through them, i.e. forward payments with.
+type Suggestion struct {
+	Address  common.Address
+	Channels int
+	Capacity *big.Int
+}
+
+// PartnerSuggester is a generic interface to suggest the partners a node should open
+// channels with in the token network of a token.
+type PartnerSuggester interface {
+	SuggestPartners(ctx context.Context, tokenAddress common.Address, n int) ([]*Suggestion, error)
+}
+
+// NewPartnerSuggester creates a new default partner suggester given the configuration
+// of the Raiden node the partners are suggested for, that of a pathfinding service and
+// an http client.
+func NewPartnerSuggester(nodeConfig, pfsConfig *config.Config, httpClient *http.Client) PartnerSuggester {
+	return &defaultPartnerSuggester{
+		addressGetter: address.NewGetter(nodeConfig, httpClient),
+		tokenGetter:   tokens.NewGetter(nodeConfig, httpClient),
+		channelLister: channels.NewLister(nodeConfig, httpClient),
+		networkGetter: NewNetworkGetter(pfsConfig, httpClient),
+	}
+}
+
+type defaultPartnerSuggester struct {
+	addressGetter address.Getter
+	tokenGetter   tokens.Getter
+	channelLister channels.Lister
+	networkGetter NetworkGetter
+}
+
+// SuggestPartners returns at most n nodes of the token network of the token, as seen
+// by the pathfinding service, the best connected first and those with the most
+// capacity among nodes with as many channels. The node itself and the partners it
+// already has a channel with that is not settled are left out.
+func (suggester *defaultPartnerSuggester) SuggestPartners(ctx context.Context, tokenAddress common.Address, n int) ([]*Suggestion, error) {
+	var (
+		err          error
+		ourAddress   common.Address
+		tokenNetwork address.TokenNetworkAddress
+		channelList  []*channels.Channel
+		network      []*NetworkChannel
+		excluded     = make(map[common.Address]bool)
+		candidates   = make(map[common.Address]*Suggestion)
+		suggestions  []*Suggestion
+	)
+
+	if ourAddress, err = suggester.addressGetter.Get(ctx); err != nil {
+		return nil, err
+	}
+
+	if tokenNetwork, err = suggester.tokenGetter.Get(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	if channelList, err = suggester.channelLister.ListToken(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	if network, err = suggester.networkGetter.GetNetwork(ctx, tokenNetwork); err != nil {
+		return nil, err
+	}
+
+	excluded[ourAddress] = true
+
+	for _, channel := range channelList {
+		if channel.State != "settled" {
+			excluded[channel.PartnerAddress] = true
+		}
+	}
+
+	add := func(participant common.Address, capacity *big.Int) {
+		if excluded[participant] {
+			return
+		}
+
+		candidate, ok := candidates[participant]
+		if !ok {
+			candidate = &Suggestion{Address: participant, Capacity: new(big.Int)}
+			candidates[participant] = candidate
+		}
+
+		candidate.Channels++
+
+		if capacity != nil {
+			candidate.Capacity.Add(candidate.Capacity, capacity)
+		}
+	}
+
+	for _, channel := range network {
+		add(channel.Participant1, channel.Capacity1)
+		add(channel.Participant2, channel.Capacity2)
+	}
+
+	suggestions = make([]*Suggestion, 0, len(candidates))
+
+	for _, candidate := range candidates {
+		suggestions = append(suggestions, candidate)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Channels != suggestions[j].Channels {
+			return suggestions[i].Channels > suggestions[j].Channels
+		}
+
+		if cmp := suggestions[i].Capacity.Cmp(suggestions[j].Capacity); cmp != 0 {
+			return cmp > 0
+		}
+
+		return bytes.Compare(suggestions[i].Address.Bytes(), suggestions[j].Address.Bytes()) < 0
+	})
+
+	if n >= 0 && len(suggestions) > n {
+		suggestions = suggestions[:n]
+	}
+
+	return suggestions, nil
+}
@@ -0,0 +1,132 @@
+package pfs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExamplePartnerSuggester() {
+	var (
+		nodeConfig = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		pfsConfig = &config.Config{
+			Host:       "https://pfs.transport01.raiden.network",
+			APIVersion: "v1",
+		}
+		suggester   = NewPartnerSuggester(nodeConfig, pfsConfig, http.DefaultClient)
+		token       = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		suggestions []*Suggestion
+		err         error
+	)
+
+	if suggestions, err = suggester.SuggestPartners(context.Background(), token, 3); err != nil {
+		panic(fmt.Sprintf("unable to suggest partners: %s", err.Error()))
+	}
+
+	for _, suggestion := range suggestions {
+		fmt.Printf("%s: %d channels, capacity %d\n", suggestion.Address.Hex(), suggestion.Channels, suggestion.Capacity)
+	}
+}
+
+func TestPartnerSuggester(t *testing.T) {
+	var (
+		nodeConfig = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		pfsConfig = &config.Config{
+			Host:       "http://localhost:6000",
+			APIVersion: "v1",
+		}
+		token       = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		ourAddress  = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		partner     = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		hub         = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+		large       = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359")
+		small       = common.HexToAddress("0x6B175474E89094C44Da98b954EedeAC495271d0F")
+		networkURL  = "http://localhost:6000/api/v1/0xE5637F0103794C7e05469A9964E4563089a5E6f2/network"
+		networkJSON = fmt.Sprintf(`{"channels":[`+
+			`{"participant1":"%s","participant2":"%s","capacity1":10,"capacity2":5},`+
+			`{"participant1":"%s","participant2":"%s","capacity1":100,"capacity2":50},`+
+			`{"participant1":"%s","participant2":"%s","capacity1":30,"capacity2":1000},`+
+			`{"participant1":"%s","participant2":"%s","capacity1":20,"capacity2":1}]}`,
+			ourAddress.Hex(), partner.Hex(), partner.Hex(), hub.Hex(), hub.Hex(), large.Hex(), hub.Hex(), small.Hex())
+	)
+
+	type testcase struct {
+		name                string
+		n                   int
+		prepHTTPMock        func()
+		expectedSuggestions []*Suggestion
+		expectedError       string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "best connected then highest capacity, without existing partners",
+			n:    3,
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", networkURL, httpmock.NewStringResponder(http.StatusOK, networkJSON))
+			},
+			expectedSuggestions: []*Suggestion{
+				&Suggestion{Address: hub, Channels: 3, Capacity: big.NewInt(100)},
+				&Suggestion{Address: large, Channels: 1, Capacity: big.NewInt(1000)},
+				&Suggestion{Address: small, Channels: 1, Capacity: big.NewInt(1)},
+			},
+		},
+		testcase{
+			name: "at most n suggestions",
+			n:    1,
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", networkURL, httpmock.NewStringResponder(http.StatusOK, networkJSON))
+			},
+			expectedSuggestions: []*Suggestion{
+				&Suggestion{Address: hub, Channels: 3, Capacity: big.NewInt(100)},
+			},
+		},
+		testcase{
+			name: "unknown token network",
+			n:    3,
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", networkURL, httpmock.NewStringResponder(http.StatusNotFound, `{"error_code":2000,"errors":"unknown token network"}`))
+			},
+			expectedError: "pathfinding service error 2000: unknown token network",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var suggester = NewPartnerSuggester(nodeConfig, pfsConfig, http.DefaultClient)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`{"our_address":"%s"}`, ourAddress.Hex())))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/tokens/"+token.Hex(), httpmock.NewStringResponder(http.StatusOK, `"0xE5637F0103794C7e05469A9964E4563089a5E6f2"`))
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels/"+token.Hex(), httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`[{"partner_address":"%s","token_address":"%s","balance":10,"total_deposit":10,"state":"opened"}]`, partner.Hex(), token.Hex())))
+
+			tc.prepHTTPMock()
+
+			suggestions, err := suggester.SuggestPartners(context.Background(), token, tc.n)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedSuggestions, suggestions)
+		})
+	}
+}
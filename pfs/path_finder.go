@@ -0,0 +1,117 @@
+// Package pfs provides a client for a Raiden pathfinding service (PFS), which finds
+// routes through the network and estimates the mediation fees of a payment.
+package pfs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type pathsRequest struct {
+	From     string      `json:"from"`
+	To       string      `json:"to"`
+	Value    json.Number `json:"value"`
+	MaxPaths int         `json:"max_paths"`
+}
+
+type pathsResponse struct {
+	Result        []*route `json:"result"`
+	FeedbackToken string   `json:"feedback_token"`
+}
+
+type errorResponse struct {
+	ErrorCode int    `json:"error_code"`
+	Errors    string `json:"errors"`
+}
+
+// PathFinder is a generic interface to find the routes of a payment of value from
+// one address to another in a token network.
+type PathFinder interface {
+	FindPaths(ctx context.Context, tokenNetworkAddress address.TokenNetworkAddress, from, to common.Address, value *big.Int, maxPaths int) ([]*Route, error)
+}
+
+// NewPathFinder creates a new default path finder given the configuration of a
+// pathfinding service and an http client.
+func NewPathFinder(config *config.Config, httpClient *http.Client) PathFinder {
+	return &defaultPathFinder{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultPathFinder struct {
+	baseClient *util.BaseClient
+}
+
+// FindPaths will ask the pathfinding service for at most maxPaths routes, cheapest
+// first. An error is returned when the service could not find any route.
+func (finder *defaultPathFinder) FindPaths(ctx context.Context, tokenNetworkAddress address.TokenNetworkAddress, from, to common.Address, value *big.Int, maxPaths int) ([]*Route, error) {
+	var (
+		err        error
+		requestURL *url.URL
+		request    *http.Request
+		response   *http.Response
+		paths      = &pathsResponse{}
+		routes     []*Route
+	)
+
+	if requestURL, err = finder.baseClient.Endpoint("%s/paths", tokenNetworkAddress.Hex()); err != nil {
+		return nil, err
+	}
+
+	if request, err = finder.baseClient.NewRequest(ctx, "POST", requestURL, &pathsRequest{
+		From:     from.Hex(),
+		To:       to.Hex(),
+		Value:    amounts.Number(value),
+		MaxPaths: maxPaths,
+	}); err != nil {
+		return nil, err
+	}
+
+	if response, err = finder.baseClient.Do(request); err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		var pfsErr = &errorResponse{}
+
+		if err = json.NewDecoder(response.Body).Decode(pfsErr); err != nil || pfsErr.Errors == "" {
+			return nil, raidenerrors.New(response.StatusCode, fmt.Errorf("recieved %d status code from pathfinding service", response.StatusCode))
+		}
+
+		return nil, raidenerrors.New(response.StatusCode, fmt.Errorf("pathfinding service error %d: %s", pfsErr.ErrorCode, pfsErr.Errors))
+	}
+
+	if err = finder.baseClient.Decode(response.Body, paths); err != nil {
+		return nil, util.ResponseError(ctx, response.StatusCode, err)
+	}
+
+	routes = make([]*Route, 0, len(paths.Result))
+
+	for _, pathResponse := range paths.Result {
+		var route *Route
+
+		if route, err = pathResponse.toRoute(); err != nil {
+			return nil, err
+		}
+
+		routes = append(routes, route)
+	}
+
+	return routes, nil
+}
@@ -0,0 +1,128 @@
+package pfs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func ExamplePathFinder() {
+	var (
+		pfsConfig = &config.Config{
+			Host:       "https://pfs.transport01.raiden.network",
+			APIVersion: "v1",
+		}
+		finder       = NewPathFinder(pfsConfig, http.DefaultClient)
+		tokenNetwork = address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2")
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		target       = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		routes       []*Route
+		err          error
+	)
+
+	if routes, err = finder.FindPaths(context.Background(), tokenNetwork, ourAddress, target, big.NewInt(1000), 3); err != nil {
+		panic(fmt.Sprintf("unable to find routes: %s", err.Error()))
+	}
+
+	for _, route := range routes {
+		fmt.Printf("%d hops, estimated fee %d\n", route.Hops(), route.EstimatedFee)
+	}
+}
+
+func TestPathFinder(t *testing.T) {
+	var (
+		pfsConfig = &config.Config{
+			Host:       "http://localhost:6000",
+			APIVersion: "v1",
+		}
+		tokenNetwork = address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2")
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		mediator     = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		target       = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		pathsURL     = "http://localhost:6000/api/v1/0xE5637F0103794C7e05469A9964E4563089a5E6f2/paths"
+	)
+
+	type testcase struct {
+		name           string
+		prepHTTPMock   func()
+		expectedRoutes []*Route
+		expectedError  error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully found routes",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", pathsURL, func(request *http.Request) (*http.Response, error) {
+					var body = &pathsRequest{}
+
+					if err := jsonDecode(request, body); err != nil || body.MaxPaths != 3 || body.Value != "1000" || body.To != target.Hex() {
+						return httpmock.NewStringResponse(http.StatusBadRequest, ``), nil
+					}
+
+					return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(`{"result":[{"path":["%s","%s","%s"],"estimated_fee":12},{"path":["%s","%s"],"estimated_fee":0}],"feedback_token":"abc"}`,
+						ourAddress.Hex(), mediator.Hex(), target.Hex(), ourAddress.Hex(), target.Hex())), nil
+				})
+			},
+			expectedRoutes: []*Route{
+				&Route{Path: []common.Address{ourAddress, mediator, target}, EstimatedFee: big.NewInt(12)},
+				&Route{Path: []common.Address{ourAddress, target}, EstimatedFee: big.NewInt(0)},
+			},
+		},
+		testcase{
+			name: "no route found",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", pathsURL, httpmock.NewStringResponder(http.StatusNotFound, `{"error_code":2201,"errors":"No suitable path found for transfer."}`))
+			},
+			expectedError: errors.New("pathfinding service error 2201: No suitable path found for transfer."),
+		},
+		testcase{
+			name: "unexpected error response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", pathsURL, httpmock.NewStringResponder(http.StatusInternalServerError, `Internal Server Error`))
+			},
+			expectedError: errors.New("recieved 500 status code from pathfinding service"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				finder = NewPathFinder(pfsConfig, http.DefaultClient)
+				ctx    = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			routes, err := finder.FindPaths(ctx, tokenNetwork, ourAddress, target, big.NewInt(1000), 3)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedRoutes, routes)
+			assert.Equal(t, 2, routes[0].Hops())
+		})
+	}
+}
+
+func jsonDecode(request *http.Request, value interface{}) error {
+	defer request.Body.Close()
+
+	return json.NewDecoder(request.Body).Decode(value)
+}
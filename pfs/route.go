@@ -0,0 +1,123 @@
+package pfs
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The margins the Raiden node adds to the fee estimated for a route when it sends a
+// payment, in parts per million, since the fees of the mediators may have changed
+// since: of the estimated fee, and of the amount of the payment.
+const (
+	FeeMargin       = 30000
+	AmountFeeMargin = 500
+)
+
+type route struct {
+	Path         []string    `json:"path"`
+	EstimatedFee json.Number `json:"estimated_fee"`
+}
+
+func (route *route) toRoute() (*Route, error) {
+	var path = make([]common.Address, 0, len(route.Path))
+
+	estimatedFee, err := amounts.Parse(route.EstimatedFee)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, address := range route.Path {
+		path = append(path, common.HexToAddress(address))
+	}
+
+	return &Route{
+		Path:         path,
+		EstimatedFee: estimatedFee,
+	}, nil
+}
+
+// Route is a path through the Raiden network found by a pathfinding service, from
+// the initiator to the target of a payment, with the fee mediators are estimated to
+// take along it.
+type Route struct {
+	Path         []common.Address
+	EstimatedFee *big.Int
+}
+
+// Hops is the number of channels the payment goes through along the route.
+func (route *Route) Hops() int {
+	if len(route.Path) == 0 {
+		return 0
+	}
+
+	return len(route.Path) - 1
+}
+
+// FeeWithMargin returns the fee the node is estimated to lock along with a payment of
+// the amount taking the route: the estimated fee and its margins, see FeeMargin, the
+// margins rounded with the rounding, e.g. amounts.RoundCeil to reserve enough for
+// the payment. A direct route has no fee, nor margins.
+func (route *Route) FeeWithMargin(amount *big.Int, rounding amounts.Rounding) *big.Int {
+	var (
+		fee     = amounts.Copy(amounts.OrZero(route.EstimatedFee))
+		million = big.NewInt(1000000)
+	)
+
+	if route.Hops() <= 1 {
+		return fee
+	}
+
+	fee.Add(fee, rounding.Scale(route.EstimatedFee, big.NewInt(FeeMargin), million))
+	fee.Add(fee, rounding.Scale(amount, big.NewInt(AmountFeeMargin), million))
+
+	return fee
+}
+
+// MarshalJSON encodes the route in the format of the pathfinding service, with the
+// addresses as checksummed hex strings and the fee as a JSON number.
+func (route Route) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fromRoute(&route))
+}
+
+// UnmarshalJSON decodes a route in the format of the pathfinding service, which is
+// the format routes are marshalled in.
+func (route *Route) UnmarshalJSON(data []byte) error {
+	var (
+		err     error
+		decoded *Route
+	)
+
+	if decoded, err = decodeRoute(data); err != nil {
+		return err
+	}
+
+	*route = *decoded
+
+	return nil
+}
+
+func decodeRoute(data []byte) (*Route, error) {
+	var response = &route{}
+
+	if err := json.Unmarshal(data, response); err != nil {
+		return nil, err
+	}
+
+	return response.toRoute()
+}
+
+func fromRoute(value *Route) *route {
+	var path = make([]string, 0, len(value.Path))
+
+	for _, address := range value.Path {
+		path = append(path, address.Hex())
+	}
+
+	return &route{
+		Path:         path,
+		EstimatedFee: amounts.Number(value.EstimatedFee),
+	}
+}
@@ -0,0 +1,42 @@
+package pfs
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouteJSONRoundTrip(t *testing.T) {
+	var (
+		data  = `{"path":["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","0x61C808D82A3Ac53231750daDc13c777b59310bD9"],"estimated_fee":12}`
+		route = &Route{}
+	)
+
+	require.NoError(t, json.Unmarshal([]byte(data), route))
+	assert.Equal(t, 1, route.Hops())
+
+	encoded, err := json.Marshal(route)
+
+	require.NoError(t, err)
+	assert.JSONEq(t, data, string(encoded))
+}
+
+func TestRouteFeeWithMargin(t *testing.T) {
+	var (
+		path     = []common.Address{common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"), common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"), common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")}
+		mediated = &Route{Path: path, EstimatedFee: big.NewInt(100)}
+		direct   = &Route{Path: path[:2], EstimatedFee: big.NewInt(0)}
+	)
+
+	// 100 of fee, 3 of fee margin and 0.05% of 3000 of amount margin
+	assert.Equal(t, int64(104), mediated.FeeWithMargin(big.NewInt(3000), amounts.RoundFloor).Int64())
+	assert.Equal(t, int64(105), mediated.FeeWithMargin(big.NewInt(3000), amounts.RoundCeil).Int64())
+	assert.Equal(t, int64(105), mediated.FeeWithMargin(big.NewInt(3000), amounts.RoundHalfEven).Int64())
+	assert.Equal(t, int64(0), direct.FeeWithMargin(big.NewInt(3000), amounts.RoundCeil).Int64())
+}
@@ -0,0 +1,315 @@
+package pfs
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultCacheTTL is how long the routes found for a payment are reused for the
+	// same payment when the selector options give no TTL.
+	DefaultCacheTTL = 10 * time.Second
+	// latencySmoothing is the weight of the latest request in the measured latency
+	// of a service, an exponentially weighted moving average.
+	latencySmoothing = 0.3
+)
+
+// ErrNoService is returned by a selector without any pathfinding service its options
+// allow, e.g. when every service charges more than MaxPrice.
+var ErrNoService = errors.New("no pathfinding service available")
+
+// SelectorOptions configures how a selector picks the pathfinding service of a
+// request: the services charging more than MaxPrice, when given, are never asked,
+// and the others are asked by increasing measured latency, or by increasing price
+// when PreferCheapest is set. The routes found are reused for CacheTTL for the same
+// payment, DefaultCacheTTL when zero, a negative TTL disabling the cache. The TTL and
+// the latencies are measured on Clock, the system time when nil.
+type SelectorOptions struct {
+	MaxPrice       *big.Int
+	PreferCheapest bool
+	CacheTTL       time.Duration
+	Clock          clock.Clock
+}
+
+// ServiceStats are what a selector measured of a pathfinding service: the smoothed
+// Latency of its requests, zero until it answered one, its Price, nil until its info
+// was fetched, and its consecutive Failures.
+type ServiceStats struct {
+	Host     string
+	Latency  time.Duration
+	Price    *big.Int
+	Failures int
+}
+
+// Selector is a path finder spreading the requests over several pathfinding services,
+// see NewSelector.
+type Selector struct {
+	services []*service
+	options  *SelectorOptions
+	cacheTTL time.Duration
+	now      func() time.Time
+
+	mutex sync.Mutex
+	cache map[routesKey]*cachedRoutes
+}
+
+var _ PathFinder = &Selector{}
+
+type service struct {
+	pathFinder PathFinder
+	infoGetter InfoGetter
+	stats      ServiceStats
+}
+
+type routesKey struct {
+	tokenNetworkAddress address.TokenNetworkAddress
+	from                common.Address
+	to                  common.Address
+	value               string
+	maxPaths            int
+}
+
+type cachedRoutes struct {
+	routes  []*Route
+	expires time.Time
+}
+
+// NewSelector creates a selector of the pathfinding services of the configurations,
+// asking the first one that answers in the order of the options, nil for the
+// defaults, see SelectorOptions. A service failing to answer, or answering with a
+// server error, is asked after the others until it answers again.
+func NewSelector(configs []*config.Config, options *SelectorOptions, httpClient *http.Client) *Selector {
+	var selector = &Selector{
+		options:  options,
+		cacheTTL: DefaultCacheTTL,
+		cache:    make(map[routesKey]*cachedRoutes),
+	}
+
+	if selector.options == nil {
+		selector.options = &SelectorOptions{}
+	}
+
+	selector.now = clock.Or(selector.options.Clock).Now
+
+	if selector.options.CacheTTL != 0 {
+		selector.cacheTTL = selector.options.CacheTTL
+	}
+
+	for _, serviceConfig := range configs {
+		selector.services = append(selector.services, &service{
+			pathFinder: NewPathFinder(serviceConfig, httpClient),
+			infoGetter: NewInfoGetter(serviceConfig, httpClient),
+			stats:      ServiceStats{Host: serviceConfig.Host},
+		})
+	}
+
+	return selector
+}
+
+// FindPaths will return the routes found for the same payment within the cache TTL,
+// or ask the pathfinding services in turn until one of them answers. A service
+// finding no route answers, its error being returned as is.
+func (selector *Selector) FindPaths(ctx context.Context, tokenNetworkAddress address.TokenNetworkAddress, from, to common.Address, value *big.Int, maxPaths int) ([]*Route, error) {
+	var (
+		err error
+		key = routesKey{tokenNetworkAddress, from, to, amounts.OrZero(value).String(), maxPaths}
+	)
+
+	if routes, ok := selector.cached(key); ok {
+		return routes, nil
+	}
+
+	services, err := selector.order(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range services {
+		var (
+			routes  []*Route
+			started = selector.now()
+		)
+
+		routes, err = service.pathFinder.FindPaths(ctx, tokenNetworkAddress, from, to, value, maxPaths)
+
+		if statusCode := raidenerrors.StatusCode(err); err != nil && (statusCode == 0 || statusCode >= 500) {
+			selector.record(service, 0, false)
+
+			if ctx.Err() != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		selector.record(service, selector.now().Sub(started), true)
+
+		if err != nil {
+			return nil, err
+		}
+
+		selector.store(key, routes)
+
+		return routes, nil
+	}
+
+	return nil, err
+}
+
+// Stats returns what the selector measured of its pathfinding services, in the order
+// of the configurations.
+func (selector *Selector) Stats() []*ServiceStats {
+	var stats = make([]*ServiceStats, 0, len(selector.services))
+
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	for _, service := range selector.services {
+		serviceStats := service.stats
+		stats = append(stats, &serviceStats)
+	}
+
+	return stats
+}
+
+// order returns the services the options allow in the order they are to be asked,
+// fetching the price of the services that have none yet when it matters.
+func (selector *Selector) order(ctx context.Context) ([]*service, error) {
+	var (
+		services    = make([]*service, 0, len(selector.services))
+		needsPrices = selector.options.MaxPrice != nil || selector.options.PreferCheapest
+	)
+
+	for _, service := range selector.services {
+		if needsPrices && selector.price(service) == nil {
+			// a service whose info can't be fetched is asked last, at its price then
+			if info, err := service.infoGetter.GetInfo(ctx); err == nil {
+				selector.mutex.Lock()
+				service.stats.Price = info.Price
+				selector.mutex.Unlock()
+			}
+		}
+
+		if price := selector.price(service); selector.options.MaxPrice != nil && price != nil && price.Cmp(selector.options.MaxPrice) > 0 {
+			continue
+		}
+
+		services = append(services, service)
+	}
+
+	if len(services) == 0 {
+		return nil, ErrNoService
+	}
+
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	sort.SliceStable(services, func(i, j int) bool {
+		var first, second = services[i].stats, services[j].stats
+
+		if (first.Failures > 0) != (second.Failures > 0) {
+			return first.Failures == 0
+		}
+
+		if selector.options.PreferCheapest {
+			if comparison := comparePrices(first.Price, second.Price); comparison != 0 {
+				return comparison < 0
+			}
+		}
+
+		return first.Latency < second.Latency
+	})
+
+	return services, nil
+}
+
+func (selector *Selector) price(service *service) *big.Int {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	return service.stats.Price
+}
+
+// record records a request answered in latency, or a failed one.
+func (selector *Selector) record(service *service, latency time.Duration, answered bool) {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	if !answered {
+		service.stats.Failures++
+		return
+	}
+
+	service.stats.Failures = 0
+
+	if service.stats.Latency == 0 {
+		service.stats.Latency = latency
+		return
+	}
+
+	service.stats.Latency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(service.stats.Latency))
+}
+
+func (selector *Selector) cached(key routesKey) ([]*Route, bool) {
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	cached, ok := selector.cache[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !selector.now().Before(cached.expires) {
+		delete(selector.cache, key)
+		return nil, false
+	}
+
+	return cached.routes, true
+}
+
+func (selector *Selector) store(key routesKey, routes []*Route) {
+	if selector.cacheTTL < 0 {
+		return
+	}
+
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	now := selector.now()
+
+	// expired routes are dropped as new ones are stored, so the cache does not grow
+	// with the targets paid once
+	for cachedKey, cached := range selector.cache {
+		if !now.Before(cached.expires) {
+			delete(selector.cache, cachedKey)
+		}
+	}
+
+	selector.cache[key] = &cachedRoutes{routes: routes, expires: now.Add(selector.cacheTTL)}
+}
+
+// comparePrices compares two prices, an unknown price being higher than any other.
+func comparePrices(first, second *big.Int) int {
+	switch {
+	case first == nil && second == nil:
+		return 0
+	case first == nil:
+		return 1
+	case second == nil:
+		return -1
+	}
+
+	return first.Cmp(second)
+}
@@ -0,0 +1,223 @@
+package pfs
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleSelector() {
+	var (
+		selector = NewSelector([]*config.Config{
+			&config.Config{Host: "https://pfs.transport01.raiden.network", APIVersion: "v1"},
+			&config.Config{Host: "https://pfs.transport02.raiden.network", APIVersion: "v1"},
+		}, &SelectorOptions{MaxPrice: big.NewInt(1000)}, http.DefaultClient)
+		tokenNetwork = address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2")
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		target       = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	routes, err := selector.FindPaths(context.Background(), tokenNetwork, ourAddress, target, big.NewInt(1000), 3)
+	if err != nil {
+		panic(fmt.Sprintf("unable to find routes: %s", err.Error()))
+	}
+
+	for _, stats := range selector.Stats() {
+		fmt.Printf("%s answers in %s\n", stats.Host, stats.Latency)
+	}
+
+	fmt.Printf("%d routes\n", len(routes))
+}
+
+const (
+	selectorPaths = "/api/v1/0xE5637F0103794C7e05469A9964E4563089a5E6f2/paths"
+	selectorRoute = `{"result":[{"path":["0x2a65Aca4D5fC5B5C859090a6c34d164135398226","0x61C808D82A3Ac53231750daDc13c777b59310bD9"],"estimated_fee":%d}],"feedback_token":"token"}`
+)
+
+func TestSelector(t *testing.T) {
+	var (
+		configs = []*config.Config{
+			&config.Config{Host: "http://pfs1:6000", APIVersion: "v1"},
+			&config.Config{Host: "http://pfs2:6000", APIVersion: "v1"},
+		}
+		tokenNetwork = address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2")
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		target       = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	type testcase struct {
+		name          string
+		options       *SelectorOptions
+		prepHTTPMock  func()
+		expectedFee   int64
+		expectedError error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "first service",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", "http://pfs1:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(selectorRoute, 1)))
+				httpmock.RegisterResponder("POST", "http://pfs2:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(selectorRoute, 2)))
+			},
+			expectedFee: 1,
+		},
+		testcase{
+			name: "failing service",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", "http://pfs1:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusBadGateway, ""))
+				httpmock.RegisterResponder("POST", "http://pfs2:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(selectorRoute, 2)))
+			},
+			expectedFee: 2,
+		},
+		testcase{
+			name: "no route",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", "http://pfs1:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusBadRequest, `{"error_code":2201,"errors":"No route between nodes found."}`))
+				httpmock.RegisterResponder("POST", "http://pfs2:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(selectorRoute, 2)))
+			},
+			expectedError: fmt.Errorf("pathfinding service error 2201: No route between nodes found."),
+		},
+		testcase{
+			name:    "service charging too much",
+			options: &SelectorOptions{MaxPrice: big.NewInt(10)},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://pfs1:6000/api/v1/info", httpmock.NewStringResponder(http.StatusOK, `{"price_info":20}`))
+				httpmock.RegisterResponder("GET", "http://pfs2:6000/api/v1/info", httpmock.NewStringResponder(http.StatusOK, `{"price_info":5}`))
+				httpmock.RegisterResponder("POST", "http://pfs1:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(selectorRoute, 1)))
+				httpmock.RegisterResponder("POST", "http://pfs2:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(selectorRoute, 2)))
+			},
+			expectedFee: 2,
+		},
+		testcase{
+			name:    "every service charging too much",
+			options: &SelectorOptions{MaxPrice: big.NewInt(1)},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://pfs1:6000/api/v1/info", httpmock.NewStringResponder(http.StatusOK, `{"price_info":20}`))
+				httpmock.RegisterResponder("GET", "http://pfs2:6000/api/v1/info", httpmock.NewStringResponder(http.StatusOK, `{"price_info":5}`))
+			},
+			expectedError: ErrNoService,
+		},
+		testcase{
+			name:    "cheapest service",
+			options: &SelectorOptions{PreferCheapest: true},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://pfs1:6000/api/v1/info", httpmock.NewStringResponder(http.StatusOK, `{"price_info":20}`))
+				httpmock.RegisterResponder("GET", "http://pfs2:6000/api/v1/info", httpmock.NewStringResponder(http.StatusOK, `{"price_info":5}`))
+				httpmock.RegisterResponder("POST", "http://pfs1:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(selectorRoute, 1)))
+				httpmock.RegisterResponder("POST", "http://pfs2:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(selectorRoute, 2)))
+			},
+			expectedFee: 2,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			routes, err := NewSelector(configs, tc.options, http.DefaultClient).FindPaths(context.Background(), tokenNetwork, ourAddress, target, big.NewInt(1000), 3)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, routes)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, routes, 1)
+			assert.Equal(t, big.NewInt(tc.expectedFee), routes[0].EstimatedFee)
+		})
+	}
+}
+
+func TestSelectorLatency(t *testing.T) {
+	var (
+		now      = time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC)
+		selector = NewSelector([]*config.Config{
+			&config.Config{Host: "http://pfs1:6000", APIVersion: "v1"},
+			&config.Config{Host: "http://pfs2:6000", APIVersion: "v1"},
+		}, &SelectorOptions{CacheTTL: -1}, http.DefaultClient)
+		tokenNetwork = address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2")
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		target       = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	// every request takes the latency of its service on the clock of the selector
+	responder := func(latency time.Duration, fee int) httpmock.Responder {
+		return func(*http.Request) (*http.Response, error) {
+			now = now.Add(latency)
+			return httpmock.NewStringResponse(http.StatusOK, fmt.Sprintf(selectorRoute, fee)), nil
+		}
+	}
+
+	httpmock.RegisterResponder("POST", "http://pfs1:6000"+selectorPaths, responder(2*time.Second, 1))
+	httpmock.RegisterResponder("POST", "http://pfs2:6000"+selectorPaths, responder(time.Second, 2))
+
+	selector.now = func() time.Time { return now }
+
+	routes, err := selector.FindPaths(context.Background(), tokenNetwork, ourAddress, target, big.NewInt(1000), 3)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(1), routes[0].EstimatedFee)
+
+	// the second service is not measured yet, it is asked before the slow first one
+	routes, err = selector.FindPaths(context.Background(), tokenNetwork, ourAddress, target, big.NewInt(1000), 3)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2), routes[0].EstimatedFee)
+
+	routes, err = selector.FindPaths(context.Background(), tokenNetwork, ourAddress, target, big.NewInt(1000), 3)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(2), routes[0].EstimatedFee)
+
+	stats := selector.Stats()
+	assert.Equal(t, 2*time.Second, stats[0].Latency)
+	assert.Equal(t, time.Second, stats[1].Latency)
+}
+
+func TestSelectorCache(t *testing.T) {
+	var (
+		manual   = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+		selector = NewSelector([]*config.Config{
+			&config.Config{Host: "http://pfs1:6000", APIVersion: "v1"},
+		}, &SelectorOptions{Clock: manual}, http.DefaultClient)
+		tokenNetwork = address.HexToTokenNetworkAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2")
+		ourAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		target       = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		pathsURL     = "POST http://pfs1:6000" + selectorPaths
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "http://pfs1:6000"+selectorPaths, httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(selectorRoute, 1)))
+
+	for _, value := range []int64{1000, 1000, 2000} {
+		_, err := selector.FindPaths(context.Background(), tokenNetwork, ourAddress, target, big.NewInt(value), 3)
+		require.NoError(t, err)
+	}
+
+	// the second payment is the same as the first one
+	assert.Equal(t, 2, httpmock.GetCallCountInfo()[pathsURL])
+
+	manual.Advance(DefaultCacheTTL)
+
+	_, err := selector.FindPaths(context.Background(), tokenNetwork, ourAddress, target, big.NewInt(1000), 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, httpmock.GetCallCountInfo()[pathsURL])
+}
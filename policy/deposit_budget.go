@@ -0,0 +1,207 @@
+package policy
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// depositBudgetPrefix is the prefix of the keys of the counters of a DepositBudget in
+// a storage.Store.
+const depositBudgetPrefix = "policy/deposit-budget/"
+
+// DepositBudget is a policy capping the amount of every token a client commits on
+// chain in a day, UTC, by opening channels, depositing to them and joining token
+// networks, e.g. so that runaway automation can't lock up the funds of a treasury.
+// The amounts committed are counted in a storage.Store, so that the budget of the day
+// survives a restart of the process. Like with SpendLimit, the amount of an
+// authorized request is held until its operation is made and only counted when it
+// succeeded.
+//
+// A deposit request carries the total deposit of the channel: the budget remembers
+// the total deposit of the channels it authorized, and counts the amount a deposit
+// adds to it. Deposits to the channels it never saw count their whole total deposit.
+type DepositBudget struct {
+	// Clock tells the day of the operations, the system time when nil.
+	Clock clock.Clock
+
+	store  storage.Store
+	mutex  sync.Mutex
+	limit  *big.Int
+	limits map[common.Address]*big.Int
+	held   map[string]*big.Int
+}
+
+// NewDepositBudget creates a deposit budget counting in the store, capping every token
+// to the limit per day unless SetLimit gives it its own. Tokens are not capped when
+// the limit is nil.
+func NewDepositBudget(store storage.Store, limit *big.Int) *DepositBudget {
+	return &DepositBudget{
+		store:  store,
+		limit:  limit,
+		limits: make(map[common.Address]*big.Int),
+		held:   make(map[string]*big.Int),
+	}
+}
+
+// SetLimit caps the token to the limit per day, instead of the limit of the budget.
+func (budget *DepositBudget) SetLimit(tokenAddress common.Address, limit *big.Int) {
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	budget.limits[tokenAddress] = limit
+}
+
+// Committed returns the amount of the token committed today.
+func (budget *DepositBudget) Committed(tokenAddress common.Address) (*big.Int, error) {
+	return budget.load(budget.dayKey(tokenAddress))
+}
+
+// Authorize denies the opens, deposits and joins adding more of their token than is
+// left of its budget today. The other requests are allowed.
+func (budget *DepositBudget) Authorize(request *Request) (Commit, error) {
+	var (
+		err        error
+		opened     = request.Resource == "channels" && request.Method == "PUT"
+		channelKey string
+		committed  *big.Int
+	)
+
+	if request.Resource == "channels" && request.PartnerAddress != (common.Address{}) {
+		channelKey = depositBudgetPrefix + "channels/" + request.TokenAddress.Hex() + "/" + request.PartnerAddress.Hex()
+	}
+
+	if request.Amount == nil || request.Amount.Sign() <= 0 {
+		if !opened || channelKey == "" {
+			return nil, nil
+		}
+
+		// a channel opened without a deposit forgets the one of a channel with the
+		// partner settled before
+		return func(succeeded bool) {
+			if succeeded {
+				budget.store.Delete(channelKey)
+			}
+		}, nil
+	}
+
+	if request.Resource != "channels" && !(request.Resource == "connections" && request.Method == "PUT") {
+		return nil, nil
+	}
+
+	budget.mutex.Lock()
+	defer budget.mutex.Unlock()
+
+	var (
+		limit        = budget.limit
+		dayKey       = budget.dayKey(request.TokenAddress)
+		amount       = new(big.Int).Set(request.Amount)
+		totalDeposit = new(big.Int).Set(request.Amount)
+	)
+
+	if tokenLimit, ok := budget.limits[request.TokenAddress]; ok {
+		limit = tokenLimit
+	}
+
+	if channelKey != "" && !opened {
+		known, err := budget.load(channelKey)
+		if err != nil {
+			return nil, err
+		}
+
+		if amount.Sub(amount, known).Sign() <= 0 {
+			return nil, nil
+		}
+	}
+
+	if limit == nil {
+		return budget.hold(dayKey, channelKey, amount, totalDeposit), nil
+	}
+
+	if committed, err = budget.load(dayKey); err != nil {
+		return nil, err
+	}
+
+	if held, ok := budget.held[dayKey]; ok {
+		committed.Add(committed, held)
+	}
+
+	if new(big.Int).Add(committed, amount).Cmp(limit) > 0 {
+		return nil, &Violation{
+			Policy: "deposit budget",
+			Reason: fmt.Sprintf("committing %s of token %s exceeds the budget of %s per day, %s being already committed today", amount, request.TokenAddress.Hex(), limit, committed),
+		}
+	}
+
+	return budget.hold(dayKey, channelKey, amount, totalDeposit), nil
+}
+
+// hold holds the amount until the operation is made, returning the Commit counting
+// it in the day and remembering the total deposit of the channel when it succeeded.
+// The budget must be locked.
+func (budget *DepositBudget) hold(dayKey, channelKey string, amount, totalDeposit *big.Int) Commit {
+	budget.add(dayKey, amount)
+
+	return func(succeeded bool) {
+		budget.mutex.Lock()
+		defer budget.mutex.Unlock()
+
+		budget.add(dayKey, new(big.Int).Neg(amount))
+
+		if !succeeded {
+			return
+		}
+
+		// an amount the store could not count is kept held, so that the budget of
+		// the process is still enforced
+		committed, err := budget.load(dayKey)
+		if err != nil || budget.store.Put(dayKey, []byte(committed.Add(committed, amount).String())) != nil {
+			budget.add(dayKey, amount)
+		}
+
+		if channelKey != "" {
+			budget.store.Put(channelKey, []byte(totalDeposit.String()))
+		}
+	}
+}
+
+// add adds the amount to the one held for the day. The budget must be locked.
+func (budget *DepositBudget) add(dayKey string, amount *big.Int) {
+	if held, ok := budget.held[dayKey]; ok {
+		amount = new(big.Int).Add(held, amount)
+	}
+
+	if amount.Sign() == 0 {
+		delete(budget.held, dayKey)
+		return
+	}
+
+	budget.held[dayKey] = amount
+}
+
+// load returns the amount counted at the key of the store, zero when there is none.
+func (budget *DepositBudget) load(key string) (*big.Int, error) {
+	value, err := budget.store.Get(key)
+
+	switch {
+	case err == storage.ErrNotFound:
+		return new(big.Int), nil
+	case err != nil:
+		return nil, err
+	}
+
+	amount, ok := new(big.Int).SetString(string(value), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q stored at %s", value, key)
+	}
+
+	return amount, nil
+}
+
+func (budget *DepositBudget) dayKey(tokenAddress common.Address) string {
+	return depositBudgetPrefix + "days/" + tokenAddress.Hex() + "/" + clock.Or(budget.Clock).Now().UTC().Format("2006-01-02")
+}
@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDepositBudget(t *testing.T) {
+	var (
+		manual    = clock.NewManual(time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC))
+		store     = storage.NewMemoryStore()
+		budget    = NewDepositBudget(store, big.NewInt(100))
+		authorize = func(method, resource string, totalDeposit int64) (Commit, error) {
+			return budget.Authorize(&Request{Method: method, Resource: resource, TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(totalDeposit)})
+		}
+		committed = func(budget *DepositBudget) int64 {
+			amount, err := budget.Committed(tokenAddress)
+			require.NoError(t, err)
+
+			return amount.Int64()
+		}
+	)
+
+	budget.Clock = manual
+
+	commit, err := authorize("PUT", "channels", 60)
+	require.NoError(t, err)
+	commit(true)
+
+	// the deposit adds 20 to the total deposit of the channel
+	commit, err = authorize("PATCH", "channels", 80)
+	require.NoError(t, err)
+	commit(true)
+	assert.Equal(t, int64(80), committed(budget))
+
+	_, err = authorize("PATCH", "channels", 130)
+	assert.EqualError(t, err, "denied by deposit budget policy: committing 50 of token 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8 exceeds the budget of 100 per day, 80 being already committed today")
+
+	commit, err = authorize("PATCH", "channels", 90)
+	require.NoError(t, err)
+
+	_, err = authorize("PUT", "connections", 15)
+	assert.Error(t, err, "the deposit being made is held")
+
+	commit(false)
+
+	// the failed deposit does not count
+	commit, err = authorize("PUT", "connections", 15)
+	require.NoError(t, err)
+	commit(true)
+	assert.Equal(t, int64(95), committed(budget))
+
+	commit, err = authorize("POST", "payments", 50)
+	assert.NoError(t, err, "payments commit nothing on chain")
+	assert.Nil(t, commit)
+
+	_, err = budget.Authorize(&Request{Method: "PUT", Resource: "channels", TokenAddress: otherAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(100)})
+	assert.NoError(t, err, "tokens have budgets of their own")
+
+	t.Run("persisted", func(t *testing.T) {
+		var restarted = NewDepositBudget(store, big.NewInt(100))
+
+		restarted.Clock = manual
+		assert.Equal(t, int64(95), committed(restarted))
+
+		// the budget remembers the total deposit of the channel
+		commit, err := restarted.Authorize(&Request{Method: "PATCH", Resource: "channels", TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(85)})
+		require.NoError(t, err)
+		commit(true)
+		assert.Equal(t, int64(100), committed(restarted))
+	})
+
+	t.Run("next day", func(t *testing.T) {
+		manual.Advance(6 * time.Hour)
+		assert.Equal(t, int64(0), committed(budget))
+
+		budget.SetLimit(tokenAddress, big.NewInt(10))
+
+		_, err := authorize("PUT", "connections", 11)
+		assert.Error(t, err, "the token has a limit of its own")
+
+		budget.SetLimit(tokenAddress, nil)
+
+		commit, err := authorize("PUT", "connections", 1000)
+		require.NoError(t, err, "the token is not capped")
+		commit(true)
+		assert.Equal(t, int64(1000), committed(budget))
+	})
+
+	t.Run("channel opened again", func(t *testing.T) {
+		commit, err := budget.Authorize(&Request{Method: "PUT", Resource: "channels", TokenAddress: tokenAddress, PartnerAddress: partnerAddress})
+		require.NoError(t, err)
+		commit(true)
+
+		_, err = store.Get(depositBudgetPrefix + "channels/" + tokenAddress.Hex() + "/" + partnerAddress.Hex())
+		assert.Equal(t, storage.ErrNotFound, err, "the total deposit of the settled channel is forgotten")
+	})
+}
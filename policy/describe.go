@@ -0,0 +1,151 @@
+package policy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// amountFields are the JSON names of the fields of amountRequest.
+var amountFields = []string{"token_address", "partner_address", "amount", "total_deposit", "funds"}
+
+// amountRequest holds the fields of the request payloads spending tokens.
+type amountRequest struct {
+	TokenAddress   string      `json:"token_address"`
+	PartnerAddress string      `json:"partner_address"`
+	Amount         json.Number `json:"amount"`
+	TotalDeposit   json.Number `json:"total_deposit"`
+	Funds          json.Number `json:"funds"`
+}
+
+// Describe returns the operation of a request to an endpoint of version 1 of the
+// Raiden node API, from the method, the path of its URL and its JSON payload, for the
+// policies to decide on. The Amount of a deposit is the total deposit of the channel:
+// callers knowing the current deposit of the channel replace it with the amount the
+// deposit adds.
+//
+// Payloads with a field given twice, or with a field of the description in another
+// case, e.g. "AMOUNT", are refused: the node, unlike encoding/json, tells field names
+// apart by case, and would not spend the amount the policies decided on.
+func Describe(method, path string, body []byte) (*Request, error) {
+	var (
+		err      error
+		payload  = &amountRequest{}
+		result   = &Request{Method: method}
+		segments []string
+	)
+
+	// the API may be mounted under a base path, see config.Config.BasePath
+	_, _, relative, ok := config.SplitAPIPath(path)
+	if !ok || relative == "" {
+		return nil, fmt.Errorf("not an endpoint of the Raiden node API: %s", path)
+	}
+
+	segments = strings.Split(relative, "/")
+	result.Resource, segments = segments[0], segments[1:]
+
+	if len(segments) > 0 && common.IsHexAddress(segments[0]) {
+		result.TokenAddress = common.HexToAddress(segments[0])
+	}
+
+	if len(segments) > 1 && common.IsHexAddress(segments[1]) {
+		result.PartnerAddress = common.HexToAddress(segments[1])
+	}
+
+	if result.Read() || len(body) == 0 {
+		return result, nil
+	}
+
+	if err = checkFields(body); err != nil {
+		return nil, fmt.Errorf("invalid request payload: %s", err.Error())
+	}
+
+	if err = json.Unmarshal(body, payload); err != nil {
+		return nil, fmt.Errorf("invalid request payload: %s", err.Error())
+	}
+
+	if common.IsHexAddress(payload.TokenAddress) {
+		result.TokenAddress = common.HexToAddress(payload.TokenAddress)
+	}
+
+	if common.IsHexAddress(payload.PartnerAddress) {
+		result.PartnerAddress = common.HexToAddress(payload.PartnerAddress)
+	}
+
+	switch result.Resource {
+	case "payments":
+		result.Amount, err = parseAmount(payload.Amount)
+	case "connections":
+		result.Amount, err = parseAmount(payload.Funds)
+	case "channels":
+		result.Amount, err = parseAmount(payload.TotalDeposit)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// checkFields returns an error when the JSON object has a field twice, whatever the
+// case of their names, or a field of amountRequest in another case. Payloads other
+// than objects are left for json.Unmarshal to refuse.
+func checkFields(body []byte) error {
+	var (
+		decoder = json.NewDecoder(bytes.NewReader(body))
+		seen    = make(map[string]bool)
+	)
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil
+	}
+
+	for decoder.More() {
+		var value json.RawMessage
+
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		name, _ := token.(string)
+		folded := strings.ToLower(name)
+
+		if seen[folded] {
+			return fmt.Errorf("duplicate field %q", name)
+		}
+
+		seen[folded] = true
+
+		for _, field := range amountFields {
+			if folded == field && name != field {
+				return fmt.Errorf("unknown field %q", name)
+			}
+		}
+
+		if err = decoder.Decode(&value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func parseAmount(number json.Number) (*big.Int, error) {
+	if number == "" {
+		return nil, nil
+	}
+
+	amount, ok := new(big.Int).SetString(number.String(), 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %s", number)
+	}
+
+	return amount, nil
+}
@@ -0,0 +1,105 @@
+package policy
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribe(t *testing.T) {
+	type testcase struct {
+		name            string
+		method          string
+		path            string
+		body            string
+		expectedRequest *Request
+		expectedError   error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:            "read",
+			method:          "GET",
+			path:            "/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			expectedRequest: &Request{Method: "GET", Resource: "channels", TokenAddress: tokenAddress},
+		},
+		testcase{
+			name:            "payment",
+			method:          "POST",
+			path:            "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			body:            `{"amount":10,"identifier":1}`,
+			expectedRequest: &Request{Method: "POST", Resource: "payments", TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(10)},
+		},
+		testcase{
+			name:            "channel open",
+			method:          "PUT",
+			path:            "/api/v1/channels",
+			body:            `{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","total_deposit":35}`,
+			expectedRequest: &Request{Method: "PUT", Resource: "channels", TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(35)},
+		},
+		testcase{
+			name:            "join",
+			method:          "PUT",
+			path:            "/api/v1/connections/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			body:            `{"funds":100}`,
+			expectedRequest: &Request{Method: "PUT", Resource: "connections", TokenAddress: tokenAddress, Amount: big.NewInt(100)},
+		},
+		testcase{
+			name:            "base path",
+			method:          "GET",
+			path:            "/raiden/node-3/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			expectedRequest: &Request{Method: "GET", Resource: "channels", TokenAddress: tokenAddress},
+		},
+		testcase{
+			name:          "not an endpoint",
+			method:        "GET",
+			path:          "/metrics",
+			expectedError: errors.New("not an endpoint of the Raiden node API: /metrics"),
+		},
+		testcase{
+			name:          "invalid amount",
+			method:        "POST",
+			path:          "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			body:          `{"amount":1.5}`,
+			expectedError: errors.New("invalid amount 1.5"),
+		},
+		testcase{
+			name:          "duplicate amount",
+			method:        "POST",
+			path:          "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			body:          `{"amount":1000,"amount":1}`,
+			expectedError: errors.New(`invalid request payload: duplicate field "amount"`),
+		},
+		testcase{
+			name:          "amount in another case",
+			method:        "POST",
+			path:          "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			body:          `{"amount":1000,"AMOUNT":1}`,
+			expectedError: errors.New(`invalid request payload: duplicate field "AMOUNT"`),
+		},
+		testcase{
+			name:          "only amount in another case",
+			method:        "POST",
+			path:          "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			body:          `{"Amount":1}`,
+			expectedError: errors.New(`invalid request payload: unknown field "Amount"`),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			request, err := Describe(tc.method, tc.path, []byte(tc.body))
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedRequest, request)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"io/ioutil"
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+// Middleware returns a middleware authorizing every request of a client with the
+// policy before it is sent, see Describe, so that the policies of a shared node apply
+// to the calls of the application itself, e.g. with a PartnerList:
+//
+//	config.Middlewares = append(config.Middlewares, policy.Middleware(partnerList))
+//
+// A denied request is not sent and its call returns the *Violation. The operation is
+// committed as succeeded when the node answered it with a status code below 400.
+func Middleware(policy Policy) config.Middleware {
+	return func(next config.Doer) config.Doer {
+		return func(request *http.Request) (*http.Response, error) {
+			var (
+				err         error
+				body        []byte
+				description *Request
+				commit      Commit
+				response    *http.Response
+			)
+
+			if request.Body != nil && request.GetBody != nil {
+				reader, err := request.GetBody()
+				if err != nil {
+					return nil, err
+				}
+
+				body, err = ioutil.ReadAll(reader)
+				reader.Close()
+
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			if description, err = Describe(request.Method, request.URL.Path, body); err != nil {
+				return nil, err
+			}
+
+			if commit, err = policy.Authorize(description); err != nil {
+				return nil, err
+			}
+
+			response, err = next(request)
+
+			if commit != nil {
+				commit(err == nil && response.StatusCode < http.StatusBadRequest)
+			}
+
+			return response, err
+		}
+	}
+}
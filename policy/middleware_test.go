@@ -0,0 +1,58 @@
+package policy
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleMiddleware() {
+	var (
+		partnerList  = NewPartnerList()
+		raidenConfig = &config.Config{
+			Host:        "http://localhost:5001",
+			APIVersion:  "v1",
+			Middlewares: []config.Middleware{Middleware(partnerList)},
+		}
+	)
+
+	// replaced whenever the sanctions list of the deployment is updated
+	partnerList.SetDenied(otherAddress)
+
+	_, err := payments.NewInitiator(raidenConfig, http.DefaultClient).Initiate(context.Background(), tokenAddress, otherAddress, big.NewInt(10))
+	if _, denied := err.(*Violation); denied {
+		panic(err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var (
+		raidenConfig = &config.Config{
+			Host:        "http://localhost:5001",
+			APIVersion:  "v1",
+			Middlewares: []config.Middleware{Middleware(NewPartnerList(otherAddress))},
+		}
+		initiator = payments.NewInitiator(raidenConfig, http.DefaultClient)
+		baseURL   = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/"
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", baseURL+partnerAddress.Hex(), httpmock.NewStringResponder(http.StatusOK, `{"amount":10,"identifier":1}`))
+	httpmock.RegisterResponder("POST", baseURL+otherAddress.Hex(), httpmock.NewStringResponder(http.StatusOK, `{"amount":10,"identifier":1}`))
+
+	_, err := initiator.Initiate(context.Background(), tokenAddress, partnerAddress, big.NewInt(10))
+	require.NoError(t, err)
+
+	_, err = initiator.Initiate(context.Background(), tokenAddress, otherAddress, big.NewInt(10))
+	assert.IsType(t, &Violation{}, err)
+	assert.Zero(t, httpmock.GetCallCountInfo()["POST "+baseURL+otherAddress.Hex()])
+}
@@ -0,0 +1,101 @@
+package policy
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DenyPartners denies requests operating on channels with, or payments to, the given
+// partners. Requests without a partner are allowed.
+func DenyPartners(partnerAddresses ...common.Address) Policy {
+	return Func(func(request *Request) error {
+		if request.PartnerAddress == (common.Address{}) || !contains(partnerAddresses, request.PartnerAddress) {
+			return nil
+		}
+
+		return &Violation{Policy: "partner deny-list", Reason: fmt.Sprintf("partner %s is denied", request.PartnerAddress.Hex())}
+	})
+}
+
+// PartnerList is a policy denying requests operating on channels with, or payments
+// to, the partners of its deny-list, and to the partners off its allow-list when it
+// has one, e.g. to comply with sanctions in a custodial deployment. Joining a token
+// network is denied while it has either list, the node choosing the partners of the
+// channels it opens. The lists can be replaced at runtime, e.g. when a sanctions list
+// is updated, while requests are being authorized.
+type PartnerList struct {
+	mutex   sync.RWMutex
+	denied  map[common.Address]bool
+	allowed map[common.Address]bool
+}
+
+// NewPartnerList creates a partner list denying the given partners, with no
+// allow-list.
+func NewPartnerList(denied ...common.Address) *PartnerList {
+	var list = &PartnerList{}
+
+	list.SetDenied(denied...)
+
+	return list
+}
+
+// SetDenied replaces the deny-list with the given partners.
+func (list *PartnerList) SetDenied(partnerAddresses ...common.Address) {
+	var denied = set(partnerAddresses)
+
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+
+	list.denied = denied
+}
+
+// SetAllowed replaces the allow-list with the given partners, no partner being
+// denied for being off the list when none are given.
+func (list *PartnerList) SetAllowed(partnerAddresses ...common.Address) {
+	var allowed = set(partnerAddresses)
+
+	list.mutex.Lock()
+	defer list.mutex.Unlock()
+
+	list.allowed = allowed
+}
+
+// Allows reports whether channels with, and payments to, the partner are allowed.
+func (list *PartnerList) Allows(partnerAddress common.Address) bool {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+
+	return !list.denied[partnerAddress] && (len(list.allowed) == 0 || list.allowed[partnerAddress])
+}
+
+// Authorize denies the requests operating on partners the list does not allow, and
+// the joins of token networks while the list is not empty.
+func (list *PartnerList) Authorize(request *Request) (Commit, error) {
+	switch {
+	case request.Resource == "connections" && request.Method == "PUT" && list.active():
+		return nil, &Violation{Policy: "partner deny-list", Reason: "joining a token network opens channels with partners chosen by the node"}
+	case request.PartnerAddress == (common.Address{}) || list.Allows(request.PartnerAddress):
+		return nil, nil
+	}
+
+	return nil, &Violation{Policy: "partner deny-list", Reason: fmt.Sprintf("partner %s is denied", request.PartnerAddress.Hex())}
+}
+
+func (list *PartnerList) active() bool {
+	list.mutex.RLock()
+	defer list.mutex.RUnlock()
+
+	return len(list.denied) > 0 || len(list.allowed) > 0
+}
+
+func set(addresses []common.Address) map[common.Address]bool {
+	var result = make(map[common.Address]bool, len(addresses))
+
+	for _, address := range addresses {
+		result[address] = true
+	}
+
+	return result
+}
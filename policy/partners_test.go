@@ -0,0 +1,43 @@
+package policy
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartnerList(t *testing.T) {
+	var (
+		list = NewPartnerList(otherAddress)
+		pay  = func(partnerAddress common.Address) error {
+			_, err := list.Authorize(&Request{Method: "POST", Resource: "payments", TokenAddress: tokenAddress, PartnerAddress: partnerAddress})
+			return err
+		}
+		join = &Request{Method: "PUT", Resource: "connections", TokenAddress: tokenAddress}
+	)
+
+	assert.NoError(t, pay(partnerAddress))
+	assert.EqualError(t, pay(otherAddress), "denied by partner deny-list policy: partner 0x2a65Aca4D5fC5B5C859090a6c34d164135398226 is denied")
+
+	_, err := list.Authorize(join)
+	assert.EqualError(t, err, "denied by partner deny-list policy: joining a token network opens channels with partners chosen by the node")
+
+	_, err = list.Authorize(&Request{Method: "GET", Resource: "channels", TokenAddress: tokenAddress})
+	assert.NoError(t, err)
+
+	// the lists are replaced at runtime
+	list.SetDenied()
+	list.SetAllowed(otherAddress)
+
+	assert.NoError(t, pay(otherAddress))
+	assert.Error(t, pay(partnerAddress))
+	assert.False(t, list.Allows(partnerAddress))
+
+	list.SetAllowed()
+
+	assert.NoError(t, pay(partnerAddress))
+
+	_, err = list.Authorize(join)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,155 @@
+// Package policy decides whether operations on a Raiden node may be made, e.g. to
+// restrict a user to reads or to cap the amounts they pay and deposit, so that a node
+// can be shared by several users.
+package policy
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Request describes an operation on a Raiden node for the policies to decide on.
+type Request struct {
+	// Method is the HTTP method of the operation, e.g. "GET" for reads.
+	Method string
+	// Resource is the resource of the Raiden node API operated on, e.g. "channels" or
+	// "payments".
+	Resource string
+	// TokenAddress is the token operated on, the zero address when there is none.
+	TokenAddress common.Address
+	// PartnerAddress is the partner of the channel, or the target of the payment,
+	// operated on, the zero address when there is none.
+	PartnerAddress common.Address
+	// Amount is the amount of the token the operation pays or deposits, nil when it
+	// spends none.
+	Amount *big.Int
+}
+
+// Read reports whether the request only reads from the node.
+func (request *Request) Read() bool {
+	return request.Method == "GET" || request.Method == "HEAD"
+}
+
+// Violation is the error of a request denied by a policy.
+type Violation struct {
+	Policy string
+	Reason string
+}
+
+func (violation *Violation) Error() string {
+	return fmt.Sprintf("denied by %s policy: %s", violation.Policy, violation.Reason)
+}
+
+// Code returns raidenerrors.CodePolicyViolation, see raidenerrors.HasCode.
+func (violation *Violation) Code() raidenerrors.Code {
+	return raidenerrors.CodePolicyViolation
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (violation *Violation) Is(target error) bool {
+	return violation.Code().Is(target)
+}
+
+// Commit is called once the operation of an authorized request was made, telling
+// whether it succeeded, so that a policy keeping track of the operations, e.g. of the
+// amounts spent, only counts the ones that happened.
+type Commit func(succeeded bool)
+
+// Policy decides whether requests may be made. The Commit returned along with an
+// authorization may be nil.
+type Policy interface {
+	Authorize(request *Request) (Commit, error)
+}
+
+// Func is a Policy deciding on requests without keeping track of them.
+type Func func(request *Request) error
+
+// Authorize calls the function.
+func (fn Func) Authorize(request *Request) (Commit, error) {
+	return nil, fn(request)
+}
+
+// Engine authorizes requests with every one of its policies.
+type Engine struct {
+	Policies []Policy
+}
+
+// NewEngine creates an engine authorizing requests with the policies, in order.
+func NewEngine(policies ...Policy) *Engine {
+	return &Engine{Policies: policies}
+}
+
+// Authorize will return the error of the first policy denying the request, or the
+// Commit of the operation of the request when every policy authorizes it. The
+// policies before the denying one are told the operation did not succeed.
+func (engine *Engine) Authorize(request *Request) (Commit, error) {
+	var commits = make([]Commit, 0, len(engine.Policies))
+
+	commit := func(succeeded bool) {
+		for _, commit := range commits {
+			commit(succeeded)
+		}
+	}
+
+	for _, policy := range engine.Policies {
+		policyCommit, err := policy.Authorize(request)
+		if err != nil {
+			commit(false)
+			return nil, err
+		}
+
+		if policyCommit != nil {
+			commits = append(commits, policyCommit)
+		}
+	}
+
+	return commit, nil
+}
+
+// ReadOnly denies every request that does not only read from the node.
+func ReadOnly() Policy {
+	return Func(func(request *Request) error {
+		if request.Read() {
+			return nil
+		}
+
+		return &Violation{Policy: "read-only", Reason: fmt.Sprintf("%s %s is not a read", request.Method, request.Resource)}
+	})
+}
+
+// AllowTokens denies requests operating on other tokens than the given ones. Requests
+// without a token are allowed.
+func AllowTokens(tokenAddresses ...common.Address) Policy {
+	return Func(func(request *Request) error {
+		if request.TokenAddress == (common.Address{}) || contains(tokenAddresses, request.TokenAddress) {
+			return nil
+		}
+
+		return &Violation{Policy: "token allowlist", Reason: fmt.Sprintf("token %s is not allowed", request.TokenAddress.Hex())}
+	})
+}
+
+// AllowPartners denies requests operating on channels with, or payments to, other
+// partners than the given ones. Requests without a partner are allowed.
+func AllowPartners(partnerAddresses ...common.Address) Policy {
+	return Func(func(request *Request) error {
+		if request.PartnerAddress == (common.Address{}) || contains(partnerAddresses, request.PartnerAddress) {
+			return nil
+		}
+
+		return &Violation{Policy: "partner allowlist", Reason: fmt.Sprintf("partner %s is not allowed", request.PartnerAddress.Hex())}
+	})
+}
+
+func contains(addresses []common.Address, address common.Address) bool {
+	for _, candidate := range addresses {
+		if candidate == address {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,127 @@
+package policy
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	otherAddress   = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+)
+
+func TestPolicies(t *testing.T) {
+	type testcase struct {
+		name          string
+		policy        Policy
+		request       *Request
+		expectedError error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:    "read-only allows reads",
+			policy:  ReadOnly(),
+			request: &Request{Method: "GET", Resource: "channels"},
+		},
+		testcase{
+			name:          "read-only denies payments",
+			policy:        ReadOnly(),
+			request:       &Request{Method: "POST", Resource: "payments", TokenAddress: tokenAddress, Amount: big.NewInt(10)},
+			expectedError: errors.New("denied by read-only policy: POST payments is not a read"),
+		},
+		testcase{
+			name:    "token allowlist allows listed tokens",
+			policy:  AllowTokens(tokenAddress),
+			request: &Request{Method: "GET", Resource: "channels", TokenAddress: tokenAddress},
+		},
+		testcase{
+			name:    "token allowlist allows requests without a token",
+			policy:  AllowTokens(tokenAddress),
+			request: &Request{Method: "GET", Resource: "address"},
+		},
+		testcase{
+			name:          "token allowlist denies other tokens",
+			policy:        AllowTokens(tokenAddress),
+			request:       &Request{Method: "GET", Resource: "channels", TokenAddress: otherAddress},
+			expectedError: errors.New("denied by token allowlist policy: token 0x2a65Aca4D5fC5B5C859090a6c34d164135398226 is not allowed"),
+		},
+		testcase{
+			name:    "partner allowlist allows listed partners",
+			policy:  AllowPartners(partnerAddress),
+			request: &Request{Method: "POST", Resource: "payments", TokenAddress: tokenAddress, PartnerAddress: partnerAddress},
+		},
+		testcase{
+			name:          "partner allowlist denies other partners",
+			policy:        AllowPartners(partnerAddress),
+			request:       &Request{Method: "POST", Resource: "payments", TokenAddress: tokenAddress, PartnerAddress: otherAddress},
+			expectedError: errors.New("denied by partner allowlist policy: partner 0x2a65Aca4D5fC5B5C859090a6c34d164135398226 is not allowed"),
+		},
+		testcase{
+			name:    "partner deny-list allows other partners",
+			policy:  DenyPartners(otherAddress),
+			request: &Request{Method: "PUT", Resource: "channels", TokenAddress: tokenAddress, PartnerAddress: partnerAddress},
+		},
+		testcase{
+			name:          "partner deny-list denies listed partners",
+			policy:        DenyPartners(otherAddress),
+			request:       &Request{Method: "POST", Resource: "payments", TokenAddress: tokenAddress, PartnerAddress: otherAddress},
+			expectedError: errors.New("denied by partner deny-list policy: partner 0x2a65Aca4D5fC5B5C859090a6c34d164135398226 is denied"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := tc.policy.Authorize(tc.request)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.IsType(t, &Violation{}, err)
+				assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodePolicyViolation))
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestEngine(t *testing.T) {
+	var (
+		commits []bool
+		tracked = policyFunc(func(request *Request) (Commit, error) {
+			return func(succeeded bool) {
+				commits = append(commits, succeeded)
+			}, nil
+		})
+		engine = NewEngine(tracked, AllowTokens(tokenAddress))
+	)
+
+	commit, err := engine.Authorize(&Request{Method: "GET", Resource: "channels", TokenAddress: tokenAddress})
+	require.NoError(t, err)
+
+	commit(true)
+	assert.Equal(t, []bool{true}, commits)
+
+	_, err = engine.Authorize(&Request{Method: "GET", Resource: "channels", TokenAddress: otherAddress})
+	require.Error(t, err)
+
+	assert.Equal(t, []bool{true, false}, commits)
+
+	commit, err = NewEngine().Authorize(&Request{Method: "GET"})
+	require.NoError(t, err)
+	commit(true)
+}
+
+type policyFunc func(request *Request) (Commit, error)
+
+func (fn policyFunc) Authorize(request *Request) (Commit, error) {
+	return fn(request)
+}
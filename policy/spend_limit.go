@@ -0,0 +1,99 @@
+package policy
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SpendLimit returns a policy capping the amount of every token paid or deposited
+// within a window of time, e.g. a day. The amount of an authorized request is held
+// until its operation is made, so that concurrent requests cannot exceed the limit
+// together, and is only counted when the operation succeeded.
+func SpendLimit(limit *big.Int, window time.Duration) Policy {
+	return SpendLimitWithClock(limit, window, nil)
+}
+
+// SpendLimitWithClock returns a spend limit policy like SpendLimit, the window being
+// measured on the clock, the system time when nil.
+func SpendLimitWithClock(limit *big.Int, window time.Duration, clock clock.Clock) Policy {
+	return &spendLimit{
+		limit:  limit,
+		window: window,
+		clock:  clock,
+		spends: make(map[common.Address][]*spend),
+	}
+}
+
+type spendLimit struct {
+	limit  *big.Int
+	window time.Duration
+	clock  clock.Clock
+
+	mutex  sync.Mutex
+	spends map[common.Address][]*spend
+}
+
+// spend is an amount of a token spent, or held for an operation being made.
+type spend struct {
+	amount *big.Int
+	at     time.Time
+}
+
+func (policy *spendLimit) Authorize(request *Request) (Commit, error) {
+	if request.Amount == nil || request.Amount.Sign() <= 0 {
+		return nil, nil
+	}
+
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+
+	var (
+		now     = clock.Or(policy.clock).Now()
+		kept    = make([]*spend, 0, len(policy.spends[request.TokenAddress])+1)
+		current = new(big.Int)
+	)
+
+	for _, spent := range policy.spends[request.TokenAddress] {
+		if now.Sub(spent.at) < policy.window {
+			kept = append(kept, spent)
+			current.Add(current, spent.amount)
+		}
+	}
+
+	if new(big.Int).Add(current, request.Amount).Cmp(policy.limit) > 0 {
+		policy.spends[request.TokenAddress] = kept
+
+		return nil, &Violation{
+			Policy: "spend limit",
+			Reason: fmt.Sprintf("spending %s of token %s exceeds the limit of %s per %s, %s being already spent", request.Amount, request.TokenAddress.Hex(), policy.limit, policy.window, current),
+		}
+	}
+
+	held := &spend{amount: new(big.Int).Set(request.Amount), at: now}
+	policy.spends[request.TokenAddress] = append(kept, held)
+
+	return func(succeeded bool) {
+		if !succeeded {
+			policy.release(request.TokenAddress, held)
+		}
+	}, nil
+}
+
+func (policy *spendLimit) release(tokenAddress common.Address, held *spend) {
+	policy.mutex.Lock()
+	defer policy.mutex.Unlock()
+
+	var spends = policy.spends[tokenAddress]
+
+	for i, spent := range spends {
+		if spent == held {
+			policy.spends[tokenAddress] = append(spends[:i:i], spends[i+1:]...)
+			return
+		}
+	}
+}
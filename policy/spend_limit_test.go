@@ -0,0 +1,75 @@
+package policy
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpendLimit(t *testing.T) {
+	var (
+		manual = clock.NewManual(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+		policy = SpendLimitWithClock(big.NewInt(100), time.Hour, manual)
+		pay    = func(amount int64) (Commit, error) {
+			return policy.Authorize(&Request{Method: "POST", Resource: "payments", TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Amount: big.NewInt(amount)})
+		}
+	)
+
+	commit, err := pay(60)
+	require.NoError(t, err)
+	commit(true)
+
+	_, err = pay(50)
+	assert.EqualError(t, err, "denied by spend limit policy: spending 50 of token 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8 exceeds the limit of 100 per 1h0m0s, 60 being already spent")
+
+	commit, err = pay(40)
+	require.NoError(t, err)
+	commit(false)
+
+	// the failed payment does not count
+	commit, err = pay(40)
+	require.NoError(t, err)
+	commit(true)
+
+	_, err = policy.Authorize(&Request{Method: "POST", Resource: "payments", TokenAddress: otherAddress, Amount: big.NewInt(100)})
+	assert.NoError(t, err, "tokens have limits of their own")
+
+	_, err = policy.Authorize(&Request{Method: "GET", Resource: "channels", TokenAddress: tokenAddress})
+	assert.NoError(t, err, "reads spend nothing")
+
+	manual.Advance(time.Hour)
+
+	commit, err = pay(100)
+	require.NoError(t, err, "spends leave the window")
+	commit(true)
+}
+
+func TestSpendLimitConcurrentRequests(t *testing.T) {
+	var (
+		policy     = SpendLimit(big.NewInt(10), time.Hour)
+		authorized int32
+		waitGroup  sync.WaitGroup
+	)
+
+	for i := 0; i < 50; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			if _, err := policy.Authorize(&Request{Method: "POST", Resource: "payments", TokenAddress: tokenAddress, Amount: big.NewInt(1)}); err == nil {
+				atomic.AddInt32(&authorized, 1)
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+
+	assert.Equal(t, int32(10), authorized)
+}
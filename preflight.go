@@ -0,0 +1,389 @@
+package raidenclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/apiv2"
+	"github.com/cpurta/go-raiden-client/node"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/version"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The checks of a preflight, in the order they are made.
+const (
+	// CheckConnectivity passes when the node answers at all.
+	CheckConnectivity = "connectivity"
+	// CheckAuth passes when the node, or the proxy in front of it, accepts the
+	// credentials of the client.
+	CheckAuth = "auth"
+	// CheckVersion passes when the node serves the API version of the configuration
+	// in the format of its NodeVersion, and is at least the MinVersion of the options.
+	CheckVersion = "version"
+	// CheckReadiness passes when the node is synced with the chain.
+	CheckReadiness = "readiness"
+	// CheckTokens passes when the Tokens of the options are registered.
+	CheckTokens = "tokens"
+	// CheckChannels passes when the Channels of the options are open.
+	CheckChannels = "channels"
+	// CheckReachability passes when none of the Targets of the options is known to
+	// be offline, see node.ReachabilityGetter.
+	CheckReachability = "reachability"
+)
+
+// The results of the checks of a preflight.
+const (
+	PreflightPassed = "passed"
+	PreflightFailed = "failed"
+	// PreflightSkipped is a check not made, because it was not asked for or because
+	// the node can't be reached.
+	PreflightSkipped = "skipped"
+)
+
+// PreflightOptions are the optional checks of a preflight. Tokens must be registered
+// on the node, and the node must have an open channel with the partners of Channels
+// in their token, e.g. the tokens of the TokenDefaults of the configuration and the
+// hubs a service pays through, and the Targets it pays must not be offline. A
+// syncing node passes the readiness check when AllowSyncing is set, its payments
+// waiting for it to catch up.
+type PreflightOptions struct {
+	MinVersion   string
+	Tokens       []common.Address
+	Channels     map[common.Address][]common.Address
+	Targets      []common.Address
+	AllowSyncing bool
+}
+
+// PreflightCheck is the Result of a check of a preflight, Detail telling why it did
+// not pass, or what it found when it did.
+type PreflightCheck struct {
+	Name   string `json:"name"`
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// PreflightReport is what a preflight found about the node: its Address, Version and
+// Status when it could get them, and the result of every check in their order.
+type PreflightReport struct {
+	Address common.Address    `json:"address"`
+	Version string            `json:"version,omitempty"`
+	Status  *node.Status      `json:"status,omitempty"`
+	Checks  []*PreflightCheck `json:"checks"`
+}
+
+// Passed tells whether no check of the preflight failed.
+func (report *PreflightReport) Passed() bool {
+	return len(report.Failed()) == 0
+}
+
+// Failed returns the checks of the preflight which failed, in their order.
+func (report *PreflightReport) Failed() []*PreflightCheck {
+	var failed = make([]*PreflightCheck, 0)
+
+	for _, check := range report.Checks {
+		if check.Result == PreflightFailed {
+			failed = append(failed, check)
+		}
+	}
+
+	return failed
+}
+
+// Check returns the check of the preflight with the name, nil when there is none.
+func (report *PreflightReport) Check(name string) *PreflightCheck {
+	for _, check := range report.Checks {
+		if check.Name == name {
+			return check
+		}
+	}
+
+	return nil
+}
+
+// PreflightError is returned by a preflight with Failed checks, along with the
+// report.
+type PreflightError struct {
+	Failed []*PreflightCheck
+}
+
+func (err *PreflightError) Error() string {
+	var reasons = make([]string, 0, len(err.Failed))
+
+	for _, check := range err.Failed {
+		reasons = append(reasons, check.Name+": "+check.Detail)
+	}
+
+	return "raiden node failed preflight: " + strings.Join(reasons, "; ")
+}
+
+// Code returns raidenerrors.CodePreflightFailed, see raidenerrors.HasCode.
+func (err *PreflightError) Code() raidenerrors.Code {
+	return raidenerrors.CodePreflightFailed
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *PreflightError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// Preflight will check that the node can serve the application, e.g. when a service
+// starts and before it accepts traffic: that the node answers, accepts the
+// credentials of the client, runs a version of the API the client is configured for
+// and is synced with the chain, then the optional checks of the options, which may
+// be nil. The report is returned whatever the result, along with a *PreflightError
+// when a check failed. The checks after the ones of connectivity and auth are
+// skipped when those fail.
+func (client *Client) Preflight(ctx context.Context, options *PreflightOptions) (*PreflightReport, error) {
+	var (
+		err    error
+		report = &PreflightReport{Checks: make([]*PreflightCheck, 0, 7)}
+		add    = func(name, result, detail string) {
+			report.Checks = append(report.Checks, &PreflightCheck{Name: name, Result: result, Detail: detail})
+		}
+	)
+
+	if options == nil {
+		options = &PreflightOptions{}
+	}
+
+	report.Address, err = client.Node().Address(ctx)
+
+	switch statusCode := raidenerrors.StatusCode(err); {
+	case err != nil && statusCode == 0:
+		add(CheckConnectivity, PreflightFailed, err.Error())
+		add(CheckAuth, PreflightSkipped, "")
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		add(CheckConnectivity, PreflightPassed, fmt.Sprintf("the node answered with status %d", statusCode))
+		add(CheckAuth, PreflightFailed, err.Error())
+	case err != nil:
+		add(CheckConnectivity, PreflightPassed, fmt.Sprintf("the node answered with status %d", statusCode))
+		add(CheckAuth, PreflightPassed, "")
+	default:
+		add(CheckConnectivity, PreflightPassed, "node "+report.Address.Hex())
+		add(CheckAuth, PreflightPassed, "")
+	}
+
+	if !report.Passed() {
+		for _, name := range []string{CheckVersion, CheckReadiness, CheckTokens, CheckChannels, CheckReachability} {
+			add(name, PreflightSkipped, "")
+		}
+
+		return report, &PreflightError{Failed: report.Failed()}
+	}
+
+	if report.Version, err = client.Node().Version(ctx); err != nil {
+		add(CheckVersion, PreflightFailed, err.Error())
+	} else if reason := client.incompatible(report.Version, options.MinVersion); reason != "" {
+		add(CheckVersion, PreflightFailed, reason)
+	} else {
+		add(CheckVersion, PreflightPassed, report.Version)
+	}
+
+	if report.Status, err = client.Node().Status(ctx); err != nil {
+		add(CheckReadiness, PreflightFailed, err.Error())
+	} else {
+		switch report.Status.Status {
+		case node.StatusReady:
+			add(CheckReadiness, PreflightPassed, "")
+		case node.StatusUnknown:
+			// nodes without the status endpoint only answer once they are synced
+			add(CheckReadiness, PreflightPassed, "the node does not report its status")
+		case node.StatusSyncing:
+			var detail = fmt.Sprintf("%d blocks to sync", report.Status.BlocksToSync)
+
+			if options.AllowSyncing {
+				add(CheckReadiness, PreflightPassed, detail)
+			} else {
+				add(CheckReadiness, PreflightFailed, detail)
+			}
+		default:
+			add(CheckReadiness, PreflightFailed, "the node is "+report.Status.Status)
+		}
+	}
+
+	if len(options.Tokens) == 0 {
+		add(CheckTokens, PreflightSkipped, "")
+	} else if missing, err := client.missingTokens(ctx, options.Tokens); err != nil {
+		add(CheckTokens, PreflightFailed, err.Error())
+	} else if len(missing) > 0 {
+		add(CheckTokens, PreflightFailed, "not registered: "+strings.Join(missing, ", "))
+	} else {
+		add(CheckTokens, PreflightPassed, "")
+	}
+
+	if len(options.Channels) == 0 {
+		add(CheckChannels, PreflightSkipped, "")
+	} else if missing := client.missingChannels(ctx, options.Channels); len(missing) > 0 {
+		add(CheckChannels, PreflightFailed, strings.Join(missing, "; "))
+	} else {
+		add(CheckChannels, PreflightPassed, "")
+	}
+
+	if len(options.Targets) == 0 {
+		add(CheckReachability, PreflightSkipped, "")
+	} else if unreachable, unknown, err := client.unreachableTargets(ctx, options.Targets); err != nil {
+		add(CheckReachability, PreflightFailed, err.Error())
+	} else if len(unreachable) > 0 {
+		add(CheckReachability, PreflightFailed, "offline: "+strings.Join(unreachable, ", "))
+	} else if len(unknown) > 0 {
+		// the node may not expose the presence of partners at all
+		add(CheckReachability, PreflightPassed, "reachability unknown: "+strings.Join(unknown, ", "))
+	} else {
+		add(CheckReachability, PreflightPassed, "")
+	}
+
+	if !report.Passed() {
+		return report, &PreflightError{Failed: report.Failed()}
+	}
+
+	return report, nil
+}
+
+// incompatible tells why the client can't use the node running the version, empty
+// when it can.
+func (client *Client) incompatible(nodeVersion, minVersion string) string {
+	var configured = client.config.NodeVersion
+
+	if client.config.APIVersion == apiv2.Version && nodeVersion == version.Legacy {
+		return fmt.Sprintf("the client is configured for API %s, which the node does not serve", apiv2.Version)
+	}
+
+	if configured != "" && compareVersions(configured, nodeVersion, 1) != 0 {
+		return fmt.Sprintf("the client is configured for version %s, the node runs %s", configured, nodeVersion)
+	}
+
+	if minVersion != "" && compareVersions(nodeVersion, minVersion, 0) < 0 {
+		return fmt.Sprintf("the node runs %s, older than %s", nodeVersion, minVersion)
+	}
+
+	return ""
+}
+
+// missingTokens returns the tokens which are not registered on the node.
+func (client *Client) missingTokens(ctx context.Context, tokenAddresses []common.Address) ([]string, error) {
+	var (
+		registered = make(map[common.Address]bool)
+		missing    = make([]string, 0)
+	)
+
+	listed, err := client.Tokens().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, tokenAddress := range listed {
+		registered[tokenAddress] = true
+	}
+
+	for _, tokenAddress := range tokenAddresses {
+		if !registered[tokenAddress] {
+			missing = append(missing, tokenAddress.Hex())
+		}
+	}
+
+	return missing, nil
+}
+
+// missingChannels returns why the channels with the partners, by token, are not
+// open, in the order of the tokens and of the partners.
+func (client *Client) missingChannels(ctx context.Context, partners map[common.Address][]common.Address) []string {
+	var (
+		missing        = make([]string, 0)
+		tokenAddresses = make([]common.Address, 0, len(partners))
+	)
+
+	for tokenAddress := range partners {
+		tokenAddresses = append(tokenAddresses, tokenAddress)
+	}
+
+	sort.Slice(tokenAddresses, func(i, j int) bool {
+		return bytes.Compare(tokenAddresses[i].Bytes(), tokenAddresses[j].Bytes()) < 0
+	})
+
+	for _, tokenAddress := range tokenAddresses {
+		for _, partnerAddress := range partners[tokenAddress] {
+			var name = fmt.Sprintf("channel with %s in %s", partnerAddress.Hex(), tokenAddress.Hex())
+
+			channel, err := client.Channels().Get(ctx, tokenAddress, partnerAddress)
+
+			switch {
+			case raidenerrors.StatusCode(err) == http.StatusNotFound:
+				missing = append(missing, name+" does not exist")
+			case err != nil:
+				missing = append(missing, name+": "+err.Error())
+			case channel.State != "opened":
+				missing = append(missing, name+" is "+channel.State)
+			}
+		}
+	}
+
+	return missing
+}
+
+// unreachableTargets returns the targets known to be offline, and the ones whose
+// reachability the node does not know, in their order.
+func (client *Client) unreachableTargets(ctx context.Context, targetAddresses []common.Address) ([]string, []string, error) {
+	var (
+		unreachable = make([]string, 0)
+		unknown     = make([]string, 0)
+	)
+
+	for _, targetAddress := range targetAddresses {
+		reachability, err := client.Node().PartnerReachable(ctx, targetAddress)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch {
+		case reachability.Unreachable():
+			unreachable = append(unreachable, targetAddress.Hex())
+		case !reachability.Reachable():
+			unknown = append(unknown, targetAddress.Hex())
+		}
+	}
+
+	return unreachable, unknown, nil
+}
+
+// compareVersions compares the first parts of two Raiden versions, e.g. "1.1.1" and
+// "v1.0.0.dev3", all of them when parts is zero, returning -1, 0 or 1 as the first is
+// older, the same or newer. The parts which are not numbers compare as 0.
+func compareVersions(first, second string, parts int) int {
+	var (
+		firstParts  = strings.Split(strings.TrimPrefix(first, "v"), ".")
+		secondParts = strings.Split(strings.TrimPrefix(second, "v"), ".")
+		part        = func(parts []string, i int) int {
+			if i >= len(parts) {
+				return 0
+			}
+
+			value, _ := strconv.Atoi(parts[i])
+
+			return value
+		}
+	)
+
+	if parts == 0 {
+		parts = len(firstParts)
+		if len(secondParts) > parts {
+			parts = len(secondParts)
+		}
+	}
+
+	for i := 0; i < parts; i++ {
+		switch a, b := part(firstParts, i), part(secondParts, i); {
+		case a < b:
+			return -1
+		case a > b:
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -0,0 +1,241 @@
+package raidenclient
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleClient_Preflight() {
+	var (
+		raidenConfig = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		raidenClient = NewClient(raidenConfig, http.DefaultClient)
+		options      = &PreflightOptions{
+			MinVersion: "1.1.0",
+			Tokens:     []common.Address{common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359")}, // DAI Stablecoin
+		}
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	// refuse to start rather than failing the payments of the first customers
+	report, err := raidenClient.Preflight(ctx, options)
+	if err != nil {
+		log.Fatalln("not starting:", err.Error())
+	}
+
+	log.Println("raiden node", report.Address.Hex(), "running", report.Version, "is ready")
+}
+
+func TestPreflight(t *testing.T) {
+	type response struct {
+		status int
+		body   string
+	}
+
+	type testcase struct {
+		name            string
+		config          *config.Config
+		options         *PreflightOptions
+		responses       map[string]response
+		expectedResults map[string]string
+		expectedError   string
+	}
+
+	var (
+		tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		otherToken     = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		targetAddress  = common.HexToAddress("0x1f7402f55e142820ea3812106d0657103fc1709e")
+		channelPath    = "/api/v1/channels/" + tokenAddress.Hex() + "/" + partnerAddress.Hex()
+		healthy        = func(overrides map[string]response) map[string]response {
+			var responses = map[string]response{
+				"/api/v1/address": response{http.StatusOK, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`},
+				"/api/v1/version": response{http.StatusOK, `{"version":"1.1.1"}`},
+				"/api/v1/status":  response{http.StatusOK, `{"status":"ready"}`},
+				"/api/v1/tokens":  response{http.StatusOK, `["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"]`},
+				channelPath:       response{http.StatusOK, `{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","state":"opened"}`},
+			}
+
+			for path, response := range overrides {
+				responses[path] = response
+			}
+
+			return responses
+		}
+		results = func(connectivity, auth, version, readiness, tokens, channels, reachability string) map[string]string {
+			return map[string]string{
+				CheckConnectivity: connectivity,
+				CheckAuth:         auth,
+				CheckVersion:      version,
+				CheckReadiness:    readiness,
+				CheckTokens:       tokens,
+				CheckChannels:     channels,
+				CheckReachability: reachability,
+			}
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:            "ready node",
+			responses:       healthy(nil),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightPassed, PreflightPassed, PreflightSkipped, PreflightSkipped, PreflightSkipped),
+		},
+		testcase{
+			name: "configured tokens and channels",
+			options: &PreflightOptions{
+				MinVersion: "1.1.0",
+				Tokens:     []common.Address{tokenAddress},
+				Channels:   map[common.Address][]common.Address{tokenAddress: []common.Address{partnerAddress}},
+			},
+			responses:       healthy(nil),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightPassed, PreflightPassed, PreflightPassed, PreflightPassed, PreflightSkipped),
+		},
+		testcase{
+			name:            "unauthorized",
+			responses:       healthy(map[string]response{"/api/v1/address": response{http.StatusUnauthorized, `{"errors":"invalid token"}`}}),
+			expectedResults: results(PreflightPassed, PreflightFailed, PreflightSkipped, PreflightSkipped, PreflightSkipped, PreflightSkipped, PreflightSkipped),
+			expectedError:   "raiden node failed preflight: auth: raiden node error 401: invalid token",
+		},
+		testcase{
+			name:            "older than the minimum version",
+			options:         &PreflightOptions{MinVersion: "1.2"},
+			responses:       healthy(nil),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightFailed, PreflightPassed, PreflightSkipped, PreflightSkipped, PreflightSkipped),
+			expectedError:   "raiden node failed preflight: version: the node runs 1.1.1, older than 1.2",
+		},
+		testcase{
+			name:            "configured for another major version",
+			config:          &config.Config{APIVersion: "v1", NodeVersion: "0.100.3"},
+			responses:       healthy(nil),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightFailed, PreflightPassed, PreflightSkipped, PreflightSkipped, PreflightSkipped),
+			expectedError:   "raiden node failed preflight: version: the client is configured for version 0.100.3, the node runs 1.1.1",
+		},
+		testcase{
+			name:            "syncing node",
+			responses:       healthy(map[string]response{"/api/v1/status": response{http.StatusOK, `{"status":"syncing","blocks_to_sync":120}`}}),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightPassed, PreflightFailed, PreflightSkipped, PreflightSkipped, PreflightSkipped),
+			expectedError:   "raiden node failed preflight: readiness: 120 blocks to sync",
+		},
+		testcase{
+			name:            "syncing node allowed",
+			options:         &PreflightOptions{AllowSyncing: true},
+			responses:       healthy(map[string]response{"/api/v1/status": response{http.StatusOK, `{"status":"syncing","blocks_to_sync":120}`}}),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightPassed, PreflightPassed, PreflightSkipped, PreflightSkipped, PreflightSkipped),
+		},
+		testcase{
+			name: "missing token and closed channel",
+			options: &PreflightOptions{
+				Tokens:   []common.Address{tokenAddress, otherToken},
+				Channels: map[common.Address][]common.Address{tokenAddress: []common.Address{partnerAddress}},
+			},
+			responses: healthy(map[string]response{
+				channelPath: response{http.StatusOK, `{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","state":"closed"}`},
+			}),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightPassed, PreflightPassed, PreflightFailed, PreflightFailed, PreflightSkipped),
+			expectedError:   "raiden node failed preflight: tokens: not registered: 0x2a65Aca4D5fC5B5C859090a6c34d164135398226; channels: channel with 0x61C808D82A3Ac53231750daDc13c777b59310bD9 in 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8 is closed",
+		},
+		testcase{
+			name:    "offline target",
+			options: &PreflightOptions{Targets: []common.Address{partnerAddress, otherToken, targetAddress}},
+			responses: healthy(map[string]response{
+				"/api/v1/_debug/reachability/" + partnerAddress.Hex(): response{http.StatusOK, `{"reachability":"reachable"}`},
+				"/api/v1/_debug/reachability/" + targetAddress.Hex():  response{http.StatusOK, `{"reachability":"unreachable"}`},
+			}),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightPassed, PreflightPassed, PreflightSkipped, PreflightSkipped, PreflightFailed),
+			expectedError:   "raiden node failed preflight: reachability: offline: 0x1f7402f55e142820EA3812106D0657103fC1709e",
+		},
+		testcase{
+			name:            "node without the reachability endpoint",
+			options:         &PreflightOptions{Targets: []common.Address{targetAddress}},
+			responses:       healthy(nil),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightPassed, PreflightPassed, PreflightSkipped, PreflightSkipped, PreflightPassed),
+		},
+		testcase{
+			name:    "channel does not exist",
+			options: &PreflightOptions{Channels: map[common.Address][]common.Address{tokenAddress: []common.Address{partnerAddress}}},
+			responses: healthy(map[string]response{
+				channelPath: response{http.StatusNotFound, `{"errors":"channel does not exist"}`},
+			}),
+			expectedResults: results(PreflightPassed, PreflightPassed, PreflightPassed, PreflightPassed, PreflightSkipped, PreflightFailed, PreflightSkipped),
+			expectedError:   "raiden node failed preflight: channels: channel with 0x61C808D82A3Ac53231750daDc13c777b59310bD9 in 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8 does not exist",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				node = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+					response, ok := tc.responses[request.URL.Path]
+					if !ok {
+						http.NotFound(writer, request)
+						return
+					}
+
+					writer.WriteHeader(response.status)
+					writer.Write([]byte(response.body))
+				}))
+				raidenConfig = &config.Config{APIVersion: "v1"}
+			)
+			defer node.Close()
+
+			if tc.config != nil {
+				raidenConfig = tc.config
+			}
+
+			raidenConfig.Host = node.URL
+
+			report, err := NewClient(raidenConfig, nil).Preflight(context.Background(), tc.options)
+			require.NotNil(t, report)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.False(t, report.Passed())
+			} else {
+				assert.NoError(t, err)
+				assert.True(t, report.Passed())
+			}
+
+			results := make(map[string]string)
+			for _, check := range report.Checks {
+				results[check.Name] = check.Result
+			}
+
+			assert.Equal(t, tc.expectedResults, results)
+		})
+	}
+
+	t.Run("unreachable node", func(t *testing.T) {
+		var node = httptest.NewServer(http.NotFoundHandler())
+		node.Close()
+
+		report, err := NewClient(&config.Config{Host: node.URL, APIVersion: "v1"}, nil).Preflight(context.Background(), nil)
+		require.Error(t, err)
+		assert.Equal(t, PreflightFailed, report.Check(CheckConnectivity).Result)
+		assert.Equal(t, PreflightSkipped, report.Check(CheckReadiness).Result)
+		assert.Len(t, err.(*PreflightError).Failed, 1)
+		assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodePreflightFailed))
+	})
+}
+
+func TestCompareVersions(t *testing.T) {
+	assert.Equal(t, 0, compareVersions("1.1.1", "v1.1.1", 0))
+	assert.Equal(t, -1, compareVersions("1.1.1", "1.2", 0))
+	assert.Equal(t, 1, compareVersions("1.1.1.dev3", "1.1", 0))
+	assert.Equal(t, 0, compareVersions("1.1.1", "1.0.0", 1))
+	assert.Equal(t, -1, compareVersions("0.100.3", "1.0.0", 1))
+}
@@ -0,0 +1,264 @@
+// Package progress reports the progress of long operations on a Raiden node, such as
+// channel opens waiting for on-chain confirmations or payments waiting for their
+// lock to be unlocked, so that user interfaces can show more than a spinner. The node
+// only answers once an operation is done, progress is derived from polling it in the
+// meantime.
+package progress
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultInterval is the time between two polls of the node for the progress of an
+// operation when the options give none.
+const DefaultInterval = 2 * time.Second
+
+// Stage is a stage of an operation.
+type Stage string
+
+// Stages of the operations, in the order they are reached.
+const (
+	// Submitted is an operation sent to the node, the first stage of every
+	// operation.
+	Submitted Stage = "submitted"
+	// Mined is a transaction of the node included in a block, e.g. the channel of an
+	// open listed as opened.
+	Mined Stage = "mined"
+	// Confirmed is a transaction the node answered for, having waited for the
+	// confirmation blocks it requires.
+	Confirmed Stage = "confirmed"
+	// RouteFound is a payment the node found a route for.
+	RouteFound Stage = "route_found"
+	// Locked is a payment whose amount is locked in the channel of its route.
+	Locked Stage = "locked"
+	// Unlocked is a payment whose lock the target unlocked, ending it.
+	Unlocked Stage = "unlocked"
+	// Draining is a node migration waiting for the payments in flight on the old node
+	// to end.
+	Draining Stage = "draining"
+	// Drained is a node migration whose old node has no payment in flight left.
+	Drained Stage = "drained"
+	// Verified is a node migration whose new node has the channels and balances of
+	// the old one.
+	Verified Stage = "verified"
+	// Switched is a node migration whose traffic was switched over to the new node,
+	// ending it.
+	Switched Stage = "switched"
+)
+
+var (
+	// TransactionStages are the stages of the operations making an on-chain
+	// transaction, i.e. channel opens, deposits, withdraws and closes.
+	TransactionStages = []Stage{Submitted, Mined, Confirmed}
+	// PaymentStages are the stages of payments.
+	PaymentStages = []Stage{Submitted, RouteFound, Locked, Unlocked}
+	// MigrationStages are the stages of node migrations.
+	MigrationStages = []Stage{Draining, Drained, Verified, Switched}
+)
+
+// Operations reported.
+const (
+	OperationChannelOpen     = "channel_open"
+	OperationChannelDeposit  = "channel_deposit"
+	OperationChannelWithdraw = "channel_withdraw"
+	OperationChannelClose    = "channel_close"
+	OperationPayment         = "payment"
+	OperationMigration       = "migration"
+)
+
+// Event reports that an operation reached a stage, the Step of Steps of the
+// operation, Elapsed after it was submitted. An operation that failed is reported
+// once more with the stage it reached and the error it failed with.
+type Event struct {
+	Operation string
+	Stage     Stage
+	Step      int
+	Steps     int
+	Elapsed   time.Duration
+	Err       error
+}
+
+// Options configures the progress reporting of the operations made with a context:
+// Report is called with every event, from the goroutine of the operation or the one
+// polling it but never concurrently for an operation, and the node is polled every
+// Interval.
+type Options struct {
+	Report   func(event *Event)
+	Interval time.Duration
+}
+
+type optionsKey struct{}
+
+// WithOptions returns a context whose operations report their progress with the
+// options. The operations of other contexts are not polled.
+func WithOptions(ctx context.Context, options *Options) context.Context {
+	return context.WithValue(ctx, optionsKey{}, options)
+}
+
+// OptionsFromContext returns the progress options of the context, or nil when it has
+// none.
+func OptionsFromContext(ctx context.Context) *Options {
+	options, _ := ctx.Value(optionsKey{}).(*Options)
+
+	return options
+}
+
+// Poll returns the furthest stage of an operation the node shows, or an empty stage
+// when it shows none yet. Errors are ignored, polling being best effort.
+type Poll func(ctx context.Context) (Stage, error)
+
+// Track runs the operation and reports its progress with the options of the context,
+// if any: the first stage when it starts, the stages returned by poll while it runs,
+// along with the stages before them that were skipped, and the stages left when it
+// succeeds. A nil poll only reports the first and last stages.
+func Track(ctx context.Context, operation string, stages []Stage, poll Poll, run func(ctx context.Context) error) error {
+	var (
+		options = OptionsFromContext(ctx)
+		report  *reporter
+		done    = make(chan struct{})
+		polled  sync.WaitGroup
+	)
+
+	if options == nil || options.Report == nil || len(stages) == 0 {
+		return run(ctx)
+	}
+
+	report = &reporter{
+		operation: operation,
+		stages:    stages,
+		report:    options.Report,
+		start:     time.Now(),
+	}
+
+	report.reach(stages[0])
+
+	if poll != nil {
+		interval := options.Interval
+		if interval <= 0 {
+			interval = DefaultInterval
+		}
+
+		polled.Add(1)
+
+		go func() {
+			defer polled.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				case <-time.After(interval):
+				}
+
+				if stage, err := poll(ctx); err == nil && stage != "" {
+					report.reach(stage)
+				}
+			}
+		}()
+	}
+
+	err := run(ctx)
+
+	close(done)
+	polled.Wait()
+
+	if err != nil {
+		report.fail(err)
+		return err
+	}
+
+	report.reach(stages[len(stages)-1])
+
+	return nil
+}
+
+// Reporter reports the progress of an operation whose stages are reached by the steps
+// of the caller rather than polled, e.g. a node migration. Its methods do nothing
+// when the context it was started with has no progress options.
+type Reporter struct {
+	reporter *reporter
+}
+
+// Start reports the first stage of the operation with the options of the context,
+// if any, returning the reporter of its next stages.
+func Start(ctx context.Context, operation string, stages []Stage) *Reporter {
+	var options = OptionsFromContext(ctx)
+
+	if options == nil || options.Report == nil || len(stages) == 0 {
+		return &Reporter{}
+	}
+
+	report := &reporter{
+		operation: operation,
+		stages:    stages,
+		report:    options.Report,
+		start:     time.Now(),
+	}
+
+	report.reach(stages[0])
+
+	return &Reporter{reporter: report}
+}
+
+// Reach reports the stage, along with the stages before it that were skipped.
+func (reporter *Reporter) Reach(stage Stage) {
+	if reporter.reporter != nil {
+		reporter.reporter.reach(stage)
+	}
+}
+
+// Fail reports that the operation failed with the error at the stage it reached.
+func (reporter *Reporter) Fail(err error) {
+	if reporter.reporter != nil {
+		reporter.reporter.fail(err)
+	}
+}
+
+// reporter reports the stages of an operation in order, at most once each.
+type reporter struct {
+	operation string
+	stages    []Stage
+	report    func(event *Event)
+	start     time.Time
+
+	mutex   sync.Mutex
+	reached int
+}
+
+func (reporter *reporter) reach(stage Stage) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	for step, reached := range reporter.stages {
+		if reached != stage {
+			continue
+		}
+
+		for reporter.reached <= step {
+			reporter.reached++
+			reporter.report(reporter.event(reporter.reached, nil))
+		}
+
+		return
+	}
+}
+
+func (reporter *reporter) fail(err error) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.report(reporter.event(reporter.reached, err))
+}
+
+func (reporter *reporter) event(step int, err error) *Event {
+	return &Event{
+		Operation: reporter.operation,
+		Stage:     reporter.stages[step-1],
+		Step:      step,
+		Steps:     len(reporter.stages),
+		Elapsed:   time.Since(reporter.start),
+		Err:       err,
+	}
+}
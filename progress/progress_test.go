@@ -0,0 +1,169 @@
+package progress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleWithOptions() {
+	var ctx = WithOptions(context.Background(), &Options{
+		Report: func(event *Event) {
+			fmt.Printf("%s %d/%d %s after %s\n", event.Operation, event.Step, event.Steps, event.Stage, event.Elapsed)
+		},
+		Interval: time.Second,
+	})
+
+	// e.g. the channel opens and payments of the client made with ctx now report
+	// their stages as they reach them
+	_ = ctx
+}
+
+func TestTrack(t *testing.T) {
+	type testcase struct {
+		name           string
+		stages         []Stage
+		polled         []Stage
+		runErr         error
+		expectedStages []Stage
+		expectedErr    error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "polled stages",
+			stages:         TransactionStages,
+			polled:         []Stage{"", Submitted, Mined, Mined},
+			expectedStages: []Stage{Submitted, Mined, Confirmed},
+		},
+		testcase{
+			name:           "skipped stages are reported",
+			stages:         PaymentStages,
+			polled:         []Stage{Locked},
+			expectedStages: []Stage{Submitted, RouteFound, Locked, Unlocked},
+		},
+		testcase{
+			name:           "nothing polled",
+			stages:         TransactionStages,
+			expectedStages: []Stage{Submitted, Mined, Confirmed},
+		},
+		testcase{
+			name:           "failed operation",
+			stages:         TransactionStages,
+			polled:         []Stage{Mined},
+			runErr:         errors.New("node unavailable"),
+			expectedStages: []Stage{Submitted, Mined, Mined},
+			expectedErr:    errors.New("node unavailable"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mutex  sync.Mutex
+				events []*Event
+				polls  = make(chan struct{})
+				ctx    = WithOptions(context.Background(), &Options{
+					Report: func(event *Event) {
+						mutex.Lock()
+						defer mutex.Unlock()
+
+						events = append(events, event)
+					},
+					Interval: time.Millisecond,
+				})
+				polled = 0
+			)
+
+			poll := func(ctx context.Context) (Stage, error) {
+				if polled == len(tc.polled) {
+					return "", errors.New("polled out")
+				}
+
+				polled++
+
+				if polled == len(tc.polled) {
+					close(polls)
+				}
+
+				return tc.polled[polled-1], nil
+			}
+
+			err := Track(ctx, OperationChannelOpen, tc.stages, poll, func(ctx context.Context) error {
+				if len(tc.polled) > 0 {
+					<-polls
+				}
+
+				return tc.runErr
+			})
+
+			assert.Equal(t, tc.expectedErr, err)
+
+			stages := make([]Stage, 0, len(events))
+			for step, event := range events {
+				stages = append(stages, event.Stage)
+
+				assert.Equal(t, OperationChannelOpen, event.Operation)
+				assert.Equal(t, len(tc.stages), event.Steps)
+
+				if event.Err == nil {
+					assert.Equal(t, step+1, event.Step)
+				}
+			}
+
+			assert.Equal(t, tc.expectedStages, stages)
+
+			if tc.runErr != nil {
+				assert.Equal(t, tc.runErr, events[len(events)-1].Err)
+			}
+		})
+	}
+}
+
+func TestTrackWithoutOptions(t *testing.T) {
+	var ran bool
+
+	err := Track(context.Background(), OperationPayment, PaymentStages, func(ctx context.Context) (Stage, error) {
+		t.Error("operations without progress options must not be polled")
+		return "", nil
+	}, func(ctx context.Context) error {
+		ran = true
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, ran)
+}
+
+func TestStart(t *testing.T) {
+	var (
+		stages  []Stage
+		failure = errors.New("parity check failed")
+		ctx     = WithOptions(context.Background(), &Options{Report: func(event *Event) {
+			stages = append(stages, event.Stage)
+
+			if event.Err != nil {
+				assert.Equal(t, failure, event.Err)
+				assert.Equal(t, 2, event.Step)
+			}
+		}})
+	)
+
+	reporter := Start(ctx, OperationMigration, MigrationStages)
+	reporter.Reach(Drained)
+	reporter.Reach(Drained)
+	reporter.Fail(failure)
+
+	assert.Equal(t, []Stage{Draining, Drained, Drained}, stages)
+
+	// without options nothing is reported
+	reporter = Start(context.Background(), OperationMigration, MigrationStages)
+	reporter.Reach(Switched)
+	reporter.Fail(failure)
+}
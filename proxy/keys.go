@@ -0,0 +1,115 @@
+package proxy
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/policy"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultSpendWindow is the window of the spend limit of a key without one.
+const DefaultSpendWindow = 24 * time.Hour
+
+// Key is an API key of the proxy, along with the policies its requests are
+// authorized with.
+type Key struct {
+	Name   string
+	Engine *policy.Engine
+}
+
+// KeyConfig configures the policies of an API key, e.g.
+//
+//	{
+//	  "name": "billing",
+//	  "allowed_tokens": ["0x6B175474E89094C44Da98b954EedeAC495271d0F"],
+//	  "spend_limit": 1000000000000000000000,
+//	  "spend_window": "24h"
+//	}
+type KeyConfig struct {
+	Name            string           `json:"name"`
+	ReadOnly        bool             `json:"read_only"`
+	AllowedTokens   []common.Address `json:"allowed_tokens"`
+	AllowedPartners []common.Address `json:"allowed_partners"`
+	DeniedPartners  []common.Address `json:"denied_partners"`
+	// SpendLimit caps the amount of every token paid or deposited with the key per
+	// SpendWindow, a nil limit spending without limits.
+	SpendLimit  *big.Int `json:"spend_limit"`
+	SpendWindow string   `json:"spend_window"`
+}
+
+// Key returns the key of the configuration with its policies.
+func (keyConfig *KeyConfig) Key() (*Key, error) {
+	var policies = make([]policy.Policy, 0)
+
+	if keyConfig.ReadOnly {
+		policies = append(policies, policy.ReadOnly())
+	}
+
+	if len(keyConfig.AllowedTokens) > 0 {
+		policies = append(policies, policy.AllowTokens(keyConfig.AllowedTokens...))
+	}
+
+	if len(keyConfig.AllowedPartners) > 0 {
+		policies = append(policies, policy.AllowPartners(keyConfig.AllowedPartners...))
+	}
+
+	if len(keyConfig.DeniedPartners) > 0 {
+		policies = append(policies, policy.DenyPartners(keyConfig.DeniedPartners...))
+	}
+
+	if keyConfig.SpendLimit != nil {
+		var window = DefaultSpendWindow
+
+		if keyConfig.SpendWindow != "" {
+			var err error
+
+			if window, err = time.ParseDuration(keyConfig.SpendWindow); err != nil {
+				return nil, fmt.Errorf("invalid spend window of key %s: %s", keyConfig.Name, err.Error())
+			}
+		}
+
+		policies = append(policies, policy.SpendLimit(keyConfig.SpendLimit, window))
+	}
+
+	return &Key{Name: keyConfig.Name, Engine: policy.NewEngine(policies...)}, nil
+}
+
+// LoadKeys will read the API keys of the proxy from the JSON file at path, mapping
+// every key to its configuration, e.g.
+//
+//	{
+//	  "3b1f0c...": {"name": "dashboard", "read_only": true},
+//	  "9a77e2...": {"name": "billing", "spend_limit": 1000000000000000000000}
+//	}
+func LoadKeys(path string) (map[string]*Key, error) {
+	var (
+		err        error
+		contents   []byte
+		keyConfigs = make(map[string]*KeyConfig)
+		keys       = make(map[string]*Key)
+	)
+
+	if contents, err = ioutil.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(contents, &keyConfigs); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", path, err.Error())
+	}
+
+	for apiKey, keyConfig := range keyConfigs {
+		if keyConfig == nil {
+			keyConfig = &KeyConfig{}
+		}
+
+		if keys[apiKey], err = keyConfig.Key(); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
@@ -0,0 +1,63 @@
+package proxy
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/policy"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadKeys(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raiden-proxy")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	var (
+		path     = filepath.Join(dir, "keys.json")
+		invalid  = filepath.Join(dir, "invalid.json")
+		token    = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		other    = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		payToken = func(key *Key, tokenAddress common.Address, amount int64) error {
+			_, err := key.Engine.Authorize(&policy.Request{Method: "POST", Resource: "payments", TokenAddress: tokenAddress, Amount: big.NewInt(amount)})
+			return err
+		}
+	)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte(`{
+		"dashboard-key": {"name": "dashboard", "read_only": true},
+		"billing-key": {"name": "billing", "allowed_tokens": ["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"], "spend_limit": 1000000000000000000, "spend_window": "1h"},
+		"admin-key": null,
+		"payout-key": {"name": "payout", "denied_partners": ["0x2a65Aca4D5fC5B5C859090a6c34d164135398226"]}
+	}`), 0600))
+	require.NoError(t, ioutil.WriteFile(invalid, []byte(`{"key": {"spend_limit": 1, "spend_window": "daily"}}`), 0600))
+
+	keys, err := LoadKeys(path)
+	require.NoError(t, err)
+	require.Len(t, keys, 4)
+
+	assert.Equal(t, "dashboard", keys["dashboard-key"].Name)
+	assert.Error(t, payToken(keys["dashboard-key"], token, 1))
+
+	assert.NoError(t, payToken(keys["billing-key"], token, 1000))
+	assert.Error(t, payToken(keys["billing-key"], other, 1))
+	assert.Error(t, payToken(keys["billing-key"], token, 1000000000000000000))
+
+	assert.NoError(t, payToken(keys["admin-key"], other, 1000000000000000000))
+
+	_, err = keys["payout-key"].Engine.Authorize(&policy.Request{Method: "POST", Resource: "payments", TokenAddress: token, PartnerAddress: other, Amount: big.NewInt(1)})
+	assert.Error(t, err)
+	_, err = keys["payout-key"].Engine.Authorize(&policy.Request{Method: "POST", Resource: "payments", TokenAddress: token, PartnerAddress: token, Amount: big.NewInt(1)})
+	assert.NoError(t, err)
+
+	_, err = LoadKeys(invalid)
+	assert.EqualError(t, err, `invalid spend window of key : time: invalid duration "daily"`)
+
+	_, err = LoadKeys(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+// Package proxy serves the Raiden node API to several users of a single node, each
+// authenticated by an API key whose requests are authorized by policies, e.g.
+// restricting a dashboard to reads or capping what a billing service pays per day.
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/cpurta/go-raiden-client/policy"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+// MaxBodySize is the largest request body the proxy accepts.
+const MaxBodySize = 1 << 20
+
+// Proxy is an http.Handler forwarding the requests of API keys, authorized by their
+// policies, to the Raiden node of its configuration. Keys are sent as bearer tokens
+// or in the X-API-Key header, and are not forwarded to the node. The metadata fields
+// of the requests, see meta.FieldsFromHeader, are forwarded with the name of their
+// key as the actor.
+type Proxy struct {
+	baseClient    *util.BaseClient
+	channelLister channels.Lister
+	keys          map[string]*Key
+}
+
+var _ http.Handler = &Proxy{}
+
+// New creates a proxy to the Raiden node of the configuration for the API keys, see
+// LoadKeys.
+func New(config *config.Config, httpClient *http.Client, keys map[string]*Key) *Proxy {
+	return &Proxy{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+		channelLister: channels.NewLister(config, httpClient),
+		keys:          keys,
+	}
+}
+
+func (proxy *Proxy) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	var (
+		err           error
+		body          []byte
+		policyRequest *policy.Request
+		commit        policy.Commit
+		name          = apiKey(request)
+		key, ok       = proxy.keys[name]
+	)
+
+	if name == "" || !ok {
+		writeError(writer, http.StatusUnauthorized, "missing or unknown API key")
+		return
+	}
+
+	if body, err = ioutil.ReadAll(http.MaxBytesReader(writer, request.Body, MaxBodySize)); err != nil {
+		writeError(writer, http.StatusRequestEntityTooLarge, err.Error())
+		return
+	}
+
+	if policyRequest, err = proxy.policyRequest(request, body); err != nil {
+		writeError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if commit, err = key.Engine.Authorize(policyRequest); err != nil {
+		writeError(writer, http.StatusForbidden, err.Error())
+		return
+	}
+
+	commit(proxy.forward(writer, request.WithContext(proxy.withFields(request, key)), body))
+}
+
+// withFields returns the context of the request with the fields of its headers, the
+// actor being the name of its API key, for the node and the audit logs to tell which
+// user made the request.
+func (proxy *Proxy) withFields(request *http.Request, key *Key) context.Context {
+	var fields = meta.FieldsFromHeader(request.Header, proxy.baseClient.Config.MetadataHeaders)
+
+	fields[meta.FieldActor] = key.Name
+
+	return meta.WithFields(request.Context(), fields)
+}
+
+// forward sends the request to the node and copies its response, returning whether
+// the node may have made the operation of the request: unless it refused it with a
+// 4xx or the request provably never reached it, a 5xx or a timeout leaving the outcome
+// unknown, so that e.g. a spend limit keeps counting a payment retried through them.
+func (proxy *Proxy) forward(writer http.ResponseWriter, request *http.Request, body []byte) bool {
+	var (
+		err      error
+		endpoint *url.URL
+		forward  *http.Request
+		response *http.Response
+	)
+
+	if endpoint, err = url.Parse(proxy.baseClient.Config.BaseURL() + request.URL.RequestURI()); err != nil {
+		writeError(writer, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	if forward, err = http.NewRequest(request.Method, endpoint.String(), bytes.NewReader(body)); err != nil {
+		writeError(writer, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	forward = forward.WithContext(request.Context())
+
+	if contentType := request.Header.Get("Content-Type"); contentType != "" {
+		forward.Header.Set("Content-Type", contentType)
+	}
+
+	if response, err = proxy.baseClient.Do(forward); err != nil {
+		writeError(writer, http.StatusBadGateway, err.Error())
+		return !raidenerrors.IsUnsent(err)
+	}
+
+	defer response.Body.Close()
+
+	for name, values := range response.Header {
+		writer.Header()[name] = values
+	}
+
+	writer.WriteHeader(response.StatusCode)
+	io.Copy(writer, response.Body)
+
+	return response.StatusCode < http.StatusBadRequest || response.StatusCode >= http.StatusInternalServerError
+}
+
+// policyRequest describes the operation of a request to the Raiden node API, see
+// policy.Describe, a deposit spending what it adds to the current deposit of the
+// channel.
+func (proxy *Proxy) policyRequest(request *http.Request, body []byte) (*policy.Request, error) {
+	var result, err = policy.Describe(request.Method, request.URL.Path, body)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if result.Resource == "channels" && request.Method != "PUT" && result.Amount != nil {
+		if result.Amount, err = proxy.depositIncrease(request, result, result.Amount); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// depositIncrease returns the amount the new total deposit of a channel adds to its
+// current one.
+func (proxy *Proxy) depositIncrease(request *http.Request, result *policy.Request, totalDeposit *big.Int) (*big.Int, error) {
+	var (
+		err         error
+		amount      = new(big.Int).Set(totalDeposit)
+		channelList []*channels.Channel
+	)
+
+	if channelList, err = proxy.channelLister.ListToken(request.Context(), result.TokenAddress); err != nil {
+		return nil, fmt.Errorf("unable to get the current deposit of the channel: %s", err.Error())
+	}
+
+	for _, channel := range channelList {
+		if channel.PartnerAddress == result.PartnerAddress && channel.TotalDeposit != nil {
+			return amount.Sub(amount, channel.TotalDeposit), nil
+		}
+	}
+
+	return amount, nil
+}
+
+// apiKey returns the API key of the request, sent as a bearer token or in the
+// X-API-Key header.
+func apiKey(request *http.Request) string {
+	if key := request.Header.Get("X-API-Key"); key != "" {
+		return key
+	}
+
+	return strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ")
+}
+
+// writeError writes an error response the way the Raiden node does.
+func writeError(writer http.ResponseWriter, statusCode int, message string) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	json.NewEncoder(writer).Encode(map[string]string{"errors": message})
+}
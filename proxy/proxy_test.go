@@ -0,0 +1,258 @@
+package proxy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/policy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNew() {
+	keys, err := LoadKeys("keys.json")
+	if err != nil {
+		panic(fmt.Sprintf("unable to load API keys: %s", err.Error()))
+	}
+
+	proxy := New(&config.Config{Host: "http://localhost:5001", APIVersion: "v1"}, http.DefaultClient, keys)
+
+	http.ListenAndServe(":5002", proxy)
+}
+
+func TestProxy(t *testing.T) {
+	var (
+		forwarded []string
+		node      = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			forwarded = append(forwarded, fmt.Sprintf("%s %s %s %s", request.Method, request.URL.RequestURI(), request.Header.Get("Authorization"), body))
+
+			switch {
+			case request.Method == "GET" && request.URL.Path == "/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8":
+				fmt.Fprint(writer, `[{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":30,"total_deposit":30,"state":"opened"}]`)
+			case request.Method == "POST" && strings.Contains(string(body), `"amount":9`):
+				writer.WriteHeader(http.StatusConflict)
+				fmt.Fprint(writer, `{"errors":"Payment couldn't be completed because: there is no route available"}`)
+			default:
+				fmt.Fprint(writer, `{}`)
+			}
+		}))
+		keys = map[string]*Key{
+			"dashboard": &Key{Name: "dashboard", Engine: policy.NewEngine(policy.ReadOnly())},
+			"billing":   &Key{Name: "billing", Engine: policy.NewEngine(policy.SpendLimit(big.NewInt(50), time.Hour))},
+		}
+		proxy = httptest.NewServer(New(&config.Config{Host: node.URL, APIVersion: "v1"}, http.DefaultClient, keys))
+	)
+	defer node.Close()
+	defer proxy.Close()
+
+	type testcase struct {
+		name              string
+		method            string
+		path              string
+		header            http.Header
+		body              string
+		expectedStatus    int
+		expectedBody      string
+		expectedForwarded []string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "missing key",
+			method:         "GET",
+			path:           "/api/v1/channels",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"errors":"missing or unknown API key"}`,
+		},
+		testcase{
+			name:           "unknown key",
+			method:         "GET",
+			path:           "/api/v1/channels",
+			header:         http.Header{"Authorization": []string{"Bearer nope"}},
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"errors":"missing or unknown API key"}`,
+		},
+		testcase{
+			name:              "read with a read-only key",
+			method:            "GET",
+			path:              "/api/v1/channels?state=opened",
+			header:            http.Header{"Authorization": []string{"Bearer dashboard"}},
+			expectedStatus:    http.StatusOK,
+			expectedBody:      `{}`,
+			expectedForwarded: []string{"GET /api/v1/channels?state=opened  "},
+		},
+		testcase{
+			name:           "payment with a read-only key",
+			method:         "POST",
+			path:           "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			header:         http.Header{"X-Api-Key": []string{"dashboard"}},
+			body:           `{"amount":10}`,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"errors":"denied by read-only policy: POST payments is not a read"}`,
+		},
+		testcase{
+			name:              "payment within the spend limit",
+			method:            "POST",
+			path:              "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			header:            http.Header{"X-Api-Key": []string{"billing"}},
+			body:              `{"amount":40}`,
+			expectedStatus:    http.StatusOK,
+			expectedBody:      `{}`,
+			expectedForwarded: []string{`POST /api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9  {"amount":40}`},
+		},
+		testcase{
+			name:           "payment over the spend limit",
+			method:         "POST",
+			path:           "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			header:         http.Header{"X-Api-Key": []string{"billing"}},
+			body:           `{"amount":"11"}`,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"errors":"denied by spend limit policy: spending 11 of token 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8 exceeds the limit of 50 per 1h0m0s, 40 being already spent"}`,
+		},
+		testcase{
+			name:           "payment hiding its amount in another case",
+			method:         "POST",
+			path:           "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			header:         http.Header{"X-Api-Key": []string{"billing"}},
+			body:           `{"amount":1000,"AMOUNT":1}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"invalid request payload: duplicate field \"AMOUNT\""}`,
+		},
+		testcase{
+			name:              "failed payment is not counted",
+			method:            "POST",
+			path:              "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			header:            http.Header{"X-Api-Key": []string{"billing"}},
+			body:              `{"amount":9}`,
+			expectedStatus:    http.StatusConflict,
+			expectedBody:      `{"errors":"Payment couldn't be completed because: there is no route available"}`,
+			expectedForwarded: []string{`POST /api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9  {"amount":9}`},
+		},
+		testcase{
+			name:           "deposit increase counted against the spend limit",
+			method:         "PATCH",
+			path:           "/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			header:         http.Header{"X-Api-Key": []string{"billing"}},
+			body:           `{"total_deposit":41}`,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"errors":"denied by spend limit policy: spending 11 of token 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8 exceeds the limit of 50 per 1h0m0s, 40 being already spent"}`,
+			expectedForwarded: []string{
+				"GET /api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8  ",
+			},
+		},
+		testcase{
+			name:           "not an endpoint of the node",
+			method:         "GET",
+			path:           "/metrics",
+			header:         http.Header{"X-Api-Key": []string{"dashboard"}},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"errors":"not an endpoint of the Raiden node API: /metrics"}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			forwarded = nil
+
+			request, err := http.NewRequest(tc.method, proxy.URL+tc.path, strings.NewReader(tc.body))
+			require.NoError(t, err)
+
+			for name, values := range tc.header {
+				request.Header[name] = values
+			}
+
+			response, err := http.DefaultClient.Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			body, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedStatus, response.StatusCode)
+			assert.JSONEq(t, tc.expectedBody, string(body))
+			assert.Equal(t, tc.expectedForwarded, forwarded)
+		})
+	}
+}
+
+func TestProxyFields(t *testing.T) {
+	var (
+		received http.Header
+		node     = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			received = request.Header
+			fmt.Fprint(writer, `[]`)
+		}))
+		keys  = map[string]*Key{"dashboard": &Key{Name: "dashboard", Engine: policy.NewEngine(policy.ReadOnly())}}
+		proxy = httptest.NewServer(New(&config.Config{Host: node.URL, APIVersion: "v1"}, http.DefaultClient, keys))
+	)
+	defer node.Close()
+	defer proxy.Close()
+
+	request, err := http.NewRequest("GET", proxy.URL+"/api/v1/channels", nil)
+	require.NoError(t, err)
+	request.Header.Set("X-API-Key", "dashboard")
+	request.Header.Set("X-Actor", "spoofed")
+	request.Header.Set("X-Correlation-ID", "42")
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "dashboard", received.Get("X-Actor"))
+	assert.Equal(t, "42", received.Get("X-Correlation-ID"))
+	assert.Empty(t, received.Get("X-API-Key"))
+}
+
+func TestProxySpendLimitOutcome(t *testing.T) {
+	var (
+		statusCode = http.StatusInternalServerError
+		node       = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(statusCode)
+			fmt.Fprint(writer, `{}`)
+		}))
+		unreachable = httptest.NewServer(http.NotFoundHandler())
+		pay         = func(host string) int {
+			var (
+				keys  = map[string]*Key{"billing": &Key{Name: "billing", Engine: policy.NewEngine(policy.SpendLimit(big.NewInt(50), time.Hour))}}
+				proxy = httptest.NewServer(New(&config.Config{Host: host, APIVersion: "v1"}, http.DefaultClient, keys))
+			)
+			defer proxy.Close()
+
+			codes := make([]int, 0, 2)
+
+			for i := 0; i < 2; i++ {
+				request, err := http.NewRequest("POST", proxy.URL+"/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", strings.NewReader(`{"amount":30}`))
+				require.NoError(t, err)
+				request.Header.Set("X-API-Key", "billing")
+
+				response, err := http.DefaultClient.Do(request)
+				require.NoError(t, err)
+				response.Body.Close()
+
+				codes = append(codes, response.StatusCode)
+			}
+
+			return codes[1]
+		}
+	)
+	defer node.Close()
+
+	// the node is gone, the connection to it is refused
+	unreachable.Close()
+
+	assert.Equal(t, http.StatusForbidden, pay(node.URL), "a payment failing with a 5xx may have been made")
+
+	statusCode = http.StatusConflict
+	assert.Equal(t, http.StatusConflict, pay(node.URL), "a payment refused with a 4xx was not made")
+
+	assert.Equal(t, http.StatusBadGateway, pay(unreachable.URL), "a payment that never reached the node was not made")
+}
@@ -0,0 +1,381 @@
+// Package pushgateway pushes the channel lifecycle and payment events of a Raiden
+// node as metrics to a Prometheus pushgateway, for short-lived batch jobs such as
+// payout runs that end before they could be scraped.
+package pushgateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/liquidity"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Names of the metrics pushed, in the text exposition format of Prometheus.
+const (
+	// MetricChannelEvents counts the channel state changes observed, by token and
+	// state, e.g. the channels opened and closed by a run.
+	MetricChannelEvents = "raiden_channel_events_total"
+	// MetricChannelBalance is the last observed balance of every channel, by token and
+	// partner.
+	MetricChannelBalance = "raiden_channel_balance"
+	// MetricChannelDeposit is the last observed total deposit of every channel, by
+	// token and partner.
+	MetricChannelDeposit = "raiden_channel_total_deposit"
+	// MetricPaymentEvents counts the payment events observed, by token and event name.
+	MetricPaymentEvents = "raiden_payment_events_total"
+	// MetricPaymentAmount sums the amounts of the payment events observed, by token and
+	// event name, in the smallest unit of the token.
+	MetricPaymentAmount = "raiden_payment_amount_total"
+	// MetricStorageEntries is the number of entries kept by the client in its storage,
+	// by prefix, see storage.Measure.
+	MetricStorageEntries = "raiden_storage_entries"
+	// MetricStorageBytes is the size of the entries kept by the client in its storage,
+	// keys and values, by prefix.
+	MetricStorageBytes = "raiden_storage_bytes"
+	// MetricLiquidityOutbound is the last observed liquidity the node can pay in every
+	// token, by token, see liquidity.Stats.
+	MetricLiquidityOutbound = "raiden_liquidity_outbound"
+	// MetricLiquidityInbound is the last observed liquidity the partners of the node
+	// can pay it at least in every token, by token.
+	MetricLiquidityInbound = "raiden_liquidity_inbound"
+	// MetricLiquidityLocked is the last observed amount locked by the pending
+	// transfers of every token, by token.
+	MetricLiquidityLocked = "raiden_liquidity_locked"
+)
+
+// ErrNoJob is returned when pushing without a job, which the pushgateway groups
+// metrics by.
+var ErrNoJob = errors.New("pushgateway job must not be empty")
+
+// Pusher collects metrics from the observed channels and payment events and pushes
+// them to the pushgateway at URL, grouped under Job and the labels of Grouping, e.g.
+// the instance of the run. Every series is labelled with the Tenant when it is set,
+// e.g. the internal customer the run pays out for. A nil HTTPClient is
+// http.DefaultClient, and the pushes of Run are timed by Clock, the system time when
+// nil.
+type Pusher struct {
+	URL        string
+	Job        string
+	Grouping   map[string]string
+	Tenant     string
+	HTTPClient *http.Client
+	Clock      clock.Clock
+
+	mutex    sync.Mutex
+	states   map[channelKey]string
+	counters map[series]float64
+	gauges   map[series]float64
+}
+
+type channelKey struct {
+	tokenAddress   common.Address
+	partnerAddress common.Address
+	identifier     int64
+}
+
+// series is a metric with its labels, formatted as in the exposition format.
+type series struct {
+	name   string
+	labels string
+}
+
+// NewPusher creates a pusher to the pushgateway at the URL, pushing the metrics of
+// the job.
+func NewPusher(url, job string, httpClient *http.Client) *Pusher {
+	return &Pusher{
+		URL:        url,
+		Job:        job,
+		HTTPClient: httpClient,
+	}
+}
+
+// ObserveChannel records the state of the channel: a state it was not observed in
+// before counts as a lifecycle event, and its balance and deposit replace the
+// previous ones.
+func (pusher *Pusher) ObserveChannel(channel *channels.Channel) {
+	var (
+		key = channelKey{
+			tokenAddress:   channel.TokenAddress,
+			partnerAddress: channel.PartnerAddress,
+			identifier:     channel.ChannelIdentifier,
+		}
+		balanceLabels = pusher.labels("token_address", channel.TokenAddress.Hex(), "partner_address", channel.PartnerAddress.Hex())
+	)
+
+	pusher.mutex.Lock()
+	defer pusher.mutex.Unlock()
+
+	pusher.init()
+
+	if state, ok := pusher.states[key]; !ok || state != channel.State {
+		pusher.states[key] = channel.State
+		pusher.counters[series{MetricChannelEvents, pusher.labels("token_address", channel.TokenAddress.Hex(), "state", channel.State)}]++
+	}
+
+	pusher.gauges[series{MetricChannelBalance, balanceLabels}] = float(channel.Balance)
+	pusher.gauges[series{MetricChannelDeposit, balanceLabels}] = float(channel.TotalDeposit)
+}
+
+// ObservePayment records the payment event, counting it and adding its amount.
+func (pusher *Pusher) ObservePayment(event *events.PaymentEvent) {
+	var eventLabels = pusher.labels("token_address", event.TokenAddress.Hex(), "event", event.EventName)
+
+	pusher.mutex.Lock()
+	defer pusher.mutex.Unlock()
+
+	pusher.init()
+
+	pusher.counters[series{MetricPaymentEvents, eventLabels}]++
+	pusher.counters[series{MetricPaymentAmount, eventLabels}] += float(event.Amount)
+}
+
+// ObserveStorage records the size of the entries of the storage under the prefix,
+// e.g. from the results of a storage.Collector, replacing the previous one.
+func (pusher *Pusher) ObserveStorage(prefix string, usage *storage.Usage) {
+	var storageLabels = pusher.labels("prefix", prefix)
+
+	pusher.mutex.Lock()
+	defer pusher.mutex.Unlock()
+
+	pusher.init()
+
+	pusher.gauges[series{MetricStorageEntries, storageLabels}] = float64(usage.Entries)
+	pusher.gauges[series{MetricStorageBytes, storageLabels}] = float64(usage.Bytes)
+}
+
+// ObserveLiquidity records the liquidity of the node in the tokens of the stats, e.g.
+// from a liquidity.Getter, replacing the previous one of those tokens.
+func (pusher *Pusher) ObserveLiquidity(stats []*liquidity.Stats) {
+	pusher.mutex.Lock()
+	defer pusher.mutex.Unlock()
+
+	pusher.init()
+
+	for _, tokenStats := range stats {
+		var tokenLabels = pusher.labels("token_address", tokenStats.TokenAddress.Hex())
+
+		pusher.gauges[series{MetricLiquidityOutbound, tokenLabels}] = float(tokenStats.Outbound)
+		pusher.gauges[series{MetricLiquidityInbound, tokenLabels}] = float(tokenStats.Inbound)
+		pusher.gauges[series{MetricLiquidityLocked, tokenLabels}] = float(tokenStats.Locked)
+	}
+}
+
+// Push will replace the metrics of the group of the pusher on the pushgateway with
+// the metrics collected so far, any status code other than 2xx being an error.
+func (pusher *Pusher) Push(ctx context.Context) error {
+	var (
+		err        error
+		groupURL   string
+		request    *http.Request
+		response   *http.Response
+		httpClient = pusher.HTTPClient
+	)
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if groupURL, err = pusher.groupURL(); err != nil {
+		return err
+	}
+
+	if request, err = http.NewRequest("PUT", groupURL, bytes.NewReader(pusher.Format())); err != nil {
+		return err
+	}
+
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "text/plain; version=0.0.4")
+
+	if response, err = httpClient.Do(request); err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return raidenerrors.New(response.StatusCode, fmt.Errorf("received %d status code from pushgateway %s", response.StatusCode, pusher.URL))
+	}
+
+	return nil
+}
+
+// Run will observe the events of the subscription and push the metrics every
+// interval, and once more when the subscription ends so that the last events of the
+// run are not lost. The errors of the pushes are returned on the returned channel,
+// which is closed once the last push is done. Errors are dropped when nobody is
+// receiving them.
+func (pusher *Pusher) Run(ctx context.Context, subscription *events.Subscription, interval time.Duration) <-chan error {
+	var errs = make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		var (
+			pace = clock.Or(pusher.Clock)
+			tick = pace.After(interval)
+		)
+
+		push := func(ctx context.Context) {
+			if err := pusher.Push(ctx); err != nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+		}
+
+		for {
+			select {
+			case event, ok := <-subscription.Events:
+				if !ok {
+					// the subscription ends along with its context, which the last push
+					// must not be cancelled by
+					push(context.Background())
+					return
+				}
+
+				pusher.ObservePayment(event)
+			case <-tick:
+				tick = pace.After(interval)
+				push(ctx)
+			}
+		}
+	}()
+
+	return errs
+}
+
+// Format returns the metrics collected so far in the text exposition format of
+// Prometheus, sorted by name and labels.
+func (pusher *Pusher) Format() []byte {
+	var buffer bytes.Buffer
+
+	pusher.mutex.Lock()
+	defer pusher.mutex.Unlock()
+
+	write := func(kind string, values map[series]float64) {
+		var (
+			all   = make([]series, 0, len(values))
+			typed = make(map[string]bool)
+		)
+
+		for series := range values {
+			all = append(all, series)
+		}
+
+		sort.Slice(all, func(i, j int) bool {
+			if all[i].name != all[j].name {
+				return all[i].name < all[j].name
+			}
+
+			return all[i].labels < all[j].labels
+		})
+
+		for _, series := range all {
+			if !typed[series.name] {
+				fmt.Fprintf(&buffer, "# TYPE %s %s\n", series.name, kind)
+				typed[series.name] = true
+			}
+
+			fmt.Fprintf(&buffer, "%s{%s} %g\n", series.name, series.labels, values[series])
+		}
+	}
+
+	write("counter", pusher.counters)
+	write("gauge", pusher.gauges)
+
+	return buffer.Bytes()
+}
+
+func (pusher *Pusher) init() {
+	if pusher.states == nil {
+		pusher.states = make(map[channelKey]string)
+		pusher.counters = make(map[series]float64)
+		pusher.gauges = make(map[series]float64)
+	}
+}
+
+// groupURL returns the URL of the group of the pusher, the job and grouping labels
+// being path segments of the pushgateway API, base64 encoded when they can't be.
+func (pusher *Pusher) groupURL() (string, error) {
+	var (
+		path = "/metrics/" + segment("job", pusher.Job)
+		keys = make([]string, 0, len(pusher.Grouping))
+	)
+
+	if pusher.Job == "" {
+		return "", ErrNoJob
+	}
+
+	for key := range pusher.Grouping {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		path += "/" + segment(key, pusher.Grouping[key])
+	}
+
+	return strings.TrimSuffix(pusher.URL, "/") + path, nil
+}
+
+func segment(name, value string) string {
+	switch {
+	case value == "":
+		// an empty value is a single padding character, to tell it from a missing one
+		return name + "@base64/="
+	case strings.Contains(value, "/"):
+		return name + "@base64/" + base64.RawURLEncoding.EncodeToString([]byte(value))
+	}
+
+	return name + "/" + url.PathEscape(value)
+}
+
+// labels formats the labels of a series of the pusher, the tenant first when it has
+// one, see labels.
+func (pusher *Pusher) labels(namesAndValues ...string) string {
+	if pusher.Tenant != "" {
+		namesAndValues = append([]string{"tenant", pusher.Tenant}, namesAndValues...)
+	}
+
+	return labels(namesAndValues...)
+}
+
+// labels formats the label names and values as in the exposition format, escaping
+// the values.
+func labels(namesAndValues ...string) string {
+	var pairs = make([]string, 0, len(namesAndValues)/2)
+
+	for i := 0; i+1 < len(namesAndValues); i += 2 {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(namesAndValues[i+1])
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, namesAndValues[i], value))
+	}
+
+	return strings.Join(pairs, ",")
+}
+
+// float returns the amount as a sample value, which loses the precision of amounts
+// beyond 2^53 like every Prometheus sample does.
+func float(amount *big.Int) float64 {
+	value, _ := new(big.Float).SetInt(amounts.OrZero(amount)).Float64()
+
+	return value
+}
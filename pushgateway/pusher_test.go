@@ -0,0 +1,233 @@
+package pushgateway
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/liquidity"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+)
+
+func ExamplePusher() {
+	var pusher = NewPusher("http://localhost:9091", "payout", http.DefaultClient)
+
+	pusher.Grouping = map[string]string{"instance": "payout-2019-03-07"}
+
+	// observe the channels opened and the payments sent by the run, then push its
+	// metrics before it exits
+	pusher.ObserveChannel(&channels.Channel{TokenAddress: tokenAddress, PartnerAddress: partnerAddress, State: "opened"})
+
+	if err := pusher.Push(context.Background()); err != nil {
+		panic(err)
+	}
+}
+
+func TestPusherFormat(t *testing.T) {
+	var (
+		pusher  = NewPusher("http://localhost:9091", "payout", nil)
+		channel = &channels.Channel{
+			ChannelIdentifier: 20,
+			TokenAddress:      tokenAddress,
+			PartnerAddress:    partnerAddress,
+			Balance:           big.NewInt(25),
+			TotalDeposit:      big.NewInt(35),
+			State:             "opened",
+		}
+		sent = &events.PaymentEvent{
+			TokenAddress:   tokenAddress,
+			PartnerAddress: partnerAddress,
+			Event:          &payments.Event{EventName: payments.EventPaymentSentSuccess, Amount: big.NewInt(10)},
+		}
+	)
+
+	pusher.ObserveChannel(channel)
+	pusher.ObserveChannel(channel)
+	pusher.ObservePayment(sent)
+	pusher.ObservePayment(sent)
+
+	channel.Balance = big.NewInt(5)
+	channel.State = "closed"
+	pusher.ObserveChannel(channel)
+
+	assert.Equal(t, `# TYPE raiden_channel_events_total counter
+raiden_channel_events_total{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",state="closed"} 1
+raiden_channel_events_total{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",state="opened"} 1
+# TYPE raiden_payment_amount_total counter
+raiden_payment_amount_total{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",event="EventPaymentSentSuccess"} 20
+# TYPE raiden_payment_events_total counter
+raiden_payment_events_total{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",event="EventPaymentSentSuccess"} 2
+# TYPE raiden_channel_balance gauge
+raiden_channel_balance{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",partner_address="0x61C808D82A3Ac53231750daDc13c777b59310bD9"} 5
+# TYPE raiden_channel_total_deposit gauge
+raiden_channel_total_deposit{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",partner_address="0x61C808D82A3Ac53231750daDc13c777b59310bD9"} 35
+`, string(pusher.Format()))
+}
+
+func TestPusherTenant(t *testing.T) {
+	var pusher = NewPusher("http://localhost:9091", "payout", nil)
+
+	pusher.Tenant = "acme"
+	pusher.ObservePayment(&events.PaymentEvent{
+		TokenAddress: tokenAddress,
+		Event:        &payments.Event{EventName: payments.EventPaymentSentSuccess, Amount: big.NewInt(10)},
+	})
+
+	assert.Equal(t, `# TYPE raiden_payment_amount_total counter
+raiden_payment_amount_total{tenant="acme",token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",event="EventPaymentSentSuccess"} 10
+# TYPE raiden_payment_events_total counter
+raiden_payment_events_total{tenant="acme",token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",event="EventPaymentSentSuccess"} 1
+`, string(pusher.Format()))
+}
+
+func TestPusherPush(t *testing.T) {
+	type testcase struct {
+		name          string
+		job           string
+		grouping      map[string]string
+		statusCode    int
+		expectedPath  string
+		expectedError error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "job",
+			job:          "payout",
+			statusCode:   http.StatusOK,
+			expectedPath: "/metrics/job/payout",
+		},
+		testcase{
+			name:         "grouping labels",
+			job:          "payout",
+			grouping:     map[string]string{"instance": "run 1", "path": "/var/run", "empty": ""},
+			statusCode:   http.StatusAccepted,
+			expectedPath: "/metrics/job/payout/empty@base64/=/instance/run 1/path@base64/L3Zhci9ydW4",
+		},
+		testcase{
+			name:          "rejected push",
+			job:           "payout",
+			statusCode:    http.StatusBadRequest,
+			expectedPath:  "/metrics/job/payout",
+			expectedError: raidenerrors.New(http.StatusBadRequest, nil),
+		},
+		testcase{
+			name:          "no job",
+			expectedError: ErrNoJob,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				path    string
+				body    []byte
+				gateway = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+					assert.Equal(t, "PUT", request.Method)
+					path = request.URL.Path
+					body, _ = ioutil.ReadAll(request.Body)
+					writer.WriteHeader(tc.statusCode)
+				}))
+				pusher = NewPusher(gateway.URL+"/", tc.job, nil)
+			)
+			defer gateway.Close()
+
+			pusher.Grouping = tc.grouping
+			pusher.ObserveChannel(&channels.Channel{TokenAddress: tokenAddress, PartnerAddress: partnerAddress, State: "opened"})
+
+			err := pusher.Push(context.Background())
+
+			switch expected := tc.expectedError.(type) {
+			case nil:
+				require.NoError(t, err)
+				assert.Equal(t, pusher.Format(), body)
+			case *raidenerrors.Error:
+				assert.Equal(t, expected.StatusCode, raidenerrors.StatusCode(err))
+			default:
+				assert.Equal(t, expected, err)
+			}
+
+			assert.Equal(t, tc.expectedPath, path)
+		})
+	}
+}
+
+func TestPusherRun(t *testing.T) {
+	var (
+		pushes  = make(chan []byte, 10)
+		gateway = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			pushes <- body
+		}))
+		pusher       = NewPusher(gateway.URL, "payout", nil)
+		eventStream  = make(chan *events.PaymentEvent)
+		subscription = &events.Subscription{Events: eventStream, Errors: make(chan error)}
+		ctx, cancel  = context.WithCancel(context.Background())
+	)
+	defer gateway.Close()
+	defer cancel()
+
+	errs := pusher.Run(ctx, subscription, time.Hour)
+
+	eventStream <- &events.PaymentEvent{
+		TokenAddress: tokenAddress,
+		Event:        &payments.Event{EventName: payments.EventPaymentReceivedSuccess, Amount: big.NewInt(3)},
+	}
+
+	// the subscription ends when its context is cancelled
+	cancel()
+	close(eventStream)
+
+	_, open := <-errs
+	assert.False(t, open)
+
+	require.Len(t, pushes, 1)
+	assert.Contains(t, string(<-pushes), `raiden_payment_amount_total{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",event="EventPaymentReceivedSuccess"} 3`)
+}
+
+func TestPusherObserveStorage(t *testing.T) {
+	var pusher = NewPusher("http://localhost:9091", "payout", nil)
+
+	pusher.ObserveStorage("history/", &storage.Usage{Entries: 3, Bytes: 512})
+	pusher.ObserveStorage("history/", &storage.Usage{Entries: 2, Bytes: 340})
+
+	assert.Equal(t, `# TYPE raiden_storage_bytes gauge
+raiden_storage_bytes{prefix="history/"} 340
+# TYPE raiden_storage_entries gauge
+raiden_storage_entries{prefix="history/"} 2
+`, string(pusher.Format()))
+}
+
+func TestPusherObserveLiquidity(t *testing.T) {
+	var (
+		pusher       = NewPusher("http://localhost:9091", "payout", nil)
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	)
+
+	pusher.ObserveLiquidity([]*liquidity.Stats{&liquidity.Stats{TokenAddress: tokenAddress, Channels: 1, Outbound: big.NewInt(300), Inbound: big.NewInt(50), Locked: big.NewInt(20)}})
+	pusher.ObserveLiquidity([]*liquidity.Stats{&liquidity.Stats{TokenAddress: tokenAddress, Channels: 1, Outbound: big.NewInt(280), Inbound: big.NewInt(70), Locked: big.NewInt(0)}})
+
+	assert.Equal(t, `# TYPE raiden_liquidity_inbound gauge
+raiden_liquidity_inbound{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"} 70
+# TYPE raiden_liquidity_locked gauge
+raiden_liquidity_locked{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"} 0
+# TYPE raiden_liquidity_outbound gauge
+raiden_liquidity_outbound{token_address="0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"} 280
+`, string(pusher.Format()))
+}
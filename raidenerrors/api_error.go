@@ -0,0 +1,81 @@
+package raidenerrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/redact"
+)
+
+// APIError is an error response of the Raiden node, which describes the error in
+// the errors field of its body. RetryAfter is how long the response asked to wait
+// before retrying, if it did.
+type APIError struct {
+	StatusCode int
+	Message    string
+	RetryAfter time.Duration
+}
+
+// Parse returns the error described by the body of a response of the Raiden node, or
+// nil when the body holds no error. The errors field is either a message, a list of
+// messages or, for invalid requests, the list of messages of every invalid field.
+func Parse(statusCode int, body []byte) *APIError {
+	var (
+		response = &struct {
+			Errors json.RawMessage `json:"errors"`
+		}{}
+		message  string
+		messages []string
+		fields   map[string][]string
+	)
+
+	if err := json.Unmarshal(body, response); err != nil || len(response.Errors) == 0 {
+		return nil
+	}
+
+	switch {
+	case json.Unmarshal(response.Errors, &message) == nil:
+	case json.Unmarshal(response.Errors, &messages) == nil:
+		message = strings.Join(messages, "; ")
+	case json.Unmarshal(response.Errors, &fields) == nil:
+		for field, fieldMessages := range fields {
+			messages = append(messages, fmt.Sprintf("%s: %s", field, strings.Join(fieldMessages, ", ")))
+		}
+
+		sort.Strings(messages)
+		message = strings.Join(messages, "; ")
+	}
+
+	if message == "" {
+		return nil
+	}
+
+	return &APIError{StatusCode: statusCode, Message: redact.String(message)}
+}
+
+func (err *APIError) Error() string {
+	return fmt.Sprintf("raiden node error %d: %s", err.StatusCode, err.Message)
+}
+
+// Code returns the code of the known error the message of the node matches.
+func (err *APIError) Code() Code {
+	return Match(err.Message)
+}
+
+// Retryable reports whether the error is one of a node that is still syncing, or the
+// status code one of a node that is overloaded or restarting.
+func (err *APIError) Retryable() bool {
+	if err.Code() == CodeNodeSyncing {
+		return true
+	}
+
+	return retryableStatus(err.StatusCode)
+}
+
+// Temporary is the same as Retryable.
+func (err *APIError) Temporary() bool {
+	return err.Retryable()
+}
@@ -0,0 +1,90 @@
+package raidenerrors
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleHasCode() {
+	var err error = Parse(http.StatusConflict, []byte(`{"errors":"Payment couldn't be completed because: Insufficient balance"}`))
+
+	if HasCode(err, CodeInsufficientBalance) {
+		fmt.Println("not enough capacity, depositing more tokens first")
+	}
+}
+
+func TestParse(t *testing.T) {
+	type testcase struct {
+		name             string
+		body             string
+		expectedAPIError *APIError
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:             "message",
+			body:             `{"errors":"Channel not found"}`,
+			expectedAPIError: &APIError{StatusCode: http.StatusConflict, Message: "Channel not found"},
+		},
+		testcase{
+			name:             "list of messages",
+			body:             `{"errors":["Insufficient balance","No route"]}`,
+			expectedAPIError: &APIError{StatusCode: http.StatusConflict, Message: "Insufficient balance; No route"},
+		},
+		testcase{
+			name:             "invalid fields",
+			body:             `{"errors":{"token_address":["Not a valid EIP55 encoded address"],"amount":["Not a valid integer."]}}`,
+			expectedAPIError: &APIError{StatusCode: http.StatusConflict, Message: "amount: Not a valid integer.; token_address: Not a valid EIP55 encoded address"},
+		},
+		testcase{
+			name:             "secrets are redacted",
+			body:             `{"errors":"Invalid payment: {\"secret\": \"0x01\", \"amount\": 10}"}`,
+			expectedAPIError: &APIError{StatusCode: http.StatusConflict, Message: `Invalid payment: {"secret": "REDACTED", "amount": 10}`},
+		},
+		testcase{
+			name: "empty body",
+			body: ``,
+		},
+		testcase{
+			name: "no errors",
+			body: `{"state":"opened"}`,
+		},
+		testcase{
+			name: "empty errors",
+			body: `{"errors":""}`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiErr := Parse(http.StatusConflict, []byte(tc.body))
+
+			if tc.expectedAPIError == nil {
+				assert.Nil(t, apiErr)
+				return
+			}
+
+			assert.Equal(t, tc.expectedAPIError, apiErr)
+		})
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	var err = &APIError{StatusCode: http.StatusConflict, Message: "Insufficient balance"}
+
+	assert.EqualError(t, err, "raiden node error 409: Insufficient balance")
+	assert.Equal(t, CodeInsufficientBalance, err.Code())
+	assert.False(t, IsRetryable(err))
+	assert.Equal(t, http.StatusConflict, StatusCode(err))
+
+	assert.True(t, IsRetryable(&APIError{StatusCode: http.StatusServiceUnavailable, Message: "Service Unavailable"}))
+	assert.True(t, IsRetryable(&APIError{StatusCode: http.StatusConflict, Message: "The node is still syncing with the blockchain"}))
+
+	assert.True(t, HasCode(err, CodeInsufficientBalance))
+	assert.False(t, HasCode(err, CodeNoRoute))
+	assert.False(t, HasCode(errors.New("Insufficient balance"), CodeInsufficientBalance))
+}
@@ -0,0 +1,142 @@
+package raidenerrors
+
+import "strings"
+
+// Code identifies a known error of the Raiden node, which the node only describes in
+// prose, or of the client. Codes are stable: the value of a code never changes
+// across versions of the client and is never given to another error, so that
+// alerts and retries matching on codes survive the rewording of the messages, which
+// may change in any version. Every typed error of the client has a code, see
+// HasCode.
+type Code string
+
+const (
+	// CodeUnknown is the code of errors that do not match any known error.
+	CodeUnknown Code = ""
+	// CodeInsufficientBalance is a payment larger than the capacity of the channels.
+	CodeInsufficientBalance Code = "insufficient_balance"
+	// CodeInsufficientFunds is a deposit larger than the tokens or ether of the node.
+	CodeInsufficientFunds Code = "insufficient_funds"
+	// CodeNoRoute is a payment for which no route to the target could be found.
+	CodeNoRoute Code = "no_route"
+	// CodeChannelExists is opening a channel with a partner that already has one.
+	CodeChannelExists Code = "channel_exists"
+	// CodeChannelNotFound is a call on a channel that does not exist.
+	CodeChannelNotFound Code = "channel_not_found"
+	// CodeChannelAlreadyClosed is closing a channel that is already closed or
+	// settled.
+	CodeChannelAlreadyClosed Code = "channel_already_closed"
+	// CodeChannelNotOpen is a deposit or a payment on a channel that is closed.
+	CodeChannelNotOpen Code = "channel_not_open"
+	// CodeInvalidAddress is an address that is not a valid EIP-55 address.
+	CodeInvalidAddress Code = "invalid_address"
+	// CodeInvalidAmount is a negative amount or one that could not be parsed.
+	CodeInvalidAmount Code = "invalid_amount"
+	// CodeInvalidSettleTimeout is a settle timeout out of the bounds of the network.
+	CodeInvalidSettleTimeout Code = "invalid_settle_timeout"
+	// CodeDepositLimitExceeded is a deposit above the limit of the token network.
+	CodeDepositLimitExceeded Code = "deposit_limit_exceeded"
+	// CodeDepositMismatch is a total deposit lower than the current deposit.
+	CodeDepositMismatch Code = "deposit_mismatch"
+	// CodeTokenNetworkDeprecated is an open or a deposit on a token network whose
+	// safety deprecation switch is on.
+	CodeTokenNetworkDeprecated Code = "token_network_deprecated"
+	// CodeTokenNotRegistered is a call for a token without a token network.
+	CodeTokenNotRegistered Code = "token_not_registered"
+	// CodeTokenAlreadyRegistered is registering a token that already has a network.
+	CodeTokenAlreadyRegistered Code = "token_already_registered"
+	// CodePaymentConflict is a payment reusing the identifier of another one.
+	CodePaymentConflict Code = "payment_conflict"
+	// CodeWithdrawExpired is a withdraw the partner did not confirm before it expired.
+	CodeWithdrawExpired Code = "withdraw_expired"
+	// CodeNodeSyncing is a call made before the node caught up with the chain.
+	CodeNodeSyncing Code = "node_syncing"
+	// CodePaymentTimeout is a payment the client gave up waiting for, which the node
+	// may still complete. The node never reports it.
+	CodePaymentTimeout Code = "payment_timeout"
+	// CodeRiskyTimeouts is a payment refused by the client because the reveal timeout
+	// of a channel it could take leaves too short a margin. The node never reports it.
+	CodeRiskyTimeouts Code = "risky_timeouts"
+	// CodeRequestPending is a request the node accepted without acting on it yet, see
+	// Pending.
+	CodeRequestPending Code = "request_pending"
+	// CodeUnexpectedStatus is an error status the node answered with without
+	// describing the error, e.g. from a proxy in front of it.
+	CodeUnexpectedStatus Code = "unexpected_status"
+	// CodeInvalidResponse is a response of the node the client could not read or
+	// decode.
+	CodeInvalidResponse Code = "invalid_response"
+	// CodeResponseDrift is a response holding values the API does not allow, refused
+	// by the validation of the client.
+	CodeResponseDrift Code = "response_drift"
+	// CodePolicyViolation is a request denied by a policy of the client before it was
+	// sent.
+	CodePolicyViolation Code = "policy_violation"
+	// CodePartialFailure is a call over several token networks, partners or nodes,
+	// some of which failed while the others succeeded.
+	CodePartialFailure Code = "partial_failure"
+	// CodeParityMismatch is a migration refused because the new node lacks the open
+	// channels or balances of the old one.
+	CodeParityMismatch Code = "parity_mismatch"
+	// CodePreflightFailed is a node failing the preflight checks of the client.
+	CodePreflightFailed Code = "preflight_failed"
+	// CodeClientSuspended is a mutating call blocked by the kill switch of the client.
+	CodeClientSuspended Code = "client_suspended"
+)
+
+// patterns are the fragments of the messages of the Raiden node for every known
+// error, lowercase. More specific messages come first.
+var patterns = []struct {
+	code      Code
+	fragments []string
+}{
+	{CodePaymentConflict, []string{"payment with the same id", "identifier already used", "another payment with the same identifier"}},
+	{CodeDepositLimitExceeded, []string{"deposit limit", "exceeds the limit", "bigger than the current limit"}},
+	{CodeDepositMismatch, []string{"new total deposit must be higher", "deposit is lower than", "deposit mismatch"}},
+	{CodeInsufficientFunds, []string{"not enough balance to deposit", "insufficient funds", "not enough tokens", "insufficient eth"}},
+	{CodeInsufficientBalance, []string{"insufficient balance", "payment amount exceeds", "not enough capacity", "insufficient capacity"}},
+	{CodeNoRoute, []string{"no route", "no suitable path", "no path", "no available route"}},
+	{CodeWithdrawExpired, []string{"withdraw expired", "withdraw request expired", "withdraw has expired"}},
+	{CodeChannelExists, []string{"channel already exists", "already has an open channel", "channel with partner already exists", "partner address already exists"}},
+	{CodeChannelAlreadyClosed, []string{"already closed", "already settled", "channel is settled"}},
+	{CodeChannelNotOpen, []string{"channel is not in an open state", "channel is closed", "channel is not open"}},
+	{CodeChannelNotFound, []string{"channel not found", "channel does not exist", "no channel", "channel doesn't exist"}},
+	{CodeInvalidSettleTimeout, []string{"settle timeout", "settlement timeout"}},
+	{CodeTokenNetworkDeprecated, []string{"deprecation switch", "token network is deprecated", "network has been deprecated"}},
+	{CodeTokenAlreadyRegistered, []string{"token already registered", "already registered"}},
+	{CodeTokenNotRegistered, []string{"not registered", "token network", "unknown token"}},
+	{CodeInvalidAddress, []string{"eip55", "not a valid address", "invalid address"}},
+	{CodeInvalidAmount, []string{"amount must be", "invalid amount", "amount is negative", "not a valid integer"}},
+	{CodeNodeSyncing, []string{"still syncing", "not synced", "not yet synced", "synchronization"}},
+}
+
+// Match returns the code of the known error the message of the Raiden node describes,
+// ignoring case, or CodeUnknown.
+func Match(message string) Code {
+	var lower = strings.ToLower(message)
+
+	for _, pattern := range patterns {
+		for _, fragment := range pattern.fragments {
+			if strings.Contains(lower, fragment) {
+				return pattern.code
+			}
+		}
+	}
+
+	return CodeUnknown
+}
+
+// HasCode reports whether the error is an error response of the Raiden node with the
+// code, or an error the client returned instead of a response with the code, such as
+// a settle timeout the node would refuse. Errors wrapping another one, with an Unwrap
+// method, have the code of the error they wrap.
+func HasCode(err error, code Code) bool {
+	switch err := err.(type) {
+	case interface{ Code() Code }:
+		return err.Code() == code
+	case interface{ Unwrap() error }:
+		return HasCode(err.Unwrap(), code)
+	}
+
+	return false
+}
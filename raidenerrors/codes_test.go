@@ -0,0 +1,109 @@
+package raidenerrors
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	type testcase struct {
+		message      string
+		expectedCode Code
+	}
+
+	testcases := []testcase{
+		testcase{message: "Payment couldn't be completed because: Insufficient balance", expectedCode: CodeInsufficientBalance},
+		testcase{message: "Payment amount exceeds the available capacity", expectedCode: CodeInsufficientBalance},
+		testcase{message: "Not enough balance to deposit. 10 tokens available", expectedCode: CodeInsufficientFunds},
+		testcase{message: "Payment couldn't be completed because: there is no route available", expectedCode: CodeNoRoute},
+		testcase{message: "No suitable path found for transfer.", expectedCode: CodeNoRoute},
+		testcase{message: "Channel already exists", expectedCode: CodeChannelExists},
+		testcase{message: "Channel with given partner address already exists", expectedCode: CodeChannelExists},
+		testcase{message: "Channel not found", expectedCode: CodeChannelNotFound},
+		testcase{message: "Channel is not in an open state", expectedCode: CodeChannelNotOpen},
+		testcase{message: "Attempted to close an already closed channel", expectedCode: CodeChannelAlreadyClosed},
+		testcase{message: "Channel is already settled", expectedCode: CodeChannelAlreadyClosed},
+		testcase{message: "Not a valid EIP55 encoded address", expectedCode: CodeInvalidAddress},
+		testcase{message: "Amount must be a positive integer", expectedCode: CodeInvalidAmount},
+		testcase{message: "Settlement timeout should be between 500 and 555428", expectedCode: CodeInvalidSettleTimeout},
+		testcase{message: "The deposit of 100 is bigger than the current limit of 75", expectedCode: CodeDepositLimitExceeded},
+		testcase{message: "The new total deposit must be higher than the current one", expectedCode: CodeDepositMismatch},
+		testcase{message: "Token 0x2a65 is not registered", expectedCode: CodeTokenNotRegistered},
+		testcase{message: "Token already registered", expectedCode: CodeTokenAlreadyRegistered},
+		testcase{message: "The token network is deprecated, no new deposits are accepted", expectedCode: CodeTokenNetworkDeprecated},
+		testcase{message: "Another payment with the same id is in flight", expectedCode: CodePaymentConflict},
+		testcase{message: "The node is still syncing with the blockchain", expectedCode: CodeNodeSyncing},
+		testcase{message: "Withdraw request expired before the partner confirmed it", expectedCode: CodeWithdrawExpired},
+		testcase{message: "The requested URL was not found on the server.", expectedCode: CodeUnknown},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.message, func(t *testing.T) {
+			assert.Equal(t, tc.expectedCode, Match(tc.message))
+		})
+	}
+}
+
+func TestCodesStable(t *testing.T) {
+	// the values of the codes are part of the API, alerts match on them, and a code
+	// given to two errors does not compile as a duplicate key
+	for code, value := range map[Code]string{
+		CodeUnknown:                "",
+		CodeInsufficientBalance:    "insufficient_balance",
+		CodeInsufficientFunds:      "insufficient_funds",
+		CodeNoRoute:                "no_route",
+		CodeChannelExists:          "channel_exists",
+		CodeChannelNotFound:        "channel_not_found",
+		CodeChannelAlreadyClosed:   "channel_already_closed",
+		CodeChannelNotOpen:         "channel_not_open",
+		CodeInvalidAddress:         "invalid_address",
+		CodeInvalidAmount:          "invalid_amount",
+		CodeInvalidSettleTimeout:   "invalid_settle_timeout",
+		CodeDepositLimitExceeded:   "deposit_limit_exceeded",
+		CodeDepositMismatch:        "deposit_mismatch",
+		CodeTokenNetworkDeprecated: "token_network_deprecated",
+		CodeTokenNotRegistered:     "token_not_registered",
+		CodeTokenAlreadyRegistered: "token_already_registered",
+		CodePaymentConflict:        "payment_conflict",
+		CodeWithdrawExpired:        "withdraw_expired",
+		CodeNodeSyncing:            "node_syncing",
+		CodePaymentTimeout:         "payment_timeout",
+		CodeRiskyTimeouts:          "risky_timeouts",
+		CodeRequestPending:         "request_pending",
+		CodeUnexpectedStatus:       "unexpected_status",
+		CodeInvalidResponse:        "invalid_response",
+		CodeResponseDrift:          "response_drift",
+		CodePolicyViolation:        "policy_violation",
+		CodePartialFailure:         "partial_failure",
+		CodeParityMismatch:         "parity_mismatch",
+		CodePreflightFailed:        "preflight_failed",
+		CodeClientSuspended:        "client_suspended",
+	} {
+		assert.Equal(t, value, string(code))
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	type testcase struct {
+		name         string
+		err          error
+		expectedCode Code
+	}
+
+	testcases := []testcase{
+		testcase{name: "error status", err: New(502, io.EOF), expectedCode: CodeUnexpectedStatus},
+		testcase{name: "undecodable response", err: New(200, errors.New("invalid character")), expectedCode: CodeInvalidResponse},
+		testcase{name: "coded underlying error", err: New(200, ErrResponseDrift), expectedCode: CodeResponseDrift},
+		testcase{name: "no response", err: &Error{Err: io.EOF}, expectedCode: CodeUnknown},
+		testcase{name: "sentinel", err: ErrClientSuspended, expectedCode: CodeClientSuspended},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.True(t, HasCode(tc.err, tc.expectedCode))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+// Package raidenerrors classifies the errors returned by the Raiden clients, so that
+// callers and retrying middlewares can tell transient network or node issues from
+// permanent errors such as an invalid address or insufficient funds.
+package raidenerrors
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/redact"
+)
+
+// Retryable is implemented by errors that know whether the request that failed can
+// be retried as is.
+type Retryable interface {
+	Retryable() bool
+}
+
+// Temporary is implemented by errors that know whether they are temporary, like the
+// errors of the net package.
+type Temporary interface {
+	Temporary() bool
+}
+
+// Error is an error returned for a response of the Raiden node, along with its
+// status code and how long the response asked to wait before retrying, if it did.
+// Its message is the one of the underlying error, with the secrets it holds redacted,
+// see redact.String.
+type Error struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Err        error
+}
+
+// New returns an error for a response with the status code.
+func New(statusCode int, err error) *Error {
+	return &Error{StatusCode: statusCode, Err: err}
+}
+
+func (err *Error) Error() string {
+	return redact.String(err.Err.Error())
+}
+
+// Unwrap returns the underlying error.
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// Code returns the code of the underlying error when it has one, e.g. the
+// CodeResponseDrift of a response refused by the validation of the client,
+// CodeUnexpectedStatus for an error status and CodeInvalidResponse for a response
+// that could not be read or decoded otherwise.
+func (err *Error) Code() Code {
+	if coded, ok := err.Err.(interface{ Code() Code }); ok {
+		return coded.Code()
+	}
+
+	switch {
+	case err.StatusCode >= http.StatusBadRequest:
+		return CodeUnexpectedStatus
+	case err.StatusCode > 0:
+		return CodeInvalidResponse
+	}
+
+	return CodeUnknown
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *Error) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// Retryable reports whether the status code is one of a node that is overloaded,
+// restarting or behind a failing proxy, or whether the underlying error is retryable
+// when the node did not respond with an error status.
+func (err *Error) Retryable() bool {
+	if err.StatusCode >= http.StatusBadRequest {
+		return retryableStatus(err.StatusCode)
+	}
+
+	return IsRetryable(err.Err)
+}
+
+// Temporary is the same as Retryable.
+func (err *Error) Temporary() bool {
+	return err.Retryable()
+}
+
+// retryableStatus reports whether an error status code is the one of a node that is
+// overloaded, restarting or behind a failing proxy.
+func retryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+
+	return false
+}
+
+// IsRetryable reports whether the request that failed with the error can be retried.
+// Errors implementing Retryable or Temporary decide for themselves. Otherwise timeouts,
+// failures to reach the node and connections dropped before a response are retryable,
+// and any other error, such as a cancelled context or an invalid response, is not.
+func IsRetryable(err error) bool {
+	switch err := err.(type) {
+	case nil:
+		return false
+	case Retryable:
+		return err.Retryable()
+	case *url.Error:
+		if err.Timeout() {
+			return true
+		}
+
+		return IsRetryable(err.Err)
+	case *net.OpError:
+		return true
+	case net.Error:
+		return err.Timeout() || err.Temporary()
+	case Temporary:
+		return err.Temporary()
+	}
+
+	switch err {
+	case context.DeadlineExceeded, io.EOF, io.ErrUnexpectedEOF:
+		return true
+	}
+
+	return false
+}
+
+// IsUnsent reports whether the request that failed with the error provably never
+// left, i.e. that the connection to the node could not be established, the address
+// of the node not resolving or the node refusing the connection, rather than e.g. that
+// the request timed out or that the connection broke while the node may have been
+// processing it. Any request, payments included, that failed so can be sent again.
+func IsUnsent(err error) bool {
+	if urlErr, ok := err.(*url.Error); ok {
+		err = urlErr.Err
+	}
+
+	switch err := err.(type) {
+	case *net.OpError:
+		return err.Op == "dial"
+	case *net.DNSError:
+		return true
+	}
+
+	return false
+}
+
+// StatusCode returns the status code of the response the error was returned for, or
+// zero when the node did not respond. Errors wrapping another one, with an Unwrap
+// method, have the status code of the error they wrap.
+func StatusCode(err error) int {
+	switch err := err.(type) {
+	case *Error:
+		return err.StatusCode
+	case *APIError:
+		return err.StatusCode
+	case interface{ Unwrap() error }:
+		return StatusCode(err.Unwrap())
+	}
+
+	return 0
+}
+
+// RetryAfter returns how long the response the error was returned for asked to wait
+// before retrying, from its Retry-After header, or zero when it did not ask.
+func RetryAfter(err error) time.Duration {
+	switch err := err.(type) {
+	case *Error:
+		return err.RetryAfter
+	case *APIError:
+		return err.RetryAfter
+	case interface{ Unwrap() error }:
+		return RetryAfter(err.Unwrap())
+	}
+
+	return 0
+}
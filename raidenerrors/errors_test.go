@@ -0,0 +1,172 @@
+package raidenerrors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleIsRetryable() {
+	var err = New(http.StatusServiceUnavailable, errors.New("EOF"))
+
+	if IsRetryable(err) {
+		fmt.Println("raiden node is unavailable, retrying later")
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	type testcase struct {
+		name              string
+		err               error
+		expectedRetryable bool
+	}
+
+	testcases := []testcase{
+		testcase{name: "nil", err: nil, expectedRetryable: false},
+		testcase{name: "service unavailable", err: New(http.StatusServiceUnavailable, io.EOF), expectedRetryable: true},
+		testcase{name: "internal server error", err: New(http.StatusInternalServerError, io.EOF), expectedRetryable: true},
+		testcase{name: "too many requests", err: New(http.StatusTooManyRequests, io.EOF), expectedRetryable: true},
+		testcase{name: "conflict", err: New(http.StatusConflict, errors.New("insufficient balance")), expectedRetryable: false},
+		testcase{name: "bad request", err: New(http.StatusBadRequest, errors.New("invalid address")), expectedRetryable: false},
+		testcase{name: "invalid response", err: New(http.StatusOK, errors.New("invalid amount: 2.5")), expectedRetryable: false},
+		testcase{name: "truncated response", err: New(http.StatusOK, io.ErrUnexpectedEOF), expectedRetryable: true},
+		testcase{name: "deadline exceeded", err: context.DeadlineExceeded, expectedRetryable: true},
+		testcase{name: "cancelled", err: context.Canceled, expectedRetryable: false},
+		testcase{name: "network timeout", err: timeoutError{}, expectedRetryable: true},
+		testcase{name: "unclassified error", err: errors.New("unknown profile"), expectedRetryable: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedRetryable, IsRetryable(tc.err))
+		})
+	}
+}
+
+func TestIsRetryableHTTPErrors(t *testing.T) {
+	var (
+		stalled = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			<-request.Context().Done()
+		}))
+		closed = httptest.NewServer(http.NotFoundHandler())
+	)
+	defer stalled.Close()
+
+	closed.Close()
+
+	t.Run("unreachable node", func(t *testing.T) {
+		_, err := http.Get(closed.URL)
+
+		require.Error(t, err)
+		assert.True(t, IsRetryable(err))
+	})
+
+	t.Run("request timeout", func(t *testing.T) {
+		_, err := (&http.Client{Timeout: 10 * time.Millisecond}).Get(stalled.URL)
+
+		require.Error(t, err)
+		assert.True(t, IsRetryable(err))
+	})
+
+	t.Run("cancelled request", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		request, _ := http.NewRequest("GET", stalled.URL, nil)
+
+		_, err := http.DefaultClient.Do(request.WithContext(ctx))
+
+		require.Error(t, err)
+		assert.False(t, IsRetryable(err))
+	})
+}
+
+func TestError(t *testing.T) {
+	var err = New(http.StatusInternalServerError, io.EOF)
+
+	assert.EqualError(t, err, "EOF")
+	assert.Equal(t, io.EOF, err.Unwrap())
+	assert.True(t, err.Temporary())
+	assert.Equal(t, http.StatusInternalServerError, StatusCode(err))
+	assert.Equal(t, 0, StatusCode(io.EOF))
+}
+
+func TestRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Second, RetryAfter(&Error{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Second, Err: io.EOF}))
+	assert.Equal(t, time.Minute, RetryAfter(&APIError{StatusCode: http.StatusServiceUnavailable, RetryAfter: time.Minute}))
+	assert.Equal(t, time.Duration(0), RetryAfter(New(http.StatusTooManyRequests, io.EOF)))
+	assert.Equal(t, time.Duration(0), RetryAfter(io.EOF))
+}
+
+func TestIsUnsent(t *testing.T) {
+	type testcase struct {
+		name     string
+		err      error
+		expected bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:     "connection refused",
+			err:      &url.Error{Op: "Post", URL: "http://localhost:5001", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}},
+			expected: true,
+		},
+		testcase{
+			name:     "unknown host",
+			err:      &url.Error{Op: "Get", URL: "http://raiden:5001", Err: &net.DNSError{Err: "no such host", Name: "raiden"}},
+			expected: true,
+		},
+		testcase{
+			name:     "timeout",
+			err:      &url.Error{Op: "Post", URL: "http://localhost:5001", Err: timeoutError{}},
+			expected: false,
+		},
+		testcase{
+			name:     "connection reset once written",
+			err:      &url.Error{Op: "Post", URL: "http://localhost:5001", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset by peer")}},
+			expected: false,
+		},
+		testcase{
+			name:     "error response",
+			err:      New(http.StatusServiceUnavailable, io.EOF),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsUnsent(tc.err))
+		})
+	}
+}
+
+type wrapped struct {
+	err error
+}
+
+func (err *wrapped) Error() string { return "wrapped: " + err.err.Error() }
+func (err *wrapped) Unwrap() error { return err.err }
+
+func TestUnwrap(t *testing.T) {
+	var err = &wrapped{err: &APIError{StatusCode: http.StatusServiceUnavailable, RetryAfter: time.Minute, Message: "node is still syncing"}}
+
+	assert.Equal(t, http.StatusServiceUnavailable, StatusCode(err))
+	assert.Equal(t, time.Minute, RetryAfter(err))
+	assert.True(t, HasCode(err, CodeNodeSyncing))
+}
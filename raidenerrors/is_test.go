@@ -0,0 +1,24 @@
+//go:build go1.13
+// +build go1.13
+
+package raidenerrors
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentinelsWrapped(t *testing.T) {
+	var err error = &Error{StatusCode: http.StatusConflict, Err: &APIError{StatusCode: http.StatusConflict, Message: "Channel is not in an open state"}}
+
+	assert.True(t, errors.Is(err, ErrChannelNotOpen))
+	assert.False(t, errors.Is(err, ErrChannelNotFound))
+
+	var apiErr *APIError
+
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "Channel is not in an open state", apiErr.Message)
+}
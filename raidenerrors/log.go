@@ -0,0 +1,34 @@
+//go:build go1.21
+// +build go1.21
+
+package raidenerrors
+
+import "log/slog"
+
+// LogValue implements slog.LogValuer, logging the status code, whether the error is
+// retryable and its message, with the credentials and queries of the URLs in the
+// message redacted, see redact.String.
+func (err *Error) LogValue() slog.Value {
+	var attrs = []slog.Attr{
+		slog.Bool("retryable", err.Retryable()),
+		slog.String("message", err.Error()),
+	}
+
+	if err.StatusCode != 0 {
+		attrs = append([]slog.Attr{slog.Int("status_code", err.StatusCode)}, attrs...)
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer, logging the status code, the code of the
+// known error the message matches and the message of the node, redacted like that of
+// an Error.
+func (err *APIError) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Int("status_code", err.StatusCode),
+		slog.String("code", string(err.Code())),
+		slog.Bool("retryable", err.Retryable()),
+		slog.String("message", err.Message),
+	)
+}
@@ -0,0 +1,52 @@
+package raidenerrors
+
+import (
+	"net/url"
+	"time"
+)
+
+// Pending is returned for a request the Raiden node, or a proxy in front of it,
+// answered with 202 Accepted, e.g. a deposit on a slow node, instead of the result
+// of the request. The request is being acted upon and must not be sent again: its
+// outcome is polled at Location, the URL of the Location header of the response, nil
+// when it had none, see config.AcceptedPolling, after RetryAfter when the response
+// asked to wait. Status is the status field of the body of the response, e.g.
+// "pending", empty when it had none.
+type Pending struct {
+	Location   *url.URL
+	RetryAfter time.Duration
+	Status     string
+	Body       []byte
+}
+
+func (err *Pending) Error() string {
+	if err.Location != nil {
+		// the query of the location may hold a token of the proxy
+		return "raiden node accepted the request, its outcome is pending at " + err.Location.Path
+	}
+
+	return "raiden node accepted the request, its outcome is pending"
+}
+
+// Retryable reports false, the request being acted upon already.
+func (err *Pending) Retryable() bool {
+	return false
+}
+
+// Code returns CodeRequestPending, see HasCode.
+func (err *Pending) Code() Code {
+	return CodeRequestPending
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *Pending) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// AsPending returns the error as a *Pending when it is one, for callers telling a
+// request accepted by the node from a failed one.
+func AsPending(err error) (*Pending, bool) {
+	pending, ok := err.(*Pending)
+
+	return pending, ok
+}
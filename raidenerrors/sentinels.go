@@ -0,0 +1,68 @@
+package raidenerrors
+
+import "strings"
+
+// The sentinel errors of the codes, which the error responses of the Raiden node and
+// the errors the client returns instead of a response match with errors.Is, from Go
+// 1.13 on, when they have the code:
+//
+//	if errors.Is(err, raidenerrors.ErrInsufficientFunds) {
+//		// depositing less
+//	}
+var (
+	ErrInsufficientBalance    error = sentinel(CodeInsufficientBalance)
+	ErrInsufficientFunds      error = sentinel(CodeInsufficientFunds)
+	ErrNoRoute                error = sentinel(CodeNoRoute)
+	ErrChannelExists          error = sentinel(CodeChannelExists)
+	ErrChannelNotFound        error = sentinel(CodeChannelNotFound)
+	ErrChannelAlreadyClosed   error = sentinel(CodeChannelAlreadyClosed)
+	ErrChannelNotOpen         error = sentinel(CodeChannelNotOpen)
+	ErrInvalidAddress         error = sentinel(CodeInvalidAddress)
+	ErrInvalidAmount          error = sentinel(CodeInvalidAmount)
+	ErrInvalidSettleTimeout   error = sentinel(CodeInvalidSettleTimeout)
+	ErrDepositLimitExceeded   error = sentinel(CodeDepositLimitExceeded)
+	ErrDepositMismatch        error = sentinel(CodeDepositMismatch)
+	ErrTokenNetworkDeprecated error = sentinel(CodeTokenNetworkDeprecated)
+	ErrTokenNotRegistered     error = sentinel(CodeTokenNotRegistered)
+	ErrTokenAlreadyRegistered error = sentinel(CodeTokenAlreadyRegistered)
+	ErrPaymentConflict        error = sentinel(CodePaymentConflict)
+	ErrWithdrawExpired        error = sentinel(CodeWithdrawExpired)
+	ErrNodeSyncing            error = sentinel(CodeNodeSyncing)
+	ErrPaymentTimeout         error = sentinel(CodePaymentTimeout)
+	ErrRiskyTimeouts          error = sentinel(CodeRiskyTimeouts)
+	ErrRequestPending         error = sentinel(CodeRequestPending)
+	ErrUnexpectedStatus       error = sentinel(CodeUnexpectedStatus)
+	ErrInvalidResponse        error = sentinel(CodeInvalidResponse)
+	ErrResponseDrift          error = sentinel(CodeResponseDrift)
+	ErrPolicyViolation        error = sentinel(CodePolicyViolation)
+	ErrPartialFailure         error = sentinel(CodePartialFailure)
+	ErrParityMismatch         error = sentinel(CodeParityMismatch)
+	ErrPreflightFailed        error = sentinel(CodePreflightFailed)
+	ErrClientSuspended        error = sentinel(CodeClientSuspended)
+)
+
+// sentinel is the error every error with its code matches.
+type sentinel Code
+
+func (err sentinel) Error() string {
+	return strings.Replace(string(err), "_", " ", -1)
+}
+
+// Code returns the code of the sentinel error, for the errors returned as is, such
+// as the ErrSuspended of the client.
+func (err sentinel) Code() Code {
+	return Code(err)
+}
+
+// Is reports whether the target is the sentinel error of the code, for the Is methods
+// of the errors with a code.
+func (code Code) Is(target error) bool {
+	sentinel, ok := target.(sentinel)
+
+	return ok && code != CodeUnknown && Code(sentinel) == code
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *APIError) Is(target error) bool {
+	return err.Code().Is(target)
+}
@@ -0,0 +1,59 @@
+package raidenerrors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentinels(t *testing.T) {
+	type testcase struct {
+		name       string
+		err        *APIError
+		target     error
+		expectedIs bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:       "insufficient funds",
+			err:        &APIError{StatusCode: http.StatusPaymentRequired, Message: "Not enough balance to deposit. 10 tokens available"},
+			target:     ErrInsufficientFunds,
+			expectedIs: true,
+		},
+		testcase{
+			name:       "channel not found",
+			err:        &APIError{StatusCode: http.StatusNotFound, Message: "Channel not found"},
+			target:     ErrChannelNotFound,
+			expectedIs: true,
+		},
+		testcase{
+			name:       "other code",
+			err:        &APIError{StatusCode: http.StatusConflict, Message: "Insufficient balance"},
+			target:     ErrInsufficientFunds,
+			expectedIs: false,
+		},
+		testcase{
+			name:       "unknown code",
+			err:        &APIError{StatusCode: http.StatusInternalServerError, Message: "Internal Server Error"},
+			target:     ErrChannelNotFound,
+			expectedIs: false,
+		},
+		testcase{
+			name:       "not a sentinel",
+			err:        &APIError{StatusCode: http.StatusNotFound, Message: "Channel not found"},
+			target:     &APIError{StatusCode: http.StatusNotFound, Message: "Channel not found"},
+			expectedIs: false,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedIs, tc.err.Is(tc.target))
+		})
+	}
+
+	assert.EqualError(t, ErrChannelNotFound, "channel not found")
+	assert.False(t, CodeUnknown.Is(ErrChannelNotFound))
+}
@@ -0,0 +1,25 @@
+package raidentest
+
+import (
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+)
+
+// Clock is the source of time of the fake server and its scenarios, so that tests
+// can control the passing of time with a ManualClock.
+type Clock = clock.Clock
+
+// ManualClock is a clock which only moves when advanced, making timed scenarios
+// deterministic, see clock.Manual.
+type ManualClock = clock.Manual
+
+// RealClock returns a clock following the system time.
+func RealClock() Clock {
+	return clock.Real()
+}
+
+// NewManualClock creates a clock standing still at start until it is advanced.
+func NewManualClock(start time.Time) *ManualClock {
+	return clock.NewManual(start)
+}
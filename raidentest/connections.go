@@ -0,0 +1,94 @@
+package raidentest
+
+import (
+	"encoding/json"
+	"math/big"
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Funds returns the funds the node joined the token network with, or nil when it did
+// not join it.
+func (server *Server) Funds(tokenAddress common.Address) *big.Int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return amounts.Copy(server.funds[tokenAddress])
+}
+
+// serveConnections joins and leaves the token networks, joining records the funds
+// without opening channels, leaving closes the opened channels of the token.
+func (server *Server) serveConnections(writer http.ResponseWriter, request *http.Request, parts []string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	switch {
+	case request.Method == "GET" && len(parts) == 0:
+		connections := make(map[string]interface{})
+
+		for tokenAddress, funds := range server.funds {
+			var (
+				sumDeposits = new(big.Int)
+				opened      int64
+			)
+
+			for _, channel := range server.channels {
+				if channel.TokenAddress == tokenAddress && channel.State == "opened" {
+					sumDeposits.Add(sumDeposits, channel.TotalDeposit)
+					opened++
+				}
+			}
+
+			connections[tokenAddress.Hex()] = map[string]interface{}{
+				"funds":        amounts.Number(funds),
+				"sum_deposits": amounts.Number(sumDeposits),
+				"channels":     opened,
+			}
+		}
+
+		respond(writer, http.StatusOK, connections)
+	case request.Method == "PUT" && len(parts) == 1:
+		tokenAddress := common.HexToAddress(parts[0])
+		join := &struct {
+			Funds json.Number `json:"funds"`
+		}{}
+
+		if err := json.NewDecoder(request.Body).Decode(join); err != nil {
+			respondError(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		funds, err := amounts.Parse(join.Funds)
+		if err != nil {
+			respondError(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if _, ok := server.networks[tokenAddress]; !ok {
+			respondError(writer, http.StatusConflict, "Token network for token "+tokenAddress.Hex()+" does not exist")
+			return
+		}
+
+		server.funds[tokenAddress] = funds
+
+		writer.WriteHeader(http.StatusNoContent)
+	case request.Method == "DELETE" && len(parts) == 1:
+		tokenAddress := common.HexToAddress(parts[0])
+		closed := make([]string, 0)
+
+		for _, channel := range server.channels {
+			if channel.TokenAddress == tokenAddress && channel.State == "opened" {
+				channel.State = "closed"
+				closed = append(closed, channel.PartnerAddress.Hex())
+			}
+		}
+
+		delete(server.funds, tokenAddress)
+
+		respond(writer, http.StatusOK, closed)
+	default:
+		respondError(writer, http.StatusMethodNotAllowed, "The method is not allowed for the requested URL.")
+	}
+}
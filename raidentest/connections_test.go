@@ -0,0 +1,38 @@
+package raidentest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerConnections(t *testing.T) {
+	var ctx = context.Background()
+
+	server, client := newTestServer()
+	defer server.Close()
+
+	assert.Error(t, client.Connections().Join(ctx, tokenAddress, big.NewInt(500)), "the token is not registered")
+
+	server.AddToken(tokenAddress, common.HexToAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2"))
+
+	require.NoError(t, client.Connections().Join(ctx, tokenAddress, big.NewInt(500)))
+	assert.Equal(t, big.NewInt(500), server.Funds(tokenAddress))
+
+	connections, err := client.Connections().List(ctx)
+	require.NoError(t, err)
+	require.Contains(t, connections, tokenAddress)
+	assert.Equal(t, big.NewInt(500), connections[tokenAddress].Funds)
+	assert.Equal(t, big.NewInt(100), connections[tokenAddress].SumDeposits)
+	assert.Equal(t, int64(1), connections[tokenAddress].Channels)
+
+	closed, err := client.Connections().Leave(ctx, tokenAddress)
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{partnerAddress}, closed)
+	assert.Equal(t, "closed", server.Channel(tokenAddress, partnerAddress).State)
+	assert.Nil(t, server.Funds(tokenAddress))
+}
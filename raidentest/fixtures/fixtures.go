@@ -0,0 +1,154 @@
+// Package fixtures holds the canonical JSON responses of a Raiden node that the tests
+// of the client are written against, along with typed accessors decoding them, so
+// that projects built on the client can mock the node with realistic payloads
+// instead of copying response strings, e.g.
+//
+//	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, fixtures.ChannelsJSON))
+//
+// The payloads are the ones of Raiden 0.100.x, see config.Config.NodeVersion. The
+// accessors return new values on every call, which the caller may modify.
+package fixtures
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/connections"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The addresses of the fixtures.
+var (
+	// OurAddress is the address of the node.
+	OurAddress = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+	// TokenAddress is the token of the channels, payments and connections.
+	TokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	// TokenNetworkAddress is the token network of TokenAddress.
+	TokenNetworkAddress = common.HexToAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2")
+	// PartnerAddress is the partner of the channel and the target of the payments.
+	PartnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+)
+
+// The JSON responses of the endpoints of the Raiden node API.
+const (
+	// AddressJSON is the response of GET /api/v1/address.
+	AddressJSON = `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`
+	// TokensJSON is the response of GET /api/v1/tokens.
+	TokensJSON = `["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"]`
+	// TokenNetworkJSON is the response of GET /api/v1/tokens/<token>.
+	TokenNetworkJSON = `"0xE5637F0103794C7e05469A9964E4563089a5E6f2"`
+	// RegisteredTokenJSON is the response of PUT /api/v1/tokens/<token>.
+	RegisteredTokenJSON = `{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2"}`
+	// PartnersJSON is the response of GET /api/v1/tokens/<token>/partners.
+	PartnersJSON = `[{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","channel":"/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"}]`
+	// ChannelJSON is the response of GET /api/v1/channels/<token>/<partner>, and of
+	// the requests opening and updating a channel.
+	ChannelJSON = `{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":25000000,"total_deposit":35000000,"state":"opened","settle_timeout":500,"reveal_timeout":30}`
+	// ChannelsJSON is the response of GET /api/v1/channels and
+	// GET /api/v1/channels/<token>.
+	ChannelsJSON = `[` + ChannelJSON + `]`
+	// PaymentJSON is the response of POST /api/v1/payments/<token>/<target>.
+	PaymentJSON = `{"initiator_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","amount":200,"identifier":42}`
+	// PaymentEventsJSON is the response of GET /api/v1/payments/<token>/<partner>.
+	PaymentEventsJSON = `[{"event":"EventPaymentReceivedSuccess","amount":5,"initiator":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","identifier":1,"log_time":"2018-10-30T07:03:52.193Z"},{"event":"EventPaymentSentSuccess","amount":35,"target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","identifier":2,"log_time":"2018-10-30T07:04:22.293Z"},{"event":"EventPaymentSentFailed","target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","identifier":3,"log_time":"2018-10-30T07:10:13.122Z"}]`
+	// PendingTransfersJSON is the response of GET /api/v1/pending_transfers.
+	PendingTransfersJSON = `[{"channel_identifier":20,"initiator":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","locked_amount":119,"payment_identifier":1,"role":"initiator","target":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","transferred_amount":331}]`
+	// ConnectionsJSON is the response of GET /api/v1/connections.
+	ConnectionsJSON = `{"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8":{"funds":100,"sum_deposits":67,"channels":3}}`
+	// ErrorJSON is the response of a request the node refused, with ErrorStatusCode.
+	ErrorJSON = `{"errors":"Not enough balance to deposit. 1337 tokens available"}`
+)
+
+// ErrorStatusCode is the status code of the ErrorJSON response.
+const ErrorStatusCode = http.StatusPaymentRequired
+
+// Tokens returns the tokens of TokensJSON.
+func Tokens() []common.Address {
+	var tokenList []common.Address
+
+	mustDecode(TokensJSON, &tokenList)
+
+	return tokenList
+}
+
+// Partners returns the partners of PartnersJSON.
+func Partners() []*tokens.Partner {
+	var partners []*tokens.Partner
+
+	mustDecode(PartnersJSON, &partners)
+
+	return partners
+}
+
+// Channel returns the channel of ChannelJSON.
+func Channel() *channels.Channel {
+	var channel = &channels.Channel{}
+
+	mustDecode(ChannelJSON, channel)
+
+	return channel
+}
+
+// Channels returns the channels of ChannelsJSON.
+func Channels() []*channels.Channel {
+	var channelList []*channels.Channel
+
+	mustDecode(ChannelsJSON, &channelList)
+
+	return channelList
+}
+
+// Payment returns the payment of PaymentJSON.
+func Payment() *payments.Payment {
+	var payment = &payments.Payment{}
+
+	mustDecode(PaymentJSON, payment)
+
+	return payment
+}
+
+// PaymentEvents returns the payment events of PaymentEventsJSON.
+func PaymentEvents() []*payments.Event {
+	var events []*payments.Event
+
+	mustDecode(PaymentEventsJSON, &events)
+
+	return events
+}
+
+// PendingTransfers returns the pending transfers of PendingTransfersJSON.
+func PendingTransfers() []*pendingtransfers.Transfer {
+	var transfers []*pendingtransfers.Transfer
+
+	mustDecode(PendingTransfersJSON, &transfers)
+
+	return transfers
+}
+
+// Connections returns the connections of ConnectionsJSON.
+func Connections() connections.Connections {
+	var connectionMap connections.Connections
+
+	mustDecode(ConnectionsJSON, &connectionMap)
+
+	return connectionMap
+}
+
+// Error returns the error of the ErrorJSON response.
+func Error() *raidenerrors.APIError {
+	return raidenerrors.Parse(ErrorStatusCode, []byte(ErrorJSON))
+}
+
+// mustDecode decodes a fixture, which can only fail when the fixtures no longer match
+// the types of the client.
+func mustDecode(fixture string, v interface{}) {
+	if err := json.Unmarshal([]byte(fixture), v); err != nil {
+		panic(fmt.Sprintf("fixtures: unable to decode fixture: %s", err.Error()))
+	}
+}
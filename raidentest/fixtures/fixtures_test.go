@@ -0,0 +1,97 @@
+package fixtures
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/connections"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/cpurta/go-raiden-client/tokens"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Example() {
+	var raidenConfig = &config.Config{
+		Host:       "http://localhost:5001",
+		APIVersion: "v1",
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, ChannelsJSON))
+
+	channelList, err := channels.NewLister(raidenConfig, http.DefaultClient).ListAll(context.Background())
+	if err != nil {
+		panic(err)
+	}
+
+	// the code under test sees the same channels as the fixture
+	fmt.Println(channelList[0].Equal(Channel()))
+}
+
+func TestFixturesDecodeStrictly(t *testing.T) {
+	type testcase struct {
+		name    string
+		fixture string
+		value   interface{}
+	}
+
+	testcases := []testcase{
+		testcase{name: "tokens", fixture: TokensJSON, value: &[]common.Address{}},
+		testcase{name: "token network", fixture: TokenNetworkJSON, value: &common.Address{}},
+		testcase{name: "partners", fixture: PartnersJSON, value: &[]*tokens.Partner{}},
+		testcase{name: "channel", fixture: ChannelJSON, value: &channels.Channel{}},
+		testcase{name: "channels", fixture: ChannelsJSON, value: &[]*channels.Channel{}},
+		testcase{name: "payment", fixture: PaymentJSON, value: &payments.Payment{}},
+		testcase{name: "payment events", fixture: PaymentEventsJSON, value: &[]*payments.Event{}},
+		testcase{name: "pending transfers", fixture: PendingTransfersJSON, value: &[]*pendingtransfers.Transfer{}},
+		testcase{name: "connections", fixture: ConnectionsJSON, value: &connections.Connections{}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.NoError(t, util.Decode(config.Strict, strings.NewReader(tc.fixture), tc.value))
+		})
+	}
+}
+
+func TestAccessors(t *testing.T) {
+	channel := Channel()
+	assert.Equal(t, int64(20), channel.ChannelIdentifier)
+	assert.Equal(t, TokenAddress, channel.TokenAddress)
+	assert.Equal(t, PartnerAddress, channel.PartnerAddress)
+	assert.Equal(t, big.NewInt(25000000), channel.Balance)
+
+	// every call returns a new value
+	channel.Balance.SetInt64(0)
+	assert.Equal(t, big.NewInt(25000000), Channel().Balance)
+	assert.Len(t, Channels(), 1)
+
+	assert.Equal(t, int64(42), Payment().Identifier)
+	assert.Equal(t, PartnerAddress, Payment().TargetAddress)
+
+	events := PaymentEvents()
+	require.Len(t, events, 3)
+	assert.Equal(t, payments.EventPaymentSentFailed, events[2].EventName)
+
+	assert.Equal(t, []common.Address{TokenAddress, common.HexToAddress("0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6")}, Tokens())
+	assert.Equal(t, PartnerAddress, Partners()[0].Address)
+	assert.Len(t, PendingTransfers(), 1)
+	assert.Equal(t, int64(3), Connections()[TokenAddress].Channels)
+
+	apiErr := Error()
+	require.NotNil(t, apiErr)
+	assert.Equal(t, ErrorStatusCode, apiErr.StatusCode)
+}
@@ -0,0 +1,48 @@
+package raidentest
+
+import (
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// AddPendingTransfer adds a transfer pending in a channel of the node, served until
+// the test is done since the node never completes it.
+func (server *Server) AddPendingTransfer(transfer *pendingtransfers.Transfer) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.pending = append(server.pending, transfer.Clone())
+}
+
+// servePendingTransfers lists the pending transfers, of a token or of the channel of
+// a token with a partner.
+func (server *Server) servePendingTransfers(writer http.ResponseWriter, parts []string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	var transfers = make([]*pendingtransfers.Transfer, 0)
+
+	if len(parts) > 2 {
+		respondError(writer, http.StatusNotFound, "The requested URL was not found on the server.")
+		return
+	}
+
+	for _, transfer := range server.pending {
+		if len(parts) >= 1 && transfer.TokenAddress != common.HexToAddress(parts[0]) {
+			continue
+		}
+
+		if len(parts) == 2 {
+			channel := server.channel(common.HexToAddress(parts[0]), common.HexToAddress(parts[1]))
+			if channel == nil || channel.ChannelIdentifier != transfer.ChannelIdentifier {
+				continue
+			}
+		}
+
+		transfers = append(transfers, transfer)
+	}
+
+	respond(writer, http.StatusOK, transfers)
+}
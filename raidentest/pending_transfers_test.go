@@ -0,0 +1,57 @@
+package raidentest
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerPendingTransfers(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		otherToken = common.HexToAddress("0x0f114A1E9Db192502E7856309cc899952b3db1ED")
+		transfer   = &pendingtransfers.Transfer{
+			ChannelIdentifier: 1,
+			Initiator:         ourAddress,
+			LockedAmount:      big.NewInt(40),
+			PaymentIdentifier: 7,
+			Role:              "initiator",
+			Target:            partnerAddress,
+			TokenAddress:      tokenAddress,
+			TransferredAmount: big.NewInt(0),
+		}
+		other = &pendingtransfers.Transfer{
+			ChannelIdentifier: 2,
+			LockedAmount:      big.NewInt(10),
+			TokenAddress:      otherToken,
+			TransferredAmount: big.NewInt(0),
+		}
+	)
+
+	server, client := newTestServer()
+	defer server.Close()
+
+	server.AddPendingTransfer(transfer)
+	server.AddPendingTransfer(other)
+
+	transfers, err := client.PendingTransfers().ListAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, transfers, 2)
+
+	transfers, err = client.PendingTransfers().ListToken(ctx, tokenAddress)
+	require.NoError(t, err)
+	assert.Equal(t, []*pendingtransfers.Transfer{transfer}, transfers)
+
+	transfers, err = client.PendingTransfers().ListChannel(ctx, tokenAddress, partnerAddress)
+	require.NoError(t, err)
+	assert.Equal(t, []*pendingtransfers.Transfer{transfer}, transfers)
+
+	transfers, err = client.PendingTransfers().ListChannel(ctx, otherToken, partnerAddress)
+	require.NoError(t, err)
+	assert.Empty(t, transfers)
+}
@@ -0,0 +1,56 @@
+package raidentest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+)
+
+// Request is a request received by the fake node, with its body, e.g. to assert on
+// the payments a test made.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Decode decodes the JSON body of the request into the value.
+func (request *Request) Decode(value interface{}) error {
+	return json.Unmarshal(request.Body, value)
+}
+
+// Requests returns the requests received by the node, in the order they were
+// received, including the ones intercepted by scenarios.
+func (server *Server) Requests() []*Request {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return append([]*Request{}, server.requests...)
+}
+
+// RequestsTo returns the requests received by the node with the method and path,
+// e.g. "POST" and "/api/v1/payments/<token>/<target>", in the order they were
+// received.
+func (server *Server) RequestsTo(method, path string) []*Request {
+	var matching = make([]*Request, 0)
+
+	for _, request := range server.Requests() {
+		if request.Method == method && request.Path == path {
+			matching = append(matching, request)
+		}
+	}
+
+	return matching
+}
+
+func copyHeader(header http.Header) http.Header {
+	var copied = make(http.Header, len(header))
+
+	for name, values := range header {
+		copied[name] = append([]string{}, values...)
+	}
+
+	return copied
+}
@@ -0,0 +1,58 @@
+package raidentest
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerRequests(t *testing.T) {
+	var (
+		ctx  = context.Background()
+		path = "/api/v1/payments/" + tokenAddress.Hex() + "/" + partnerAddress.Hex()
+	)
+
+	server, client := newTestServer()
+	defer server.Close()
+
+	server.AddPaymentEvent(tokenAddress, partnerAddress, &payments.Event{
+		EventName:  "EventPaymentReceivedSuccess",
+		Amount:     big.NewInt(5),
+		Initiator:  partnerAddress,
+		Identifier: 3,
+		LogTime:    time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC),
+	})
+
+	_, err := client.Payments().InitiateWithIdentifier(ctx, tokenAddress, partnerAddress, big.NewInt(40), 7)
+	require.NoError(t, err)
+
+	events, err := client.Payments().List(ctx, tokenAddress, partnerAddress)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, "EventPaymentReceivedSuccess", events[0].EventName)
+	assert.Equal(t, big.NewInt(5), events[0].Amount)
+	assert.Equal(t, "EventPaymentSentSuccess", events[1].EventName)
+
+	requests := server.Requests()
+	require.Len(t, requests, 2)
+	assert.Equal(t, "GET", requests[1].Method)
+
+	posted := server.RequestsTo("POST", path)
+	require.Len(t, posted, 1)
+
+	var payment struct {
+		Amount     json.Number `json:"amount"`
+		Identifier int64       `json:"identifier"`
+	}
+
+	require.NoError(t, posted[0].Decode(&payment))
+	assert.Equal(t, json.Number("40"), payment.Amount)
+	assert.Equal(t, int64(7), payment.Identifier)
+	assert.Equal(t, "application/json", posted[0].Header.Get("Content-Type"))
+}
@@ -0,0 +1,107 @@
+package raidentest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Scenario scripts the behaviour of the fake server. Intercept is called before every
+// request is served, with the time elapsed on the clock of the server since the
+// scenario was played, and returns true when the scenario answered the request
+// itself instead of the server.
+type Scenario interface {
+	Intercept(server *Server, elapsed time.Duration, writer http.ResponseWriter, request *http.Request) bool
+}
+
+// ScenarioFunc adapts a function to a Scenario.
+type ScenarioFunc func(server *Server, elapsed time.Duration, writer http.ResponseWriter, request *http.Request) bool
+
+// Intercept calls the function.
+func (fn ScenarioFunc) Intercept(server *Server, elapsed time.Duration, writer http.ResponseWriter, request *http.Request) bool {
+	return fn(server, elapsed, writer, request)
+}
+
+// PaymentFailsAfter makes every payment hang for the delay, as a payment without a
+// responsive route does, before failing with a conflict and recording a failed
+// payment event.
+func PaymentFailsAfter(delay time.Duration) Scenario {
+	return ScenarioFunc(func(server *Server, elapsed time.Duration, writer http.ResponseWriter, request *http.Request) bool {
+		parts := strings.Split(strings.Trim(request.URL.Path, "/"), "/")
+
+		if request.Method != "POST" || len(parts) != 5 || parts[2] != "payments" {
+			return false
+		}
+
+		payment := &payments.Payment{}
+		json.NewDecoder(request.Body).Decode(payment)
+
+		select {
+		case <-server.Clock.After(delay):
+		case <-request.Context().Done():
+			return true
+		}
+
+		server.mutex.Lock()
+
+		if payment.Identifier == 0 {
+			server.identifier++
+			payment.Identifier = server.identifier
+		}
+
+		payment.TokenAddress = common.HexToAddress(parts[3])
+		payment.TargetAddress = common.HexToAddress(parts[4])
+		server.failPayment(payment)
+
+		server.mutex.Unlock()
+
+		respondError(writer, http.StatusConflict, "Payment couldn't be completed because: the payment timed out")
+
+		return true
+	})
+}
+
+// ChannelStuck keeps the channel with the partner in the given state, e.g.
+// "waiting_for_settle", whatever the client asks for. The channel is reported in that
+// state and requests changing it fail with a conflict.
+func ChannelStuck(tokenAddress, partnerAddress common.Address, state string) Scenario {
+	return ScenarioFunc(func(server *Server, elapsed time.Duration, writer http.ResponseWriter, request *http.Request) bool {
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+
+		if channel := server.channel(tokenAddress, partnerAddress); channel != nil {
+			channel.State = state
+		}
+
+		parts := strings.Split(strings.Trim(request.URL.Path, "/"), "/")
+
+		if request.Method != "PATCH" || len(parts) != 5 || parts[2] != "channels" ||
+			common.HexToAddress(parts[3]) != tokenAddress || common.HexToAddress(parts[4]) != partnerAddress {
+			return false
+		}
+
+		respondError(writer, http.StatusConflict, "Channel is "+state+" and can not be changed")
+
+		return true
+	})
+}
+
+// NodeFlaps makes the node alternate between being ready for the ready duration and
+// unavailable for the unavailable duration, starting ready. An unavailable node
+// answers every request with a service unavailable status, as a node that lost its
+// connection to the chain does.
+func NodeFlaps(ready, unavailable time.Duration) Scenario {
+	return ScenarioFunc(func(server *Server, elapsed time.Duration, writer http.ResponseWriter, request *http.Request) bool {
+		if ready+unavailable <= 0 || elapsed%(ready+unavailable) < ready {
+			return false
+		}
+
+		respondError(writer, http.StatusServiceUnavailable, "The Raiden node is unavailable")
+
+		return true
+	})
+}
@@ -0,0 +1,120 @@
+package raidentest
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExamplePaymentFailsAfter() {
+	server := NewServer(common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"))
+	defer server.Close()
+
+	// every payment hangs for a minute before failing
+	server.Play(PaymentFailsAfter(time.Minute))
+}
+
+func TestPaymentFailsAfter(t *testing.T) {
+	var (
+		clock          = NewManualClock(time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC))
+		server, client = newTestServer()
+		responses      = make(chan *http.Response, 1)
+	)
+
+	defer server.Close()
+
+	server.Clock = clock
+	server.Play(PaymentFailsAfter(30 * time.Second))
+
+	go func() {
+		response, err := http.Post(server.URL+"/api/v1/payments/"+tokenAddress.Hex()+"/"+partnerAddress.Hex(), "application/json", strings.NewReader(`{"amount":10,"identifier":3}`))
+		if err != nil {
+			close(responses)
+			return
+		}
+
+		response.Body.Close()
+		responses <- response
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(29 * time.Second)
+
+	select {
+	case <-responses:
+		t.Fatal("payment failed before the delay")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	clock.Advance(time.Second)
+
+	response := <-responses
+	require.NotNil(t, response)
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+	events, err := client.Payments().List(context.Background(), tokenAddress, partnerAddress)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	assert.Equal(t, "EventPaymentSentFailed", events[0].EventName)
+	assert.Equal(t, int64(3), events[0].Identifier)
+	assert.Equal(t, big.NewInt(100), server.Channel(tokenAddress, partnerAddress).Balance)
+}
+
+func TestChannelStuck(t *testing.T) {
+	server, client := newTestServer()
+	defer server.Close()
+
+	server.Play(ChannelStuck(tokenAddress, partnerAddress, "waiting_for_settle"))
+
+	channelList, err := client.Channels().ListAll(context.Background())
+	require.NoError(t, err)
+	require.Len(t, channelList, 1)
+	assert.Equal(t, "waiting_for_settle", channelList[0].State)
+
+	request, err := http.NewRequest("PATCH", server.URL+"/api/v1/channels/"+tokenAddress.Hex()+"/"+partnerAddress.Hex(), strings.NewReader(`{"state":"settled"}`))
+	require.NoError(t, err)
+
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, http.StatusConflict, response.StatusCode)
+	assert.Equal(t, "waiting_for_settle", server.Channel(tokenAddress, partnerAddress).State)
+}
+
+func TestNodeFlaps(t *testing.T) {
+	var (
+		clock     = NewManualClock(time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC))
+		server, _ = newTestServer()
+		status    = func() int {
+			response, err := http.Get(server.URL + "/api/v1/address")
+			require.NoError(t, err)
+			response.Body.Close()
+
+			return response.StatusCode
+		}
+	)
+
+	defer server.Close()
+
+	server.Clock = clock
+	server.Play(NodeFlaps(10*time.Second, 5*time.Second))
+
+	assert.Equal(t, http.StatusOK, status())
+
+	clock.Advance(10 * time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, status())
+
+	clock.Advance(4 * time.Second)
+	assert.Equal(t, http.StatusServiceUnavailable, status())
+
+	clock.Advance(time.Second)
+	assert.Equal(t, http.StatusOK, status())
+}
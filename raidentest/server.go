@@ -0,0 +1,435 @@
+// Package raidentest provides a fake Raiden node served over HTTP, holding its
+// tokens, channels, connections, pending transfers and payments in memory, for
+// testing code built on the client without a real node or a mock of every endpoint.
+// The requests it received are recorded for tests to assert on. Scenarios script the failures of a real node, such as failing payments
+// or a node becoming unavailable, so that resilience paths can be tested
+// deterministically.
+package raidentest
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// DefaultSettleTimeout is the settle timeout of the channels opened without one.
+	DefaultSettleTimeout = 500
+	// DefaultRevealTimeout is the reveal timeout of the channels opened by the node.
+	DefaultRevealTimeout = 50
+)
+
+// NewServer starts a fake Raiden node with the given address, without tokens,
+// channels nor payments. It must be closed once the test is done.
+func NewServer(address common.Address) *Server {
+	server := NewUnstartedServer(address)
+	server.Start()
+
+	return server
+}
+
+// NewUnstartedServer creates a fake Raiden node like NewServer without starting it,
+// e.g. to serve its Handler on an address of choice rather than a random port.
+func NewUnstartedServer(address common.Address) *Server {
+	server := &Server{
+		Address:  address,
+		Clock:    RealClock(),
+		channels: make([]*channels.Channel, 0),
+		events:   make(map[paymentKey][]*payments.Event),
+		networks: make(map[common.Address]common.Address),
+		funds:    make(map[common.Address]*big.Int),
+		pending:  make([]*pendingtransfers.Transfer, 0),
+		requests: make([]*Request, 0),
+		playing:  make([]*playing, 0),
+	}
+
+	server.Server = httptest.NewUnstartedServer(http.HandlerFunc(server.serveHTTP))
+
+	return server
+}
+
+// Handler returns the handler serving the API of the fake node.
+func (server *Server) Handler() http.Handler {
+	return server.Server.Config.Handler
+}
+
+// Server is a fake Raiden node. Its Clock can be replaced, before any scenario is
+// played, to control time in tests.
+type Server struct {
+	*httptest.Server
+
+	Address common.Address
+	Clock   Clock
+
+	mutex      sync.Mutex
+	channels   []*channels.Channel
+	events     map[paymentKey][]*payments.Event
+	networks   map[common.Address]common.Address
+	funds      map[common.Address]*big.Int
+	pending    []*pendingtransfers.Transfer
+	requests   []*Request
+	playing    []*playing
+	identifier int64
+}
+
+type paymentKey struct {
+	token  common.Address
+	target common.Address
+}
+
+type playing struct {
+	scenario Scenario
+	started  time.Time
+}
+
+// Config returns the configuration of a client for the fake node.
+func (server *Server) Config() *config.Config {
+	return &config.Config{
+		Host:       server.URL,
+		APIVersion: "v1",
+	}
+}
+
+// AddChannel adds a channel of the node, it is served as given until changed by the
+// requests of the client.
+func (server *Server) AddChannel(channel *channels.Channel) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	stored := *channel
+	stored.Balance = new(big.Int).Set(amounts.OrZero(channel.Balance))
+	stored.TotalDeposit = new(big.Int).Set(amounts.OrZero(channel.TotalDeposit))
+	server.channels = append(server.channels, &stored)
+}
+
+// Channel returns a copy of the channel of the node with the partner, or nil when
+// there is none.
+func (server *Server) Channel(tokenAddress, partnerAddress common.Address) *channels.Channel {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	if channel := server.channel(tokenAddress, partnerAddress); channel != nil {
+		found := *channel
+		found.Balance = new(big.Int).Set(channel.Balance)
+		found.TotalDeposit = new(big.Int).Set(channel.TotalDeposit)
+		return &found
+	}
+
+	return nil
+}
+
+// AddPaymentEvent adds an event to the payment history of the node with the partner,
+// e.g. a payment received from it, served after the events already there.
+func (server *Server) AddPaymentEvent(tokenAddress, partnerAddress common.Address, event *payments.Event) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	key := paymentKey{tokenAddress, partnerAddress}
+	server.events[key] = append(server.events[key], event.Clone())
+}
+
+// Payments returns the payment events of the node with the target.
+func (server *Server) Payments(tokenAddress, targetAddress common.Address) []*payments.Event {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return append([]*payments.Event{}, server.events[paymentKey{tokenAddress, targetAddress}]...)
+}
+
+// Play starts the scenarios, they intercept every request from now on in the order
+// they were played.
+func (server *Server) Play(scenarios ...Scenario) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	for _, scenario := range scenarios {
+		server.playing = append(server.playing, &playing{scenario: scenario, started: server.Clock.Now()})
+	}
+}
+
+func (server *Server) serveHTTP(writer http.ResponseWriter, request *http.Request) {
+	var (
+		path  = strings.Trim(strings.TrimPrefix(request.URL.Path, "/api/v1"), "/")
+		parts = strings.Split(path, "/")
+	)
+
+	body, _ := ioutil.ReadAll(request.Body)
+	request.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	server.mutex.Lock()
+	server.requests = append(server.requests, &Request{Method: request.Method, Path: request.URL.Path, Query: request.URL.Query(), Header: copyHeader(request.Header), Body: body})
+	scenarios := append([]*playing{}, server.playing...)
+	server.mutex.Unlock()
+
+	for _, playing := range scenarios {
+		if playing.scenario.Intercept(server, server.Clock.Now().Sub(playing.started), writer, request) {
+			return
+		}
+	}
+
+	switch {
+	case request.Method == "GET" && path == "address":
+		respond(writer, http.StatusOK, map[string]string{"our_address": server.Address.Hex()})
+	case request.Method == "PUT" && path == "channels":
+		server.openChannel(writer, request)
+	case parts[0] == "channels":
+		server.serveChannels(writer, request, parts[1:])
+	case parts[0] == "tokens":
+		server.serveTokens(writer, request, parts[1:])
+	case parts[0] == "connections":
+		server.serveConnections(writer, request, parts[1:])
+	case request.Method == "GET" && parts[0] == "pending_transfers":
+		server.servePendingTransfers(writer, parts[1:])
+	case parts[0] == "payments" && len(parts) == 3:
+		server.servePayments(writer, request, common.HexToAddress(parts[1]), common.HexToAddress(parts[2]))
+	default:
+		respondError(writer, http.StatusNotFound, "The requested URL was not found on the server.")
+	}
+}
+
+// openChannel opens a channel in a registered token network, with its deposit at
+// once.
+func (server *Server) openChannel(writer http.ResponseWriter, request *http.Request) {
+	var open = &struct {
+		PartnerAddress common.Address `json:"partner_address"`
+		TokenAddress   common.Address `json:"token_address"`
+		TotalDeposit   json.Number    `json:"total_deposit"`
+		SettleTimeout  int64          `json:"settle_timeout"`
+	}{}
+
+	if err := json.NewDecoder(request.Body).Decode(open); err != nil {
+		respondError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	totalDeposit, err := amounts.Parse(open.TotalDeposit)
+	if err != nil {
+		respondError(writer, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	network, ok := server.networks[open.TokenAddress]
+	if !ok {
+		respondError(writer, http.StatusConflict, "Token network for token "+open.TokenAddress.Hex()+" does not exist")
+		return
+	}
+
+	if server.channel(open.TokenAddress, open.PartnerAddress) != nil {
+		respondError(writer, http.StatusConflict, "Channel with given partner address already exists")
+		return
+	}
+
+	if open.SettleTimeout == 0 {
+		open.SettleTimeout = DefaultSettleTimeout
+	}
+
+	channel := &channels.Channel{
+		TokenNetworkIdentifier: address.TokenNetworkAddress(network),
+		ChannelIdentifier:      int64(len(server.channels) + 1),
+		PartnerAddress:         open.PartnerAddress,
+		TokenAddress:           open.TokenAddress,
+		Balance:                totalDeposit,
+		TotalDeposit:           new(big.Int).Set(totalDeposit),
+		State:                  "opened",
+		SettleTimeout:          open.SettleTimeout,
+		RevealTimeout:          DefaultRevealTimeout,
+	}
+	server.channels = append(server.channels, channel)
+
+	respond(writer, http.StatusCreated, channelResponse(channel))
+}
+
+func (server *Server) serveChannels(writer http.ResponseWriter, request *http.Request, parts []string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	switch {
+	case request.Method == "GET" && len(parts) == 0:
+		respond(writer, http.StatusOK, channelResponses(server.channels, nil))
+	case request.Method == "GET" && len(parts) == 1:
+		tokenAddress := common.HexToAddress(parts[0])
+		respond(writer, http.StatusOK, channelResponses(server.channels, &tokenAddress))
+	case len(parts) == 2:
+		channel := server.channel(common.HexToAddress(parts[0]), common.HexToAddress(parts[1]))
+		if channel == nil {
+			respondError(writer, http.StatusNotFound, "Channel not found")
+			return
+		}
+
+		if request.Method == "PATCH" {
+			update := &struct {
+				State        string      `json:"state"`
+				TotalDeposit json.Number `json:"total_deposit"`
+			}{}
+
+			if err := json.NewDecoder(request.Body).Decode(update); err != nil {
+				respondError(writer, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			totalDeposit, err := amounts.Parse(update.TotalDeposit)
+			if err != nil {
+				respondError(writer, http.StatusBadRequest, err.Error())
+				return
+			}
+
+			if totalDeposit.Cmp(channel.TotalDeposit) > 0 {
+				channel.Balance.Add(channel.Balance, new(big.Int).Sub(totalDeposit, channel.TotalDeposit))
+				channel.TotalDeposit = totalDeposit
+			}
+
+			if update.State != "" {
+				channel.State = update.State
+			}
+		}
+
+		respond(writer, http.StatusOK, channelResponse(channel))
+	default:
+		respondError(writer, http.StatusMethodNotAllowed, "The method is not allowed for the requested URL.")
+	}
+}
+
+// servePayments pays directly over the channel with the target, payments without an
+// opened channel holding enough balance fail.
+func (server *Server) servePayments(writer http.ResponseWriter, request *http.Request, tokenAddress, targetAddress common.Address) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	key := paymentKey{tokenAddress, targetAddress}
+
+	switch request.Method {
+	case "GET":
+		events := make([]map[string]interface{}, 0, len(server.events[key]))
+
+		for _, event := range server.events[key] {
+			events = append(events, eventResponse(event))
+		}
+
+		respond(writer, http.StatusOK, events)
+	case "POST":
+		payment := &payments.Payment{}
+
+		if err := json.NewDecoder(request.Body).Decode(payment); err != nil {
+			respondError(writer, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if payment.Identifier == 0 {
+			server.identifier++
+			payment.Identifier = server.identifier
+		}
+
+		payment.InitiatorAddress = server.Address
+		payment.TargetAddress = targetAddress
+		payment.TokenAddress = tokenAddress
+
+		channel := server.channel(tokenAddress, targetAddress)
+		payment.Amount = amounts.OrZero(payment.Amount)
+
+		if channel == nil || channel.State != "opened" || channel.Balance.Cmp(payment.Amount) < 0 {
+			server.failPayment(payment)
+			respondError(writer, http.StatusConflict, "Payment couldn't be completed because: there is no route available")
+			return
+		}
+
+		channel.Balance.Sub(channel.Balance, payment.Amount)
+		server.events[key] = append(server.events[key], &payments.Event{
+			EventName:  "EventPaymentSentSuccess",
+			Amount:     payment.Amount,
+			Target:     targetAddress,
+			Identifier: payment.Identifier,
+			LogTime:    server.Clock.Now(),
+		})
+
+		respond(writer, http.StatusOK, payment)
+	default:
+		respondError(writer, http.StatusMethodNotAllowed, "The method is not allowed for the requested URL.")
+	}
+}
+
+// failPayment records the failure of the payment, the mutex must be held.
+func (server *Server) failPayment(payment *payments.Payment) {
+	key := paymentKey{payment.TokenAddress, payment.TargetAddress}
+
+	server.events[key] = append(server.events[key], &payments.Event{
+		EventName:  "EventPaymentSentFailed",
+		Target:     payment.TargetAddress,
+		Identifier: payment.Identifier,
+		LogTime:    server.Clock.Now(),
+	})
+}
+
+// channel returns the stored channel with the partner, the mutex must be held.
+func (server *Server) channel(tokenAddress, partnerAddress common.Address) *channels.Channel {
+	for _, channel := range server.channels {
+		if channel.TokenAddress == tokenAddress && channel.PartnerAddress == partnerAddress {
+			return channel
+		}
+	}
+
+	return nil
+}
+
+func channelResponses(channelList []*channels.Channel, tokenAddress *common.Address) []map[string]interface{} {
+	responses := make([]map[string]interface{}, 0, len(channelList))
+
+	for _, channel := range channelList {
+		if tokenAddress == nil || channel.TokenAddress == *tokenAddress {
+			responses = append(responses, channelResponse(channel))
+		}
+	}
+
+	return responses
+}
+
+func channelResponse(channel *channels.Channel) map[string]interface{} {
+	return map[string]interface{}{
+		"token_network_identifier": channel.TokenNetworkIdentifier.Hex(),
+		"channel_identifier":       channel.ChannelIdentifier,
+		"partner_address":          channel.PartnerAddress.Hex(),
+		"token_address":            channel.TokenAddress.Hex(),
+		"balance":                  channel.Balance.String(),
+		"total_deposit":            channel.TotalDeposit.String(),
+		"state":                    channel.State,
+		"settle_timeout":           channel.SettleTimeout,
+		"reveal_timeout":           channel.RevealTimeout,
+	}
+}
+
+func eventResponse(event *payments.Event) map[string]interface{} {
+	return map[string]interface{}{
+		"event":      event.EventName,
+		"amount":     amounts.OrZero(event.Amount).String(),
+		"initiator":  event.Initiator.Hex(),
+		"target":     event.Target.Hex(),
+		"identifier": event.Identifier,
+		"log_time":   event.LogTime.UTC().Format(time.RFC3339Nano),
+	}
+}
+
+func respond(writer http.ResponseWriter, statusCode int, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(statusCode)
+	json.NewEncoder(writer).Encode(body)
+}
+
+func respondError(writer http.ResponseWriter, statusCode int, message string) {
+	respond(writer, statusCode, map[string]string{"errors": message})
+}
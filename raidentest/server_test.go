@@ -0,0 +1,127 @@
+package raidentest
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	ourAddress     = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+	tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	partnerAddress = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+)
+
+func ExampleServer() {
+	var (
+		server = NewServer(common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226"))
+		client = raidenclient.NewClient(server.Config(), http.DefaultClient)
+	)
+
+	defer server.Close()
+
+	server.AddChannel(&channels.Channel{
+		ChannelIdentifier: 1,
+		TokenAddress:      common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+		PartnerAddress:    common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+		Balance:           big.NewInt(100),
+		TotalDeposit:      big.NewInt(100),
+		State:             "opened",
+	})
+
+	channelList, _ := client.Channels().ListAll(context.Background())
+
+	fmt.Println(len(channelList), "channels")
+}
+
+func newTestServer() (*Server, *raidenclient.Client) {
+	server := NewServer(ourAddress)
+
+	server.AddChannel(&channels.Channel{
+		ChannelIdentifier: 1,
+		TokenAddress:      tokenAddress,
+		PartnerAddress:    partnerAddress,
+		Balance:           big.NewInt(100),
+		TotalDeposit:      big.NewInt(100),
+		State:             "opened",
+		SettleTimeout:     500,
+	})
+
+	return server, raidenclient.NewClient(server.Config(), http.DefaultClient)
+}
+
+func TestServer(t *testing.T) {
+	var ctx = context.Background()
+
+	t.Run("address", func(t *testing.T) {
+		server, client := newTestServer()
+		defer server.Close()
+
+		address, err := client.Address().Get(ctx)
+
+		require.NoError(t, err)
+		assert.Equal(t, ourAddress, address)
+	})
+
+	t.Run("channels", func(t *testing.T) {
+		server, client := newTestServer()
+		defer server.Close()
+
+		channelList, err := client.Channels().ListToken(ctx, tokenAddress)
+		require.NoError(t, err)
+		require.Len(t, channelList, 1)
+		assert.Equal(t, partnerAddress, channelList[0].PartnerAddress)
+		assert.Equal(t, big.NewInt(100), channelList[0].Balance)
+
+		channel, err := client.Channels().Close(ctx, tokenAddress, partnerAddress)
+		require.NoError(t, err)
+		assert.Equal(t, "closed", channel.State)
+		assert.Equal(t, "closed", server.Channel(tokenAddress, partnerAddress).State)
+	})
+
+	t.Run("payments", func(t *testing.T) {
+		server, client := newTestServer()
+		defer server.Close()
+
+		payment, err := client.Payments().InitiateWithIdentifier(ctx, tokenAddress, partnerAddress, big.NewInt(40), 7)
+		require.NoError(t, err)
+		assert.Equal(t, int64(7), payment.Identifier)
+		assert.Equal(t, big.NewInt(60), server.Channel(tokenAddress, partnerAddress).Balance)
+
+		response, err := http.Post(server.URL+"/api/v1/payments/"+tokenAddress.Hex()+"/"+partnerAddress.Hex(), "application/json", strings.NewReader(`{"amount":100}`))
+		require.NoError(t, err)
+		response.Body.Close()
+		assert.Equal(t, http.StatusConflict, response.StatusCode)
+
+		events, err := client.Payments().List(ctx, tokenAddress, partnerAddress)
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+		assert.Equal(t, "EventPaymentSentSuccess", events[0].EventName)
+		assert.Equal(t, int64(7), events[0].Identifier)
+		assert.Equal(t, "EventPaymentSentFailed", events[1].EventName)
+	})
+}
+
+func TestUnstartedServer(t *testing.T) {
+	var (
+		server   = NewUnstartedServer(ourAddress)
+		recorder = httptest.NewRecorder()
+	)
+
+	defer server.Close()
+
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/api/v1/address", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"our_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226"}`, recorder.Body.String())
+}
@@ -0,0 +1,90 @@
+package raidentest
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// AddToken registers a token with the node, in the given token network, so that
+// channels can be opened and token networks joined in it.
+func (server *Server) AddToken(tokenAddress, tokenNetworkAddress common.Address) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	server.networks[tokenAddress] = tokenNetworkAddress
+}
+
+// Tokens returns the tokens registered with the node, sorted by address.
+func (server *Server) Tokens() []common.Address {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	return server.tokens()
+}
+
+// tokens returns the registered tokens sorted by address, the mutex must be held.
+func (server *Server) tokens() []common.Address {
+	var tokenList = make([]common.Address, 0, len(server.networks))
+
+	for tokenAddress := range server.networks {
+		tokenList = append(tokenList, tokenAddress)
+	}
+
+	sort.Slice(tokenList, func(i, j int) bool {
+		return tokenList[i].Hex() < tokenList[j].Hex()
+	})
+
+	return tokenList
+}
+
+func (server *Server) serveTokens(writer http.ResponseWriter, request *http.Request, parts []string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	switch {
+	case request.Method == "GET" && len(parts) == 0:
+		respond(writer, http.StatusOK, server.tokens())
+	case request.Method == "GET" && len(parts) == 1:
+		network, ok := server.networks[common.HexToAddress(parts[0])]
+		if !ok {
+			respondError(writer, http.StatusNotFound, "No token network registered for token "+parts[0])
+			return
+		}
+
+		respond(writer, http.StatusOK, network.Hex())
+	case request.Method == "PUT" && len(parts) == 1:
+		tokenAddress := common.HexToAddress(parts[0])
+
+		if _, ok := server.networks[tokenAddress]; ok {
+			respondError(writer, http.StatusConflict, "Token already registered")
+			return
+		}
+
+		// the network of a token is derived from it, to be the same on every run
+		network := common.BytesToAddress(crypto.Keccak256(tokenAddress.Bytes()))
+		server.networks[tokenAddress] = network
+
+		respond(writer, http.StatusCreated, map[string]string{"token_network_address": network.Hex()})
+	case request.Method == "GET" && len(parts) == 2 && parts[1] == "partners":
+		tokenAddress := common.HexToAddress(parts[0])
+		partners := make([]map[string]string, 0)
+
+		for _, channel := range server.channels {
+			if channel.TokenAddress != tokenAddress {
+				continue
+			}
+
+			partners = append(partners, map[string]string{
+				"partner_address": channel.PartnerAddress.Hex(),
+				"channel":         "/api/v1/channels/" + tokenAddress.Hex() + "/" + channel.PartnerAddress.Hex(),
+			})
+		}
+
+		respond(writer, http.StatusOK, partners)
+	default:
+		respondError(writer, http.StatusMethodNotAllowed, "The method is not allowed for the requested URL.")
+	}
+}
@@ -0,0 +1,65 @@
+package raidentest
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"testing"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServerTokens(t *testing.T) {
+	var (
+		ctx          = context.Background()
+		server       = NewServer(ourAddress)
+		client       = raidenclient.NewClient(server.Config(), http.DefaultClient)
+		networkToken = common.HexToAddress("0x0f114A1E9Db192502E7856309cc899952b3db1ED")
+		network      = common.HexToAddress("0xE5637F0103794C7e05469A9964E4563089a5E6f2")
+	)
+	defer server.Close()
+
+	server.AddToken(tokenAddress, network)
+
+	_, err := client.Channels().Open(ctx, networkToken, partnerAddress, big.NewInt(100), 0)
+	assert.Equal(t, http.StatusConflict, raidenerrors.StatusCode(err), "the token is not registered")
+
+	registered, err := client.Tokens().Register(ctx, networkToken)
+	require.NoError(t, err)
+
+	_, err = client.Tokens().Register(ctx, networkToken)
+	assert.Equal(t, http.StatusConflict, raidenerrors.StatusCode(err))
+
+	got, err := client.Tokens().Get(ctx, networkToken)
+	require.NoError(t, err)
+	assert.Equal(t, registered, got)
+
+	got, err = client.Tokens().Get(ctx, tokenAddress)
+	require.NoError(t, err)
+	assert.Equal(t, address.TokenNetworkAddress(network), got)
+
+	tokenList, err := client.Tokens().List(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, server.Tokens(), tokenList)
+	assert.ElementsMatch(t, []common.Address{tokenAddress, networkToken}, tokenList)
+
+	channel, err := client.Channels().Open(ctx, networkToken, partnerAddress, big.NewInt(100), 0)
+	require.NoError(t, err)
+	assert.Equal(t, "opened", channel.State)
+	assert.Equal(t, big.NewInt(100), channel.Balance)
+	assert.Equal(t, int64(DefaultSettleTimeout), channel.SettleTimeout)
+	assert.Equal(t, registered, channel.TokenNetworkIdentifier)
+
+	_, err = client.Channels().Open(ctx, networkToken, partnerAddress, big.NewInt(100), 0)
+	assert.Equal(t, http.StatusConflict, raidenerrors.StatusCode(err), "the channel already exists")
+
+	partners, err := client.Tokens().ListPartners(ctx, networkToken)
+	require.NoError(t, err)
+	require.Len(t, partners, 1)
+	assert.Equal(t, partnerAddress, partners[0].Address)
+}
@@ -0,0 +1,301 @@
+// Package recovery verifies a Raiden node restored from a backup: a snapshot of the
+// state of the node, its address, channels and pending transfers, is saved while the
+// node runs, e.g. along with every backup, and the restored node is compared with it,
+// so that operators know whether the restore lost channels, balances or payments in
+// flight before the node takes traffic again.
+package recovery
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The kinds of the problems of a restored node.
+const (
+	// ProblemAddressMismatch is a restored node with another address than the one of
+	// the snapshot, e.g. restored with the wrong keystore.
+	ProblemAddressMismatch = "address_mismatch"
+	// ProblemMissingChannel is a channel of the snapshot, not settled yet, the
+	// restored node does not list.
+	ProblemMissingChannel = "missing_channel"
+	// ProblemStateMismatch is a channel of the restored node whose state is not the
+	// one of the snapshot.
+	ProblemStateMismatch = "state_mismatch"
+	// ProblemBalanceMismatch is a channel of the restored node whose balance, total
+	// deposit or total withdraw, the Field of the problem, is not the one of the
+	// snapshot.
+	ProblemBalanceMismatch = "balance_mismatch"
+	// ProblemMissingTransfer is a pending transfer of the snapshot the restored node
+	// does not list, an obligation of a payment in flight the node may have lost
+	// unless it was unlocked since the node was restored.
+	ProblemMissingTransfer = "missing_transfer"
+)
+
+// Snapshot is the state of a Raiden node at a time, saved to verify the node once
+// restored from a backup of that time.
+type Snapshot struct {
+	TakenAt          time.Time                    `json:"taken_at"`
+	OurAddress       common.Address               `json:"our_address"`
+	Channels         []*channels.Channel          `json:"channels"`
+	PendingTransfers []*pendingtransfers.Transfer `json:"pending_transfers"`
+}
+
+// LoadSnapshot will read a snapshot saved as JSON in the file at path.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	var (
+		err      error
+		contents []byte
+		snapshot = &Snapshot{}
+	)
+
+	if contents, err = ioutil.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(contents, snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %s: %s", path, err.Error())
+	}
+
+	return snapshot, nil
+}
+
+// Save will write the snapshot as JSON to the file at path.
+func (snapshot *Snapshot) Save(path string) error {
+	contents, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, contents, 0600)
+}
+
+// Problem is a difference between a restored node and its snapshot, of a Kind, with
+// the value the snapshot Expected and the Actual one of the node, empty for what the
+// node lacks.
+type Problem struct {
+	Kind              string         `json:"kind"`
+	TokenAddress      common.Address `json:"token_address"`
+	PartnerAddress    common.Address `json:"partner_address"`
+	ChannelIdentifier int64          `json:"channel_identifier,omitempty"`
+	PaymentIdentifier int64          `json:"payment_identifier,omitempty"`
+	Field             string         `json:"field,omitempty"`
+	Expected          string         `json:"expected"`
+	Actual            string         `json:"actual"`
+}
+
+func (problem *Problem) String() string {
+	switch problem.Kind {
+	case ProblemAddressMismatch:
+		return fmt.Sprintf("node address is %s, expected %s", problem.Actual, problem.Expected)
+	case ProblemMissingTransfer:
+		return fmt.Sprintf("pending transfer of payment %d in channel %d of %s is missing", problem.PaymentIdentifier, problem.ChannelIdentifier, problem.TokenAddress.Hex())
+	case ProblemMissingChannel:
+		return fmt.Sprintf("channel %d with %s in %s is missing", problem.ChannelIdentifier, problem.PartnerAddress.Hex(), problem.TokenAddress.Hex())
+	}
+
+	return fmt.Sprintf("%s of channel %d with %s in %s is %s, expected %s", problem.Field, problem.ChannelIdentifier, problem.PartnerAddress.Hex(), problem.TokenAddress.Hex(), problem.Actual, problem.Expected)
+}
+
+// Report is the outcome of the verification of a restored node against a snapshot:
+// the channels of the snapshot Checked, the Problems found and the pending transfers
+// the node lists, Unsettled obligations to watch until they are unlocked or expire.
+type Report struct {
+	SnapshotTakenAt time.Time                    `json:"snapshot_taken_at"`
+	VerifiedAt      time.Time                    `json:"verified_at"`
+	Checked         int                          `json:"checked"`
+	Problems        []*Problem                   `json:"problems"`
+	Unsettled       []*pendingtransfers.Transfer `json:"unsettled"`
+}
+
+// OK tells whether the restored node has the state of the snapshot.
+func (report *Report) OK() bool {
+	return len(report.Problems) == 0
+}
+
+// Verifier is a generic interface to take snapshots of the state of a Raiden node and
+// to verify a node restored from a backup against them.
+type Verifier interface {
+	Snapshot(ctx context.Context) (*Snapshot, error)
+	Verify(ctx context.Context, snapshot *Snapshot) (*Report, error)
+}
+
+var _ Verifier = &defaultVerifier{}
+
+// NewVerifier creates a new default verifier given a Raiden node configuration and an
+// http client, timing the snapshots and reports on the clock of the configuration.
+func NewVerifier(config *config.Config, httpClient *http.Client) Verifier {
+	return &defaultVerifier{
+		addressGetter:  address.NewGetter(config, httpClient),
+		channelLister:  channels.NewLister(config, httpClient),
+		transferLister: pendingtransfers.NewLister(config, httpClient),
+		clock:          config.Time(),
+	}
+}
+
+type defaultVerifier struct {
+	addressGetter  address.Getter
+	channelLister  channels.Lister
+	transferLister pendingtransfers.Lister
+	clock          clock.Clock
+}
+
+// channelKey identifies a channel across snapshots, by its token, partner and
+// identifier, a channel opened again with a partner having another identifier.
+type channelKey struct {
+	tokenAddress      common.Address
+	partnerAddress    common.Address
+	channelIdentifier int64
+}
+
+// transferKey identifies a pending transfer by the channel it is locked in, the
+// identifier of its payment and the role of the node in it.
+type transferKey struct {
+	tokenAddress      common.Address
+	channelIdentifier int64
+	paymentIdentifier int64
+	role              string
+}
+
+// Snapshot will take a snapshot of the address, the channels and the pending
+// transfers of the node.
+func (verifier *defaultVerifier) Snapshot(ctx context.Context) (*Snapshot, error) {
+	var (
+		err      error
+		snapshot = &Snapshot{TakenAt: verifier.clock.Now()}
+	)
+
+	if snapshot.OurAddress, err = verifier.addressGetter.Get(ctx); err != nil {
+		return nil, err
+	}
+
+	if snapshot.Channels, err = verifier.channelLister.ListAll(ctx); err != nil {
+		return nil, err
+	}
+
+	if snapshot.PendingTransfers, err = verifier.transferLister.ListAll(ctx); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Verify will compare the node with the snapshot, reporting a node of another
+// address, the channels of the snapshot not settled yet the node lacks or has in
+// another state or with other balances, and the pending transfers of the snapshot the
+// node lacks. Channels opened and payments made since the snapshot are not problems,
+// but the payments made with the channels of the snapshot change their balances.
+func (verifier *defaultVerifier) Verify(ctx context.Context, snapshot *Snapshot) (*Report, error) {
+	var (
+		err         error
+		ourAddress  common.Address
+		channelList []*channels.Channel
+		report      = &Report{SnapshotTakenAt: snapshot.TakenAt, Problems: make([]*Problem, 0)}
+		current     = make(map[channelKey]*channels.Channel)
+		pending     = make(map[transferKey]bool)
+	)
+
+	if ourAddress, err = verifier.addressGetter.Get(ctx); err != nil {
+		return nil, err
+	}
+
+	if channelList, err = verifier.channelLister.ListAll(ctx); err != nil {
+		return nil, err
+	}
+
+	if report.Unsettled, err = verifier.transferLister.ListAll(ctx); err != nil {
+		return nil, err
+	}
+
+	report.VerifiedAt = verifier.clock.Now()
+
+	if ourAddress != snapshot.OurAddress {
+		report.Problems = append(report.Problems, &Problem{Kind: ProblemAddressMismatch, Expected: snapshot.OurAddress.Hex(), Actual: ourAddress.Hex()})
+	}
+
+	for _, channel := range channelList {
+		current[channelKey{channel.TokenAddress, channel.PartnerAddress, channel.ChannelIdentifier}] = channel
+	}
+
+	for _, transfer := range report.Unsettled {
+		pending[transferKey{transfer.TokenAddress, transfer.ChannelIdentifier, transfer.PaymentIdentifier, transfer.Role}] = true
+	}
+
+	for _, expected := range snapshot.Channels {
+		if expected.State == "settled" {
+			continue
+		}
+
+		report.Checked++
+		report.Problems = append(report.Problems, compareChannel(expected, current[channelKey{expected.TokenAddress, expected.PartnerAddress, expected.ChannelIdentifier}])...)
+	}
+
+	for _, transfer := range snapshot.PendingTransfers {
+		if !pending[transferKey{transfer.TokenAddress, transfer.ChannelIdentifier, transfer.PaymentIdentifier, transfer.Role}] {
+			report.Problems = append(report.Problems, &Problem{
+				Kind:              ProblemMissingTransfer,
+				TokenAddress:      transfer.TokenAddress,
+				ChannelIdentifier: transfer.ChannelIdentifier,
+				PaymentIdentifier: transfer.PaymentIdentifier,
+				Field:             "locked_amount",
+				Expected:          amounts.OrZero(transfer.LockedAmount).String(),
+			})
+		}
+	}
+
+	return report, nil
+}
+
+// compareChannel returns the problems of the channel of the node, nil when missing,
+// with respect to the channel of the snapshot.
+func compareChannel(expected, actual *channels.Channel) []*Problem {
+	var (
+		problems = make([]*Problem, 0)
+		problem  = func(kind, field, expectedValue, actualValue string) *Problem {
+			return &Problem{
+				Kind:              kind,
+				TokenAddress:      expected.TokenAddress,
+				PartnerAddress:    expected.PartnerAddress,
+				ChannelIdentifier: expected.ChannelIdentifier,
+				Field:             field,
+				Expected:          expectedValue,
+				Actual:            actualValue,
+			}
+		}
+	)
+
+	if actual == nil {
+		return append(problems, problem(ProblemMissingChannel, "", expected.State, ""))
+	}
+
+	if actual.State != expected.State {
+		problems = append(problems, problem(ProblemStateMismatch, "state", expected.State, actual.State))
+	}
+
+	for _, field := range []struct {
+		name             string
+		expected, actual *big.Int
+	}{
+		{"balance", expected.Balance, actual.Balance},
+		{"total_deposit", expected.TotalDeposit, actual.TotalDeposit},
+		{"total_withdraw", expected.TotalWithdraw, actual.TotalWithdraw},
+	} {
+		if !amounts.Equal(field.expected, field.actual) {
+			problems = append(problems, problem(ProblemBalanceMismatch, field.name, amounts.OrZero(field.expected).String(), amounts.OrZero(field.actual).String()))
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,152 @@
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tokenHex   = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+	ourHex     = "0x2a65Aca4D5fC5B5C859090a6c34d164135398226"
+	partnerHex = "0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+	otherHex   = "0x2c4b0Bdac486d492E3cD701F4cA87e480AE4C685"
+)
+
+func ExampleVerifier() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		verifier = NewVerifier(config, http.DefaultClient)
+	)
+
+	// before the backup of the node
+	snapshot, err := verifier.Snapshot(context.Background())
+	if err != nil {
+		panic(fmt.Sprintf("unable to take snapshot: %s", err.Error()))
+	}
+
+	// once the node is restored from the backup
+	report, err := verifier.Verify(context.Background(), snapshot)
+	if err != nil {
+		panic(fmt.Sprintf("unable to verify the restored node: %s", err.Error()))
+	}
+
+	for _, problem := range report.Problems {
+		fmt.Println(problem)
+	}
+}
+
+func TestVerifier(t *testing.T) {
+	var (
+		manual = clock.NewManual(time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC))
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+			Clock:      manual,
+		}
+		verifier     = NewVerifier(config, http.DefaultClient)
+		ctx          = context.Background()
+		channelJSON  = `{"channel_identifier":%d,"token_address":"` + tokenHex + `","partner_address":"%s","balance":%d,"total_deposit":%d,"total_withdraw":0,"state":"%s","settle_timeout":500,"reveal_timeout":50}`
+		transferJSON = `{"channel_identifier":%d,"initiator":"` + ourHex + `","locked_amount":%d,"payment_identifier":%d,"role":"initiator","target":"` + partnerHex + `","token_address":"` + tokenHex + `","token_network_identifier":"0x111157460c0F41EfD9107239B7864c062aA8B978","transferred_amount":0}`
+	)
+
+	dir, err := ioutil.TempDir("", "recovery")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"`+ourHex+`"}`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, "["+
+		fmt.Sprintf(channelJSON, 1, partnerHex, 40, 100, "opened")+","+
+		fmt.Sprintf(channelJSON, 2, otherHex, 10, 10, "closed")+","+
+		fmt.Sprintf(channelJSON, 3, otherHex, 0, 5, "settled")+"]"))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", httpmock.NewStringResponder(http.StatusOK, "["+
+		fmt.Sprintf(transferJSON, 1, 12, 7)+","+
+		fmt.Sprintf(transferJSON, 1, 3, 8)+"]"))
+
+	snapshot, err := verifier.Snapshot(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, manual.Now(), snapshot.TakenAt)
+	assert.Len(t, snapshot.Channels, 3)
+	assert.Len(t, snapshot.PendingTransfers, 2)
+
+	path := filepath.Join(dir, "snapshot.json")
+	require.NoError(t, snapshot.Save(path))
+
+	snapshot, err = LoadSnapshot(path)
+	require.NoError(t, err)
+
+	t.Run("restored", func(t *testing.T) {
+		report, err := verifier.Verify(ctx, snapshot)
+		require.NoError(t, err)
+		assert.True(t, report.OK())
+		assert.Equal(t, 2, report.Checked, "settled channels are not checked")
+		assert.Len(t, report.Unsettled, 2)
+	})
+
+	t.Run("stale restore", func(t *testing.T) {
+		manual.Advance(time.Hour)
+
+		httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK, "["+
+			fmt.Sprintf(channelJSON, 1, partnerHex, 55, 80, "closed")+","+
+			fmt.Sprintf(channelJSON, 4, partnerHex, 5, 5, "opened")+"]"))
+		httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/pending_transfers", httpmock.NewStringResponder(http.StatusOK, "["+fmt.Sprintf(transferJSON, 1, 12, 7)+"]"))
+
+		report, err := verifier.Verify(ctx, snapshot)
+		require.NoError(t, err)
+		assert.False(t, report.OK())
+		assert.Equal(t, manual.Now(), report.VerifiedAt)
+		assert.Equal(t, snapshot.TakenAt, report.SnapshotTakenAt)
+
+		problems := make([]string, 0, len(report.Problems))
+		for _, problem := range report.Problems {
+			problems = append(problems, problem.String())
+		}
+
+		// the channel opened since the snapshot is not a problem
+		assert.Equal(t, []string{
+			"state of channel 1 with " + partnerHex + " in " + tokenHex + " is closed, expected opened",
+			"balance of channel 1 with " + partnerHex + " in " + tokenHex + " is 55, expected 40",
+			"total_deposit of channel 1 with " + partnerHex + " in " + tokenHex + " is 80, expected 100",
+			"channel 2 with " + otherHex + " in " + tokenHex + " is missing",
+			"pending transfer of payment 8 in channel 1 of " + tokenHex + " is missing",
+		}, problems)
+		assert.Equal(t, ProblemMissingTransfer, report.Problems[4].Kind)
+		assert.Equal(t, "3", report.Problems[4].Expected)
+	})
+
+	t.Run("other node", func(t *testing.T) {
+		httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusOK, `{"our_address":"`+otherHex+`"}`))
+
+		report, err := verifier.Verify(ctx, snapshot)
+		require.NoError(t, err)
+		require.NotEmpty(t, report.Problems)
+		assert.Equal(t, &Problem{Kind: ProblemAddressMismatch, Expected: ourHex, Actual: otherHex}, report.Problems[0])
+		assert.Equal(t, common.Address{}, report.Problems[0].TokenAddress)
+	})
+
+	t.Run("invalid snapshot", func(t *testing.T) {
+		invalid := filepath.Join(dir, "invalid.json")
+		require.NoError(t, ioutil.WriteFile(invalid, []byte(`{"channels":`), 0600))
+
+		_, err := LoadSnapshot(invalid)
+		assert.EqualError(t, err, "invalid snapshot "+invalid+": unexpected end of JSON input")
+	})
+}
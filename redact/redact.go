@@ -0,0 +1,132 @@
+// Package redact removes the secrets of the requests to Raiden nodes, e.g. the secrets
+// of hash time locked payments, the credentials of authenticating proxies and API
+// keys, from the text logged, recorded or returned in errors by the client, so that
+// logs, recordings and support bundles can be shared.
+package redact
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted is the value secrets are replaced with.
+const Redacted = "REDACTED"
+
+var (
+	// fields are the JSON fields and query parameters holding secrets, matched in
+	// lower case. The secret hashes of payments are not secrets.
+	fields = []string{"secret", "password", "token", "api_key", "apikey", "access_token", "private_key", "authorization"}
+	// headers are the request and response headers holding credentials.
+	headers = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token"}
+
+	// urlCredentials matches the user info of a URL, e.g. the basic auth credentials
+	// of a node behind a reverse proxy.
+	urlCredentials = regexp.MustCompile(`(https?://)[^/@\s]+@`)
+	// urlQuery matches the query of a URL, which may hold API keys.
+	urlQuery = regexp.MustCompile(`(https?://[^?\s"]*)\?[^\s"]*`)
+	// jsonFields matches the fields holding secrets of JSON documents, with a string
+	// or a scalar value, objects being left to the fields they hold.
+	jsonFields = regexp.MustCompile(`(?i)("(?:` + strings.Join(fields, "|") + `)"\s*:\s*)(?:"(?:[^"\\]|\\.)*"|[\w.+-]+)`)
+	// headerLines matches the headers holding credentials of dumped requests, e.g.
+	// "Authorization: Bearer ...", up to the end of the line.
+	headerLines = regexp.MustCompile(`(?im)^(\s*(?:` + strings.Join(headers, "|") + `):[ \t]*)[^\r\n]+`)
+)
+
+// String returns the text with the secrets it holds redacted: the user info and query
+// of URLs, the fields holding secrets of JSON documents and the headers holding
+// credentials of dumped requests, e.g. the message of an error or a line of a log.
+func String(text string) string {
+	text = urlCredentials.ReplaceAllString(text, "${1}"+Redacted+"@")
+	text = urlQuery.ReplaceAllString(text, "${1}?"+Redacted)
+	text = jsonFields.ReplaceAllString(text, `${1}"`+Redacted+`"`)
+
+	return headerLines.ReplaceAllString(text, "${1}"+Redacted)
+}
+
+// JSON returns the body with the values of its fields holding secrets redacted, e.g.
+// the secret of a payment, leaving the rest of the body as it is.
+func JSON(body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+
+	return jsonFields.ReplaceAll(body, []byte(`${1}"`+Redacted+`"`))
+}
+
+// URL returns the URL with its user info and the values of its query parameters
+// holding secrets redacted, keeping the other parameters, e.g. so that recorded
+// requests can still be told apart.
+func URL(requestURL *url.URL) string {
+	if requestURL == nil {
+		return ""
+	}
+
+	var redacted = *requestURL
+
+	if redacted.User != nil {
+		redacted.User = url.User(Redacted)
+	}
+
+	if redacted.RawQuery != "" {
+		query := redacted.Query()
+
+		for name, values := range query {
+			if Field(name) {
+				for i := range values {
+					values[i] = Redacted
+				}
+			}
+		}
+
+		redacted.RawQuery = query.Encode()
+	}
+
+	return redacted.String()
+}
+
+// Header returns a copy of the header with the values of the headers holding
+// credentials redacted.
+func Header(header http.Header) http.Header {
+	if header == nil {
+		return nil
+	}
+
+	var redacted = make(http.Header, len(header))
+
+	for name, values := range header {
+		values = append([]string(nil), values...)
+
+		if headerName(name) {
+			for i := range values {
+				values[i] = Redacted
+			}
+		}
+
+		redacted[name] = values
+	}
+
+	return redacted
+}
+
+// Field tells whether the JSON field or query parameter holds a secret.
+func Field(name string) bool {
+	for _, field := range fields {
+		if strings.EqualFold(name, field) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func headerName(name string) bool {
+	for _, header := range headers {
+		if strings.EqualFold(name, header) {
+			return true
+		}
+	}
+
+	return false
+}
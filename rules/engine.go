@@ -0,0 +1,441 @@
+// Package rules reacts to the activity of a Raiden node without custom code: rules
+// declared in a configuration file fire on payment events or on channels running low,
+// and call a webhook, make a payment, deposit into or close the channel they fired
+// for. Every action run is given to an audit function.
+package rules
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/redact"
+	"github.com/cpurta/go-raiden-client/webhook"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultCheckInterval is the time between two checks of the balances of every
+// channel while running, when the options give no interval.
+const DefaultCheckInterval = time.Minute
+
+// Options configures an engine: Audit is called with every action run, and the
+// balances of every channel are checked every CheckInterval while running, besides
+// the channel of every payment event. The checks and the cooldowns of the rules are
+// timed by Clock, the system time when nil.
+type Options struct {
+	Audit         func(record *AuditRecord)
+	CheckInterval time.Duration
+	Clock         clock.Clock
+}
+
+// Firing is a rule that fired for the channel of the token with the partner, on the
+// payment Event or for the Channel running low.
+type Firing struct {
+	Rule           *Rule
+	TokenAddress   common.Address
+	PartnerAddress common.Address
+	Event          *events.PaymentEvent
+	Channel        *channels.Channel
+}
+
+// redacted returns the firing with the secret of the action of its rule redacted,
+// copying the rule so that the engine keeps signing webhooks with the secret.
+func (firing *Firing) redacted() *Firing {
+	if firing.Rule == nil || firing.Rule.Action == nil || firing.Rule.Action.Secret == "" {
+		return firing
+	}
+
+	var (
+		copied = *firing
+		rule   = *firing.Rule
+		action = *firing.Rule.Action
+	)
+
+	action.Secret = redact.Redacted
+	rule.Action = &action
+	copied.Rule = &rule
+
+	return &copied
+}
+
+// AuditRecord is an action run by a rule that fired, with the channel or payment it
+// resulted in, or the error it failed with. The secret of the webhook of the rule is
+// redacted, see redact.Redacted.
+type AuditRecord struct {
+	*Firing
+	Action   string
+	Result   *channels.Channel
+	Payment  *payments.Payment
+	Err      error
+	Started  time.Time
+	Finished time.Time
+	// Fields are the fields of the context of the engine, see meta.WithFields, with
+	// the Tenant of the configuration when the context has none.
+	Fields meta.Fields
+}
+
+// Payload is the JSON body of the requests of the webhook actions, with the addresses
+// as checksummed hex strings.
+type Payload struct {
+	Rule           string            `json:"rule"`
+	TokenAddress   string            `json:"token_address"`
+	PartnerAddress string            `json:"partner_address"`
+	Event          *payments.Event   `json:"event,omitempty"`
+	Channel        *channels.Channel `json:"channel,omitempty"`
+}
+
+// Engine runs the actions of the rules whose triggers fire.
+type Engine struct {
+	rules         []*Rule
+	options       *Options
+	tenant        string
+	httpClient    *http.Client
+	channelLister channels.Lister
+	initiator     payments.Initiator
+	depositor     channels.IncreaseDepositor
+	closer        channels.Closer
+
+	mutex sync.Mutex
+	fired map[firingKey]time.Time
+	clock clock.Clock
+	now   func() time.Time
+}
+
+type firingKey struct {
+	rule           string
+	tokenAddress   common.Address
+	partnerAddress common.Address
+}
+
+// NewEngine creates an engine of the rules, see LoadRules, acting on the Raiden node
+// of the configuration. The webhooks are called with the http client as well.
+func NewEngine(config *config.Config, rules []*Rule, options *Options, httpClient *http.Client) *Engine {
+	var engine = &Engine{
+		rules:         rules,
+		options:       options,
+		httpClient:    httpClient,
+		channelLister: channels.NewLister(config, httpClient),
+		initiator:     payments.NewInitiator(config, httpClient),
+		depositor:     channels.NewIncreaseDepositor(config, httpClient),
+		closer:        channels.NewCloser(config, httpClient),
+		fired:         make(map[firingKey]time.Time),
+	}
+
+	if engine.options == nil {
+		engine.options = &Options{}
+	}
+
+	engine.clock = clock.Or(engine.options.Clock)
+	engine.now = engine.clock.Now
+
+	if engine.httpClient == nil {
+		engine.httpClient = http.DefaultClient
+	}
+
+	if config != nil {
+		engine.tenant = config.Tenant
+	}
+
+	return engine
+}
+
+// HandleEvent will run the rules firing on the payment event, then the rules firing
+// for the balance of its channel, returning the first error of their actions.
+func (engine *Engine) HandleEvent(ctx context.Context, event *events.PaymentEvent) error {
+	var firings = make([]*Firing, 0)
+
+	for _, rule := range engine.rules {
+		if rule.Trigger.Type == TriggerPayment && paymentMatches(rule.Trigger, event) {
+			firings = append(firings, &Firing{Rule: rule, TokenAddress: event.TokenAddress, PartnerAddress: event.PartnerAddress, Event: event})
+		}
+	}
+
+	firstErr := engine.fire(ctx, firings)
+
+	channelList, err := engine.channelLister.ListToken(ctx, event.TokenAddress)
+	if err != nil {
+		return firstOf(firstErr, err)
+	}
+
+	for _, channel := range channelList {
+		if channel.PartnerAddress == event.PartnerAddress {
+			firstErr = firstOf(firstErr, engine.checkBalances(ctx, channel))
+		}
+	}
+
+	return firstErr
+}
+
+// Check will run the rules firing for the balances of the opened channels of the node,
+// returning the first error of their actions.
+func (engine *Engine) Check(ctx context.Context) error {
+	var firstErr error
+
+	channelList, err := engine.channelLister.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channelList {
+		firstErr = firstOf(firstErr, engine.checkBalances(ctx, channel))
+	}
+
+	return firstErr
+}
+
+// Run will check the balances of every channel, then handle the events of the
+// subscription and check the balances every CheckInterval until the subscription
+// ends, returning the errors of the actions on the returned channel, which is closed
+// when the subscription ends. Errors are dropped when nobody is receiving them.
+func (engine *Engine) Run(ctx context.Context, subscription *events.Subscription) <-chan error {
+	var (
+		errs     = make(chan error, 1)
+		interval = engine.options.CheckInterval
+	)
+
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+
+	deliver := func(err error) {
+		if err != nil {
+			select {
+			case errs <- err:
+			default:
+			}
+		}
+	}
+
+	go func() {
+		var tick = engine.clock.After(interval)
+
+		defer close(errs)
+
+		deliver(engine.Check(ctx))
+
+		for {
+			select {
+			case event, ok := <-subscription.Events:
+				if !ok {
+					return
+				}
+
+				deliver(engine.HandleEvent(ctx, event))
+			case <-tick:
+				tick = engine.clock.After(interval)
+				deliver(engine.Check(ctx))
+			}
+		}
+	}()
+
+	return errs
+}
+
+// checkBalances runs the rules firing for the balance of the channel.
+func (engine *Engine) checkBalances(ctx context.Context, channel *channels.Channel) error {
+	var firings = make([]*Firing, 0)
+
+	if channel.State != "opened" {
+		return nil
+	}
+
+	for _, rule := range engine.rules {
+		if rule.Trigger.Type == TriggerChannelBalance && balanceMatches(rule.Trigger, channel) {
+			firings = append(firings, &Firing{Rule: rule, TokenAddress: channel.TokenAddress, PartnerAddress: channel.PartnerAddress, Channel: channel})
+		}
+	}
+
+	return engine.fire(ctx, firings)
+}
+
+// fire runs the actions of the firings whose rule is not cooling down for their
+// channel, returning the first error.
+func (engine *Engine) fire(ctx context.Context, firings []*Firing) error {
+	var firstErr error
+
+	for _, firing := range firings {
+		if !engine.claim(firing) {
+			continue
+		}
+
+		record := &AuditRecord{
+			Firing:  firing,
+			Action:  firing.Rule.Action.Type,
+			Started: engine.now(),
+			Fields:  meta.TenantFields(ctx, engine.tenant),
+		}
+
+		record.Err = engine.act(ctx, record)
+		record.Finished = engine.now()
+		record.Firing = firing.redacted()
+
+		if engine.options.Audit != nil {
+			engine.options.Audit(record)
+		}
+
+		if record.Err != nil {
+			firstErr = firstOf(firstErr, fmt.Errorf("rule %s: %s", firing.Rule.Name, record.Err.Error()))
+		}
+	}
+
+	return firstErr
+}
+
+// claim tells whether the rule of the firing may fire for its channel, recording it
+// as fired when it may. A rule that failed to act is not fired again before its
+// cooldown either, so that a failing action is not retried on every event.
+func (engine *Engine) claim(firing *Firing) bool {
+	var (
+		key         = firingKey{firing.Rule.Name, firing.TokenAddress, firing.PartnerAddress}
+		cooldown, _ = firing.Rule.cooldown()
+		now         = engine.now()
+	)
+
+	engine.mutex.Lock()
+	defer engine.mutex.Unlock()
+
+	if last, ok := engine.fired[key]; ok && now.Sub(last) < cooldown {
+		return false
+	}
+
+	engine.fired[key] = now
+
+	return true
+}
+
+// act runs the action of the firing of the record, setting its result.
+func (engine *Engine) act(ctx context.Context, record *AuditRecord) error {
+	var (
+		err    error
+		action = record.Rule.Action
+	)
+
+	switch action.Type {
+	case ActionWebhook:
+		return engine.post(ctx, record.Firing)
+	case ActionPayment:
+		record.Payment, err = engine.initiator.Initiate(ctx, record.TokenAddress, *action.Target, action.Amount)
+	case ActionDeposit:
+		var channel = record.Channel
+
+		if channel == nil {
+			if channel, err = engine.channel(ctx, record.TokenAddress, record.PartnerAddress); err != nil {
+				return err
+			}
+		}
+
+		totalDeposit := new(big.Int).Add(amounts.OrZero(channel.TotalDeposit), action.Amount)
+
+		record.Result, err = engine.depositor.IncreaseDeposit(ctx, record.TokenAddress, record.PartnerAddress, totalDeposit)
+	case ActionClose:
+		record.Result, err = engine.closer.Close(ctx, record.TokenAddress, record.PartnerAddress)
+	}
+
+	return err
+}
+
+// channel returns the channel of the token with the partner the node lists.
+func (engine *Engine) channel(ctx context.Context, tokenAddress, partnerAddress common.Address) (*channels.Channel, error) {
+	channelList, err := engine.channelLister.ListToken(ctx, tokenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, channel := range channelList {
+		if channel.PartnerAddress == partnerAddress && channel.State == "opened" {
+			return channel, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no open channel of token %s with %s", tokenAddress.Hex(), partnerAddress.Hex())
+}
+
+// post posts the firing to the URL of the webhook of its rule, signed with its secret.
+func (engine *Engine) post(ctx context.Context, firing *Firing) error {
+	var (
+		err     error
+		body    []byte
+		request *http.Request
+		action  = firing.Rule.Action
+		payload = &Payload{
+			Rule:           firing.Rule.Name,
+			TokenAddress:   firing.TokenAddress.Hex(),
+			PartnerAddress: firing.PartnerAddress.Hex(),
+			Channel:        firing.Channel,
+		}
+	)
+
+	if firing.Event != nil {
+		payload.Event = firing.Event.Event
+	}
+
+	if body, err = json.Marshal(payload); err != nil {
+		return err
+	}
+
+	if request, err = http.NewRequest("POST", action.URL, bytes.NewReader(body)); err != nil {
+		return err
+	}
+
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(webhook.SignatureHeader, webhook.Sign([]byte(action.Secret), engine.now(), body))
+
+	response, err := engine.httpClient.Do(request)
+	if err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("received %d status code from webhook %s", response.StatusCode, action.URL)
+	}
+
+	return nil
+}
+
+func paymentMatches(trigger *Trigger, event *events.PaymentEvent) bool {
+	switch {
+	case event.Event == nil:
+		return false
+	case !channelMatches(trigger, event.TokenAddress, event.PartnerAddress):
+		return false
+	case trigger.Event != "" && trigger.Event != event.EventName:
+		return false
+	case trigger.MinAmount != nil && amounts.OrZero(event.Amount).Cmp(trigger.MinAmount) < 0:
+		return false
+	}
+
+	return true
+}
+
+func balanceMatches(trigger *Trigger, channel *channels.Channel) bool {
+	return channelMatches(trigger, channel.TokenAddress, channel.PartnerAddress) && amounts.OrZero(channel.Balance).Cmp(trigger.Below) < 0
+}
+
+func channelMatches(trigger *Trigger, tokenAddress, partnerAddress common.Address) bool {
+	return (trigger.Token == nil || *trigger.Token == tokenAddress) && (trigger.Partner == nil || *trigger.Partner == partnerAddress)
+}
+
+func firstOf(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,335 @@
+package rules
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/redact"
+	"github.com/cpurta/go-raiden-client/webhook"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleEngine() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		options = &Options{
+			Audit: func(record *AuditRecord) {
+				fmt.Printf("rule %s ran %s action: %v\n", record.Rule.Name, record.Action, record.Err)
+			},
+		}
+	)
+
+	rules, err := LoadRules("rules.json")
+	if err != nil {
+		panic(fmt.Sprintf("unable to load rules: %s", err.Error()))
+	}
+
+	engine := NewEngine(config, rules, options, http.DefaultClient)
+	subscription := events.NewSubscriber(config, http.DefaultClient).SubscribePayments(context.Background(), nil)
+
+	for err := range engine.Run(context.Background(), subscription) {
+		fmt.Printf("rules: %s\n", err.Error())
+	}
+}
+
+const (
+	tokenHex   = "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+	partnerHex = "0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+	listURL    = "http://localhost:5001/api/v1/channels"
+	tokenURL   = "http://localhost:5001/api/v1/channels/" + tokenHex
+	channelURL = tokenURL + "/" + partnerHex
+	hookURL    = "https://example.com/hook"
+)
+
+func channelJSON(balance, totalDeposit int64) string {
+	return fmt.Sprintf(`{"channel_identifier":20,"partner_address":"%s","token_address":"%s","balance":%d,"total_deposit":%d,"state":"opened"}`, partnerHex, tokenHex, balance, totalDeposit)
+}
+
+func TestEngineCheck(t *testing.T) {
+	var config = &config.Config{
+		Host:       "http://localhost:5001",
+		APIVersion: "v1",
+	}
+
+	type testcase struct {
+		name             string
+		rule             *Rule
+		balance          int64
+		expectedDeposit  string
+		expectedActions  int
+		lowChannelClosed bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:            "deposit into a channel running low",
+			rule:            &Rule{Name: "top up", Trigger: &Trigger{Type: TriggerChannelBalance, Below: big.NewInt(10)}, Action: &Action{Type: ActionDeposit, Amount: big.NewInt(50)}},
+			balance:         5,
+			expectedDeposit: `{"total_deposit":80}`,
+			expectedActions: 1,
+		},
+		testcase{
+			name:            "channel with enough balance",
+			rule:            &Rule{Name: "top up", Trigger: &Trigger{Type: TriggerChannelBalance, Below: big.NewInt(10)}, Action: &Action{Type: ActionDeposit, Amount: big.NewInt(50)}},
+			balance:         10,
+			expectedActions: 0,
+		},
+		testcase{
+			name:             "close a channel running low",
+			rule:             &Rule{Name: "close", Trigger: &Trigger{Type: TriggerChannelBalance, Below: big.NewInt(10)}, Action: &Action{Type: ActionClose}},
+			balance:          5,
+			expectedDeposit:  `{"state":"closed"}`,
+			expectedActions:  1,
+			lowChannelClosed: true,
+		},
+		testcase{
+			name:            "rule of another token",
+			rule:            &Rule{Name: "top up", Trigger: &Trigger{Type: TriggerChannelBalance, Token: &common.Address{}, Below: big.NewInt(10)}, Action: &Action{Type: ActionDeposit, Amount: big.NewInt(50)}},
+			balance:         5,
+			expectedActions: 0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				bodies  = make([]string, 0)
+				records = make([]*AuditRecord, 0)
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "["+channelJSON(tc.balance, 30)+"]"))
+			httpmock.RegisterResponder("PATCH", channelURL, func(request *http.Request) (*http.Response, error) {
+				body, _ := ioutil.ReadAll(request.Body)
+				bodies = append(bodies, string(body))
+
+				return httpmock.NewStringResponse(http.StatusOK, channelJSON(tc.balance+50, 80)), nil
+			})
+
+			engine := NewEngine(config, []*Rule{tc.rule}, &Options{Audit: func(record *AuditRecord) { records = append(records, record) }}, http.DefaultClient)
+
+			require.NoError(t, engine.Check(context.Background()))
+			// the rule is cooling down
+			require.NoError(t, engine.Check(context.Background()))
+
+			require.Len(t, records, tc.expectedActions)
+			require.Len(t, bodies, tc.expectedActions)
+
+			if tc.expectedActions > 0 {
+				assert.JSONEq(t, tc.expectedDeposit, bodies[0])
+				assert.Equal(t, tc.rule.Action.Type, records[0].Action)
+				assert.Equal(t, common.HexToAddress(partnerHex), records[0].PartnerAddress)
+				assert.NotNil(t, records[0].Channel)
+				assert.NotNil(t, records[0].Result)
+				assert.NoError(t, records[0].Err)
+			}
+		})
+	}
+}
+
+func TestEngineHandleEvent(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+			Tenant:     "acme",
+		}
+		tokenAddress   = common.HexToAddress(tokenHex)
+		partnerAddress = common.HexToAddress(partnerHex)
+		target         = common.HexToAddress("0x2c4b0Bdac486d492E3cD701F4cA87e480AE4C685")
+		secret         = []byte("s3cr3t")
+	)
+
+	type testcase struct {
+		name            string
+		rule            *Rule
+		event           *payments.Event
+		hookStatus      int
+		expectedActions int
+		expectedErr     string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:            "webhook on a payment received",
+			rule:            &Rule{Name: "notify", Trigger: &Trigger{Type: TriggerPayment, Event: payments.EventPaymentReceivedSuccess, MinAmount: big.NewInt(100)}, Action: &Action{Type: ActionWebhook, URL: hookURL, Secret: string(secret)}},
+			event:           &payments.Event{EventName: payments.EventPaymentReceivedSuccess, Amount: big.NewInt(150), Identifier: 7},
+			hookStatus:      http.StatusOK,
+			expectedActions: 1,
+		},
+		testcase{
+			name:            "payment below the minimum",
+			rule:            &Rule{Name: "notify", Trigger: &Trigger{Type: TriggerPayment, MinAmount: big.NewInt(100)}, Action: &Action{Type: ActionWebhook, URL: hookURL, Secret: string(secret)}},
+			event:           &payments.Event{EventName: payments.EventPaymentReceivedSuccess, Amount: big.NewInt(50), Identifier: 7},
+			expectedActions: 0,
+		},
+		testcase{
+			name:            "other event",
+			rule:            &Rule{Name: "notify", Trigger: &Trigger{Type: TriggerPayment, Event: payments.EventPaymentReceivedSuccess}, Action: &Action{Type: ActionWebhook, URL: hookURL, Secret: string(secret)}},
+			event:           &payments.Event{EventName: payments.EventPaymentSentSuccess, Amount: big.NewInt(150), Identifier: 7},
+			expectedActions: 0,
+		},
+		testcase{
+			name:            "failing webhook",
+			rule:            &Rule{Name: "notify", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionWebhook, URL: hookURL, Secret: string(secret)}},
+			event:           &payments.Event{EventName: payments.EventPaymentReceivedSuccess, Amount: big.NewInt(150), Identifier: 7},
+			hookStatus:      http.StatusInternalServerError,
+			expectedActions: 1,
+			expectedErr:     "rule notify: received 500 status code from webhook https://example.com/hook",
+		},
+		testcase{
+			name:            "forward a payment",
+			rule:            &Rule{Name: "forward", Trigger: &Trigger{Type: TriggerPayment, Partner: &partnerAddress}, Action: &Action{Type: ActionPayment, Target: &target, Amount: big.NewInt(20)}},
+			event:           &payments.Event{EventName: payments.EventPaymentReceivedSuccess, Amount: big.NewInt(150), Identifier: 7},
+			expectedActions: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				records  = make([]*AuditRecord, 0)
+				payloads = make([]*Payload, 0)
+			)
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", tokenURL, httpmock.NewStringResponder(http.StatusOK, "["+channelJSON(100, 100)+"]"))
+			httpmock.RegisterResponder("POST", hookURL, func(request *http.Request) (*http.Response, error) {
+				body, err := webhook.VerifyRequest(secret, request, time.Minute)
+				require.NoError(t, err)
+
+				payload := &Payload{}
+				require.NoError(t, json.Unmarshal(body, payload))
+				payloads = append(payloads, payload)
+
+				return httpmock.NewStringResponse(tc.hookStatus, ""), nil
+			})
+			httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/payments/"+tokenHex+"/"+target.Hex(), httpmock.NewStringResponder(http.StatusOK, fmt.Sprintf(`{"initiator_address":"%s","target_address":"%s","token_address":"%s","amount":20,"identifier":8}`, partnerHex, target.Hex(), tokenHex)))
+
+			engine := NewEngine(config, []*Rule{tc.rule}, &Options{Audit: func(record *AuditRecord) { records = append(records, record) }}, http.DefaultClient)
+			event := &events.PaymentEvent{TokenAddress: tokenAddress, PartnerAddress: partnerAddress, Event: tc.event}
+
+			err := engine.HandleEvent(context.Background(), event)
+
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedErr, err.Error())
+			} else {
+				require.NoError(t, err)
+			}
+
+			// the rule is cooling down
+			require.NoError(t, engine.HandleEvent(context.Background(), event))
+
+			require.Len(t, records, tc.expectedActions)
+
+			if tc.expectedActions == 0 {
+				return
+			}
+
+			assert.Equal(t, event, records[0].Event)
+			assert.Equal(t, "acme", records[0].Fields["tenant"])
+
+			switch tc.rule.Action.Type {
+			case ActionWebhook:
+				require.Len(t, payloads, 1)
+				assert.Equal(t, tc.rule.Name, payloads[0].Rule)
+				assert.Equal(t, tokenHex, payloads[0].TokenAddress)
+				assert.Equal(t, partnerHex, payloads[0].PartnerAddress)
+				assert.Equal(t, int64(7), payloads[0].Event.Identifier)
+				assert.Equal(t, redact.Redacted, records[0].Rule.Action.Secret)
+				assert.Equal(t, string(secret), tc.rule.Action.Secret, "the rule keeps its secret")
+			case ActionPayment:
+				require.NotNil(t, records[0].Payment)
+				assert.Equal(t, int64(8), records[0].Payment.Identifier)
+			}
+		})
+	}
+}
+
+func TestEngineCooldown(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		manual  = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+		actions = 0
+		rule    = &Rule{Name: "close", Trigger: &Trigger{Type: TriggerChannelBalance, Below: big.NewInt(10)}, Action: &Action{Type: ActionClose}, Cooldown: "1h"}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "["+channelJSON(5, 30)+"]"))
+	httpmock.RegisterResponder("PATCH", channelURL, httpmock.NewStringResponder(http.StatusOK, channelJSON(5, 30)))
+
+	engine := NewEngine(config, []*Rule{rule}, &Options{Audit: func(*AuditRecord) { actions++ }, Clock: manual}, http.DefaultClient)
+
+	require.NoError(t, engine.Check(context.Background()))
+	assert.Equal(t, 1, actions)
+
+	manual.Advance(59 * time.Minute)
+	require.NoError(t, engine.Check(context.Background()))
+	assert.Equal(t, 1, actions)
+
+	manual.Advance(time.Minute)
+	require.NoError(t, engine.Check(context.Background()))
+	assert.Equal(t, 2, actions)
+}
+
+func TestEngineRun(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		eventChan = make(chan *events.PaymentEvent, 1)
+		records   = make(chan *AuditRecord, 2)
+		rule      = &Rule{Name: "notify", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionWebhook, URL: hookURL}}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", listURL, httpmock.NewStringResponder(http.StatusOK, "[]"))
+	httpmock.RegisterResponder("GET", tokenURL, httpmock.NewStringResponder(http.StatusOK, "[]"))
+	httpmock.RegisterResponder("POST", hookURL, httpmock.NewStringResponder(http.StatusBadGateway, ""))
+
+	engine := NewEngine(config, []*Rule{rule}, &Options{Audit: func(record *AuditRecord) { records <- record }}, http.DefaultClient)
+	errs := engine.Run(context.Background(), &events.Subscription{Events: eventChan})
+
+	eventChan <- &events.PaymentEvent{TokenAddress: common.HexToAddress(tokenHex), PartnerAddress: common.HexToAddress(partnerHex), Event: &payments.Event{EventName: payments.EventPaymentReceivedSuccess}}
+
+	record := <-records
+	assert.Equal(t, "notify", record.Rule.Name)
+
+	err := <-errs
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rule notify")
+
+	close(eventChan)
+
+	_, ok := <-errs
+	assert.False(t, ok)
+}
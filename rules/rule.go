@@ -0,0 +1,185 @@
+package rules
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultCooldown is the time a rule does not fire again for the same channel after
+// firing, when the rule gives no cooldown.
+const DefaultCooldown = 10 * time.Minute
+
+// The types of triggers.
+const (
+	// TriggerPayment fires on the payment events of the node, of the Event name when
+	// given, for at least MinAmount when given.
+	TriggerPayment = "payment"
+	// TriggerChannelBalance fires for the opened channels whose balance is below
+	// Below.
+	TriggerChannelBalance = "channel_balance"
+)
+
+// The types of actions.
+const (
+	// ActionWebhook posts the firing to the URL, signed with the Secret like the
+	// webhook package does.
+	ActionWebhook = "webhook"
+	// ActionPayment pays the Amount of the token of the firing to the Target.
+	ActionPayment = "payment"
+	// ActionDeposit deposits the Amount into the channel of the firing.
+	ActionDeposit = "deposit"
+	// ActionClose closes the channel of the firing.
+	ActionClose = "close"
+)
+
+// Trigger is what a rule fires on, restricted to the channels of Token and with
+// Partner when they are given.
+type Trigger struct {
+	Type      string          `json:"type"`
+	Token     *common.Address `json:"token,omitempty"`
+	Partner   *common.Address `json:"partner,omitempty"`
+	Event     string          `json:"event,omitempty"`
+	MinAmount *big.Int        `json:"min_amount,omitempty"`
+	Below     *big.Int        `json:"below,omitempty"`
+}
+
+// Action is what a rule does when it fires.
+type Action struct {
+	Type   string          `json:"type"`
+	URL    string          `json:"url,omitempty"`
+	Secret string          `json:"secret,omitempty"`
+	Target *common.Address `json:"target,omitempty"`
+	Amount *big.Int        `json:"amount,omitempty"`
+}
+
+// Rule runs its action when its trigger fires, at most once per Cooldown for every
+// channel, DefaultCooldown when empty, e.g. topping up a channel running low:
+//
+//	{
+//	  "name": "top up",
+//	  "trigger": {"type": "channel_balance", "token": "0x6B17...", "below": 1000},
+//	  "action": {"type": "deposit", "amount": 5000},
+//	  "cooldown": "1h"
+//	}
+type Rule struct {
+	Name     string   `json:"name"`
+	Trigger  *Trigger `json:"trigger"`
+	Action   *Action  `json:"action"`
+	Cooldown string   `json:"cooldown,omitempty"`
+}
+
+// Validate returns an error when the trigger or the action of the rule is of an
+// unknown type or lacks what its type requires.
+func (rule *Rule) Validate() error {
+	switch {
+	case rule.Name == "":
+		return errors.New("rule without a name")
+	case rule.Trigger == nil:
+		return fmt.Errorf("rule %s has no trigger", rule.Name)
+	case rule.Action == nil:
+		return fmt.Errorf("rule %s has no action", rule.Name)
+	}
+
+	switch rule.Trigger.Type {
+	case TriggerPayment:
+	case TriggerChannelBalance:
+		if rule.Trigger.Below == nil {
+			return fmt.Errorf("channel_balance trigger of rule %s has no below", rule.Name)
+		}
+	default:
+		return fmt.Errorf("unknown trigger type of rule %s: %s", rule.Name, rule.Trigger.Type)
+	}
+
+	switch rule.Action.Type {
+	case ActionWebhook:
+		if rule.Action.URL == "" {
+			return fmt.Errorf("webhook action of rule %s has no url", rule.Name)
+		}
+	case ActionPayment:
+		if rule.Action.Target == nil {
+			return fmt.Errorf("payment action of rule %s has no target", rule.Name)
+		}
+
+		if err := validAmount(rule); err != nil {
+			return err
+		}
+	case ActionDeposit:
+		if err := validAmount(rule); err != nil {
+			return err
+		}
+	case ActionClose:
+	default:
+		return fmt.Errorf("unknown action type of rule %s: %s", rule.Name, rule.Action.Type)
+	}
+
+	if _, err := rule.cooldown(); err != nil {
+		return fmt.Errorf("invalid cooldown of rule %s: %s", rule.Name, err.Error())
+	}
+
+	return nil
+}
+
+// cooldown returns the cooldown of the rule, DefaultCooldown when it has none.
+func (rule *Rule) cooldown() (time.Duration, error) {
+	if rule.Cooldown == "" {
+		return DefaultCooldown, nil
+	}
+
+	return time.ParseDuration(rule.Cooldown)
+}
+
+// validAmount returns an error when the amount of the action of the rule is missing
+// or not positive.
+func validAmount(rule *Rule) error {
+	var amount = rule.Action.Amount
+
+	if amount == nil || amount.Sign() <= 0 {
+		return fmt.Errorf("%s action of rule %s needs a positive amount", rule.Action.Type, rule.Name)
+	}
+
+	return nil
+}
+
+// LoadRules will read the rules from the JSON file at path, holding a list of rules,
+// and validate them.
+func LoadRules(path string) ([]*Rule, error) {
+	var (
+		err      error
+		contents []byte
+		rules    []*Rule
+		names    = make(map[string]bool)
+	)
+
+	if contents, err = ioutil.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(contents, &rules); err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %s", path, err.Error())
+	}
+
+	for _, rule := range rules {
+		if rule == nil {
+			return nil, fmt.Errorf("empty rule in %s", path)
+		}
+
+		if err = rule.Validate(); err != nil {
+			return nil, err
+		}
+
+		// cooldowns are tracked by name
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicate rule %s in %s", rule.Name, path)
+		}
+
+		names[rule.Name] = true
+	}
+
+	return rules, nil
+}
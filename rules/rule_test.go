@@ -0,0 +1,181 @@
+package rules
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleValidate(t *testing.T) {
+	var target = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+
+	type testcase struct {
+		name        string
+		rule        *Rule
+		expectedErr string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "balance rule depositing",
+			rule: &Rule{Name: "top up", Trigger: &Trigger{Type: TriggerChannelBalance, Below: big.NewInt(10)}, Action: &Action{Type: ActionDeposit, Amount: big.NewInt(50)}, Cooldown: "1h"},
+		},
+		testcase{
+			name: "payment rule calling a webhook",
+			rule: &Rule{Name: "notify", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionWebhook, URL: "https://example.com/hook"}},
+		},
+		testcase{
+			name: "payment rule paying",
+			rule: &Rule{Name: "forward", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionPayment, Target: &target, Amount: big.NewInt(1)}},
+		},
+		testcase{
+			name: "balance rule closing",
+			rule: &Rule{Name: "close", Trigger: &Trigger{Type: TriggerChannelBalance, Below: big.NewInt(1)}, Action: &Action{Type: ActionClose}},
+		},
+		testcase{
+			name:        "no name",
+			rule:        &Rule{Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionClose}},
+			expectedErr: "rule without a name",
+		},
+		testcase{
+			name:        "no trigger",
+			rule:        &Rule{Name: "r", Action: &Action{Type: ActionClose}},
+			expectedErr: "rule r has no trigger",
+		},
+		testcase{
+			name:        "unknown trigger",
+			rule:        &Rule{Name: "r", Trigger: &Trigger{Type: "block"}, Action: &Action{Type: ActionClose}},
+			expectedErr: "unknown trigger type of rule r: block",
+		},
+		testcase{
+			name:        "balance trigger without below",
+			rule:        &Rule{Name: "r", Trigger: &Trigger{Type: TriggerChannelBalance}, Action: &Action{Type: ActionClose}},
+			expectedErr: "channel_balance trigger of rule r has no below",
+		},
+		testcase{
+			name:        "webhook without url",
+			rule:        &Rule{Name: "r", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionWebhook}},
+			expectedErr: "webhook action of rule r has no url",
+		},
+		testcase{
+			name:        "payment without target",
+			rule:        &Rule{Name: "r", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionPayment, Amount: big.NewInt(1)}},
+			expectedErr: "payment action of rule r has no target",
+		},
+		testcase{
+			name:        "deposit without amount",
+			rule:        &Rule{Name: "r", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionDeposit}},
+			expectedErr: "deposit action of rule r needs a positive amount",
+		},
+		testcase{
+			name:        "negative deposit",
+			rule:        &Rule{Name: "r", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionDeposit, Amount: big.NewInt(-1)}},
+			expectedErr: "deposit action of rule r needs a positive amount",
+		},
+		testcase{
+			name:        "unknown action",
+			rule:        &Rule{Name: "r", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: "mint"}},
+			expectedErr: "unknown action type of rule r: mint",
+		},
+		testcase{
+			name:        "invalid cooldown",
+			rule:        &Rule{Name: "r", Trigger: &Trigger{Type: TriggerPayment}, Action: &Action{Type: ActionClose}, Cooldown: "soon"},
+			expectedErr: `invalid cooldown of rule r: time: invalid duration`,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.rule.Validate()
+
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rules")
+	require.NoError(t, err)
+
+	defer os.RemoveAll(dir)
+
+	type testcase struct {
+		name          string
+		contents      string
+		expectedNames []string
+		expectedErr   string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "rules",
+			contents: `[
+				{"name": "top up", "trigger": {"type": "channel_balance", "token": "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", "below": 1000}, "action": {"type": "deposit", "amount": 5000}, "cooldown": "1h"},
+				{"name": "notify", "trigger": {"type": "payment", "event": "EventPaymentReceivedSuccess", "min_amount": 100}, "action": {"type": "webhook", "url": "https://example.com/hook", "secret": "s3cr3t"}}
+			]`,
+			expectedNames: []string{"top up", "notify"},
+		},
+		testcase{
+			name:        "invalid json",
+			contents:    `{"name": "top up"}`,
+			expectedErr: "unable to parse",
+		},
+		testcase{
+			name:        "invalid rule",
+			contents:    `[{"name": "top up", "trigger": {"type": "channel_balance"}, "action": {"type": "close"}}]`,
+			expectedErr: "channel_balance trigger of rule top up has no below",
+		},
+		testcase{
+			name:        "empty rule",
+			contents:    `[null]`,
+			expectedErr: "empty rule",
+		},
+		testcase{
+			name: "duplicate rule",
+			contents: `[
+				{"name": "close", "trigger": {"type": "payment"}, "action": {"type": "close"}},
+				{"name": "close", "trigger": {"type": "payment"}, "action": {"type": "close"}}
+			]`,
+			expectedErr: "duplicate rule close",
+		},
+	}
+
+	for i, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(dir, string(rune('a'+i))+".json")
+			require.NoError(t, ioutil.WriteFile(path, []byte(tc.contents), 0600))
+
+			rules, err := LoadRules(path)
+
+			if tc.expectedErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+
+			names := make([]string, 0)
+			for _, rule := range rules {
+				names = append(names, rule.Name)
+			}
+
+			assert.Equal(t, tc.expectedNames, names)
+		})
+	}
+
+	_, err = LoadRules(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
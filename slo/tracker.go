@@ -0,0 +1,280 @@
+// Package slo tracks the reliability of the interactions with a Raiden node, such as
+// payment sends, against service level objectives, and reports how fast their error
+// budget burns so that platform teams can alert on degrading payment reliability.
+package slo
+
+import (
+	"math"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+// DefaultResolution is the granularity of the observations of a tracker when its
+// objective has none.
+const DefaultResolution = time.Minute
+
+// Objective is a service level objective over a window, e.g. 99% of the payment sends
+// of the last 30 days succeed and 95% of them take less than 2 seconds. A zero
+// LatencyTarget sets no latency objective.
+type Objective struct {
+	Name             string
+	SuccessTarget    float64
+	LatencyThreshold time.Duration
+	LatencyTarget    float64
+	Window           time.Duration
+	// Resolution is the granularity of the observations, windows shorter than a few
+	// resolutions being imprecise. It is DefaultResolution when zero.
+	Resolution time.Duration
+	// Clock times the observations and their latency, the system time when nil.
+	Clock clock.Clock
+}
+
+// Status tells how the observations of a window fare against the objective. A burn
+// rate is the rate of failed, or slow, observations over the rate the objective
+// allows: at a burn rate of 1 the error budget is exhausted exactly at the end of the
+// window of the objective.
+type Status struct {
+	Window          time.Duration
+	Total           int
+	Failed          int
+	Slow            int
+	SuccessRate     float64
+	LatencyRate     float64
+	SuccessBurnRate float64
+	LatencyBurnRate float64
+	// BudgetRemaining is the fraction of the error budget of the window left, a
+	// negative one being overspent.
+	BudgetRemaining float64
+}
+
+// BurnRate returns the highest of the success and latency burn rates.
+func (status *Status) BurnRate() float64 {
+	if status.LatencyBurnRate > status.SuccessBurnRate {
+		return status.LatencyBurnRate
+	}
+
+	return status.SuccessBurnRate
+}
+
+// Alert fires when the burn rate over its window reaches its threshold, e.g. a burn
+// rate of 14.4 over an hour spends 2% of a 30 day budget.
+type Alert struct {
+	Name     string
+	Window   time.Duration
+	BurnRate float64
+}
+
+// AlertEvent is given to the callback of an alert when it starts firing, and when it
+// resolves again.
+type AlertEvent struct {
+	Alert  *Alert
+	Status *Status
+	Firing bool
+}
+
+// Tracker records the outcome and latency of observations, e.g. payment sends, and
+// evaluates them against an objective. It is safe for concurrent use.
+type Tracker struct {
+	objective  *Objective
+	resolution time.Duration
+	now        func() time.Time
+
+	mutex   sync.Mutex
+	buckets []bucket
+	alerts  []*alertState
+}
+
+type bucket struct {
+	index  int64
+	total  int
+	failed int
+	slow   int
+}
+
+type alertState struct {
+	alert    *Alert
+	callback func(*AlertEvent)
+	firing   bool
+}
+
+// NewTracker creates a tracker of the objective.
+func NewTracker(objective *Objective) *Tracker {
+	var resolution = objective.Resolution
+
+	if resolution <= 0 {
+		resolution = DefaultResolution
+	}
+
+	return &Tracker{
+		objective:  objective,
+		resolution: resolution,
+		now:        clock.Or(objective.Clock).Now,
+		buckets:    make([]bucket, int(objective.Window/resolution)+1),
+	}
+}
+
+// OnAlert registers the callback of the alert, called when the alert starts firing
+// and when it resolves. Callbacks are called by Observe, after the observation is
+// recorded, and are to return quickly.
+func (tracker *Tracker) OnAlert(alert *Alert, callback func(*AlertEvent)) {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	tracker.alerts = append(tracker.alerts, &alertState{alert: alert, callback: callback})
+}
+
+// Observe records an observation that took the duration and failed when failed is
+// true, then evaluates the alerts.
+func (tracker *Tracker) Observe(duration time.Duration, failed bool) {
+	var (
+		fired  []*alertState
+		events []*AlertEvent
+		now    = tracker.now()
+		index  = now.UnixNano() / int64(tracker.resolution)
+	)
+
+	tracker.mutex.Lock()
+
+	slot := &tracker.buckets[index%int64(len(tracker.buckets))]
+	if slot.index != index {
+		*slot = bucket{index: index}
+	}
+
+	slot.total++
+
+	if failed {
+		slot.failed++
+	}
+
+	if tracker.objective.LatencyTarget > 0 && duration > tracker.objective.LatencyThreshold {
+		slot.slow++
+	}
+
+	for _, state := range tracker.alerts {
+		var (
+			status = tracker.status(now, state.alert.Window)
+			firing = status.BurnRate() >= state.alert.BurnRate
+		)
+
+		if firing != state.firing {
+			state.firing = firing
+			fired = append(fired, state)
+			events = append(events, &AlertEvent{Alert: state.alert, Status: status, Firing: firing})
+		}
+	}
+
+	tracker.mutex.Unlock()
+
+	for i, state := range fired {
+		state.callback(events[i])
+	}
+}
+
+// Status returns how the observations of the last window fare against the objective,
+// the window being capped to the one of the objective. A zero window is the window of
+// the objective.
+func (tracker *Tracker) Status(window time.Duration) *Status {
+	tracker.mutex.Lock()
+	defer tracker.mutex.Unlock()
+
+	return tracker.status(tracker.now(), window)
+}
+
+func (tracker *Tracker) status(now time.Time, window time.Duration) *Status {
+	if window <= 0 || window > tracker.objective.Window {
+		window = tracker.objective.Window
+	}
+
+	var (
+		status = &Status{Window: window}
+		last   = now.UnixNano() / int64(tracker.resolution)
+		first  = now.Add(-window).UnixNano() / int64(tracker.resolution)
+	)
+
+	for _, slot := range tracker.buckets {
+		if slot.index > first && slot.index <= last {
+			status.Total += slot.total
+			status.Failed += slot.failed
+			status.Slow += slot.slow
+		}
+	}
+
+	status.SuccessRate, status.LatencyRate = 1, 1
+
+	if status.Total == 0 {
+		status.BudgetRemaining = 1
+		return status
+	}
+
+	status.SuccessRate = 1 - float64(status.Failed)/float64(status.Total)
+	status.SuccessBurnRate = burnRate(status.Failed, status.Total, tracker.objective.SuccessTarget)
+	status.BudgetRemaining = 1 - status.SuccessBurnRate
+
+	if tracker.objective.LatencyTarget > 0 {
+		status.LatencyRate = 1 - float64(status.Slow)/float64(status.Total)
+		status.LatencyBurnRate = burnRate(status.Slow, status.Total, tracker.objective.LatencyTarget)
+
+		if remaining := 1 - status.LatencyBurnRate; remaining < status.BudgetRemaining {
+			status.BudgetRemaining = remaining
+		}
+	}
+
+	return status
+}
+
+func burnRate(bad, total int, target float64) float64 {
+	var allowed = 1 - target
+
+	if allowed <= 0 {
+		if bad > 0 {
+			return math.Inf(1)
+		}
+
+		return 0
+	}
+
+	return float64(bad) / float64(total) / allowed
+}
+
+// Middleware returns a middleware observing the payment sends of a client with the
+// tracker, see config.Config. A payment send fails when the node can't be reached or
+// answers with a server error, a timeout or a conflict, i.e. the payment could not be
+// made, and not when the request itself was invalid.
+func (tracker *Tracker) Middleware() config.Middleware {
+	return func(next config.Doer) config.Doer {
+		return func(request *http.Request) (*http.Response, error) {
+			if request.Method != "POST" || !strings.Contains(request.URL.Path, "/payments/") {
+				return next(request)
+			}
+
+			var (
+				start         = tracker.now()
+				response, err = next(request)
+			)
+
+			tracker.Observe(tracker.now().Sub(start), Failed(response, err))
+
+			return response, err
+		}
+	}
+}
+
+// Failed tells whether a request failed for reasons of the node or the network rather
+// than of the request.
+func Failed(response *http.Response, err error) bool {
+	if err != nil || response == nil {
+		return true
+	}
+
+	switch response.StatusCode {
+	case http.StatusRequestTimeout, http.StatusConflict:
+		return true
+	default:
+		return response.StatusCode >= http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,260 @@
+package slo
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleTracker() {
+	var (
+		tracker = NewTracker(&Objective{
+			Name:             "payment sends",
+			SuccessTarget:    0.99,
+			LatencyThreshold: 2 * time.Second,
+			LatencyTarget:    0.95,
+			Window:           30 * 24 * time.Hour,
+		})
+		config = &config.Config{
+			Host:        "http://localhost:5001",
+			APIVersion:  "v1",
+			Middlewares: []config.Middleware{tracker.Middleware()},
+		}
+	)
+
+	tracker.OnAlert(&Alert{Name: "fast burn", Window: time.Hour, BurnRate: 14.4}, func(event *AlertEvent) {
+		log.Printf("%s firing=%t: burn rate %.1f over %s", event.Alert.Name, event.Firing, event.Status.BurnRate(), event.Alert.Window)
+	})
+
+	payments.NewInitiator(config, http.DefaultClient).Initiate(context.Background(),
+		common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+		common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+		big.NewInt(42),
+	)
+
+	fmt.Printf("%.0f%% of the error budget left\n", 100*tracker.Status(0).BudgetRemaining)
+}
+
+// observation is a number of observations of the same duration and outcome.
+type observation struct {
+	count    int
+	duration time.Duration
+	failed   bool
+	age      time.Duration
+}
+
+func TestTrackerStatus(t *testing.T) {
+	type testcase struct {
+		name           string
+		observations   []observation
+		window         time.Duration
+		expectedStatus *Status
+	}
+
+	var objective = &Objective{
+		SuccessTarget:    0.99,
+		LatencyThreshold: time.Second,
+		LatencyTarget:    0.9,
+		Window:           24 * time.Hour,
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "no observations",
+			expectedStatus: &Status{Window: 24 * time.Hour, SuccessRate: 1, LatencyRate: 1, BudgetRemaining: 1},
+		},
+		testcase{
+			name: "within the objective",
+			observations: []observation{
+				observation{count: 995, duration: 100 * time.Millisecond},
+				observation{count: 5, duration: 100 * time.Millisecond, failed: true},
+			},
+			expectedStatus: &Status{
+				Window:          24 * time.Hour,
+				Total:           1000,
+				Failed:          5,
+				SuccessRate:     0.995,
+				LatencyRate:     1,
+				SuccessBurnRate: 0.5,
+				BudgetRemaining: 0.5,
+			},
+		},
+		testcase{
+			name: "slow observations",
+			observations: []observation{
+				observation{count: 80, duration: 100 * time.Millisecond},
+				observation{count: 20, duration: 3 * time.Second},
+			},
+			expectedStatus: &Status{
+				Window:          24 * time.Hour,
+				Total:           100,
+				Slow:            20,
+				SuccessRate:     1,
+				LatencyRate:     0.8,
+				LatencyBurnRate: 2,
+				BudgetRemaining: -1,
+			},
+		},
+		testcase{
+			name: "observations out of the window",
+			observations: []observation{
+				observation{count: 10, failed: true, age: 2 * time.Hour},
+				observation{count: 100, age: 30 * time.Minute},
+			},
+			window: time.Hour,
+			expectedStatus: &Status{
+				Window:          time.Hour,
+				Total:           100,
+				SuccessRate:     1,
+				LatencyRate:     1,
+				BudgetRemaining: 1,
+			},
+		},
+		testcase{
+			name: "observations out of the window of the objective",
+			observations: []observation{
+				observation{count: 10, failed: true, age: 25 * time.Hour},
+				observation{count: 10, age: time.Hour},
+			},
+			window: 48 * time.Hour,
+			expectedStatus: &Status{
+				Window:          24 * time.Hour,
+				Total:           10,
+				SuccessRate:     1,
+				LatencyRate:     1,
+				BudgetRemaining: 1,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				now     = time.Date(2018, 10, 30, 12, 0, 0, 0, time.UTC)
+				tracker = NewTracker(objective)
+			)
+
+			for _, observation := range tc.observations {
+				tracker.now = func() time.Time { return now.Add(-observation.age) }
+
+				for i := 0; i < observation.count; i++ {
+					tracker.Observe(observation.duration, observation.failed)
+				}
+			}
+
+			tracker.now = func() time.Time { return now }
+
+			status := tracker.Status(tc.window)
+
+			assert.Equal(t, tc.expectedStatus.Window, status.Window)
+			assert.Equal(t, tc.expectedStatus.Total, status.Total)
+			assert.Equal(t, tc.expectedStatus.Failed, status.Failed)
+			assert.Equal(t, tc.expectedStatus.Slow, status.Slow)
+			assert.InDelta(t, tc.expectedStatus.SuccessRate, status.SuccessRate, 1e-9)
+			assert.InDelta(t, tc.expectedStatus.LatencyRate, status.LatencyRate, 1e-9)
+			assert.InDelta(t, tc.expectedStatus.SuccessBurnRate, status.SuccessBurnRate, 1e-9)
+			assert.InDelta(t, tc.expectedStatus.LatencyBurnRate, status.LatencyBurnRate, 1e-9)
+			assert.InDelta(t, tc.expectedStatus.BudgetRemaining, status.BudgetRemaining, 1e-9)
+		})
+	}
+}
+
+func TestTrackerAlerts(t *testing.T) {
+	var (
+		manual  = clock.NewManual(time.Date(2018, 10, 30, 12, 0, 0, 0, time.UTC))
+		tracker = NewTracker(&Objective{SuccessTarget: 0.99, Window: 30 * 24 * time.Hour, Clock: manual})
+		events  = make([]*AlertEvent, 0)
+	)
+
+	tracker.OnAlert(&Alert{Name: "fast burn", Window: time.Hour, BurnRate: 5}, func(event *AlertEvent) {
+		events = append(events, event)
+	})
+
+	for i := 0; i < 9; i++ {
+		tracker.Observe(time.Second, false)
+	}
+
+	assert.Empty(t, events)
+
+	tracker.Observe(time.Second, true)
+	tracker.Observe(time.Second, true)
+
+	require.Len(t, events, 1)
+	assert.True(t, events[0].Firing)
+	assert.Equal(t, "fast burn", events[0].Alert.Name)
+	assert.InDelta(t, 10, events[0].Status.SuccessBurnRate, 1e-9)
+
+	for i := 0; i < 100; i++ {
+		tracker.Observe(time.Second, false)
+	}
+
+	require.Len(t, events, 2)
+	assert.False(t, events[1].Firing)
+	assert.True(t, events[1].Status.SuccessBurnRate < 5)
+
+	// the failures leave the window of the alert an hour later
+	manual.Advance(time.Hour + DefaultResolution)
+
+	tracker.Observe(time.Second, true)
+
+	require.Len(t, events, 3)
+	assert.True(t, events[2].Firing)
+	assert.InDelta(t, 100, events[2].Status.SuccessBurnRate, 1e-9)
+}
+
+func TestTrackerMiddleware(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	var (
+		tracker = NewTracker(&Objective{SuccessTarget: 0.99, Window: time.Hour})
+		config  = &config.Config{
+			Host:        "http://localhost:5001",
+			APIVersion:  "v1",
+			Middlewares: []config.Middleware{tracker.Middleware()},
+		}
+		initiator    = payments.NewInitiator(config, http.DefaultClient)
+		lister       = payments.NewLister(config, http.DefaultClient)
+		token        = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		target       = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+		invalid      = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+		unroutable   = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		ctx          = context.Background()
+		paymentsURL  = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/"
+		paymentsJSON = `{"initiator_address":"0x2a65Aca4D5fC5B5C859090a6c34d164135398226","target_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","amount":10,"identifier":42}`
+	)
+
+	httpmock.RegisterResponder("POST", paymentsURL+target.Hex(), httpmock.NewStringResponder(http.StatusOK, paymentsJSON))
+	httpmock.RegisterResponder("GET", paymentsURL+target.Hex(), httpmock.NewStringResponder(http.StatusInternalServerError, ""))
+	httpmock.RegisterResponder("POST", paymentsURL+invalid.Hex(), httpmock.NewStringResponder(http.StatusBadRequest, `{"errors":"invalid amount"}`))
+	httpmock.RegisterResponder("POST", paymentsURL+unroutable.Hex(), httpmock.NewStringResponder(http.StatusConflict, `{"errors":"no route available"}`))
+
+	_, err := initiator.Initiate(ctx, token, target, big.NewInt(10))
+	require.NoError(t, err)
+
+	_, err = initiator.Initiate(ctx, token, invalid, big.NewInt(10))
+	assert.Error(t, err)
+
+	_, err = initiator.Initiate(ctx, token, unroutable, big.NewInt(10))
+	assert.Error(t, err)
+
+	_, err = lister.List(ctx, token, target)
+	assert.Error(t, err)
+
+	status := tracker.Status(0)
+
+	assert.Equal(t, 3, status.Total)
+	assert.Equal(t, 1, status.Failed)
+}
@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+)
+
+// Policy is the retention of the entries of a Store under Prefix, so that the state
+// of a long-running service does not grow without bound: the entries older than
+// MaxAge are deleted, then the oldest ones until at most MaxEntries entries of at
+// most MaxBytes, keys and values, are left, a zero limit not applying.
+//
+// Time returns the time of an entry and whether it may be deleted at all, e.g. not
+// while the payment it records is pending. Without Time, MaxAge does not apply and
+// the entries are taken to be ordered by age along with their keys, as the keys of
+// the subsystems of the client that keep a log are.
+type Policy struct {
+	Prefix     string
+	MaxAge     time.Duration
+	MaxEntries int
+	MaxBytes   int64
+	Time       func(entry *Entry) (time.Time, bool)
+}
+
+// Usage is the size of the entries of a Store under a prefix, keys and values.
+type Usage struct {
+	Entries int
+	Bytes   int64
+}
+
+// Measure returns the size of the entries of the store under the prefix, e.g. to
+// export it as metrics.
+func Measure(store Store, prefix string) (*Usage, error) {
+	entries, err := store.List(prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return usage(entries), nil
+}
+
+// Compactor is implemented by the stores reclaiming the space of the deleted entries
+// on demand, rather than as they are deleted, e.g. the SQLite store.
+type Compactor interface {
+	Compact() error
+}
+
+// CollectResult is what a collection did under the prefix of a policy: the Usage
+// Before and After it, and the number of entries Deleted.
+type CollectResult struct {
+	Prefix  string
+	Before  Usage
+	After   Usage
+	Deleted int
+}
+
+// Collection is a pass of a collector over its policies, in their order, and
+// whether the store was compacted after it.
+type Collection struct {
+	Started   time.Time
+	Finished  time.Time
+	Results   []*CollectResult
+	Compacted bool
+}
+
+// Collector deletes the entries of the Store its Policies no longer retain, and
+// compacts the store when it deleted any and the store is a Compactor. Report is
+// called with every collection, and the ages and the passes of Run are measured on
+// Clock, the system time when nil.
+type Collector struct {
+	Store    Store
+	Policies []*Policy
+	Clock    clock.Clock
+	Report   func(collection *Collection)
+}
+
+// NewCollector creates a collector of the entries of the store under the policies.
+func NewCollector(store Store, policies ...*Policy) *Collector {
+	return &Collector{
+		Store:    store,
+		Policies: policies,
+	}
+}
+
+// Collect will apply every policy in turn, stopping at the first error or when the
+// context is done, the collection so far being returned along with the error.
+func (collector *Collector) Collect(ctx context.Context) (*Collection, error) {
+	var (
+		err        error
+		now        = clock.Or(collector.Clock).Now()
+		deleted    int
+		collection = &Collection{Started: now, Results: make([]*CollectResult, 0, len(collector.Policies))}
+	)
+
+	for _, policy := range collector.Policies {
+		var result *CollectResult
+
+		if err = ctx.Err(); err != nil {
+			break
+		}
+
+		result, err = collector.apply(policy, now)
+		if result != nil {
+			collection.Results = append(collection.Results, result)
+			deleted += result.Deleted
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	if compactor, ok := collector.Store.(Compactor); ok && err == nil && deleted > 0 {
+		if err = compactor.Compact(); err == nil {
+			collection.Compacted = true
+		}
+	}
+
+	collection.Finished = clock.Or(collector.Clock).Now()
+
+	if collector.Report != nil {
+		collector.Report(collection)
+	}
+
+	return collection, err
+}
+
+// Run will collect every interval until ctx is done. The errors of the collections
+// are returned on the returned channel, which is closed once the collector stopped.
+// Errors are dropped when nobody is receiving them.
+func (collector *Collector) Run(ctx context.Context, interval time.Duration) <-chan error {
+	var errs = make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		for {
+			if _, err := collector.Collect(ctx); err != nil && ctx.Err() == nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-clock.Or(collector.Clock).After(interval):
+			}
+		}
+	}()
+
+	return errs
+}
+
+// collectable is an entry the policy may delete, with its time when known.
+type collectable struct {
+	entry *Entry
+	time  time.Time
+}
+
+// apply deletes the entries the policy no longer retains, oldest first.
+func (collector *Collector) apply(policy *Policy, now time.Time) (*CollectResult, error) {
+	var (
+		candidates = make([]*collectable, 0)
+		result     = &CollectResult{Prefix: policy.Prefix}
+	)
+
+	entries, err := collector.Store.List(policy.Prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Before = *usage(entries)
+	result.After = result.Before
+
+	for _, entry := range entries {
+		var candidate = &collectable{entry: entry}
+
+		if policy.Time != nil {
+			var ok bool
+
+			if candidate.time, ok = policy.Time(entry); !ok {
+				continue
+			}
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	if policy.Time != nil {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].time.Before(candidates[j].time)
+		})
+	}
+
+	for _, candidate := range candidates {
+		var expired = policy.Time != nil && policy.MaxAge > 0 && now.Sub(candidate.time) > policy.MaxAge
+
+		if !expired && !policy.exceeded(&result.After) {
+			break
+		}
+
+		if err = collector.Store.Delete(candidate.entry.Key); err != nil {
+			return result, err
+		}
+
+		result.Deleted++
+		result.After.Entries--
+		result.After.Bytes -= size(candidate.entry)
+	}
+
+	return result, nil
+}
+
+// exceeded tells whether the usage is beyond the limits of the policy.
+func (policy *Policy) exceeded(usage *Usage) bool {
+	return (policy.MaxEntries > 0 && usage.Entries > policy.MaxEntries) || (policy.MaxBytes > 0 && usage.Bytes > policy.MaxBytes)
+}
+
+func usage(entries []*Entry) *Usage {
+	var measured = &Usage{Entries: len(entries)}
+
+	for _, entry := range entries {
+		measured.Bytes += size(entry)
+	}
+
+	return measured
+}
+
+func size(entry *Entry) int64 {
+	return int64(len(entry.Key) + len(entry.Value))
+}
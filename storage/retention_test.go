@@ -0,0 +1,237 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleCollector() {
+	var (
+		store     = NewFileStore("/var/lib/payouts/state.json")
+		collector = NewCollector(store, &Policy{Prefix: "invoices/", MaxEntries: 10000})
+	)
+
+	collector.Report = func(collection *Collection) {
+		for _, result := range collection.Results {
+			fmt.Printf("%s: %d entries, %d bytes\n", result.Prefix, result.After.Entries, result.After.Bytes)
+		}
+	}
+
+	// the invoices beyond the 10000 latest are deleted every hour
+	errs := collector.Run(context.Background(), time.Hour)
+
+	for err := range errs {
+		fmt.Println("unable to collect:", err.Error())
+	}
+}
+
+func TestCollector(t *testing.T) {
+	type testcase struct {
+		name            string
+		policy          *Policy
+		expectedKeys    []string
+		expectedDeleted int
+	}
+
+	var (
+		now       = time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC)
+		entryTime = func(entry *Entry) (time.Time, bool) {
+			var value struct {
+				Time time.Time `json:"time"`
+				Done bool      `json:"done"`
+			}
+
+			if err := json.Unmarshal(entry.Value, &value); err != nil {
+				return time.Time{}, false
+			}
+
+			return value.Time, value.Done
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:            "max age",
+			policy:          &Policy{Prefix: "log/", MaxAge: 36 * time.Hour, Time: entryTime},
+			expectedKeys:    []string{"log/1", "log/3", "log/4", "other/1"},
+			expectedDeleted: 1,
+		},
+		testcase{
+			name:            "max entries",
+			policy:          &Policy{Prefix: "log/", MaxEntries: 2, Time: entryTime},
+			expectedKeys:    []string{"log/1", "log/4", "other/1"},
+			expectedDeleted: 2,
+		},
+		testcase{
+			name:            "max bytes",
+			policy:          &Policy{Prefix: "log/", MaxBytes: 100},
+			expectedKeys:    []string{"log/3", "log/4", "other/1"},
+			expectedDeleted: 2,
+		},
+		testcase{
+			name:            "max entries by key",
+			policy:          &Policy{Prefix: "log/", MaxEntries: 1},
+			expectedKeys:    []string{"log/4", "other/1"},
+			expectedDeleted: 3,
+		},
+		testcase{
+			name:            "max age without time",
+			policy:          &Policy{Prefix: "log/", MaxAge: time.Hour},
+			expectedKeys:    []string{"log/1", "log/2", "log/3", "log/4", "other/1"},
+			expectedDeleted: 0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				store     = NewMemoryStore()
+				collector = NewCollector(store, tc.policy)
+				reported  *Collection
+			)
+
+			collector.Clock = clock.NewManual(now)
+			collector.Report = func(collection *Collection) { reported = collection }
+
+			// log/1 is not done, so it is never deleted, log/2 is the oldest done entry
+			for key, value := range map[string]string{
+				"log/1":   fmt.Sprintf(`{"time":"%s","done":false}`, now.Add(-72*time.Hour).Format(time.RFC3339)),
+				"log/2":   fmt.Sprintf(`{"time":"%s","done":true}`, now.Add(-48*time.Hour).Format(time.RFC3339)),
+				"log/3":   fmt.Sprintf(`{"time":"%s","done":true}`, now.Add(-24*time.Hour).Format(time.RFC3339)),
+				"log/4":   fmt.Sprintf(`{"time":"%s","done":true}`, now.Format(time.RFC3339)),
+				"other/1": fmt.Sprintf(`{"time":"%s","done":true}`, now.Add(-72*time.Hour).Format(time.RFC3339)),
+			} {
+				require.NoError(t, store.Put(key, []byte(value)))
+			}
+
+			before, err := Measure(store, "log/")
+			require.NoError(t, err)
+
+			collection, err := collector.Collect(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, collection, reported)
+			assert.False(t, collection.Compacted, "the memory store is not compacted")
+
+			entries, err := store.List("")
+			require.NoError(t, err)
+
+			keys := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				keys = append(keys, entry.Key)
+			}
+
+			after, err := Measure(store, "log/")
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedKeys, keys)
+			assert.Equal(t, []*CollectResult{&CollectResult{Prefix: "log/", Before: *before, After: *after, Deleted: tc.expectedDeleted}}, collection.Results)
+			assert.Equal(t, now, collection.Started)
+		})
+	}
+}
+
+func TestCollectorCompact(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storage")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	db, err := sql.Open(fakeDriverName, "compact")
+	require.NoError(t, err)
+
+	sqliteStore, err := NewSQLiteStore(db, "")
+	require.NoError(t, err)
+
+	var (
+		path   = filepath.Join(dir, "state.json")
+		stores = map[string]Store{
+			"file":   NewFileStore(path),
+			"sqlite": sqliteStore,
+			"tenant": ForTenant(sqliteStore, "acme"),
+		}
+	)
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			var collector = NewCollector(store, &Policy{Prefix: "log/", MaxEntries: 1})
+
+			require.NoError(t, store.Put("log/1", []byte(`{}`)))
+
+			// nothing is deleted, nothing is compacted
+			collection, err := collector.Collect(context.Background())
+			require.NoError(t, err)
+			assert.False(t, collection.Compacted)
+
+			require.NoError(t, store.Put("log/2", []byte(`{}`)))
+
+			registeredDriver.mutex.Lock()
+			vacuums := registeredDriver.vacuums
+			registeredDriver.mutex.Unlock()
+
+			collection, err = collector.Collect(context.Background())
+			require.NoError(t, err)
+			assert.True(t, collection.Compacted)
+
+			registeredDriver.mutex.Lock()
+			vacuums = registeredDriver.vacuums - vacuums
+			registeredDriver.mutex.Unlock()
+
+			if name == "file" {
+				assert.Equal(t, 0, vacuums)
+
+				// a crash during a write leaves a partial temporary file behind
+				require.NoError(t, ioutil.WriteFile(path+".tmp", []byte(`{"log/`), 0600))
+				require.NoError(t, store.(Compactor).Compact())
+
+				_, err = os.Stat(path + ".tmp")
+				assert.True(t, os.IsNotExist(err), "the file store removes its temporary file")
+			} else {
+				assert.Equal(t, 1, vacuums)
+			}
+
+			value, err := store.Get("log/2")
+			require.NoError(t, err)
+			assert.Equal(t, `{}`, string(value))
+		})
+	}
+}
+
+func TestCollectorRun(t *testing.T) {
+	var (
+		manual      = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+		store       = NewMemoryStore()
+		collector   = NewCollector(store, &Policy{Prefix: "log/", MaxEntries: 1})
+		collections = make(chan *Collection, 2)
+		ctx, cancel = context.WithCancel(context.Background())
+	)
+
+	collector.Clock = manual
+	collector.Report = func(collection *Collection) { collections <- collection }
+
+	errs := collector.Run(ctx, time.Hour)
+
+	assert.Equal(t, 0, (<-collections).Results[0].Deleted)
+
+	require.NoError(t, store.Put("log/1", []byte(`{}`)))
+	require.NoError(t, store.Put("log/2", []byte(`{}`)))
+
+	manual.BlockUntil(1)
+	manual.Advance(time.Hour)
+
+	assert.Equal(t, 1, (<-collections).Results[0].Deleted)
+
+	cancel()
+
+	for range errs {
+	}
+}
@@ -0,0 +1,136 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// DefaultTable is the table of a SQLite store when no table is given.
+const DefaultTable = "raiden_store"
+
+// tableName matches the table names a SQLite store accepts, which are part of its
+// statements rather than their parameters.
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// NewSQLiteStore creates a Store that persists values in the table of a SQLite
+// database, created when it does not exist, so that several processes on a host can
+// share the state and query it with SQL. The database is opened by the application
+// with the driver of its choice, e.g. github.com/mattn/go-sqlite3 or modernc.org/sqlite,
+// which keeps this package free of cgo and of a driver dependency:
+//
+//	db, err := sql.Open("sqlite3", "/var/lib/payouts/state.db")
+//	store, err := storage.NewSQLiteStore(db, "")
+//
+// The table defaults to DefaultTable.
+func NewSQLiteStore(db *sql.DB, table string) (Store, error) {
+	if table == "" {
+		table = DefaultTable
+	}
+
+	if !tableName.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+
+	if _, err := db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (key TEXT PRIMARY KEY, value BLOB NOT NULL)", table)); err != nil {
+		return nil, err
+	}
+
+	return &sqliteStore{
+		db:    db,
+		table: table,
+	}, nil
+}
+
+type sqliteStore struct {
+	db    *sql.DB
+	table string
+}
+
+func (store *sqliteStore) Get(key string) ([]byte, error) {
+	var value []byte
+
+	err := store.db.QueryRow(fmt.Sprintf("SELECT value FROM %s WHERE key = ?", store.table), key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (store *sqliteStore) Put(key string, value []byte) error {
+	_, err := store.db.Exec(fmt.Sprintf("INSERT INTO %s (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value", store.table), key, copyValue(value))
+
+	return err
+}
+
+func (store *sqliteStore) Delete(key string) error {
+	_, err := store.db.Exec(fmt.Sprintf("DELETE FROM %s WHERE key = ?", store.table), key)
+
+	return err
+}
+
+func (store *sqliteStore) List(prefix string) ([]*Entry, error) {
+	var (
+		err     error
+		rows    *sql.Rows
+		entries = make([]*Entry, 0)
+	)
+
+	// the prefix is matched as a range of keys, which unlike LIKE uses the primary key
+	// and needs no escaping
+	if end := prefixEnd(prefix); end != "" {
+		rows, err = store.db.Query(fmt.Sprintf("SELECT key, value FROM %s WHERE key >= ? AND key < ? ORDER BY key", store.table), prefix, end)
+	} else {
+		rows, err = store.db.Query(fmt.Sprintf("SELECT key, value FROM %s WHERE key >= ? ORDER BY key", store.table), prefix)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry = &Entry{}
+
+		if err = rows.Scan(&entry.Key, &entry.Value); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// Compact will rebuild the database with VACUUM, returning the pages of the deleted
+// entries to the file system, see Collector.
+func (store *sqliteStore) Compact() error {
+	_, err := store.db.Exec("VACUUM")
+
+	return err
+}
+
+// prefixEnd returns the smallest key greater than every key starting with the
+// prefix, or an empty string when there is none, e.g. for an empty prefix.
+func prefixEnd(prefix string) string {
+	var end = []byte(prefix)
+
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return string(end[:i+1])
+		}
+	}
+
+	return ""
+}
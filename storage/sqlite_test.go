@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeDriverName = "storage-fake"
+
+// registeredDriver is the fake driver registered as fakeDriverName.
+var registeredDriver = &fakeDriver{tables: make(map[string]map[string][]byte)}
+
+func init() {
+	sql.Register(fakeDriverName, registeredDriver)
+}
+
+// fakeDriver answers the statements of the SQLite store from maps, one per data source
+// name, so that the store is tested without a SQLite driver.
+type fakeDriver struct {
+	mutex   sync.Mutex
+	tables  map[string]map[string][]byte
+	vacuums int
+}
+
+func (fake *fakeDriver) Open(name string) (driver.Conn, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if fake.tables[name] == nil {
+		fake.tables[name] = make(map[string][]byte)
+	}
+
+	return &fakeConn{driver: fake, table: fake.tables[name]}, nil
+}
+
+type fakeConn struct {
+	driver *fakeDriver
+	table  map[string][]byte
+}
+
+func (conn *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{conn: conn, query: query}, nil
+}
+
+func (conn *fakeConn) Close() error {
+	return nil
+}
+
+func (conn *fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeStmt struct {
+	conn  *fakeConn
+	query string
+}
+
+func (stmt *fakeStmt) Close() error {
+	return nil
+}
+
+func (stmt *fakeStmt) NumInput() int {
+	return -1
+}
+
+func (stmt *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	stmt.conn.driver.mutex.Lock()
+	defer stmt.conn.driver.mutex.Unlock()
+
+	switch {
+	case strings.HasPrefix(stmt.query, "CREATE TABLE IF NOT EXISTS raiden_store "):
+	case strings.HasPrefix(stmt.query, "INSERT INTO raiden_store ") && strings.Contains(stmt.query, "ON CONFLICT (key)"):
+		stmt.conn.table[args[0].(string)] = args[1].([]byte)
+	case strings.HasPrefix(stmt.query, "DELETE FROM raiden_store WHERE key = ?"):
+		delete(stmt.conn.table, args[0].(string))
+	case stmt.query == "VACUUM":
+		stmt.conn.driver.vacuums++
+	default:
+		return nil, errors.New("unexpected statement: " + stmt.query)
+	}
+
+	return driver.RowsAffected(1), nil
+}
+
+func (stmt *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	var rows = &fakeRows{}
+
+	stmt.conn.driver.mutex.Lock()
+	defer stmt.conn.driver.mutex.Unlock()
+
+	switch {
+	case stmt.query == "SELECT value FROM raiden_store WHERE key = ?":
+		rows.columns = []string{"value"}
+
+		if value, ok := stmt.conn.table[args[0].(string)]; ok {
+			rows.values = [][]driver.Value{{value}}
+		}
+	case strings.HasPrefix(stmt.query, "SELECT key, value FROM raiden_store WHERE key >= ?"):
+		rows.columns = []string{"key", "value"}
+
+		for key, value := range stmt.conn.table {
+			if key >= args[0].(string) && (len(args) == 1 || key < args[1].(string)) {
+				rows.values = append(rows.values, []driver.Value{key, value})
+			}
+		}
+
+		sort.Slice(rows.values, func(i, j int) bool {
+			return rows.values[i][0].(string) < rows.values[j][0].(string)
+		})
+	default:
+		return nil, errors.New("unexpected query: " + stmt.query)
+	}
+
+	return rows, nil
+}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (rows *fakeRows) Columns() []string {
+	return rows.columns
+}
+
+func (rows *fakeRows) Close() error {
+	return nil
+}
+
+func (rows *fakeRows) Next(dest []driver.Value) error {
+	if len(rows.values) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, rows.values[0])
+	rows.values = rows.values[1:]
+
+	return nil
+}
+
+func TestNewSQLiteStoreTable(t *testing.T) {
+	db, err := sql.Open(fakeDriverName, "table")
+	require.NoError(t, err)
+
+	_, err = NewSQLiteStore(db, "state; DROP TABLE users")
+	assert.EqualError(t, err, `invalid table name "state; DROP TABLE users"`)
+
+	_, err = NewSQLiteStore(db, "payouts_state")
+	assert.EqualError(t, err, "unexpected statement: CREATE TABLE IF NOT EXISTS payouts_state (key TEXT PRIMARY KEY, value BLOB NOT NULL)")
+}
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Equal(t, "idempotency0", prefixEnd("idempotency/"))
+	assert.Equal(t, "b", prefixEnd("a\xff"))
+	assert.Equal(t, "", prefixEnd("\xff\xff"))
+	assert.Equal(t, "", prefixEnd(""))
+}
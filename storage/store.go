@@ -0,0 +1,242 @@
+// Package storage defines the persistence shared by the stateful subsystems of the
+// client, such as the idempotency records of payments, the offline queue and the
+// balance history, so that an application can back all of them with its own database
+// by implementing a single interface. Stores are provided in memory, in a JSON file
+// and in a SQLite database.
+package storage
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned by a Store when no value is stored at a key.
+var ErrNotFound = errors.New("no value stored at key")
+
+// Entry is a value of a Store along with its key.
+type Entry struct {
+	Key   string
+	Value []byte
+}
+
+// Store is a generic interface to persist values at keys, the subsystems using it
+// prefixing their keys with their name, e.g. "idempotency/42". Get returns ErrNotFound
+// for a key that was never stored or was deleted, Delete does nothing for such a key,
+// and List returns the entries whose key starts with the prefix, sorted by key.
+// Stores must be safe for concurrent use.
+type Store interface {
+	Get(key string) ([]byte, error)
+	Put(key string, value []byte) error
+	Delete(key string) error
+	List(prefix string) ([]*Entry, error)
+}
+
+// NewMemoryStore creates a Store that only keeps values in memory, values will not
+// survive a restart of the process.
+func NewMemoryStore() Store {
+	return &memoryStore{
+		values: make(map[string][]byte),
+	}
+}
+
+type memoryStore struct {
+	mutex  sync.Mutex
+	values map[string][]byte
+}
+
+func (store *memoryStore) Get(key string) ([]byte, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	value, ok := store.values[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return copyValue(value), nil
+}
+
+func (store *memoryStore) Put(key string, value []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.values[key] = copyValue(value)
+
+	return nil
+}
+
+func (store *memoryStore) Delete(key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.values, key)
+
+	return nil
+}
+
+func (store *memoryStore) List(prefix string) ([]*Entry, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	return list(store.values, prefix), nil
+}
+
+// NewFileStore creates a Store that persists values as JSON in the file at the given
+// path, so that they outlive the process. The whole file is read and written for
+// every operation, which suits the small state of a single service.
+func NewFileStore(path string) Store {
+	return &fileStore{
+		path: path,
+	}
+}
+
+type fileStore struct {
+	mutex sync.Mutex
+	path  string
+}
+
+func (store *fileStore) Get(key string) ([]byte, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	values, err := store.read()
+	if err != nil {
+		return nil, err
+	}
+
+	value, ok := values[key]
+	if !ok {
+		return nil, ErrNotFound
+	}
+
+	return value, nil
+}
+
+func (store *fileStore) Put(key string, value []byte) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	values, err := store.read()
+	if err != nil {
+		return err
+	}
+
+	values[key] = value
+
+	return store.write(values)
+}
+
+func (store *fileStore) Delete(key string) error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	values, err := store.read()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := values[key]; !ok {
+		return nil
+	}
+
+	delete(values, key)
+
+	return store.write(values)
+}
+
+func (store *fileStore) List(prefix string) ([]*Entry, error) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	values, err := store.read()
+	if err != nil {
+		return nil, err
+	}
+
+	return list(values, prefix), nil
+}
+
+// Compact will write the file again, removing the temporary file a crash during a
+// write may have left behind.
+func (store *fileStore) Compact() error {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	values, err := store.read()
+	if err != nil {
+		return err
+	}
+
+	if err = os.Remove(store.path + ".tmp"); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return store.write(values)
+}
+
+func (store *fileStore) read() (map[string][]byte, error) {
+	var (
+		err      error
+		contents []byte
+		values   = make(map[string][]byte)
+	)
+
+	if contents, err = ioutil.ReadFile(store.path); err != nil {
+		if os.IsNotExist(err) {
+			return values, nil
+		}
+
+		return nil, err
+	}
+
+	if err = json.Unmarshal(contents, &values); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// write will replace the file atomically so that a crash never leaves a partially
+// written store behind.
+func (store *fileStore) write(values map[string][]byte) error {
+	var (
+		err      error
+		contents []byte
+		tmpPath  = store.path + ".tmp"
+	)
+
+	if contents, err = json.Marshal(values); err != nil {
+		return err
+	}
+
+	if err = ioutil.WriteFile(tmpPath, contents, 0600); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpPath, store.path)
+}
+
+func list(values map[string][]byte, prefix string) []*Entry {
+	var entries = make([]*Entry, 0)
+
+	for key, value := range values {
+		if strings.HasPrefix(key, prefix) {
+			entries = append(entries, &Entry{Key: key, Value: copyValue(value)})
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key < entries[j].Key
+	})
+
+	return entries
+}
+
+func copyValue(value []byte) []byte {
+	return append([]byte{}, value...)
+}
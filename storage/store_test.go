@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewSQLiteStore() {
+	// the driver is registered by the application, e.g. with
+	// import _ "github.com/mattn/go-sqlite3"
+	db, err := sql.Open("sqlite3", "/var/lib/payouts/state.db")
+	if err != nil {
+		panic(err)
+	}
+
+	store, err := NewSQLiteStore(db, "")
+	if err != nil {
+		panic(err)
+	}
+
+	if err = store.Put("invoices/42", []byte(`{"amount":10}`)); err != nil {
+		panic(err)
+	}
+}
+
+func TestStores(t *testing.T) {
+	var (
+		dir, err = ioutil.TempDir("", "storage")
+		path     string
+	)
+
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path = filepath.Join(dir, "state.json")
+
+	stores := map[string]func() Store{
+		"memory": NewMemoryStore,
+		"file": func() Store {
+			return NewFileStore(path)
+		},
+		"sqlite": func() Store {
+			db, err := sql.Open(fakeDriverName, "stores")
+			require.NoError(t, err)
+
+			store, err := NewSQLiteStore(db, "")
+			require.NoError(t, err)
+
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			var (
+				store   = newStore()
+				value   []byte
+				entries []*Entry
+				err     error
+			)
+
+			_, err = store.Get("idempotency/1")
+			assert.Equal(t, ErrNotFound, err)
+
+			require.NoError(t, store.Put("idempotency/1", []byte("pending")))
+			require.NoError(t, store.Put("idempotency/1", []byte("succeeded")))
+			require.NoError(t, store.Put("idempotency/2", []byte("failed")))
+			require.NoError(t, store.Put("offline/a", []byte("queued")))
+
+			value, err = store.Get("idempotency/1")
+			require.NoError(t, err)
+			assert.Equal(t, "succeeded", string(value))
+
+			entries, err = store.List("idempotency/")
+			require.NoError(t, err)
+			assert.Equal(t, []*Entry{
+				&Entry{Key: "idempotency/1", Value: []byte("succeeded")},
+				&Entry{Key: "idempotency/2", Value: []byte("failed")},
+			}, entries)
+
+			require.NoError(t, store.Delete("idempotency/2"))
+			require.NoError(t, store.Delete("idempotency/3"))
+
+			_, err = store.Get("idempotency/2")
+			assert.Equal(t, ErrNotFound, err)
+
+			entries, err = store.List("")
+			require.NoError(t, err)
+			require.Len(t, entries, 2)
+			assert.Equal(t, "offline/a", entries[1].Key)
+		})
+	}
+
+	t.Run("file store survives a restart", func(t *testing.T) {
+		value, err := NewFileStore(path).Get("offline/a")
+
+		require.NoError(t, err)
+		assert.Equal(t, "queued", string(value))
+	})
+}
+
+func TestMemoryStoreCopies(t *testing.T) {
+	var (
+		store = NewMemoryStore()
+		value = []byte("pending")
+	)
+
+	require.NoError(t, store.Put("idempotency/1", value))
+	value[0] = 'P'
+
+	stored, err := store.Get("idempotency/1")
+	require.NoError(t, err)
+	assert.Equal(t, "pending", string(stored))
+}
@@ -0,0 +1,62 @@
+package storage
+
+import "strings"
+
+// TenantPrefix is the prefix of the keys of a tenant in a store shared by tenants,
+// followed by the tenant and a slash, see ForTenant.
+const TenantPrefix = "tenants/"
+
+// ForTenant returns a Store keeping the values of the tenant apart from the ones of
+// other tenants in the backend, prefixing its keys with TenantPrefix and the tenant,
+// so that the subsystems of a payment service shared by internal customers can be
+// given one store per customer while backed by a single database. The backend itself
+// is returned for an empty tenant.
+func ForTenant(backend Store, tenant string) Store {
+	if tenant == "" {
+		return backend
+	}
+
+	return &tenantStore{
+		backend: backend,
+		prefix:  TenantPrefix + tenant + "/",
+	}
+}
+
+type tenantStore struct {
+	backend Store
+	prefix  string
+}
+
+func (store *tenantStore) Get(key string) ([]byte, error) {
+	return store.backend.Get(store.prefix + key)
+}
+
+func (store *tenantStore) Put(key string, value []byte) error {
+	return store.backend.Put(store.prefix+key, value)
+}
+
+func (store *tenantStore) Delete(key string) error {
+	return store.backend.Delete(store.prefix + key)
+}
+
+// Compact compacts the backend, shared with the other tenants.
+func (store *tenantStore) Compact() error {
+	if compactor, ok := store.backend.(Compactor); ok {
+		return compactor.Compact()
+	}
+
+	return nil
+}
+
+func (store *tenantStore) List(prefix string) ([]*Entry, error) {
+	entries, err := store.backend.List(store.prefix + prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, entry := range entries {
+		entries[i] = &Entry{Key: strings.TrimPrefix(entry.Key, store.prefix), Value: entry.Value}
+	}
+
+	return entries, nil
+}
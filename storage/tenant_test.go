@@ -0,0 +1,44 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForTenant(t *testing.T) {
+	var (
+		backend = NewMemoryStore()
+		acme    = ForTenant(backend, "acme")
+		globex  = ForTenant(backend, "globex")
+	)
+
+	assert.Equal(t, backend, ForTenant(backend, ""))
+
+	require.NoError(t, acme.Put("idempotency/1", []byte("succeeded")))
+	require.NoError(t, globex.Put("idempotency/1", []byte("pending")))
+	require.NoError(t, globex.Put("idempotency/2", []byte("failed")))
+
+	value, err := acme.Get("idempotency/1")
+	require.NoError(t, err)
+	assert.Equal(t, "succeeded", string(value))
+
+	_, err = acme.Get("idempotency/2")
+	assert.Equal(t, ErrNotFound, err)
+
+	entries, err := globex.List("idempotency/")
+	require.NoError(t, err)
+	assert.Equal(t, []*Entry{
+		&Entry{Key: "idempotency/1", Value: []byte("pending")},
+		&Entry{Key: "idempotency/2", Value: []byte("failed")},
+	}, entries)
+
+	require.NoError(t, globex.Delete("idempotency/1"))
+
+	entries, err = backend.List(TenantPrefix)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "tenants/acme/idempotency/1", entries[0].Key)
+	assert.Equal(t, "tenants/globex/idempotency/2", entries[1].Key)
+}
@@ -0,0 +1,323 @@
+// Package support gathers what is needed to investigate a bug report about a Raiden
+// node or the client into a single archive: the address, version and block height of
+// the node, its channels, connections and pending transfers, its recent payment
+// events, the recent errors of the client and its configuration, with the credentials
+// left out.
+package support
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/connections"
+	"github.com/cpurta/go-raiden-client/node"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pending_transfers"
+	"github.com/cpurta/go-raiden-client/redact"
+	"github.com/cpurta/go-raiden-client/version"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// DefaultEventsWindow is how far back the payment events of the node are gathered when
+// the options give no window.
+const DefaultEventsWindow = 24 * time.Hour
+
+// The files of a support bundle.
+const (
+	FileManifest         = "manifest.json"
+	FileNode             = "node.json"
+	FileChannels         = "channels.json"
+	FileConnections      = "connections.json"
+	FilePendingTransfers = "pending_transfers.json"
+	FilePaymentEvents    = "payment_events.json"
+	FileErrors           = "errors.json"
+	FileConfig           = "config.json"
+)
+
+// Options configures a support bundle: the payment events logged within EventsWindow
+// are gathered, along with the errors of ErrorLog when it is not nil.
+type Options struct {
+	EventsWindow time.Duration
+	ErrorLog     *ErrorLog
+}
+
+// Manifest describes a support bundle, with the error of every file whose content the
+// node could not give, so that a bundle is made even when the node is failing.
+type Manifest struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Files     []string          `json:"files"`
+	Failures  map[string]string `json:"failures,omitempty"`
+}
+
+// Bundler is a generic interface to make support bundles of a Raiden node.
+type Bundler interface {
+	SupportBundle(ctx context.Context, writer io.Writer) error
+}
+
+var _ Bundler = &defaultBundler{}
+
+// NewBundler creates a default support bundler given a Raiden node configuration, the
+// options of the bundles and an http client.
+func NewBundler(config *config.Config, options *Options, httpClient *http.Client) Bundler {
+	if options == nil {
+		options = &Options{}
+	}
+
+	return &defaultBundler{
+		config:           config,
+		options:          options,
+		addressGetter:    address.NewGetter(config, httpClient),
+		versionGetter:    version.NewGetter(config, httpClient),
+		syncInfoGetter:   node.NewSyncInfoGetter(config, httpClient),
+		channelLister:    channels.NewLister(config, httpClient),
+		connectionLister: connections.NewLister(config, httpClient),
+		transferLister:   pendingtransfers.NewLister(config, httpClient),
+		paymentLister:    payments.NewLister(config, httpClient),
+	}
+}
+
+type defaultBundler struct {
+	config           *config.Config
+	options          *Options
+	addressGetter    address.Getter
+	versionGetter    version.Getter
+	syncInfoGetter   node.SyncInfoGetter
+	channelLister    channels.Lister
+	connectionLister connections.Lister
+	transferLister   pendingtransfers.Lister
+	paymentLister    payments.Lister
+}
+
+// nodeInfo, paymentEvent and the connections hold the addresses as checksummed hex
+// strings, the format of the Raiden node API.
+type nodeInfo struct {
+	Address string         `json:"address"`
+	Version string         `json:"version"`
+	Sync    *node.SyncInfo `json:"sync,omitempty"`
+}
+
+type paymentEvent struct {
+	TokenAddress   string          `json:"token_address"`
+	PartnerAddress string          `json:"partner_address"`
+	Event          *payments.Event `json:"event"`
+}
+
+type sanitizedConfig struct {
+	Host             string                           `json:"host"`
+	BasePath         string                           `json:"base_path,omitempty"`
+	APIVersion       string                           `json:"api_version"`
+	DecodeMode       config.DecodeMode                `json:"decode_mode"`
+	NodeVersion      string                           `json:"node_version,omitempty"`
+	FieldAliases     map[string]map[string]string     `json:"field_aliases,omitempty"`
+	RequestTimeout   string                           `json:"request_timeout"`
+	EndpointTimeouts map[config.Endpoint]string       `json:"endpoint_timeouts,omitempty"`
+	Middlewares      int                              `json:"middlewares"`
+	MetadataHeaders  map[string]string                `json:"metadata_headers,omitempty"`
+	Tenant           string                           `json:"tenant,omitempty"`
+	MarkerHeader     string                           `json:"marker_header,omitempty"`
+	SyncRetry        *syncRetry                       `json:"sync_retry,omitempty"`
+	TokenDefaults    map[string]*config.TokenDefaults `json:"token_defaults,omitempty"`
+}
+
+type syncRetry struct {
+	Budget         string `json:"budget"`
+	InitialBackoff string `json:"initial_backoff"`
+	MaxBackoff     string `json:"max_backoff"`
+}
+
+// SupportBundle will write a zip archive of the files of a support bundle to the
+// writer, see Manifest. An error is only returned when the archive could not be
+// written, the failures of the node being recorded in the manifest.
+func (bundler *defaultBundler) SupportBundle(ctx context.Context, writer io.Writer) error {
+	var (
+		err            error
+		ourAddress     common.Address
+		connectionList connections.Connections
+		archive        = zip.NewWriter(writer)
+		manifest       = &Manifest{CreatedAt: time.Now().UTC(), Failures: make(map[string]string)}
+		files          = make(map[string]interface{})
+		ourNode        = &nodeInfo{}
+		channelList    []*channels.Channel
+	)
+
+	if ourAddress, err = bundler.addressGetter.Get(ctx); err != nil {
+		manifest.Failures[FileNode] = redact.String(err.Error())
+	} else {
+		ourNode.Address = ourAddress.Hex()
+
+		if ourNode.Version, err = bundler.versionGetter.Get(ctx); err != nil {
+			manifest.Failures[FileNode] = redact.String(err.Error())
+		}
+	}
+
+	// the block height tells how stale the rest of the bundle may be
+	if ourNode.Sync, err = bundler.syncInfoGetter.SyncInfo(ctx); err != nil && manifest.Failures[FileNode] == "" {
+		manifest.Failures[FileNode] = redact.String(err.Error())
+	}
+
+	files[FileNode] = ourNode
+
+	if channelList, err = bundler.channelLister.ListAll(ctx); err != nil {
+		manifest.Failures[FileChannels] = redact.String(err.Error())
+	}
+
+	files[FileChannels] = channelList
+
+	if connectionList, err = bundler.connectionLister.List(ctx); err != nil {
+		manifest.Failures[FileConnections] = redact.String(err.Error())
+	}
+
+	connectionsByToken := make(map[string]*connections.Connection)
+
+	for tokenAddress, connection := range connectionList {
+		connectionsByToken[tokenAddress.Hex()] = connection
+	}
+
+	files[FileConnections] = connectionsByToken
+
+	if files[FilePendingTransfers], err = bundler.transferLister.ListAll(ctx); err != nil {
+		manifest.Failures[FilePendingTransfers] = redact.String(err.Error())
+	}
+
+	if files[FilePaymentEvents], err = bundler.paymentEvents(ctx, channelList); err != nil {
+		manifest.Failures[FilePaymentEvents] = redact.String(err.Error())
+	}
+
+	files[FileErrors] = []*ErrorEntry{}
+
+	if bundler.options.ErrorLog != nil {
+		files[FileErrors] = bundler.options.ErrorLog.Recent()
+	}
+
+	files[FileConfig] = sanitize(bundler.config)
+
+	for _, name := range []string{FileNode, FileChannels, FileConnections, FilePendingTransfers, FilePaymentEvents, FileErrors, FileConfig} {
+		if err = writeFile(archive, name, files[name]); err != nil {
+			return err
+		}
+
+		manifest.Files = append(manifest.Files, name)
+	}
+
+	if err = writeFile(archive, FileManifest, manifest); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+// paymentEvents lists the payment events of every channel logged within the window,
+// along with the first error, if any.
+func (bundler *defaultBundler) paymentEvents(ctx context.Context, channelList []*channels.Channel) ([]*paymentEvent, error) {
+	var (
+		firstErr error
+		window   = bundler.options.EventsWindow
+		events   = make([]*paymentEvent, 0)
+		listed   = make(map[[2]common.Address]bool)
+	)
+
+	if window <= 0 {
+		window = DefaultEventsWindow
+	}
+
+	since := time.Now().Add(-window)
+
+	for _, channel := range channelList {
+		// the events of a partner are listed once, whatever its number of channels
+		key := [2]common.Address{channel.TokenAddress, channel.PartnerAddress}
+		if listed[key] {
+			continue
+		}
+
+		listed[key] = true
+
+		channelEvents, err := bundler.paymentLister.List(ctx, channel.TokenAddress, channel.PartnerAddress)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			continue
+		}
+
+		for _, event := range channelEvents {
+			if event.LogTime.After(since) {
+				events = append(events, &paymentEvent{TokenAddress: channel.TokenAddress.Hex(), PartnerAddress: channel.PartnerAddress.Hex(), Event: event})
+			}
+		}
+	}
+
+	return events, firstErr
+}
+
+func writeFile(archive *zip.Writer, name string, content interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(content)
+}
+
+// sanitize returns the configuration without the credentials the host may hold, nor
+// the middlewares, which are only counted, with the durations as strings.
+func sanitize(nodeConfig *config.Config) *sanitizedConfig {
+	if nodeConfig == nil {
+		return nil
+	}
+
+	var sanitized = &sanitizedConfig{
+		Host:            nodeConfig.Host,
+		BasePath:        nodeConfig.BasePath,
+		APIVersion:      nodeConfig.APIVersion,
+		DecodeMode:      nodeConfig.DecodeMode,
+		NodeVersion:     nodeConfig.NodeVersion,
+		FieldAliases:    nodeConfig.FieldAliases,
+		RequestTimeout:  nodeConfig.RequestTimeout.String(),
+		Middlewares:     len(nodeConfig.Middlewares),
+		MetadataHeaders: nodeConfig.MetadataHeaders,
+		Tenant:          nodeConfig.Tenant,
+		MarkerHeader:    nodeConfig.MarkerHeader,
+	}
+
+	if hostURL, err := url.Parse(nodeConfig.Host); err == nil {
+		sanitized.Host = sanitizeURL(hostURL)
+	}
+
+	if len(nodeConfig.EndpointTimeouts) > 0 {
+		sanitized.EndpointTimeouts = make(map[config.Endpoint]string)
+
+		for endpoint, timeout := range nodeConfig.EndpointTimeouts {
+			sanitized.EndpointTimeouts[endpoint] = timeout.String()
+		}
+	}
+
+	if len(nodeConfig.TokenDefaults) > 0 {
+		sanitized.TokenDefaults = make(map[string]*config.TokenDefaults)
+
+		for tokenAddress, defaults := range nodeConfig.TokenDefaults {
+			sanitized.TokenDefaults[tokenAddress.Hex()] = defaults
+		}
+	}
+
+	if retry := nodeConfig.SyncRetry; retry != nil {
+		sanitized.SyncRetry = &syncRetry{
+			Budget:         retry.Budget.String(),
+			InitialBackoff: retry.InitialBackoff.String(),
+			MaxBackoff:     retry.MaxBackoff.String(),
+		}
+	}
+
+	return sanitized
+}
@@ -0,0 +1,127 @@
+package support
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/redact"
+)
+
+// DefaultErrorLogSize is the number of errors an error log keeps when it is created
+// without a size.
+const DefaultErrorLogSize = 100
+
+// maxMessage is the length of the body of a failed response kept as its message.
+const maxMessage = 1024
+
+// ErrorEntry is a request of the client that failed, with the URL stripped of its
+// credentials and query, and the start of the body of the response of the node or the
+// error of the transport as its message, with the secrets it holds redacted, see
+// redact.String.
+type ErrorEntry struct {
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method"`
+	URL        string    `json:"url"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Message    string    `json:"message"`
+}
+
+// ErrorLog keeps the most recent requests of a client that failed, once its
+// Middleware is added to the Middlewares of the configuration, for support bundles.
+type ErrorLog struct {
+	mutex   sync.Mutex
+	size    int
+	entries []*ErrorEntry
+}
+
+// NewErrorLog creates an error log keeping the last size errors, DefaultErrorLogSize
+// when size is not positive.
+func NewErrorLog(size int) *ErrorLog {
+	if size <= 0 {
+		size = DefaultErrorLogSize
+	}
+
+	return &ErrorLog{size: size}
+}
+
+// Middleware returns the middleware recording the requests failing with a transport
+// error or a response of 400 or above.
+func (errorLog *ErrorLog) Middleware() config.Middleware {
+	return func(next config.Doer) config.Doer {
+		return func(request *http.Request) (*http.Response, error) {
+			response, err := next(request)
+
+			switch {
+			case err != nil:
+				errorLog.add(&ErrorEntry{Method: request.Method, URL: sanitizeURL(request.URL), Message: redact.String(err.Error())})
+			case response.StatusCode >= http.StatusBadRequest:
+				// the start of the body is read for the message, and given back to the
+				// client along with the rest
+				message, _ := ioutil.ReadAll(io.LimitReader(response.Body, maxMessage))
+				response.Body = &replayedBody{Reader: io.MultiReader(bytes.NewReader(message), response.Body), Closer: response.Body}
+
+				errorLog.add(&ErrorEntry{Method: request.Method, URL: sanitizeURL(request.URL), StatusCode: response.StatusCode, Message: redact.String(string(message))})
+			}
+
+			return response, err
+		}
+	}
+}
+
+// Record records an error of the application, e.g. one that did not come from a
+// request.
+func (errorLog *ErrorLog) Record(err error) {
+	errorLog.add(&ErrorEntry{Message: redact.String(err.Error())})
+}
+
+// Recent returns the errors recorded, oldest first.
+func (errorLog *ErrorLog) Recent() []*ErrorEntry {
+	errorLog.mutex.Lock()
+	defer errorLog.mutex.Unlock()
+
+	return append([]*ErrorEntry{}, errorLog.entries...)
+}
+
+func (errorLog *ErrorLog) add(entry *ErrorEntry) {
+	var size = errorLog.size
+
+	if size <= 0 {
+		size = DefaultErrorLogSize
+	}
+
+	errorLog.mutex.Lock()
+	defer errorLog.mutex.Unlock()
+
+	entry.Time = time.Now()
+	errorLog.entries = append(errorLog.entries, entry)
+
+	if len(errorLog.entries) > size {
+		errorLog.entries = errorLog.entries[len(errorLog.entries)-size:]
+	}
+}
+
+type replayedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// sanitizeURL returns the URL without its user info nor its query, which may hold
+// credentials.
+func sanitizeURL(requestURL *url.URL) string {
+	if requestURL == nil {
+		return ""
+	}
+
+	sanitized := *requestURL
+	sanitized.User = nil
+	sanitized.RawQuery = ""
+	sanitized.ForceQuery = false
+
+	return sanitized.String()
+}
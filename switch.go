@@ -0,0 +1,39 @@
+package raidenclient
+
+import "sync"
+
+// Switch is a facade shared by the parts of an application whose Raiden node can be
+// replaced while they use it, e.g. by a node migration. Every call to Client returns
+// the client of the current node, callers must not keep it between calls so that
+// they are switched over with the rest of the traffic.
+type Switch struct {
+	mutex  sync.RWMutex
+	client *Client
+}
+
+// NewSwitch creates a switch whose traffic goes to the client.
+func NewSwitch(client *Client) *Switch {
+	return &Switch{
+		client: client,
+	}
+}
+
+// Client returns the client of the node the traffic currently goes to.
+func (facade *Switch) Client() *Client {
+	facade.mutex.RLock()
+	defer facade.mutex.RUnlock()
+
+	return facade.client
+}
+
+// Swap switches the traffic over to the client at once, returning the client it went
+// to before.
+func (facade *Switch) Swap(client *Client) *Client {
+	facade.mutex.Lock()
+	defer facade.mutex.Unlock()
+
+	previous := facade.client
+	facade.client = client
+
+	return previous
+}
@@ -0,0 +1,37 @@
+package raidenclient
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSwitch(t *testing.T) {
+	var (
+		oldClient = NewClient(&config.Config{Host: "http://old:5001", APIVersion: "v1"}, nil)
+		newClient = NewClient(&config.Config{Host: "http://new:5001", APIVersion: "v1"}, nil)
+		facade    = NewSwitch(oldClient)
+		waitGroup sync.WaitGroup
+	)
+
+	assert.Equal(t, oldClient, facade.Client())
+
+	// callers reading the switch while it is swapped see one client or the other
+	for i := 0; i < 10; i++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			client := facade.Client()
+			assert.True(t, client == oldClient || client == newClient)
+		}()
+	}
+
+	assert.Equal(t, oldClient, facade.Swap(newClient))
+	waitGroup.Wait()
+
+	assert.Equal(t, newClient, facade.Client())
+}
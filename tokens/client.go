@@ -4,13 +4,15 @@ import (
 	"net/http"
 
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
 )
 
 var (
-	_ Lister        = &Client{}
-	_ PartnerLister = &Client{}
-	_ Getter        = &Client{}
-	_ Registrar     = &Client{}
+	_ Lister         = &Client{}
+	_ PartnerLister  = &Client{}
+	_ Getter         = &Client{}
+	_ Registrar      = &Client{}
+	_ util.RawCaller = &Client{}
 )
 
 func NewClient(config *config.Config, httpClient *http.Client) *Client {
@@ -19,6 +21,7 @@ func NewClient(config *config.Config, httpClient *http.Client) *Client {
 		PartnerLister: NewPartnerLister(config, httpClient),
 		Getter:        NewGetter(config, httpClient),
 		Registrar:     NewRegistrar(config, httpClient),
+		RawCaller:     util.NewRawCaller(config, httpClient),
 	}
 }
 
@@ -27,4 +30,5 @@ type Client struct {
 	PartnerLister
 	Getter
 	Registrar
+	util.RawCaller
 }
@@ -2,11 +2,10 @@ package tokens
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 
+	"github.com/cpurta/go-raiden-client/address"
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/cpurta/go-raiden-client/util"
 	"github.com/ethereum/go-ethereum/common"
@@ -16,7 +15,7 @@ import (
 // Raiden node. It allows for a context to be passed to allow for request timeouts
 // and/or deadlines on the response.
 type Getter interface {
-	Get(ctx context.Context, address common.Address) (common.Address, error)
+	Get(ctx context.Context, tokenAddress common.Address) (address.TokenNetworkAddress, error)
 }
 
 var _ Getter = &defaultGetter{}
@@ -36,53 +35,58 @@ type defaultGetter struct {
 }
 
 // List will return the associated Ethereum address to the Raiden node configured
-// in the Getter config.
-func (Getter *defaultGetter) Get(ctx context.Context, tokenAddress common.Address) (common.Address, error) {
+// in the Getter config. The address is kept in the Cache of the config when it has
+// one, a token network never moving once registered.
+func (Getter *defaultGetter) Get(ctx context.Context, tokenAddress common.Address) (address.TokenNetworkAddress, error) {
 	var (
 		err            error
-		address        string
-		networkAddress = common.Address{}
+		hexAddress     string
+		networkAddress = address.TokenNetworkAddress{}
 
 		requestURL *url.URL
-		request    *http.Request
-		response   *http.Response
 	)
 
-	if requestURL, err = Getter.getRequestURL(tokenAddress); err != nil {
-		return networkAddress, err
+	if cached, ok := cachedNetwork(Getter.baseClient.Config, tokenAddress); ok {
+		return cached, nil
 	}
 
-	if request, err = http.NewRequest("GET", requestURL.String(), nil); err != nil {
+	if requestURL, err = Getter.baseClient.Endpoint("tokens/%s", tokenAddress.Hex()); err != nil {
 		return networkAddress, err
 	}
 
-	request = request.WithContext(ctx)
-
-	if response, err = Getter.baseClient.HTTPClient.Do(request); err != nil {
+	if err = Getter.baseClient.Call(ctx, "GET", requestURL, nil, &hexAddress); err != nil {
 		return networkAddress, err
 	}
 
-	defer response.Body.Close()
+	networkAddress = address.HexToTokenNetworkAddress(hexAddress)
+	cacheNetwork(Getter.baseClient.Config, tokenAddress, networkAddress)
 
-	if err = json.NewDecoder(response.Body).Decode(&address); err != nil {
-		return networkAddress, err
+	return networkAddress, nil
+}
+
+// networkKey is the key of the token network address of a token in the Cache of a
+// configuration.
+const networkKey = "tokens/network"
+
+func cachedNetwork(config *config.Config, tokenAddress common.Address) (address.TokenNetworkAddress, bool) {
+	if config == nil || config.Cache == nil {
+		return address.TokenNetworkAddress{}, false
 	}
 
-	networkAddress = common.HexToAddress(address)
+	value, ok := config.Cache.Load(tokenAddress, networkKey)
+	if !ok {
+		return address.TokenNetworkAddress{}, false
+	}
 
-	return networkAddress, nil
+	return value.(address.TokenNetworkAddress), true
 }
 
-func (Getter *defaultGetter) getRequestURL(address common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/tokens/%s", Getter.baseClient.Config.Host, Getter.baseClient.Config.APIVersion, address.Hex())
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
+// cacheNetwork stores the token network address of the token in the Cache of the
+// configuration, unless it is the zero address of a token with no network.
+func cacheNetwork(config *config.Config, tokenAddress common.Address, networkAddress address.TokenNetworkAddress) {
+	if config == nil || config.Cache == nil || networkAddress == (address.TokenNetworkAddress{}) {
+		return
 	}
 
-	return requestURL, nil
+	config.Cache.Store(tokenAddress, networkKey, networkAddress)
 }
@@ -8,6 +8,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/cpurta/go-raiden-client/address"
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jarcoal/httpmock"
@@ -22,18 +23,18 @@ func ExampleGetter() {
 			Host:       "http://localhost:5001",
 			APIVersion: "v1",
 		}
-		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
-		address      common.Address
-		err          error
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		networkAddress address.TokenNetworkAddress
+		err            error
 	)
 
 	tokenClient = NewClient(config, http.DefaultClient)
 
-	if address, err = tokenClient.Get(context.Background(), tokenAddress); err != nil {
+	if networkAddress, err = tokenClient.Get(context.Background(), tokenAddress); err != nil {
 		panic(fmt.Sprintf("unable to get address: %s", err.Error()))
 	}
 
-	fmt.Printf("address: %s\n", address.String())
+	fmt.Printf("address: %s\n", networkAddress.String())
 }
 
 func TestGetter(t *testing.T) {
@@ -52,7 +53,7 @@ func TestGetter(t *testing.T) {
 	type testcase struct {
 		name            string
 		prepHTTPMock    func()
-		expectedAddress common.Address
+		expectedAddress address.TokenNetworkAddress
 		expectedError   error
 	}
 
@@ -70,7 +71,7 @@ func TestGetter(t *testing.T) {
 				)
 			},
 			expectedError:   nil,
-			expectedAddress: common.HexToAddress("0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"),
+			expectedAddress: address.HexToTokenNetworkAddress("0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"),
 		},
 		testcase{
 			name: "unexpected 500 response",
@@ -85,7 +86,7 @@ func TestGetter(t *testing.T) {
 				)
 			},
 			expectedError:   errors.New("EOF"),
-			expectedAddress: common.Address{},
+			expectedAddress: address.TokenNetworkAddress{},
 		},
 		testcase{
 			name: "unable to make http request",
@@ -93,16 +94,16 @@ func TestGetter(t *testing.T) {
 				httpmock.Deactivate()
 			},
 			expectedError:   fmt.Errorf("Get http://localhost:5001/api/v1/tokens/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8: dial tcp %s:5001: connect: connection refused", localhostIP),
-			expectedAddress: common.Address{},
+			expectedAddress: address.TokenNetworkAddress{},
 		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			var (
-				err          error
-				address      common.Address
-				tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+				err            error
+				networkAddress address.TokenNetworkAddress
+				tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
 
 				getter = NewGetter(config, http.DefaultClient)
 				ctx    = context.Background()
@@ -113,15 +114,51 @@ func TestGetter(t *testing.T) {
 
 			tc.prepHTTPMock()
 
-			address, err = getter.Get(ctx, tokenAddress)
+			networkAddress, err = getter.Get(ctx, tokenAddress)
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Zero(t, networkAddress)
 				return
 			}
 
 			require.NoError(t, err)
-			assert.Equal(t, tc.expectedAddress, address)
+			assert.Equal(t, tc.expectedAddress, networkAddress)
 		})
 	}
 }
+
+func TestGetterCache(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+			Cache:      config.NewCache(),
+		}
+		ctx          = context.Background()
+		tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		tokenURL     = "http://localhost:5001/api/v1/tokens/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		expected     = address.HexToTokenNetworkAddress("0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6")
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("PUT", tokenURL, httpmock.NewStringResponder(http.StatusCreated, `{"token_network_address":"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"}`))
+	httpmock.RegisterResponder("GET", tokenURL, httpmock.NewStringResponder(http.StatusOK, `"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"`))
+
+	_, err := NewRegistrar(config, http.DefaultClient).Register(ctx, tokenAddress)
+	require.NoError(t, err)
+
+	networkAddress, err := NewGetter(config, http.DefaultClient).Get(ctx, tokenAddress)
+	require.NoError(t, err)
+	assert.Equal(t, expected, networkAddress)
+	assert.Equal(t, 0, httpmock.GetCallCountInfo()["GET "+tokenURL], "the registered network is cached")
+
+	config.Cache.Invalidate(tokenAddress, "")
+
+	networkAddress, err = NewGetter(config, http.DefaultClient).Get(ctx, tokenAddress)
+	require.NoError(t, err)
+	assert.Equal(t, expected, networkAddress)
+	assert.Equal(t, 1, httpmock.GetCallCountInfo()["GET "+tokenURL])
+}
@@ -0,0 +1,25 @@
+//go:build go1.23
+// +build go1.23
+
+package tokens
+
+import (
+	"context"
+	"iter"
+
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// All returns an iterator over the addresses of the tokens registered on the node,
+// listed with the lister once the iteration starts, see util.Iterate.
+func All(ctx context.Context, lister Lister) iter.Seq2[common.Address, error] {
+	return util.Iterate(ctx, lister.List)
+}
+
+// AllPartners returns an iterator over the partners of the token, see All.
+func AllPartners(ctx context.Context, lister PartnerLister, tokenAddress common.Address) iter.Seq2[*Partner, error] {
+	return util.Iterate(ctx, func(ctx context.Context) ([]*Partner, error) {
+		return lister.ListPartners(ctx, tokenAddress)
+	})
+}
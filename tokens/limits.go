@@ -0,0 +1,181 @@
+package tokens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Reasons a deposit is refused by the limits of a token network.
+const (
+	// ReasonDeprecated is an open or a deposit on a token network whose safety
+	// deprecation switch is on.
+	ReasonDeprecated = "deprecated"
+	// ReasonParticipantDepositLimit is a total deposit above the limit of a participant
+	// of a channel.
+	ReasonParticipantDepositLimit = "participant_deposit_limit"
+	// ReasonNetworkDepositLimit is a deposit that would take the tokens held by the
+	// token network above its limit.
+	ReasonNetworkDepositLimit = "network_deposit_limit"
+)
+
+// ContractCaller is the part of an Ethereum client the token network contracts are
+// read with, which *ethclient.Client implements.
+type ContractCaller interface {
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+}
+
+// Limits are the deposit limits and deprecation switch of the token network of a
+// token, along with the tokens the network holds, which its deposit limit bounds.
+type Limits struct {
+	TokenAddress                   common.Address
+	TokenNetworkAddress            address.TokenNetworkAddress
+	ChannelParticipantDepositLimit *big.Int
+	TokenNetworkDepositLimit       *big.Int
+	TokenNetworkDeposit            *big.Int
+	Deprecated                     bool
+}
+
+// Validate returns a *LimitError when the token network would refuse to raise the
+// total deposit of a participant of a channel from the current one to the total
+// deposit, a zero total deposit being only refused by a deprecated network.
+func (limits *Limits) Validate(currentDeposit, totalDeposit *big.Int) error {
+	var added = new(big.Int).Sub(totalDeposit, currentDeposit)
+
+	switch {
+	case limits.Deprecated:
+		return &LimitError{TotalDeposit: totalDeposit, Limits: limits, Reason: ReasonDeprecated}
+	case totalDeposit.Cmp(limits.ChannelParticipantDepositLimit) > 0:
+		return &LimitError{TotalDeposit: totalDeposit, Limits: limits, Reason: ReasonParticipantDepositLimit}
+	case added.Sign() > 0 && new(big.Int).Add(limits.TokenNetworkDeposit, added).Cmp(limits.TokenNetworkDepositLimit) > 0:
+		return &LimitError{TotalDeposit: totalDeposit, Limits: limits, Reason: ReasonNetworkDepositLimit}
+	}
+
+	return nil
+}
+
+// LimitError is a deposit the token network would refuse, returned before sending it
+// instead of the error the node reports once the transaction failed. It has the
+// raidenerrors.CodeTokenNetworkDeprecated code on a deprecated network, the
+// raidenerrors.CodeDepositLimitExceeded one otherwise, see raidenerrors.HasCode.
+type LimitError struct {
+	TotalDeposit *big.Int
+	Limits       *Limits
+	Reason       string
+}
+
+func (err *LimitError) Error() string {
+	switch err.Reason {
+	case ReasonDeprecated:
+		return fmt.Sprintf("token network %s of token %s is deprecated and accepts no new channels or deposits", err.Limits.TokenNetworkAddress.Hex(), err.Limits.TokenAddress.Hex())
+	case ReasonParticipantDepositLimit:
+		return fmt.Sprintf("total deposit of %s is above the channel participant deposit limit of %s", err.TotalDeposit, err.Limits.ChannelParticipantDepositLimit)
+	}
+
+	return fmt.Sprintf("total deposit of %s would take the %s tokens held by token network %s above its deposit limit of %s", err.TotalDeposit, err.Limits.TokenNetworkDeposit, err.Limits.TokenNetworkAddress.Hex(), err.Limits.TokenNetworkDepositLimit)
+}
+
+// Code returns raidenerrors.CodeTokenNetworkDeprecated or
+// raidenerrors.CodeDepositLimitExceeded.
+func (err *LimitError) Code() raidenerrors.Code {
+	if err.Reason == ReasonDeprecated {
+		return raidenerrors.CodeTokenNetworkDeprecated
+	}
+
+	return raidenerrors.CodeDepositLimitExceeded
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *LimitError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// LimitsGetter is a generic interface to get the limits of the token network of a
+// token.
+type LimitsGetter interface {
+	Limits(ctx context.Context, tokenAddress common.Address) (*Limits, error)
+}
+
+// NewLimitsGetter creates a new default limits getter given a Raiden node
+// configuration, the contract caller of an Ethereum client and an http client.
+func NewLimitsGetter(config *config.Config, caller ContractCaller, httpClient *http.Client) LimitsGetter {
+	return &defaultLimitsGetter{
+		getter: NewGetter(config, httpClient),
+		caller: caller,
+	}
+}
+
+type defaultLimitsGetter struct {
+	getter Getter
+	caller ContractCaller
+}
+
+// Limits will get the token network of the token from the node and read its limits,
+// its deprecation switch and the tokens it holds from the contracts at the latest
+// block.
+func (getter *defaultLimitsGetter) Limits(ctx context.Context, tokenAddress common.Address) (*Limits, error) {
+	var (
+		err        error
+		deprecated *big.Int
+		limits     = &Limits{TokenAddress: tokenAddress}
+	)
+
+	if limits.TokenNetworkAddress, err = getter.getter.Get(ctx, tokenAddress); err != nil {
+		return nil, err
+	}
+
+	if limits.TokenNetworkAddress.IsZero() {
+		return nil, raidenerrors.New(http.StatusNotFound, fmt.Errorf("token %s is not registered", tokenAddress.Hex()))
+	}
+
+	tokenNetwork := limits.TokenNetworkAddress.Address()
+
+	if limits.ChannelParticipantDepositLimit, err = getter.call(ctx, tokenNetwork, "channel_participant_deposit_limit()"); err != nil {
+		return nil, err
+	}
+
+	if limits.TokenNetworkDepositLimit, err = getter.call(ctx, tokenNetwork, "token_network_deposit_limit()"); err != nil {
+		return nil, err
+	}
+
+	if deprecated, err = getter.call(ctx, tokenNetwork, "safety_deprecation_switch()"); err != nil {
+		return nil, err
+	}
+
+	limits.Deprecated = deprecated.Sign() != 0
+
+	if limits.TokenNetworkDeposit, err = getter.call(ctx, tokenAddress, "balanceOf(address)", tokenNetwork); err != nil {
+		return nil, err
+	}
+
+	return limits, nil
+}
+
+// call calls the function of the contract, whose signature only takes addresses, and
+// decodes the single word it returns as an unsigned integer.
+func (getter *defaultLimitsGetter) call(ctx context.Context, contract common.Address, signature string, arguments ...common.Address) (*big.Int, error) {
+	var data = crypto.Keccak256([]byte(signature))[:4]
+
+	for _, argument := range arguments {
+		data = append(data, common.LeftPadBytes(argument.Bytes(), 32)...)
+	}
+
+	result, err := getter.caller.CallContract(ctx, ethereum.CallMsg{To: &contract, Data: data}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result) < 32 {
+		return nil, fmt.Errorf("unexpected result of %s on contract %s: %x", signature, contract.Hex(), result)
+	}
+
+	return new(big.Int).SetBytes(result[:32]), nil
+}
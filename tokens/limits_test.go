@@ -0,0 +1,218 @@
+package tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleLimitsGetter() {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		// an *ethclient.Client reads the token network contracts
+		caller       ContractCaller
+		limitsGetter = NewLimitsGetter(config, caller, http.DefaultClient)
+		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+	)
+
+	limits, err := limitsGetter.Limits(context.Background(), tokenAddress)
+	if err != nil {
+		panic(fmt.Sprintf("unable to get the limits: %s", err.Error()))
+	}
+
+	fmt.Println(limits.Deprecated, limits.ChannelParticipantDepositLimit, limits.TokenNetworkDepositLimit)
+}
+
+// contractCallerFunc calls contracts with a function of the contract address and the
+// called function selector, for tests.
+type contractCallerFunc func(contract common.Address, selector string, data []byte) ([]byte, error)
+
+func (caller contractCallerFunc) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return caller(*call.To, fmt.Sprintf("%x", call.Data[:4]), call.Data[4:])
+}
+
+func selector(signature string) string {
+	return fmt.Sprintf("%x", crypto.Keccak256([]byte(signature))[:4])
+}
+
+func word(value int64) []byte {
+	return common.LeftPadBytes(big.NewInt(value).Bytes(), 32)
+}
+
+func TestLimitsGetter(t *testing.T) {
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		tokenAddress        = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+		tokenNetworkAddress = common.HexToAddress("0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6")
+		tokenURL            = "http://localhost:5001/api/v1/tokens/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"
+		contracts           = func(deprecated int64) ContractCaller {
+			return contractCallerFunc(func(contract common.Address, called string, data []byte) ([]byte, error) {
+				switch {
+				case contract == tokenNetworkAddress && called == selector("channel_participant_deposit_limit()"):
+					return word(100), nil
+				case contract == tokenNetworkAddress && called == selector("token_network_deposit_limit()"):
+					return word(1000), nil
+				case contract == tokenNetworkAddress && called == selector("safety_deprecation_switch()"):
+					return word(deprecated), nil
+				case contract == tokenAddress && called == selector("balanceOf(address)") && common.BytesToAddress(data) == tokenNetworkAddress:
+					return word(950), nil
+				}
+
+				return nil, errors.New("execution reverted")
+			})
+		}
+	)
+
+	type testcase struct {
+		name           string
+		caller         ContractCaller
+		tokenResponse  string
+		expectedLimits *Limits
+		expectedError  error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:          "limits of the token network",
+			caller:        contracts(0),
+			tokenResponse: `"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"`,
+			expectedLimits: &Limits{
+				TokenAddress:                   tokenAddress,
+				TokenNetworkAddress:            address.TokenNetworkAddress(tokenNetworkAddress),
+				ChannelParticipantDepositLimit: big.NewInt(100),
+				TokenNetworkDepositLimit:       big.NewInt(1000),
+				TokenNetworkDeposit:            big.NewInt(950),
+			},
+		},
+		testcase{
+			name:          "deprecated token network",
+			caller:        contracts(1),
+			tokenResponse: `"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"`,
+			expectedLimits: &Limits{
+				TokenAddress:                   tokenAddress,
+				TokenNetworkAddress:            address.TokenNetworkAddress(tokenNetworkAddress),
+				ChannelParticipantDepositLimit: big.NewInt(100),
+				TokenNetworkDepositLimit:       big.NewInt(1000),
+				TokenNetworkDeposit:            big.NewInt(950),
+				Deprecated:                     true,
+			},
+		},
+		testcase{
+			name: "contract without the limits",
+			caller: contractCallerFunc(func(contract common.Address, called string, data []byte) ([]byte, error) {
+				return []byte{}, nil
+			}),
+			tokenResponse: `"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6"`,
+			expectedError: fmt.Errorf("unexpected result of channel_participant_deposit_limit() on contract %s: ", tokenNetworkAddress.Hex()),
+		},
+		testcase{
+			name:          "token without a token network",
+			caller:        contracts(0),
+			tokenResponse: `"0x0000000000000000000000000000000000000000"`,
+			expectedError: fmt.Errorf("token %s is not registered", tokenAddress.Hex()),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", tokenURL, httpmock.NewStringResponder(http.StatusOK, tc.tokenResponse))
+
+			limits, err := NewLimitsGetter(config, tc.caller, http.DefaultClient).Limits(context.Background(), tokenAddress)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedLimits, limits)
+		})
+	}
+}
+
+func TestLimitsValidate(t *testing.T) {
+	var limits = &Limits{
+		ChannelParticipantDepositLimit: big.NewInt(100),
+		TokenNetworkDepositLimit:       big.NewInt(1000),
+		TokenNetworkDeposit:            big.NewInt(950),
+	}
+
+	type testcase struct {
+		name           string
+		deprecated     bool
+		currentDeposit int64
+		totalDeposit   int64
+		expectedReason string
+		expectedCode   raidenerrors.Code
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "within the limits",
+			totalDeposit: 50,
+		},
+		testcase{
+			name:           "above the participant limit",
+			totalDeposit:   150,
+			expectedReason: ReasonParticipantDepositLimit,
+			expectedCode:   raidenerrors.CodeDepositLimitExceeded,
+		},
+		testcase{
+			name:           "above the network limit",
+			currentDeposit: 20,
+			totalDeposit:   80,
+			expectedReason: ReasonNetworkDepositLimit,
+			expectedCode:   raidenerrors.CodeDepositLimitExceeded,
+		},
+		testcase{
+			name:           "only the added deposit counts towards the network limit",
+			currentDeposit: 40,
+			totalDeposit:   90,
+		},
+		testcase{
+			name:           "deprecated network",
+			deprecated:     true,
+			expectedReason: ReasonDeprecated,
+			expectedCode:   raidenerrors.CodeTokenNetworkDeprecated,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			limits.Deprecated = tc.deprecated
+
+			err := limits.Validate(big.NewInt(tc.currentDeposit), big.NewInt(tc.totalDeposit))
+
+			if tc.expectedReason == "" {
+				assert.NoError(t, err)
+				return
+			}
+
+			require.IsType(t, &LimitError{}, err)
+			assert.Equal(t, tc.expectedReason, err.(*LimitError).Reason)
+			assert.True(t, raidenerrors.HasCode(err, tc.expectedCode))
+		})
+	}
+}
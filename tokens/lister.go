@@ -2,8 +2,6 @@ package tokens
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -43,43 +41,15 @@ func (lister *defaultLister) List(ctx context.Context) ([]common.Address, error)
 		addresses = make([]common.Address, 0)
 
 		requestURL *url.URL
-		request    *http.Request
-		response   *http.Response
 	)
 
-	if requestURL, err = lister.getRequestURL(); err != nil {
+	if requestURL, err = lister.baseClient.Endpoint("tokens"); err != nil {
 		return nil, err
 	}
 
-	if request, err = http.NewRequest("GET", requestURL.String(), nil); err != nil {
-		return nil, err
-	}
-
-	request = request.WithContext(ctx)
-
-	if response, err = lister.baseClient.HTTPClient.Do(request); err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&addresses); err != nil {
+	if err = lister.baseClient.Call(ctx, "GET", requestURL, nil, &addresses); err != nil {
 		return nil, err
 	}
 
 	return addresses, nil
 }
-
-func (lister *defaultLister) getRequestURL() (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/tokens", lister.baseClient.Config.Host, lister.baseClient.Config.APIVersion)
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
-	}
-
-	return requestURL, nil
-}
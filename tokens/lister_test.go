@@ -117,6 +117,7 @@ func TestLister(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, addresses)
 				return
 			}
 
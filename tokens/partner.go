@@ -1,8 +1,46 @@
 package tokens
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"encoding/json"
+
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 type Partner struct {
 	Address    common.Address `json:"partner_address"`
 	ChannelURI string         `json:"channel"`
 }
+
+// MarshalJSON encodes the partner in the format of the Raiden node API, with the
+// address as a checksummed hex string.
+func (partner Partner) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&struct {
+		Address    util.Address `json:"partner_address"`
+		ChannelURI string       `json:"channel"`
+	}{
+		Address:    util.Address(partner.Address),
+		ChannelURI: partner.ChannelURI,
+	})
+}
+
+// Equal tells whether the partner is the same as the other. Two nil partners are
+// equal.
+func (partner *Partner) Equal(other *Partner) bool {
+	if partner == nil || other == nil {
+		return partner == other
+	}
+
+	return partner.Address == other.Address && partner.ChannelURI == other.ChannelURI
+}
+
+// Clone returns a copy of the partner.
+func (partner *Partner) Clone() *Partner {
+	if partner == nil {
+		return nil
+	}
+
+	clone := *partner
+
+	return &clone
+}
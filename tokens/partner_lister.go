@@ -2,8 +2,6 @@ package tokens
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -43,43 +41,15 @@ func (lister *defaultPartnerLister) ListPartners(ctx context.Context, tokenAddre
 		partners = make([]*Partner, 0)
 
 		requestURL *url.URL
-		request    *http.Request
-		response   *http.Response
 	)
 
-	if requestURL, err = lister.getRequestURL(tokenAddress); err != nil {
+	if requestURL, err = lister.baseClient.Endpoint("tokens/%s/partners", tokenAddress.Hex()); err != nil {
 		return nil, err
 	}
 
-	if request, err = http.NewRequest("GET", requestURL.String(), nil); err != nil {
-		return nil, err
-	}
-
-	request = request.WithContext(ctx)
-
-	if response, err = lister.baseClient.HTTPClient.Do(request); err != nil {
-		return nil, err
-	}
-
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&partners); err != nil {
+	if err = lister.baseClient.Call(ctx, "GET", requestURL, nil, &partners); err != nil {
 		return nil, err
 	}
 
 	return partners, nil
 }
-
-func (lister *defaultPartnerLister) getRequestURL(tokenAddress common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/tokens/%s/partners", lister.baseClient.Config.Host, lister.baseClient.Config.APIVersion, tokenAddress.Hex())
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
-	}
-
-	return requestURL, nil
-}
@@ -89,16 +89,14 @@ func TestPartnerLister(t *testing.T) {
 					),
 				)
 			},
-			expectedError:    errors.New("EOF"),
-			expectedPartners: []*Partner{},
+			expectedError: errors.New("EOF"),
 		},
 		testcase{
 			name: "unable to make http request",
 			prepHTTPMock: func() {
 				httpmock.Deactivate()
 			},
-			expectedError:    fmt.Errorf("Get http://localhost:5001/api/v1/tokens/0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6/partners: dial tcp %s:5001: connect: connection refused", localhostIP),
-			expectedPartners: []*Partner{},
+			expectedError: fmt.Errorf("Get http://localhost:5001/api/v1/tokens/0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6/partners: dial tcp %s:5001: connect: connection refused", localhostIP),
 		},
 	}
 
@@ -121,6 +119,7 @@ func TestPartnerLister(t *testing.T) {
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Nil(t, partners)
 				return
 			}
 
@@ -129,3 +128,23 @@ func TestPartnerLister(t *testing.T) {
 		})
 	}
 }
+
+func TestPartnerEqualAndClone(t *testing.T) {
+	var (
+		partner = &Partner{
+			Address:    common.HexToAddress("0x2a65aca4d5fc5b5c859090a6c34d164135398226"),
+			ChannelURI: "/api/v1/channels/0x61C808D82A3Ac53231750daDc13c777b59310bD9/0x2a65aca4d5fc5b5c859090a6c34d164135398226",
+		}
+		clone = partner.Clone()
+	)
+
+	assert.True(t, partner.Equal(clone))
+	assert.False(t, partner == clone)
+
+	clone.ChannelURI = ""
+	assert.False(t, partner.Equal(clone))
+
+	assert.True(t, (*Partner)(nil).Equal(nil))
+	assert.False(t, partner.Equal(nil))
+	assert.Nil(t, (*Partner)(nil).Clone())
+}
@@ -2,11 +2,10 @@ package tokens
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
 	"net/http"
 	"net/url"
 
+	"github.com/cpurta/go-raiden-client/address"
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/cpurta/go-raiden-client/util"
 	"github.com/ethereum/go-ethereum/common"
@@ -20,7 +19,7 @@ type registerTokenResponse struct {
 // Raiden node. It allows for a context to be passed to allow for request timeouts
 // and/or deadlines on the response.
 type Registrar interface {
-	Register(ctx context.Context, tokenAddress common.Address) (common.Address, error)
+	Register(ctx context.Context, tokenAddress common.Address) (address.TokenNetworkAddress, error)
 }
 
 var _ Registrar = &defaultRegistrar{}
@@ -41,52 +40,25 @@ type defaultRegistrar struct {
 
 // List will return the associated Ethereum address to the Raiden node configured
 // in the Lister config.
-func (lister *defaultRegistrar) Register(ctx context.Context, tokenAddress common.Address) (common.Address, error) {
+func (lister *defaultRegistrar) Register(ctx context.Context, tokenAddress common.Address) (address.TokenNetworkAddress, error) {
 	var (
 		err              error
 		registerResponse *registerTokenResponse
-		networkAddress   = common.Address{}
+		networkAddress   = address.TokenNetworkAddress{}
 
 		requestURL *url.URL
-		request    *http.Request
-		response   *http.Response
 	)
 
-	if requestURL, err = lister.getRequestURL(tokenAddress); err != nil {
+	if requestURL, err = lister.baseClient.Endpoint("tokens/%s", tokenAddress.Hex()); err != nil {
 		return networkAddress, err
 	}
 
-	if request, err = http.NewRequest("PUT", requestURL.String(), nil); err != nil {
+	if err = lister.baseClient.Call(ctx, "PUT", requestURL, nil, &registerResponse); err != nil {
 		return networkAddress, err
 	}
 
-	request = request.WithContext(ctx)
-
-	if response, err = lister.baseClient.HTTPClient.Do(request); err != nil {
-		return networkAddress, err
-	}
-
-	defer response.Body.Close()
-
-	if err = json.NewDecoder(response.Body).Decode(&registerResponse); err != nil {
-		return networkAddress, err
-	}
-
-	networkAddress = common.HexToAddress(registerResponse.NetworkAddress)
+	networkAddress = address.HexToTokenNetworkAddress(registerResponse.NetworkAddress)
+	cacheNetwork(lister.baseClient.Config, tokenAddress, networkAddress)
 
 	return networkAddress, nil
 }
-
-func (lister *defaultRegistrar) getRequestURL(address common.Address) (*url.URL, error) {
-	var (
-		err        error
-		endpoint   = fmt.Sprintf("%s/api/%s/tokens/%s", lister.baseClient.Config.Host, lister.baseClient.Config.APIVersion, address.Hex())
-		requestURL *url.URL
-	)
-
-	if requestURL, err = url.Parse(endpoint); err != nil {
-		return nil, err
-	}
-
-	return requestURL, nil
-}
@@ -8,6 +8,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/cpurta/go-raiden-client/address"
 	"github.com/cpurta/go-raiden-client/config"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/jarcoal/httpmock"
@@ -22,18 +23,18 @@ func ExampleRegistrar() {
 			Host:       "http://localhost:5001",
 			APIVersion: "v1",
 		}
-		tokenAddress = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
-		address      common.Address
-		err          error
+		tokenAddress   = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		networkAddress address.TokenNetworkAddress
+		err            error
 	)
 
 	tokenClient = NewClient(config, http.DefaultClient)
 
-	if address, err = tokenClient.Register(context.Background(), tokenAddress); err != nil {
+	if networkAddress, err = tokenClient.Register(context.Background(), tokenAddress); err != nil {
 		panic(fmt.Sprintf("unable to register token: %s", err.Error()))
 	}
 
-	fmt.Printf("token address: %+v\n", address)
+	fmt.Printf("token address: %+v\n", networkAddress)
 }
 
 func TestRegistrar(t *testing.T) {
@@ -52,7 +53,7 @@ func TestRegistrar(t *testing.T) {
 	type testcase struct {
 		name            string
 		prepHTTPMock    func()
-		expectedAddress common.Address
+		expectedAddress address.TokenNetworkAddress
 		expectedError   error
 	}
 
@@ -70,7 +71,7 @@ func TestRegistrar(t *testing.T) {
 				)
 			},
 			expectedError:   nil,
-			expectedAddress: common.HexToAddress("0xC4F8393fb7971E8B299bC1b302F85BfFB3a1275a"),
+			expectedAddress: address.HexToTokenNetworkAddress("0xC4F8393fb7971E8B299bC1b302F85BfFB3a1275a"),
 		},
 		testcase{
 			name: "unexpected 500 response",
@@ -85,7 +86,7 @@ func TestRegistrar(t *testing.T) {
 				)
 			},
 			expectedError:   errors.New("EOF"),
-			expectedAddress: common.Address{},
+			expectedAddress: address.TokenNetworkAddress{},
 		},
 		testcase{
 			name: "unable to make http request",
@@ -93,18 +94,18 @@ func TestRegistrar(t *testing.T) {
 				httpmock.Deactivate()
 			},
 			expectedError:   fmt.Errorf("Put http://localhost:5001/api/v1/tokens/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8: dial tcp %s:5001: connect: connection refused", localhostIP),
-			expectedAddress: common.Address{},
+			expectedAddress: address.TokenNetworkAddress{},
 		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
 			var (
-				err          error
-				address      common.Address
-				tokenAddress = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
-				registrar    = NewRegistrar(config, http.DefaultClient)
-				ctx          = context.Background()
+				err            error
+				networkAddress address.TokenNetworkAddress
+				tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+				registrar      = NewRegistrar(config, http.DefaultClient)
+				ctx            = context.Background()
 			)
 
 			httpmock.Activate()
@@ -112,15 +113,16 @@ func TestRegistrar(t *testing.T) {
 
 			tc.prepHTTPMock()
 
-			address, err = registrar.Register(ctx, tokenAddress)
+			networkAddress, err = registrar.Register(ctx, tokenAddress)
 
 			if tc.expectedError != nil {
 				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Zero(t, networkAddress)
 				return
 			}
 
 			require.NoError(t, err)
-			assert.Equal(t, tc.expectedAddress, address)
+			assert.Equal(t, tc.expectedAddress, networkAddress)
 		})
 	}
 }
@@ -0,0 +1,172 @@
+// Package tracing records the trace of the payments of an application: the routes the
+// pathfinding service found for a payment, the attempts made to send it and the
+// payment event the node logged for it, with their timings, so that support can tell
+// a customer asking for their payment where it is from its identifier.
+package tracing
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pfs"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Route is a route the pathfinding service found for a payment, see pfs.Route.
+type Route struct {
+	Path         []common.Address `json:"path"`
+	EstimatedFee *big.Int         `json:"estimated_fee"`
+}
+
+// Hops is the number of channels the payment goes through along the route.
+func (route *Route) Hops() int {
+	return (&pfs.Route{Path: route.Path}).Hops()
+}
+
+// Lookup is a request to the pathfinding service for the routes of a payment, with
+// the routes it found, cheapest first, or the error it failed with.
+type Lookup struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Routes   []*Route  `json:"routes,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Attempt is a call of the application sending a payment, with the error it failed
+// with. A payment may be attempted more than once with the same identifier, e.g.
+// after a timeout, the node sending it at most once.
+type Attempt struct {
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Trace is the record of a payment from its route lookups to the event the node
+// logged for it, EventPaymentSentSuccess or EventPaymentSentFailed, Finished being
+// the time the event was handled. Payment is the answer of the node to the attempt
+// that succeeded.
+type Trace struct {
+	Identifier    int64             `json:"identifier"`
+	TokenAddress  common.Address    `json:"token_address"`
+	TargetAddress common.Address    `json:"target_address"`
+	Amount        *big.Int          `json:"amount"`
+	Lookups       []*Lookup         `json:"lookups,omitempty"`
+	Attempts      []*Attempt        `json:"attempts,omitempty"`
+	Payment       *payments.Payment `json:"payment,omitempty"`
+	Event         *payments.Event   `json:"event,omitempty"`
+	Started       time.Time         `json:"started"`
+	Updated       time.Time         `json:"updated"`
+	Finished      time.Time         `json:"finished,omitempty"`
+}
+
+// Route returns the cheapest route of the last lookup that found any, the route the
+// node is expected to take when it asks the same pathfinding service, or nil.
+func (trace *Trace) Route() *Route {
+	for i := len(trace.Lookups) - 1; i >= 0; i-- {
+		if len(trace.Lookups[i].Routes) > 0 {
+			return trace.Lookups[i].Routes[0]
+		}
+	}
+
+	return nil
+}
+
+// Status returns the outcome of the payment as told by its event, StatusUnknown
+// while the payment is in flight or was never sent.
+func (trace *Trace) Status() payments.Status {
+	if trace.Event == nil {
+		return payments.StatusUnknown
+	}
+
+	switch trace.Event.EventName {
+	case payments.EventPaymentSentSuccess:
+		return payments.StatusSent
+	case payments.EventPaymentSentFailed:
+		return payments.StatusFailed
+	}
+
+	return payments.StatusUnknown
+}
+
+// Duration returns the time from the start of the trace to its event, or to its last
+// update while the payment has none.
+func (trace *Trace) Duration() time.Duration {
+	if !trace.Finished.IsZero() {
+		return trace.Finished.Sub(trace.Started)
+	}
+
+	return trace.Updated.Sub(trace.Started)
+}
+
+// String returns the trace as a timeline for support, a line for the payment and its
+// status then a line for every lookup, attempt and event, in the order they happened.
+func (trace *Trace) String() string {
+	type step struct {
+		time time.Time
+		text string
+	}
+
+	var (
+		buffer = &bytes.Buffer{}
+		steps  = make([]*step, 0, len(trace.Lookups)+len(trace.Attempts)+1)
+	)
+
+	fmt.Fprintf(buffer, "payment %d of %s of token %s to %s: %s after %s\n", trace.Identifier, amounts.OrZero(trace.Amount), trace.TokenAddress.Hex(), trace.TargetAddress.Hex(), trace.Status(), trace.Duration())
+
+	for _, lookup := range trace.Lookups {
+		var (
+			elapsed = lookup.Finished.Sub(lookup.Started)
+			text    = fmt.Sprintf("route lookup failed after %s: %s", elapsed, lookup.Error)
+		)
+
+		switch {
+		case lookup.Error != "":
+		case len(lookup.Routes) == 0:
+			text = fmt.Sprintf("route lookup found no route after %s", elapsed)
+		default:
+			text = fmt.Sprintf("route lookup found %d routes after %s, the cheapest of %d hops for a fee of %s", len(lookup.Routes), elapsed, lookup.Routes[0].Hops(), amounts.OrZero(lookup.Routes[0].EstimatedFee))
+		}
+
+		steps = append(steps, &step{time: lookup.Started, text: text})
+	}
+
+	for i, attempt := range trace.Attempts {
+		var text = fmt.Sprintf("attempt %d succeeded after %s", i+1, attempt.Finished.Sub(attempt.Started))
+
+		if attempt.Error != "" {
+			text = fmt.Sprintf("attempt %d failed after %s: %s", i+1, attempt.Finished.Sub(attempt.Started), attempt.Error)
+		}
+
+		steps = append(steps, &step{time: attempt.Started, text: text})
+	}
+
+	if trace.Event != nil {
+		steps = append(steps, &step{time: trace.Event.LogTime, text: trace.Event.EventName + " logged by the node"})
+	}
+
+	sort.SliceStable(steps, func(i, j int) bool {
+		return steps[i].time.Before(steps[j].time)
+	})
+
+	for _, step := range steps {
+		fmt.Fprintf(buffer, "  %s %s\n", step.time.UTC().Format(time.RFC3339), step.text)
+	}
+
+	return buffer.String()
+}
+
+// routes returns the routes of the pathfinding service as routes of a trace.
+func routes(found []*pfs.Route) []*Route {
+	var traced = make([]*Route, 0, len(found))
+
+	for _, route := range found {
+		traced = append(traced, &Route{Path: append([]common.Address(nil), route.Path...), EstimatedFee: amounts.Copy(route.EstimatedFee)})
+	}
+
+	return traced
+}
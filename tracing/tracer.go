@@ -0,0 +1,277 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/idempotency"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pfs"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// storagePrefix is the prefix of the keys of the traces in a storage.Store.
+const storagePrefix = "tracing/"
+
+// ErrTraceNotFound is returned for a payment identifier without a trace.
+var ErrTraceNotFound = errors.New("no trace of the payment")
+
+type identifierKey struct{}
+
+// WithIdentifier returns a context carrying the identifier of the payment the calls
+// made with it are for, so that the lookups of a traced path finder are recorded in
+// the trace of the payment, e.g. before the payment is sent with the same context.
+func WithIdentifier(ctx context.Context, identifier int64) context.Context {
+	return context.WithValue(ctx, identifierKey{}, identifier)
+}
+
+// IdentifierFromContext returns the identifier of the payment the context carries, see
+// WithIdentifier.
+func IdentifierFromContext(ctx context.Context) (int64, bool) {
+	identifier, ok := ctx.Value(identifierKey{}).(int64)
+
+	return identifier, ok && identifier != 0
+}
+
+// RetentionPolicy returns the retention of the traces a tracer keeps in its
+// storage.Store, the traces not updated for more than maxAge being deleted, see
+// storage.Collector.
+func RetentionPolicy(maxAge time.Duration) *storage.Policy {
+	return &storage.Policy{
+		Prefix: storagePrefix,
+		MaxAge: maxAge,
+		Time: func(entry *storage.Entry) (time.Time, bool) {
+			var trace = &Trace{}
+
+			if err := json.Unmarshal(entry.Value, trace); err != nil {
+				return time.Time{}, false
+			}
+
+			return trace.Updated, true
+		},
+	}
+}
+
+// Tracer assembles the traces of payments in a storage.Store, under the "tracing/"
+// prefix, from the path finder and the payer it wraps and from the payment events it
+// handles, see Trace. Payments without an identifier are not traced, and a trace
+// that could not be stored never fails the call it records. Its Clock, which times
+// the traces, can be replaced before it is used, e.g. by a clock.Manual in tests.
+type Tracer struct {
+	Clock clock.Clock
+
+	store storage.Store
+	mutex sync.Mutex
+}
+
+// NewTracer creates a tracer keeping the traces of payments in the store.
+func NewTracer(store storage.Store) *Tracer {
+	return &Tracer{
+		Clock: clock.Real(),
+		store: store,
+	}
+}
+
+// Trace returns the trace of the payment with the identifier, or ErrTraceNotFound.
+func (tracer *Tracer) Trace(identifier int64) (*Trace, error) {
+	value, err := tracer.store.Get(key(identifier))
+
+	switch {
+	case err == storage.ErrNotFound:
+		return nil, ErrTraceNotFound
+	case err != nil:
+		return nil, err
+	}
+
+	var trace = &Trace{}
+
+	if err = json.Unmarshal(value, trace); err != nil {
+		return nil, err
+	}
+
+	return trace, nil
+}
+
+// PathFinder returns the path finder recording the lookups made with the context of
+// a payment, see WithIdentifier, in its trace, before asking the next path finder.
+func (tracer *Tracer) PathFinder(next pfs.PathFinder) pfs.PathFinder {
+	return &tracedPathFinder{tracer: tracer, next: next}
+}
+
+// Payer returns the payer recording every payment it sends in its trace, along with
+// the attempt made, before sending it with the next payer. The next payer is given the
+// context of the payment, see WithIdentifier.
+func (tracer *Tracer) Payer(next idempotency.Payer) idempotency.Payer {
+	return &tracedPayer{tracer: tracer, next: next}
+}
+
+// HandleEvent will record the payment event in the trace of its payment when it is
+// the outcome of a payment sent, EventPaymentSentSuccess or EventPaymentSentFailed,
+// of the token of the trace. Events of the payments without a trace are ignored.
+func (tracer *Tracer) HandleEvent(ctx context.Context, event *events.PaymentEvent) error {
+	if event == nil || event.Event == nil || event.Identifier == 0 {
+		return nil
+	}
+
+	if event.EventName != payments.EventPaymentSentSuccess && event.EventName != payments.EventPaymentSentFailed {
+		return nil
+	}
+
+	err := tracer.update(event.Identifier, time.Time{}, func(trace *Trace, now time.Time) bool {
+		if trace.TokenAddress != (common.Address{}) && trace.TokenAddress != event.TokenAddress {
+			return false
+		}
+
+		trace.Event = event.Event.Clone()
+		trace.Finished = now
+
+		return true
+	})
+
+	if err == ErrTraceNotFound {
+		return nil
+	}
+
+	return err
+}
+
+// Follow will handle the events of the subscription until it ends, returning the
+// errors of the traces that could not be stored on the returned channel, which is
+// closed when the subscription ends. Errors are dropped when nobody is receiving them.
+func (tracer *Tracer) Follow(ctx context.Context, subscription *events.Subscription) <-chan error {
+	var errs = make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		for event := range subscription.Events {
+			if err := tracer.HandleEvent(ctx, event); err != nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+		}
+	}()
+
+	return errs
+}
+
+// update will apply the change to the trace of the identifier and store it unless the
+// change tells it changed nothing. A missing trace is created as started at started,
+// unless it is zero, ErrTraceNotFound being returned then.
+func (tracer *Tracer) update(identifier int64, started time.Time, change func(trace *Trace, now time.Time) bool) error {
+	tracer.mutex.Lock()
+	defer tracer.mutex.Unlock()
+
+	var (
+		now        = clock.Or(tracer.Clock).Now()
+		trace, err = tracer.Trace(identifier)
+	)
+
+	switch {
+	case err == ErrTraceNotFound && !started.IsZero():
+		trace = &Trace{Identifier: identifier, Started: started}
+	case err != nil:
+		return err
+	}
+
+	if !change(trace, now) {
+		return nil
+	}
+
+	trace.Updated = now
+
+	value, err := json.Marshal(trace)
+	if err != nil {
+		return err
+	}
+
+	return tracer.store.Put(key(identifier), value)
+}
+
+func key(identifier int64) string {
+	return storagePrefix + strconv.FormatInt(identifier, 10)
+}
+
+type tracedPathFinder struct {
+	tracer *Tracer
+	next   pfs.PathFinder
+}
+
+func (finder *tracedPathFinder) FindPaths(ctx context.Context, tokenNetworkAddress address.TokenNetworkAddress, from, to common.Address, value *big.Int, maxPaths int) ([]*pfs.Route, error) {
+	var (
+		identifier, ok = IdentifierFromContext(ctx)
+		started        = clock.Or(finder.tracer.Clock).Now()
+	)
+
+	found, err := finder.next.FindPaths(ctx, tokenNetworkAddress, from, to, value, maxPaths)
+
+	if !ok {
+		return found, err
+	}
+
+	// the trace is best effort, the lookup is returned whatever becomes of it
+	finder.tracer.update(identifier, started, func(trace *Trace, now time.Time) bool {
+		var lookup = &Lookup{Started: started, Finished: now}
+
+		if lookup.Routes = routes(found); err != nil {
+			lookup.Routes, lookup.Error = nil, err.Error()
+		}
+
+		if trace.Amount == nil {
+			trace.TargetAddress, trace.Amount = to, amounts.Copy(value)
+		}
+
+		trace.Lookups = append(trace.Lookups, lookup)
+
+		return true
+	})
+
+	return found, err
+}
+
+type tracedPayer struct {
+	tracer *Tracer
+	next   idempotency.Payer
+}
+
+func (payer *tracedPayer) SendIdempotent(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64) (*payments.Payment, error) {
+	var started = clock.Or(payer.tracer.Clock).Now()
+
+	if identifier == 0 {
+		return payer.next.SendIdempotent(ctx, tokenAddress, targetAddress, amount, identifier)
+	}
+
+	payment, err := payer.next.SendIdempotent(WithIdentifier(ctx, identifier), tokenAddress, targetAddress, amount, identifier)
+
+	// the trace is best effort, the payment is returned whatever becomes of it
+	payer.tracer.update(identifier, started, func(trace *Trace, now time.Time) bool {
+		var attempt = &Attempt{Started: started, Finished: now}
+
+		if err != nil {
+			attempt.Error = err.Error()
+		}
+
+		trace.TokenAddress, trace.TargetAddress, trace.Amount = tokenAddress, targetAddress, amounts.Copy(amount)
+		trace.Attempts = append(trace.Attempts, attempt)
+
+		if payment != nil {
+			trace.Payment = payment
+		}
+
+		return true
+	})
+
+	return payment, err
+}
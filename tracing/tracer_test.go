@@ -0,0 +1,218 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/address"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/idempotency"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/pfs"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	tokenAddress   = common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	targetAddress  = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	mediator       = common.HexToAddress("0x2c4b0Bdac486d492E3cD701F4cA87e480AE4C685")
+	ourAddress     = common.HexToAddress("0x2a65Aca4D5fC5B5C859090a6c34d164135398226")
+	tokenNetwork   = address.TokenNetworkAddress(common.HexToAddress("0x3E79bD1B5c1bA1DDB3b5a9E7ec6F1D6f1e0FcE7D"))
+	errUnreachable = errors.New("raiden node error 409: payment failed: no route available")
+)
+
+type stubPathFinder struct {
+	clock  *clock.Manual
+	routes []*pfs.Route
+	err    error
+}
+
+func (finder *stubPathFinder) FindPaths(ctx context.Context, tokenNetworkAddress address.TokenNetworkAddress, from, to common.Address, value *big.Int, maxPaths int) ([]*pfs.Route, error) {
+	finder.clock.Advance(200 * time.Millisecond)
+
+	return finder.routes, finder.err
+}
+
+type stubPayer struct {
+	clock *clock.Manual
+	errs  []error
+	ctx   context.Context
+}
+
+func (payer *stubPayer) SendIdempotent(ctx context.Context, tokenAddress, targetAddress common.Address, amount *big.Int, identifier int64) (*payments.Payment, error) {
+	var err error
+
+	payer.clock.Advance(time.Second)
+	payer.ctx = ctx
+
+	if len(payer.errs) > 0 {
+		err, payer.errs = payer.errs[0], payer.errs[1:]
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &payments.Payment{InitiatorAddress: ourAddress, TargetAddress: targetAddress, TokenAddress: tokenAddress, Amount: amount, Identifier: identifier}, nil
+}
+
+func ExampleTracer() {
+	var (
+		nodeConfig = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+		pfsConfig = &config.Config{
+			Host:       "https://pfs.example.com",
+			APIVersion: "v1",
+		}
+		tracer     = NewTracer(storage.NewMemoryStore())
+		payer      = tracer.Payer(idempotency.NewPayer(nodeConfig, http.DefaultClient, idempotency.NewMemoryStore()))
+		pathFinder = tracer.PathFinder(pfs.NewPathFinder(pfsConfig, http.DefaultClient))
+		ctx        = WithIdentifier(context.Background(), 42)
+		token      = common.HexToAddress("0x89d24a6b4ccb1b6faa2625fe562bdd9a23260359") // DAI Stablecoin
+		target     = common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	)
+
+	// the outcomes of the payments are recorded from the events of the node
+	tracer.Follow(ctx, events.NewSubscriber(nodeConfig, http.DefaultClient).SubscribePayments(ctx, nil))
+
+	pathFinder.FindPaths(ctx, address.TokenNetworkAddress(token), common.Address{}, target, big.NewInt(1000), 3)
+	payer.SendIdempotent(ctx, token, target, big.NewInt(1000), 42)
+
+	// later on, when the customer asks where payment 42 is
+	if trace, err := tracer.Trace(42); err == nil {
+		fmt.Print(trace)
+	}
+}
+
+func TestTracer(t *testing.T) {
+	var (
+		manual     = clock.NewManual(time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC))
+		store      = storage.NewMemoryStore()
+		tracer     = NewTracer(store)
+		finder     = &stubPathFinder{clock: manual, routes: []*pfs.Route{&pfs.Route{Path: []common.Address{ourAddress, mediator, targetAddress}, EstimatedFee: big.NewInt(3)}, &pfs.Route{Path: []common.Address{ourAddress, targetAddress}, EstimatedFee: big.NewInt(9)}}}
+		stub       = &stubPayer{clock: manual, errs: []error{errUnreachable}}
+		pathFinder = tracer.PathFinder(finder)
+		payer      = tracer.Payer(stub)
+		ctx        = context.Background()
+	)
+
+	tracer.Clock = manual
+
+	_, err := tracer.Trace(42)
+	assert.Equal(t, ErrTraceNotFound, err)
+
+	_, err = pathFinder.FindPaths(WithIdentifier(ctx, 42), tokenNetwork, ourAddress, targetAddress, big.NewInt(100), 3)
+	require.NoError(t, err)
+
+	_, err = payer.SendIdempotent(ctx, tokenAddress, targetAddress, big.NewInt(100), 42)
+	assert.Equal(t, errUnreachable, err)
+
+	manual.Advance(time.Minute)
+
+	payment, err := payer.SendIdempotent(ctx, tokenAddress, targetAddress, big.NewInt(100), 42)
+	require.NoError(t, err)
+
+	identifier, ok := IdentifierFromContext(stub.ctx)
+	assert.True(t, ok)
+	assert.Equal(t, int64(42), identifier, "the next payer is given the context of the payment")
+
+	manual.Advance(3 * time.Second)
+
+	require.NoError(t, tracer.HandleEvent(ctx, &events.PaymentEvent{TokenAddress: tokenAddress, PartnerAddress: mediator, Event: &payments.Event{EventName: payments.EventPaymentSentSuccess, Identifier: 7, Amount: big.NewInt(5)}}), "events of untraced payments are ignored")
+	require.NoError(t, tracer.HandleEvent(ctx, &events.PaymentEvent{TokenAddress: tokenAddress, PartnerAddress: mediator, Event: &payments.Event{EventName: payments.EventPaymentReceivedSuccess, Identifier: 42, Amount: big.NewInt(100)}}))
+	require.NoError(t, tracer.HandleEvent(ctx, &events.PaymentEvent{TokenAddress: tokenAddress, PartnerAddress: mediator, Event: &payments.Event{EventName: payments.EventPaymentSentSuccess, Identifier: 42, Amount: big.NewInt(100), Target: targetAddress, LogTime: manual.Now().Add(-time.Second)}}))
+
+	trace, err := tracer.Trace(42)
+	require.NoError(t, err)
+
+	assert.Equal(t, payments.StatusSent, trace.Status())
+	assert.Equal(t, payment, trace.Payment)
+	assert.Equal(t, 2, trace.Route().Hops())
+	assert.Equal(t, big.NewInt(3), trace.Route().EstimatedFee)
+	assert.Len(t, trace.Attempts, 2)
+	assert.Equal(t, "EventPaymentSentSuccess", trace.Event.EventName)
+	assert.Equal(t, 65200*time.Millisecond, trace.Duration())
+	assert.Equal(t, `payment 42 of 100 of token 0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8 to 0x61C808D82A3Ac53231750daDc13c777b59310bD9: sent after 1m5.2s
+  2024-01-01T18:00:00Z route lookup found 2 routes after 200ms, the cheapest of 2 hops for a fee of 3
+  2024-01-01T18:00:00Z attempt 1 failed after 1s: raiden node error 409: payment failed: no route available
+  2024-01-01T18:01:01Z attempt 2 succeeded after 1s
+  2024-01-01T18:01:04Z EventPaymentSentSuccess logged by the node
+`, trace.String())
+
+	t.Run("untraced", func(t *testing.T) {
+		_, err := pathFinder.FindPaths(ctx, tokenNetwork, ourAddress, targetAddress, big.NewInt(100), 3)
+		require.NoError(t, err)
+
+		_, err = payer.SendIdempotent(ctx, tokenAddress, targetAddress, big.NewInt(100), 0)
+		require.NoError(t, err)
+
+		entries, err := store.List(storagePrefix)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1, "lookups without a payment and payments without an identifier are not traced")
+	})
+
+	t.Run("failed lookup", func(t *testing.T) {
+		finder.err = errUnreachable
+
+		_, err := pathFinder.FindPaths(WithIdentifier(ctx, 43), tokenNetwork, ourAddress, targetAddress, big.NewInt(10), 3)
+		assert.Equal(t, errUnreachable, err)
+
+		trace, err := tracer.Trace(43)
+		require.NoError(t, err)
+		assert.Equal(t, payments.StatusUnknown, trace.Status())
+		assert.Nil(t, trace.Route())
+		assert.Equal(t, big.NewInt(10), trace.Amount)
+		assert.Equal(t, targetAddress, trace.TargetAddress)
+		require.Len(t, trace.Lookups, 1)
+		assert.Equal(t, errUnreachable.Error(), trace.Lookups[0].Error)
+	})
+
+	t.Run("retention", func(t *testing.T) {
+		manual.Advance(2 * time.Hour)
+
+		collection, err := storage.NewCollector(store, RetentionPolicy(time.Hour)).Collect(ctx)
+		require.NoError(t, err)
+		require.Len(t, collection.Results, 1)
+		assert.Equal(t, 2, collection.Results[0].Deleted)
+	})
+}
+
+func TestTracerFollow(t *testing.T) {
+	var (
+		manual       = clock.NewManual(time.Date(2024, 1, 1, 18, 0, 0, 0, time.UTC))
+		tracer       = NewTracer(storage.NewMemoryStore())
+		paymentsSent = make(chan *events.PaymentEvent, 1)
+		payer        = tracer.Payer(&stubPayer{clock: manual})
+		ctx          = context.Background()
+	)
+
+	tracer.Clock = manual
+
+	_, err := payer.SendIdempotent(ctx, tokenAddress, targetAddress, big.NewInt(100), 42)
+	require.NoError(t, err)
+
+	// an event of the same identifier in another token is not the one of the payment
+	paymentsSent <- &events.PaymentEvent{TokenAddress: mediator, PartnerAddress: targetAddress, Event: &payments.Event{EventName: payments.EventPaymentSentFailed, Identifier: 42}}
+	close(paymentsSent)
+
+	for range tracer.Follow(ctx, &events.Subscription{Events: paymentsSent}) {
+		t.Fatal("no error expected")
+	}
+
+	trace, err := tracer.Trace(42)
+	require.NoError(t, err)
+	assert.Nil(t, trace.Event)
+	assert.Equal(t, payments.StatusUnknown, trace.Status())
+}
@@ -0,0 +1,77 @@
+package transport
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+// NewBulkhead returns a transport limiting the number of concurrent requests of every
+// class of endpoints, see util.Classify, e.g. to 2 channel opens and 20 reads, so that
+// a flood of slow mutating calls can't take every connection to the node and starve
+// reads and health checks. A nil next transport is http.DefaultTransport.
+//
+// A request over the limit of its class waits for a slot until its context is done,
+// and holds its slot until the body of its response is closed. Classes without a
+// limit, or with a limit that is not positive, are not limited.
+func NewBulkhead(next http.RoundTripper, limits map[config.Endpoint]int) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	var slots = make(map[config.Endpoint]chan struct{}, len(limits))
+
+	for endpoint, limit := range limits {
+		if limit > 0 {
+			slots[endpoint] = make(chan struct{}, limit)
+		}
+	}
+
+	return &bulkheadTransport{next: next, slots: slots}
+}
+
+type bulkheadTransport struct {
+	next  http.RoundTripper
+	slots map[config.Endpoint]chan struct{}
+}
+
+func (transport *bulkheadTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	slots, ok := transport.slots[util.Classify(request)]
+	if !ok {
+		return transport.next.RoundTrip(request)
+	}
+
+	select {
+	case slots <- struct{}{}:
+	case <-request.Context().Done():
+		return nil, request.Context().Err()
+	}
+
+	var release = func() { <-slots }
+
+	response, err := transport.next.RoundTrip(request)
+	if err != nil {
+		release()
+		return nil, err
+	}
+
+	response.Body = &releaseBody{ReadCloser: response.Body, release: release}
+
+	return response, nil
+}
+
+// releaseBody gives the slot of a request back once its response body is closed.
+type releaseBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (body *releaseBody) Close() error {
+	defer body.once.Do(body.release)
+
+	return body.ReadCloser.Close()
+}
@@ -0,0 +1,98 @@
+package transport
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewBulkhead() {
+	var httpClient = &http.Client{
+		Transport: NewBulkhead(http.DefaultTransport, map[config.Endpoint]int{
+			config.EndpointChannelOpen: 2,
+			config.EndpointRead:        20,
+		}),
+	}
+
+	// at most 2 channel opens are in flight, reads never wait behind them
+	httpClient.Get("http://localhost:5001/api/v1/channels")
+}
+
+func TestBulkhead(t *testing.T) {
+	var (
+		opening int32
+		unblock = make(chan struct{})
+		opened  sync.WaitGroup
+		server  = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.Method == "PUT" {
+				atomic.AddInt32(&opening, 1)
+				<-unblock
+			}
+
+			writer.WriteHeader(http.StatusOK)
+		}))
+		httpClient = &http.Client{Transport: NewBulkhead(nil, map[config.Endpoint]int{
+			config.EndpointChannelOpen: 2,
+			config.EndpointPayment:     0,
+		})}
+		open = func(ctx context.Context) error {
+			request, err := http.NewRequest("PUT", server.URL+"/api/v1/channels", nil)
+			require.NoError(t, err)
+
+			response, err := httpClient.Do(request.WithContext(ctx))
+			if err != nil {
+				return err
+			}
+
+			return response.Body.Close()
+		}
+	)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		opened.Add(1)
+
+		go func() {
+			defer opened.Done()
+			assert.NoError(t, open(context.Background()))
+		}()
+	}
+
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&opening) == 2 }, time.Second, time.Millisecond)
+
+	t.Run("open over the limit waits for a slot", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.Error(t, open(ctx))
+		assert.Equal(t, int32(2), atomic.LoadInt32(&opening))
+	})
+
+	t.Run("reads and unlimited classes do not wait", func(t *testing.T) {
+		response, err := httpClient.Get(server.URL + "/api/v1/channels")
+		require.NoError(t, err)
+		response.Body.Close()
+
+		response, err = httpClient.Post(server.URL+"/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", "application/json", nil)
+		require.NoError(t, err)
+		response.Body.Close()
+	})
+
+	close(unblock)
+	opened.Wait()
+
+	t.Run("slots are released", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		assert.NoError(t, open(ctx))
+	})
+}
@@ -0,0 +1,211 @@
+package transport
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+const (
+	// UnchangedHeader is set on the responses of the conditional transport whose body
+	// is the same as the previous response to the same request.
+	UnchangedHeader = util.UnchangedHeader
+	// MaxConditionalEntries is the number of requests whose last response the
+	// conditional transport remembers, the least recently requested being forgotten
+	// first.
+	MaxConditionalEntries = 1024
+	// ConditionalTTL is how long the conditional transport remembers the response to
+	// a request that is not made again.
+	ConditionalTTL = time.Hour
+)
+
+// NewConditional returns a transport that remembers the last successful response to
+// every GET request and tells whether the next one changed, so that pollers can skip
+// decoding payloads they already handled. Requests are the same when they have the
+// same URL and the same Authorization header. A nil next transport is
+// http.DefaultTransport.
+//
+// When the node, or a reverse proxy in front of it, returned an ETag or Last-Modified
+// validator, the next request is made conditional and a 304 Not Modified answer is
+// served from the remembered body. Otherwise the bodies are compared by their hash.
+// Either way, a response that did not change carries the UnchangedHeader, see
+// Unchanged.
+//
+// Requests that already have conditional headers of their own are passed through. At
+// most MaxConditionalEntries responses are remembered, for up to ConditionalTTL each.
+// Calls given a context from util.SkipUnchanged return util.ErrUnchanged for the
+// responses that did not change, without decoding them.
+func NewConditional(next http.RoundTripper) http.RoundTripper {
+	return NewConditionalWithClock(next, nil)
+}
+
+// NewConditionalWithClock returns a conditional transport like NewConditional, the
+// ConditionalTTL being measured on the clock, the system time when nil.
+func NewConditionalWithClock(next http.RoundTripper, clock clock.Clock) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &conditionalTransport{
+		next:    next,
+		entries: make(map[string]*conditionalEntry),
+		clock:   clock,
+	}
+}
+
+// Unchanged tells whether the response has the same body as the previous response to
+// the same request made through the conditional transport.
+func Unchanged(response *http.Response) bool {
+	return response != nil && response.Header.Get(UnchangedHeader) != ""
+}
+
+type conditionalTransport struct {
+	next http.RoundTripper
+
+	mutex   sync.Mutex
+	entries map[string]*conditionalEntry
+	clock   clock.Clock
+}
+
+// conditionalEntry is the last successful response to a request, and when the
+// request was last made.
+type conditionalEntry struct {
+	*sharedResponse
+	etag         string
+	lastModified string
+	hash         [sha256.Size]byte
+	used         time.Time
+}
+
+func (transport *conditionalTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != "GET" || request.Header.Get("If-None-Match") != "" || request.Header.Get("If-Modified-Since") != "" {
+		return transport.next.RoundTrip(request)
+	}
+
+	var (
+		err      error
+		response *http.Response
+		body     []byte
+		key      = request.URL.String() + "\n" + request.Header.Get("Authorization")
+		entry    = transport.entry(key)
+		outgoing = request
+	)
+
+	if entry != nil && (entry.etag != "" || entry.lastModified != "") {
+		outgoing = request.WithContext(request.Context())
+		outgoing.Header = make(http.Header, len(request.Header)+1)
+		for name, values := range request.Header {
+			outgoing.Header[name] = values
+		}
+
+		if entry.etag != "" {
+			outgoing.Header.Set("If-None-Match", entry.etag)
+		}
+
+		if entry.lastModified != "" {
+			outgoing.Header.Set("If-Modified-Since", entry.lastModified)
+		}
+	}
+
+	if response, err = transport.next.RoundTrip(outgoing); err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode == http.StatusNotModified && entry != nil {
+		response.Body.Close()
+
+		return entry.unchanged(request), nil
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return response, nil
+	}
+
+	defer response.Body.Close()
+
+	if body, err = ioutil.ReadAll(response.Body); err != nil {
+		return nil, err
+	}
+
+	var current = &conditionalEntry{
+		sharedResponse: &sharedResponse{response: response, body: body},
+		etag:           response.Header.Get("ETag"),
+		lastModified:   response.Header.Get("Last-Modified"),
+		hash:           sha256.Sum256(body),
+	}
+
+	transport.store(key, current)
+
+	if entry != nil && entry.hash == current.hash {
+		return current.unchanged(request), nil
+	}
+
+	return current.copy(request), nil
+}
+
+func (transport *conditionalTransport) entry(key string) *conditionalEntry {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+
+	var (
+		now   = clock.Or(transport.clock).Now()
+		entry = transport.entries[key]
+	)
+
+	if entry == nil {
+		return nil
+	}
+
+	if now.Sub(entry.used) >= ConditionalTTL {
+		delete(transport.entries, key)
+		return nil
+	}
+
+	entry.used = now
+
+	return entry
+}
+
+// store remembers the response, forgetting the expired ones and the least recently
+// requested ones beyond MaxConditionalEntries.
+func (transport *conditionalTransport) store(key string, entry *conditionalEntry) {
+	transport.mutex.Lock()
+	defer transport.mutex.Unlock()
+
+	var now = clock.Or(transport.clock).Now()
+
+	entry.used = now
+	transport.entries[key] = entry
+
+	for storedKey, stored := range transport.entries {
+		if now.Sub(stored.used) >= ConditionalTTL {
+			delete(transport.entries, storedKey)
+		}
+	}
+
+	for len(transport.entries) > MaxConditionalEntries {
+		var oldestKey string
+
+		for storedKey, stored := range transport.entries {
+			if oldestKey == "" || stored.used.Before(transport.entries[oldestKey].used) {
+				oldestKey = storedKey
+			}
+		}
+
+		delete(transport.entries, oldestKey)
+	}
+}
+
+// unchanged returns a copy of the remembered response marked as unchanged.
+func (entry *conditionalEntry) unchanged(request *http.Request) *http.Response {
+	var response = entry.copy(request)
+
+	response.Header.Set(UnchangedHeader, "1")
+
+	return response
+}
@@ -0,0 +1,228 @@
+package transport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewConditional() {
+	var httpClient = &http.Client{
+		Transport: NewConditional(http.DefaultTransport),
+	}
+
+	response, err := httpClient.Get("http://localhost:5001/api/v1/channels")
+	if err != nil {
+		fmt.Println("unable to list payment channels:", err.Error())
+		return
+	}
+	defer response.Body.Close()
+
+	if Unchanged(response) {
+		// the channels are the same as at the previous poll
+		return
+	}
+}
+
+func TestConditional(t *testing.T) {
+	type exchange struct {
+		body              string
+		expectedStatus    int
+		expectedUnchanged bool
+	}
+
+	type testcase struct {
+		name              string
+		handler           func(version *int) http.HandlerFunc
+		requestHeader     http.Header
+		versions          []int
+		expectedExchanges []exchange
+	}
+
+	var (
+		etagHandler = func(version *int) http.HandlerFunc {
+			return func(writer http.ResponseWriter, request *http.Request) {
+				etag := fmt.Sprintf(`"%d"`, *version)
+				writer.Header().Set("ETag", etag)
+
+				if request.Header.Get("If-None-Match") == etag {
+					writer.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				fmt.Fprintf(writer, `{"version":%d}`, *version)
+			}
+		}
+		lastModifiedHandler = func(version *int) http.HandlerFunc {
+			return func(writer http.ResponseWriter, request *http.Request) {
+				lastModified := fmt.Sprintf("Mon, 0%d Jan 2024 00:00:00 GMT", *version)
+				writer.Header().Set("Last-Modified", lastModified)
+
+				if request.Header.Get("If-Modified-Since") == lastModified {
+					writer.WriteHeader(http.StatusNotModified)
+					return
+				}
+
+				fmt.Fprintf(writer, `{"version":%d}`, *version)
+			}
+		}
+		plainHandler = func(version *int) http.HandlerFunc {
+			return func(writer http.ResponseWriter, request *http.Request) {
+				fmt.Fprintf(writer, `{"version":%d}`, *version)
+			}
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:     "etag validator",
+			handler:  etagHandler,
+			versions: []int{1, 1, 2, 2},
+			expectedExchanges: []exchange{
+				exchange{body: `{"version":1}`, expectedStatus: http.StatusOK},
+				exchange{body: `{"version":1}`, expectedStatus: http.StatusOK, expectedUnchanged: true},
+				exchange{body: `{"version":2}`, expectedStatus: http.StatusOK},
+				exchange{body: `{"version":2}`, expectedStatus: http.StatusOK, expectedUnchanged: true},
+			},
+		},
+		testcase{
+			name:     "last modified validator",
+			handler:  lastModifiedHandler,
+			versions: []int{1, 1, 2},
+			expectedExchanges: []exchange{
+				exchange{body: `{"version":1}`, expectedStatus: http.StatusOK},
+				exchange{body: `{"version":1}`, expectedStatus: http.StatusOK, expectedUnchanged: true},
+				exchange{body: `{"version":2}`, expectedStatus: http.StatusOK},
+			},
+		},
+		testcase{
+			name:     "no validators",
+			handler:  plainHandler,
+			versions: []int{1, 1, 2, 1},
+			expectedExchanges: []exchange{
+				exchange{body: `{"version":1}`, expectedStatus: http.StatusOK},
+				exchange{body: `{"version":1}`, expectedStatus: http.StatusOK, expectedUnchanged: true},
+				exchange{body: `{"version":2}`, expectedStatus: http.StatusOK},
+				exchange{body: `{"version":1}`, expectedStatus: http.StatusOK},
+			},
+		},
+		testcase{
+			name:          "conditional request of the caller",
+			handler:       etagHandler,
+			requestHeader: http.Header{"If-None-Match": []string{`"1"`}},
+			versions:      []int{1, 1},
+			expectedExchanges: []exchange{
+				exchange{expectedStatus: http.StatusNotModified},
+				exchange{expectedStatus: http.StatusNotModified},
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				version    int
+				server     = httptest.NewServer(tc.handler(&version))
+				httpClient = &http.Client{Transport: NewConditional(nil)}
+			)
+			defer server.Close()
+
+			for i, expected := range tc.expectedExchanges {
+				version = tc.versions[i]
+
+				request, err := http.NewRequest("GET", server.URL+"/api/v1/channels", nil)
+				require.NoError(t, err)
+
+				for name, values := range tc.requestHeader {
+					request.Header[name] = values
+				}
+
+				response, err := httpClient.Do(request)
+				require.NoError(t, err)
+
+				body, err := ioutil.ReadAll(response.Body)
+				require.NoError(t, err)
+				response.Body.Close()
+
+				assert.Equal(t, expected.expectedStatus, response.StatusCode)
+				assert.Equal(t, expected.body, string(body))
+				assert.Equal(t, expected.expectedUnchanged, Unchanged(response))
+			}
+		})
+	}
+}
+
+func TestConditionalPassesThroughOtherRequests(t *testing.T) {
+	var (
+		hits   int
+		server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			hits++
+			assert.Empty(t, request.Header.Get("If-None-Match"))
+			writer.Header().Set("ETag", `"1"`)
+			fmt.Fprint(writer, `{}`)
+		}))
+		httpClient = &http.Client{Transport: NewConditional(nil)}
+	)
+	defer server.Close()
+
+	for i := 0; i < 2; i++ {
+		response, err := httpClient.Post(server.URL+"/api/v1/channels", "application/json", nil)
+		require.NoError(t, err)
+		response.Body.Close()
+
+		assert.False(t, Unchanged(response))
+	}
+
+	assert.Equal(t, 2, hits)
+}
+
+func TestConditionalEviction(t *testing.T) {
+	var (
+		server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			fmt.Fprint(writer, request.URL.Path)
+		}))
+		manual     = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+		transport  = NewConditionalWithClock(nil, manual).(*conditionalTransport)
+		httpClient = &http.Client{Transport: transport}
+		get        = func(path string) bool {
+			response, err := httpClient.Get(server.URL + path)
+			require.NoError(t, err)
+			response.Body.Close()
+
+			return Unchanged(response)
+		}
+	)
+	defer server.Close()
+
+	t.Run("expired", func(t *testing.T) {
+		assert.False(t, get("/api/v1/channels"))
+		assert.True(t, get("/api/v1/channels"))
+
+		manual.Advance(ConditionalTTL)
+
+		assert.False(t, get("/api/v1/channels"), "the response is forgotten once expired")
+		assert.True(t, get("/api/v1/channels"))
+	})
+
+	t.Run("least recently requested", func(t *testing.T) {
+		for i := 0; i <= MaxConditionalEntries; i++ {
+			manual.Advance(time.Millisecond)
+			get(fmt.Sprintf("/api/v1/tokens/%d", i))
+		}
+
+		transport.mutex.Lock()
+		assert.Len(t, transport.entries, MaxConditionalEntries)
+		_, ok := transport.entries[server.URL+"/api/v1/channels\n"]
+		transport.mutex.Unlock()
+
+		assert.False(t, ok, "the least recently requested response is forgotten first")
+		assert.True(t, get("/api/v1/tokens/1"))
+	})
+}
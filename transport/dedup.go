@@ -0,0 +1,89 @@
+// Package transport provides http.RoundTripper middlewares that change how the
+// requests of a client reach the Raiden node. They are enabled per client by wrapping
+// the transport of its http.Client.
+package transport
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// NewDedup returns a transport that collapses concurrent identical GET requests into
+// a single request to the next transport, every caller getting its own copy of the
+// response. Requests are identical when they have the same URL and the same
+// Authorization header. A nil next transport is http.DefaultTransport.
+//
+// The shared request is made with the context of the first caller, so when that
+// caller gives up, the callers waiting on the same request get its error too.
+func NewDedup(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &dedupTransport{next: next}
+}
+
+type dedupTransport struct {
+	next  http.RoundTripper
+	group singleflight.Group
+}
+
+// sharedResponse is a response whose body was read so that it can be copied for every
+// caller of a deduplicated request.
+type sharedResponse struct {
+	response *http.Response
+	body     []byte
+}
+
+func (transport *dedupTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != "GET" {
+		return transport.next.RoundTrip(request)
+	}
+
+	key := request.URL.String() + "\n" + request.Header.Get("Authorization")
+
+	result, err, _ := transport.group.Do(key, func() (interface{}, error) {
+		var (
+			err      error
+			response *http.Response
+			body     []byte
+		)
+
+		if response, err = transport.next.RoundTrip(request); err != nil {
+			return nil, err
+		}
+
+		defer response.Body.Close()
+
+		if body, err = ioutil.ReadAll(response.Body); err != nil {
+			return nil, err
+		}
+
+		return &sharedResponse{response: response, body: body}, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*sharedResponse).copy(request), nil
+}
+
+// copy returns a response of its own to the request of a caller.
+func (shared *sharedResponse) copy(request *http.Request) *http.Response {
+	var response = *shared.response
+
+	response.Header = make(http.Header, len(shared.response.Header))
+	for key, values := range shared.response.Header {
+		response.Header[key] = append([]string(nil), values...)
+	}
+
+	response.Body = ioutil.NopCloser(bytes.NewReader(shared.body))
+	response.ContentLength = int64(len(shared.body))
+	response.Request = request
+
+	return &response
+}
@@ -0,0 +1,160 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewDedup() {
+	var (
+		httpClient = &http.Client{
+			Transport: NewDedup(http.DefaultTransport),
+		}
+		channelClient = channels.NewClient(&config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}, httpClient)
+		waitGroup sync.WaitGroup
+	)
+
+	// dashboard widgets refreshing at the same time share a single request to the node
+	for widget := 0; widget < 3; widget++ {
+		waitGroup.Add(1)
+
+		go func() {
+			defer waitGroup.Done()
+
+			if _, err := channelClient.ListAll(context.Background()); err != nil {
+				fmt.Println("unable to list payment channels:", err.Error())
+			}
+		}()
+	}
+
+	waitGroup.Wait()
+}
+
+func TestDedup(t *testing.T) {
+	var (
+		hits    int32
+		release = make(chan struct{})
+		server  = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			atomic.AddInt32(&hits, 1)
+			<-release
+			writer.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(writer, `{"method":"%s"}`, request.Method)
+		}))
+		httpClient = &http.Client{Transport: NewDedup(nil)}
+	)
+	defer server.Close()
+
+	t.Run("concurrent identical GETs share one request", func(t *testing.T) {
+		var (
+			waitGroup sync.WaitGroup
+			bodies    = make([]string, 10)
+			errs      = make([]error, 10)
+		)
+
+		atomic.StoreInt32(&hits, 0)
+
+		for i := range bodies {
+			waitGroup.Add(1)
+
+			go func(i int) {
+				defer waitGroup.Done()
+
+				response, err := httpClient.Get(server.URL + "/api/v1/channels")
+				if err != nil {
+					errs[i] = err
+					return
+				}
+
+				defer response.Body.Close()
+
+				body, err := ioutil.ReadAll(response.Body)
+				bodies[i], errs[i] = string(body), err
+			}(i)
+		}
+
+		// let every caller join the request in flight before it completes
+		time.Sleep(100 * time.Millisecond)
+		release <- struct{}{}
+
+		waitGroup.Wait()
+
+		assert.Equal(t, int32(1), atomic.LoadInt32(&hits))
+
+		for i := range bodies {
+			require.NoError(t, errs[i])
+			assert.Equal(t, `{"method":"GET"}`, bodies[i])
+		}
+	})
+
+	t.Run("other methods are not deduplicated", func(t *testing.T) {
+		var waitGroup sync.WaitGroup
+
+		atomic.StoreInt32(&hits, 0)
+
+		for i := 0; i < 3; i++ {
+			waitGroup.Add(1)
+
+			go func() {
+				defer waitGroup.Done()
+
+				response, err := httpClient.Post(server.URL+"/api/v1/payments", "application/json", strings.NewReader(`{}`))
+				if assert.NoError(t, err) {
+					response.Body.Close()
+				}
+			}()
+		}
+
+		for i := 0; i < 3; i++ {
+			release <- struct{}{}
+		}
+
+		waitGroup.Wait()
+
+		assert.Equal(t, int32(3), atomic.LoadInt32(&hits))
+	})
+
+	t.Run("different credentials are not deduplicated", func(t *testing.T) {
+		var waitGroup sync.WaitGroup
+
+		atomic.StoreInt32(&hits, 0)
+
+		for _, token := range []string{"first", "second"} {
+			waitGroup.Add(1)
+
+			go func(token string) {
+				defer waitGroup.Done()
+
+				request, _ := http.NewRequest("GET", server.URL+"/api/v1/channels", nil)
+				request.Header.Set("Authorization", "Bearer "+token)
+
+				response, err := httpClient.Do(request)
+				if assert.NoError(t, err) {
+					response.Body.Close()
+				}
+			}(token)
+		}
+
+		release <- struct{}{}
+		release <- struct{}{}
+
+		waitGroup.Wait()
+
+		assert.Equal(t, int32(2), atomic.LoadInt32(&hits))
+	})
+}
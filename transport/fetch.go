@@ -0,0 +1,12 @@
+package transport
+
+// FetchOptions are the options of the requests a browser makes with the Fetch API for
+// a client compiled to js/wasm, e.g. Mode "cors" and Credentials "include" for a
+// dashboard served from another origin than the Raiden node or its proxy. Empty
+// options are left to the defaults of the browser. See
+// https://developer.mozilla.org/en-US/docs/Web/API/fetch for their values.
+type FetchOptions struct {
+	Mode        string
+	Credentials string
+	Redirect    string
+}
@@ -0,0 +1,46 @@
+//go:build js && wasm
+// +build js,wasm
+
+package transport
+
+import "net/http"
+
+// NewFetch returns a transport making requests with the Fetch API of the browser and
+// the given options, for dashboards compiled to js/wasm.
+func NewFetch(options *FetchOptions) http.RoundTripper {
+	if options == nil {
+		options = &FetchOptions{}
+	}
+
+	return &fetchTransport{next: http.DefaultTransport, options: *options}
+}
+
+type fetchTransport struct {
+	next    http.RoundTripper
+	options FetchOptions
+}
+
+func (transport *fetchTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	var header = make(http.Header, len(request.Header)+3)
+
+	for key, values := range request.Header {
+		header[key] = values
+	}
+
+	// the fetch transport of net/http reads its options from these headers, which are
+	// not sent to the node
+	for key, value := range map[string]string{
+		"js.fetch:mode":        transport.options.Mode,
+		"js.fetch:credentials": transport.options.Credentials,
+		"js.fetch:redirect":    transport.options.Redirect,
+	} {
+		if value != "" {
+			header.Set(key, value)
+		}
+	}
+
+	request = request.WithContext(request.Context())
+	request.Header = header
+
+	return transport.next.RoundTrip(request)
+}
@@ -0,0 +1,43 @@
+//go:build js && wasm
+// +build js,wasm
+
+package transport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestNewFetch(t *testing.T) {
+	var (
+		header    http.Header
+		transport = NewFetch(&FetchOptions{Mode: "cors", Credentials: "include"}).(*fetchTransport)
+	)
+
+	transport.next = roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		header = request.Header
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: request}, nil
+	})
+
+	request, err := http.NewRequest("GET", "https://raiden.example.com/api/v1/address", nil)
+	require.NoError(t, err)
+	request.Header.Set("Authorization", "Bearer secret")
+
+	_, err = transport.RoundTrip(request)
+	require.NoError(t, err)
+
+	assert.Equal(t, "cors", header.Get("js.fetch:mode"))
+	assert.Equal(t, "include", header.Get("js.fetch:credentials"))
+	assert.Empty(t, header.Get("js.fetch:redirect"))
+	assert.Equal(t, "Bearer secret", header.Get("Authorization"))
+	assert.Empty(t, request.Header.Get("js.fetch:mode"))
+}
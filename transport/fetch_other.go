@@ -0,0 +1,13 @@
+//go:build !js || !wasm
+// +build !js !wasm
+
+package transport
+
+import "net/http"
+
+// NewFetch returns a transport making requests with the Fetch API of the browser and
+// the given options, for dashboards compiled to js/wasm. Outside of the browser it is
+// http.DefaultTransport, so that the same code runs in both.
+func NewFetch(options *FetchOptions) http.RoundTripper {
+	return http.DefaultTransport
+}
@@ -0,0 +1,15 @@
+//go:build !js || !wasm
+// +build !js !wasm
+
+package transport
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFetch(t *testing.T) {
+	assert.Equal(t, http.DefaultTransport, NewFetch(&FetchOptions{Mode: "cors"}))
+}
@@ -0,0 +1,27 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	raidenclient "github.com/cpurta/go-raiden-client"
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+func ExampleNewFetch() {
+	var (
+		httpClient = &http.Client{
+			Transport: NewFetch(&FetchOptions{Mode: "cors", Credentials: "include"}),
+		}
+		raidenClient = raidenclient.NewClient(&config.Config{Host: "https://raiden.example.com", APIVersion: "v1"}, httpClient)
+	)
+
+	address, err := raidenClient.Address().Get(context.Background())
+	if err != nil {
+		fmt.Println("unable to get the address of the node:", err.Error())
+		return
+	}
+
+	fmt.Println("node address:", address.Hex())
+}
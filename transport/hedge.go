@@ -0,0 +1,135 @@
+package transport
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+)
+
+// NewHedge returns a transport that hedges slow reads: when a GET or HEAD request
+// has not returned after delay, a second attempt of it is made and the response of
+// whichever attempt returns first is used, the other one being cancelled. Requests
+// with other methods, which are not idempotent, or with a body are never hedged. A
+// nil next transport is http.DefaultTransport, and a delay of zero disables hedging.
+func NewHedge(next http.RoundTripper, delay time.Duration) http.RoundTripper {
+	return NewHedgeWithClock(next, delay, nil)
+}
+
+// NewHedgeWithClock returns a hedging transport like NewHedge, the delay being
+// measured on the clock, the system time when nil.
+func NewHedgeWithClock(next http.RoundTripper, delay time.Duration, clock clock.Clock) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &hedgeTransport{next: next, delay: delay, clock: clock}
+}
+
+type hedgeTransport struct {
+	next  http.RoundTripper
+	delay time.Duration
+	clock clock.Clock
+}
+
+type hedgeResult struct {
+	attempt  int
+	response *http.Response
+	err      error
+}
+
+func (transport *hedgeTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if !hedgeable(request) || transport.delay <= 0 {
+		return transport.next.RoundTrip(request)
+	}
+
+	var (
+		results = make(chan *hedgeResult, 2)
+		cancels = make([]context.CancelFunc, 0, 2)
+		pending = 0
+		delayed = clock.Or(transport.clock).After(transport.delay)
+	)
+
+	start := func() {
+		var (
+			attempt     = len(cancels)
+			ctx, cancel = context.WithCancel(request.Context())
+		)
+
+		cancels = append(cancels, cancel)
+		pending++
+
+		go func() {
+			response, err := transport.next.RoundTrip(request.WithContext(ctx))
+			results <- &hedgeResult{attempt: attempt, response: response, err: err}
+		}()
+	}
+
+	start()
+
+	for {
+		select {
+		case <-delayed:
+			if len(cancels) == 1 && pending == 1 {
+				start()
+			}
+		case result := <-results:
+			pending--
+
+			if result.err != nil {
+				cancels[result.attempt]()
+
+				if pending == 0 {
+					return nil, result.err
+				}
+
+				continue
+			}
+
+			for attempt, cancel := range cancels {
+				if attempt != result.attempt {
+					cancel()
+				}
+			}
+
+			go discard(results, pending)
+
+			result.response.Body = &cancelBody{ReadCloser: result.response.Body, cancel: cancels[result.attempt]}
+
+			return result.response, nil
+		}
+	}
+}
+
+// hedgeable reports whether the request can be sent twice, reads without a body that
+// the attempts would have to share.
+func hedgeable(request *http.Request) bool {
+	if request.Method != "GET" && request.Method != "HEAD" {
+		return false
+	}
+
+	return request.Body == nil || request.Body == http.NoBody
+}
+
+// discard closes the responses of the attempts that lost.
+func discard(results <-chan *hedgeResult, pending int) {
+	for ; pending > 0; pending-- {
+		if result := <-results; result.response != nil {
+			result.response.Body.Close()
+		}
+	}
+}
+
+// cancelBody releases the context of the winning attempt once its body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (body *cancelBody) Close() error {
+	defer body.cancel()
+
+	return body.ReadCloser.Close()
+}
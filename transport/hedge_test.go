@@ -0,0 +1,169 @@
+package transport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHedge(t *testing.T) {
+	type testcase struct {
+		name         string
+		method       string
+		delay        time.Duration
+		stall        func(hit int32) time.Duration
+		expectedHits int32
+		expectedBody string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "fast read is not hedged",
+			method:       "GET",
+			delay:        50 * time.Millisecond,
+			stall:        func(hit int32) time.Duration { return 0 },
+			expectedHits: 1,
+			expectedBody: "attempt 1",
+		},
+		testcase{
+			name:   "stalled read is answered by the hedged attempt",
+			method: "GET",
+			delay:  20 * time.Millisecond,
+			stall: func(hit int32) time.Duration {
+				if hit == 1 {
+					return time.Minute
+				}
+
+				return 0
+			},
+			expectedHits: 2,
+			expectedBody: "attempt 2",
+		},
+		testcase{
+			name:   "slow first attempt still wins when it returns first",
+			method: "GET",
+			delay:  20 * time.Millisecond,
+			stall: func(hit int32) time.Duration {
+				if hit == 1 {
+					return 40 * time.Millisecond
+				}
+
+				return time.Minute
+			},
+			expectedHits: 2,
+			expectedBody: "attempt 1",
+		},
+		testcase{
+			name:         "writes are never hedged",
+			method:       "POST",
+			delay:        10 * time.Millisecond,
+			stall:        func(hit int32) time.Duration { return 50 * time.Millisecond },
+			expectedHits: 1,
+			expectedBody: "attempt 1",
+		},
+		testcase{
+			name:         "zero delay disables hedging",
+			method:       "GET",
+			stall:        func(hit int32) time.Duration { return 30 * time.Millisecond },
+			expectedHits: 1,
+			expectedBody: "attempt 1",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				hits   int32
+				server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+					hit := atomic.AddInt32(&hits, 1)
+
+					select {
+					case <-time.After(tc.stall(hit)):
+						fmt.Fprintf(writer, "attempt %d", hit)
+					case <-request.Context().Done():
+					}
+				}))
+				httpClient = &http.Client{Transport: NewHedge(nil, tc.delay)}
+			)
+			defer server.Close()
+
+			request, err := http.NewRequest(tc.method, server.URL+"/api/v1/channels", strings.NewReader(""))
+			require.NoError(t, err)
+
+			response, err := httpClient.Do(request)
+			require.NoError(t, err)
+
+			body, err := ioutil.ReadAll(response.Body)
+			response.Body.Close()
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedBody, string(body))
+			assert.Equal(t, tc.expectedHits, atomic.LoadInt32(&hits))
+		})
+	}
+}
+
+func TestHedgeError(t *testing.T) {
+	var (
+		server     = httptest.NewServer(http.NotFoundHandler())
+		httpClient = &http.Client{Transport: NewHedge(nil, time.Second)}
+	)
+
+	server.Close()
+
+	start := time.Now()
+
+	_, err := httpClient.Get(server.URL)
+
+	require.Error(t, err)
+	assert.True(t, time.Since(start) < time.Second, "a failed attempt is returned without waiting for a hedge")
+}
+
+func TestHedgeClock(t *testing.T) {
+	var (
+		attempts int32
+		release  = make(chan struct{})
+		manual   = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+		server   = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			// the first attempt hangs until the test ends
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				select {
+				case <-release:
+				case <-request.Context().Done():
+				}
+			}
+
+			fmt.Fprintf(writer, "attempt %d", atomic.LoadInt32(&attempts))
+		}))
+		httpClient = &http.Client{Transport: NewHedgeWithClock(nil, time.Second, manual)}
+		bodies     = make(chan string, 1)
+	)
+	defer server.Close()
+	defer close(release)
+
+	go func() {
+		response, err := httpClient.Get(server.URL)
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		body, _ := ioutil.ReadAll(response.Body)
+		bodies <- string(body)
+	}()
+
+	// the second attempt is only made once the delay passed on the clock
+	manual.BlockUntil(1)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&attempts) == 1 }, time.Second, time.Millisecond)
+
+	manual.Advance(time.Second)
+
+	assert.Equal(t, "attempt 2", <-bodies)
+}
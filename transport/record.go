@@ -0,0 +1,141 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/redact"
+)
+
+// Exchange is a request made to the Raiden node and its response, along with the time
+// the response was received.
+type Exchange struct {
+	Time        time.Time   `json:"time"`
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody []byte      `json:"request_body,omitempty"`
+	StatusCode  int         `json:"status_code"`
+	Header      http.Header `json:"header"`
+	Body        []byte      `json:"body"`
+}
+
+// Recording is a session of exchanges with a Raiden node, oldest first.
+type Recording struct {
+	Exchanges []*Exchange `json:"exchanges"`
+}
+
+// LoadRecording will read a recording saved as JSON in the file at path.
+func LoadRecording(path string) (*Recording, error) {
+	var (
+		err       error
+		contents  []byte
+		recording = &Recording{}
+	)
+
+	if contents, err = ioutil.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	if err = json.Unmarshal(contents, recording); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(recording.Exchanges, func(i, j int) bool {
+		return recording.Exchanges[i].Time.Before(recording.Exchanges[j].Time)
+	})
+
+	return recording, nil
+}
+
+// Save will write the recording as JSON to the file at path.
+func (recording *Recording) Save(path string) error {
+	contents, err := json.Marshal(recording)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, contents, 0600)
+}
+
+// Recorder is a transport recording every exchange made through it with the time it
+// happened, so that a production session can be replayed later on, see NewReplayer.
+// Credentials are not recorded: requests are recorded without their headers, and the
+// user info of their URLs, the secrets of the bodies of the requests and of the
+// responses, e.g. the secrets of payments the node echoes, and the credentials of the
+// headers of the responses are redacted, see the redact package.
+type Recorder struct {
+	next http.RoundTripper
+	now  func() time.Time
+
+	mutex     sync.Mutex
+	exchanges []*Exchange
+}
+
+// NewRecorder returns a transport recording the exchanges made through the next
+// transport. A nil next transport is http.DefaultTransport.
+func NewRecorder(next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &Recorder{next: next, now: time.Now}
+}
+
+func (recorder *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	var (
+		err          error
+		requestBody  []byte
+		responseBody []byte
+		response     *http.Response
+	)
+
+	if request.Body != nil && request.Body != http.NoBody {
+		if requestBody, err = ioutil.ReadAll(request.Body); err != nil {
+			return nil, err
+		}
+
+		request.Body.Close()
+
+		request = request.WithContext(request.Context())
+		request.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	if response, err = recorder.next.RoundTrip(request); err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if responseBody, err = ioutil.ReadAll(response.Body); err != nil {
+		return nil, err
+	}
+
+	var shared = &sharedResponse{response: response, body: responseBody}
+
+	recorder.mutex.Lock()
+	recorder.exchanges = append(recorder.exchanges, &Exchange{
+		Time:        recorder.now(),
+		Method:      request.Method,
+		URL:         redact.URL(request.URL),
+		RequestBody: redact.JSON(requestBody),
+		StatusCode:  response.StatusCode,
+		Header:      redact.Header(shared.copy(request).Header),
+		Body:        redact.JSON(responseBody),
+	})
+	recorder.mutex.Unlock()
+
+	return shared.copy(request), nil
+}
+
+// Recording returns the exchanges recorded so far.
+func (recorder *Recorder) Recording() *Recording {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return &Recording{Exchanges: append([]*Exchange(nil), recorder.exchanges...)}
+}
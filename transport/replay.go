@@ -0,0 +1,130 @@
+package transport
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/redact"
+)
+
+// ReplayOptions configures a replay. Speed is how much faster than it was recorded
+// the session is replayed, e.g. 60 for an hour in a minute, a speed that is not
+// positive being the original one. The replay starts at Start, the time of the first
+// exchange of the recording when zero, and goes by on Clock, the system time when nil.
+type ReplayOptions struct {
+	Speed float64
+	Start time.Time
+	Clock clock.Clock
+}
+
+// Replayer is a transport answering requests from a recording, as the node answered
+// them at the time of the recording, so that a production incident can be replayed
+// locally against application logic, e.g. an events subscription.
+//
+// The time of the replay starts at the beginning of the recording and goes by at the
+// speed of the replay: a request is answered with the last response recorded for its
+// method and URL, redacted like the recorded ones, at that time, or with the first one recorded when the replay has
+// not reached it yet. Poll intervals are to be divided by the speed for the
+// application to see the responses as often as it did.
+type Replayer struct {
+	speed     float64
+	start     time.Time
+	end       time.Time
+	exchanges map[string][]*Exchange
+	now       func() time.Time
+
+	once    sync.Once
+	started time.Time
+}
+
+// NewReplayer returns a transport replaying the recording, see Replayer. The replay
+// starts with the first request.
+func NewReplayer(recording *Recording, options *ReplayOptions) *Replayer {
+	var replayer = &Replayer{
+		speed:     1,
+		exchanges: make(map[string][]*Exchange),
+		now:       time.Now,
+	}
+
+	for _, exchange := range recording.Exchanges {
+		key := exchange.Method + " " + exchange.URL
+		replayer.exchanges[key] = append(replayer.exchanges[key], exchange)
+
+		if replayer.start.IsZero() || exchange.Time.Before(replayer.start) {
+			replayer.start = exchange.Time
+		}
+
+		if exchange.Time.After(replayer.end) {
+			replayer.end = exchange.Time
+		}
+	}
+
+	for _, exchanges := range replayer.exchanges {
+		sort.SliceStable(exchanges, func(i, j int) bool {
+			return exchanges[i].Time.Before(exchanges[j].Time)
+		})
+	}
+
+	if options != nil {
+		if options.Speed > 0 {
+			replayer.speed = options.Speed
+		}
+
+		if !options.Start.IsZero() {
+			replayer.start = options.Start
+		}
+
+		replayer.now = clock.Or(options.Clock).Now
+	}
+
+	return replayer
+}
+
+// Time returns the time of the replay, i.e. the time of the recording being replayed.
+func (replayer *Replayer) Time() time.Time {
+	replayer.once.Do(func() { replayer.started = replayer.now() })
+
+	var elapsed = float64(replayer.now().Sub(replayer.started)) * replayer.speed
+
+	return replayer.start.Add(time.Duration(elapsed))
+}
+
+// Done tells whether the replay went past the last exchange of the recording.
+func (replayer *Replayer) Done() bool {
+	return replayer.Time().After(replayer.end)
+}
+
+func (replayer *Replayer) RoundTrip(request *http.Request) (*http.Response, error) {
+	var (
+		now       = replayer.Time()
+		exchanges = replayer.exchanges[request.Method+" "+redact.URL(request.URL)]
+	)
+
+	if len(exchanges) == 0 {
+		return nil, fmt.Errorf("no recorded response to %s %s", request.Method, redact.URL(request.URL))
+	}
+
+	// the last exchange recorded at the time of the replay
+	i := sort.Search(len(exchanges), func(i int) bool { return exchanges[i].Time.After(now) }) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	var shared = &sharedResponse{
+		response: &http.Response{
+			Status:     fmt.Sprintf("%d %s", exchanges[i].StatusCode, http.StatusText(exchanges[i].StatusCode)),
+			StatusCode: exchanges[i].StatusCode,
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     exchanges[i].Header,
+		},
+		body: exchanges[i].Body,
+	}
+
+	return shared.copy(request), nil
+}
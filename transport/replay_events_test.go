@@ -0,0 +1,72 @@
+package transport_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/transport"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleNewReplayer() {
+	recording, err := transport.LoadRecording("incident.json")
+	if err != nil {
+		fmt.Println("unable to load the recording:", err.Error())
+		return
+	}
+
+	var (
+		replayer   = transport.NewReplayer(recording, &transport.ReplayOptions{Speed: 60})
+		httpClient = &http.Client{Transport: replayer}
+		config     = &config.Config{Host: "http://localhost:5001", APIVersion: "v1"}
+		ctx, stop  = context.WithCancel(context.Background())
+		// poll as often as production did, in the time of the replay
+		subscription = events.NewSubscriber(config, httpClient).SubscribePayments(ctx, &events.SubscribeOptions{Interval: events.DefaultInterval / 60})
+	)
+
+	defer stop()
+
+	for event := range subscription.Events {
+		fmt.Printf("%s: %s payment %d\n", replayer.Time(), event.EventName, event.Identifier)
+
+		if replayer.Done() {
+			stop()
+		}
+	}
+}
+
+func TestReplayerSubscription(t *testing.T) {
+	var (
+		start       = time.Date(2018, 10, 30, 7, 0, 0, 0, time.UTC)
+		paymentsURL = "http://localhost:5001/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9"
+		firstEvent  = `{"event":"EventPaymentReceivedSuccess","amount":5,"identifier":1,"log_time":"2018-10-30T07:00:00Z"}`
+		secondEvent = `{"event":"EventPaymentReceivedSuccess","amount":7,"identifier":2,"log_time":"2018-10-30T08:00:00Z"}`
+		recording   = &transport.Recording{Exchanges: []*transport.Exchange{
+			&transport.Exchange{Time: start, Method: "GET", URL: "http://localhost:5001/api/v1/channels", StatusCode: http.StatusOK,
+				Body: []byte(`[{"channel_identifier":1,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","state":"opened"}]`)},
+			&transport.Exchange{Time: start, Method: "GET", URL: paymentsURL, StatusCode: http.StatusOK, Body: []byte("[" + firstEvent + "]")},
+			&transport.Exchange{Time: start.Add(time.Hour), Method: "GET", URL: paymentsURL, StatusCode: http.StatusOK, Body: []byte("[" + firstEvent + "," + secondEvent + "]")},
+		}}
+		replayer     = transport.NewReplayer(recording, &transport.ReplayOptions{Speed: 36000})
+		config       = &config.Config{Host: "http://localhost:5001", APIVersion: "v1"}
+		ctx, cancel  = context.WithTimeout(context.Background(), 5*time.Second)
+		subscription = events.NewSubscriber(config, &http.Client{Transport: replayer}).SubscribePayments(ctx, &events.SubscribeOptions{Interval: time.Millisecond})
+	)
+	defer cancel()
+
+	first := <-subscription.Events
+	require.NotNil(t, first)
+	assert.Equal(t, int64(1), first.Identifier)
+	assert.False(t, replayer.Done())
+
+	second := <-subscription.Events
+	require.NotNil(t, second)
+	assert.Equal(t, int64(2), second.Identifier)
+	assert.False(t, replayer.Time().Before(start.Add(time.Hour)))
+}
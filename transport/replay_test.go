@@ -0,0 +1,193 @@
+package transport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	var (
+		version int
+		now     = time.Date(2018, 10, 30, 7, 0, 0, 0, time.UTC)
+		server  = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := ioutil.ReadAll(request.Body)
+			writer.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(writer, `{"version":%d,"request":%q}`, version, body)
+		}))
+		recorder   = NewRecorder(nil)
+		httpClient = &http.Client{Transport: recorder}
+	)
+	defer server.Close()
+
+	recorder.now = func() time.Time { return now }
+
+	for version = 1; version <= 2; version++ {
+		request, err := http.NewRequest("GET", server.URL+"/api/v1/channels", nil)
+		require.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer secret")
+
+		response, err := httpClient.Do(request)
+		require.NoError(t, err)
+
+		body, err := ioutil.ReadAll(response.Body)
+		require.NoError(t, err)
+		response.Body.Close()
+
+		assert.Equal(t, fmt.Sprintf(`{"version":%d,"request":""}`, version), string(body))
+
+		now = now.Add(time.Minute)
+	}
+
+	response, err := httpClient.Post(server.URL+"/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9", "application/json", strings.NewReader(`{"amount":10}`))
+	require.NoError(t, err)
+	response.Body.Close()
+
+	dir, err := ioutil.TempDir("", "transport")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "recording.json")
+	require.NoError(t, recorder.Recording().Save(path))
+
+	contents, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(contents), "secret")
+
+	recording, err := LoadRecording(path)
+	require.NoError(t, err)
+	require.Len(t, recording.Exchanges, 3)
+
+	assert.Equal(t, time.Date(2018, 10, 30, 7, 1, 0, 0, time.UTC), recording.Exchanges[1].Time)
+	assert.Equal(t, "GET", recording.Exchanges[1].Method)
+	assert.Equal(t, server.URL+"/api/v1/channels", recording.Exchanges[1].URL)
+	assert.Equal(t, http.StatusOK, recording.Exchanges[1].StatusCode)
+	assert.Equal(t, "application/json", recording.Exchanges[1].Header.Get("Content-Type"))
+	assert.Equal(t, `{"version":2,"request":""}`, string(recording.Exchanges[1].Body))
+	assert.Equal(t, `{"amount":10}`, string(recording.Exchanges[2].RequestBody))
+	assert.Equal(t, `{"version":3,"request":"{\"amount\":10}"}`, string(recording.Exchanges[2].Body))
+}
+
+func TestRecorderRedaction(t *testing.T) {
+	var (
+		server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			http.SetCookie(writer, &http.Cookie{Name: "session", Value: "s3ss10n"})
+			fmt.Fprint(writer, `{"identifier":1,"secret":"0x8f1c"}`)
+		}))
+		recorder   = NewRecorder(nil)
+		httpClient = &http.Client{Transport: recorder}
+		requestURL = strings.Replace(server.URL, "http://", "http://raiden:hunter2@", 1) + "/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9?api_key=k3y&limit=2"
+	)
+	defer server.Close()
+
+	response, err := httpClient.Post(requestURL, "application/json", strings.NewReader(`{"amount":10,"secret":"0x8f1c","secret_hash":"0x2a"}`))
+	require.NoError(t, err)
+
+	body, err := ioutil.ReadAll(response.Body)
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, `{"identifier":1,"secret":"0x8f1c"}`, string(body), "the caller gets the response as is")
+
+	recording := recorder.Recording()
+	require.Len(t, recording.Exchanges, 1)
+
+	exchange := recording.Exchanges[0]
+	assert.Equal(t, strings.Replace(server.URL, "http://", "http://REDACTED@", 1)+"/api/v1/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9?api_key=REDACTED&limit=2", exchange.URL)
+	assert.Equal(t, `{"amount":10,"secret":"REDACTED","secret_hash":"0x2a"}`, string(exchange.RequestBody))
+	assert.Equal(t, `{"identifier":1,"secret":"REDACTED"}`, string(exchange.Body), "the node echoes the secret of the payment")
+	assert.Equal(t, []string{"REDACTED"}, exchange.Header["Set-Cookie"])
+
+	// the requests are replayed with their credentials
+	replayed, err := (&http.Client{Transport: NewReplayer(recording, nil)}).Post(requestURL, "application/json", nil)
+	require.NoError(t, err)
+	defer replayed.Body.Close()
+
+	body, err = ioutil.ReadAll(replayed.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"identifier":1,"secret":"REDACTED"}`, string(body))
+}
+
+func TestReplayer(t *testing.T) {
+	var (
+		start     = time.Date(2018, 10, 30, 7, 0, 0, 0, time.UTC)
+		manual    = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+		elapsed   time.Duration
+		recording = &Recording{Exchanges: []*Exchange{
+			&Exchange{Time: start, Method: "GET", URL: "http://localhost:5001/api/v1/channels", StatusCode: http.StatusOK, Body: []byte(`"version 1"`)},
+			&Exchange{Time: start.Add(10 * time.Second), Method: "GET", URL: "http://localhost:5001/api/v1/channels", StatusCode: http.StatusServiceUnavailable, Body: []byte(`"version 2"`)},
+			&Exchange{Time: start.Add(20 * time.Second), Method: "GET", URL: "http://localhost:5001/api/v1/channels", StatusCode: http.StatusOK, Body: []byte(`"version 3"`)},
+		}}
+		replayer   = NewReplayer(recording, &ReplayOptions{Speed: 10, Clock: manual})
+		httpClient = &http.Client{Transport: replayer}
+	)
+
+	type testcase struct {
+		name               string
+		elapsed            time.Duration
+		expectedStatusCode int
+		expectedBody       string
+		expectedDone       bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:               "start of the recording",
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `"version 1"`,
+		},
+		testcase{
+			name:               "accelerated to the second exchange",
+			elapsed:            time.Second,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       `"version 2"`,
+		},
+		testcase{
+			name:               "between exchanges",
+			elapsed:            1500 * time.Millisecond,
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedBody:       `"version 2"`,
+		},
+		testcase{
+			name:               "end of the recording",
+			elapsed:            3 * time.Second,
+			expectedStatusCode: http.StatusOK,
+			expectedBody:       `"version 3"`,
+			expectedDone:       true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			manual.Advance(tc.elapsed - elapsed)
+			elapsed = tc.elapsed
+
+			response, err := httpClient.Get("http://localhost:5001/api/v1/channels")
+			require.NoError(t, err)
+
+			body, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+			response.Body.Close()
+
+			assert.Equal(t, tc.expectedStatusCode, response.StatusCode)
+			assert.Equal(t, tc.expectedBody, string(body))
+			assert.Equal(t, tc.expectedDone, replayer.Done())
+			assert.Equal(t, start.Add(10*tc.elapsed), replayer.Time())
+		})
+	}
+
+	t.Run("request that was not recorded", func(t *testing.T) {
+		_, err := httpClient.Get("http://localhost:5001/api/v1/tokens")
+
+		assert.Contains(t, err.Error(), "no recorded response to GET http://localhost:5001/api/v1/tokens")
+	})
+}
@@ -0,0 +1,417 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/redact"
+)
+
+const (
+	// DefaultShadowTimeout is the deadline of the requests mirrored to the shadow node
+	// when the options give none.
+	DefaultShadowTimeout = 10 * time.Second
+	// DefaultShadowConcurrency is the number of reads mirrored at once when the
+	// options give none.
+	DefaultShadowConcurrency = 16
+	// maxDifferences is the number of differences reported for a divergence, the
+	// first ones in the order of the fields.
+	maxDifferences = 10
+)
+
+// ShadowOptions configures a shadow: the reads are mirrored to the node at Host,
+// e.g. "http://localhost:5002", the host of the request when empty, the path of Host
+// being the base path of the shadow node, see config.Config.BasePath, with the API
+// version of their path replaced by APIVersion when given, e.g. to compare "v1" with
+// "v2" of the same node. The mirrored requests are sent through Transport,
+// http.DefaultTransport when nil, within Timeout, DefaultShadowTimeout when zero. At
+// most MaxConcurrent reads are mirrored at once, DefaultShadowConcurrency when zero,
+// the reads made while they all are being dropped, so that a slow shadow node never
+// piles up goroutines. IgnoreFields are the names of the fields of JSON objects left out of the
+// comparison, e.g. fields a newer node adds. Report is called with every divergence,
+// timed by Clock, the system time when nil.
+type ShadowOptions struct {
+	Host          string
+	APIVersion    string
+	Transport     http.RoundTripper
+	Timeout       time.Duration
+	MaxConcurrent int
+	IgnoreFields  []string
+	Report        func(divergence *Divergence)
+	Clock         clock.Clock
+}
+
+// Divergence is a read the shadow node answered differently from the primary one:
+// with another status code, another body, listed in Differences as the JSON paths
+// whose values differ, or with the error of the mirrored request. Its URL is redacted,
+// see redact.URL.
+type Divergence struct {
+	Time          time.Time
+	Method        string
+	URL           string
+	PrimaryStatus int
+	ShadowStatus  int
+	Differences   []string
+	Err           error
+}
+
+func (divergence *Divergence) String() string {
+	switch {
+	case divergence.Err != nil:
+		return fmt.Sprintf("%s %s: shadow failed: %s", divergence.Method, divergence.URL, redact.String(divergence.Err.Error()))
+	case divergence.PrimaryStatus != divergence.ShadowStatus:
+		return fmt.Sprintf("%s %s: status %d, shadow %d", divergence.Method, divergence.URL, divergence.PrimaryStatus, divergence.ShadowStatus)
+	}
+
+	return fmt.Sprintf("%s %s: %s", divergence.Method, divergence.URL, strings.Join(divergence.Differences, ", "))
+}
+
+// ShadowStats counts the reads a shadow mirrored, the ones the shadow node answered
+// differently and, among those, the ones it failed to answer, along with the reads
+// not mirrored because MaxConcurrent ones already were being.
+type ShadowStats struct {
+	Mirrored int64
+	Diverged int64
+	Failed   int64
+	Dropped  int64
+}
+
+// Shadow is a transport mirroring the reads of a client to a second Raiden node, or
+// to another API version of the same node, and comparing their responses, so that an
+// upgrade can be checked against production traffic. The caller always gets the
+// response of the primary node: the reads are mirrored in the background once it
+// answered, and the divergences are only reported, see ShadowOptions.
+type Shadow struct {
+	next      http.RoundTripper
+	transport http.RoundTripper
+	host      *url.URL
+	hostErr   error
+	version   string
+	timeout   time.Duration
+	ignore    map[string]bool
+	report    func(divergence *Divergence)
+	now       func() time.Time
+	slots     chan struct{}
+
+	waitGroup sync.WaitGroup
+	stats     ShadowStats
+}
+
+// NewShadow returns a transport sending the requests through the next transport and
+// mirroring the GET ones to the shadow node of the options, see Shadow. A nil next
+// transport is http.DefaultTransport. An invalid Host fails every mirrored read.
+func NewShadow(next http.RoundTripper, options *ShadowOptions) *Shadow {
+	var shadow = &Shadow{
+		next:      next,
+		transport: http.DefaultTransport,
+		timeout:   DefaultShadowTimeout,
+		ignore:    make(map[string]bool),
+	}
+
+	if shadow.next == nil {
+		shadow.next = http.DefaultTransport
+	}
+
+	if options == nil {
+		options = &ShadowOptions{}
+	}
+
+	if options.Host != "" {
+		if shadow.host, shadow.hostErr = url.Parse(options.Host); shadow.hostErr != nil {
+			shadow.hostErr = fmt.Errorf("invalid shadow host: %s", shadow.hostErr.Error())
+		}
+	}
+
+	if options.Transport != nil {
+		shadow.transport = options.Transport
+	}
+
+	shadow.now = clock.Or(options.Clock).Now
+
+	if options.Timeout > 0 {
+		shadow.timeout = options.Timeout
+	}
+
+	if options.MaxConcurrent > 0 {
+		shadow.slots = make(chan struct{}, options.MaxConcurrent)
+	} else {
+		shadow.slots = make(chan struct{}, DefaultShadowConcurrency)
+	}
+
+	for _, field := range options.IgnoreFields {
+		shadow.ignore[field] = true
+	}
+
+	shadow.version = options.APIVersion
+	shadow.report = options.Report
+
+	return shadow
+}
+
+func (shadow *Shadow) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Method != "GET" {
+		return shadow.next.RoundTrip(request)
+	}
+
+	response, err := shadow.next.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var primary = &sharedResponse{response: response, body: body}
+
+	select {
+	case shadow.slots <- struct{}{}:
+		shadow.waitGroup.Add(1)
+
+		go func() {
+			defer shadow.waitGroup.Done()
+			defer func() { <-shadow.slots }()
+
+			shadow.mirror(request, primary)
+		}()
+	default:
+		atomic.AddInt64(&shadow.stats.Dropped, 1)
+	}
+
+	return primary.copy(request), nil
+}
+
+// Stats returns the counts of the reads mirrored so far.
+func (shadow *Shadow) Stats() ShadowStats {
+	return ShadowStats{
+		Mirrored: atomic.LoadInt64(&shadow.stats.Mirrored),
+		Diverged: atomic.LoadInt64(&shadow.stats.Diverged),
+		Failed:   atomic.LoadInt64(&shadow.stats.Failed),
+		Dropped:  atomic.LoadInt64(&shadow.stats.Dropped),
+	}
+}
+
+// Wait waits for the reads being mirrored, e.g. before a batch job exits.
+func (shadow *Shadow) Wait() {
+	shadow.waitGroup.Wait()
+}
+
+// mirror sends the request to the shadow node and compares its response with the
+// primary one. The request is not cancelled with its context, which ends as soon as
+// the caller is done with the primary response.
+func (shadow *Shadow) mirror(request *http.Request, primary *sharedResponse) {
+	var divergence = &Divergence{
+		Method:        request.Method,
+		URL:           redact.URL(request.URL),
+		PrimaryStatus: primary.response.StatusCode,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shadow.timeout)
+	defer cancel()
+
+	atomic.AddInt64(&shadow.stats.Mirrored, 1)
+
+	var (
+		err      error
+		mirrored *http.Request
+	)
+
+	if err = shadow.hostErr; err == nil {
+		mirrored, err = http.NewRequest(request.Method, shadow.url(request.URL).String(), nil)
+	}
+
+	if err == nil {
+		mirrored = mirrored.WithContext(ctx)
+		mirrored.Header = make(http.Header, len(request.Header))
+
+		for key, values := range request.Header {
+			mirrored.Header[key] = append([]string(nil), values...)
+		}
+
+		var response *http.Response
+
+		if response, err = shadow.transport.RoundTrip(mirrored); err == nil {
+			var body []byte
+
+			body, err = ioutil.ReadAll(response.Body)
+			response.Body.Close()
+
+			divergence.ShadowStatus = response.StatusCode
+			divergence.Differences = shadow.compare(primary.body, body)
+		}
+	}
+
+	divergence.Err = err
+
+	if err == nil && divergence.PrimaryStatus == divergence.ShadowStatus && len(divergence.Differences) == 0 {
+		return
+	}
+
+	atomic.AddInt64(&shadow.stats.Diverged, 1)
+
+	if err != nil {
+		atomic.AddInt64(&shadow.stats.Failed, 1)
+	}
+
+	if shadow.report != nil {
+		divergence.Time = shadow.now()
+		shadow.report(divergence)
+	}
+}
+
+// url returns the URL of the request on the shadow node.
+func (shadow *Shadow) url(requestURL *url.URL) *url.URL {
+	var mirrored = *requestURL
+
+	if shadow.host != nil {
+		mirrored.Scheme = shadow.host.Scheme
+		mirrored.Host = shadow.host.Host
+		mirrored.User = shadow.host.User
+		mirrored.Path = strings.TrimSuffix(shadow.host.Path, "/") + requestURL.Path
+		mirrored.RawPath = ""
+
+		// the path of the host is the base path of the shadow node, in place of the
+		// one of the primary node
+		if _, version, relative, ok := config.SplitAPIPath(requestURL.Path); ok {
+			mirrored.Path = strings.TrimSuffix(strings.TrimSuffix(shadow.host.Path, "/")+"/api/"+version+"/"+relative, "/")
+		}
+	}
+
+	if shadow.version != "" {
+		// the version is the segment after the "api" one, e.g. /api/v1/channels,
+		// under the base path of the node, see config.SplitAPIPath
+		if basePath, _, relative, ok := config.SplitAPIPath(mirrored.Path); ok {
+			mirrored.Path = strings.TrimSuffix(basePath+"/api/"+shadow.version+"/"+relative, "/")
+			mirrored.RawPath = ""
+		}
+	}
+
+	return &mirrored
+}
+
+// compare returns the differences between the bodies, compared as JSON when both
+// are, and as bytes otherwise.
+func (shadow *Shadow) compare(primaryBody, shadowBody []byte) []string {
+	var (
+		differences = make([]string, 0)
+		primary     interface{}
+		mirrored    interface{}
+	)
+
+	if decodeJSON(primaryBody, &primary) != nil || decodeJSON(shadowBody, &mirrored) != nil {
+		if !bytes.Equal(primaryBody, shadowBody) {
+			differences = append(differences, "body")
+		}
+
+		return differences
+	}
+
+	diffJSON("$", primary, mirrored, shadow.ignore, &differences)
+
+	return differences
+}
+
+func decodeJSON(body []byte, value *interface{}) error {
+	var decoder = json.NewDecoder(bytes.NewReader(body))
+
+	// numbers are compared as written, amounts exceeding the precision of floats
+	decoder.UseNumber()
+
+	return decoder.Decode(value)
+}
+
+// diffJSON appends the paths whose values differ between the decoded JSON values.
+func diffJSON(path string, primary, mirrored interface{}, ignore map[string]bool, differences *[]string) {
+	if len(*differences) >= maxDifferences {
+		return
+	}
+
+	switch primaryValue := primary.(type) {
+	case map[string]interface{}:
+		mirroredValue, ok := mirrored.(map[string]interface{})
+		if !ok {
+			break
+		}
+
+		keys := make([]string, 0, len(primaryValue)+len(mirroredValue))
+		for key := range primaryValue {
+			keys = append(keys, key)
+		}
+
+		for key := range mirroredValue {
+			if _, ok := primaryValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if !ignore[key] {
+				diffJSON(path+"."+key, field(primaryValue, key), field(mirroredValue, key), ignore, differences)
+			}
+		}
+
+		return
+	case []interface{}:
+		mirroredValue, ok := mirrored.([]interface{})
+		if !ok {
+			break
+		}
+
+		if len(primaryValue) != len(mirroredValue) {
+			*differences = append(*differences, fmt.Sprintf("%s: %d items != %d items", path, len(primaryValue), len(mirroredValue)))
+			return
+		}
+
+		for i := range primaryValue {
+			diffJSON(fmt.Sprintf("%s[%d]", path, i), primaryValue[i], mirroredValue[i], ignore, differences)
+		}
+
+		return
+	}
+
+	if !reflect.DeepEqual(primary, mirrored) {
+		*differences = append(*differences, fmt.Sprintf("%s: %s != %s", path, formatJSON(primary), formatJSON(mirrored)))
+	}
+}
+
+// missingField is the value of a field an object lacks.
+type missingField struct{}
+
+func field(object map[string]interface{}, key string) interface{} {
+	if value, ok := object[key]; ok {
+		return value
+	}
+
+	return missingField{}
+}
+
+func formatJSON(value interface{}) string {
+	if _, ok := value.(missingField); ok {
+		return "missing"
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
@@ -0,0 +1,323 @@
+package transport
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleShadow() {
+	var (
+		shadow = NewShadow(nil, &ShadowOptions{
+			Host: "http://raiden-next:5001",
+			Report: func(divergence *Divergence) {
+				fmt.Printf("divergence: %s\n", divergence.String())
+			},
+		})
+		httpClient = &http.Client{Transport: shadow}
+	)
+
+	// the reads of the http client are compared with the ones of raiden-next
+	if response, err := httpClient.Get("http://localhost:5001/api/v1/channels"); err == nil {
+		response.Body.Close()
+	}
+
+	shadow.Wait()
+	fmt.Printf("%d reads diverged\n", shadow.Stats().Diverged)
+}
+
+func TestShadow(t *testing.T) {
+	type testcase struct {
+		name                string
+		path                string
+		method              string
+		options             *ShadowOptions
+		primaryStatus       int
+		primaryBody         string
+		shadowStatus        int
+		shadowBody          string
+		expectedPath        string
+		expectedMirrored    int64
+		expectedDivergence  string
+		expectedDifferences []string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:             "same response",
+			path:             "/api/v1/channels",
+			method:           "GET",
+			primaryBody:      `[{"balance":30,"state":"opened"}]`,
+			shadowBody:       `[{"state":"opened","balance":30}]`,
+			expectedPath:     "/api/v1/channels",
+			expectedMirrored: 1,
+		},
+		testcase{
+			name:                "different fields",
+			path:                "/api/v1/channels",
+			method:              "GET",
+			primaryBody:         `[{"balance":30,"state":"opened","total_deposit":"100000000000000000001"}]`,
+			shadowBody:          `[{"balance":40,"state":"opened","total_deposit":"100000000000000000001","fee":1}]`,
+			expectedPath:        "/api/v1/channels",
+			expectedMirrored:    1,
+			expectedDivergence:  "GET /api/v1/channels: $[0].balance: 30 != 40, $[0].fee: missing != 1",
+			expectedDifferences: []string{"$[0].balance: 30 != 40", "$[0].fee: missing != 1"},
+		},
+		testcase{
+			name:               "ignored fields",
+			path:               "/api/v1/channels",
+			method:             "GET",
+			options:            &ShadowOptions{IgnoreFields: []string{"fee"}},
+			primaryBody:        `[{"balance":30}]`,
+			shadowBody:         `[{"balance":30,"fee":1}]`,
+			expectedPath:       "/api/v1/channels",
+			expectedMirrored:   1,
+			expectedDivergence: "",
+		},
+		testcase{
+			name:                "different lengths",
+			path:                "/api/v1/tokens",
+			method:              "GET",
+			primaryBody:         `["0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"]`,
+			shadowBody:          `[]`,
+			expectedPath:        "/api/v1/tokens",
+			expectedMirrored:    1,
+			expectedDivergence:  "GET /api/v1/tokens: $: 1 items != 0 items",
+			expectedDifferences: []string{"$: 1 items != 0 items"},
+		},
+		testcase{
+			name:               "different status",
+			path:               "/api/v1/address",
+			method:             "GET",
+			primaryBody:        `{"our_address":"0x"}`,
+			shadowStatus:       http.StatusNotFound,
+			shadowBody:         `{"errors":"not found"}`,
+			expectedPath:       "/api/v1/address",
+			expectedMirrored:   1,
+			expectedDivergence: "GET /api/v1/address: status 200, shadow 404",
+			expectedDifferences: []string{
+				"$.errors: missing != \"not found\"",
+				"$.our_address: \"0x\" != missing",
+			},
+		},
+		testcase{
+			name:                "other api version",
+			path:                "/api/v1/version",
+			method:              "GET",
+			options:             &ShadowOptions{APIVersion: "v2"},
+			primaryBody:         `text`,
+			shadowBody:          `other text`,
+			expectedPath:        "/api/v2/version",
+			expectedMirrored:    1,
+			expectedDivergence:  "GET /api/v1/version: body",
+			expectedDifferences: []string{"body"},
+		},
+		testcase{
+			name:             "writes are not mirrored",
+			path:             "/api/v1/channels",
+			method:           "PUT",
+			primaryBody:      `{}`,
+			expectedMirrored: 0,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mutex       sync.Mutex
+				shadowPaths []string
+				divergences []*Divergence
+			)
+
+			primaryServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				if tc.primaryStatus != 0 {
+					writer.WriteHeader(tc.primaryStatus)
+				}
+
+				writer.Write([]byte(tc.primaryBody))
+			}))
+			defer primaryServer.Close()
+
+			shadowServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+				mutex.Lock()
+				shadowPaths = append(shadowPaths, request.URL.Path)
+				mutex.Unlock()
+
+				assert.Equal(t, "Bearer secret", request.Header.Get("Authorization"))
+
+				if tc.shadowStatus != 0 {
+					writer.WriteHeader(tc.shadowStatus)
+				}
+
+				writer.Write([]byte(tc.shadowBody))
+			}))
+			defer shadowServer.Close()
+
+			options := tc.options
+			if options == nil {
+				options = &ShadowOptions{}
+			}
+
+			options.Host = shadowServer.URL
+			options.Report = func(divergence *Divergence) {
+				mutex.Lock()
+				divergences = append(divergences, divergence)
+				mutex.Unlock()
+			}
+
+			shadow := NewShadow(nil, options)
+
+			request, err := http.NewRequest(tc.method, primaryServer.URL+tc.path, strings.NewReader(""))
+			require.NoError(t, err)
+			request.Header.Set("Authorization", "Bearer secret")
+
+			response, err := (&http.Client{Transport: shadow}).Do(request)
+			require.NoError(t, err)
+
+			body, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+			response.Body.Close()
+
+			// the caller always gets the primary response
+			assert.Equal(t, tc.primaryBody, string(body))
+
+			shadow.Wait()
+
+			assert.Equal(t, tc.expectedMirrored, shadow.Stats().Mirrored)
+
+			if tc.expectedMirrored == 0 {
+				assert.Empty(t, shadowPaths)
+				return
+			}
+
+			assert.Equal(t, []string{tc.expectedPath}, shadowPaths)
+
+			if tc.expectedDivergence == "" {
+				assert.Empty(t, divergences)
+				assert.Zero(t, shadow.Stats().Diverged)
+				return
+			}
+
+			require.Len(t, divergences, 1)
+			assert.Equal(t, tc.expectedDivergence, strings.Replace(divergences[0].String(), primaryServer.URL, "", 1))
+			assert.Equal(t, tc.expectedDifferences, divergences[0].Differences)
+			assert.Equal(t, int64(1), shadow.Stats().Diverged)
+		})
+	}
+}
+
+func TestShadowFailure(t *testing.T) {
+	var divergences = make(chan *Divergence, 1)
+
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte(`[]`))
+	}))
+	defer primaryServer.Close()
+
+	shadowServer := httptest.NewServer(http.NotFoundHandler())
+	shadowServer.Close()
+
+	shadow := NewShadow(nil, &ShadowOptions{Host: shadowServer.URL, Report: func(divergence *Divergence) { divergences <- divergence }})
+
+	response, err := (&http.Client{Transport: shadow}).Get(primaryServer.URL + "/api/v1/channels")
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	divergence := <-divergences
+	assert.Error(t, divergence.Err)
+	assert.Contains(t, divergence.String(), "shadow failed")
+
+	shadow.Wait()
+	assert.Equal(t, ShadowStats{Mirrored: 1, Diverged: 1, Failed: 1}, shadow.Stats())
+
+	invalid := NewShadow(nil, &ShadowOptions{Host: "http://[::1", Report: func(divergence *Divergence) { divergences <- divergence }})
+
+	response, err = (&http.Client{Transport: invalid}).Get(primaryServer.URL + "/api/v1/channels")
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Contains(t, (<-divergences).Err.Error(), "invalid shadow host")
+}
+
+func TestShadowConcurrency(t *testing.T) {
+	var release = make(chan struct{})
+
+	primaryServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.Write([]byte(`[]`))
+	}))
+	defer primaryServer.Close()
+
+	shadowServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		<-release
+		writer.Write([]byte(`[]`))
+	}))
+	defer shadowServer.Close()
+
+	var (
+		shadow     = NewShadow(nil, &ShadowOptions{Host: shadowServer.URL, MaxConcurrent: 2})
+		httpClient = &http.Client{Transport: shadow}
+	)
+
+	for i := 0; i < 5; i++ {
+		response, err := httpClient.Get(primaryServer.URL + "/api/v1/channels")
+		require.NoError(t, err)
+		response.Body.Close()
+
+		assert.Equal(t, http.StatusOK, response.StatusCode, "the primary response is never held back")
+	}
+
+	close(release)
+	shadow.Wait()
+
+	assert.Equal(t, ShadowStats{Mirrored: 2, Dropped: 3}, shadow.Stats())
+}
+
+func TestShadowURL(t *testing.T) {
+	type testcase struct {
+		name        string
+		options     *ShadowOptions
+		requestURL  string
+		expectedURL string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:        "host",
+			options:     &ShadowOptions{Host: "http://raiden-next:5001"},
+			requestURL:  "http://localhost:5001/api/v1/channels?limit=10",
+			expectedURL: "http://raiden-next:5001/api/v1/channels?limit=10",
+		},
+		testcase{
+			name:        "base paths",
+			options:     &ShadowOptions{Host: "https://gateway.example.com/raiden/next"},
+			requestURL:  "https://gateway.example.com/raiden/node-3/api/v1/channels",
+			expectedURL: "https://gateway.example.com/raiden/next/api/v1/channels",
+		},
+		testcase{
+			name:        "base path and version",
+			options:     &ShadowOptions{Host: "http://raiden-next:5001", APIVersion: "v2"},
+			requestURL:  "https://gateway.example.com/raiden/node-3/api/v1/address",
+			expectedURL: "http://raiden-next:5001/api/v2/address",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var shadow = NewShadow(nil, tc.options)
+
+			requestURL, err := url.Parse(tc.requestURL)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedURL, shadow.url(requestURL).String())
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package userdeposit
+
+import (
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+var (
+	_ Depositor       = &Client{}
+	_ WithdrawPlanner = &Client{}
+	_ Withdrawer      = &Client{}
+)
+
+// NewClient creates a new user deposit client that provides access to depositing in
+// and withdrawing from the user deposit contract.
+func NewClient(config *config.Config, httpClient *http.Client) *Client {
+	return &Client{
+		Depositor:       NewDepositor(config, httpClient),
+		WithdrawPlanner: NewWithdrawPlanner(config, httpClient),
+		Withdrawer:      NewWithdrawer(config, httpClient),
+	}
+}
+
+// Client is a user deposit client that allows access to the user deposit HTTP calls
+// to a Raiden node.
+type Client struct {
+	Depositor
+	WithdrawPlanner
+	Withdrawer
+}
@@ -0,0 +1,65 @@
+// Package userdeposit manages the deposit of the Raiden node in the user deposit
+// contract (UDC), from which the node pays the monitoring and pathfinding services.
+package userdeposit
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type depositRequest struct {
+	TotalDeposit json.Number `json:"total_deposit"`
+}
+
+type transactionResponse struct {
+	TransactionHash common.Hash `json:"transaction_hash"`
+}
+
+// Depositor is a generic interface to deposit tokens in the user deposit contract, up
+// to a new total deposit.
+type Depositor interface {
+	Deposit(ctx context.Context, totalDeposit *big.Int) (common.Hash, error)
+}
+
+// NewDepositor creates a new default depositor given a Raiden node configuration and
+// an http client.
+func NewDepositor(config *config.Config, httpClient *http.Client) Depositor {
+	return &defaultDepositor{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultDepositor struct {
+	baseClient *util.BaseClient
+}
+
+// Deposit will raise the total deposit of the node in the user deposit contract and
+// return the hash of the transaction once it is mined.
+func (depositor *defaultDepositor) Deposit(ctx context.Context, totalDeposit *big.Int) (common.Hash, error) {
+	var (
+		err                 error
+		requestURL          *url.URL
+		transactionResponse = &transactionResponse{}
+	)
+
+	if requestURL, err = depositor.baseClient.Endpoint("user_deposit"); err != nil {
+		return common.Hash{}, err
+	}
+
+	if err = depositor.baseClient.Call(ctx, "POST", requestURL, &depositRequest{TotalDeposit: amounts.Number(totalDeposit)}, transactionResponse); err != nil {
+		return common.Hash{}, err
+	}
+
+	return transactionResponse.TransactionHash, nil
+}
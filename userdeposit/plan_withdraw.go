@@ -0,0 +1,66 @@
+package userdeposit
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type planWithdrawRequest struct {
+	PlannedWithdrawAmount json.Number `json:"planned_withdraw_amount"`
+}
+
+// PlannedWithdraw is a withdraw planned in the user deposit contract, which may be
+// made from BlockNumber on.
+type PlannedWithdraw struct {
+	TransactionHash common.Hash `json:"transaction_hash"`
+	BlockNumber     int64       `json:"planned_withdraw_block_number"`
+}
+
+// WithdrawPlanner is a generic interface to plan a withdraw from the user deposit
+// contract, which has to be planned before it is made.
+type WithdrawPlanner interface {
+	PlanWithdraw(ctx context.Context, amount *big.Int) (*PlannedWithdraw, error)
+}
+
+// NewWithdrawPlanner creates a new default withdraw planner given a Raiden node
+// configuration and an http client.
+func NewWithdrawPlanner(config *config.Config, httpClient *http.Client) WithdrawPlanner {
+	return &defaultWithdrawPlanner{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultWithdrawPlanner struct {
+	baseClient *util.BaseClient
+}
+
+// PlanWithdraw will plan the withdraw of the amount from the user deposit contract
+// and return the block number from which it may be withdrawn.
+func (planner *defaultWithdrawPlanner) PlanWithdraw(ctx context.Context, amount *big.Int) (*PlannedWithdraw, error) {
+	var (
+		err             error
+		requestURL      *url.URL
+		plannedWithdraw = &PlannedWithdraw{}
+	)
+
+	if requestURL, err = planner.baseClient.Endpoint("user_deposit"); err != nil {
+		return nil, err
+	}
+
+	if err = planner.baseClient.Call(ctx, "POST", requestURL, &planWithdrawRequest{PlannedWithdrawAmount: amounts.Number(amount)}, plannedWithdraw); err != nil {
+		return nil, err
+	}
+
+	return plannedWithdraw, nil
+}
@@ -0,0 +1,59 @@
+package userdeposit
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/amounts"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type withdrawRequest struct {
+	WithdrawAmount json.Number `json:"withdraw_amount"`
+}
+
+// Withdrawer is a generic interface to withdraw tokens from the user deposit contract.
+type Withdrawer interface {
+	Withdraw(ctx context.Context, amount *big.Int) (common.Hash, error)
+}
+
+// NewWithdrawer creates a new default withdrawer given a Raiden node configuration
+// and an http client.
+func NewWithdrawer(config *config.Config, httpClient *http.Client) Withdrawer {
+	return &defaultWithdrawer{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultWithdrawer struct {
+	baseClient *util.BaseClient
+}
+
+// Withdraw will withdraw the amount from the user deposit contract, which has to be
+// planned first, see WithdrawPlanner, and return the hash of the transaction once it
+// is mined.
+func (withdrawer *defaultWithdrawer) Withdraw(ctx context.Context, amount *big.Int) (common.Hash, error) {
+	var (
+		err                 error
+		requestURL          *url.URL
+		transactionResponse = &transactionResponse{}
+	)
+
+	if requestURL, err = withdrawer.baseClient.Endpoint("user_deposit"); err != nil {
+		return common.Hash{}, err
+	}
+
+	if err = withdrawer.baseClient.Call(ctx, "POST", requestURL, &withdrawRequest{WithdrawAmount: amounts.Number(amount)}, transactionResponse); err != nil {
+		return common.Hash{}, err
+	}
+
+	return transactionResponse.TransactionHash, nil
+}
@@ -0,0 +1,83 @@
+package userdeposit
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithdrawer(t *testing.T) {
+	type testcase struct {
+		name                    string
+		prepHTTPMock            func(body *string)
+		expectedBody            string
+		expectedTransactionHash common.Hash
+		expectedError           error
+	}
+
+	var (
+		config = &config.Config{
+			Host:       "http://localhost:5001",
+			APIVersion: "v1",
+		}
+	)
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully withdrawn",
+			prepHTTPMock: func(body *string) {
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/user_deposit", func(request *http.Request) (*http.Response, error) {
+					read, _ := ioutil.ReadAll(request.Body)
+					*body = string(read)
+
+					return httpmock.NewStringResponse(http.StatusOK, `{"transaction_hash":"0x5a7b3e6bbb4a1ed6a4ab0b7d2e0e7bfe8f3ab1d2a1c7e54a5f4bd5c0e3a2a1b7"}`), nil
+				})
+			},
+			expectedBody:            `{"withdraw_amount":1500}`,
+			expectedTransactionHash: common.HexToHash("0x5a7b3e6bbb4a1ed6a4ab0b7d2e0e7bfe8f3ab1d2a1c7e54a5f4bd5c0e3a2a1b7"),
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func(body *string) {
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/user_deposit", httpmock.NewStringResponder(http.StatusInternalServerError, ``))
+			},
+			expectedError: errors.New("EOF"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				body       string
+				withdrawer = NewWithdrawer(config, http.DefaultClient)
+				ctx        = context.Background()
+			)
+
+			httpmock.Activate()
+			defer httpmock.Deactivate()
+
+			tc.prepHTTPMock(&body)
+
+			transactionHash, err := withdrawer.Withdraw(ctx, big.NewInt(1500))
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Zero(t, transactionHash)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.JSONEq(t, tc.expectedBody, body)
+			assert.Equal(t, tc.expectedTransactionHash, transactionHash)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package util
+
+import "github.com/ethereum/go-ethereum/common"
+
+// Address is an ethereum address that is marshalled as the EIP-55 checksummed hex
+// string used by the Raiden node, and unmarshalled like a common.Address.
+type Address common.Address
+
+// MarshalText returns the checksummed hex string of the address.
+func (address Address) MarshalText() ([]byte, error) {
+	return []byte(common.Address(address).Hex()), nil
+}
+
+// UnmarshalText parses a hex string as an address.
+func (address *Address) UnmarshalText(input []byte) error {
+	return (*common.Address)(address).UnmarshalText(input)
+}
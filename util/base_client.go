@@ -1,9 +1,15 @@
 package util
 
 import (
+	"context"
+	"io"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/meta"
 )
 
 // BaseClient serves as the HTTP client responsible for making all outbound requests
@@ -13,3 +19,270 @@ type BaseClient struct {
 	Config     *config.Config
 	HTTPClient *http.Client
 }
+
+// Do will send the request with the HTTP client. When the context of the request has
+// no deadline and the configuration sets a timeout for its endpoint, see timeout, the
+// request is given that deadline, which lasts until the body of the response is
+// closed. The fields of the context, see meta.WithFields, are sent in the
+// MetadataHeaders of the configuration, along with its Tenant when the context has
+// none. The request goes through the Middlewares of
+// the configuration, and is recorded in the metadata of the context when it has some,
+// see meta.WithMeta. Requests rejected while the node is syncing, or by a rate
+// limiting proxy, are sent again as configured by the SyncRetry of the configuration.
+// Every attempt of the request carries the same marker when the configuration has a
+// MarkerHeader.
+func (client *BaseClient) Do(request *http.Request) (*http.Response, error) {
+	if callMeta := meta.FromContext(request.Context()); callMeta != nil {
+		defer func(started time.Time) {
+			callMeta.Duration += time.Since(started)
+		}(time.Now())
+	}
+
+	if client.Config != nil && client.Config.MarkerHeader != "" {
+		request = client.mark(request, client.Config.MarkerHeader)
+	}
+
+	if client.Config != nil && client.Config.SyncRetry != nil && client.Config.SyncRetry.Budget > 0 {
+		return client.doSyncRetry(request, client.Config.SyncRetry)
+	}
+
+	return client.do(request)
+}
+
+// do sends the request once, see Do.
+func (client *BaseClient) do(request *http.Request) (*http.Response, error) {
+	var (
+		err      error
+		response *http.Response
+		cancel   context.CancelFunc = func() {}
+	)
+
+	if fields := client.fields(request.Context()); len(fields) > 0 {
+		request = client.withFields(request, fields)
+	}
+
+	if timeout := client.timeout(request); timeout > 0 {
+		if _, ok := request.Context().Deadline(); !ok {
+			var ctx context.Context
+
+			ctx, cancel = context.WithTimeout(request.Context(), timeout)
+			request = request.WithContext(ctx)
+		}
+	}
+
+	if response, err = client.doer()(request); err != nil {
+		cancel()
+		return nil, err
+	}
+
+	response.Body = newCancelBody(request.Context(), response.Body, cancel)
+
+	return response, nil
+}
+
+// mark returns a copy of the request with the marker of its context, or a new one, in
+// the header, recording the marker in the metadata of the context.
+func (client *BaseClient) mark(request *http.Request, headerName string) *http.Request {
+	var (
+		marker = meta.MarkerFromContext(request.Context())
+		header = make(http.Header, len(request.Header)+1)
+	)
+
+	if marker == "" {
+		marker = meta.NewMarker()
+	}
+
+	if callMeta := meta.FromContext(request.Context()); callMeta != nil {
+		callMeta.Marker = marker
+	}
+
+	for key, values := range request.Header {
+		header[key] = values
+	}
+
+	header.Set(headerName, marker)
+
+	request = request.WithContext(request.Context())
+	request.Header = header
+
+	return request
+}
+
+// fields returns the fields of the context, with the Tenant of the configuration when
+// the context carries none.
+func (client *BaseClient) fields(ctx context.Context) meta.Fields {
+	if client.Config == nil {
+		return meta.FieldsFromContext(ctx)
+	}
+
+	return meta.TenantFields(ctx, client.Config.Tenant)
+}
+
+// withFields returns a copy of the request with the headers of the fields.
+func (client *BaseClient) withFields(request *http.Request, fields meta.Fields) *http.Request {
+	var (
+		headers map[string]string
+		header  = make(http.Header, len(request.Header)+len(fields))
+	)
+
+	if client.Config != nil {
+		headers = client.Config.MetadataHeaders
+	}
+
+	for key, values := range request.Header {
+		header[key] = values
+	}
+
+	fields.SetHeaders(header, headers)
+
+	request = request.WithContext(request.Context())
+	request.Header = header
+
+	return request
+}
+
+// timeout returns the timeout of the class of the endpoint of the request in the
+// configuration, or the RequestTimeout when the class has none.
+func (client *BaseClient) timeout(request *http.Request) time.Duration {
+	if client.Config == nil {
+		return 0
+	}
+
+	if timeout, ok := client.Config.EndpointTimeouts[Classify(request)]; ok {
+		return timeout
+	}
+
+	return client.Config.RequestTimeout
+}
+
+// Classify returns the class of the endpoint of a request to the Raiden node API, by
+// its method and the resource of its path. Requests to unknown endpoints are reads
+// when they are GET requests and have no class otherwise.
+func Classify(request *http.Request) config.Endpoint {
+	if request.Method == "GET" || request.Method == "HEAD" {
+		return config.EndpointRead
+	}
+
+	switch resource(request.URL.Path) {
+	case "channels":
+		if request.Method == "PUT" {
+			return config.EndpointChannelOpen
+		}
+
+		return config.EndpointChannelUpdate
+	case "payments":
+		return config.EndpointPayment
+	case "connections":
+		return config.EndpointConnection
+	case "tokens":
+		return config.EndpointTokenRegistration
+	}
+
+	return ""
+}
+
+// resource returns the first segment of the path after /api/<version>/, see
+// config.SplitAPIPath.
+func resource(path string) string {
+	var _, _, relative, ok = config.SplitAPIPath(path)
+
+	if !ok {
+		return ""
+	}
+
+	return strings.SplitN(relative, "/", 2)[0]
+}
+
+// doer returns the HTTP client sending requests wrapped by the middlewares of the
+// configuration.
+func (client *BaseClient) doer() config.Doer {
+	var doer = record(client.HTTPClient.Do)
+
+	if client.Config == nil {
+		return doer
+	}
+
+	for i := len(client.Config.Middlewares) - 1; i >= 0; i-- {
+		doer = client.Config.Middlewares[i](doer)
+	}
+
+	return doer
+}
+
+// record counts the requests sent by the next doer, which sees every retry of the
+// middlewares, in the metadata of their context, along with the status code and rate
+// limit of their last response.
+func record(next config.Doer) config.Doer {
+	return func(request *http.Request) (*http.Response, error) {
+		var callMeta = meta.FromContext(request.Context())
+
+		if callMeta == nil {
+			return next(request)
+		}
+
+		callMeta.Attempts++
+		callMeta.Node = request.URL.Host
+		callMeta.StatusCode = 0
+		callMeta.RateLimit = nil
+
+		response, err := next(request)
+		if err == nil {
+			callMeta.StatusCode = response.StatusCode
+			callMeta.RateLimit = RateLimit(response.Header, time.Now())
+		}
+
+		return response, err
+	}
+}
+
+// cancelBody releases the deadline of a request once its response body is closed,
+// and aborts the reads of the body once the context of the request is done, even
+// through a transport ignoring the context: the body is closed as soon as the
+// context is done, and the reads failing from then on return the error of the
+// context, e.g. context.Canceled, so that watchers shut down at once.
+type cancelBody struct {
+	io.ReadCloser
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// newCancelBody wraps the body of the response to the request with the context.
+func newCancelBody(ctx context.Context, body io.ReadCloser, cancel context.CancelFunc) *cancelBody {
+	var wrapped = &cancelBody{ReadCloser: body, ctx: ctx, cancel: cancel, closed: make(chan struct{})}
+
+	// a context which is never done needs no watching
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				body.Close()
+			case <-wrapped.closed:
+			}
+		}()
+	}
+
+	return wrapped
+}
+
+func (body *cancelBody) Read(p []byte) (int, error) {
+	n, err := body.ReadCloser.Read(p)
+
+	if err != nil && err != io.EOF {
+		if ctxErr := body.ctx.Err(); ctxErr != nil {
+			return n, ctxErr
+		}
+	}
+
+	return n, err
+}
+
+func (body *cancelBody) Close() error {
+	defer body.cancel()
+
+	body.closeOnce.Do(func() { close(body.closed) })
+
+	return body.ReadCloser.Close()
+}
@@ -0,0 +1,402 @@
+package util
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClientDo(t *testing.T) {
+	var server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		delay, _ := time.ParseDuration(request.URL.Query().Get("delay"))
+
+		select {
+		case <-time.After(delay):
+			writer.Write([]byte(`{"ok":true}`))
+		case <-request.Context().Done():
+		}
+	}))
+	defer server.Close()
+
+	type testcase struct {
+		name           string
+		requestTimeout time.Duration
+		callerTimeout  time.Duration
+		delay          time.Duration
+		expectTimeout  bool
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:  "no default deadline",
+			delay: 50 * time.Millisecond,
+		},
+		testcase{
+			name:           "default deadline applied to a context without one",
+			requestTimeout: 20 * time.Millisecond,
+			delay:          time.Second,
+			expectTimeout:  true,
+		},
+		testcase{
+			name:           "default deadline leaves fast requests alone",
+			requestTimeout: time.Second,
+			delay:          0,
+		},
+		testcase{
+			name:           "caller deadline honored over the default",
+			requestTimeout: 20 * time.Millisecond,
+			callerTimeout:  time.Second,
+			delay:          100 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				ctx    = context.Background()
+				client = &BaseClient{
+					Config:     &config.Config{RequestTimeout: tc.requestTimeout},
+					HTTPClient: http.DefaultClient,
+				}
+			)
+
+			if tc.callerTimeout > 0 {
+				var cancel context.CancelFunc
+
+				ctx, cancel = context.WithTimeout(ctx, tc.callerTimeout)
+				defer cancel()
+			}
+
+			request, err := http.NewRequest("GET", server.URL+"?delay="+tc.delay.String(), nil)
+			require.NoError(t, err)
+
+			response, err := client.Do(request.WithContext(ctx))
+
+			if tc.expectTimeout {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			body, err := ioutil.ReadAll(response.Body)
+
+			require.NoError(t, err)
+			assert.Equal(t, `{"ok":true}`, string(body))
+		})
+	}
+}
+
+func TestClassify(t *testing.T) {
+	type testcase struct {
+		method        string
+		url           string
+		expectedClass config.Endpoint
+	}
+
+	testcases := []testcase{
+		testcase{method: "GET", url: "http://localhost:5001/api/v1/channels", expectedClass: config.EndpointRead},
+		testcase{method: "GET", url: "http://localhost:5001/api/v1/payments/0x1/0x2", expectedClass: config.EndpointRead},
+		testcase{method: "PUT", url: "http://localhost:5001/api/v1/channels", expectedClass: config.EndpointChannelOpen},
+		testcase{method: "PATCH", url: "http://localhost:5001/api/v1/channels/0x1/0x2", expectedClass: config.EndpointChannelUpdate},
+		testcase{method: "POST", url: "http://localhost:5001/api/v1/payments/0x1/0x2", expectedClass: config.EndpointPayment},
+		testcase{method: "PUT", url: "http://localhost:5001/api/v1/connections/0x1", expectedClass: config.EndpointConnection},
+		testcase{method: "DELETE", url: "http://localhost:5001/api/v1/connections/0x1", expectedClass: config.EndpointConnection},
+		testcase{method: "PUT", url: "http://localhost:5001/api/v1/tokens/0x1", expectedClass: config.EndpointTokenRegistration},
+		testcase{method: "PUT", url: "http://proxy/raiden/api/v1/channels", expectedClass: config.EndpointChannelOpen},
+		testcase{method: "POST", url: "http://localhost:5001/api/v1/_testing/tokens/0x1/mint", expectedClass: ""},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.method+" "+tc.url, func(t *testing.T) {
+			request, err := http.NewRequest(tc.method, tc.url, nil)
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedClass, Classify(request))
+		})
+	}
+}
+
+func TestBaseClientTimeout(t *testing.T) {
+	var client = &BaseClient{
+		Config: &config.Config{
+			RequestTimeout: time.Minute,
+			EndpointTimeouts: map[config.Endpoint]time.Duration{
+				config.EndpointRead:        10 * time.Second,
+				config.EndpointChannelOpen: 10 * time.Minute,
+				config.EndpointPayment:     0,
+			},
+		},
+	}
+
+	for method, expectedTimeout := range map[string]time.Duration{
+		"GET":    10 * time.Second,
+		"PUT":    10 * time.Minute,
+		"PATCH":  time.Minute,
+		"DELETE": time.Minute,
+	} {
+		request, err := http.NewRequest(method, "http://localhost:5001/api/v1/channels", nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, expectedTimeout, client.timeout(request), method)
+	}
+
+	request, err := http.NewRequest("POST", "http://localhost:5001/api/v1/payments/0x1/0x2", nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, time.Duration(0), client.timeout(request), "a zero endpoint timeout applies no deadline")
+}
+
+func TestBaseClientDoMeta(t *testing.T) {
+	var (
+		hits   int
+		server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			hits++
+			if hits == 1 {
+				writer.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			writer.Header().Set("X-RateLimit-Remaining", "9")
+			writer.WriteHeader(http.StatusConflict)
+		}))
+		retryOnce = func(next config.Doer) config.Doer {
+			return func(request *http.Request) (*http.Response, error) {
+				response, err := next(request)
+				if err == nil && response.StatusCode == http.StatusServiceUnavailable {
+					response.Body.Close()
+					return next(request)
+				}
+
+				return response, err
+			}
+		}
+		client = &BaseClient{
+			Config:     &config.Config{Middlewares: []config.Middleware{retryOnce}},
+			HTTPClient: http.DefaultClient,
+		}
+	)
+	defer server.Close()
+
+	ctx, callMeta := meta.WithMeta(context.Background())
+
+	request, err := http.NewRequest("GET", server.URL+"/api/v1/channels", nil)
+	require.NoError(t, err)
+
+	response, err := client.Do(request.WithContext(ctx))
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, 2, callMeta.Attempts)
+	assert.Equal(t, http.StatusConflict, callMeta.StatusCode)
+	assert.Equal(t, request.URL.Host, callMeta.Node)
+	assert.True(t, callMeta.Duration > 0)
+	assert.Equal(t, &meta.RateLimit{Limit: -1, Remaining: 9}, callMeta.RateLimit)
+
+	response, err = client.Do(request)
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, 2, callMeta.Attempts)
+}
+
+func TestBaseClientDoFields(t *testing.T) {
+	var (
+		received http.Header
+		server   = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			received = request.Header
+		}))
+		client = &BaseClient{
+			Config:     &config.Config{MetadataHeaders: map[string]string{meta.FieldCorrelationID: "X-Request-ID"}},
+			HTTPClient: http.DefaultClient,
+		}
+		ctx = meta.WithFields(context.Background(), meta.Fields{meta.FieldActor: "billing", meta.FieldCorrelationID: "42"})
+	)
+	defer server.Close()
+
+	request, err := http.NewRequest("GET", server.URL+"/api/v1/channels", nil)
+	require.NoError(t, err)
+	request.Header.Set("Accept", "application/json")
+
+	response, err := client.Do(request.WithContext(ctx))
+	require.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, "42", received.Get("X-Request-ID"))
+	assert.Empty(t, received.Get("X-Actor"))
+	assert.Equal(t, "application/json", received.Get("Accept"))
+	assert.Empty(t, request.Header.Get("X-Request-ID"))
+}
+
+func TestBaseClientDoTenant(t *testing.T) {
+	var (
+		tenants []string
+		server  = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			tenants = append(tenants, request.Header.Get("X-Tenant"))
+		}))
+		client = &BaseClient{
+			Config:     &config.Config{Tenant: "acme"},
+			HTTPClient: http.DefaultClient,
+		}
+	)
+	defer server.Close()
+
+	for _, ctx := range []context.Context{
+		context.Background(),
+		meta.WithFields(context.Background(), meta.Fields{meta.FieldActor: "billing"}),
+		meta.WithTenant(context.Background(), "globex"),
+	} {
+		request, err := http.NewRequest("GET", server.URL+"/api/v1/channels", nil)
+		require.NoError(t, err)
+
+		response, err := client.Do(request.WithContext(ctx))
+		require.NoError(t, err)
+		response.Body.Close()
+	}
+
+	assert.Equal(t, []string{"acme", "acme", "globex"}, tenants)
+}
+
+func TestBaseClientDoMarker(t *testing.T) {
+	var (
+		markers []string
+		server  = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			markers = append(markers, request.Header.Get(meta.DefaultMarkerHeader))
+		}))
+		client = &BaseClient{
+			Config:     &config.Config{MarkerHeader: meta.DefaultMarkerHeader},
+			HTTPClient: http.DefaultClient,
+		}
+	)
+	defer server.Close()
+
+	ctx, callMeta := meta.WithMeta(context.Background())
+
+	request, err := http.NewRequest("GET", server.URL+"/api/v1/channels", nil)
+	require.NoError(t, err)
+
+	response, err := client.Do(request.WithContext(ctx))
+	require.NoError(t, err)
+	response.Body.Close()
+
+	response, err = client.Do(request.WithContext(meta.WithMarker(ctx, "payout-42")))
+	require.NoError(t, err)
+	response.Body.Close()
+
+	require.Len(t, markers, 2)
+	assert.Regexp(t, "^rc-[0-9a-f]{16}$", markers[0])
+	assert.Equal(t, "payout-42", markers[1])
+	assert.Equal(t, "payout-42", callMeta.Marker)
+	assert.Empty(t, request.Header.Get(meta.DefaultMarkerHeader), "the request of the caller is not modified")
+}
+
+// stalledTransport answers every request with the start of a body which never ends,
+// ignoring the context of the request.
+type stalledTransport struct{}
+
+func (stalledTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	reader, writer := io.Pipe()
+
+	go writer.Write([]byte(`[{"channel_identifier":`))
+
+	return &http.Response{StatusCode: http.StatusOK, Header: make(http.Header), Body: reader, Request: request}, nil
+}
+
+func TestBaseClientCancel(t *testing.T) {
+	type testcase struct {
+		name       string
+		httpClient func(url string) (*http.Client, string)
+	}
+
+	var (
+		done   = make(chan struct{})
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`[{"channel_identifier":`))
+			w.(http.Flusher).Flush()
+
+			select {
+			case <-r.Context().Done():
+			case <-done:
+			}
+		}))
+	)
+	defer server.Close()
+	defer close(done)
+
+	testcases := []testcase{
+		testcase{
+			name: "transport following the context",
+			httpClient: func(url string) (*http.Client, string) {
+				return http.DefaultClient, url
+			},
+		},
+		testcase{
+			name: "transport ignoring the context",
+			httpClient: func(url string) (*http.Client, string) {
+				return &http.Client{Transport: stalledTransport{}}, "http://localhost:5001"
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		httpClient, host := tc.httpClient(server.URL)
+		client := &BaseClient{Config: &config.Config{Host: host, APIVersion: "v1"}, HTTPClient: httpClient}
+
+		t.Run(tc.name+" call", func(t *testing.T) {
+			var (
+				channels    []interface{}
+				ctx, cancel = context.WithCancel(context.Background())
+				started     = time.Now()
+			)
+
+			endpoint, err := client.Endpoint("channels")
+			require.NoError(t, err)
+
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			err = client.Call(ctx, "GET", endpoint, nil, &channels)
+			assert.Equal(t, context.Canceled, err)
+			assert.True(t, time.Since(started) < 5*time.Second, "the read is aborted at once")
+		})
+
+		t.Run(tc.name+" streaming decoder", func(t *testing.T) {
+			var (
+				channels    []interface{}
+				ctx, cancel = context.WithCancel(context.Background())
+			)
+
+			endpoint, err := client.Endpoint("channels")
+			require.NoError(t, err)
+
+			request, err := client.NewRequest(ctx, "GET", endpoint, nil)
+			require.NoError(t, err)
+
+			response, err := client.Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			err = ResponseError(ctx, response.StatusCode, client.Decode(response.Body, &channels))
+			assert.Equal(t, context.Canceled, err)
+		})
+	}
+
+	t.Run("read error without cancellation", func(t *testing.T) {
+		var err = ResponseError(context.Background(), http.StatusOK, io.ErrUnexpectedEOF)
+
+		assert.Equal(t, raidenerrors.New(http.StatusOK, io.ErrUnexpectedEOF), err)
+	})
+}
@@ -0,0 +1,148 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/apiv2"
+)
+
+// compatRenames are the fields of the payloads of Raiden 1.x renamed since 0.100.x,
+// by resource of the endpoint, from their new name to the name the sub-clients decode.
+var compatRenames = map[string]map[string]string{
+	"channels":          {"token_network_address": "token_network_identifier"},
+	"pending_transfers": {"token_network_address": "token_network_identifier"},
+}
+
+// DefaultFieldAliases are the fields renamed by known forks of the Raiden node, by
+// resource of the endpoint, from the name they send to the name the sub-clients
+// decode, see config.Config.FieldAliases. Forks based on Raiden 1.x keep its names
+// whatever version they report.
+var DefaultFieldAliases = map[string]map[string]string{
+	"channels":          {"token_network_address": "token_network_identifier"},
+	"pending_transfers": {"token_network_address": "token_network_identifier"},
+}
+
+// compatIntegers are the integer fields Raiden 1.x sends as strings, by resource of
+// the endpoint.
+var compatIntegers = map[string][]string{
+	"channels":          {"channel_identifier", "settle_timeout", "reveal_timeout"},
+	"pending_transfers": {"channel_identifier", "payment_identifier"},
+	"payments":          {"identifier"},
+}
+
+// upgrade rewrites the payload of a response of a Raiden 1.x node, or of version 2 of
+// the API, to the endpoint into the format of Raiden 0.100.x the sub-clients decode,
+// see config.Config.NodeVersion, after renaming the fields of its aliases, see
+// config.Config.FieldAliases. Payloads of other nodes and endpoints are returned as
+// is.
+func (client *BaseClient) upgrade(path string, data []byte) ([]byte, error) {
+	var (
+		err      error
+		decoded  interface{}
+		resource = resource(path)
+		compat   bool
+		aliases  map[string]string
+	)
+
+	if client.Config == nil {
+		return data, nil
+	}
+
+	if client.Config.APIVersion == apiv2.Version {
+		resource = apiv2.Resource(path)
+		compat = true
+	} else {
+		compat = majorVersion(client.Config.NodeVersion) >= 1
+	}
+
+	if compat {
+		_, renamed := compatRenames[resource]
+		_, integers := compatIntegers[resource]
+		compat = renamed || integers
+	}
+
+	if aliases = client.fieldAliases()[resource]; !compat && len(aliases) == 0 {
+		return data, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	if err = decoder.Decode(&decoded); err != nil {
+		// left for the decoding of the payload to report
+		return data, nil
+	}
+
+	upgrade := func(object map[string]interface{}) {
+		rename(object, aliases)
+
+		if compat {
+			upgradeObject(resource, object)
+		}
+	}
+
+	switch value := decoded.(type) {
+	case []interface{}:
+		for _, element := range value {
+			if object, ok := element.(map[string]interface{}); ok {
+				upgrade(object)
+			}
+		}
+	case map[string]interface{}:
+		upgrade(value)
+	}
+
+	return json.Marshal(decoded)
+}
+
+// fieldAliases returns the FieldAliases of the configuration, or the
+// DefaultFieldAliases when it has none.
+func (client *BaseClient) fieldAliases() map[string]map[string]string {
+	if client.Config.FieldAliases == nil {
+		return DefaultFieldAliases
+	}
+
+	return client.Config.FieldAliases
+}
+
+func upgradeObject(resource string, object map[string]interface{}) {
+	rename(object, compatRenames[resource])
+
+	for _, name := range compatIntegers[resource] {
+		if value, ok := object[name].(string); ok {
+			if _, err := strconv.ParseInt(value, 10, 64); err == nil {
+				object[name] = json.Number(value)
+			}
+		}
+	}
+}
+
+// rename renames the fields of the object from the keys to the values of the renames,
+// a field already holding the new name being kept.
+func rename(object map[string]interface{}, renames map[string]string) {
+	for from, to := range renames {
+		if value, ok := object[from]; ok {
+			if _, exists := object[to]; !exists {
+				object[to] = value
+			}
+
+			delete(object, from)
+		}
+	}
+}
+
+// majorVersion returns the major version of a Raiden version, e.g. 1 for "v1.1.1",
+// and 0 when it is empty or invalid.
+func majorVersion(version string) int {
+	var major = strings.SplitN(strings.TrimPrefix(version, "v"), ".", 2)[0]
+
+	value, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+
+	return value
+}
@@ -0,0 +1,119 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClientUpgrade(t *testing.T) {
+	type testcase struct {
+		name         string
+		nodeVersion  string
+		fieldAliases map[string]map[string]string
+		path         string
+		payload      string
+		expectedJSON string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:         "0.100.x channels left as is",
+			nodeVersion:  "0.100.3",
+			path:         "/api/v1/channels",
+			payload:      `[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":"20"}]`,
+			expectedJSON: `[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":"20"}]`,
+		},
+		testcase{
+			name:         "unknown version left as is without aliases",
+			fieldAliases: map[string]map[string]string{},
+			path:         "/api/v1/channels",
+			payload:      `[{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":"20"}]`,
+			expectedJSON: `[{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":"20"}]`,
+		},
+		testcase{
+			name:         "unknown version renamed by the default aliases",
+			path:         "/api/v1/channels",
+			payload:      `[{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":"20"}]`,
+			expectedJSON: `[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":"20"}]`,
+		},
+		testcase{
+			name:         "fork aliases renamed",
+			nodeVersion:  "0.100.3",
+			fieldAliases: map[string]map[string]string{"channels": {"channel_id": "channel_identifier", "network": "token_network_identifier"}},
+			path:         "/api/v1/channels/0x9aBa529db3FF2D8409A1da4C9eB148879b046700",
+			payload:      `[{"network":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_id":20,"token_network_address":"0x0"}]`,
+			expectedJSON: `[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"token_network_address":"0x0"}]`,
+		},
+		testcase{
+			name:         "fork aliases renamed before a 1.x upgrade",
+			nodeVersion:  "1.1.1",
+			fieldAliases: map[string]map[string]string{"payments": {"payment_id": "identifier"}},
+			path:         "/api/v1/payments/0x9aBa529db3FF2D8409A1da4C9eB148879b046700",
+			payload:      `[{"event":"EventPaymentSentSuccess","payment_id":"2"}]`,
+			expectedJSON: `[{"event":"EventPaymentSentSuccess","identifier":2}]`,
+		},
+		testcase{
+			name:         "1.x channels upgraded",
+			nodeVersion:  "1.1.1",
+			path:         "/api/v1/channels/0x9aBa529db3FF2D8409A1da4C9eB148879b046700",
+			payload:      `[{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":"20","settle_timeout":"500","reveal_timeout":"50","balance":"25000000"}]`,
+			expectedJSON: `[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"settle_timeout":500,"reveal_timeout":50,"balance":"25000000"}]`,
+		},
+		testcase{
+			name:         "1.x channel upgraded",
+			nodeVersion:  "v1.0.0",
+			path:         "/api/v1/channels/0x9aBa529db3FF2D8409A1da4C9eB148879b046700/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			payload:      `{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":"20"}`,
+			expectedJSON: `{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20}`,
+		},
+		testcase{
+			name:         "1.x payment events upgraded",
+			nodeVersion:  "1.1.1",
+			path:         "/api/v1/payments/0x9aBa529db3FF2D8409A1da4C9eB148879b046700/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			payload:      `[{"event":"EventPaymentSentSuccess","identifier":"2","amount":"10"}]`,
+			expectedJSON: `[{"event":"EventPaymentSentSuccess","identifier":2,"amount":"10"}]`,
+		},
+		testcase{
+			name:         "1.x token network address kept for other endpoints",
+			nodeVersion:  "1.1.1",
+			path:         "/api/v1/tokens/0x9aBa529db3FF2D8409A1da4C9eB148879b046700",
+			payload:      `{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2"}`,
+			expectedJSON: `{"token_network_address":"0xE5637F0103794C7e05469A9964E4563089a5E6f2"}`,
+		},
+		testcase{
+			name:         "invalid payload left for decoding",
+			nodeVersion:  "1.1.1",
+			path:         "/api/v1/channels",
+			payload:      `[{"token_network_address":`,
+			expectedJSON: ``,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var client = &BaseClient{Config: &config.Config{NodeVersion: tc.nodeVersion, FieldAliases: tc.fieldAliases}}
+
+			data, err := client.upgrade(tc.path, []byte(tc.payload))
+			require.NoError(t, err)
+
+			if tc.expectedJSON == "" {
+				assert.Equal(t, tc.payload, string(data))
+				return
+			}
+
+			assert.JSONEq(t, tc.expectedJSON, string(data))
+		})
+	}
+}
+
+func TestMajorVersion(t *testing.T) {
+	assert.Equal(t, 0, majorVersion(""))
+	assert.Equal(t, 0, majorVersion("0.100.3"))
+	assert.Equal(t, 1, majorVersion("1.1.1"))
+	assert.Equal(t, 1, majorVersion("v1.0.0"))
+	assert.Equal(t, 2, majorVersion("2"))
+	assert.Equal(t, 0, majorVersion("develop"))
+}
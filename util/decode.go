@@ -0,0 +1,170 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+// Decode will decode the JSON body of a response from the Raiden node into v,
+// following the decode mode of the client configuration.
+func (client *BaseClient) Decode(body io.Reader, v interface{}) error {
+	var mode = config.Lenient
+
+	if client.Config != nil {
+		mode = client.Config.DecodeMode
+	}
+
+	return Decode(mode, body, v)
+}
+
+// Decode will decode the JSON body into v in the given decode mode. In strict mode
+// unknown fields and nulls in required fields are errors.
+func Decode(mode config.DecodeMode, body io.Reader, v interface{}) error {
+	var (
+		err     error
+		data    []byte
+		decoded interface{}
+	)
+
+	if mode != config.Strict {
+		return json.NewDecoder(body).Decode(v)
+	}
+
+	if data, err = ioutil.ReadAll(body); err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err = decoder.Decode(v); err != nil {
+		return err
+	}
+
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+
+	return checkNulls(reflect.TypeOf(v), decoded, "")
+}
+
+// checkNulls walks the decoded JSON value along the type it was decoded into, and
+// returns an error for the first null held by a required field.
+func checkNulls(t reflect.Type, value interface{}, path string) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		values, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+
+		for i, element := range values {
+			if element == nil && isRequired(t.Elem()) {
+				return fmt.Errorf("json: null element %s[%d]", strings.TrimPrefix(path, "."), i)
+			}
+
+			if err := checkNulls(t.Elem(), element, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		for key, element := range object {
+			if err := checkNulls(t.Elem(), element, path+"."+key); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		return checkFields(t, object, path)
+	}
+
+	return nil
+}
+
+func checkFields(t reflect.Type, object map[string]interface{}, path string) error {
+	for i := 0; i < t.NumField(); i++ {
+		var (
+			field     = t.Field(i)
+			tag       = strings.Split(field.Tag.Get("json"), ",")
+			name      = tag[0]
+			omitempty = strings.Contains(field.Tag.Get("json"), ",omitempty")
+		)
+
+		if field.Anonymous && name == "" {
+			if err := checkNulls(field.Type, object, path); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if field.PkgPath != "" || name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		element, present := lookup(object, name)
+		if !present {
+			continue
+		}
+
+		if element == nil {
+			if !omitempty && isRequired(field.Type) {
+				return fmt.Errorf("json: null value for required field %s", strings.TrimPrefix(path+"."+name, "."))
+			}
+
+			continue
+		}
+
+		if err := checkNulls(field.Type, element, path+"."+name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lookup finds the value of the field the way encoding/json matches names, preferring
+// an exact match over a case-insensitive one.
+func lookup(object map[string]interface{}, name string) (interface{}, bool) {
+	if value, ok := object[name]; ok {
+		return value, true
+	}
+
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// isRequired reports whether a null is an error for a value of the type, only
+// pointers and interfaces are allowed to be null.
+func isRequired(t reflect.Type) bool {
+	return t.Kind() != reflect.Ptr && t.Kind() != reflect.Interface
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type decodeTarget struct {
+	Name     string            `json:"name"`
+	Amount   int64             `json:"amount"`
+	Note     string            `json:"note,omitempty"`
+	Parent   *decodeTarget     `json:"parent"`
+	Children []decodeTarget    `json:"children"`
+	Labels   map[string]string `json:"labels"`
+}
+
+func TestDecode(t *testing.T) {
+	type testcase struct {
+		name           string
+		body           string
+		mode           config.DecodeMode
+		expectedTarget *decodeTarget
+		expectedError  error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "lenient decoding of unknown fields",
+			body:           `{"name":"a","amount":1,"extra":true}`,
+			mode:           config.Lenient,
+			expectedTarget: &decodeTarget{Name: "a", Amount: 1},
+		},
+		testcase{
+			name:           "lenient decoding of nulls",
+			body:           `{"name":null,"amount":null}`,
+			mode:           config.Lenient,
+			expectedTarget: &decodeTarget{},
+		},
+		testcase{
+			name:           "strict decoding of a valid body",
+			body:           `{"name":"a","amount":1,"note":null,"parent":null,"children":[{"name":"b","amount":2}]}`,
+			mode:           config.Strict,
+			expectedTarget: &decodeTarget{Name: "a", Amount: 1, Children: []decodeTarget{{Name: "b", Amount: 2}}},
+		},
+		testcase{
+			name:          "strict decoding of unknown fields",
+			body:          `{"name":"a","amount":1,"extra":true}`,
+			mode:          config.Strict,
+			expectedError: errors.New(`json: unknown field "extra"`),
+		},
+		testcase{
+			name:          "strict decoding of a null required field",
+			body:          `{"name":"a","amount":null}`,
+			mode:          config.Strict,
+			expectedError: errors.New("json: null value for required field amount"),
+		},
+		testcase{
+			name:          "strict decoding of a nested null required field",
+			body:          `{"name":"a","children":[{"name":null}]}`,
+			mode:          config.Strict,
+			expectedError: errors.New("json: null value for required field children[0].name"),
+		},
+		testcase{
+			name:          "strict decoding of a null element",
+			body:          `{"name":"a","children":[null]}`,
+			mode:          config.Strict,
+			expectedError: errors.New("json: null element children[0]"),
+		},
+		testcase{
+			name:          "strict decoding of a case-insensitive match",
+			body:          `{"NAME":null}`,
+			mode:          config.Strict,
+			expectedError: errors.New("json: null value for required field name"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var target = &decodeTarget{}
+
+			err := Decode(tc.mode, strings.NewReader(tc.body), target)
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTarget, target)
+		})
+	}
+}
@@ -0,0 +1,29 @@
+//go:build go1.18
+// +build go1.18
+
+package util
+
+import (
+	"context"
+	"net/url"
+)
+
+// Fetch will make a request to the endpoint with the body and return its response
+// decoded as a T, see BaseClient.Call, so that with Go 1.18 or later an endpoint
+// returning a new value is a single call, e.g.
+//
+//	channels, err := util.Fetch[[]*channels.Channel](ctx, lister.baseClient, "GET", requestURL, nil)
+//
+// The sub-clients of the client keep using Call, since they still build with Go
+// releases that predate type parameters.
+func Fetch[T any](ctx context.Context, client *BaseClient, method string, endpoint *url.URL, requestBody interface{}) (T, error) {
+	var result T
+
+	if err := client.Call(ctx, method, endpoint, requestBody, &result); err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return result, nil
+}
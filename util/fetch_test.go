@@ -0,0 +1,48 @@
+//go:build go1.18
+// +build go1.18
+
+package util
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetch(t *testing.T) {
+	type token struct {
+		Address string `json:"address"`
+	}
+
+	var client = &BaseClient{
+		Config:     &config.Config{Host: "http://localhost:5001", APIVersion: "v1"},
+		HTTPClient: http.DefaultClient,
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/tokens", httpmock.NewStringResponder(http.StatusOK, `[{"address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"}]`))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", httpmock.NewStringResponder(http.StatusInternalServerError, `{"errors":"Internal server error"}`))
+
+	requestURL, err := client.Endpoint("tokens")
+	require.NoError(t, err)
+
+	tokens, err := Fetch[[]*token](context.Background(), client, "GET", requestURL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*token{&token{Address: "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"}}, tokens)
+
+	requestURL, err = client.Endpoint("address")
+	require.NoError(t, err)
+
+	address, err := Fetch[*token](context.Background(), client, "GET", requestURL, nil)
+	assert.EqualError(t, err, "raiden node error 500: Internal server error")
+	assert.Equal(t, http.StatusInternalServerError, raidenerrors.StatusCode(err))
+	assert.Nil(t, address)
+}
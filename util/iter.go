@@ -0,0 +1,31 @@
+//go:build go1.23
+// +build go1.23
+
+package util
+
+import (
+	"context"
+	"iter"
+)
+
+// Iterate returns an iterator over the results of list, which is called every time
+// the iteration starts. The results are yielded with a nil error, then the error of
+// list when it failed, after the partial results it may have returned along with it.
+// Breaking out of the loop stops the iteration.
+func Iterate[T any](ctx context.Context, list func(ctx context.Context) ([]T, error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		results, err := list(ctx)
+
+		for _, result := range results {
+			if !yield(result, nil) {
+				return
+			}
+		}
+
+		if err != nil {
+			var zero T
+
+			yield(zero, err)
+		}
+	}
+}
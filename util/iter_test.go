@@ -0,0 +1,88 @@
+//go:build go1.23
+// +build go1.23
+
+package util
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterate(t *testing.T) {
+	type testcase struct {
+		name            string
+		results         []int
+		err             error
+		stopAt          int
+		expectedResults []int
+		expectedErrors  int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:            "every result",
+			results:         []int{1, 2, 3},
+			expectedResults: []int{1, 2, 3},
+		},
+		testcase{
+			name:            "break out of the loop",
+			results:         []int{1, 2, 3},
+			stopAt:          2,
+			expectedResults: []int{1, 2},
+		},
+		testcase{
+			name:           "error",
+			err:            errors.New("EOF"),
+			expectedErrors: 1,
+		},
+		testcase{
+			name:            "partial results before the error",
+			results:         []int{1},
+			err:             errors.New("some token networks failed"),
+			expectedResults: []int{1},
+			expectedErrors:  1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				calls   int
+				results []int
+				errs    int
+				seq     = Iterate(context.Background(), func(ctx context.Context) ([]int, error) {
+					calls++
+					return tc.results, tc.err
+				})
+			)
+
+			assert.Zero(t, calls, "nothing is listed before the iteration starts")
+
+			for result, err := range seq {
+				if err != nil {
+					assert.Equal(t, tc.err, err)
+					errs++
+					continue
+				}
+
+				results = append(results, result)
+
+				if len(results) == tc.stopAt {
+					break
+				}
+			}
+
+			assert.Equal(t, tc.expectedResults, results)
+			assert.Equal(t, tc.expectedErrors, errs)
+
+			for range seq {
+				break
+			}
+
+			assert.Equal(t, 2, calls, "every iteration lists again")
+		})
+	}
+}
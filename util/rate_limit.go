@@ -0,0 +1,96 @@
+package util
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/meta"
+)
+
+// resetEpoch is the smallest reset value taken as a unix time rather than a number of
+// seconds, proxies telling either.
+const resetEpoch = 1000000000
+
+// RateLimit returns the quota told by the rate limit headers of a response, the
+// X-RateLimit-Limit, -Remaining and -Reset headers or their RateLimit-* counterparts,
+// or nil when the response has none. The reset is either a number of seconds from now
+// or a unix time.
+func RateLimit(header http.Header, now time.Time) *meta.RateLimit {
+	var (
+		found     bool
+		rateLimit = &meta.RateLimit{Limit: -1, Remaining: -1}
+	)
+
+	if value, ok := rateLimitHeader(header, "Limit"); ok {
+		// the limit may be followed by the policy, e.g. "100, 100;w=60"
+		if limit, err := strconv.Atoi(strings.TrimSpace(strings.SplitN(value, ",", 2)[0])); err == nil {
+			rateLimit.Limit, found = limit, true
+		}
+	}
+
+	if value, ok := rateLimitHeader(header, "Remaining"); ok {
+		if remaining, err := strconv.Atoi(value); err == nil {
+			rateLimit.Remaining, found = remaining, true
+		}
+	}
+
+	if value, ok := rateLimitHeader(header, "Reset"); ok {
+		if reset, err := strconv.ParseInt(value, 10, 64); err == nil && reset >= 0 {
+			if reset >= resetEpoch {
+				rateLimit.Reset = time.Unix(reset, 0)
+			} else {
+				rateLimit.Reset = now.Add(time.Duration(reset) * time.Second)
+			}
+
+			found = true
+		}
+	}
+
+	if !found {
+		return nil
+	}
+
+	return rateLimit
+}
+
+func rateLimitHeader(header http.Header, name string) (string, bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		if value := strings.TrimSpace(header.Get(prefix + name)); value != "" {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// RetryAfter returns how long the Retry-After header of a response asks to wait
+// before sending the request again, as a number of seconds or an HTTP date, and
+// whether the response has a valid one.
+func RetryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	var value = strings.TrimSpace(header.Get("Retry-After"))
+
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+
+	return 0, true
+}
@@ -0,0 +1,116 @@
+package util
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/meta"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	type testcase struct {
+		name     string
+		header   http.Header
+		expected *meta.RateLimit
+	}
+
+	var now = time.Date(2019, 3, 7, 12, 0, 0, 0, time.UTC)
+
+	testcases := []testcase{
+		testcase{
+			name: "x-ratelimit headers",
+			header: http.Header{
+				"X-Ratelimit-Limit":     []string{"100"},
+				"X-Ratelimit-Remaining": []string{"42"},
+				"X-Ratelimit-Reset":     []string{"30"},
+			},
+			expected: &meta.RateLimit{Limit: 100, Remaining: 42, Reset: now.Add(30 * time.Second)},
+		},
+		testcase{
+			name: "ratelimit headers with a policy",
+			header: http.Header{
+				"Ratelimit-Limit":     []string{"100, 100;w=60"},
+				"Ratelimit-Remaining": []string{"0"},
+			},
+			expected: &meta.RateLimit{Limit: 100, Remaining: 0},
+		},
+		testcase{
+			name:     "unix time reset",
+			header:   http.Header{"X-Ratelimit-Reset": []string{"1551960060"}},
+			expected: &meta.RateLimit{Limit: -1, Remaining: -1, Reset: time.Unix(1551960060, 0)},
+		},
+		testcase{
+			name:   "invalid headers",
+			header: http.Header{"X-Ratelimit-Remaining": []string{"many"}},
+		},
+		testcase{
+			name:   "no headers",
+			header: http.Header{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, RateLimit(tc.header, now))
+		})
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	type testcase struct {
+		name          string
+		value         string
+		expectedWait  time.Duration
+		expectedValid bool
+	}
+
+	var now = time.Date(2019, 3, 7, 12, 0, 0, 0, time.UTC)
+
+	testcases := []testcase{
+		testcase{
+			name:          "seconds",
+			value:         "120",
+			expectedWait:  2 * time.Minute,
+			expectedValid: true,
+		},
+		testcase{
+			name:          "http date",
+			value:         "Thu, 07 Mar 2019 12:00:30 GMT",
+			expectedWait:  30 * time.Second,
+			expectedValid: true,
+		},
+		testcase{
+			name:          "past http date",
+			value:         "Thu, 07 Mar 2019 11:00:00 GMT",
+			expectedValid: true,
+		},
+		testcase{
+			name:  "negative seconds",
+			value: "-1",
+		},
+		testcase{
+			name:  "invalid",
+			value: "soon",
+		},
+		testcase{
+			name: "missing",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var header = http.Header{}
+
+			if tc.value != "" {
+				header.Set("Retry-After", tc.value)
+			}
+
+			wait, valid := RetryAfter(header, now)
+
+			assert.Equal(t, tc.expectedWait, wait)
+			assert.Equal(t, tc.expectedValid, valid)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+)
+
+// RawCaller is a generic interface to call any endpoint of the Raiden node API with
+// the authentication, retries and middlewares of the typed calls, e.g. a new or node
+// specific endpoint the client has no call for yet.
+type RawCaller interface {
+	Raw(ctx context.Context, method, path string, query url.Values, body interface{}) (json.RawMessage, error)
+}
+
+// NewRawCaller creates a new default raw caller given a Raiden node configuration
+// and an http client.
+func NewRawCaller(config *config.Config, httpClient *http.Client) RawCaller {
+	return &BaseClient{
+		Config:     config,
+		HTTPClient: httpClient,
+	}
+}
+
+// Raw will call the endpoint at the path, relative to /api/<version>/ like
+// "channels/0x…", with the query and the body sent as JSON unless it is nil, and
+// return the response as the node sent it. Unlike the typed calls the path and body
+// are not translated for version 2 of the API, and the response is neither upgraded
+// nor checked, see Call. Error responses are returned as errors like those of the
+// typed calls, and an empty response as a nil message.
+func (client *BaseClient) Raw(ctx context.Context, method, path string, query url.Values, body interface{}) (json.RawMessage, error) {
+	var (
+		err         error
+		endpoint    *url.URL
+		request     *http.Request
+		response    *http.Response
+		requestBody io.Reader
+		data        []byte
+	)
+
+	if endpoint, err = url.Parse(client.Config.APIURL() + "/" + strings.TrimPrefix(path, "/")); err != nil {
+		return nil, err
+	}
+
+	if len(query) > 0 {
+		endpoint.RawQuery = query.Encode()
+	}
+
+	if body != nil {
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+
+		requestBody = bytes.NewReader(data)
+	}
+
+	if request, err = http.NewRequest(method, endpoint.String(), requestBody); err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	if response, err = client.Do(request.WithContext(ctx)); err != nil {
+		return nil, err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode >= http.StatusBadRequest {
+		return nil, client.errorResponse(response)
+	}
+
+	if data, err = ioutil.ReadAll(response.Body); err != nil {
+		return nil, ResponseError(ctx, response.StatusCode, err)
+	}
+
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, nil
+	}
+
+	if !json.Valid(data) {
+		return nil, raidenerrors.New(response.StatusCode, fmt.Errorf("invalid JSON response from %s", endpoint.Path))
+	}
+
+	return json.RawMessage(data), nil
+}
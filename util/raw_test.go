@@ -0,0 +1,133 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClientRaw(t *testing.T) {
+	var (
+		middlewareCalls int
+		caller          = NewRawCaller(&config.Config{
+			Host:        "http://localhost:5001",
+			APIVersion:  "v1",
+			NodeVersion: "1.1.0",
+			Middlewares: []config.Middleware{
+				func(next config.Doer) config.Doer {
+					return func(request *http.Request) (*http.Response, error) {
+						middlewareCalls++
+						return next(request)
+					}
+				},
+			},
+		}, http.DefaultClient)
+	)
+
+	type testcase struct {
+		name            string
+		method          string
+		path            string
+		query           url.Values
+		body            interface{}
+		prepHTTPMock    func()
+		expectedMessage json.RawMessage
+		expectedCode    raidenerrors.Code
+		expectedStatus  int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:   "query passed through and response left as is",
+			method: "GET",
+			path:   "channels",
+			query:  url.Values{"limit": []string{"10"}, "state": []string{"opened"}},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels?limit=10&state=opened", httpmock.NewStringResponder(http.StatusOK, `[{"token_network_address":"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6","channel_identifier":"7"}]`))
+			},
+			expectedMessage: json.RawMessage(`[{"token_network_address":"0x61bB630D3B2e8eda0FC1d50F9f958eC02e3969F6","channel_identifier":"7"}]`),
+		},
+		testcase{
+			name:   "body sent as JSON",
+			method: "POST",
+			path:   "/_debug/snapshot",
+			body:   map[string]interface{}{"compress": true},
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("POST", "http://localhost:5001/api/v1/_debug/snapshot", func(request *http.Request) (*http.Response, error) {
+					body, _ := ioutil.ReadAll(request.Body)
+
+					assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+					assert.JSONEq(t, `{"compress":true}`, string(body))
+
+					return httpmock.NewStringResponse(http.StatusNoContent, ``), nil
+				})
+			},
+		},
+		testcase{
+			name:   "error response",
+			method: "PUT",
+			path:   "channels",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusConflict, `{"errors":"Channel with partner already exists"}`))
+			},
+			expectedCode:   raidenerrors.CodeChannelExists,
+			expectedStatus: http.StatusConflict,
+		},
+		testcase{
+			name:   "error response describing no error",
+			method: "GET",
+			path:   "unknown",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/unknown", httpmock.NewStringResponder(http.StatusNotFound, `<html>not found</html>`))
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		testcase{
+			name:   "invalid response",
+			method: "GET",
+			path:   "status",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/status", httpmock.NewStringResponder(http.StatusOK, `ready`))
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+			middlewareCalls = 0
+
+			message, err := caller.Raw(context.Background(), tc.method, tc.path, tc.query, tc.body)
+
+			assert.Equal(t, 1, middlewareCalls)
+
+			if tc.expectedStatus != 0 {
+				require.Error(t, err)
+				assert.Equal(t, tc.expectedStatus, raidenerrors.StatusCode(err))
+
+				if tc.expectedCode != raidenerrors.CodeUnknown {
+					assert.True(t, raidenerrors.HasCode(err, tc.expectedCode))
+				}
+
+				assert.Nil(t, message)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, message)
+		})
+	}
+}
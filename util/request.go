@@ -0,0 +1,296 @@
+package util
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/apiv2"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+)
+
+// Endpoint returns the URL of an endpoint of the Raiden node API, the path being
+// formatted with the args and relative to /api/<version>/ under the base path of the
+// configuration, e.g. "channels/%s", see config.Config.APIURL. Paths
+// are those of version 1 of the API, routed to their version 2 counterpart when the
+// configuration uses it, see apiv2.Path, with their addresses in the AddressFormat
+// of the configuration.
+func (client *BaseClient) Endpoint(path string, args ...interface{}) (*url.URL, error) {
+	var relative = fmt.Sprintf(path, args...)
+
+	if client.Config.APIVersion == apiv2.Version {
+		relative = apiv2.Path(relative)
+	}
+
+	if client.Config.AddressFormat != config.Checksummed {
+		relative = string(client.Config.AddressFormat.Rewrite([]byte(relative)))
+	}
+
+	return url.Parse(client.Config.APIURL() + "/" + relative)
+}
+
+// NewRequest creates a request to the endpoint bound to the context. The body is
+// sent as JSON unless it is nil, in the shape of version 2 of the API when the
+// configuration uses it, see apiv2.EncodeRequest, with its addresses in the
+// AddressFormat of the configuration.
+func (client *BaseClient) NewRequest(ctx context.Context, method string, endpoint *url.URL, body interface{}) (*http.Request, error) {
+	var (
+		err         error
+		request     *http.Request
+		requestBody io.Reader
+	)
+
+	if body != nil {
+		var data []byte
+
+		if data, err = json.Marshal(body); err != nil {
+			return nil, err
+		}
+
+		if client.Config != nil && client.Config.APIVersion == apiv2.Version {
+			if data, err = apiv2.EncodeRequest(data); err != nil {
+				return nil, err
+			}
+		}
+
+		if client.Config != nil && client.Config.AddressFormat != config.Checksummed {
+			data = client.Config.AddressFormat.Rewrite(data)
+		}
+
+		requestBody = bytes.NewReader(data)
+	}
+
+	if request, err = http.NewRequest(method, endpoint.String(), requestBody); err != nil {
+		return nil, err
+	}
+
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	return request.WithContext(ctx), nil
+}
+
+// Call will make a request to the endpoint with the body, see NewRequest, and decode
+// the JSON response into v unless it is nil, in the format of the version of the
+// node, see config.Config.NodeVersion. Any status code other than 2xx is an error:
+// an error response describing the error, as the Raiden node does, is returned as a
+// *raidenerrors.APIError, any other one, and errors decoding the response, as a
+// *raidenerrors.Error holding its status code. A request answered with 202 Accepted
+// returns a *raidenerrors.Pending, or the response its outcome is polled to, see
+// config.Config.AcceptedPolling. A response marked as unchanged returns ErrUnchanged
+// for a context from SkipUnchanged. This is all an
+// endpoint of the Raiden node API needs, behaving like every other one, e.g.
+//
+//	if requestURL, err = lister.baseClient.Endpoint("channels/%s", tokenAddress.Hex()); err != nil {
+//		return nil, err
+//	}
+//
+//	if err = lister.baseClient.Call(ctx, "GET", requestURL, nil, &channels); err != nil {
+//		return nil, err
+//	}
+func (client *BaseClient) Call(ctx context.Context, method string, endpoint *url.URL, requestBody, v interface{}) error {
+	var (
+		err      error
+		request  *http.Request
+		response *http.Response
+		body     []byte
+	)
+
+	if request, err = client.NewRequest(ctx, method, endpoint, requestBody); err != nil {
+		return err
+	}
+
+	if response, err = client.Do(request); err != nil {
+		return err
+	}
+
+	if response.StatusCode == http.StatusAccepted {
+		if response, err = client.awaitAccepted(ctx, response); err != nil {
+			return err
+		}
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return client.errorResponse(response)
+	}
+
+	if response.Header.Get(UnchangedHeader) != "" && skipsUnchanged(ctx) {
+		return ErrUnchanged
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	if body, err = ioutil.ReadAll(response.Body); err != nil {
+		return ResponseError(ctx, response.StatusCode, err)
+	}
+
+	if body, err = client.upgrade(endpoint.Path, body); err != nil {
+		return raidenerrors.New(response.StatusCode, err)
+	}
+
+	if err = client.validate(endpoint.Path, body); err != nil {
+		return raidenerrors.New(response.StatusCode, err)
+	}
+
+	if err = client.Decode(bytes.NewReader(body), v); err != nil {
+		return raidenerrors.New(response.StatusCode, err)
+	}
+
+	return nil
+}
+
+// ResponseError returns the error reading or decoding the body of a response with
+// the status code as a *raidenerrors.Error, or as is when it is the error of the
+// context, the read being aborted because the context is done, see BaseClient.Do.
+// Streaming decoders reading the body of a response themselves, e.g. with Decode,
+// return their errors through it to return the error of the context like Call.
+func ResponseError(ctx context.Context, statusCode int, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil && err == ctxErr {
+		return err
+	}
+
+	return raidenerrors.New(statusCode, err)
+}
+
+// errorResponse returns the error described by the body of an error response as a
+// *raidenerrors.APIError, with the Retry-After of the response. A body describing no
+// error is never decoded as a result, the error being io.EOF for an empty body and
+// the status code otherwise.
+func (client *BaseClient) errorResponse(response *http.Response) error {
+	var (
+		err           error
+		body          []byte
+		retryAfter, _ = RetryAfter(response.Header, time.Now())
+	)
+
+	if body, err = ioutil.ReadAll(response.Body); err != nil {
+		if response.Request != nil && err == response.Request.Context().Err() {
+			return err
+		}
+
+		return &raidenerrors.Error{StatusCode: response.StatusCode, RetryAfter: retryAfter, Err: err}
+	}
+
+	if apiErr := raidenerrors.Parse(response.StatusCode, body); apiErr != nil {
+		apiErr.RetryAfter = retryAfter
+		return apiErr
+	}
+
+	if err = io.EOF; len(bytes.TrimSpace(body)) > 0 {
+		err = fmt.Errorf("recieved %d status code from raiden node", response.StatusCode)
+	}
+
+	return &raidenerrors.Error{StatusCode: response.StatusCode, RetryAfter: retryAfter, Err: err}
+}
+
+// DefaultAcceptedInterval is the time between two polls of the outcome of a request
+// answered with 202 Accepted when the polling has no interval.
+const DefaultAcceptedInterval = time.Second
+
+// maxPendingBody is how much of a 202 Accepted response is read for its status.
+const maxPendingBody = 64 << 10
+
+// awaitAccepted polls the Location of a 202 Accepted response for the outcome of its
+// request as configured by the AcceptedPolling of the configuration, returning the
+// first response which is not 202 Accepted, or the *raidenerrors.Pending of the last
+// one when the request is not polled or the polling timed out.
+func (client *BaseClient) awaitAccepted(ctx context.Context, response *http.Response) (*http.Response, error) {
+	var (
+		err      error
+		request  *http.Request
+		polling  *config.AcceptedPolling
+		pace     = clock.Real()
+		deadline time.Time
+	)
+
+	if client.Config != nil {
+		polling = client.Config.AcceptedPolling
+	}
+
+	if polling != nil {
+		pace = clock.Or(polling.Clock)
+		deadline = pace.Now().Add(polling.Timeout)
+	}
+
+	for {
+		var pending = pendingResponse(response, pace.Now())
+
+		if polling == nil || pending.Location == nil {
+			return nil, pending
+		}
+
+		var wait = polling.Interval
+
+		if wait <= 0 {
+			wait = DefaultAcceptedInterval
+		}
+
+		if pending.RetryAfter > 0 {
+			wait = pending.RetryAfter
+		}
+
+		if polling.Timeout > 0 && pace.Now().Add(wait).After(deadline) {
+			return nil, pending
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-pace.After(wait):
+		}
+
+		if request, err = client.NewRequest(ctx, "GET", pending.Location, nil); err != nil {
+			return nil, err
+		}
+
+		if response, err = client.Do(request); err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode != http.StatusAccepted {
+			return response, nil
+		}
+	}
+}
+
+// pendingResponse returns the *raidenerrors.Pending of a 202 Accepted response,
+// closing its body, its Location resolved against the URL of its request.
+func pendingResponse(response *http.Response, now time.Time) *raidenerrors.Pending {
+	var (
+		pending = &raidenerrors.Pending{}
+		status  = &struct {
+			Status string `json:"status"`
+		}{}
+	)
+
+	defer response.Body.Close()
+
+	pending.RetryAfter, _ = RetryAfter(response.Header, now)
+
+	if location, err := response.Location(); err == nil {
+		pending.Location = location
+	}
+
+	if body, err := ioutil.ReadAll(io.LimitReader(response.Body, maxPendingBody)); err == nil && len(body) > 0 {
+		pending.Body = body
+
+		if json.Unmarshal(body, status) == nil {
+			pending.Status = status.Status
+		}
+	}
+
+	return pending
+}
@@ -0,0 +1,374 @@
+package util
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClientCall(t *testing.T) {
+	var (
+		calls  = make([]string, 0)
+		client = &BaseClient{
+			Config: &config.Config{
+				Host:       "http://localhost:5001",
+				APIVersion: "v1",
+				Middlewares: []config.Middleware{
+					func(next config.Doer) config.Doer {
+						return func(request *http.Request) (*http.Response, error) {
+							calls = append(calls, "outer")
+							return next(request)
+						}
+					},
+					func(next config.Doer) config.Doer {
+						return func(request *http.Request) (*http.Response, error) {
+							calls = append(calls, "inner")
+							return next(request)
+						}
+					},
+				},
+			},
+			HTTPClient: http.DefaultClient,
+		}
+		response = &struct {
+			State string `json:"state"`
+		}{}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("PATCH", "http://localhost:5001/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		func(request *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(request.Body)
+
+			assert.Equal(t, "application/json", request.Header.Get("Content-Type"))
+			assert.JSONEq(t, `{"state":"closed"}`, string(body))
+
+			return httpmock.NewStringResponse(http.StatusOK, `{"state":"closed"}`), nil
+		})
+
+	requestURL, err := client.Endpoint("channels/%s/%s", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", "0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	require.NoError(t, err)
+
+	err = client.Call(context.Background(), "PATCH", requestURL, map[string]string{"state": "closed"}, response)
+
+	require.NoError(t, err)
+	assert.Equal(t, "closed", response.State)
+	assert.Equal(t, []string{"outer", "inner"}, calls)
+}
+
+func TestBaseClientNewRequest(t *testing.T) {
+	var client = &BaseClient{
+		Config: &config.Config{Host: "http://localhost:5001", APIVersion: "v1"},
+	}
+
+	requestURL, err := client.Endpoint("tokens")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:5001/api/v1/tokens", requestURL.String())
+
+	request, err := client.NewRequest(context.Background(), "GET", requestURL, nil)
+
+	require.NoError(t, err)
+	assert.Nil(t, request.Body)
+	assert.Empty(t, request.Header.Get("Content-Type"))
+}
+
+func TestBaseClientEndpointBasePath(t *testing.T) {
+	var client = &BaseClient{
+		Config: &config.Config{Host: "https://gateway.example.com", BasePath: "/raiden/node-3", APIVersion: "v1"},
+	}
+
+	requestURL, err := client.Endpoint("channels/%s", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8")
+	require.NoError(t, err)
+	assert.Equal(t, "https://gateway.example.com/raiden/node-3/api/v1/channels/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", requestURL.String())
+	assert.Equal(t, "channels", resource(requestURL.Path))
+}
+
+func TestBaseClientCallErrors(t *testing.T) {
+	var (
+		client = &BaseClient{
+			Config:     &config.Config{Host: "http://localhost:5001", APIVersion: "v1"},
+			HTTPClient: http.DefaultClient,
+		}
+		channels []interface{}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusServiceUnavailable, ``))
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/tokens", httpmock.NewStringResponder(http.StatusNotFound, ``))
+
+	requestURL, _ := client.Endpoint("channels")
+	err := client.Call(context.Background(), "GET", requestURL, nil, &channels)
+
+	assert.EqualError(t, err, "EOF")
+	assert.Equal(t, http.StatusServiceUnavailable, raidenerrors.StatusCode(err))
+	assert.True(t, raidenerrors.IsRetryable(err))
+
+	requestURL, _ = client.Endpoint("tokens")
+	err = client.Call(context.Background(), "GET", requestURL, nil, &channels)
+
+	assert.EqualError(t, err, "EOF")
+	assert.False(t, raidenerrors.IsRetryable(err))
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/address", func(request *http.Request) (*http.Response, error) {
+		response := httpmock.NewStringResponse(http.StatusTooManyRequests, `{"errors":"Too many requests"}`)
+		response.Header.Set("Retry-After", "3")
+
+		return response, nil
+	})
+
+	requestURL, _ = client.Endpoint("address")
+	err = client.Call(context.Background(), "GET", requestURL, nil, &channels)
+
+	assert.EqualError(t, err, "raiden node error 429: Too many requests")
+	assert.True(t, raidenerrors.IsRetryable(err))
+	assert.Equal(t, 3*time.Second, raidenerrors.RetryAfter(err))
+}
+
+func TestBaseClientCallErrorBodies(t *testing.T) {
+	type testcase struct {
+		name          string
+		status        int
+		body          string
+		v             interface{}
+		expectedError string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:          "json body without errors",
+			status:        http.StatusInternalServerError,
+			body:          `[]`,
+			v:             &[]interface{}{},
+			expectedError: "recieved 500 status code from raiden node",
+		},
+		testcase{
+			name:          "json body without errors, no result",
+			status:        http.StatusInternalServerError,
+			body:          `{"state":"closed"}`,
+			expectedError: "recieved 500 status code from raiden node",
+		},
+		testcase{
+			name:          "empty body, no result",
+			status:        http.StatusInternalServerError,
+			body:          ``,
+			expectedError: "EOF",
+		},
+		testcase{
+			name:          "redirection",
+			status:        http.StatusNotModified,
+			body:          ``,
+			v:             &[]interface{}{},
+			expectedError: "EOF",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var client = &BaseClient{
+				Config:     &config.Config{Host: "http://localhost:5001", APIVersion: "v1"},
+				HTTPClient: http.DefaultClient,
+			}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(tc.status, tc.body))
+
+			requestURL, _ := client.Endpoint("channels")
+			err := client.Call(context.Background(), "GET", requestURL, nil, tc.v)
+
+			assert.EqualError(t, err, tc.expectedError)
+			assert.Equal(t, tc.status, raidenerrors.StatusCode(err))
+		})
+	}
+}
+
+func TestBaseClientCallAPIVersion2(t *testing.T) {
+	var (
+		client = &BaseClient{
+			Config: &config.Config{
+				Host:       "http://localhost:5001",
+				APIVersion: "v2",
+			},
+			HTTPClient: http.DefaultClient,
+		}
+		payment = &struct {
+			Amount     json.Number `json:"amount"`
+			Identifier int64       `json:"identifier"`
+		}{}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("POST", "http://localhost:5001/api/v2/payments/0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8/0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		func(request *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(request.Body)
+
+			assert.JSONEq(t, `{"amount":"10","identifier":42}`, string(body))
+
+			return httpmock.NewStringResponse(http.StatusOK, `{"amount":"10","identifier":"42"}`), nil
+		})
+
+	requestURL, err := client.Endpoint("payments/%s/%s", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", "0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	require.NoError(t, err)
+
+	require.NoError(t, client.Call(context.Background(), "POST", requestURL, map[string]int64{"amount": 10, "identifier": 42}, payment))
+
+	assert.Equal(t, json.Number("10"), payment.Amount)
+	assert.Equal(t, int64(42), payment.Identifier)
+}
+
+func TestBaseClientCallAddressFormat(t *testing.T) {
+	var client = &BaseClient{
+		Config: &config.Config{
+			Host:          "http://localhost:5001",
+			APIVersion:    "v1",
+			AddressFormat: config.Lowercase,
+		},
+		HTTPClient: http.DefaultClient,
+	}
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("PUT", "http://localhost:5001/api/v1/channels",
+		func(request *http.Request) (*http.Response, error) {
+			body, _ := ioutil.ReadAll(request.Body)
+
+			assert.JSONEq(t, `{"partner_address":"0x61c808d82a3ac53231750dadc13c777b59310bd9","token_address":"0xea674fdde714fd979de3edf0f56aa9716b898ec8"}`, string(body))
+
+			return httpmock.NewStringResponse(http.StatusCreated, `{}`), nil
+		})
+
+	requestURL, err := client.Endpoint("channels/%s/%s", "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8", "0x61C808D82A3Ac53231750daDc13c777b59310bD9")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:5001/api/v1/channels/0xea674fdde714fd979de3edf0f56aa9716b898ec8/0x61c808d82a3ac53231750dadc13c777b59310bd9", requestURL.String())
+
+	requestURL, err = client.Endpoint("channels")
+	require.NoError(t, err)
+
+	require.NoError(t, client.Call(context.Background(), "PUT", requestURL, map[string]string{
+		"partner_address": "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		"token_address":   "0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+	}, nil))
+}
+
+func TestBaseClientCallAccepted(t *testing.T) {
+	type testcase struct {
+		name             string
+		polling          *config.AcceptedPolling
+		pendingPolls     int32
+		location         string
+		expectedResult   string
+		expectedPending  bool
+		expectedLocation string
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:             "pending without polling",
+			pendingPolls:     1,
+			location:         "/api/v1/operations/7",
+			expectedPending:  true,
+			expectedLocation: "/api/v1/operations/7",
+		},
+		testcase{
+			name:           "polled to completion",
+			polling:        &config.AcceptedPolling{Interval: time.Millisecond},
+			pendingPolls:   3,
+			location:       "/api/v1/operations/7",
+			expectedResult: "0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+		},
+		testcase{
+			name:             "polling timed out",
+			polling:          &config.AcceptedPolling{Interval: time.Millisecond, Timeout: 5 * time.Millisecond},
+			pendingPolls:     1000,
+			location:         "/api/v1/operations/7",
+			expectedPending:  true,
+			expectedLocation: "/api/v1/operations/7",
+		},
+		testcase{
+			name:            "nowhere to poll",
+			polling:         &config.AcceptedPolling{Interval: time.Millisecond},
+			pendingPolls:    1,
+			expectedPending: true,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				requests int32
+				server   = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+					if atomic.AddInt32(&requests, 1) <= tc.pendingPolls {
+						if tc.location != "" {
+							writer.Header().Set("Location", tc.location)
+						}
+
+						writer.WriteHeader(http.StatusAccepted)
+						writer.Write([]byte(`{"status":"pending"}`))
+						return
+					}
+
+					assert.Equal(t, "GET", request.Method)
+					assert.Equal(t, tc.location, request.URL.Path)
+					writer.Write([]byte(`{"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9"}`))
+				}))
+				client = &BaseClient{
+					Config: &config.Config{
+						Host:            server.URL,
+						APIVersion:      "v1",
+						AcceptedPolling: tc.polling,
+					},
+					HTTPClient: http.DefaultClient,
+				}
+				result = &struct {
+					PartnerAddress string `json:"partner_address"`
+				}{}
+			)
+			defer server.Close()
+
+			endpoint, err := client.Endpoint("channels")
+			require.NoError(t, err)
+
+			err = client.Call(context.Background(), "PUT", endpoint, map[string]string{"partner_address": "0x61C808D82A3Ac53231750daDc13c777b59310bD9"}, result)
+
+			if tc.expectedPending {
+				pending, ok := raidenerrors.AsPending(err)
+				require.True(t, ok, "%v", err)
+				assert.Equal(t, "pending", pending.Status)
+				assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodeRequestPending))
+				assert.False(t, raidenerrors.IsRetryable(err))
+
+				if tc.expectedLocation != "" {
+					require.NotNil(t, pending.Location)
+					assert.Equal(t, server.URL+tc.expectedLocation, pending.Location.String())
+				} else {
+					assert.Nil(t, pending.Location)
+				}
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedResult, result.PartnerAddress)
+			assert.Equal(t, tc.pendingPolls+1, atomic.LoadInt32(&requests))
+		})
+	}
+}
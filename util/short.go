@@ -0,0 +1,9 @@
+package util
+
+import "github.com/ethereum/go-ethereum/common"
+
+// ShortAddress returns the address abbreviated to its first four hex digits, e.g.
+// 0x61C8…, for log lines and string representations that are read by people.
+func ShortAddress(address common.Address) string {
+	return address.Hex()[:6] + "…"
+}
@@ -0,0 +1,13 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShortAddress(t *testing.T) {
+	assert.Equal(t, "0x61C8…", ShortAddress(common.HexToAddress("0x61c808d82a3ac53231750dadc13c777b59310bd9")))
+	assert.Equal(t, "0x0000…", ShortAddress(common.Address{}))
+}
@@ -0,0 +1,129 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/changefeed"
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+)
+
+const (
+	// DefaultSyncInitialBackoff is the first backoff of a request rejected while the
+	// node is syncing when no backoff is given.
+	DefaultSyncInitialBackoff = time.Second
+	// DefaultSyncMaxBackoff is the longest backoff of a request rejected while the
+	// node is syncing when no maximum is given.
+	DefaultSyncMaxBackoff = 30 * time.Second
+)
+
+// maxErrorBody is how much of an error response is read to tell whether the node is
+// syncing.
+const maxErrorBody = 64 << 10
+
+// doSyncRetry sends the request until the node no longer rejects it for syncing, nor
+// a proxy for exceeding its rate limit, or the budget of the retry is spent. The
+// Retry-After of a rejection is waited for in place of the backoff. Requests whose
+// body cannot be sent again are sent once.
+func (client *BaseClient) doSyncRetry(request *http.Request, retry *config.SyncRetry) (*http.Response, error) {
+	var (
+		initial  = retry.InitialBackoff
+		max      = retry.MaxBackoff
+		pace     = clock.Or(retry.Clock)
+		deadline = pace.Now().Add(retry.Budget)
+		attempt  = request
+	)
+
+	if initial <= 0 {
+		initial = DefaultSyncInitialBackoff
+	}
+
+	if max <= 0 {
+		max = DefaultSyncMaxBackoff
+	}
+
+	var (
+		feed    = Backoff(initial, max, pace)
+		backoff = feed.Next(true)
+	)
+
+	for {
+		response, err := client.do(attempt)
+		if err != nil || !(rateLimited(response) || nodeSyncing(response)) {
+			return response, err
+		}
+
+		wait := backoff
+		if retryAfter, ok := RetryAfter(response.Header, pace.Now()); ok {
+			wait = retryAfter
+		}
+
+		if (request.Body != nil && request.GetBody == nil) || pace.Now().Add(wait).After(deadline) {
+			return response, nil
+		}
+
+		response.Body.Close()
+
+		select {
+		case <-request.Context().Done():
+			return nil, request.Context().Err()
+		case <-pace.After(wait):
+		}
+
+		if attempt, err = Resend(request); err != nil {
+			return nil, err
+		}
+
+		backoff = feed.Next(false)
+	}
+}
+
+// Backoff returns the backoffs between the attempts of a request, from the initial
+// one doubling up to max, waited for on the clock.
+func Backoff(initial, max time.Duration, pace clock.Clock) *changefeed.Feed {
+	return changefeed.New(&changefeed.Options{MinInterval: initial, MaxInterval: max, Clock: pace})
+}
+
+// Resend returns a copy of the request with a new body, to send it again.
+func Resend(request *http.Request) (*http.Request, error) {
+	var attempt = request.WithContext(request.Context())
+
+	if request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			return nil, err
+		}
+
+		attempt.Body = body
+	}
+
+	return attempt, nil
+}
+
+// rateLimited tells whether the response rejects the request for exceeding the rate
+// limit of a proxy in front of the node.
+func rateLimited(response *http.Response) bool {
+	return response.StatusCode == http.StatusTooManyRequests
+}
+
+// nodeSyncing tells whether the response rejects the request because the node is
+// still syncing with the chain. The body of the response is still to be read.
+func nodeSyncing(response *http.Response) bool {
+	if response.StatusCode != http.StatusServiceUnavailable && response.StatusCode != http.StatusConflict {
+		return false
+	}
+
+	body, err := ioutil.ReadAll(io.LimitReader(response.Body, maxErrorBody))
+
+	response.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), response.Body), response.Body}
+
+	return err == nil && raidenerrors.Match(string(body)) == raidenerrors.CodeNodeSyncing
+}
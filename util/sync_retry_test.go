@@ -0,0 +1,293 @@
+package util
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/clock"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBaseClientDoSyncRetry(t *testing.T) {
+	type testcase struct {
+		name             string
+		syncing          int
+		statusCode       int
+		message          string
+		budget           time.Duration
+		expectedStatus   int
+		expectedAttempts int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:             "retried until the node is synced",
+			syncing:          2,
+			statusCode:       http.StatusServiceUnavailable,
+			message:          `{"errors":"The node is still syncing with the blockchain"}`,
+			budget:           time.Second,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 3,
+		},
+		testcase{
+			name:             "conflict while syncing",
+			syncing:          1,
+			statusCode:       http.StatusConflict,
+			message:          `{"errors":"Node not synced yet"}`,
+			budget:           time.Second,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 2,
+		},
+		testcase{
+			name:             "budget spent",
+			syncing:          100,
+			statusCode:       http.StatusServiceUnavailable,
+			message:          `{"errors":"The node is still syncing with the blockchain"}`,
+			budget:           25 * time.Millisecond,
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedAttempts: 2,
+		},
+		testcase{
+			name:             "other conflicts are not retried",
+			syncing:          1,
+			statusCode:       http.StatusConflict,
+			message:          `{"errors":"Channel is not in an open state"}`,
+			budget:           time.Second,
+			expectedStatus:   http.StatusConflict,
+			expectedAttempts: 1,
+		},
+		testcase{
+			name:             "disabled without a budget",
+			syncing:          1,
+			statusCode:       http.StatusServiceUnavailable,
+			message:          `{"errors":"The node is still syncing with the blockchain"}`,
+			expectedStatus:   http.StatusServiceUnavailable,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mutex    sync.Mutex
+				attempts int
+				bodies   = make([]string, 0)
+				server   = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+					body, _ := ioutil.ReadAll(request.Body)
+
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					attempts++
+					bodies = append(bodies, string(body))
+
+					if attempts <= tc.syncing {
+						writer.WriteHeader(tc.statusCode)
+						writer.Write([]byte(tc.message))
+						return
+					}
+
+					writer.Write([]byte(`{"ok":true}`))
+				}))
+				client = &BaseClient{
+					Config: &config.Config{
+						Host:       server.URL,
+						APIVersion: "v1",
+						SyncRetry:  &config.SyncRetry{Budget: tc.budget, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 100 * time.Millisecond},
+					},
+					HTTPClient: http.DefaultClient,
+				}
+			)
+			defer server.Close()
+
+			endpoint, err := client.Endpoint("channels")
+			require.NoError(t, err)
+
+			request, err := client.NewRequest(context.Background(), "PUT", endpoint, map[string]int64{"total_deposit": 10})
+			require.NoError(t, err)
+
+			response, err := client.Do(request)
+			require.NoError(t, err)
+			defer response.Body.Close()
+
+			body, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expectedStatus, response.StatusCode)
+			assert.Equal(t, tc.expectedAttempts, attempts)
+
+			if tc.expectedStatus != http.StatusOK {
+				assert.Equal(t, tc.message, string(body), "the rejection is returned whole")
+			}
+
+			for _, sent := range bodies {
+				assert.Equal(t, `{"total_deposit":10}`, sent, "every attempt sends the body")
+			}
+		})
+	}
+}
+
+func TestBaseClientDoSyncRetryCancelled(t *testing.T) {
+	var (
+		server = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusServiceUnavailable)
+			writer.Write([]byte(`{"errors":"The node is still syncing with the blockchain"}`))
+		}))
+		client = &BaseClient{
+			Config:     &config.Config{Host: server.URL, APIVersion: "v1", SyncRetry: &config.SyncRetry{Budget: time.Minute}},
+			HTTPClient: http.DefaultClient,
+		}
+		ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	)
+	defer server.Close()
+	defer cancel()
+
+	endpoint, err := client.Endpoint("channels")
+	require.NoError(t, err)
+
+	request, err := client.NewRequest(ctx, "GET", endpoint, nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(request)
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestBaseClientDoRateLimitRetry(t *testing.T) {
+	type testcase struct {
+		name             string
+		retryAfter       string
+		budget           time.Duration
+		expectedStatus   int
+		expectedAttempts int
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:             "retried after the backoff",
+			budget:           time.Second,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 2,
+		},
+		testcase{
+			name:             "retried after retry-after",
+			retryAfter:       "0",
+			budget:           time.Second,
+			expectedStatus:   http.StatusOK,
+			expectedAttempts: 2,
+		},
+		testcase{
+			name:             "retry-after outlasting the budget",
+			retryAfter:       "60",
+			budget:           time.Second,
+			expectedStatus:   http.StatusTooManyRequests,
+			expectedAttempts: 1,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				mutex    sync.Mutex
+				attempts int
+				server   = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+					mutex.Lock()
+					defer mutex.Unlock()
+
+					attempts++
+
+					if attempts == 1 {
+						if tc.retryAfter != "" {
+							writer.Header().Set("Retry-After", tc.retryAfter)
+						}
+
+						writer.WriteHeader(http.StatusTooManyRequests)
+						return
+					}
+
+					writer.Write([]byte(`{"ok":true}`))
+				}))
+				client = &BaseClient{
+					Config: &config.Config{
+						Host:       server.URL,
+						APIVersion: "v1",
+						SyncRetry:  &config.SyncRetry{Budget: tc.budget, InitialBackoff: 10 * time.Millisecond},
+					},
+					HTTPClient: http.DefaultClient,
+				}
+			)
+			defer server.Close()
+
+			endpoint, err := client.Endpoint("channels")
+			require.NoError(t, err)
+
+			request, err := client.NewRequest(context.Background(), "GET", endpoint, nil)
+			require.NoError(t, err)
+
+			response, err := client.Do(request)
+			require.NoError(t, err)
+			response.Body.Close()
+
+			assert.Equal(t, tc.expectedStatus, response.StatusCode)
+			assert.Equal(t, tc.expectedAttempts, attempts)
+		})
+	}
+}
+
+func TestSyncRetryClock(t *testing.T) {
+	var (
+		attempts int32
+		manual   = clock.NewManual(time.Date(2019, 3, 7, 18, 0, 0, 0, time.UTC))
+		server   = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if atomic.AddInt32(&attempts, 1) == 1 {
+				writer.Header().Set("Retry-After", "30")
+				writer.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+
+			writer.Write([]byte(`{"ok":true}`))
+		}))
+		client = &BaseClient{
+			Config: &config.Config{
+				Host:       server.URL,
+				APIVersion: "v1",
+				SyncRetry:  &config.SyncRetry{Budget: time.Minute, Clock: manual},
+			},
+			HTTPClient: http.DefaultClient,
+		}
+		responses = make(chan *http.Response, 1)
+	)
+	defer server.Close()
+
+	endpoint, err := client.Endpoint("channels")
+	require.NoError(t, err)
+
+	request, err := client.NewRequest(context.Background(), "GET", endpoint, nil)
+	require.NoError(t, err)
+
+	go func() {
+		response, err := client.Do(request)
+		assert.NoError(t, err)
+		responses <- response
+	}()
+
+	// the retry after is waited for on the clock, not in real time
+	manual.BlockUntil(1)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+	manual.Advance(30 * time.Second)
+
+	response := <-responses
+	require.NotNil(t, response)
+	response.Body.Close()
+
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&attempts))
+}
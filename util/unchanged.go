@@ -0,0 +1,29 @@
+package util
+
+import (
+	"context"
+	"errors"
+)
+
+// UnchangedHeader is set on the responses whose body is the same as the previous
+// response to the same request, see transport.NewConditional.
+const UnchangedHeader = "X-Raiden-Client-Unchanged"
+
+// ErrUnchanged is returned by Call in place of decoding a response whose body is the
+// same as the previous one, for a context from SkipUnchanged.
+var ErrUnchanged = errors.New("response unchanged since the previous request")
+
+type skipUnchangedKey struct{}
+
+// SkipUnchanged returns a copy of the context asking Call not to decode the
+// responses marked with UnchangedHeader, returning ErrUnchanged instead, so that
+// pollers skip the payloads they already handled.
+func SkipUnchanged(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipUnchangedKey{}, true)
+}
+
+// skipsUnchanged tells whether the context is from SkipUnchanged.
+func skipsUnchanged(ctx context.Context) bool {
+	skip, _ := ctx.Value(skipUnchangedKey{}).(bool)
+	return skip
+}
@@ -0,0 +1,198 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cpurta/go-raiden-client/apiv2"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// driftAmounts are the fields holding amounts of tokens, or other counts, which are
+// never negative, in the payloads of every endpoint.
+var driftAmounts = map[string]bool{
+	"amount":                  true,
+	"balance":                 true,
+	"blocks_to_sync":          true,
+	"capacity1":               true,
+	"capacity2":               true,
+	"channel_identifier":      true,
+	"estimated_fee":           true,
+	"funds":                   true,
+	"locked_amount":           true,
+	"planned_withdraw_amount": true,
+	"reveal_timeout":          true,
+	"settle_timeout":          true,
+	"sum_deposits":            true,
+	"total_deposit":           true,
+	"total_withdraw":          true,
+	"transferred_amount":      true,
+	"withdraw_amount":         true,
+}
+
+// driftAddresses are the address fields which are never the zero address, by
+// resource of the endpoint.
+var driftAddresses = map[string][]string{
+	"address":           {"our_address"},
+	"channels":          {"token_network_identifier", "partner_address", "token_address"},
+	"pending_transfers": {"token_network_identifier", "partner_address", "token_address"},
+	"tokens":            {"partner_address"},
+}
+
+// driftStates are the values the state fields take, by resource of the endpoint.
+var driftStates = map[string]map[string][]string{
+	"channels": {"state": {"opened", "closing", "closed", "settling", "settled", "unusable", "waiting_for_settle"}},
+	"status":   {"status": {"ready", "syncing", "unavailable"}},
+}
+
+// DriftError is returned by the calls whose response holds drift when the Validation
+// of the configuration rejects it.
+type DriftError struct {
+	Drifts []*config.Drift
+}
+
+func (err *DriftError) Error() string {
+	var messages = make([]string, 0, len(err.Drifts))
+
+	for _, drift := range err.Drifts {
+		messages = append(messages, fmt.Sprintf("%s %s", drift.Field, drift.Reason))
+	}
+
+	return fmt.Sprintf("%d impossible value(s) in the response of %s: %s", len(err.Drifts), err.Drifts[0].Path, strings.Join(messages, ", "))
+}
+
+// Code returns raidenerrors.CodeResponseDrift, see raidenerrors.HasCode.
+func (err *DriftError) Code() raidenerrors.Code {
+	return raidenerrors.CodeResponseDrift
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (err *DriftError) Is(target error) bool {
+	return err.Code().Is(target)
+}
+
+// Validate returns the drift of the payload of a response to the endpoint of the
+// resource at the path, in the format of Raiden 0.100.x: negative amounts, zero
+// addresses in required fields and unknown states, in the order of the fields.
+// Payloads that are not JSON have no drift, their decoding reporting them.
+func Validate(resource, path string, data []byte) []*config.Drift {
+	var (
+		decoded interface{}
+		drifts  = make([]*config.Drift, 0)
+		decoder = json.NewDecoder(bytes.NewReader(data))
+	)
+
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&decoded); err != nil {
+		return drifts
+	}
+
+	var walk func(value interface{}, field string)
+
+	walk = func(value interface{}, field string) {
+		switch value := value.(type) {
+		case []interface{}:
+			for i, element := range value {
+				walk(element, fmt.Sprintf("%s[%d]", field, i))
+			}
+		case map[string]interface{}:
+			keys := make([]string, 0, len(value))
+			for key := range value {
+				keys = append(keys, key)
+			}
+
+			sort.Strings(keys)
+
+			for _, key := range keys {
+				var (
+					element = value[key]
+					name    = strings.TrimPrefix(field+"."+key, ".")
+				)
+
+				if reason := drift(resource, key, element); reason != "" {
+					drifts = append(drifts, &config.Drift{Resource: resource, Path: path, Field: name, Value: fmt.Sprint(element), Reason: reason})
+					continue
+				}
+
+				walk(element, name)
+			}
+		}
+	}
+
+	walk(decoded, "")
+
+	return drifts
+}
+
+// drift returns why the value of the field of a payload of the resource is
+// impossible, or an empty string when it is not.
+func drift(resource, key string, value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	if driftAmounts[key] {
+		if number := fmt.Sprint(value); strings.HasPrefix(strings.TrimSpace(number), "-") {
+			return "is negative"
+		}
+	}
+
+	for _, name := range driftAddresses[resource] {
+		if name != key {
+			continue
+		}
+
+		if hex, ok := value.(string); ok && common.HexToAddress(hex) == (common.Address{}) {
+			return "is the zero address"
+		}
+	}
+
+	if states, ok := driftStates[resource][key]; ok {
+		state, _ := value.(string)
+
+		for _, known := range states {
+			if state == known {
+				return ""
+			}
+		}
+
+		return "is an unknown state"
+	}
+
+	return ""
+}
+
+// validate checks the payload of a response to the path for drift along the
+// Validation of the configuration, reporting it and returning a *DriftError when the
+// validation rejects it.
+func (client *BaseClient) validate(path string, data []byte) error {
+	var resource = resource(path)
+
+	if client.Config == nil || client.Config.Validation == nil {
+		return nil
+	}
+
+	if client.Config.APIVersion == apiv2.Version {
+		resource = apiv2.Resource(path)
+	}
+
+	drifts := Validate(resource, path, data)
+
+	if onDrift := client.Config.Validation.OnDrift; onDrift != nil {
+		for _, drift := range drifts {
+			onDrift(drift)
+		}
+	}
+
+	if len(drifts) > 0 && client.Config.Validation.Reject {
+		return &DriftError{Drifts: drifts}
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	type testcase struct {
+		name           string
+		resource       string
+		payload        string
+		expectedDrifts []*config.Drift
+	}
+
+	testcases := []testcase{
+		testcase{
+			name:           "valid channels",
+			resource:       "channels",
+			payload:        `[{"token_network_identifier":"0xE5637F0103794C7e05469A9964E4563089a5E6f2","channel_identifier":20,"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":"25000000000000000000","total_deposit":35,"state":"opened"}]`,
+			expectedDrifts: []*config.Drift{},
+		},
+		testcase{
+			name:     "impossible channel",
+			resource: "channels",
+			payload:  `[{"channel_identifier":20,"partner_address":"0x0000000000000000000000000000000000000000","token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","balance":"-25","total_deposit":-35,"state":"frozen"}]`,
+			expectedDrifts: []*config.Drift{
+				&config.Drift{Resource: "channels", Path: "/api/v1/channels", Field: "[0].balance", Value: "-25", Reason: "is negative"},
+				&config.Drift{Resource: "channels", Path: "/api/v1/channels", Field: "[0].partner_address", Value: "0x0000000000000000000000000000000000000000", Reason: "is the zero address"},
+				&config.Drift{Resource: "channels", Path: "/api/v1/channels", Field: "[0].state", Value: "frozen", Reason: "is an unknown state"},
+				&config.Drift{Resource: "channels", Path: "/api/v1/channels", Field: "[0].total_deposit", Value: "-35", Reason: "is negative"},
+			},
+		},
+		testcase{
+			name:           "states of other resources",
+			resource:       "payments",
+			payload:        `[{"event":"EventPaymentSentSuccess","amount":5,"state":"frozen"}]`,
+			expectedDrifts: []*config.Drift{},
+		},
+		testcase{
+			name:     "unknown status",
+			resource: "status",
+			payload:  `{"status":"halted"}`,
+			expectedDrifts: []*config.Drift{
+				&config.Drift{Resource: "status", Path: "/api/v1/status", Field: "status", Value: "halted", Reason: "is an unknown state"},
+			},
+		},
+		testcase{
+			name:           "not json",
+			resource:       "channels",
+			payload:        `not json`,
+			expectedDrifts: []*config.Drift{},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedDrifts, Validate(tc.resource, "/api/v1/"+tc.resource, []byte(tc.payload)))
+		})
+	}
+}
+
+func TestBaseClientCallValidation(t *testing.T) {
+	var (
+		drifts []*config.Drift
+		client = &BaseClient{
+			Config: &config.Config{
+				Host:       "http://localhost:5001",
+				APIVersion: "v1",
+				Validation: &config.Validation{
+					OnDrift: func(drift *config.Drift) { drifts = append(drifts, drift) },
+				},
+			},
+			HTTPClient: http.DefaultClient,
+		}
+		channels []map[string]interface{}
+	)
+
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/channels", httpmock.NewStringResponder(http.StatusOK,
+		`[{"channel_identifier":20,"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8","partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","balance":-25,"state":"opened"}]`))
+
+	requestURL, err := client.Endpoint("channels")
+	require.NoError(t, err)
+
+	// drift is reported, the response decoded as is
+	require.NoError(t, client.Call(context.Background(), "GET", requestURL, nil, &channels))
+	assert.Len(t, channels, 1)
+	require.Len(t, drifts, 1)
+	assert.Equal(t, "[0].balance", drifts[0].Field)
+
+	// and rejected
+	client.Config.Validation.Reject = true
+
+	err = client.Call(context.Background(), "GET", requestURL, nil, &channels)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusOK, raidenerrors.StatusCode(err))
+	require.IsType(t, &DriftError{}, err.(*raidenerrors.Error).Err)
+	assert.True(t, raidenerrors.HasCode(err, raidenerrors.CodeResponseDrift))
+	assert.EqualError(t, err, "1 impossible value(s) in the response of /api/v1/channels: [0].balance is negative")
+	assert.Len(t, drifts, 2)
+}
@@ -0,0 +1,25 @@
+package version
+
+import (
+	"net/http"
+
+	"github.com/cpurta/go-raiden-client/config"
+)
+
+var (
+	_ Getter = &Client{}
+)
+
+// NewClient creates a new version client that provides access to the version of a
+// Raiden node.
+func NewClient(config *config.Config, httpClient *http.Client) *Client {
+	return &Client{
+		Getter: NewGetter(config, httpClient),
+	}
+}
+
+// Client is a version client that allows access to the get version HTTP call to a
+// Raiden node.
+type Client struct {
+	Getter
+}
@@ -0,0 +1,117 @@
+// Package version detects the version of a Raiden node, so that the client decodes
+// the payloads of the node in the format of its version.
+package version
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"github.com/cpurta/go-raiden-client/apiv2"
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/cpurta/go-raiden-client/raidenerrors"
+	"github.com/cpurta/go-raiden-client/util"
+)
+
+// Legacy is the version reported for nodes without the version endpoint, which was
+// added in Raiden 1.0.
+const Legacy = "0.100"
+
+type versionResponse struct {
+	Version string `json:"version"`
+}
+
+// Getter is a generic interface to get the version of a Raiden node.
+type Getter interface {
+	Get(ctx context.Context) (string, error)
+}
+
+var _ Getter = &defaultGetter{}
+
+// NewGetter will return a default version getter for a configured Raiden node.
+func NewGetter(config *config.Config, httpClient *http.Client) Getter {
+	return &defaultGetter{
+		baseClient: &util.BaseClient{
+			Config:     config,
+			HTTPClient: httpClient,
+		},
+	}
+}
+
+type defaultGetter struct {
+	baseClient *util.BaseClient
+}
+
+// Get will return the version of the Raiden node, e.g. "1.1.1", or Legacy when the
+// node does not report its version.
+func (getter *defaultGetter) Get(ctx context.Context) (string, error) {
+	var (
+		err             error
+		requestURL      *url.URL
+		versionResponse = &versionResponse{}
+	)
+
+	if requestURL, err = getter.baseClient.Endpoint("version"); err != nil {
+		return "", err
+	}
+
+	if err = getter.baseClient.Call(ctx, "GET", requestURL, nil, versionResponse); err != nil {
+		if raidenerrors.StatusCode(err) == http.StatusNotFound {
+			return Legacy, nil
+		}
+
+		return "", err
+	}
+
+	return versionResponse.Version, nil
+}
+
+// Detect will set the NodeVersion of the configuration to the version of the node it
+// configures, unless it is already set, see config.Config.NodeVersion.
+func Detect(ctx context.Context, config *config.Config, httpClient *http.Client) error {
+	var (
+		err     error
+		version string
+	)
+
+	if config.NodeVersion != "" {
+		return nil
+	}
+
+	if version, err = NewGetter(config, httpClient).Get(ctx); err != nil {
+		return err
+	}
+
+	config.NodeVersion = version
+
+	return nil
+}
+
+// DetectAPIVersion will set the APIVersion of the configuration to the latest version
+// of the API the node serves, apiv2.Version when it serves version 2 and "v1"
+// otherwise, unless it is already set.
+func DetectAPIVersion(ctx context.Context, config *config.Config, httpClient *http.Client) error {
+	var (
+		err     error
+		version string
+		probe   = *config
+	)
+
+	if config.APIVersion != "" {
+		return nil
+	}
+
+	probe.APIVersion = apiv2.Version
+
+	if version, err = NewGetter(&probe, httpClient).Get(ctx); err != nil {
+		return err
+	}
+
+	if version == Legacy {
+		config.APIVersion = "v1"
+	} else {
+		config.APIVersion = apiv2.Version
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package version
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cpurta/go-raiden-client/config"
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ExampleDetect() {
+	var config = &config.Config{
+		Host:       "http://localhost:5001",
+		APIVersion: "v1",
+	}
+
+	if err := Detect(context.Background(), config, http.DefaultClient); err != nil {
+		panic(fmt.Sprintf("unable to detect raiden node version: %s", err.Error()))
+	}
+
+	fmt.Println("raiden version:", config.NodeVersion)
+}
+
+func TestGetter(t *testing.T) {
+	var config = &config.Config{
+		Host:       "http://localhost:5001",
+		APIVersion: "v1",
+	}
+
+	type testcase struct {
+		name            string
+		prepHTTPMock    func()
+		expectedVersion string
+		expectedError   error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "successfully got version",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder(
+					"GET",
+					"http://localhost:5001/api/v1/version",
+					httpmock.NewStringResponder(
+						http.StatusOK,
+						`{"version":"1.1.1"}`,
+					),
+				)
+			},
+			expectedVersion: "1.1.1",
+		},
+		testcase{
+			name: "legacy node without version endpoint",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder(
+					"GET",
+					"http://localhost:5001/api/v1/version",
+					httpmock.NewStringResponder(
+						http.StatusNotFound,
+						``,
+					),
+				)
+			},
+			expectedVersion: Legacy,
+		},
+		testcase{
+			name: "unexpected 500 response",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder(
+					"GET",
+					"http://localhost:5001/api/v1/version",
+					httpmock.NewStringResponder(
+						http.StatusInternalServerError,
+						``,
+					),
+				)
+			},
+			expectedError: errors.New("EOF"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			version, err := NewClient(config, http.DefaultClient).Get(context.Background())
+
+			if tc.expectedError != nil {
+				assert.EqualError(t, err, tc.expectedError.Error())
+				assert.Empty(t, version)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedVersion, version)
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	httpmock.Activate()
+	defer httpmock.DeactivateAndReset()
+
+	httpmock.RegisterResponder("GET", "http://localhost:5001/api/v1/version", httpmock.NewStringResponder(http.StatusOK, `{"version":"1.1.1"}`))
+
+	var detected = &config.Config{Host: "http://localhost:5001", APIVersion: "v1"}
+
+	require.NoError(t, Detect(context.Background(), detected, http.DefaultClient))
+	assert.Equal(t, "1.1.1", detected.NodeVersion)
+
+	var configured = &config.Config{Host: "http://localhost:5001", APIVersion: "v1", NodeVersion: "0.100.3"}
+
+	require.NoError(t, Detect(context.Background(), configured, http.DefaultClient))
+	assert.Equal(t, "0.100.3", configured.NodeVersion)
+	assert.Equal(t, 1, httpmock.GetTotalCallCount())
+}
+
+func TestDetectAPIVersion(t *testing.T) {
+	type testcase struct {
+		name               string
+		apiVersion         string
+		prepHTTPMock       func()
+		expectedAPIVersion string
+		expectedError      error
+	}
+
+	testcases := []testcase{
+		testcase{
+			name: "node serving version 2",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v2/version", httpmock.NewStringResponder(http.StatusOK, `{"version":"2.0.0"}`))
+			},
+			expectedAPIVersion: "v2",
+		},
+		testcase{
+			name: "node serving version 1 only",
+			prepHTTPMock: func() {
+				httpmock.RegisterResponder("GET", "http://localhost:5001/api/v2/version", httpmock.NewStringResponder(http.StatusNotFound, ``))
+			},
+			expectedAPIVersion: "v1",
+		},
+		testcase{
+			name:               "configured version kept",
+			apiVersion:         "v1",
+			prepHTTPMock:       func() {},
+			expectedAPIVersion: "v1",
+		},
+		testcase{
+			name:          "unreachable node",
+			prepHTTPMock:  func() {},
+			expectedError: errors.New("no responder found"),
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var config = &config.Config{Host: "http://localhost:5001", APIVersion: tc.apiVersion}
+
+			httpmock.Activate()
+			defer httpmock.DeactivateAndReset()
+
+			tc.prepHTTPMock()
+
+			err := DetectAPIVersion(context.Background(), config, http.DefaultClient)
+
+			if tc.expectedError != nil {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError.Error())
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedAPIVersion, config.APIVersion)
+		})
+	}
+}
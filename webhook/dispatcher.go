@@ -0,0 +1,122 @@
+// Package webhook delivers the payment events of a Raiden node to HTTP endpoints,
+// signing every payload with a shared secret so that receivers can tell genuine
+// notifications from spoofed ones, see Verify.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/payments"
+)
+
+// Payload is the JSON body of the webhook requests, with the addresses as checksummed
+// hex strings like in the event, and the label of the channel with the partner when
+// it has one.
+type Payload struct {
+	TokenAddress   string          `json:"token_address"`
+	PartnerAddress string          `json:"partner_address"`
+	Label          *channels.Label `json:"label,omitempty"`
+	Event          *payments.Event `json:"event"`
+}
+
+// Dispatcher posts payment events to the URL of a webhook, signed with its Secret in
+// the SignatureHeader. The payloads carry the labels of the channels kept in Labels
+// when it is set. A nil HTTPClient is http.DefaultClient.
+type Dispatcher struct {
+	URL        string
+	Secret     []byte
+	Labels     *channels.LabelStore
+	HTTPClient *http.Client
+
+	now func() time.Time
+}
+
+// NewDispatcher creates a dispatcher posting to the URL, signed with the secret.
+func NewDispatcher(url string, secret []byte, httpClient *http.Client) *Dispatcher {
+	return &Dispatcher{
+		URL:        url,
+		Secret:     secret,
+		HTTPClient: httpClient,
+	}
+}
+
+// Dispatch will post the event to the webhook, any status code other than 2xx being
+// an error.
+func (dispatcher *Dispatcher) Dispatch(ctx context.Context, event *events.PaymentEvent) error {
+	var (
+		err        error
+		label      *channels.Label
+		payload    []byte
+		request    *http.Request
+		response   *http.Response
+		httpClient = dispatcher.HTTPClient
+		now        = time.Now
+	)
+
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if dispatcher.now != nil {
+		now = dispatcher.now
+	}
+
+	if dispatcher.Labels != nil {
+		if label, err = dispatcher.Labels.Get(event.TokenAddress, event.PartnerAddress); err != nil {
+			return err
+		}
+	}
+
+	if payload, err = json.Marshal(&Payload{TokenAddress: event.TokenAddress.Hex(), PartnerAddress: event.PartnerAddress.Hex(), Label: label, Event: event.Event}); err != nil {
+		return err
+	}
+
+	if request, err = http.NewRequest("POST", dispatcher.URL, bytes.NewReader(payload)); err != nil {
+		return err
+	}
+
+	request = request.WithContext(ctx)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(SignatureHeader, Sign(dispatcher.Secret, now(), payload))
+
+	if response, err = httpClient.Do(request); err != nil {
+		return err
+	}
+
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("received %d status code from webhook %s", response.StatusCode, dispatcher.URL)
+	}
+
+	return nil
+}
+
+// Run will dispatch the events of the subscription until it ends, returning the
+// errors of the deliveries on the returned channel, which is closed when the
+// subscription ends. Errors are dropped when nobody is receiving them.
+func (dispatcher *Dispatcher) Run(ctx context.Context, subscription *events.Subscription) <-chan error {
+	var errs = make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+
+		for event := range subscription.Events {
+			if err := dispatcher.Dispatch(ctx, event); err != nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+		}
+	}()
+
+	return errs
+}
@@ -0,0 +1,109 @@
+package webhook
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cpurta/go-raiden-client/channels"
+	"github.com/cpurta/go-raiden-client/events"
+	"github.com/cpurta/go-raiden-client/payments"
+	"github.com/cpurta/go-raiden-client/storage"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDispatcher(t *testing.T) {
+	var (
+		secret     = []byte("shared secret")
+		statusCode = http.StatusOK
+		payloads   = make(chan []byte, 1)
+		receiver   = httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			payload, err := VerifyRequest(secret, request, 0)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusUnauthorized)
+				return
+			}
+
+			payloads <- payload
+			writer.WriteHeader(statusCode)
+		}))
+		event = &events.PaymentEvent{
+			TokenAddress:   common.HexToAddress("0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"),
+			PartnerAddress: common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+			Event: &payments.Event{
+				EventName:  "EventPaymentReceivedSuccess",
+				Amount:     big.NewInt(5),
+				Initiator:  common.HexToAddress("0x61C808D82A3Ac53231750daDc13c777b59310bD9"),
+				Identifier: 42,
+				LogTime:    time.Date(2018, 10, 30, 7, 3, 52, 0, time.UTC),
+			},
+		}
+		ctx = context.Background()
+	)
+	defer receiver.Close()
+
+	t.Run("signed payload", func(t *testing.T) {
+		require.NoError(t, NewDispatcher(receiver.URL, secret, nil).Dispatch(ctx, event))
+
+		assert.JSONEq(t, `{
+			"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8",
+			"partner_address":"0x61C808D82A3Ac53231750daDc13c777b59310bD9",
+			"event":{"event":"EventPaymentReceivedSuccess","amount":5,"initiator":"0x61C808D82A3Ac53231750daDc13c777b59310bD9","identifier":42,"log_time":"2018-10-30T07:03:52Z"}
+		}`, string(<-payloads))
+	})
+
+	t.Run("labelled channel", func(t *testing.T) {
+		var (
+			labels     = channels.NewLabelStore(storage.NewMemoryStore())
+			dispatcher = NewDispatcher(receiver.URL, secret, nil)
+		)
+
+		require.NoError(t, labels.Set(event.TokenAddress, event.PartnerAddress, &channels.Label{Name: "Binance hot wallet"}))
+
+		dispatcher.Labels = labels
+		require.NoError(t, dispatcher.Dispatch(ctx, event))
+
+		assert.Contains(t, string(<-payloads), `"label":{"name":"Binance hot wallet"}`)
+	})
+
+	t.Run("spoofed secret", func(t *testing.T) {
+		err := NewDispatcher(receiver.URL, []byte("spoofed"), nil).Dispatch(ctx, event)
+
+		assert.EqualError(t, err, "received 401 status code from webhook "+receiver.URL)
+		assert.Empty(t, payloads)
+	})
+
+	t.Run("replayed payload", func(t *testing.T) {
+		dispatcher := NewDispatcher(receiver.URL, secret, nil)
+		dispatcher.now = func() time.Time { return time.Now().Add(-time.Hour) }
+
+		assert.Error(t, dispatcher.Dispatch(ctx, event))
+		assert.Empty(t, payloads)
+	})
+
+	t.Run("run a subscription", func(t *testing.T) {
+		var (
+			subscriptionEvents = make(chan *events.PaymentEvent, 2)
+			subscription       = &events.Subscription{Events: subscriptionEvents}
+		)
+
+		statusCode = http.StatusInternalServerError
+		defer func() { statusCode = http.StatusOK }()
+
+		subscriptionEvents <- event
+		close(subscriptionEvents)
+
+		errs := NewDispatcher(receiver.URL, secret, nil).Run(ctx, subscription)
+
+		assert.EqualError(t, <-errs, "received 500 status code from webhook "+receiver.URL)
+		assert.NotEmpty(t, <-payloads)
+
+		_, open := <-errs
+		assert.False(t, open)
+	})
+}
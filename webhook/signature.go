@@ -0,0 +1,132 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the header of the webhook requests carrying the signature of
+// their payload, formatted as t=<unix timestamp>,v1=<hex HMAC-SHA256>.
+const SignatureHeader = "X-Raiden-Signature"
+
+// DefaultTolerance is the age past which Verify refuses a signature when no tolerance
+// is given, so that recorded requests can't be replayed later on.
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrMissingSignature is returned by Verify when the signature header is empty or
+	// lacks the timestamp or the signature.
+	ErrMissingSignature = errors.New("missing webhook signature")
+	// ErrInvalidSignature is returned by Verify when no signature of the header
+	// matches the payload.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrExpiredSignature is returned by Verify when the timestamp of the signature is
+	// further from the current time than the tolerance.
+	ErrExpiredSignature = errors.New("expired webhook signature")
+)
+
+// Sign returns the signature header of the payload sent at the timestamp, the HMAC
+// being computed over the unix timestamp, a dot and the payload.
+func Sign(secret []byte, timestamp time.Time, payload []byte) string {
+	var unix = timestamp.Unix()
+
+	return fmt.Sprintf("t=%d,v1=%s", unix, hex.EncodeToString(mac(secret, unix, payload)))
+}
+
+// Verify checks that the signature header was made with the secret over the payload,
+// no more than tolerance ago, a zero tolerance being DefaultTolerance. The header may
+// hold several v1 signatures, e.g. while the secret is rotated, one match being
+// enough.
+func Verify(secret []byte, header string, payload []byte, tolerance time.Duration) error {
+	return verify(secret, header, payload, tolerance, time.Now())
+}
+
+// VerifyRequest reads the body of a webhook request and verifies its signature, see
+// Verify. The body is returned only when the signature is valid.
+func VerifyRequest(secret []byte, request *http.Request, tolerance time.Duration) ([]byte, error) {
+	var (
+		err     error
+		payload []byte
+	)
+
+	if payload, err = ioutil.ReadAll(request.Body); err != nil {
+		return nil, err
+	}
+
+	if err = Verify(secret, request.Header.Get(SignatureHeader), payload, tolerance); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func verify(secret []byte, header string, payload []byte, tolerance time.Duration, now time.Time) error {
+	var (
+		unix       int64
+		timestamp  bool
+		signatures = make([][]byte, 0, 1)
+	)
+
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+
+	for _, field := range strings.Split(header, ",") {
+		var parts = strings.SplitN(strings.TrimSpace(field), "=", 2)
+
+		if len(parts) != 2 {
+			continue
+		}
+
+		switch parts[0] {
+		case "t":
+			var err error
+
+			if unix, err = strconv.ParseInt(parts[1], 10, 64); err != nil {
+				return ErrMissingSignature
+			}
+
+			timestamp = true
+		case "v1":
+			if signature, err := hex.DecodeString(parts[1]); err == nil {
+				signatures = append(signatures, signature)
+			}
+		}
+	}
+
+	if !timestamp || len(signatures) == 0 {
+		return ErrMissingSignature
+	}
+
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrExpiredSignature
+	}
+
+	var expected = mac(secret, unix, payload)
+
+	for _, signature := range signatures {
+		if hmac.Equal(signature, expected) {
+			return nil
+		}
+	}
+
+	return ErrInvalidSignature
+}
+
+func mac(secret []byte, unix int64, payload []byte) []byte {
+	var hash = hmac.New(sha256.New, secret)
+
+	hash.Write([]byte(strconv.FormatInt(unix, 10)))
+	hash.Write([]byte("."))
+	hash.Write(payload)
+
+	return hash.Sum(nil)
+}
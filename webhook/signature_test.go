@@ -0,0 +1,108 @@
+package webhook
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func ExampleVerifyRequest() {
+	var secret = []byte("shared secret")
+
+	http.HandleFunc("/raiden/payments", func(writer http.ResponseWriter, request *http.Request) {
+		payload, err := VerifyRequest(secret, request, 0)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		fmt.Println("genuine payment notification:", string(payload))
+	})
+
+	http.ListenAndServe(":8080", nil)
+}
+
+func TestVerify(t *testing.T) {
+	type testcase struct {
+		name        string
+		header      string
+		payload     string
+		tolerance   time.Duration
+		expectedErr error
+	}
+
+	var (
+		secret    = []byte("shared secret")
+		payload   = `{"token_address":"0xEA674fdDe714fd979de3EdF0F56AA9716B898ec8"}`
+		now       = time.Unix(1540883032, 0)
+		signature = Sign(secret, now, []byte(payload))
+	)
+
+	testcases := []testcase{
+		testcase{
+			name:    "valid signature",
+			header:  signature,
+			payload: payload,
+		},
+		testcase{
+			name:    "one of several signatures",
+			header:  signature + ",v1=" + Sign([]byte("old secret"), now, []byte(payload))[len("t=1540883032,v1="):],
+			payload: payload,
+		},
+		testcase{
+			name:    "within the tolerance",
+			header:  Sign(secret, now.Add(-time.Minute), []byte(payload)),
+			payload: payload,
+		},
+		testcase{
+			name:        "other secret",
+			header:      Sign([]byte("spoofed"), now, []byte(payload)),
+			payload:     payload,
+			expectedErr: ErrInvalidSignature,
+		},
+		testcase{
+			name:        "tampered payload",
+			header:      signature,
+			payload:     `{"token_address":"0x0000000000000000000000000000000000000000"}`,
+			expectedErr: ErrInvalidSignature,
+		},
+		testcase{
+			name:        "tampered timestamp",
+			header:      "t=1540883033" + signature[len("t=1540883032"):],
+			payload:     payload,
+			expectedErr: ErrInvalidSignature,
+		},
+		testcase{
+			name:        "expired signature",
+			header:      Sign(secret, now.Add(-time.Hour), []byte(payload)),
+			payload:     payload,
+			expectedErr: ErrExpiredSignature,
+		},
+		testcase{
+			name:      "custom tolerance",
+			header:    Sign(secret, now.Add(-time.Hour), []byte(payload)),
+			payload:   payload,
+			tolerance: 2 * time.Hour,
+		},
+		testcase{
+			name:        "missing signature",
+			payload:     payload,
+			expectedErr: ErrMissingSignature,
+		},
+		testcase{
+			name:        "missing timestamp",
+			header:      signature[len("t=1540883032,"):],
+			payload:     payload,
+			expectedErr: ErrMissingSignature,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedErr, verify(secret, tc.header, []byte(tc.payload), tc.tolerance, now))
+		})
+	}
+}